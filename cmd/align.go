@@ -2,13 +2,17 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/ataiva-software/runestone/internal/config"
 	"github.com/ataiva-software/runestone/internal/drift"
+	"github.com/ataiva-software/runestone/internal/executor"
+	"github.com/ataiva-software/runestone/internal/notify"
+	"github.com/ataiva-software/runestone/internal/output"
 	"github.com/ataiva-software/runestone/internal/providers"
-	"github.com/ataiva-software/runestone/internal/providers/aws"
 	"github.com/spf13/cobra"
 )
 
@@ -17,160 +21,303 @@ var alignCmd = &cobra.Command{
 	Short: "Continuously reconcile drift",
 	Long: `Align reconciles infrastructure drift by:
 - Detecting differences between current and desired state
-- Automatically healing drift for resources with auto-heal enabled
-- Reporting drift for resources with notify-only policy`,
+- Automatically healing drift for resources with auto-heal enabled, unless the drift
+  policy's heal_drift_types or max_changes rule the drift out
+- Reporting drift for resources with notify-only policy
+
+With --report-only, align reports the current drift of every resource without healing
+anything, whatever its drift policy, and exits with code 2 when drift exists.
+
+When the configuration's notifications section sets a webhook_url, each pass that leaves
+drift unhealed, or fails to auto-heal a resource, posts the drifted resources to it as JSON.
+Delivery is best effort: a webhook that fails or times out is logged and align carries on.`,
 	RunE: runAlign,
 }
 
 func init() {
-	alignCmd.Flags().StringP("config", "c", "infra.yaml", "Path to the configuration file")
+	alignCmd.Flags().StringP("config", "c", "infra.yaml", "Path to the configuration file, a directory or glob of files to merge, or - for standard input")
+	addVariableFlags(alignCmd)
 	alignCmd.Flags().Bool("once", false, "Run alignment once instead of continuously")
 	alignCmd.Flags().Duration("interval", 5*time.Minute, "Interval between alignment checks (ignored with --once)")
-	alignCmd.Flags().StringP("output", "o", "human", "Output format (human, json, markdown)")
+	alignCmd.Flags().Int("max-iterations", 0, "Stop after this many alignment passes (0 runs until interrupted)")
+	alignCmd.Flags().StringP("output", "o", "human", "Output format (human, json, markdown, yaml, junit)")
+	alignCmd.Flags().Bool("report-only", false, "Report drift for every resource once without healing anything (implies --once)")
 }
 
 func runAlign(cmd *cobra.Command, args []string) error {
 	configFile, _ := cmd.Flags().GetString("config")
 	runOnce, _ := cmd.Flags().GetBool("once")
 	interval, _ := cmd.Flags().GetDuration("interval")
+	maxIterations, _ := cmd.Flags().GetInt("max-iterations")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	reportOnly, _ := cmd.Flags().GetBool("report-only")
+	if reportOnly {
+		runOnce = true
+	}
+	if configFile == config.StdinPath && !runOnce {
+		return fmt.Errorf("reading the configuration from standard input requires --once, since each alignment pass reads the configuration again")
+	}
+
+	formatter := newFormatter(cmd, outputFormat)
 
 	if runOnce {
-		return runAlignmentOnce(configFile)
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+
+		result := runAlignmentOnce(ctx, cmd, configFile, reportOnly)
+		if err := printAlignResult(formatter, result); err != nil {
+			return err
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+		if hasUnresolvedDrift(result) {
+			return withExitCode(ExitChanges, nil)
+		}
+		return nil
 	}
 
-	fmt.Printf("🔄 Starting continuous alignment (interval: %v)\n", interval)
-	fmt.Println("Press Ctrl+C to stop")
+	// The first SIGINT or SIGTERM stops the loop once the current pass has finished; passes
+	// don't run under this context so they aren't cancelled mid-reconcile
+	shutdown, stop := signalContext(cmd.Context(), "Stopping after the current alignment pass")
+	defer stop()
+
+	slog.Info("starting continuous alignment; press Ctrl+C to stop", "interval", interval)
 
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
-	// Run initial alignment
-	if err := runAlignmentOnce(configFile); err != nil {
-		fmt.Printf("Initial alignment failed: %v\n", err)
-	}
+	passes := 0
+	failures := 0
+alignment:
+	for {
+		ctx, cancel := timeoutContext(cmd, cmd.Context())
+		result := runAlignmentOnce(ctx, cmd, configFile, false)
+		cancel()
 
-	// Run continuous alignment
-	for range ticker.C {
-		if err := runAlignmentOnce(configFile); err != nil {
-			fmt.Printf("Alignment failed: %v\n", err)
+		passes++
+		if result.Error != nil {
+			failures++
+		}
+		if err := printAlignResult(formatter, result); err != nil {
+			return err
 		}
+
+		if maxIterations > 0 && passes >= maxIterations {
+			break
+		}
+
+		select {
+		case <-shutdown.Done():
+			break alignment
+		case <-ticker.C:
+		}
+	}
+
+	slog.Info("alignment stopped", "passes", passes, "failed", failures)
+	return nil
+}
+
+// printAlignResult writes an alignment result in the chosen output format
+func printAlignResult(formatter output.Formatter, result output.AlignResult) error {
+	formatted, err := formatter.FormatAlignResult(result)
+	if err != nil {
+		return fmt.Errorf("failed to format output: %w", err)
 	}
-	
+	fmt.Print(formatted)
 	return nil
 }
 
-func runAlignmentOnce(configFile string) error {
-	fmt.Printf("\n🔄 Aligning desired state with reality... (%s)\n", time.Now().Format("15:04:05"))
+// runAlignmentOnce runs a single alignment pass and returns its result. Resources with drift
+// are reported as healed, drifted (no auto-heal policy) or error. With reportOnly set nothing
+// is healed and every resource is reported, as aligned or drifted. Progress is logged while
+// the pass runs, and drift left unhealed is sent to the configured notification sink.
+func runAlignmentOnce(ctx context.Context, cmd *cobra.Command, configFile string, reportOnly bool) output.AlignResult {
+	startTime := time.Now()
+	result := output.AlignResult{
+		ReportOnly: reportOnly,
+		Resources:  []output.ResourceStatus{},
+	}
+
+	fail := func(err error) output.AlignResult {
+		result.Error = err
+		result.Duration = time.Since(startTime)
+		return result
+	}
+
+	slog.Info("inspecting live infrastructure", "started", startTime.Format("15:04:05"))
 
 	// Parse configuration
-	parser := config.NewParser()
+	parser, err := newConfigParser(cmd)
+	if err != nil {
+		return fail(err)
+	}
 	cfg, err := parser.ParseFile(configFile)
 	if err != nil {
-		return fmt.Errorf("failed to parse configuration: %w", err)
+		return fail(fmt.Errorf("failed to parse configuration: %w", err))
 	}
 
 	// Set up provider registry
 	registry := providers.NewProviderRegistry()
-	ctx := context.Background()
 
 	// Initialize providers
 	for providerName, providerConfig := range cfg.Providers {
-		var provider providers.Provider
-		switch providerName {
-		case "aws":
-			provider = aws.NewProvider()
-		default:
-			return fmt.Errorf("unsupported provider: %s", providerName)
+		provider, err := newProvider(providerName)
+		if err != nil {
+			return fail(err)
 		}
 
-		providerConfigMap := make(map[string]interface{})
-		providerConfigMap["region"] = providerConfig.Region
-		providerConfigMap["profile"] = providerConfig.Profile
-
-		if err := provider.Initialize(ctx, providerConfigMap); err != nil {
-			return fmt.Errorf("failed to initialize provider %s: %w", providerName, err)
+		if err := provider.Initialize(ctx, providerConfig.Settings()); err != nil {
+			return fail(fmt.Errorf("failed to initialize provider %s: %w", providerName, err))
 		}
 
 		registry.Register(providerName, provider)
 	}
 
+	sink, err := notify.FromConfig(cfg.Notifications)
+	if err != nil {
+		return fail(err)
+	}
+
+	if err := loadModules(cfg, parser); err != nil {
+		return fail(err)
+	}
+
 	// Expand resources
 	instances, err := parser.ExpandResources(cfg.Resources)
 	if err != nil {
-		return fmt.Errorf("failed to expand resources: %w", err)
+		return fail(fmt.Errorf("failed to expand resources: %w", err))
+	}
+	if err := config.ValidateMoves(cfg.Moved, instances); err != nil {
+		return fail(err)
+	}
+	if err := validateProviderKeys(instances); err != nil {
+		return fail(err)
 	}
 
-	// Detect drift
+	// Detect drift; moved resources are reported, but only commit renames them
 	detector := drift.NewDetector(registry)
-	driftResults, err := detector.DetectDriftBatch(ctx, instances)
+	detector.SetMoves(cfg.Moved)
+	driftResults, err := detectDriftWithReferences(ctx, detector, instances)
 	if err != nil {
-		return fmt.Errorf("failed to detect drift: %w", err)
+		return fail(fmt.Errorf("failed to detect drift: %w", err))
 	}
 
-	// Process drift results
-	driftCount := 0
-	healedCount := 0
+	// Heal drift where the resource's policy allows it; everything else is reported
 	errorCount := 0
-
+	var unhealed []notify.Resource
 	for _, instance := range instances {
 		driftResult, exists := driftResults[instance.ID]
-		if !exists || !driftResult.HasDrift {
+		if !exists {
 			continue
 		}
-
-		driftCount++
-
-		// Check drift policy
-		if instance.DriftPolicy == nil {
-			fmt.Printf("  • %s has drift (no policy defined)\n", instance.ID)
+		if !driftResult.HasDrift {
+			if reportOnly {
+				result.Resources = append(result.Resources, output.ResourceStatus{Name: instance.ID, Status: "aligned"})
+			}
 			continue
 		}
 
-		if instance.DriftPolicy.NotifyOnly {
-			fmt.Printf("  • %s has drift (notify-only policy)\n", instance.ID)
-			displayDriftDetails(driftResult)
-			continue
+		result.DriftDetected = true
+		status := output.ResourceStatus{
+			Name:        instance.ID,
+			Status:      "drifted",
+			Changes:     describeDifferences(driftResult.Differences),
+			Differences: outputDifferences(driftResult.Differences),
 		}
+		if driftResult.CurrentState == nil {
+			status.Changes = []string{"Resource does not exist"}
+		}
+		if driftResult.MovedFrom != "" {
+			status.Changes = append([]string{fmt.Sprintf("Moved from %s", driftResult.MovedFrom)}, status.Changes...)
+		}
+
+		if !reportOnly && instance.DriftPolicy != nil && instance.DriftPolicy.AutoHeal && !instance.DriftPolicy.NotifyOnly {
+			slog.Info("auto-healing resource", "resource", instance.ID)
 
-		if instance.DriftPolicy.AutoHeal {
-			fmt.Printf("  • %s has drift - attempting auto-heal...\n", instance.ID)
-			
-			if err := detector.AutoHeal(ctx, instance, driftResult); err != nil {
-				fmt.Printf("    ✗ Auto-heal failed: %v\n", err)
+			healStart := time.Now()
+			err := healResource(ctx, registry, detector, instance, driftResult)
+			var skipped *drift.HealSkippedError
+			switch {
+			case errors.As(err, &skipped):
+				// The drift policy rules this drift out; it's reported, not an error
+				slog.Warn("auto-heal skipped", "resource", instance.ID, "reason", skipped.Reason)
+				status.Status = "skipped"
+				status.Changes = append(status.Changes, fmt.Sprintf("Auto-heal skipped: %s", skipped.Reason))
+				result.HealsSkipped++
+				unhealed = append(unhealed, notify.NewResource(instance.ID, driftResult, err))
+			case err != nil:
+				status.Status = "error"
+				status.Changes = append(status.Changes, fmt.Sprintf("Auto-heal failed: %v", err))
 				errorCount++
-			} else {
-				fmt.Printf("    ✓ Auto-heal successful\n")
-				healedCount++
+				unhealed = append(unhealed, notify.NewResource(instance.ID, driftResult, err))
+			default:
+				status.Status = "healed"
+				result.ActionsApplied++
 			}
+			status.Duration = time.Since(healStart)
 		}
+		if status.Status == "drifted" {
+			unhealed = append(unhealed, notify.NewResource(instance.ID, driftResult, nil))
+		}
+
+		result.Resources = append(result.Resources, status)
 	}
 
-	// Display summary
-	if driftCount == 0 {
-		fmt.Println(" Infrastructure aligned (no drift detected)")
-	} else {
-		fmt.Printf(" Infrastructure alignment complete\n")
-		fmt.Printf("  - %d resource%s with drift detected\n", driftCount, pluralize(driftCount))
-		if healedCount > 0 {
-			fmt.Printf("  - %d resource%s auto-healed\n", healedCount, pluralize(healedCount))
-		}
-		if errorCount > 0 {
-			fmt.Printf("  - %d error%s during auto-heal\n", errorCount, pluralize(errorCount))
+	if sink != nil && len(unhealed) > 0 {
+		sendDriftNotification(ctx, sink, notify.Event{
+			Project:     cfg.Project,
+			Environment: cfg.Environment,
+			DetectedAt:  startTime.UTC(),
+			Resources:   unhealed,
+		})
+	}
+
+	if errorCount > 0 {
+		return fail(fmt.Errorf("%d resource%s failed to auto-heal", errorCount, pluralize(errorCount)))
+	}
+
+	result.Success = true
+	result.Duration = time.Since(startTime)
+	return result
+}
+
+// healResource auto-heals a drifted resource with the properties its drift was detected
+// against, in which references to other resources have been resolved. A reference that
+// couldn't be resolved, because the resource it refers to doesn't exist, skips the heal.
+// Secrets are read right before healing, as commit reads them.
+func healResource(ctx context.Context, registry *providers.ProviderRegistry, detector *drift.Detector, instance config.ResourceInstance, driftResult *providers.DriftResult) error {
+	if driftResult.DesiredState != nil {
+		instance.Properties = driftResult.DesiredState
+	}
+	if references := executor.FindReferences(instance.Properties); len(references) > 0 {
+		return &drift.HealSkippedError{Reason: fmt.Sprintf("its reference to %s couldn't be resolved; run commit to create it", references[0].ResourceID)}
+	}
+	if len(config.FindSecrets(instance.Properties)) > 0 {
+		resolved, err := resolveSecrets(ctx, registry, instance)
+		if err != nil {
+			return fmt.Errorf("failed to resolve secrets for %s: %w", instance.ID, err)
 		}
+		instance.Properties = resolved
 	}
+	return detector.AutoHeal(ctx, instance, driftResult)
+}
 
-	return nil
+// sendDriftNotification delivers a drift event, logging rather than returning a failure so
+// that an unreachable webhook never stops alignment. The sink bounds how long it waits.
+func sendDriftNotification(ctx context.Context, sink notify.Sink, event notify.Event) {
+	slog.Info("sending drift notification", "resources", len(event.Resources))
+	if err := sink.Send(ctx, event); err != nil {
+		slog.Warn("failed to send drift notification", "error", err)
+	}
 }
 
-func displayDriftDetails(driftResult *providers.DriftResult) {
-	for _, diff := range driftResult.Differences {
-		switch diff.DriftType {
-		case providers.DriftTypeAdded:
-			fmt.Printf("    - Missing property: %s (expected: %v)\n", diff.Property, diff.DesiredValue)
-		case providers.DriftTypeModified:
-			fmt.Printf("    - Property %s: %v → %v\n", diff.Property, diff.CurrentValue, diff.DesiredValue)
-		case providers.DriftTypeRemoved:
-			fmt.Printf("    - Extra property: %s (current: %v)\n", diff.Property, diff.CurrentValue)
+// hasUnresolvedDrift reports whether an alignment left drifted resources that weren't healed
+func hasUnresolvedDrift(result output.AlignResult) bool {
+	for _, resource := range result.Resources {
+		if resource.Status == "drifted" || resource.Status == "skipped" {
+			return true
 		}
 	}
+	return false
 }
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/ataiva-software/runestone/internal/drift"
+	"github.com/ataiva-software/runestone/internal/providers/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealResource_ResolvesReferences(t *testing.T) {
+	target := testInstance("target", map[string]interface{}{"name": "target"})
+	source := testInstance("source", map[string]interface{}{"target_arn": "${test:resource:type.target.arn}"})
+	source.DriftPolicy = &config.DriftPolicy{AutoHeal: true}
+
+	f := newCommitFixture(target, source)
+	f.provider.SetState(target.ID, map[string]interface{}{"name": "target", "arn": "arn:test:target"})
+	f.provider.SetState(source.ID, map[string]interface{}{"target_arn": "arn:test:old"})
+
+	detector := drift.NewDetector(f.registry)
+	driftResults, err := detectDriftWithReferences(context.Background(), detector, f.instances)
+	require.NoError(t, err)
+	assert.False(t, driftResults[target.ID].HasDrift)
+	require.True(t, driftResults[source.ID].HasDrift)
+
+	require.NoError(t, healResource(context.Background(), f.registry, detector, source, driftResults[source.ID]))
+	assert.Equal(t, "arn:test:target", f.provider.State(source.ID)["target_arn"])
+
+	// Once healed, the resolved reference matches the live value
+	driftResults, err = detectDriftWithReferences(context.Background(), drift.NewDetector(f.registry), f.instances)
+	require.NoError(t, err)
+	assert.False(t, driftResults[source.ID].HasDrift)
+}
+
+func TestHealResource_SkipsUnresolvedReferences(t *testing.T) {
+	target := testInstance("target", map[string]interface{}{"name": "target"})
+	source := testInstance("source", map[string]interface{}{"target_arn": "${test:resource:type.target.arn}"})
+	source.DriftPolicy = &config.DriftPolicy{AutoHeal: true}
+
+	// The target doesn't exist, so the reference can't be resolved
+	f := newCommitFixture(target, source)
+	f.provider.SetState(source.ID, map[string]interface{}{"target_arn": "arn:test:old"})
+
+	detector := drift.NewDetector(f.registry)
+	driftResults, err := detectDriftWithReferences(context.Background(), detector, f.instances)
+	require.NoError(t, err)
+
+	err = healResource(context.Background(), f.registry, detector, source, driftResults[source.ID])
+	var skipped *drift.HealSkippedError
+	require.ErrorAs(t, err, &skipped)
+	assert.Contains(t, skipped.Reason, target.ID)
+	assert.Empty(t, f.provider.Updated())
+}
+
+// secretsProvider is a fake provider that also resolves secrets from a fixed set
+type secretsProvider struct {
+	*fake.Provider
+	secrets map[string]string
+}
+
+func (p *secretsProvider) ResolveSecret(ctx context.Context, name string) (string, error) {
+	value, exists := p.secrets[name]
+	if !exists {
+		return "", fmt.Errorf("secret %s not found", name)
+	}
+	return value, nil
+}
+
+func TestHealResource_ResolvesSecrets(t *testing.T) {
+	instance := testInstance("db", map[string]interface{}{"password": `${secret("app/db")}`})
+	instance.DriftPolicy = &config.DriftPolicy{AutoHeal: true}
+
+	f := newCommitFixture(instance)
+	f.registry.Register("aws", &secretsProvider{Provider: fake.New(), secrets: map[string]string{"app/db": "hunter2"}})
+
+	detector := drift.NewDetector(f.registry)
+	driftResults, err := detectDriftWithReferences(context.Background(), detector, f.instances)
+	require.NoError(t, err)
+	require.Nil(t, driftResults[instance.ID].CurrentState)
+
+	// The missing resource is created with the secret's value, never the reference
+	require.NoError(t, healResource(context.Background(), f.registry, detector, instance, driftResults[instance.ID]))
+	assert.Equal(t, "hunter2", f.provider.State(instance.ID)["password"])
+}
@@ -1,9 +1,10 @@
 package cmd
 
 import (
-	"context"
+	"errors"
 	"fmt"
-	"strings"
+	"log/slog"
+	"sort"
 	"time"
 
 	"github.com/ataiva-software/runestone/internal/config"
@@ -11,7 +12,6 @@ import (
 	"github.com/ataiva-software/runestone/internal/output"
 	"github.com/ataiva-software/runestone/internal/policy"
 	"github.com/ataiva-software/runestone/internal/providers"
-	"github.com/ataiva-software/runestone/internal/providers/aws"
 	"github.com/spf13/cobra"
 )
 
@@ -26,8 +26,10 @@ var bootstrapCmd = &cobra.Command{
 }
 
 func init() {
-	bootstrapCmd.Flags().StringP("config", "c", "infra.yaml", "Path to the configuration file")
-	bootstrapCmd.Flags().StringP("output", "o", "human", "Output format (human, json, markdown)")
+	bootstrapCmd.Flags().StringP("config", "c", "infra.yaml", "Path to the configuration file, a directory or glob of files to merge, or - for standard input")
+	addVariableFlags(bootstrapCmd)
+	addPolicyFlags(bootstrapCmd)
+	bootstrapCmd.Flags().StringP("output", "o", "human", "Output format (human, json, markdown, yaml, junit)")
 }
 
 func runBootstrap(cmd *cobra.Command, args []string) error {
@@ -37,7 +39,7 @@ func runBootstrap(cmd *cobra.Command, args []string) error {
 	startTime := time.Now()
 	
 	// Create output formatter
-	formatter := output.NewFormatter(output.OutputFormat(outputFormat))
+	formatter := newFormatter(cmd, outputFormat)
 	
 	// Initialize result
 	result := output.BootstrapResult{
@@ -53,12 +55,17 @@ func runBootstrap(cmd *cobra.Command, args []string) error {
 	// Only show progress messages for human output
 	showProgress := outputFormat == "human"
 	
-	if showProgress {
-		fmt.Println(" Bootstrapping Runestone environment...")
-	}
+	slog.Info("bootstrapping Runestone environment")
 
 	// Parse configuration
-	parser := config.NewParser()
+	parser, err := newConfigParser(cmd)
+	if err != nil {
+		result.Error = err
+		result.Duration = time.Since(startTime)
+		output, _ := formatter.FormatBootstrapResult(result)
+		fmt.Print(output)
+		return result.Error
+	}
 	cfg, err := parser.ParseFile(configFile)
 	if err != nil {
 		result.Error = fmt.Errorf("failed to parse configuration: %w", err)
@@ -72,30 +79,21 @@ func runBootstrap(cmd *cobra.Command, args []string) error {
 	registry := providers.NewProviderRegistry()
 
 	// Initialize providers
-	ctx := context.Background()
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
 	for providerName, providerConfig := range cfg.Providers {
-		if showProgress {
-			fmt.Printf(" Installing provider %s...\n", providerName)
-		}
+		slog.Info("installing provider", "provider", providerName)
 
-		var provider providers.Provider
-		switch providerName {
-		case "aws":
-			provider = aws.NewProvider()
-		default:
-			result.Error = fmt.Errorf("unsupported provider: %s", providerName)
+		provider, err := newProvider(providerName)
+		if err != nil {
+			result.Error = err
 			result.Duration = time.Since(startTime)
 			output, _ := formatter.FormatBootstrapResult(result)
 			fmt.Print(output)
 			return result.Error
 		}
 
-		// Convert provider config to map[string]interface{}
-		providerConfigMap := make(map[string]interface{})
-		providerConfigMap["region"] = providerConfig.Region
-		providerConfigMap["profile"] = providerConfig.Profile
-
-		if err := provider.Initialize(ctx, providerConfigMap); err != nil {
+		if err := provider.Initialize(ctx, providerConfig.Settings()); err != nil {
 			result.Error = fmt.Errorf("failed to initialize provider %s: %w", providerName, err)
 			result.Duration = time.Since(startTime)
 			output, _ := formatter.FormatBootstrapResult(result)
@@ -107,10 +105,47 @@ func runBootstrap(cmd *cobra.Command, args []string) error {
 		result.ProvidersInstalled = append(result.ProvidersInstalled, providerName)
 	}
 
-	// Validate configuration
-	if showProgress {
-		fmt.Println(" Validating configuration...")
+	// Pull and validate modules before expansion so their resources are included
+	if len(cfg.Modules) > 0 {
+		slog.Info("loading modules", "count", len(cfg.Modules))
+		
+		moduleRegistry := modules.NewRegistry()
+		
+		moduleNames := make([]string, 0, len(cfg.Modules))
+		for moduleName := range cfg.Modules {
+			moduleNames = append(moduleNames, moduleName)
+		}
+		sort.Strings(moduleNames)
+		
+		for _, moduleName := range moduleNames {
+			slog.Info("loading module", "module", moduleName)
+			
+			module, err := loadModule(moduleName, cfg.Modules[moduleName], parser)
+			if err != nil {
+				result.Error = err
+				result.Duration = time.Since(startTime)
+				output, _ := formatter.FormatBootstrapResult(result)
+				fmt.Print(output)
+				return result.Error
+			}
+			
+			// Register the module
+			if err := moduleRegistry.RegisterModule(module); err != nil {
+				result.Error = fmt.Errorf("failed to register module '%s': %w", moduleName, err)
+				result.Duration = time.Since(startTime)
+				output, _ := formatter.FormatBootstrapResult(result)
+				fmt.Print(output)
+				return result.Error
+			}
+			
+			slog.Debug("module loaded", "module", moduleName)
+			result.ModulesLoaded++
+		}
+		
 	}
+
+	// Validate configuration
+	slog.Info("validating configuration")
 	if err := validateConfiguration(cfg, registry, parser); err != nil {
 		result.Error = fmt.Errorf("configuration validation failed: %w", err)
 		result.Duration = time.Since(startTime)
@@ -134,13 +169,13 @@ func runBootstrap(cmd *cobra.Command, args []string) error {
 	if showProgress {
 		fmt.Printf(" Configuration validated successfully\n")
 		fmt.Printf(" Found %d resource instances\n", len(instances))
-		fmt.Printf(" Evaluating policies...\n")
 	}
+	slog.Info("evaluating policies")
 
 	// Evaluate policies
-	policyEngine := policy.NewPolicyEngine()
-	if err := policyEngine.LoadBuiltinPolicies(); err != nil {
-		result.Error = fmt.Errorf("failed to load builtin policies: %w", err)
+	policyEngine, err := newPolicyEngine(cmd, cfg)
+	if err != nil {
+		result.Error = err
 		result.Duration = time.Since(startTime)
 		output, _ := formatter.FormatBootstrapResult(result)
 		fmt.Print(output)
@@ -149,7 +184,8 @@ func runBootstrap(cmd *cobra.Command, args []string) error {
 
 	allViolations := make([]policy.PolicyViolation, 0)
 	for _, instance := range instances {
-		violations, err := policyEngine.EvaluateResource(ctx, instance)
+		// Provider default tags are applied at create time, so they count towards tag policies
+		violations, err := policyEngine.EvaluateResource(ctx, withProviderDefaultTags(instance, cfg))
 		if err != nil {
 			result.Error = fmt.Errorf("failed to evaluate policies for resource %s: %w", instance.ID, err)
 			result.Duration = time.Since(startTime)
@@ -166,7 +202,17 @@ func runBootstrap(cmd *cobra.Command, args []string) error {
 	if showProgress && len(allViolations) > 0 {
 		fmt.Printf("️  Found %d policy violations:\n", len(allViolations))
 		
-		bySeverity := policyEngine.GetViolationsBySeverity(allViolations)
+		// Waived violations are listed on their own so they don't read as failures
+		var activeViolations, waivedViolations []policy.PolicyViolation
+		for _, violation := range allViolations {
+			if violation.Waived {
+				waivedViolations = append(waivedViolations, violation)
+			} else {
+				activeViolations = append(activeViolations, violation)
+			}
+		}
+
+		bySeverity := policyEngine.GetViolationsBySeverity(activeViolations)
 		
 		if errors, hasErrors := bySeverity["error"]; hasErrors {
 			fmt.Printf("  🚨 %d errors\n", len(errors))
@@ -185,6 +231,16 @@ func runBootstrap(cmd *cobra.Command, args []string) error {
 		if info, hasInfo := bySeverity["info"]; hasInfo {
 			fmt.Printf("  ℹ  %d info\n", len(info))
 		}
+
+		if len(waivedViolations) > 0 {
+			fmt.Printf("  🔕 %d waived\n", len(waivedViolations))
+			for _, violation := range waivedViolations {
+				fmt.Printf("    - %s: %s (%s)\n", violation.ResourceID, violation.Rule.Name, violation.Severity)
+				if violation.WaiverReason != "" {
+					fmt.Printf("      Reason: %s\n", violation.WaiverReason)
+				}
+			}
+		}
 	} else if showProgress {
 		fmt.Printf(" No policy violations found\n")
 	}
@@ -195,65 +251,7 @@ func runBootstrap(cmd *cobra.Command, args []string) error {
 		result.Duration = time.Since(startTime)
 		output, _ := formatter.FormatBootstrapResult(result)
 		fmt.Print(output)
-		return result.Error
-	}
-
-	// Pull and validate modules
-	if len(cfg.Modules) > 0 {
-		if showProgress {
-			fmt.Printf("📦 Loading %d modules...\n", len(cfg.Modules))
-		}
-		
-		moduleRegistry := modules.NewRegistry()
-		
-		for moduleName, moduleConfig := range cfg.Modules {
-			if showProgress {
-				fmt.Printf("  📦 Loading module: %s\n", moduleName)
-			}
-			
-			module := &modules.Module{
-				Name:    moduleName,
-				Source:  moduleConfig.Source,
-				Version: moduleConfig.Version,
-				Inputs:  moduleConfig.Inputs,
-			}
-			
-			// Validate module configuration
-			if err := module.Validate(); err != nil {
-				result.Error = fmt.Errorf("invalid module configuration for '%s': %w", moduleName, err)
-				result.Duration = time.Since(startTime)
-				output, _ := formatter.FormatBootstrapResult(result)
-				fmt.Print(output)
-				return result.Error
-			}
-			
-			// Load the module
-			if err := module.Load(); err != nil {
-				result.Error = fmt.Errorf("failed to load module '%s': %w", moduleName, err)
-				result.Duration = time.Since(startTime)
-				output, _ := formatter.FormatBootstrapResult(result)
-				fmt.Print(output)
-				return result.Error
-			}
-			
-			// Register the module
-			if err := moduleRegistry.RegisterModule(module); err != nil {
-				result.Error = fmt.Errorf("failed to register module '%s': %w", moduleName, err)
-				result.Duration = time.Since(startTime)
-				output, _ := formatter.FormatBootstrapResult(result)
-				fmt.Print(output)
-				return result.Error
-			}
-			
-			if showProgress {
-				fmt.Printf("   Module '%s' loaded successfully\n", moduleName)
-			}
-			result.ModulesLoaded++
-		}
-		
-		if showProgress {
-			fmt.Printf(" All modules loaded successfully\n")
-		}
+		return withExitCode(ExitPolicyViolations, result.Error)
 	}
 
 	if showProgress {
@@ -293,8 +291,11 @@ func validateConfiguration(cfg *config.Config, registry *providers.ProviderRegis
 	}
 
 	for _, instance := range instances {
-		// Extract provider name from resource kind
-		providerName := extractProviderName(instance.Kind)
+		if err := providers.ValidateProviderKey(instance); err != nil {
+			return err
+		}
+
+		providerName := extractProviderName(instance)
 		provider, exists := registry.Get(providerName)
 		if !exists {
 			return fmt.Errorf("provider %s not found for resource %s", providerName, instance.ID)
@@ -309,10 +310,48 @@ func validateConfiguration(cfg *config.Config, registry *providers.ProviderRegis
 	return nil
 }
 
-func extractProviderName(kind string) string {
-	parts := strings.Split(kind, ":")
-	if len(parts) > 0 {
-		return parts[0]
+// withProviderDefaultTags returns a copy of the instance with its provider's default tags merged in
+func withProviderDefaultTags(instance config.ResourceInstance, cfg *config.Config) config.ResourceInstance {
+	providerConfig, exists := cfg.Providers[extractProviderName(instance)]
+	if !exists || len(providerConfig.DefaultTags) == 0 {
+		return instance
+	}
+
+	tags := make(map[string]interface{})
+	for key, value := range providerConfig.DefaultTags {
+		tags[key] = value
+	}
+	if resourceTags, ok := instance.Properties["tags"].(map[string]interface{}); ok {
+		for key, value := range resourceTags {
+			tags[key] = value
+		}
+	}
+
+	properties := make(map[string]interface{}, len(instance.Properties)+1)
+	for key, value := range instance.Properties {
+		properties[key] = value
+	}
+	properties["tags"] = tags
+	instance.Properties = properties
+
+	return instance
+}
+
+// extractProviderName returns the providers entry that manages an instance, such as aws
+// or an alias like aws.eu-west-1
+func extractProviderName(instance config.ResourceInstance) string {
+	return providers.ProviderKey(instance)
+}
+
+// validateProviderKeys checks the provider field of every instance, reporting all the
+// mismatches at once, so that no resource is read or changed through a provider for
+// another kind
+func validateProviderKeys(instances []config.ResourceInstance) error {
+	var errs []error
+	for _, instance := range instances {
+		if err := providers.ValidateProviderKey(instance); err != nil {
+			errs = append(errs, err)
+		}
 	}
-	return ""
+	return errors.Join(errs...)
 }
@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateProviderKeys(t *testing.T) {
+	assert.NoError(t, validateProviderKeys([]config.ResourceInstance{
+		{ID: "aws:s3:bucket.logs", Kind: "aws:s3:bucket", Name: "logs"},
+		{ID: "aws:s3:bucket.eu-logs", Kind: "aws:s3:bucket", Name: "eu-logs", Provider: "aws.eu"},
+	}))
+
+	err := validateProviderKeys([]config.ResourceInstance{
+		{ID: "aws:s3:bucket.logs", Kind: "aws:s3:bucket", Name: "logs", Provider: "gcp"},
+		{ID: "aws:s3:bucket.assets", Kind: "aws:s3:bucket", Name: "assets"},
+		{ID: "k8s:core:service.web", Kind: "k8s:core:service", Name: "web", Provider: "aws.eu"},
+	})
+	assert.EqualError(t, err, "resource aws:s3:bucket.logs of kind aws:s3:bucket can't use provider gcp; use aws or an alias such as aws.<alias>\n"+
+		"resource k8s:core:service.web of kind k8s:core:service can't use provider aws.eu; use kubernetes or an alias such as kubernetes.<alias>")
+}
@@ -2,14 +2,22 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/ataiva-software/runestone/internal/config"
 	"github.com/ataiva-software/runestone/internal/drift"
 	"github.com/ataiva-software/runestone/internal/executor"
+	"github.com/ataiva-software/runestone/internal/output"
+	"github.com/ataiva-software/runestone/internal/plan"
 	"github.com/ataiva-software/runestone/internal/providers"
-	"github.com/ataiva-software/runestone/internal/providers/aws"
 	"github.com/spf13/cobra"
 )
 
@@ -19,26 +27,128 @@ var commitCmd = &cobra.Command{
 	Long: `Commit applies the planned changes to your infrastructure:
 - Creates, updates, or deletes resources as needed
 - Executes changes in dependency order using DAG
-- Shows progress and results`,
+- Shows progress and results
+
+With --dry-run the execution DAG is walked exactly as it would be for a real commit, but
+no resources are created or updated; each change is reported as simulated.
+
+With --rollback-on-failure, resources created by the commit are deleted again, in reverse
+order, if any resource fails. Updates can't be undone, so they are kept and listed. The
+rollback still runs after an interrupt or --timeout, and a further Ctrl+C stops it.
+
+Each resource is recorded in a journal (--journal) as it completes, and the journal is
+removed once the commit succeeds. After a failed or interrupted commit, --continue skips
+the resources the journal records, as long as their configuration hasn't changed and they
+still exist, and applies the rest.`,
 	RunE: runCommit,
 }
 
 func init() {
-	commitCmd.Flags().StringP("config", "c", "infra.yaml", "Path to the configuration file")
+	commitCmd.Flags().StringP("config", "c", "infra.yaml", "Path to the configuration file, a directory or glob of files to merge, or - for standard input")
+	addVariableFlags(commitCmd)
 	commitCmd.Flags().Bool("graph", false, "Show DAG visualization during execution")
+	commitCmd.Flags().String("graph-format", "text", "Graph format: text (execution levels, before applying) or dot (Graphviz, colored by outcome after applying)")
+	commitCmd.Flags().String("graph-out", "", "Write the DOT graph to a file instead of standard output")
 	commitCmd.Flags().Bool("auto-approve", false, "Skip interactive approval")
-	commitCmd.Flags().StringP("output", "o", "human", "Output format (human, json, markdown)")
+	commitCmd.Flags().StringP("output", "o", "human", "Output format (human, json, markdown, yaml, junit)")
+	commitCmd.Flags().StringArray("target", nil, "Limit the commit to a resource ID and its dependencies (repeatable)")
+	commitCmd.Flags().String("plan", "", "Apply a plan file written by 'preview --out' instead of recomputing changes")
+	commitCmd.Flags().String("events-format", "", "Stream progress events to standard error while applying: ndjson (one JSON object per line)")
+	commitCmd.Flags().Bool("refresh", true, "Read live state before applying; --refresh=false applies a --plan against the state it recorded")
+	commitCmd.Flags().Int("parallelism", drift.DefaultParallelism, "Maximum number of resources processed concurrently")
+	commitCmd.Flags().Bool("dry-run", false, "Walk the execution DAG and report each change without applying it")
+	commitCmd.Flags().Int("max-retries", executor.DefaultRetryPolicy().MaxRetries, "Retries for a resource whose create or update fails with a transient error")
+	commitCmd.Flags().Bool("rollback-on-failure", false, "Delete the resources this commit created if any resource fails")
+	commitCmd.Flags().Duration("retry-delay", executor.DefaultRetryPolicy().BaseDelay, "Initial delay before retrying a resource, doubled on each retry")
+	commitCmd.Flags().Bool("show-sensitive", false, "Show the values of outputs marked sensitive instead of redacting them")
+	commitCmd.Flags().Bool("continue", false, "Resume a failed commit, skipping the resources its journal records as completed")
+	commitCmd.Flags().String("journal", executor.DefaultJournalPath, "Path of the journal recording the resources a commit has completed")
 }
 
 func runCommit(cmd *cobra.Command, args []string) error {
 	configFile, _ := cmd.Flags().GetString("config")
 	showGraph, _ := cmd.Flags().GetBool("graph")
+	graphFormat, _ := cmd.Flags().GetString("graph-format")
+	graphOut, _ := cmd.Flags().GetString("graph-out")
+	switch graphFormat {
+	case "text":
+		if graphOut != "" {
+			return fmt.Errorf("--graph-out requires --graph-format dot")
+		}
+	case "dot":
+		showGraph = true
+	default:
+		return fmt.Errorf("unsupported graph format: %s (expected text or dot)", graphFormat)
+	}
 	autoApprove, _ := cmd.Flags().GetBool("auto-approve")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	targets, _ := cmd.Flags().GetStringArray("target")
+	parallelism, _ := cmd.Flags().GetInt("parallelism")
+	if parallelism < 1 {
+		return fmt.Errorf("--parallelism must be at least 1")
+	}
+	planFile, _ := cmd.Flags().GetString("plan")
+	refresh, _ := cmd.Flags().GetBool("refresh")
+	if !refresh && planFile == "" {
+		return fmt.Errorf("--refresh=false requires --plan, since live state decides whether each resource is created or updated")
+	}
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	maxRetries, _ := cmd.Flags().GetInt("max-retries")
+	if maxRetries < 0 {
+		return fmt.Errorf("--max-retries must not be negative")
+	}
+	retryDelay, _ := cmd.Flags().GetDuration("retry-delay")
+	if retryDelay <= 0 {
+		return fmt.Errorf("--retry-delay must be positive")
+	}
+	retryPolicy := executor.RetryPolicy{MaxRetries: maxRetries, BaseDelay: retryDelay}
+	rollbackOnFailure, _ := cmd.Flags().GetBool("rollback-on-failure")
+	eventsFormat, _ := cmd.Flags().GetString("events-format")
+	showSensitive, _ := cmd.Flags().GetBool("show-sensitive")
+	resume, _ := cmd.Flags().GetBool("continue")
+	journalPath, _ := cmd.Flags().GetString("journal")
+	if eventsFormat != "" && eventsFormat != "ndjson" {
+		return fmt.Errorf("unsupported events format: %s (expected ndjson)", eventsFormat)
+	}
+
+	var changePlan *plan.Plan
+	if planFile != "" {
+		if len(targets) > 0 {
+			return fmt.Errorf("--target cannot be combined with --plan")
+		}
+
+		var err error
+		changePlan, err = plan.Load(planFile)
+		if err != nil {
+			return err
+		}
+
+		// Default to the configuration the plan was produced from
+		if !cmd.Flags().Changed("config") && changePlan.ConfigFile != "" {
+			configFile = changePlan.ConfigFile
+		}
+	}
 
-	fmt.Println("⏳ Committing infrastructure changes...")
+	// Completed resources are only skipped once their live state confirms they're unchanged
+	if resume && !refresh {
+		return fmt.Errorf("--continue cannot be combined with --refresh=false")
+	}
+
+	if configFile == config.StdinPath && !autoApprove && !dryRun {
+		return fmt.Errorf("reading the configuration from standard input requires --auto-approve, since approval is read from standard input too")
+	}
+
+	if dryRun {
+		slog.Info("simulating infrastructure changes (dry run)")
+	} else {
+		slog.Info("committing infrastructure changes")
+	}
 
 	// Parse configuration
-	parser := config.NewParser()
+	parser, err := newConfigParser(cmd)
+	if err != nil {
+		return err
+	}
 	cfg, err := parser.ParseFile(configFile)
 	if err != nil {
 		return fmt.Errorf("failed to parse configuration: %w", err)
@@ -46,47 +156,79 @@ func runCommit(cmd *cobra.Command, args []string) error {
 
 	// Set up provider registry
 	registry := providers.NewProviderRegistry()
-	ctx := context.Background()
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
 
 	// Initialize providers
 	for providerName, providerConfig := range cfg.Providers {
-		var provider providers.Provider
-		switch providerName {
-		case "aws":
-			provider = aws.NewProvider()
-		default:
-			return fmt.Errorf("unsupported provider: %s", providerName)
+		provider, err := newProvider(providerName)
+		if err != nil {
+			return err
 		}
 
-		providerConfigMap := make(map[string]interface{})
-		providerConfigMap["region"] = providerConfig.Region
-		providerConfigMap["profile"] = providerConfig.Profile
-
-		if err := provider.Initialize(ctx, providerConfigMap); err != nil {
+		if err := provider.Initialize(ctx, providerConfig.Settings()); err != nil {
 			return fmt.Errorf("failed to initialize provider %s: %w", providerName, err)
 		}
 
 		registry.Register(providerName, provider)
 	}
 
-	// Expand resources
-	instances, err := parser.ExpandResources(cfg.Resources)
-	if err != nil {
-		return fmt.Errorf("failed to expand resources: %w", err)
+	// Expand resources, or take them verbatim from the plan
+	var instances []config.ResourceInstance
+	if changePlan != nil {
+		instances = changePlan.Instances()
+	} else {
+		if err := loadModules(cfg, parser); err != nil {
+			return err
+		}
+
+		instances, err = parser.ExpandResources(cfg.Resources)
+		if err != nil {
+			return fmt.Errorf("failed to expand resources: %w", err)
+		}
+
+		if err := config.ValidateMoves(cfg.Moved, instances); err != nil {
+			return err
+		}
+		if err := validateProviderKeys(instances); err != nil {
+			return err
+		}
+
+		instances, err = filterInstancesByTarget(instances, targets)
+		if err != nil {
+			return err
+		}
 	}
 
-	// Detect drift to determine what needs to be done
+	// Detect drift to determine what needs to be done, or trust the state the plan recorded
 	detector := drift.NewDetector(registry)
-	driftResults, err := detector.DetectDriftBatch(ctx, instances)
-	if err != nil {
-		return fmt.Errorf("failed to detect drift: %w", err)
+	detector.SetParallelism(parallelism)
+	detector.SetMoves(cfg.Moved)
+	var driftResults map[string]*providers.DriftResult
+	if refresh {
+		driftResults, err = detectDriftWithReferences(ctx, detector, instances)
+		if err != nil {
+			return fmt.Errorf("failed to detect drift: %w", err)
+		}
+
+		// Refuse to apply a plan whose underlying live state has moved on
+		if changePlan != nil {
+			if err := changePlan.Verify(driftResults); err != nil {
+				return err
+			}
+		}
+	} else {
+		driftResults, err = changePlan.DriftResults()
+		if err != nil {
+			return err
+		}
 	}
 
 	// Generate change summary
 	changeSummary := generateChangeSummary(instances, driftResults)
 
-	// Show preview and ask for confirmation
-	if !autoApprove {
+	// Show preview and ask for confirmation; a dry run changes nothing, so it doesn't ask
+	if !autoApprove && !dryRun {
 		displayPreviewResults(changeSummary, driftResults)
 		fmt.Print("\nDo you want to apply these changes? (yes/no): ")
 		var response string
@@ -106,134 +248,475 @@ func runCommit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create execution DAG: %w", err)
 	}
 
-	if showGraph {
+	if showGraph && graphFormat == "text" {
 		displayDAGVisualization(dag)
 	}
 
+	if eventsFormat == "ndjson" {
+		dag.SetEventHandler(ndjsonEventHandler(os.Stderr))
+	}
+
+	// Continue the previous commit's journal, or start a new one; a dry run only reads it
+	var journal *executor.Journal
+	if resume {
+		journal, err = executor.LoadJournal(journalPath)
+	} else if !dryRun {
+		journal, err = executor.NewJournal(journalPath)
+	}
+	if err != nil {
+		return err
+	}
+
 	// Execute changes
-	startTime := time.Now()
-	result, err := executeChanges(ctx, dag, registry, driftResults)
-	duration := time.Since(startTime)
+	result, err := executeChanges(ctx, dag, registry, detector, driftResults, executor.OutputResources(cfg.Outputs), journal, parallelism, retryPolicy, dryRun, refresh)
 
 	if err != nil {
 		return fmt.Errorf("execution failed: %w", err)
 	}
 
+	if rollbackOnFailure && !dryRun && !result.Success {
+		rollbackCtx, cancelRollback := rollbackContext()
+		rollbackCreates(rollbackCtx, dag, registry, result)
+		cancelRollback()
+		if journal != nil && len(result.RolledBack) > 0 {
+			if err := journal.Forget(result.RolledBack...); err != nil {
+				slog.Warn("failed to update commit journal", "error", err)
+			}
+		}
+	}
+
+	if journal != nil && !dryRun {
+		if result.Success && ctx.Err() == nil {
+			if err := journal.Remove(); err != nil {
+				slog.Warn("failed to remove commit journal", "error", err)
+			}
+		} else if !journal.Empty() {
+			slog.Info("progress saved; run commit --continue to resume", "journal", journal.Path())
+		}
+	}
+
+	// Outputs are only reported once resources have really been applied
+	var outputs map[string]interface{}
+	if len(cfg.Outputs) > 0 && !dryRun {
+		outputs, err = executor.EvaluateOutputs(cfg.Outputs, result.States)
+		if err != nil {
+			slog.Warn("some outputs are unavailable", "error", err)
+		}
+		outputs = redactOutputs(cfg.Outputs, outputs, showSensitive)
+	}
+
 	// Display results
-	displayExecutionResults(result, duration)
+	if outputFormat == "human" {
+		displayExecutionResults(result, resultSymbols{color: useColor(cmd)})
+		displayOutputs(outputs)
+	} else {
+		commit := commitResult(dag, result)
+		commit.Outputs = outputs
+		formatted, err := newFormatter(cmd, outputFormat).FormatCommitResult(commit)
+		if err != nil {
+			return fmt.Errorf("failed to format output: %w", err)
+		}
+		fmt.Print(formatted)
+	}
+
+	// The DOT graph is written after execution so it shows each resource's outcome
+	if showGraph && graphFormat == "dot" {
+		if err := writeDOTGraph(dag, graphOut); err != nil {
+			return err
+		}
+	}
 
+	if ctx.Err() != nil {
+		return fmt.Errorf("commit %s; %d change%s applied before stopping", interruptedError(ctx), len(result.Changes), pluralize(len(result.Changes)))
+	}
+	if err := failedResourcesError(result); err != nil {
+		return err
+	}
+	if dryRun && len(result.Changes) > 0 {
+		return withExitCode(ExitChanges, nil)
+	}
 	return nil
 }
 
-func executeChanges(ctx context.Context, dag *executor.DAG, registry *providers.ProviderRegistry, driftResults map[string]*providers.DriftResult) (*config.ExecutionResult, error) {
+// executeChanges applies changes in DAG order, starting each resource as soon as its
+// dependencies have been applied. Resources that depend on a failed resource are skipped.
+// Creates and updates that fail with a transient error are retried according to retryPolicy.
+// With dryRun set it follows the same path but skips the provider Create and Update calls,
+// recording each change as simulated. Without refresh, drift isn't re-checked once references
+// are resolved, so the drift results decide every change. The state of applied resources that
+// other resources or outputResources reference is read back into result.States. State that
+// drift detection has already read is reused from detector's cache rather than read again.
+//
+// Each completed resource is recorded in journal, unless it is nil or dryRun is set. Resources
+// the journal already records from their current configuration are skipped as long as they
+// still exist, whatever their drift.
+func executeChanges(ctx context.Context, dag *executor.DAG, registry *providers.ProviderRegistry, detector *drift.Detector, driftResults map[string]*providers.DriftResult, outputResources map[string]bool, journal *executor.Journal, parallelism int, retryPolicy executor.RetryPolicy, dryRun, refresh bool) (*config.ExecutionResult, error) {
 	result := &config.ExecutionResult{
-		Success:  true,
-		Changes:  make([]config.Change, 0),
-		Errors:   make([]error, 0),
+		Success:   true,
+		Changes:   make([]config.Change, 0),
+		Errors:    make([]error, 0),
+		Retries:   make(map[string]int),
+		Durations: make(map[string]time.Duration),
 	}
 
-	// Execute in topological order with parallel execution within each level
-	executionOrder := dag.GetExecutionOrder()
+	// Seed resource outputs with the live state of existing resources; created and
+	// updated resources replace theirs as they are applied
+	outputs := executor.NewOutputs()
+	for id, driftResult := range driftResults {
+		if driftResult.CurrentState != nil {
+			outputs.Set(id, driftResult.CurrentState)
+		}
+	}
 
-	for levelIndex, level := range executionOrder {
-		fmt.Printf("\n--- Execution Level %d ---\n", levelIndex+1)
-
-		// Execute all nodes in this level in parallel
-		type nodeResult struct {
-			nodeID string
-			change *config.Change
-			err    error
-		}
-
-		resultChan := make(chan nodeResult, len(level))
-		
-		// Start goroutines for each node in the level
-		for _, nodeID := range level {
-			go func(nodeID string) {
-				node, exists := dag.GetNode(nodeID)
-				if !exists {
-					resultChan <- nodeResult{nodeID: nodeID, err: fmt.Errorf("node %s not found", nodeID)}
-					return
-				}
+	// Guards result while nodes are applied concurrently
+	var mutex sync.Mutex
+
+	// The parallelism bound limits concurrent provider calls to avoid API throttling
+	startTime := time.Now()
+	dag.Execute(ctx, parallelism, func(node *executor.DAGNode) error {
+		nodeID := node.ID
+		nodeStart := time.Now()
+
+		driftResult, hasDrift := driftResults[nodeID]
+		if !hasDrift {
+			return nil
+		}
+
+		// The previous commit applied this resource from the same configuration, so it isn't
+		// applied again, even if its live state still shows drift, as long as it exists
+		if journal != nil && journal.Completed(node.Instance) {
+			if driftResult.CurrentState != nil {
+				slog.Info("skipping resource completed by the previous commit", "resource", nodeID)
+				return nil
+			}
+			slog.Warn("resource completed by the previous commit no longer exists; applying it again", "resource", nodeID)
+		}
+
+		fail := func(err error) error {
+			slog.Error("failed to process resource", "resource", nodeID, "error", err)
+			mutex.Lock()
+			result.Errors = append(result.Errors, err)
+			result.Success = false
+			mutex.Unlock()
+			return err
+		}
 
-				driftResult, hasDrift := driftResults[nodeID]
-				if !hasDrift {
-					resultChan <- nodeResult{nodeID: nodeID}
-					return
+		// Extract provider name
+		providerName := extractProviderName(node.Instance)
+		provider, exists := registry.Get(providerName)
+		if !exists {
+			return fail(fmt.Errorf("provider %s not found", providerName))
+		}
+
+		// Resolve references to other resources now that they have been applied,
+		// then re-check drift against the resolved values
+		instance := node.Instance
+		if len(executor.FindReferences(instance.Properties)) > 0 {
+			resolved, err := executor.ResolveReferences(instance.Properties, outputs.Snapshot())
+			if err != nil && dryRun {
+				// Resources that would be created have no outputs yet, so keep the
+				// drift detected up front
+				slog.Debug("references can't be resolved until dependencies are applied", "resource", nodeID)
+			} else if err != nil {
+				return fail(fmt.Errorf("failed to resolve references for %s: %w", nodeID, err))
+			} else {
+				instance.Properties = resolved
+
+				if driftResult.CurrentState != nil && refresh {
+					driftResult, err = detector.DetectDrift(ctx, instance)
+					if err != nil {
+						return fail(err)
+					}
 				}
+			}
+		}
+
+		// Secrets are read only for resources about to be created or updated, right
+		// before the provider call, and their values are never printed
+		needsApply := driftResult.CurrentState == nil || driftResult.HasDrift
+		if needsApply && !dryRun && len(config.FindSecrets(instance.Properties)) > 0 {
+			resolved, err := resolveSecrets(ctx, registry, instance)
+			if err != nil {
+				return fail(fmt.Errorf("failed to resolve secrets for %s: %w", nodeID, err))
+			}
+			instance.Properties = resolved
+		}
 
-				// Set node status to running
-				dag.SetNodeStatus(nodeID, executor.StatusRunning, nil)
-
-				// Extract provider name
-				providerName := extractProviderName(node.Instance.Kind)
-				provider, exists := registry.Get(providerName)
-				if !exists {
-					err := fmt.Errorf("provider %s not found", providerName)
-					dag.SetNodeStatus(nodeID, executor.StatusFailed, err)
-					resultChan <- nodeResult{nodeID: nodeID, err: err}
-					return
+		// Execute the appropriate action
+		var err error
+		var change *config.Change
+		var retries int
+
+		// A moved resource is renamed first, so the update below applies to it under its new ID
+		if driftResult.MovedFrom != "" {
+			if dryRun {
+				slog.Info("would rename resource", "resource", nodeID, "from", driftResult.MovedFrom)
+			} else {
+				slog.Info("renaming resource", "resource", nodeID, "from", driftResult.MovedFrom)
+				if err := renameResource(ctx, provider, instance, driftResult.MovedFrom); err != nil {
+					return fail(err)
 				}
+			}
+			change = &config.Change{
+				Type:         config.ChangeTypeUpdate,
+				ResourceID:   nodeID,
+				ResourceKind: node.Instance.Kind,
+				ResourceName: node.Instance.Name,
+				MovedFrom:    driftResult.MovedFrom,
+				Simulated:    dryRun,
+			}
+		}
+
+		if driftResult.CurrentState == nil {
+			// Create resource
+			if dryRun {
+				slog.Info("would create resource", "resource", nodeID)
+			} else {
+				slog.Info("creating resource", "resource", nodeID)
+				retries, err = retryPolicy.Do(ctx, func(attempt int) error {
+					if attempt == 0 {
+						return providers.RunWithTimeout(ctx, instance, config.ChangeTypeCreate, func(ctx context.Context) error {
+							return provider.Create(ctx, instance)
+						})
+					}
 
-				// Execute the appropriate action
-				var err error
-				var change *config.Change
-				
-				if driftResult.CurrentState == nil {
-					// Create resource
-					fmt.Printf("+ Creating %s\n", nodeID)
-					err = provider.Create(ctx, node.Instance)
-					if err == nil {
-						change = &config.Change{
-							Type:         config.ChangeTypeCreate,
-							ResourceID:   nodeID,
-							ResourceKind: node.Instance.Kind,
-							ResourceName: node.Instance.Name,
-						}
+					// A create that failed part way may have left the resource
+					// behind, so update it rather than creating a duplicate
+					slog.Info("retrying resource", "resource", nodeID, "retry", attempt, "max_retries", retryPolicy.MaxRetries)
+					state, stateErr := provider.GetCurrentState(ctx, instance)
+					if stateErr != nil {
+						return stateErr
 					}
-				} else if driftResult.HasDrift {
-					// Update resource
-					fmt.Printf("~ Updating %s\n", nodeID)
-					err = provider.Update(ctx, node.Instance, driftResult.CurrentState)
-					if err == nil {
-						change = &config.Change{
-							Type:         config.ChangeTypeUpdate,
-							ResourceID:   nodeID,
-							ResourceKind: node.Instance.Kind,
-							ResourceName: node.Instance.Name,
-						}
+					if state != nil {
+						return providers.RunWithTimeout(ctx, instance, config.ChangeTypeUpdate, func(ctx context.Context) error {
+							return provider.Update(ctx, instance, state)
+						})
 					}
+					return providers.RunWithTimeout(ctx, instance, config.ChangeTypeCreate, func(ctx context.Context) error {
+						return provider.Create(ctx, instance)
+					})
+				})
+			}
+			if err == nil {
+				change = &config.Change{
+					Type:         config.ChangeTypeCreate,
+					ResourceID:   nodeID,
+					ResourceKind: node.Instance.Kind,
+					ResourceName: node.Instance.Name,
+					Simulated:    dryRun,
 				}
-
-				// Update node status
-				if err != nil {
-					fmt.Printf("✗ Failed to process %s: %v\n", nodeID, err)
-					dag.SetNodeStatus(nodeID, executor.StatusFailed, err)
-				} else {
-					fmt.Printf("✓ Completed %s\n", nodeID)
-					dag.SetNodeStatus(nodeID, executor.StatusCompleted, nil)
+			}
+		} else if driftResult.HasDrift && (driftResult.MovedFrom == "" || len(driftResult.Differences) > 0) {
+			// Update resource
+			if dryRun {
+				slog.Info("would update resource", "resource", nodeID)
+			} else {
+				slog.Info("updating resource", "resource", nodeID)
+				retries, err = retryPolicy.Do(ctx, func(attempt int) error {
+					if attempt > 0 {
+						slog.Info("retrying resource", "resource", nodeID, "retry", attempt, "max_retries", retryPolicy.MaxRetries)
+					}
+					return providers.RunWithTimeout(ctx, instance, config.ChangeTypeUpdate, func(ctx context.Context) error {
+						return provider.Update(ctx, instance, driftResult.CurrentState)
+					})
+				})
+			}
+			if err == nil {
+				change = &config.Change{
+					Type:         config.ChangeTypeUpdate,
+					ResourceID:   nodeID,
+					ResourceKind: node.Instance.Kind,
+					ResourceName: node.Instance.Name,
+					MovedFrom:    driftResult.MovedFrom,
+					Simulated:    dryRun,
 				}
+			}
+		}
 
-				resultChan <- nodeResult{nodeID: nodeID, change: change, err: err}
-			}(nodeID)
+		if retries > 0 {
+			mutex.Lock()
+			result.Retries[nodeID] = retries
+			mutex.Unlock()
 		}
 
-		// Collect results from all goroutines
-		for i := 0; i < len(level); i++ {
-			res := <-resultChan
-			if res.err != nil {
-				result.Errors = append(result.Errors, res.err)
-				result.Success = false
+		// The resource has changed, or may have if applying it failed part way, so its state
+		// cached by drift detection is stale
+		if needsApply && !dryRun {
+			detector.Invalidate(nodeID)
+			if driftResult.MovedFrom != "" {
+				detector.Invalidate(driftResult.MovedFrom)
 			}
-			if res.change != nil {
-				result.Changes = append(result.Changes, *res.change)
+		}
+
+		// Record the applied state so dependents and outputs can reference its attributes
+		if err == nil && change != nil && !dryRun && (len(node.Dependents) > 0 || outputResources[nodeID]) {
+			state, stateErr := detector.CurrentState(ctx, instance)
+			if stateErr != nil {
+				err = fmt.Errorf("failed to read state of %s after apply: %w", nodeID, stateErr)
+			} else if state != nil {
+				outputs.Set(nodeID, state)
+			}
+		}
+
+		if err != nil {
+			return fail(err)
+		}
+
+		slog.Info("completed resource", "resource", nodeID, "duration", time.Since(nodeStart).Round(time.Millisecond))
+		if journal != nil && !dryRun {
+			// The resource has been applied, so failing to record it mustn't fail it
+			if err := journal.Record(node.Instance); err != nil {
+				slog.Warn("failed to update commit journal", "resource", nodeID, "error", err)
+			}
+		}
+		if change != nil {
+			mutex.Lock()
+			result.Changes = append(result.Changes, *change)
+			mutex.Unlock()
+		}
+		return nil
+	})
+	result.Duration = time.Since(startTime)
+	result.States = outputs.Snapshot()
+
+	for _, node := range dag.GetAllNodes() {
+		if !node.StartedAt.IsZero() {
+			result.Durations[node.ID] = node.Duration()
+		}
+	}
+
+	// Dependents of failed resources were never started
+	for _, node := range dag.GetSkippedNodes() {
+		result.Skipped = append(result.Skipped, node.ID)
+	}
+	sort.Strings(result.Skipped)
+
+	// Once cancelled no further resources are started; changes already applied are kept in
+	// the result so they can be reported
+	if ctx.Err() != nil {
+		pending := 0
+		for _, node := range dag.GetAllNodes() {
+			if node.Status == executor.StatusPending {
+				pending++
 			}
 		}
+		if pending > 0 {
+			result.Errors = append(result.Errors, fmt.Errorf("stopped before %d resource%s started: %w", pending, pluralize(pending), interruptedError(ctx)))
+			result.Success = false
+		}
 	}
 
 	return result, nil
 }
 
+// ndjsonEventHandler writes each execution event to w as a line of JSON, for tools that
+// follow a commit's progress as it runs
+func ndjsonEventHandler(w io.Writer) executor.EventHandler {
+	encoder := json.NewEncoder(w)
+	return func(event executor.Event) {
+		// Progress events are best effort; a closed pipe mustn't fail the commit
+		_ = encoder.Encode(event)
+	}
+}
+
+// resolveSecrets replaces the secret references in an instance's properties with their
+// values, read from AWS Secrets Manager through the aws provider that manages the instance,
+// so an alias's region and assumed role apply. Secrets of resources other providers manage
+// are read through the unaliased aws provider.
+func resolveSecrets(ctx context.Context, registry *providers.ProviderRegistry, instance config.ResourceInstance) (map[string]interface{}, error) {
+	providerName := providers.ProviderKey(instance)
+	if providers.ProviderType(providerName) != "aws" {
+		providerName = "aws"
+	}
+	provider, exists := registry.Get(providerName)
+	if !exists {
+		return nil, fmt.Errorf("secret() requires the %s provider to be configured", providerName)
+	}
+	resolver, ok := provider.(providers.SecretResolver)
+	if !ok {
+		return nil, fmt.Errorf("provider %s can't resolve secrets", providerName)
+	}
+
+	return config.ResolveSecrets(instance.Properties, func(name string) (string, error) {
+		return resolver.ResolveSecret(ctx, name)
+	})
+}
+
+// renameResource renames the resource a moved block moves instance from, so that it is found
+// as instance from now on
+func renameResource(ctx context.Context, provider providers.Provider, instance config.ResourceInstance, movedFrom string) error {
+	renamer, ok := provider.(providers.Renamer)
+	if !ok || !renamer.CanRename(instance.Kind) {
+		return fmt.Errorf("can't move %s to %s: %s resources can't be renamed in place", movedFrom, instance.ID, instance.Kind)
+	}
+	if err := renamer.Rename(ctx, instance.Renamed(movedFrom), instance); err != nil {
+		return fmt.Errorf("failed to rename %s to %s: %w", movedFrom, instance.ID, err)
+	}
+	return nil
+}
+
+// rollbackCreates deletes the resources a failed commit created, most recently created first
+// so that dependents are deleted before their dependencies. Updates are left in place, since
+// the previous configuration of a resource isn't known. ctx should come from rollbackContext,
+// since the commit's own context may already have been cancelled.
+func rollbackCreates(ctx context.Context, dag *executor.DAG, registry *providers.ProviderRegistry, result *config.ExecutionResult) {
+	slog.Info("rolling back created resources")
+
+	for i := len(result.Changes) - 1; i >= 0; i-- {
+		change := result.Changes[i]
+		if change.Type != config.ChangeTypeCreate {
+			continue
+		}
+
+		if ctx.Err() != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("rollback stopped before deleting %s: %w", change.ResourceID, interruptedError(ctx)))
+			return
+		}
+
+		node, exists := dag.GetNode(change.ResourceID)
+		if !exists {
+			continue
+		}
+		provider, exists := registry.Get(extractProviderName(node.Instance))
+		if !exists {
+			continue
+		}
+
+		slog.Info("deleting resource", "resource", change.ResourceID)
+		err := providers.RunWithTimeout(ctx, node.Instance, config.ChangeTypeDelete, func(ctx context.Context) error {
+			return provider.Delete(ctx, node.Instance)
+		})
+		if err != nil {
+			slog.Error("failed to roll back resource", "resource", change.ResourceID, "error", err)
+			result.Errors = append(result.Errors, fmt.Errorf("failed to roll back %s: %w", change.ResourceID, err))
+			continue
+		}
+		result.RolledBack = append(result.RolledBack, change.ResourceID)
+	}
+
+	for _, change := range result.Changes {
+		if change.Type == config.ChangeTypeUpdate {
+			slog.Warn("updated resource can't be rolled back", "resource", change.ResourceID)
+		}
+	}
+}
+
+// writeDOTGraph writes the DAG in Graphviz DOT format to path, or to standard output when
+// path is empty
+func writeDOTGraph(dag *executor.DAG, path string) error {
+	if path == "" {
+		fmt.Println("\n--- Execution Graph (DOT) ---")
+		fmt.Print(dag.ToDOT())
+		return nil
+	}
+
+	if err := os.WriteFile(path, []byte(dag.ToDOT()), 0644); err != nil {
+		return fmt.Errorf("failed to write graph file: %w", err)
+	}
+	fmt.Printf("\nGraph written to %s. Render it with 'dot -Tpng %s -o graph.png'.\n", path, path)
+	return nil
+}
+
 func displayDAGVisualization(dag *executor.DAG) {
 	fmt.Println("\n--- Execution Plan (DAG) ---")
 	
@@ -251,33 +734,172 @@ func displayDAGVisualization(dag *executor.DAG) {
 	fmt.Println()
 }
 
-func displayExecutionResults(result *config.ExecutionResult, duration time.Duration) {
+// redactOutputs replaces the values of sensitive outputs unless showSensitive is set
+func redactOutputs(declared map[string]config.Output, values map[string]interface{}, showSensitive bool) map[string]interface{} {
+	if showSensitive {
+		return values
+	}
+	redacted := make(map[string]interface{}, len(values))
+	for name, value := range values {
+		if declared[name].Sensitive {
+			value = drift.RedactedValue
+		}
+		redacted[name] = value
+	}
+	return redacted
+}
+
+// displayOutputs prints output values in name order
+func displayOutputs(outputs map[string]interface{}) {
+	if len(outputs) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(outputs))
+	for name := range outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Printf("\nOutputs:\n")
+	for _, name := range names {
+		fmt.Printf("  %s = %v\n", name, outputs[name])
+	}
+}
+
+// commitResult builds the formatted result of a commit from the executed DAG. Levels
+// list the resources that were started, each level's duration spanning from the first
+// of them starting to the last finishing.
+func commitResult(dag *executor.DAG, result *config.ExecutionResult) output.CommitResult {
+	commit := output.CommitResult{
+		Success:          result.Success,
+		ResourcesApplied: len(result.Changes),
+		ExecutionLevels:  make([]output.ExecutionLevel, 0),
+		TotalDuration:    result.Duration,
+	}
+	if len(result.Errors) > 0 {
+		commit.Error = errors.Join(result.Errors...)
+	}
+
+	for i, levelIDs := range dag.GetExecutionOrder() {
+		level := output.ExecutionLevel{
+			Level:             i + 1,
+			Resources:         make([]string, 0, len(levelIDs)),
+			ResourceDurations: make(map[string]time.Duration),
+		}
+
+		var first, last time.Time
+		for _, nodeID := range levelIDs {
+			node, exists := dag.GetNode(nodeID)
+			if !exists || node.StartedAt.IsZero() {
+				continue
+			}
+			level.Resources = append(level.Resources, nodeID)
+			level.ResourceDurations[nodeID] = node.Duration()
+			if first.IsZero() || node.StartedAt.Before(first) {
+				first = node.StartedAt
+			}
+			if node.FinishedAt.After(last) {
+				last = node.FinishedAt
+			}
+		}
+		if len(level.Resources) == 0 {
+			continue
+		}
+		level.Duration = last.Sub(first)
+		commit.ExecutionLevels = append(commit.ExecutionLevels, level)
+	}
+
+	return commit
+}
+
+// resultSymbols decorates the lines of commit and dismantle results that aren't changes.
+// Without color, symbols are left out or replaced by plain text, as in the human formatter.
+type resultSymbols struct {
+	color bool
+}
+
+// marker returns the symbol leading a list item, or a plain dash without color
+func (s resultSymbols) marker(symbol string) string {
+	if !s.color {
+		return "-"
+	}
+	return symbol
+}
+
+// headline returns text led by symbol, or the text alone without color
+func (s resultSymbols) headline(symbol, text string) string {
+	if !s.color {
+		return text
+	}
+	return symbol + " " + text
+}
+
+func displayExecutionResults(result *config.ExecutionResult, symbols resultSymbols) {
 	fmt.Printf("\n--- Execution Complete ---\n")
-	
+
 	if result.Success {
-		fmt.Printf(" Commit complete (duration: %v)\n", duration.Round(time.Second))
+		fmt.Printf(" Commit complete (duration: %v)\n", result.Duration.Round(time.Second))
 	} else {
-		fmt.Printf("✗ Commit completed with errors (duration: %v)\n", duration.Round(time.Second))
+		fmt.Println(symbols.headline("✗", fmt.Sprintf("Commit completed with errors (duration: %v)", result.Duration.Round(time.Second))))
 	}
 
 	if len(result.Changes) > 0 {
 		fmt.Printf("\nChanges applied:\n")
 		for _, change := range result.Changes {
+			marker := ""
+			if change.Simulated {
+				marker = " (simulated)"
+			}
+			if duration, ok := result.Durations[change.ResourceID]; ok && !change.Simulated {
+				marker = fmt.Sprintf(" (%v)", duration.Round(time.Millisecond))
+			}
 			switch change.Type {
 			case config.ChangeTypeCreate:
-				fmt.Printf("+ Created %s\n", change.ResourceID)
+				fmt.Printf("+ Created %s%s\n", change.ResourceID, marker)
 			case config.ChangeTypeUpdate:
-				fmt.Printf("~ Updated %s\n", change.ResourceID)
+				if change.MovedFrom != "" {
+					fmt.Printf("~ Renamed %s to %s%s\n", change.MovedFrom, change.ResourceID, marker)
+				} else {
+					fmt.Printf("~ Updated %s%s\n", change.ResourceID, marker)
+				}
 			case config.ChangeTypeDelete:
-				fmt.Printf("- Deleted %s\n", change.ResourceID)
+				fmt.Printf("- Deleted %s%s\n", change.ResourceID, marker)
 			}
 		}
 	}
 
+	if len(result.RolledBack) > 0 {
+		fmt.Printf("\nRolled back:\n")
+		for _, resourceID := range result.RolledBack {
+			fmt.Printf("- Deleted %s\n", resourceID)
+		}
+	}
+
+	if len(result.Retries) > 0 {
+		resourceIDs := make([]string, 0, len(result.Retries))
+		for resourceID := range result.Retries {
+			resourceIDs = append(resourceIDs, resourceID)
+		}
+		sort.Strings(resourceIDs)
+
+		fmt.Printf("\nRetried after transient errors:\n")
+		for _, resourceID := range resourceIDs {
+			fmt.Printf("%s %s (retried %d time%s)\n", symbols.marker("↻"), resourceID, result.Retries[resourceID], pluralize(result.Retries[resourceID]))
+		}
+	}
+
+	if len(result.Skipped) > 0 {
+		fmt.Printf("\nSkipped because a dependency failed:\n")
+		for _, resourceID := range result.Skipped {
+			fmt.Printf("%s %s\n", symbols.marker("⊘"), resourceID)
+		}
+	}
+
 	if len(result.Errors) > 0 {
 		fmt.Printf("\nErrors encountered:\n")
 		for _, err := range result.Errors {
-			fmt.Printf("✗ %v\n", err)
+			fmt.Printf("%s %v\n", symbols.marker("✗"), err)
 		}
 	}
 }
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/ataiva-software/runestone/internal/drift"
+	"github.com/ataiva-software/runestone/internal/executor"
+	"github.com/ataiva-software/runestone/internal/providers"
+	"github.com/ataiva-software/runestone/internal/providers/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// commitFixture runs executeChanges against a fake provider for the test kind
+type commitFixture struct {
+	provider  *fake.Provider
+	registry  *providers.ProviderRegistry
+	instances []config.ResourceInstance
+	dag       *executor.DAG
+}
+
+func newCommitFixture(instances ...config.ResourceInstance) *commitFixture {
+	provider := fake.New("test:resource:type")
+	registry := providers.NewRegistry()
+	registry.Register("test", provider)
+	return &commitFixture{provider: provider, registry: registry, instances: instances}
+}
+
+// execute detects drift for every instance and applies the changes
+func (f *commitFixture) execute(t *testing.T, journal *executor.Journal) *config.ExecutionResult {
+	t.Helper()
+
+	dag, err := executor.NewDAG(f.instances)
+	require.NoError(t, err)
+	f.dag = dag
+	detector := drift.NewDetector(f.registry)
+	driftResults := make(map[string]*providers.DriftResult, len(f.instances))
+	for _, instance := range f.instances {
+		driftResults[instance.ID], err = detector.DetectDrift(context.Background(), instance)
+		require.NoError(t, err)
+	}
+
+	result, err := executeChanges(context.Background(), dag, f.registry, detector, driftResults, nil, journal, 1, executor.RetryPolicy{}, false, true)
+	require.NoError(t, err)
+	return result
+}
+
+func testInstance(name string, properties map[string]interface{}) config.ResourceInstance {
+	return config.ResourceInstance{
+		ID:         "test:resource:type." + name,
+		Kind:       "test:resource:type",
+		Name:       name,
+		Properties: properties,
+	}
+}
+
+func TestExecuteChanges_ContinueSkipsJournaledResources(t *testing.T) {
+	applied := testInstance("applied", map[string]interface{}{"size": 2})
+	missing := testInstance("missing", map[string]interface{}{"size": 1})
+	pending := testInstance("pending", map[string]interface{}{"size": 3})
+	fixture := newCommitFixture(applied, missing, pending)
+
+	// The previous commit applied two resources; one of them has since been deleted
+	journal, err := executor.NewJournal(filepath.Join(t.TempDir(), "journal.json"))
+	require.NoError(t, err)
+	require.NoError(t, journal.Record(applied))
+	require.NoError(t, journal.Record(missing))
+	fixture.provider.SetState(applied.ID, map[string]interface{}{"size": 1})
+	fixture.provider.SetState(pending.ID, map[string]interface{}{"size": 1})
+
+	result := fixture.execute(t, journal)
+	assert.True(t, result.Success)
+
+	// A journaled resource that still exists isn't applied again, even though it drifted
+	assert.Equal(t, []string{pending.ID}, fixture.provider.Updated())
+	assert.Equal(t, []string{pending.ID}, changeIDs(result, config.ChangeTypeUpdate))
+
+	// A journaled resource that no longer exists is created again
+	assert.Equal(t, []string{missing.ID}, fixture.provider.Created())
+}
+
+func TestRollbackCreates(t *testing.T) {
+	created := testInstance("created", map[string]interface{}{"size": 1})
+	failed := testInstance("failed", map[string]interface{}{"size": 1})
+	failed.DependsOn = []string{created.ID}
+	fixture := newCommitFixture(created, failed)
+	fixture.provider.FailOn(fake.OperationCreate, failed.ID, errors.New("quota exceeded"))
+
+	result := fixture.execute(t, nil)
+	require.False(t, result.Success)
+
+	// The rollback runs in its own context, so it still works after the commit was interrupted
+	ctx, cancel := rollbackContext()
+	defer cancel()
+	rollbackCreates(ctx, fixture.dag, fixture.registry, result)
+
+	assert.Equal(t, []string{created.ID}, fixture.provider.Deleted())
+	assert.Equal(t, []string{created.ID}, result.RolledBack)
+	assert.Len(t, result.Errors, 1)
+}
+
+func TestRollbackCreates_Cancelled(t *testing.T) {
+	created := testInstance("created", map[string]interface{}{"size": 1})
+	failed := testInstance("failed", map[string]interface{}{"size": 1})
+	failed.DependsOn = []string{created.ID}
+	fixture := newCommitFixture(created, failed)
+	fixture.provider.FailOn(fake.OperationCreate, failed.ID, errors.New("quota exceeded"))
+
+	result := fixture.execute(t, nil)
+	require.False(t, result.Success)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rollbackCreates(ctx, fixture.dag, fixture.registry, result)
+
+	assert.Empty(t, fixture.provider.Deleted())
+	require.Len(t, result.Errors, 2)
+	assert.EqualError(t, result.Errors[1], "rollback stopped before deleting "+created.ID+": interrupted: context canceled")
+}
+
+func changeIDs(result *config.ExecutionResult, changeType config.ChangeType) []string {
+	var ids []string
+	for _, change := range result.Changes {
+		if change.Type == changeType {
+			ids = append(ids, change.ResourceID)
+		}
+	}
+	return ids
+}
+
+func TestResultSymbols(t *testing.T) {
+	decorated := resultSymbols{color: true}
+	assert.Equal(t, "↻", decorated.marker("↻"))
+	assert.Equal(t, "✗ Commit completed with errors", decorated.headline("✗", "Commit completed with errors"))
+
+	plain := resultSymbols{}
+	assert.Equal(t, "-", plain.marker("↻"))
+	assert.Equal(t, "Commit completed with errors", plain.headline("✗", "Commit completed with errors"))
+}
+
+func TestResolveSecrets(t *testing.T) {
+	bucket := func(provider string) config.ResourceInstance {
+		return config.ResourceInstance{
+			ID:         "aws:s3:bucket.logs",
+			Kind:       "aws:s3:bucket",
+			Name:       "logs",
+			Provider:   provider,
+			Properties: map[string]interface{}{"token": `${secret("app/token")}`},
+		}
+	}
+	secrets := func(value string) *secretsProvider {
+		return &secretsProvider{Provider: fake.New(), secrets: map[string]string{"app/token": value}}
+	}
+
+	registry := providers.NewRegistry()
+	registry.Register("aws", secrets("default"))
+	registry.Register("aws.prod", secrets("prod"))
+	registry.Register("test", fake.New("test:resource:type"))
+
+	resolved, err := resolveSecrets(context.Background(), registry, bucket(""))
+	require.NoError(t, err)
+	assert.Equal(t, "default", resolved["token"])
+
+	// An aliased resource reads the secret with its own provider's account and region
+	resolved, err = resolveSecrets(context.Background(), registry, bucket("aws.prod"))
+	require.NoError(t, err)
+	assert.Equal(t, "prod", resolved["token"])
+
+	// Resources of other providers read secrets through the unaliased aws provider
+	instance := testInstance("app", map[string]interface{}{"token": `${secret("app/token")}`})
+	resolved, err = resolveSecrets(context.Background(), registry, instance)
+	require.NoError(t, err)
+	assert.Equal(t, "default", resolved["token"])
+
+	// Only an alias is configured
+	aliased := providers.NewRegistry()
+	aliased.Register("aws.prod", secrets("prod"))
+	resolved, err = resolveSecrets(context.Background(), aliased, bucket("aws.prod"))
+	require.NoError(t, err)
+	assert.Equal(t, "prod", resolved["token"])
+
+	_, err = resolveSecrets(context.Background(), aliased, bucket(""))
+	assert.EqualError(t, err, "secret() requires the aws provider to be configured")
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// commandContext returns the context for a command's provider operations. It is cancelled
+// on SIGINT or SIGTERM, so in-flight operations return promptly, and once --timeout elapses.
+func commandContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	ctx, release := signalContext(cmd.Context(), "Interrupted, cancelling in-flight operations")
+
+	ctx, cancelTimeout := timeoutContext(cmd, ctx)
+	return ctx, func() {
+		cancelTimeout()
+		release()
+	}
+}
+
+// signalContext returns a context that is cancelled, after printing message, on the first
+// SIGINT or SIGTERM. The default handling is then restored, so a second Ctrl+C exits at once.
+func signalContext(parent context.Context, message string) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-signals:
+			signal.Stop(signals)
+			fmt.Fprintf(os.Stderr, "\n%s (press Ctrl+C again to exit immediately)\n", message)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(signals)
+		cancel()
+	}
+}
+
+// timeoutContext applies --timeout to a context, without any signal handling
+func timeoutContext(cmd *cobra.Command, parent context.Context) (context.Context, context.CancelFunc) {
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	if timeout <= 0 {
+		return context.WithCancel(parent)
+	}
+	return context.WithTimeout(parent, timeout)
+}
+
+// rollbackTimeout bounds how long a rollback may run after a failed commit
+const rollbackTimeout = 30 * time.Minute
+
+// rollbackContext returns the context for rolling back a failed commit. It is independent of
+// the command's context, which has usually been cancelled by an interrupt or --timeout by
+// the time a rollback starts, and is cancelled by the next SIGINT or SIGTERM instead.
+func rollbackContext() (context.Context, context.CancelFunc) {
+	ctx, release := signalContext(context.Background(), "Interrupted, stopping rollback")
+
+	ctx, cancelTimeout := context.WithTimeout(ctx, rollbackTimeout)
+	return ctx, func() {
+		cancelTimeout()
+		release()
+	}
+}
+
+// interruptedError describes why a command's context was cancelled
+func interruptedError(ctx context.Context) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out: %w", ctx.Err())
+	}
+	return fmt.Errorf("interrupted: %w", ctx.Err())
+}
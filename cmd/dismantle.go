@@ -3,13 +3,13 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/ataiva-software/runestone/internal/config"
 	"github.com/ataiva-software/runestone/internal/drift"
 	"github.com/ataiva-software/runestone/internal/executor"
 	"github.com/ataiva-software/runestone/internal/providers"
-	"github.com/ataiva-software/runestone/internal/providers/aws"
 	"github.com/spf13/cobra"
 )
 
@@ -24,9 +24,10 @@ var dismantleCmd = &cobra.Command{
 }
 
 func init() {
-	dismantleCmd.Flags().StringP("config", "c", "infra.yaml", "Path to the configuration file")
+	dismantleCmd.Flags().StringP("config", "c", "infra.yaml", "Path to the configuration file, a directory or glob of files to merge, or - for standard input")
+	addVariableFlags(dismantleCmd)
 	dismantleCmd.Flags().Bool("auto-approve", false, "Skip interactive approval")
-	dismantleCmd.Flags().Bool("force", false, "Force deletion even if resources have dependencies")
+	dismantleCmd.Flags().Bool("force", false, "Force deletion even if resources have dependencies or deletion protection")
 	dismantleCmd.Flags().StringP("output", "o", "human", "Output format (human, json, markdown)")
 }
 
@@ -34,11 +35,17 @@ func runDismantle(cmd *cobra.Command, args []string) error {
 	configFile, _ := cmd.Flags().GetString("config")
 	autoApprove, _ := cmd.Flags().GetBool("auto-approve")
 	force, _ := cmd.Flags().GetBool("force")
+	if configFile == config.StdinPath && !autoApprove {
+		return fmt.Errorf("reading the configuration from standard input requires --auto-approve, since approval is read from standard input too")
+	}
 
-	fmt.Println("️  Preparing to dismantle infrastructure...")
+	slog.Info("preparing to dismantle infrastructure")
 
 	// Parse configuration
-	parser := config.NewParser()
+	parser, err := newConfigParser(cmd)
+	if err != nil {
+		return err
+	}
 	cfg, err := parser.ParseFile(configFile)
 	if err != nil {
 		return fmt.Errorf("failed to parse configuration: %w", err)
@@ -46,34 +53,35 @@ func runDismantle(cmd *cobra.Command, args []string) error {
 
 	// Set up provider registry
 	registry := providers.NewProviderRegistry()
-	ctx := context.Background()
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
 
 	// Initialize providers
 	for providerName, providerConfig := range cfg.Providers {
-		var provider providers.Provider
-		switch providerName {
-		case "aws":
-			provider = aws.NewProvider()
-		default:
-			return fmt.Errorf("unsupported provider: %s", providerName)
+		provider, err := newProvider(providerName)
+		if err != nil {
+			return err
 		}
 
-		providerConfigMap := make(map[string]interface{})
-		providerConfigMap["region"] = providerConfig.Region
-		providerConfigMap["profile"] = providerConfig.Profile
-
-		if err := provider.Initialize(ctx, providerConfigMap); err != nil {
+		if err := provider.Initialize(ctx, providerConfig.Settings()); err != nil {
 			return fmt.Errorf("failed to initialize provider %s: %w", providerName, err)
 		}
 
 		registry.Register(providerName, provider)
 	}
 
+	if err := loadModules(cfg, parser); err != nil {
+		return err
+	}
+
 	// Expand resources
 	instances, err := parser.ExpandResources(cfg.Resources)
 	if err != nil {
 		return fmt.Errorf("failed to expand resources: %w", err)
 	}
+	if err := validateProviderKeys(instances); err != nil {
+		return err
+	}
 
 	// Detect which resources actually exist
 	detector := drift.NewDetector(registry)
@@ -121,6 +129,11 @@ func runDismantle(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create execution DAG: %w", err)
 	}
 
+	// Let providers override safeguards such as deletion protection
+	if force {
+		ctx = providers.WithForceDelete(ctx)
+	}
+
 	// Execute deletions
 	startTime := time.Now()
 	result, err := executeDeletions(ctx, dag, registry, force)
@@ -131,9 +144,12 @@ func runDismantle(cmd *cobra.Command, args []string) error {
 	}
 
 	// Display results
-	displayDismantleResults(result, duration)
+	displayDismantleResults(result, duration, resultSymbols{color: useColor(cmd)})
 
-	return nil
+	if ctx.Err() != nil {
+		return fmt.Errorf("dismantle %s; %d resource%s deleted before stopping", interruptedError(ctx), len(result.Changes), pluralize(len(result.Changes)))
+	}
+	return failedResourcesError(result)
 }
 
 func executeDeletions(ctx context.Context, dag *executor.DAG, registry *providers.ProviderRegistry, force bool) (*config.ExecutionResult, error) {
@@ -149,7 +165,14 @@ func executeDeletions(ctx context.Context, dag *executor.DAG, registry *provider
 	// Reverse the order for safe deletion
 	for i := len(executionOrder) - 1; i >= 0; i-- {
 		level := executionOrder[i]
-		fmt.Printf("\n--- Deletion Level %d ---\n", len(executionOrder)-i)
+
+		if ctx.Err() != nil {
+			result.Errors = append(result.Errors, fmt.Errorf("stopped before deletion level %d: %w", len(executionOrder)-i, interruptedError(ctx)))
+			result.Success = false
+			break
+		}
+
+		slog.Debug("deleting level", "level", len(executionOrder)-i)
 
 		// Delete all nodes in this level
 		for _, nodeID := range level {
@@ -162,7 +185,7 @@ func executeDeletions(ctx context.Context, dag *executor.DAG, registry *provider
 			dag.SetNodeStatus(nodeID, executor.StatusRunning, nil)
 
 			// Extract provider name
-			providerName := extractProviderName(node.Instance.Kind)
+			providerName := extractProviderName(node.Instance)
 			provider, exists := registry.Get(providerName)
 			if !exists {
 				err := fmt.Errorf("provider %s not found", providerName)
@@ -173,19 +196,21 @@ func executeDeletions(ctx context.Context, dag *executor.DAG, registry *provider
 			}
 
 			// Delete resource
-			fmt.Printf("- Deleting %s\n", nodeID)
-			err := provider.Delete(ctx, node.Instance)
+			slog.Info("deleting resource", "resource", nodeID)
+			err := providers.RunWithTimeout(ctx, node.Instance, config.ChangeTypeDelete, func(ctx context.Context) error {
+				return provider.Delete(ctx, node.Instance)
+			})
 
 			// Update node status
 			if err != nil {
-				fmt.Printf("✗ Failed to delete %s: %v\n", nodeID, err)
+				slog.Error("failed to delete resource", "resource", nodeID, "error", err)
 				dag.SetNodeStatus(nodeID, executor.StatusFailed, err)
 				result.Errors = append(result.Errors, err)
 				if !force {
 					result.Success = false
 				}
 			} else {
-				fmt.Printf("✓ Deleted %s\n", nodeID)
+				slog.Info("deleted resource", "resource", nodeID)
 				dag.SetNodeStatus(nodeID, executor.StatusCompleted, nil)
 				result.Changes = append(result.Changes, config.Change{
 					Type:         config.ChangeTypeDelete,
@@ -200,13 +225,13 @@ func executeDeletions(ctx context.Context, dag *executor.DAG, registry *provider
 	return result, nil
 }
 
-func displayDismantleResults(result *config.ExecutionResult, duration time.Duration) {
+func displayDismantleResults(result *config.ExecutionResult, duration time.Duration, symbols resultSymbols) {
 	fmt.Printf("\n--- Dismantle Complete ---\n")
 	
 	if result.Success {
 		fmt.Printf(" Dismantle complete (duration: %v)\n", duration.Round(time.Second))
 	} else {
-		fmt.Printf("✗ Dismantle completed with errors (duration: %v)\n", duration.Round(time.Second))
+		fmt.Println(symbols.headline("✗", fmt.Sprintf("Dismantle completed with errors (duration: %v)", duration.Round(time.Second))))
 	}
 
 	if len(result.Changes) > 0 {
@@ -219,7 +244,7 @@ func displayDismantleResults(result *config.ExecutionResult, duration time.Durat
 	if len(result.Errors) > 0 {
 		fmt.Printf("\nErrors encountered:\n")
 		for _, err := range result.Errors {
-			fmt.Printf("✗ %v\n", err)
+			fmt.Printf("%s %v\n", symbols.marker("✗"), err)
 		}
 	}
 }
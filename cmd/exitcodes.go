@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ataiva-software/runestone/internal/config"
+)
+
+// Exit codes returned by runestone commands
+const (
+	ExitOK               = 0 // Success with nothing to change
+	ExitError            = 1 // Operational failure
+	ExitChanges          = 2 // Changes or drift are present
+	ExitPolicyViolations = 3 // Error-level policy violations
+)
+
+// exitError ends a command with a specific exit code. With a nil err the command's own
+// output already explains the result, so no error message is printed.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	if e.err == nil {
+		return fmt.Sprintf("exit status %d", e.code)
+	}
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// withExitCode returns an error that makes the process exit with code
+func withExitCode(code int, err error) error {
+	return &exitError{code: code, err: err}
+}
+
+// failedResourcesError returns the error that ends a commit or dismantle with ExitError when
+// any of its resources failed, or nil when every resource succeeded
+func failedResourcesError(result *config.ExecutionResult) error {
+	if result.Success {
+		return nil
+	}
+	return withExitCode(ExitError, fmt.Errorf("%d resource(s) failed", len(result.Errors)))
+}
+
+// exitCodeFor maps a command error to the process exit code and the message to print, if any
+func exitCodeFor(err error) (int, string) {
+	if err == nil {
+		return ExitOK, ""
+	}
+
+	var codeErr *exitError
+	if errors.As(err, &codeErr) {
+		if codeErr.err == nil {
+			return codeErr.code, ""
+		}
+		return codeErr.code, codeErr.err.Error()
+	}
+
+	return ExitError, err.Error()
+}
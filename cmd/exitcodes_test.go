@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFailedResourcesError(t *testing.T) {
+	assert.NoError(t, failedResourcesError(&config.ExecutionResult{Success: true}))
+
+	err := failedResourcesError(&config.ExecutionResult{
+		Success: false,
+		Errors:  []error{errors.New("create failed"), errors.New("update failed")},
+	})
+	code, message := exitCodeFor(err)
+	assert.Equal(t, ExitError, code)
+	assert.Equal(t, "2 resource(s) failed", message)
+}
+
+func TestExitCodeFor(t *testing.T) {
+	code, message := exitCodeFor(nil)
+	assert.Equal(t, ExitOK, code)
+	assert.Empty(t, message)
+
+	code, message = exitCodeFor(withExitCode(ExitChanges, nil))
+	assert.Equal(t, ExitChanges, code)
+	assert.Empty(t, message)
+
+	code, message = exitCodeFor(errors.New("boom"))
+	assert.Equal(t, ExitError, code)
+	assert.Equal(t, "boom", message)
+}
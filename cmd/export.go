@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ataiva-software/runestone/internal/executor"
+	"github.com/ataiva-software/runestone/internal/output"
+	"github.com/ataiva-software/runestone/internal/providers"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the live state of configured resources",
+	Long: `Export reads the live state of every resource in the configuration:
+- Parses the configuration and expands resources and modules
+- Reads each resource's current state from its provider
+- Prints the states keyed by resource ID
+
+No drift is computed and no changes are planned or made. Resources that don't
+exist yet are reported as missing.`,
+	RunE: runExport,
+}
+
+func init() {
+	exportCmd.Flags().StringP("config", "c", "infra.yaml", "Path to the configuration file, a directory or glob of files to merge, or - for standard input")
+	addVariableFlags(exportCmd)
+	exportCmd.Flags().StringP("output", "o", "json", "Output format (json, yaml, human, markdown, junit)")
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	configFile, _ := cmd.Flags().GetString("config")
+	outputFormat, _ := cmd.Flags().GetString("output")
+
+	startTime := time.Now()
+	formatter := newFormatter(cmd, outputFormat)
+
+	result := output.ExportResult{
+		Resources: []output.ExportedResource{},
+	}
+
+	fail := func(err error) error {
+		result.Error = err
+		result.Duration = time.Since(startTime)
+		formatted, _ := formatter.FormatExportResult(result)
+		fmt.Print(formatted)
+		return err
+	}
+
+	// Parse configuration
+	parser, err := newConfigParser(cmd)
+	if err != nil {
+		return fail(err)
+	}
+	cfg, err := parser.ParseFile(configFile)
+	if err != nil {
+		return fail(fmt.Errorf("failed to parse configuration: %w", err))
+	}
+
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	// Initialize providers
+	registry := providers.NewProviderRegistry()
+	for providerName, providerConfig := range cfg.Providers {
+		provider, err := newProvider(providerName)
+		if err != nil {
+			return fail(err)
+		}
+		if err := provider.Initialize(ctx, providerConfig.Settings()); err != nil {
+			return fail(fmt.Errorf("failed to initialize provider %s: %w", providerName, err))
+		}
+		registry.Register(providerName, provider)
+	}
+
+	if err := loadModules(cfg, parser); err != nil {
+		return fail(err)
+	}
+
+	// Expand resources
+	instances, err := parser.ExpandResources(cfg.Resources)
+	if err != nil {
+		return fail(fmt.Errorf("failed to expand resources: %w", err))
+	}
+	if err := validateProviderKeys(instances); err != nil {
+		return fail(err)
+	}
+
+	// Read states in dependency order so references to other resources' attributes can be
+	// resolved from their live state first
+	dag, err := executor.NewDAG(instances)
+	if err != nil {
+		return fail(fmt.Errorf("failed to build dependency graph: %w", err))
+	}
+
+	outputs := make(map[string]map[string]interface{})
+	failed := 0
+	for _, level := range dag.GetExecutionOrder() {
+		for _, nodeID := range level {
+			if ctx.Err() != nil {
+				return fail(interruptedError(ctx))
+			}
+
+			node, _ := dag.GetNode(nodeID)
+			instance := node.Instance
+			if len(executor.FindReferences(instance.Properties)) > 0 {
+				if resolved, err := executor.ResolveReferences(instance.Properties, outputs); err == nil {
+					instance.Properties = resolved
+				}
+			}
+
+			exported := output.ExportedResource{
+				ID:   instance.ID,
+				Kind: instance.Kind,
+			}
+
+			providerName := extractProviderName(instance)
+			provider, exists := registry.Get(providerName)
+			if !exists {
+				exported.Error = fmt.Sprintf("provider %s is not configured", providerName)
+			} else if state, err := provider.GetCurrentState(ctx, instance); err != nil {
+				exported.Error = err.Error()
+			} else if state != nil {
+				exported.Exists = true
+				exported.State = state
+				outputs[instance.ID] = state
+			}
+
+			if exported.Error != "" {
+				failed++
+			}
+			result.Resources = append(result.Resources, exported)
+		}
+	}
+
+	if failed > 0 {
+		return fail(fmt.Errorf("failed to read the state of %d resources", failed))
+	}
+
+	result.Success = true
+	result.Duration = time.Since(startTime)
+
+	formatted, err := formatter.FormatExportResult(result)
+	if err != nil {
+		return fmt.Errorf("failed to format output: %w", err)
+	}
+	fmt.Print(formatted)
+
+	return nil
+}
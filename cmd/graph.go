@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ataiva-software/runestone/internal/executor"
+	"github.com/spf13/cobra"
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Show the dependency graph of configured resources",
+	Long: `Graph shows how resources depend on each other without contacting any provider:
+- Parses the configuration and expands resources and modules
+- Builds the dependency graph used by commit
+- Prints the execution order, or the graph as Graphviz DOT or JSON
+
+Circular dependencies are reported with the chain of resources that forms them.`,
+	RunE: runGraph,
+}
+
+func init() {
+	graphCmd.Flags().StringP("config", "c", "infra.yaml", "Path to the configuration file, a directory or glob of files to merge, or - for standard input")
+	addVariableFlags(graphCmd)
+	graphCmd.Flags().StringP("output", "o", "text", "Output format (text, dot, json)")
+	graphCmd.Flags().String("out", "", "Write the graph to a file instead of standard output")
+}
+
+// graphOutput is the JSON form of the dependency graph
+type graphOutput struct {
+	Levels       [][]string          `json:"levels"`
+	Dependencies map[string][]string `json:"dependencies"`
+}
+
+func runGraph(cmd *cobra.Command, args []string) error {
+	configFile, _ := cmd.Flags().GetString("config")
+	outputFormat, _ := cmd.Flags().GetString("output")
+	outFile, _ := cmd.Flags().GetString("out")
+
+	if outputFormat != "text" && outputFormat != "dot" && outputFormat != "json" {
+		return fmt.Errorf("unsupported output format: %s (expected text, dot or json)", outputFormat)
+	}
+
+	// Parse configuration
+	parser, err := newConfigParser(cmd)
+	if err != nil {
+		return err
+	}
+	cfg, err := parser.ParseFile(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to parse configuration: %w", err)
+	}
+
+	if err := loadModules(cfg, parser); err != nil {
+		return err
+	}
+
+	instances, err := parser.ExpandResources(cfg.Resources)
+	if err != nil {
+		return fmt.Errorf("failed to expand resources: %w", err)
+	}
+
+	dag, err := executor.NewDAG(instances)
+	if err != nil {
+		return fmt.Errorf("failed to build dependency graph: %w", err)
+	}
+
+	var rendered string
+	switch outputFormat {
+	case "dot":
+		rendered = dag.ToDOT()
+	case "json":
+		graph := graphOutput{
+			Levels:       dag.GetExecutionOrder(),
+			Dependencies: make(map[string][]string),
+		}
+		for nodeID, node := range dag.GetAllNodes() {
+			dependencies := append([]string{}, node.Dependencies...)
+			sort.Strings(dependencies)
+			graph.Dependencies[nodeID] = dependencies
+		}
+		data, err := json.MarshalIndent(graph, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format graph: %w", err)
+		}
+		rendered = string(data) + "\n"
+	default:
+		var b strings.Builder
+		for levelIndex, level := range dag.GetExecutionOrder() {
+			fmt.Fprintf(&b, "Level %d:\n", levelIndex+1)
+			for _, nodeID := range level {
+				node, _ := dag.GetNode(nodeID)
+				if len(node.Dependencies) == 0 {
+					fmt.Fprintf(&b, "  %s\n", nodeID)
+					continue
+				}
+				dependencies := append([]string{}, node.Dependencies...)
+				sort.Strings(dependencies)
+				fmt.Fprintf(&b, "  %s (depends on %s)\n", nodeID, strings.Join(dependencies, ", "))
+			}
+		}
+		rendered = b.String()
+	}
+
+	if outFile == "" {
+		fmt.Print(rendered)
+		return nil
+	}
+	if err := os.WriteFile(outFile, []byte(rendered), 0644); err != nil {
+		return fmt.Errorf("failed to write graph file: %w", err)
+	}
+	fmt.Printf("Graph written to %s\n", outFile)
+	return nil
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/ataiva-software/runestone/internal/drift"
+	"github.com/ataiva-software/runestone/internal/providers"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <resource-id> <cloud-id>",
+	Short: "Adopt an existing cloud resource",
+	Long: `Import adopts a resource that was created outside Runestone:
+- Finds the resource by its cloud ID (for example vpc-0a1b2c3d)
+- Tags it with the Name the configuration expects and runestone:managed=true
+- Checks that drift detection now matches it
+- Prints its properties so they can be pasted into the configuration
+
+Import is needed for resources that are found by their Name tag (EC2 instances,
+VPCs, subnets, internet gateways, EBS volumes and security groups). Other resources are found
+by name and are adopted by naming the resource after the existing one.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runImport,
+}
+
+func init() {
+	importCmd.Flags().StringP("config", "c", "infra.yaml", "Path to the configuration file, a directory or glob of files to merge, or - for standard input")
+	addVariableFlags(importCmd)
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	configFile, _ := cmd.Flags().GetString("config")
+	resourceID, cloudID := args[0], args[1]
+
+	// Parse configuration
+	parser, err := newConfigParser(cmd)
+	if err != nil {
+		return err
+	}
+	cfg, err := parser.ParseFile(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to parse configuration: %w", err)
+	}
+
+	if err := loadModules(cfg, parser); err != nil {
+		return err
+	}
+
+	instances, err := parser.ExpandResources(cfg.Resources)
+	if err != nil {
+		return fmt.Errorf("failed to expand resources: %w", err)
+	}
+	if err := validateProviderKeys(instances); err != nil {
+		return err
+	}
+
+	// The resource's dependencies are included so references to them can be resolved
+	targeted, err := filterInstancesByTarget(instances, []string{resourceID})
+	if err != nil {
+		return err
+	}
+	var instance config.ResourceInstance
+	for _, candidate := range targeted {
+		if candidate.ID == resourceID {
+			instance = candidate
+		}
+	}
+
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	// Initialize providers
+	registry := providers.NewProviderRegistry()
+	for providerName, providerConfig := range cfg.Providers {
+		provider, err := newProvider(providerName)
+		if err != nil {
+			return err
+		}
+		if err := provider.Initialize(ctx, providerConfig.Settings()); err != nil {
+			return fmt.Errorf("failed to initialize provider %s: %w", providerName, err)
+		}
+		registry.Register(providerName, provider)
+	}
+
+	providerName := extractProviderName(instance)
+	provider, exists := registry.Get(providerName)
+	if !exists {
+		return fmt.Errorf("provider %s is not configured", providerName)
+	}
+	importer, ok := provider.(providers.Importer)
+	if !ok {
+		return fmt.Errorf("provider %s does not support import", providerName)
+	}
+
+	slog.Info("importing resource", "cloud_id", cloudID, "resource", resourceID)
+	properties, err := importer.Import(ctx, instance, cloudID)
+	if err != nil {
+		return fmt.Errorf("failed to import %s: %w", cloudID, err)
+	}
+	symbols := resultSymbols{color: useColor(cmd)}
+	fmt.Printf("%s\n\n", symbols.headline("✔", "Imported "+cloudID))
+
+	// Show how the adopted resource compares with its configuration
+	driftResults, err := detectDriftWithReferences(ctx, drift.NewDetector(registry), targeted)
+	if err != nil {
+		return fmt.Errorf("failed to detect drift for %s: %w", resourceID, err)
+	}
+	driftResult := driftResults[resourceID]
+	if driftResult.HasDrift {
+		fmt.Println("The configuration differs from the imported resource:")
+		for _, change := range describeDifferences(driftResult.Differences) {
+			fmt.Printf("  ~ %s\n", change)
+		}
+		fmt.Println()
+	} else {
+		fmt.Println("The configuration matches the imported resource.")
+		fmt.Println()
+	}
+
+	document, err := yaml.Marshal(map[string]interface{}{"properties": properties})
+	if err != nil {
+		return fmt.Errorf("failed to format properties: %w", err)
+	}
+	fmt.Println("Live properties, for pasting into the configuration:")
+	for _, line := range strings.Split(strings.TrimRight(string(document), "\n"), "\n") {
+		fmt.Printf("  %s\n", line)
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ataiva-software/runestone/internal/output"
+	"github.com/ataiva-software/runestone/internal/policy"
+	"github.com/spf13/cobra"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Warn about common configuration mistakes",
+	Long: `Lint checks a configuration offline for common mistakes that are valid but risky:
+- AWS resources without tags
+- Hardcoded credentials in resource properties
+- Security groups that allow SSH (port 22) from 0.0.0.0/0
+- Resources without a drift policy
+
+Each issue names the resource and how to fix it. Issues are warnings, so lint exits with
+code 0 unless the configuration can't be parsed. Policy exemptions waive lint rules like any
+other rule. Use validate to check the configuration against provider schemas and policies.`,
+	RunE: runLint,
+}
+
+func init() {
+	lintCmd.Flags().StringP("config", "c", "infra.yaml", "Path to the configuration file, a directory or glob of files to merge, or - for standard input")
+	addVariableFlags(lintCmd)
+	lintCmd.Flags().StringP("output", "o", "human", "Output format (human, json, markdown, yaml, junit)")
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	configFile, _ := cmd.Flags().GetString("config")
+	outputFormat, _ := cmd.Flags().GetString("output")
+
+	startTime := time.Now()
+	formatter := newFormatter(cmd, outputFormat)
+
+	result := output.LintResult{
+		Findings: []policy.PolicyViolation{},
+	}
+
+	fail := func(err error) error {
+		result.Error = err
+		result.Duration = time.Since(startTime)
+		formatted, _ := formatter.FormatLintResult(result)
+		fmt.Print(formatted)
+		return err
+	}
+
+	parser, err := newConfigParser(cmd)
+	if err != nil {
+		return fail(err)
+	}
+	cfg, err := parser.ParseFile(configFile)
+	if err != nil {
+		return fail(fmt.Errorf("failed to parse configuration: %w", err))
+	}
+
+	if err := loadModules(cfg, parser); err != nil {
+		return fail(err)
+	}
+
+	instances, err := parser.ExpandResources(cfg.Resources)
+	if err != nil {
+		return fail(fmt.Errorf("failed to expand resources: %w", err))
+	}
+	result.ResourceCount = len(instances)
+
+	lintEngine := policy.NewPolicyEngine()
+	if err := lintEngine.LoadLintRules(); err != nil {
+		return fail(err)
+	}
+	if err := lintEngine.AddExemptions(cfg.Exemptions); err != nil {
+		return fail(fmt.Errorf("invalid policy exemptions: %w", err))
+	}
+
+	// Credentials are looked for in the configuration as written, before env() and other
+	// expressions have been evaluated
+	result.Findings = append(result.Findings, lintEngine.FindHardcodedSecrets(cfg.Resources)...)
+
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	for _, instance := range instances {
+		findings, err := lintEngine.EvaluateResource(ctx, withProviderDefaultTags(instance, cfg))
+		if err != nil {
+			return fail(fmt.Errorf("failed to lint resource %s: %w", instance.ID, err))
+		}
+		result.Findings = append(result.Findings, findings...)
+	}
+
+	result.Success = true
+	result.Duration = time.Since(startTime)
+
+	formatted, err := formatter.FormatLintResult(result)
+	if err != nil {
+		return fmt.Errorf("failed to format output: %w", err)
+	}
+	fmt.Print(formatted)
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/ataiva-software/runestone/internal/providers"
+	"github.com/spf13/cobra"
+)
+
+// addLoggingFlags registers the persistent flags that control diagnostics on stderr
+func addLoggingFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("log-level", "info", "Minimum level of diagnostics logged to stderr: debug, info, warn or error")
+	cmd.PersistentFlags().BoolP("quiet", "q", false, "Log only warnings and errors (same as --log-level warn)")
+	cmd.MarkFlagsMutuallyExclusive("log-level", "quiet")
+}
+
+// configureLogging installs the default logger from --log-level and --quiet. Diagnostics
+// such as progress, retries and warnings are logged to stderr, so stdout carries only a
+// command's results and stays parseable with --output json.
+func configureLogging(cmd *cobra.Command) error {
+	levelName, _ := cmd.Flags().GetString("log-level")
+	quiet, _ := cmd.Flags().GetBool("quiet")
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(levelName)); err != nil {
+		return fmt.Errorf("invalid log level %q: expected debug, info, warn or error", levelName)
+	}
+	if quiet {
+		level = slog.LevelWarn
+	}
+
+	slog.SetDefault(newLogger(os.Stderr, level))
+	return nil
+}
+
+// newLogger returns a text logger that leaves out timestamps, which add noise to interactive
+// output; align logs the start time of each pass itself
+func newLogger(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	}))
+}
+
+// newProvider constructs the uninitialized provider a providers entry configures, logging
+// with the entry's name so records from aliased providers can be told apart
+func newProvider(providerName string) (providers.Provider, error) {
+	provider, err := providers.NewProviderByName(providers.ProviderType(providerName))
+	if err != nil {
+		return nil, err
+	}
+	if logging, ok := provider.(providers.LoggingProvider); ok {
+		logging.SetLogger(slog.Default().With("provider", providerName))
+	}
+	return provider, nil
+}
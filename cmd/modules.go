@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/ataiva-software/runestone/internal/modules"
+)
+
+// loadModules loads every module declared in the configuration and registers it with the
+// parser so its resources are included by ExpandResources
+func loadModules(cfg *config.Config, parser *config.Parser) error {
+	names := make([]string, 0, len(cfg.Modules))
+	for name := range cfg.Modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, err := loadModule(name, cfg.Modules[name], parser); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadModule validates, loads and registers a single module
+func loadModule(name string, moduleConfig config.Module, parser *config.Parser) (*modules.Module, error) {
+	module := &modules.Module{
+		Name:    name,
+		Source:  moduleConfig.Source,
+		Version: moduleConfig.Version,
+		Inputs:  moduleConfig.Inputs,
+	}
+
+	if err := module.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid module configuration for '%s': %w", name, err)
+	}
+
+	if err := module.Load(); err != nil {
+		return nil, fmt.Errorf("failed to load module '%s': %w", name, err)
+	}
+
+	parser.AddModule(name, module.Definition, module.Inputs)
+	return module, nil
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/ataiva-software/runestone/internal/policy"
+	"github.com/spf13/cobra"
+)
+
+// addPolicyFlags registers the --policy-file flag on a command
+func addPolicyFlags(cmd *cobra.Command) {
+	cmd.Flags().String("policy-file", "", "Load additional policy rules from a YAML file")
+}
+
+// newPolicyEngine returns a policy engine for the configured engine (the builtin rules or
+// Rego policies), any rules from --policy-file and the configuration's exemptions
+func newPolicyEngine(cmd *cobra.Command, cfg *config.Config) (*policy.PolicyEngine, error) {
+	policyFile, _ := cmd.Flags().GetString("policy-file")
+
+	policyConfig := config.PolicyConfig{}
+	if cfg.Policy != nil {
+		policyConfig = *cfg.Policy
+	}
+
+	policyEngine := policy.NewPolicyEngine()
+	switch policyConfig.Engine {
+	case "", "builtin":
+		if err := policyEngine.LoadBuiltinPolicies(); err != nil {
+			return nil, fmt.Errorf("failed to load builtin policies: %w", err)
+		}
+	case "rego":
+		if err := policyEngine.LoadRegoPolicies(cmd.Context(), policyConfig.Package, policyConfig.Files); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported policy engine: %s", policyConfig.Engine)
+	}
+
+	if policyFile != "" {
+		if err := policyEngine.LoadPoliciesFromFile(policyFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := policyEngine.AddExemptions(cfg.Exemptions); err != nil {
+		return nil, fmt.Errorf("invalid policy exemptions: %w", err)
+	}
+
+	return policyEngine, nil
+}
@@ -3,13 +3,18 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/ataiva-software/runestone/internal/config"
 	"github.com/ataiva-software/runestone/internal/drift"
+	"github.com/ataiva-software/runestone/internal/executor"
 	"github.com/ataiva-software/runestone/internal/output"
+	"github.com/ataiva-software/runestone/internal/plan"
+	"github.com/ataiva-software/runestone/internal/policy"
 	"github.com/ataiva-software/runestone/internal/providers"
-	"github.com/ataiva-software/runestone/internal/providers/aws"
 	"github.com/spf13/cobra"
 )
 
@@ -24,38 +29,50 @@ var previewCmd = &cobra.Command{
 }
 
 func init() {
-	previewCmd.Flags().StringP("config", "c", "infra.yaml", "Path to the configuration file")
-	previewCmd.Flags().StringP("output", "o", "human", "Output format (human, json, markdown)")
+	previewCmd.Flags().StringP("config", "c", "infra.yaml", "Path to the configuration file, a directory or glob of files to merge, or - for standard input")
+	addVariableFlags(previewCmd)
+	previewCmd.Flags().StringP("output", "o", "human", "Output format (human, json, markdown, yaml, junit)")
+	previewCmd.Flags().StringArray("target", nil, "Limit the preview to a resource ID and its dependencies (repeatable)")
+	previewCmd.Flags().String("out", "", "Write the computed plan to a file for use with 'commit --plan'")
+	addPolicyFlags(previewCmd)
 }
 
 func runPreview(cmd *cobra.Command, args []string) error {
 	configFile, _ := cmd.Flags().GetString("config")
 	outputFormat, _ := cmd.Flags().GetString("output")
+	targets, _ := cmd.Flags().GetStringArray("target")
+	planFile, _ := cmd.Flags().GetString("out")
 	
 	startTime := time.Now()
 	
 	// Create output formatter
-	formatter := output.NewFormatter(output.OutputFormat(outputFormat))
+	formatter := newFormatter(cmd, outputFormat)
 	
 	// Initialize result
 	result := output.PreviewResult{
-		Success:      false,
-		ChangesCount: 0,
-		Changes:      []output.Change{},
-		DriftResults: []output.DriftResult{},
-		Duration:     0,
-		Error:        nil,
+		Success:          false,
+		ChangesCount:     0,
+		Changes:          []output.Change{},
+		DriftResults:     []output.DriftResult{},
+		PolicyViolations: []policy.PolicyViolation{},
+		Duration:         0,
+		Error:            nil,
 	}
 
 	// Only show progress messages for human output
 	showProgress := outputFormat == "human"
 	
-	if showProgress {
-		fmt.Println(" Inspecting live infrastructure...")
-	}
+	slog.Info("inspecting live infrastructure")
 
 	// Parse configuration
-	parser := config.NewParser()
+	parser, err := newConfigParser(cmd)
+	if err != nil {
+		result.Error = err
+		result.Duration = time.Since(startTime)
+		output, _ := formatter.FormatPreviewResult(result)
+		fmt.Print(output)
+		return result.Error
+	}
 	cfg, err := parser.ParseFile(configFile)
 	if err != nil {
 		result.Error = fmt.Errorf("failed to parse configuration: %w", err)
@@ -67,27 +84,21 @@ func runPreview(cmd *cobra.Command, args []string) error {
 
 	// Set up provider registry
 	registry := providers.NewProviderRegistry()
-	ctx := context.Background()
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
 
 	// Initialize providers
 	for providerName, providerConfig := range cfg.Providers {
-		var provider providers.Provider
-		switch providerName {
-		case "aws":
-			provider = aws.NewProvider()
-		default:
-			result.Error = fmt.Errorf("unsupported provider: %s", providerName)
+		provider, err := newProvider(providerName)
+		if err != nil {
+			result.Error = err
 			result.Duration = time.Since(startTime)
 			output, _ := formatter.FormatPreviewResult(result)
 			fmt.Print(output)
 			return result.Error
 		}
 
-		providerConfigMap := make(map[string]interface{})
-		providerConfigMap["region"] = providerConfig.Region
-		providerConfigMap["profile"] = providerConfig.Profile
-
-		if err := provider.Initialize(ctx, providerConfigMap); err != nil {
+		if err := provider.Initialize(ctx, providerConfig.Settings()); err != nil {
 			result.Error = fmt.Errorf("failed to initialize provider %s: %w", providerName, err)
 			result.Duration = time.Since(startTime)
 			output, _ := formatter.FormatPreviewResult(result)
@@ -98,6 +109,14 @@ func runPreview(cmd *cobra.Command, args []string) error {
 		registry.Register(providerName, provider)
 	}
 
+	if err := loadModules(cfg, parser); err != nil {
+		result.Error = err
+		result.Duration = time.Since(startTime)
+		output, _ := formatter.FormatPreviewResult(result)
+		fmt.Print(output)
+		return result.Error
+	}
+
 	// Expand resources using the same parser that has the variables
 	instances, err := parser.ExpandResources(cfg.Resources)
 	if err != nil {
@@ -108,9 +127,56 @@ func runPreview(cmd *cobra.Command, args []string) error {
 		return result.Error
 	}
 
+	if err := validateProviderKeys(instances); err != nil {
+		result.Error = err
+		result.Duration = time.Since(startTime)
+		output, _ := formatter.FormatPreviewResult(result)
+		fmt.Print(output)
+		return result.Error
+	}
+
+	if err := config.ValidateMoves(cfg.Moved, instances); err != nil {
+		result.Error = err
+		result.Duration = time.Since(startTime)
+		output, _ := formatter.FormatPreviewResult(result)
+		fmt.Print(output)
+		return result.Error
+	}
+
+	instances, err = filterInstancesByTarget(instances, targets)
+	if err != nil {
+		result.Error = err
+		result.Duration = time.Since(startTime)
+		output, _ := formatter.FormatPreviewResult(result)
+		fmt.Print(output)
+		return result.Error
+	}
+
+	// Report policy violations alongside the planned changes
+	policyEngine, err := newPolicyEngine(cmd, cfg)
+	if err != nil {
+		result.Error = err
+		result.Duration = time.Since(startTime)
+		output, _ := formatter.FormatPreviewResult(result)
+		fmt.Print(output)
+		return result.Error
+	}
+	for _, instance := range instances {
+		violations, err := policyEngine.EvaluateResource(ctx, withProviderDefaultTags(instance, cfg))
+		if err != nil {
+			result.Error = fmt.Errorf("failed to evaluate policies for resource %s: %w", instance.ID, err)
+			result.Duration = time.Since(startTime)
+			output, _ := formatter.FormatPreviewResult(result)
+			fmt.Print(output)
+			return result.Error
+		}
+		result.PolicyViolations = append(result.PolicyViolations, violations...)
+	}
+
 	// Detect drift
 	detector := drift.NewDetector(registry)
-	driftResults, err := detector.DetectDriftBatch(ctx, instances)
+	detector.SetMoves(cfg.Moved)
+	driftResults, err := detectDriftWithReferences(ctx, detector, instances)
 	if err != nil {
 		result.Error = fmt.Errorf("failed to detect drift: %w", err)
 		result.Duration = time.Since(startTime)
@@ -119,9 +185,23 @@ func runPreview(cmd *cobra.Command, args []string) error {
 		return result.Error
 	}
 
+	// Persist the plan so commit can apply exactly what was previewed
+	if planFile != "" {
+		changePlan, err := plan.New(configFile, instances, driftResults)
+		if err == nil {
+			err = changePlan.Save(planFile)
+		}
+		if err != nil {
+			result.Error = fmt.Errorf("failed to save plan: %w", err)
+			result.Duration = time.Since(startTime)
+			output, _ := formatter.FormatPreviewResult(result)
+			fmt.Print(output)
+			return result.Error
+		}
+	}
+
 	// Convert results to output format
-	result.Changes, result.DriftResults = convertToOutputFormat(instances, driftResults)
-	result.ChangesCount = len(result.Changes)
+	convertToOutputFormat(&result, instances, driftResults)
 	result.Success = true
 	result.Duration = time.Since(startTime)
 
@@ -132,10 +212,23 @@ func runPreview(cmd *cobra.Command, args []string) error {
 	}
 	
 	fmt.Print(outputStr)
+	if planFile != "" && showProgress {
+		fmt.Printf("\nPlan saved to %s. Apply it with 'runestone commit --plan %s'.\n", planFile, planFile)
+	}
+
+	// Exit codes let pipelines gate on policy errors and pending changes
+	if policyEngine.HasErrors(result.PolicyViolations) {
+		return withExitCode(ExitPolicyViolations, nil)
+	}
+	if result.ChangesCount > 0 {
+		return withExitCode(ExitChanges, nil)
+	}
 	return nil
 }
 
-func convertToOutputFormat(instances []config.ResourceInstance, driftResults map[string]*providers.DriftResult) ([]output.Change, []output.DriftResult) {
+// convertToOutputFormat fills in the planned changes, drift results and change counts of a
+// preview result
+func convertToOutputFormat(result *output.PreviewResult, instances []config.ResourceInstance, driftResults map[string]*providers.DriftResult) {
 	changes := make([]output.Change, 0)
 	driftResultsOutput := make([]output.DriftResult, 0)
 
@@ -147,23 +240,20 @@ func convertToOutputFormat(instances []config.ResourceInstance, driftResults map
 
 		// Add drift result
 		driftChanges := make([]string, 0)
+		differences := make([]output.DriftDifference, 0)
 		if driftResult.HasDrift {
-			for _, diff := range driftResult.Differences {
-				switch diff.DriftType {
-				case providers.DriftTypeAdded:
-					driftChanges = append(driftChanges, fmt.Sprintf("Missing property: %s (expected: %v)", diff.Property, diff.DesiredValue))
-				case providers.DriftTypeModified:
-					driftChanges = append(driftChanges, fmt.Sprintf("Property %s: %v → %v", diff.Property, diff.CurrentValue, diff.DesiredValue))
-				case providers.DriftTypeRemoved:
-					driftChanges = append(driftChanges, fmt.Sprintf("Extra property: %s (current: %v)", diff.Property, diff.CurrentValue))
-				}
-			}
+			driftChanges = describeDifferences(driftResult.Differences)
+			differences = outputDifferences(driftResult.Differences)
+		}
+		if driftResult.MovedFrom != "" {
+			driftChanges = append([]string{fmt.Sprintf("Moved from %s", driftResult.MovedFrom)}, driftChanges...)
 		}
 
 		driftResultsOutput = append(driftResultsOutput, output.DriftResult{
 			ResourceName: instance.ID,
 			HasDrift:     driftResult.HasDrift,
 			Changes:      driftChanges,
+			Differences:  differences,
 		})
 
 		// Add change if needed
@@ -175,6 +265,14 @@ func convertToOutputFormat(instances []config.ResourceInstance, driftResults map
 				ResourceName: instance.Name,
 				Description:  fmt.Sprintf("Create %s %s", instance.Kind, instance.Name),
 			})
+		} else if driftResult.MovedFrom != "" {
+			// Resource exists under the ID it was moved from - renamed in place
+			changes = append(changes, output.Change{
+				Type:         "update",
+				ResourceKind: instance.Kind,
+				ResourceName: instance.Name,
+				Description:  fmt.Sprintf("Rename %s to %s", driftResult.MovedFrom, instance.ID),
+			})
 		} else if driftResult.HasDrift {
 			// Resource exists but has drift - needs to be updated
 			changes = append(changes, output.Change{
@@ -186,7 +284,150 @@ func convertToOutputFormat(instances []config.ResourceInstance, driftResults map
 		}
 	}
 
-	return changes, driftResultsOutput
+	result.Changes = changes
+	result.DriftResults = driftResultsOutput
+	result.ChangesCount = len(changes)
+	result.CreateCount, result.UpdateCount, result.DeleteCount = output.CountChanges(changes)
+}
+
+// outputDifferences converts drift differences for the output formatters, ordered by property
+func outputDifferences(differences map[string]providers.DriftDifference) []output.DriftDifference {
+	properties := make([]string, 0, len(differences))
+	for property := range differences {
+		properties = append(properties, property)
+	}
+	sort.Strings(properties)
+
+	converted := make([]output.DriftDifference, 0, len(differences))
+	for _, property := range properties {
+		diff := differences[property]
+		converted = append(converted, output.DriftDifference{
+			Property:     diff.Property,
+			Path:         diff.Path,
+			CurrentValue: diff.CurrentValue,
+			DesiredValue: diff.DesiredValue,
+			DriftType:    string(diff.DriftType),
+		})
+	}
+	return converted
+}
+
+// describeDifferences renders drift differences as human-readable change descriptions,
+// ordered by property
+func describeDifferences(differences map[string]providers.DriftDifference) []string {
+	properties := make([]string, 0, len(differences))
+	for property := range differences {
+		properties = append(properties, property)
+	}
+	sort.Strings(properties)
+
+	descriptions := make([]string, 0, len(differences))
+	for _, property := range properties {
+		diff := differences[property]
+		switch diff.DriftType {
+		case providers.DriftTypeAdded:
+			descriptions = append(descriptions, fmt.Sprintf("Missing property: %s (expected: %v)", diff.Property, diff.DesiredValue))
+		case providers.DriftTypeModified:
+			descriptions = append(descriptions, fmt.Sprintf("Property %s: %v → %v", diff.Property, diff.CurrentValue, diff.DesiredValue))
+		case providers.DriftTypeRemoved:
+			descriptions = append(descriptions, fmt.Sprintf("Extra property: %s (current: %v)", diff.Property, diff.CurrentValue))
+		}
+	}
+	return descriptions
+}
+
+// detectDriftWithReferences detects drift in dependency order so that references to other
+// resources' attributes can be resolved from their live state before comparison. References
+// to resources that don't exist yet are left unresolved until commit.
+func detectDriftWithReferences(ctx context.Context, detector *drift.Detector, instances []config.ResourceInstance) (map[string]*providers.DriftResult, error) {
+	dag, err := executor.NewDAG(instances)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build dependency graph: %w", err)
+	}
+
+	driftResults := make(map[string]*providers.DriftResult, len(instances))
+	outputs := make(map[string]map[string]interface{})
+
+	for _, level := range dag.GetExecutionOrder() {
+		levelInstances := make([]config.ResourceInstance, 0, len(level))
+		for _, nodeID := range level {
+			node, _ := dag.GetNode(nodeID)
+			instance := node.Instance
+			if len(executor.FindReferences(instance.Properties)) > 0 {
+				if resolved, err := executor.ResolveReferences(instance.Properties, outputs); err == nil {
+					instance.Properties = resolved
+				}
+			}
+			levelInstances = append(levelInstances, instance)
+		}
+
+		levelResults, err := detector.DetectDriftBatch(ctx, levelInstances)
+		if err != nil {
+			return nil, err
+		}
+
+		for id, result := range levelResults {
+			driftResults[id] = result
+			if result.CurrentState != nil {
+				outputs[id] = result.CurrentState
+			}
+		}
+	}
+
+	return driftResults, nil
+}
+
+// filterInstancesByTarget narrows instances to the targeted resource IDs plus everything
+// they transitively depend on. An empty target list returns instances unchanged.
+func filterInstancesByTarget(instances []config.ResourceInstance, targets []string) ([]config.ResourceInstance, error) {
+	if len(targets) == 0 {
+		return instances, nil
+	}
+
+	known := make(map[string]bool, len(instances))
+	for _, instance := range instances {
+		known[instance.ID] = true
+	}
+
+	var unknown []string
+	for _, target := range targets {
+		if !known[target] {
+			unknown = append(unknown, target)
+		}
+	}
+	if len(unknown) > 0 {
+		validIDs := make([]string, 0, len(instances))
+		for _, instance := range instances {
+			validIDs = append(validIDs, instance.ID)
+		}
+		sort.Strings(validIDs)
+		return nil, fmt.Errorf("unknown target resource(s): %s\nValid resource IDs:\n  %s",
+			strings.Join(unknown, ", "), strings.Join(validIDs, "\n  "))
+	}
+
+	dag, err := executor.NewDAG(instances)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build dependency graph: %w", err)
+	}
+
+	closure, err := dag.GetDependencyClosure(targets)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make(map[string]bool, len(closure))
+	for _, id := range closure {
+		selected[id] = true
+	}
+
+	filtered := make([]config.ResourceInstance, 0, len(closure))
+	for _, instance := range instances {
+		if selected[instance.ID] {
+			filtered = append(filtered, instance)
+		}
+	}
+
+	return filtered, nil
 }
 
 // Legacy function for commit command compatibility
@@ -232,6 +473,7 @@ func generateChangeSummary(instances []config.ResourceInstance, driftResults map
 				Properties:   instance.Properties,
 				OldValues:    oldValues,
 				NewValues:    newValues,
+				MovedFrom:    driftResult.MovedFrom,
 			})
 		}
 	}
@@ -254,6 +496,9 @@ func displayPreviewResults(summary *config.ChangeSummary, driftResults map[strin
 		for resourceID, result := range driftResults {
 			if result.HasDrift && result.CurrentState != nil {
 				fmt.Printf("  • %s has configuration drift\n", resourceID)
+				if result.MovedFrom != "" {
+					fmt.Printf("    - Moved from %s\n", result.MovedFrom)
+				}
 				for _, diff := range result.Differences {
 					switch diff.DriftType {
 					case providers.DriftTypeAdded:
@@ -299,7 +544,11 @@ func displayPreviewResults(summary *config.ChangeSummary, driftResults map[strin
 			case config.ChangeTypeCreate:
 				fmt.Printf("+ Create %s (%s)\n", change.ResourceID, change.ResourceKind)
 			case config.ChangeTypeUpdate:
-				fmt.Printf("~ Update %s (%s)\n", change.ResourceID, change.ResourceKind)
+				if change.MovedFrom != "" {
+					fmt.Printf("~ Rename %s to %s (%s)\n", change.MovedFrom, change.ResourceID, change.ResourceKind)
+				} else {
+					fmt.Printf("~ Update %s (%s)\n", change.ResourceID, change.ResourceKind)
+				}
 				for property, newValue := range change.NewValues {
 					if oldValue, exists := change.OldValues[property]; exists {
 						fmt.Printf("    %s: %v → %v\n", property, oldValue, newValue)
@@ -0,0 +1,8 @@
+package cmd
+
+import (
+	// Provider implementations register themselves with the providers package on import
+	_ "github.com/ataiva-software/runestone/internal/providers/aws"
+	_ "github.com/ataiva-software/runestone/internal/providers/gcp"
+	_ "github.com/ataiva-software/runestone/internal/providers/kubernetes"
+)
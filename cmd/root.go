@@ -1,6 +1,10 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+
+	"github.com/ataiva-software/runestone/internal/output"
 	"github.com/spf13/cobra"
 )
 
@@ -11,21 +15,55 @@ var rootCmd = &cobra.Command{
 It solves the common pain points of existing IaC tools — brittle state files,
 drift surprises, and complex multi-cloud orchestration — by offering a stateless,
 DAG-driven execution engine with real-time reconciliation and human-friendly CLI workflows.`,
+	// Errors are printed by Execute so commands can exit with specific codes
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return configureLogging(cmd)
+	},
 }
 
 func SetVersion(version string) {
 	rootCmd.Version = version
 }
 
-func Execute() error {
-	return rootCmd.Execute()
+// newFormatter returns the formatter for an --output format. Human output is decorated with
+// colors and symbols only when stdout is a terminal and --no-color isn't set.
+func newFormatter(cmd *cobra.Command, format string) output.Formatter {
+	return output.NewFormatter(output.OutputFormat(format), output.WithColor(useColor(cmd)))
+}
+
+// useColor reports whether human output a command prints itself, rather than through a
+// formatter, should be decorated. It makes the same decision as newFormatter.
+func useColor(cmd *cobra.Command) bool {
+	noColor, _ := cmd.Flags().GetBool("no-color")
+	return output.UseColor(os.Stdout, noColor)
+}
+
+// Execute runs the CLI and returns the process exit code
+func Execute() int {
+	code, message := exitCodeFor(rootCmd.Execute())
+	if message != "" {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", message)
+	}
+	return code
 }
 
 func init() {
+	addLoggingFlags(rootCmd)
+	rootCmd.PersistentFlags().Bool("no-color", false, "Leave colors and symbols out of human output (the default when stdout isn't a terminal)")
+	rootCmd.PersistentFlags().Duration("timeout", 0, "Cancel provider operations that run longer than this (0 disables the timeout)")
+
 	rootCmd.AddCommand(bootstrapCmd)
+	rootCmd.AddCommand(validateCmd)
+	rootCmd.AddCommand(lintCmd)
 	rootCmd.AddCommand(previewCmd)
 	rootCmd.AddCommand(commitCmd)
 	rootCmd.AddCommand(alignCmd)
 	rootCmd.AddCommand(dismantleCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(scanCmd)
+	rootCmd.AddCommand(graphCmd)
 	rootCmd.AddCommand(docsCmd)
 }
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/ataiva-software/runestone/internal/output"
+	"github.com/ataiva-software/runestone/internal/providers"
+	"github.com/spf13/cobra"
+)
+
+var scanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Report resources in the account that Runestone doesn't manage",
+	Long: `Scan lists the real resources of supported kinds in the accounts and regions of the
+configured providers, and reports those that aren't managed by Runestone:
+- S3 buckets in the provider's region
+- EC2 instances that haven't been terminated
+- VPCs other than the default VPC
+
+A resource is managed when it is tagged runestone:managed, which import sets and
+default_tags can add to every resource Runestone creates, or runestone:id, which Runestone
+sets on the EC2 resources it creates. Only the providers section of the configuration is
+read. Scan makes no changes and exits with code 0 whether or not unmanaged resources are
+found.`,
+	RunE: runScan,
+}
+
+func init() {
+	scanCmd.Flags().StringP("config", "c", "infra.yaml", "Path to the configuration file, a directory or glob of files to merge, or - for standard input")
+	addVariableFlags(scanCmd)
+	scanCmd.Flags().StringP("output", "o", "human", "Output format (human, json, markdown, yaml, junit)")
+}
+
+func runScan(cmd *cobra.Command, args []string) error {
+	configFile, _ := cmd.Flags().GetString("config")
+	outputFormat, _ := cmd.Flags().GetString("output")
+
+	startTime := time.Now()
+	formatter := newFormatter(cmd, outputFormat)
+
+	result := output.ScanResult{
+		Resources: []output.ScannedResource{},
+	}
+
+	fail := func(err error) error {
+		result.Error = err
+		result.Duration = time.Since(startTime)
+		formatted, _ := formatter.FormatScanResult(result)
+		fmt.Print(formatted)
+		return err
+	}
+
+	parser, err := newConfigParser(cmd)
+	if err != nil {
+		return fail(err)
+	}
+	cfg, err := parser.ParseFile(configFile)
+	if err != nil {
+		return fail(fmt.Errorf("failed to parse configuration: %w", err))
+	}
+
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	// Scan providers in a stable order so output can be compared between runs
+	providerNames := make([]string, 0, len(cfg.Providers))
+	for providerName := range cfg.Providers {
+		providerNames = append(providerNames, providerName)
+	}
+	sort.Strings(providerNames)
+
+	scanned := 0
+	for _, providerName := range providerNames {
+		provider, err := newProvider(providerName)
+		if err != nil {
+			return fail(err)
+		}
+		scanner, ok := provider.(providers.Scanner)
+		if !ok {
+			slog.Warn("provider doesn't support scanning; skipping it", "provider", providerName)
+			continue
+		}
+		if err := provider.Initialize(ctx, cfg.Providers[providerName].Settings()); err != nil {
+			return fail(fmt.Errorf("failed to initialize provider %s: %w", providerName, err))
+		}
+
+		resources, err := scanner.Scan(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return fail(interruptedError(ctx))
+			}
+			return fail(fmt.Errorf("failed to scan provider %s: %w", providerName, err))
+		}
+		for _, resource := range resources {
+			result.Resources = append(result.Resources, output.ScannedResource{
+				Provider:  providerName,
+				Kind:      resource.Kind,
+				CloudID:   resource.CloudID,
+				Name:      resource.Name,
+				CreatedAt: resource.CreatedAt,
+				Managed:   resource.Managed,
+			})
+		}
+		scanned++
+	}
+
+	if scanned == 0 {
+		return fail(fmt.Errorf("no configured provider supports scanning"))
+	}
+
+	result.Success = true
+	result.Duration = time.Since(startTime)
+
+	formatted, err := formatter.FormatScanResult(result)
+	if err != nil {
+		return fmt.Errorf("failed to format output: %w", err)
+	}
+	fmt.Print(formatted)
+
+	return nil
+}
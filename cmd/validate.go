@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/ataiva-software/runestone/internal/output"
+	"github.com/ataiva-software/runestone/internal/policy"
+	"github.com/ataiva-software/runestone/internal/providers"
+	"github.com/spf13/cobra"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate configuration without contacting cloud providers",
+	Long: `Validate checks a configuration offline:
+- Parses the configuration and expands resources and modules
+- Validates each resource against its provider's schema
+- Evaluates policies
+
+No provider clients are created, so no cloud credentials are required.`,
+	RunE: runValidate,
+}
+
+func init() {
+	validateCmd.Flags().StringP("config", "c", "infra.yaml", "Path to the configuration file, a directory or glob of files to merge, or - for standard input")
+	addVariableFlags(validateCmd)
+	addPolicyFlags(validateCmd)
+	validateCmd.Flags().StringP("output", "o", "human", "Output format (human, json, markdown, yaml, junit)")
+}
+
+func runValidate(cmd *cobra.Command, args []string) error {
+	configFile, _ := cmd.Flags().GetString("config")
+	outputFormat, _ := cmd.Flags().GetString("output")
+
+	startTime := time.Now()
+	formatter := newFormatter(cmd, outputFormat)
+
+	result := output.ValidateResult{
+		ValidationErrors: []output.ValidationError{},
+		PolicyViolations: []policy.PolicyViolation{},
+	}
+
+	fail := func(err error) error {
+		result.Error = err
+		result.Duration = time.Since(startTime)
+		formatted, _ := formatter.FormatValidateResult(result)
+		fmt.Print(formatted)
+		return err
+	}
+
+	// Parse configuration
+	parser, err := newConfigParser(cmd)
+	if err != nil {
+		return fail(err)
+	}
+	cfg, err := parser.ParseFile(configFile)
+	if err != nil {
+		return fail(fmt.Errorf("failed to parse configuration: %w", err))
+	}
+
+	// Providers are constructed but never initialized, so no clients or credentials are needed.
+	// Those that can take their settings offline are configured, so checks such as the S3
+	// bucket region still run.
+	registry := providers.NewProviderRegistry()
+	for providerName, providerConfig := range cfg.Providers {
+		provider, err := newProvider(providerName)
+		if err != nil {
+			return fail(err)
+		}
+		if configurer, ok := provider.(providers.OfflineConfigurer); ok {
+			if err := configurer.Configure(providerConfig.Settings()); err != nil {
+				return fail(fmt.Errorf("invalid configuration for provider %s: %w", providerName, err))
+			}
+		}
+		registry.Register(providerName, provider)
+	}
+
+	if err := loadModules(cfg, parser); err != nil {
+		return fail(err)
+	}
+	result.ModulesLoaded = len(cfg.Modules)
+
+	// Expand resources
+	instances, err := parser.ExpandResources(cfg.Resources)
+	if err != nil {
+		return fail(fmt.Errorf("failed to expand resources: %w", err))
+	}
+	result.ResourceCount = len(instances)
+
+	if err := config.ValidateMoves(cfg.Moved, instances); err != nil {
+		return fail(err)
+	}
+
+	// Validate every resource so all problems are reported at once
+	for _, instance := range instances {
+		if err := providers.ValidateProviderKey(instance); err != nil {
+			result.ValidationErrors = append(result.ValidationErrors, output.ValidationError{
+				ResourceID: instance.ID,
+				Message:    err.Error(),
+			})
+			continue
+		}
+
+		providerName := extractProviderName(instance)
+		provider, exists := registry.Get(providerName)
+		if !exists {
+			result.ValidationErrors = append(result.ValidationErrors, output.ValidationError{
+				ResourceID: instance.ID,
+				Message:    fmt.Sprintf("provider %s is not configured", providerName),
+			})
+			continue
+		}
+
+		if err := provider.ValidateResource(instance); err != nil {
+			result.ValidationErrors = append(result.ValidationErrors, output.ValidationError{
+				ResourceID: instance.ID,
+				Message:    err.Error(),
+			})
+		}
+	}
+
+	// Evaluate policies
+	policyEngine, err := newPolicyEngine(cmd, cfg)
+	if err != nil {
+		return fail(err)
+	}
+
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+	for _, instance := range instances {
+		violations, err := policyEngine.EvaluateResource(ctx, withProviderDefaultTags(instance, cfg))
+		if err != nil {
+			return fail(fmt.Errorf("failed to evaluate policies for resource %s: %w", instance.ID, err))
+		}
+		result.PolicyViolations = append(result.PolicyViolations, violations...)
+	}
+
+	if len(result.ValidationErrors) > 0 {
+		return fail(fmt.Errorf("%d resources failed validation", len(result.ValidationErrors)))
+	}
+	if policyEngine.HasErrors(result.PolicyViolations) {
+		return withExitCode(ExitPolicyViolations, fail(fmt.Errorf("validation failed due to policy violations")))
+	}
+
+	result.Success = true
+	result.Duration = time.Since(startTime)
+
+	formatted, err := formatter.FormatValidateResult(result)
+	if err != nil {
+		return fmt.Errorf("failed to format output: %w", err)
+	}
+	fmt.Print(formatted)
+
+	return nil
+}
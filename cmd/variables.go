@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"log/slog"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// addVariableFlags registers the --var-file and --var flags on a command
+func addVariableFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArray("var-file", nil, "Load variables from a YAML file (repeatable, later files win)")
+	cmd.Flags().StringArray("var", nil, "Set a variable as key=value; the value is parsed as YAML (repeatable)")
+}
+
+// newConfigParser returns a parser seeded with variables from --var-file and --var.
+// Precedence is inline variables, then variable files in order, then --var overrides; each
+// layer is deep-merged into the one before it, so nested maps keep keys a layer leaves out.
+func newConfigParser(cmd *cobra.Command) (*config.Parser, error) {
+	varFiles, _ := cmd.Flags().GetStringArray("var-file")
+	vars, _ := cmd.Flags().GetStringArray("var")
+
+	overrides := make(map[string]interface{})
+	for _, varFile := range varFiles {
+		fileVars, err := config.LoadVariableFile(varFile)
+		if err != nil {
+			return nil, err
+		}
+		overrides = config.MergeVariables(overrides, fileVars)
+	}
+
+	for _, override := range vars {
+		name, value, err := config.ParseVariableOverride(override)
+		if err != nil {
+			return nil, err
+		}
+		overrides = config.MergeVariables(overrides, map[string]interface{}{name: value})
+	}
+
+	parser := config.NewParser()
+	parser.SetVariableOverrides(overrides)
+	parser.SetWarningHandler(func(message string) {
+		slog.Warn(message)
+	})
+	return parser, nil
+}
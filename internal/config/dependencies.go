@@ -0,0 +1,138 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxSuggestionDistance is the largest edit distance at which a declared resource ID is
+// suggested for a depends_on entry that doesn't match any resource
+const maxSuggestionDistance = 3
+
+// resourceLocations maps the kind and name of each resource in YAML data, as written
+// before expressions are evaluated, to where it is declared: path:line, or line N when
+// the data didn't come from a file. Positions are best effort, so data that can't be
+// read this way yields no locations.
+func resourceLocations(data []byte, path string) map[string]string {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil || len(document.Content) == 0 {
+		return nil
+	}
+
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	locations := make(map[string]string)
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "resources" || root.Content[i+1].Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, item := range root.Content[i+1].Content {
+			if item.Kind != yaml.MappingNode {
+				continue
+			}
+			var kind, name string
+			for j := 0; j+1 < len(item.Content); j += 2 {
+				switch item.Content[j].Value {
+				case "kind":
+					kind = item.Content[j+1].Value
+				case "name":
+					name = item.Content[j+1].Value
+				}
+			}
+
+			location := fmt.Sprintf("line %d", item.Line)
+			if path != "" {
+				location = fmt.Sprintf("%s:%d", path, item.Line)
+			}
+			locations[kind+"."+name] = location
+		}
+	}
+
+	return locations
+}
+
+// checkDependencies reports every depends_on entry that doesn't name one of the instances,
+// suggesting the closest declared ID when one is near enough to be a likely typo. locations
+// maps instance IDs to where they were declared, for resources whose position is known.
+func checkDependencies(instances []ResourceInstance, locations map[string]string) error {
+	declared := make(map[string]bool, len(instances))
+	ids := make([]string, 0, len(instances))
+	for _, instance := range instances {
+		if !declared[instance.ID] {
+			declared[instance.ID] = true
+			ids = append(ids, instance.ID)
+		}
+	}
+	sort.Strings(ids)
+
+	var errs []error
+	for _, instance := range instances {
+		for _, dependency := range instance.DependsOn {
+			if declared[dependency] {
+				continue
+			}
+
+			subject := instance.ID
+			if location := locations[instance.ID]; location != "" {
+				subject = fmt.Sprintf("%s (%s)", instance.ID, location)
+			}
+			message := fmt.Sprintf("resource %s depends on %s, which is not declared", subject, dependency)
+			if suggestion := closestMatch(dependency, ids); suggestion != "" {
+				message += fmt.Sprintf("; did you mean %s?", suggestion)
+			}
+			errs = append(errs, errors.New(message))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// closestMatch returns the candidate with the smallest edit distance from target, or an
+// empty string when none is close enough. Candidates are expected in sorted order, so ties
+// go to the first.
+func closestMatch(target string, candidates []string) string {
+	limit := maxSuggestionDistance
+	if third := len(target) / 3; third < limit {
+		limit = third
+	}
+
+	best, bestDistance := "", limit+1
+	for _, candidate := range candidates {
+		if distance := levenshtein(target, candidate); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// levenshtein returns the number of single-character insertions, deletions and
+// substitutions needed to turn a into b
+func levenshtein(a, b string) int {
+	source, target := []rune(a), []rune(b)
+
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(target)]
+}
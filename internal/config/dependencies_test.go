@@ -0,0 +1,89 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParser_ExpandResources_UnknownDependency(t *testing.T) {
+	parser := NewParser()
+	cfg, err := parser.Parse([]byte(`
+project: test-project
+environment: dev
+resources:
+  - kind: aws:ec2:vpc
+    name: main
+  - kind: aws:ec2:subnet
+    name: private-${count.index}
+    count: 2
+    depends_on:
+      - aws:ec2:vcp.main
+  - kind: aws:s3:bucket
+    name: logs
+    depends_on:
+      - aws:ec2:vpc.main
+      - aws:iam:role.unrelated
+`))
+	require.NoError(t, err)
+
+	_, err = parser.ExpandResources(cfg.Resources)
+	require.Error(t, err)
+	assert.Equal(t, "resource aws:ec2:subnet.private-0 (line 7) depends on aws:ec2:vcp.main, which is not declared; did you mean aws:ec2:vpc.main?\n"+
+		"resource aws:ec2:subnet.private-1 (line 7) depends on aws:ec2:vcp.main, which is not declared; did you mean aws:ec2:vpc.main?\n"+
+		"resource aws:s3:bucket.logs (line 12) depends on aws:iam:role.unrelated, which is not declared", err.Error())
+}
+
+func TestParser_ExpandResources_UnknownDependencyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`project: test-project
+environment: dev
+resources:
+  - kind: aws:ec2:vpc
+    name: main
+  - kind: aws:ec2:subnet
+    name: private
+    depends_on: [aws:ec2:vpc.mian]
+`), 0600))
+
+	parser := NewParser()
+	cfg, err := parser.ParseFile(path)
+	require.NoError(t, err)
+
+	_, err = parser.ExpandResources(cfg.Resources)
+	assert.EqualError(t, err, "resource aws:ec2:subnet.private ("+path+":6) depends on aws:ec2:vpc.mian, which is not declared; did you mean aws:ec2:vpc.main?")
+}
+
+func TestParser_ExpandResources_ModuleDependency(t *testing.T) {
+	parser := NewParser()
+	parser.AddModule("network", &ModuleDefinition{Resources: []Resource{
+		{Kind: "aws:ec2:vpc", Name: "${module.name}"},
+		{Kind: "aws:ec2:subnet", Name: "${module.name}-private", DependsOn: []string{"aws:ec2:vpc.netwrok"}},
+	}}, nil)
+
+	// Resources built in code have no known position
+	_, err := parser.ExpandResources([]Resource{
+		{Kind: "aws:s3:bucket", Name: "logs", DependsOn: []string{"aws:ec2:vpc.network"}},
+	})
+	assert.EqualError(t, err, "resource aws:ec2:subnet.network-private (module network) depends on aws:ec2:vpc.netwrok, which is not declared; did you mean aws:ec2:vpc.network?")
+}
+
+func TestClosestMatch(t *testing.T) {
+	candidates := []string{"aws:ec2:vpc.main", "aws:ec2:vpc.mains", "aws:s3:bucket.logs"}
+
+	assert.Equal(t, "aws:ec2:vpc.main", closestMatch("aws:ec2:vcp.main", candidates))
+	assert.Equal(t, "aws:s3:bucket.logs", closestMatch("aws:s3:bucket.log", candidates))
+	assert.Equal(t, "", closestMatch("aws:iam:role.admin", candidates))
+	// Short IDs need a closer match to avoid unhelpful suggestions
+	assert.Equal(t, "", closestMatch("a.b", []string{"c.d"}))
+}
+
+func TestLevenshtein(t *testing.T) {
+	assert.Equal(t, 0, levenshtein("vpc", "vpc"))
+	assert.Equal(t, 2, levenshtein("vpc", "vcp"))
+	assert.Equal(t, 3, levenshtein("", "vpc"))
+	assert.Equal(t, 3, levenshtein("kitten", "sitting"))
+}
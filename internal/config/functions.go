@@ -0,0 +1,219 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/expr-lang/expr"
+)
+
+// functionError is returned by built-in expression functions. Unlike unresolved
+// variables, which are deferred until resource expansion, it is reported immediately.
+type functionError struct {
+	message string
+}
+
+func (e *functionError) Error() string {
+	return e.message
+}
+
+// expressionFunction is a built-in function available in ${...} expressions
+type expressionFunction struct {
+	name      string
+	signature string
+	call      func(signature string, params []interface{}) (interface{}, error)
+}
+
+// builtinFunctions lists the functions registered into every expression environment
+var builtinFunctions = []expressionFunction{
+	{
+		name:      "env",
+		signature: "env(name string) string",
+		call:      envFunction,
+	},
+	{
+		name:      "secret",
+		signature: "secret(name string) string",
+		call:      secretFunction,
+	},
+	{
+		name:      "upper",
+		signature: "upper(s string) string",
+		call:      stringFunction(strings.ToUpper),
+	},
+	{
+		name:      "lower",
+		signature: "lower(s string) string",
+		call:      stringFunction(strings.ToLower),
+	},
+	{
+		name:      "replace",
+		signature: "replace(s string, old string, new string) string",
+		call:      replaceFunction,
+	},
+	{
+		name:      "join",
+		signature: "join(list []any, separator string) string",
+		call:      joinFunction,
+	},
+	{
+		name:      "split",
+		signature: "split(s string, separator string) []string",
+		call:      splitFunction,
+	},
+	{
+		name:      "format",
+		signature: "format(format string, args ...any) string",
+		call:      formatFunction,
+	},
+	{
+		name:      "length",
+		signature: "length(value string|list|map) int",
+		call:      lengthFunction,
+	},
+}
+
+// signatureError reports a misused built-in function along with its signature
+func signatureError(signature string, format string, args ...interface{}) error {
+	return &functionError{message: fmt.Sprintf("%s: %s", signature, fmt.Sprintf(format, args...))}
+}
+
+// stringArgs checks that params are exactly count strings
+func stringArgs(signature string, params []interface{}, count int) ([]string, error) {
+	if len(params) != count {
+		return nil, signatureError(signature, "expected %d argument(s), got %d", count, len(params))
+	}
+
+	args := make([]string, count)
+	for i, param := range params {
+		str, ok := param.(string)
+		if !ok {
+			return nil, signatureError(signature, "argument %d must be a string, got %T", i+1, param)
+		}
+		args[i] = str
+	}
+	return args, nil
+}
+
+// stringFunction adapts a single-argument string transformation into an expression function
+func stringFunction(transform func(string) string) func(string, []interface{}) (interface{}, error) {
+	return func(signature string, params []interface{}) (interface{}, error) {
+		args, err := stringArgs(signature, params, 1)
+		if err != nil {
+			return nil, err
+		}
+		return transform(args[0]), nil
+	}
+}
+
+func replaceFunction(signature string, params []interface{}) (interface{}, error) {
+	args, err := stringArgs(signature, params, 3)
+	if err != nil {
+		return nil, err
+	}
+	return strings.ReplaceAll(args[0], args[1], args[2]), nil
+}
+
+func joinFunction(signature string, params []interface{}) (interface{}, error) {
+	if len(params) != 2 {
+		return nil, signatureError(signature, "expected 2 arguments, got %d", len(params))
+	}
+
+	separator, ok := params[1].(string)
+	if !ok {
+		return nil, signatureError(signature, "separator must be a string, got %T", params[1])
+	}
+
+	list := reflect.ValueOf(params[0])
+	if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+		return nil, signatureError(signature, "first argument must be a list, got %T", params[0])
+	}
+
+	parts := make([]string, list.Len())
+	for i := 0; i < list.Len(); i++ {
+		parts[i] = fmt.Sprintf("%v", list.Index(i).Interface())
+	}
+	return strings.Join(parts, separator), nil
+}
+
+func splitFunction(signature string, params []interface{}) (interface{}, error) {
+	args, err := stringArgs(signature, params, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(args[0], args[1])
+	result := make([]interface{}, len(parts))
+	for i, part := range parts {
+		result[i] = part
+	}
+	return result, nil
+}
+
+func formatFunction(signature string, params []interface{}) (interface{}, error) {
+	if len(params) == 0 {
+		return nil, signatureError(signature, "expected at least 1 argument")
+	}
+
+	format, ok := params[0].(string)
+	if !ok {
+		return nil, signatureError(signature, "format must be a string, got %T", params[0])
+	}
+	return fmt.Sprintf(format, params[1:]...), nil
+}
+
+func lengthFunction(signature string, params []interface{}) (interface{}, error) {
+	if len(params) != 1 {
+		return nil, signatureError(signature, "expected 1 argument, got %d", len(params))
+	}
+
+	value := reflect.ValueOf(params[0])
+	switch value.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(value.String()), nil
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return value.Len(), nil
+	default:
+		return nil, signatureError(signature, "value must be a string, list or map, got %T", params[0])
+	}
+}
+
+// envFunction returns the value of an OS environment variable, failing if it is unset
+func envFunction(signature string, params []interface{}) (interface{}, error) {
+	args, err := stringArgs(signature, params, 1)
+	if err != nil {
+		return nil, err
+	}
+	name := args[0]
+
+	value, exists := os.LookupEnv(name)
+	if !exists {
+		return nil, &functionError{message: fmt.Sprintf("environment variable %s is not set", name)}
+	}
+
+	return value, nil
+}
+
+// exprOptions returns the compile options for evaluating expressions against the parser's
+// variables. A variable with the same name as a built-in function shadows the function.
+func (p *Parser) exprOptions() []expr.Option {
+	options := []expr.Option{expr.Env(p.variables)}
+	// Variables such as count must also win over expr's own builtins of the same name
+	for name := range p.variables {
+		options = append(options, expr.DisableBuiltin(name))
+	}
+	for _, fn := range builtinFunctions {
+		if _, shadowed := p.variables[fn.name]; shadowed {
+			continue
+		}
+		// Several names clash with expr's own builtins, which must be disabled to be replaced
+		call := func(params ...interface{}) (interface{}, error) {
+			return fn.call(fn.signature, params)
+		}
+		options = append(options, expr.DisableBuiltin(fn.name), expr.Function(fn.name, call))
+	}
+	return options
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParser_EnvFunction(t *testing.T) {
+	t.Setenv("RUNESTONE_TEST_DB_PASSWORD", "s3cret")
+
+	parser := NewParser()
+
+	result, err := parser.evaluateExpression("${env('RUNESTONE_TEST_DB_PASSWORD')}")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", result)
+
+	result, err = parser.evaluateExpression("db-${env('RUNESTONE_TEST_DB_PASSWORD')}")
+	require.NoError(t, err)
+	assert.Equal(t, "db-s3cret", result)
+}
+
+func TestParser_EnvFunction_Missing(t *testing.T) {
+	parser := NewParser()
+
+	_, err := parser.evaluateExpression("${env('RUNESTONE_TEST_UNSET_VARIABLE')}")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "environment variable RUNESTONE_TEST_UNSET_VARIABLE is not set")
+
+	cfg, err := parser.Parse([]byte(`
+project: test-project
+environment: dev
+resources:
+  - kind: aws:s3:bucket
+    name: logs
+    properties:
+      owner: "${env('RUNESTONE_TEST_UNSET_VARIABLE')}"
+`))
+	require.NoError(t, err)
+
+	_, err = parser.ExpandResources(cfg.Resources)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "RUNESTONE_TEST_UNSET_VARIABLE is not set")
+}
+
+func TestParser_EnvFunction_ShadowedByVariable(t *testing.T) {
+	parser := NewParser()
+	parser.variables = map[string]interface{}{"env": "prod"}
+
+	result, err := parser.evaluateExpression("${env == 'prod' ? 'production' : 'development'}")
+	require.NoError(t, err)
+	assert.Equal(t, "production", result)
+}
+
+func TestParser_HelperFunctions(t *testing.T) {
+	variables := map[string]interface{}{
+		"project":     "MyApp",
+		"environment": "prod",
+		"regions":     []interface{}{"us-east-1", "eu-west-1"},
+		"tags":        map[string]interface{}{"owner": "platform"},
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected interface{}
+	}{
+		{name: "upper", input: "${upper(project)}", expected: "MYAPP"},
+		{name: "lower with concatenation", input: "${lower(project) + '-' + environment}", expected: "myapp-prod"},
+		{name: "replace", input: "${replace('a.b.c', '.', '-')}", expected: "a-b-c"},
+		{name: "join", input: "${join(regions, ',')}", expected: "us-east-1,eu-west-1"},
+		{name: "split", input: "${split('a,b', ',')}", expected: []interface{}{"a", "b"}},
+		{name: "format", input: "${format('%s-%d', project, 3)}", expected: "MyApp-3"},
+		{name: "length of list", input: "${length(regions)}", expected: 2},
+		{name: "length of map", input: "${length(tags)}", expected: 1},
+		{name: "length of string", input: "${length(project)}", expected: 5},
+		{name: "embedded in string", input: "logs-${lower(project)}", expected: "logs-myapp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewParser()
+			parser.variables = variables
+
+			result, err := parser.evaluateExpression(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestParser_HelperFunctions_Misuse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		contains string
+	}{
+		{name: "wrong argument count", input: "${upper('a', 'b')}", contains: "upper(s string) string: expected 1 argument(s), got 2"},
+		{name: "wrong argument type", input: "${replace(1, 'a', 'b')}", contains: "replace(s string, old string, new string) string: argument 1 must be a string"},
+		{name: "join non-list", input: "${join('abc', ',')}", contains: "join(list []any, separator string) string"},
+		{name: "length of number", input: "${length(42)}", contains: "length(value string|list|map) int"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewParser()
+
+			_, err := parser.evaluateExpression(tt.input)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.contains)
+		})
+	}
+}
+
+func TestParser_HelperFunctionsInExpansion(t *testing.T) {
+	parser := NewParser()
+	parser.variables = map[string]interface{}{"project": "MyApp"}
+
+	instances, err := parser.ExpandResources([]Resource{
+		{
+			Kind:    "aws:s3:bucket",
+			Name:    "${lower(project)}-${region}",
+			ForEach: []interface{}{"us-east-1"},
+			Properties: map[string]interface{}{
+				"owner": "${upper(project)}",
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.Equal(t, "myapp-us-east-1", instances[0].Name)
+	assert.Equal(t, "MYAPP", instances[0].Properties["owner"])
+}
@@ -0,0 +1,171 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// StdinPath is the configuration path that reads the configuration from standard input
+const StdinPath = "-"
+
+// configFile is a configuration decoded from one of several files being merged
+type configFile struct {
+	path   string
+	config *Config
+}
+
+// configPaths returns the files a configuration path refers to: the YAML files directly
+// inside a directory, the files matching a glob pattern, or the path itself. Directories
+// and patterns are read in name order.
+func configPaths(path string) ([]string, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		var paths []string
+		for _, pattern := range []string{"*.yaml", "*.yml"} {
+			matches, err := filepath.Glob(filepath.Join(path, pattern))
+			if err != nil {
+				return nil, err
+			}
+			paths = append(paths, matches...)
+		}
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("no .yaml or .yml files found in config directory %s", path)
+		}
+		sort.Strings(paths)
+		return paths, nil
+	}
+
+	if strings.ContainsAny(path, "*?[") {
+		paths, err := filepath.Glob(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid config pattern %s: %w", path, err)
+		}
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("no config files match %s", path)
+		}
+		sort.Strings(paths)
+		return paths, nil
+	}
+
+	return []string{path}, nil
+}
+
+// resolveModuleSources makes the relative local module sources of a configuration read from
+// path relative to the file's directory instead of the working directory. Resolved sources
+// keep their ./ or ../ prefix, so they're still recognised as local.
+func resolveModuleSources(config *Config, path string) {
+	for name, module := range config.Modules {
+		if !strings.HasPrefix(module.Source, "./") && !strings.HasPrefix(module.Source, "../") {
+			continue
+		}
+
+		source := filepath.Join(filepath.Dir(path), module.Source)
+		if !filepath.IsAbs(source) && !strings.HasPrefix(source, "../") {
+			source = "./" + source
+		}
+		module.Source = source
+		config.Modules[name] = module
+	}
+}
+
+// mergeConfigs combines configurations split across files into one. Resources and
+// exemptions are concatenated in file order. Variables, providers, modules and outputs
+// are merged by name, and apiVersion, project, environment and policy may be set in any
+// number of files; setting any of them to different values in two files is an error, as
+// is defining the same resource in two files.
+func mergeConfigs(files []configFile) (*Config, error) {
+	merged := &Config{}
+	sources := make(map[string]string)
+
+	// setOnce records a top-level value, rejecting a different value from another file
+	setOnce := func(field string, target interface{}, value interface{}, path string) error {
+		if reflect.ValueOf(value).IsZero() {
+			return nil
+		}
+		current := reflect.ValueOf(target).Elem()
+		if !current.IsZero() && !reflect.DeepEqual(current.Interface(), value) {
+			return fmt.Errorf("%s is set to different values in %s and %s", field, sources[field], path)
+		}
+		current.Set(reflect.ValueOf(value))
+		sources[field] = path
+		return nil
+	}
+
+	resourceFiles := make(map[string]string)
+	for _, file := range files {
+		cfg := file.config
+
+		if err := setOnce("apiVersion", &merged.APIVersion, cfg.APIVersion, file.path); err != nil {
+			return nil, err
+		}
+		if err := setOnce("project", &merged.Project, cfg.Project, file.path); err != nil {
+			return nil, err
+		}
+		if err := setOnce("environment", &merged.Environment, cfg.Environment, file.path); err != nil {
+			return nil, err
+		}
+		if err := setOnce("policy", &merged.Policy, cfg.Policy, file.path); err != nil {
+			return nil, err
+		}
+		if err := setOnce("notifications", &merged.Notifications, cfg.Notifications, file.path); err != nil {
+			return nil, err
+		}
+
+		if err := mergeByName("variable", &merged.Variables, cfg.Variables, sources, file.path); err != nil {
+			return nil, err
+		}
+		if err := mergeByName("provider", &merged.Providers, cfg.Providers, sources, file.path); err != nil {
+			return nil, err
+		}
+		if err := mergeByName("module", &merged.Modules, cfg.Modules, sources, file.path); err != nil {
+			return nil, err
+		}
+		if err := mergeByName("output", &merged.Outputs, cfg.Outputs, sources, file.path); err != nil {
+			return nil, err
+		}
+
+		for _, resource := range cfg.Resources {
+			id := fmt.Sprintf("%s.%s", resource.Kind, resource.Name)
+			if previous, exists := resourceFiles[id]; exists {
+				if previous == file.path {
+					return nil, fmt.Errorf("resource %s is defined twice in %s", id, file.path)
+				}
+				return nil, fmt.Errorf("resource %s is defined in both %s and %s", id, previous, file.path)
+			}
+			resourceFiles[id] = file.path
+			merged.Resources = append(merged.Resources, resource)
+		}
+		merged.Exemptions = append(merged.Exemptions, cfg.Exemptions...)
+		merged.Moved = append(merged.Moved, cfg.Moved...)
+	}
+
+	return merged, nil
+}
+
+// mergeByName copies the entries of a map-valued section into target. An entry defined
+// in an earlier file with a different value is a conflict.
+func mergeByName[V any](section string, target *map[string]V, entries map[string]V, sources map[string]string, path string) error {
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if *target == nil {
+			*target = make(map[string]V)
+		}
+		key := section + " " + name
+		if existing, exists := (*target)[name]; exists && !reflect.DeepEqual(existing, entries[name]) {
+			return fmt.Errorf("%s %s is defined differently in %s and %s", section, name, sources[key], path)
+		}
+		(*target)[name] = entries[name]
+		if _, exists := sources[key]; !exists {
+			sources[key] = path
+		}
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfigFiles writes each named file into a new directory and returns its path
+func writeConfigFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+	return dir
+}
+
+func TestParser_ParseReader(t *testing.T) {
+	config, err := NewParser().ParseReader(strings.NewReader(`
+apiVersion: runestone/v1
+project: piped
+environment: dev
+resources:
+  - kind: aws:s3:bucket
+    name: ${project}-logs
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "piped", config.Project)
+	require.Len(t, config.Resources, 1)
+}
+
+func TestParser_ParseFile_Directory(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"main.yaml": `
+apiVersion: runestone/v1
+project: shop
+environment: prod
+variables:
+  region: us-east-1
+providers:
+  aws:
+    region: ${region}
+`,
+		"storage.yaml": `
+variables:
+  region: us-east-1
+  retention_days: 30
+resources:
+  - kind: aws:s3:bucket
+    name: ${project}-logs
+`,
+		"network.yml": `
+resources:
+  - kind: aws:ec2:vpc
+    name: main
+exemptions:
+  - resource: aws:ec2:vpc.main
+    rule: resources-must-have-environment-tag
+`,
+		"README.md": "not configuration",
+	})
+
+	parser := NewParser()
+	config, err := parser.ParseFile(dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, "shop", config.Project)
+	assert.Equal(t, "prod", config.Environment)
+	assert.Equal(t, "us-east-1", config.Providers["aws"].Region)
+	assert.Equal(t, 30, config.Variables["retention_days"])
+	require.Len(t, config.Exemptions, 1)
+
+	// Files are merged in name order
+	instances, err := parser.ExpandResources(config.Resources)
+	require.NoError(t, err)
+	require.Len(t, instances, 2)
+	assert.Equal(t, "aws:ec2:vpc.main", instances[0].ID)
+	assert.Equal(t, "aws:s3:bucket.shop-logs", instances[1].ID)
+}
+
+func TestParser_ParseFile_Glob(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"infra-a.yaml": "apiVersion: runestone/v1\nproject: shop\nenvironment: dev\nresources:\n  - kind: aws:s3:bucket\n    name: a\n",
+		"infra-b.yaml": "resources:\n  - kind: aws:s3:bucket\n    name: b\n",
+		"other.yaml":   "resources:\n  - kind: aws:s3:bucket\n    name: other\n",
+	})
+
+	config, err := NewParser().ParseFile(filepath.Join(dir, "infra-*.yaml"))
+	require.NoError(t, err)
+	require.Len(t, config.Resources, 2)
+	assert.Equal(t, "a", config.Resources[0].Name)
+	assert.Equal(t, "b", config.Resources[1].Name)
+
+	_, err = NewParser().ParseFile(filepath.Join(dir, "missing-*.yaml"))
+	assert.ErrorContains(t, err, "no config files match")
+
+	_, err = NewParser().ParseFile(t.TempDir())
+	assert.ErrorContains(t, err, "no .yaml or .yml files found")
+}
+
+func TestParser_ParseFile_MergeConflicts(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name: "duplicate resource",
+			files: map[string]string{
+				"a.yaml": "resources:\n  - kind: aws:s3:bucket\n    name: logs\n",
+				"b.yaml": "resources:\n  - kind: aws:s3:bucket\n    name: logs\n",
+			},
+			wantErr: "resource aws:s3:bucket.logs is defined in both",
+		},
+		{
+			name: "conflicting project",
+			files: map[string]string{
+				"a.yaml": "project: shop\n",
+				"b.yaml": "project: blog\n",
+			},
+			wantErr: "project is set to different values in",
+		},
+		{
+			name: "conflicting variable",
+			files: map[string]string{
+				"a.yaml": "variables:\n  region: us-east-1\n",
+				"b.yaml": "variables:\n  region: eu-west-1\n",
+			},
+			wantErr: "variable region is defined differently in",
+		},
+		{
+			name: "conflicting provider",
+			files: map[string]string{
+				"a.yaml": "providers:\n  aws:\n    region: us-east-1\n",
+				"b.yaml": "providers:\n  aws:\n    region: eu-west-1\n",
+			},
+			wantErr: "provider aws is defined differently in",
+		},
+		{
+			name: "invalid file",
+			files: map[string]string{
+				"a.yaml": "project: shop\n",
+				"b.yaml": "resources:\n  - kind: aws:s3:bucket\n    nme: logs\n",
+			},
+			wantErr: "b.yaml: ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewParser().ParseFile(writeConfigFiles(t, tt.files))
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestParser_ParseFile_ModuleSources(t *testing.T) {
+	dir := writeConfigFiles(t, map[string]string{
+		"main.yaml": `
+apiVersion: runestone/v1
+project: shop
+environment: prod
+modules:
+  network:
+    source: ./modules/network
+  shared:
+    source: ../shared
+  remote:
+    source: git::https://github.com/acme/modules.git//network
+`,
+	})
+
+	// Local sources are relative to the file, not the working directory
+	config, err := NewParser().ParseFile(filepath.Join(dir, "main.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "modules", "network"), config.Modules["network"].Source)
+	assert.Equal(t, filepath.Join(filepath.Dir(dir), "shared"), config.Modules["shared"].Source)
+	assert.Equal(t, "git::https://github.com/acme/modules.git//network", config.Modules["remote"].Source)
+}
+
+func TestResolveModuleSources(t *testing.T) {
+	config := &Config{Modules: map[string]Module{
+		"network": {Source: "./modules/network"},
+		"parent":  {Source: "../shared"},
+		"sibling": {Source: "../../shared"},
+		"root":    {Source: "/opt/modules/dns"},
+	}}
+	resolveModuleSources(config, filepath.Join("infra", "main.yaml"))
+
+	assert.Equal(t, "./infra/modules/network", config.Modules["network"].Source)
+	assert.Equal(t, "./shared", config.Modules["parent"].Source)
+	assert.Equal(t, "../shared", config.Modules["sibling"].Source)
+	assert.Equal(t, "/opt/modules/dns", config.Modules["root"].Source)
+
+	// A configuration in the working directory keeps its sources as written
+	config = &Config{Modules: map[string]Module{"network": {Source: "./modules/network"}}}
+	resolveModuleSources(config, "main.yaml")
+	assert.Equal(t, "./modules/network", config.Modules["network"].Source)
+}
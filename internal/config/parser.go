@@ -1,9 +1,14 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"reflect"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -14,6 +19,19 @@ import (
 // Parser handles parsing and processing of Runestone configuration files
 type Parser struct {
 	variables map[string]interface{}
+	overrides map[string]interface{}
+	modules   []moduleInstance
+	warn      func(message string)
+	// locations records where each resource in the parsed configuration is declared,
+	// for error messages
+	locations map[string]string
+}
+
+// moduleInstance is a loaded module whose resources are expanded alongside the root resources
+type moduleInstance struct {
+	name       string
+	definition *ModuleDefinition
+	inputs     map[string]interface{}
 }
 
 // NewParser creates a new configuration parser
@@ -23,11 +41,90 @@ func NewParser() *Parser {
 	}
 }
 
-// ParseFile parses a Runestone configuration file
+// SetVariableOverrides sets variables that take precedence over those declared in the
+// configuration. They are merged with MergeVariables, so nested maps keep the keys an
+// override leaves out.
+func (p *Parser) SetVariableOverrides(overrides map[string]interface{}) {
+	p.overrides = overrides
+}
+
+// SetWarningHandler sets the function called with non-fatal problems found while parsing,
+// such as deprecated syntax. Warnings are discarded when no handler is set.
+func (p *Parser) SetWarningHandler(handler func(message string)) {
+	p.warn = handler
+}
+
+// warning reports a non-fatal problem to the warning handler, if any
+func (p *Parser) warning(message string) {
+	if p.warn != nil {
+		p.warn(message)
+	}
+}
+
+// AddModule registers a loaded module so ExpandResources includes its resources. Inside the
+// module, inputs override the module's variable defaults and ${module.name} is the module name.
+func (p *Parser) AddModule(name string, definition *ModuleDefinition, inputs map[string]interface{}) {
+	p.modules = append(p.modules, moduleInstance{name: name, definition: definition, inputs: inputs})
+}
+
+// ParseFile parses a Runestone configuration file. The filename "-" reads the configuration
+// from standard input, and a directory or glob pattern merges every matching YAML file into
+// one configuration as described by mergeConfigs.
 func (p *Parser) ParseFile(filename string) (*Config, error) {
-	data, err := os.ReadFile(filename)
+	if filename == StdinPath {
+		return p.ParseReader(os.Stdin)
+	}
+
+	paths, err := configPaths(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(paths) == 1 {
+		data, err := os.ReadFile(paths[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		config, err := decodeConfig(data)
+		if err != nil {
+			return nil, err
+		}
+		resolveModuleSources(config, paths[0])
+		p.locations = resourceLocations(data, paths[0])
+		return p.process(config)
+	}
+
+	p.locations = make(map[string]string)
+
+	files := make([]configFile, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		config, err := decodeConfig(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		resolveModuleSources(config, path)
+		for key, location := range resourceLocations(data, path) {
+			p.locations[key] = location
+		}
+		files = append(files, configFile{path: path, config: config})
+	}
+
+	config, err := mergeConfigs(files)
+	if err != nil {
+		return nil, err
+	}
+	return p.process(config)
+}
+
+// ParseReader parses a Runestone configuration read from r, such as standard input
+func (p *Parser) ParseReader(r io.Reader) (*Config, error) {
+	data, err := io.ReadAll(r)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return nil, fmt.Errorf("failed to read config: %w", err)
 	}
 
 	return p.Parse(data)
@@ -40,24 +137,53 @@ func (p *Parser) ParseFromString(configYAML string) (*Config, error) {
 
 // Parse parses Runestone configuration from YAML data
 func (p *Parser) Parse(data []byte) (*Config, error) {
-	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	config, err := decodeConfig(data)
+	if err != nil {
+		return nil, err
 	}
+	p.locations = resourceLocations(data, "")
 
-	// Set up variables for expression evaluation
-	p.variables = config.Variables
-	if p.variables == nil {
-		p.variables = make(map[string]interface{})
+	return p.process(config)
+}
+
+// process checks the apiVersion of a decoded configuration and evaluates its expressions
+func (p *Parser) process(config *Config) (*Config, error) {
+	warning, err := checkAPIVersion(config)
+	if err != nil {
+		return nil, err
 	}
+	if warning != "" {
+		p.warning(warning)
+	}
+
+	// Set up variables for expression evaluation
+	p.variables = MergeVariables(config.Variables, p.overrides)
 	p.variables["environment"] = config.Environment
 	p.variables["project"] = config.Project
 
 	// Process expressions in the configuration
-	if err := p.processExpressions(&config); err != nil {
+	if err := p.processExpressions(config); err != nil {
 		return nil, fmt.Errorf("failed to process expressions: %w", err)
 	}
 
+	return config, nil
+}
+
+// decodeConfig unmarshals YAML into a Config, rejecting unknown or misspelled keys.
+// Errors from the YAML decoder carry the offending line number.
+func decodeConfig(data []byte) (*Config, error) {
+	var config Config
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && err != io.EOF {
+		var typeErr *yaml.TypeError
+		if errors.As(err, &typeErr) {
+			return nil, fmt.Errorf("invalid configuration:\n  %s", strings.Join(typeErr.Errors, "\n  "))
+		}
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+
 	return &config, nil
 }
 
@@ -83,6 +209,17 @@ func (p *Parser) processExpressions(config *Config) error {
 			}
 		}
 		
+		// Process DefaultTags values
+		for key, value := range provider.DefaultTags {
+			if strings.Contains(value, "${") {
+				if processed, err := p.evaluateExpression(value); err != nil {
+					return fmt.Errorf("error processing provider %s default tag %s: %w", name, key, err)
+				} else {
+					provider.DefaultTags[key] = fmt.Sprintf("%v", processed)
+				}
+			}
+		}
+
 		config.Providers[name] = provider
 	}
 
@@ -94,13 +231,24 @@ func (p *Parser) processExpressions(config *Config) error {
 		config.Modules[name] = module
 	}
 
-	// Process resource expressions using reflection
-	for i := range config.Resources {
-		if err := p.processValue(&config.Resources[i]); err != nil {
-			return fmt.Errorf("error processing resource %d: %w", i, err)
+	// Resource references in outputs are left for the executor to resolve after a commit
+	for name, output := range config.Outputs {
+		if err := p.processValue(&output); err != nil {
+			return fmt.Errorf("error processing output %s: %w", name, err)
 		}
+		config.Outputs[name] = output
 	}
 
+	// Webhook URLs usually embed a token, so they are often read with env()
+	if config.Notifications != nil {
+		if err := p.processValue(config.Notifications); err != nil {
+			return fmt.Errorf("error processing notifications: %w", err)
+		}
+	}
+
+	// Resource expressions are evaluated in ExpandResources, once count and for_each
+	// variables are known
+
 	return nil
 }
 
@@ -121,12 +269,8 @@ func (p *Parser) processValueReflectWithVisited(val reflect.Value, visited map[u
 		if val.IsNil() {
 			return nil
 		}
-		// Check for cycles
-		ptr := val.Pointer()
-		if visited[ptr] {
-			return nil // Already processed
-		}
-		visited[ptr] = true
+		// Cycles are caught by the struct, map and slice cases; recording the pointer
+		// here would make a struct look visited before its fields are processed
 		return p.processValueReflectWithVisited(val.Elem(), visited)
 	}
 
@@ -135,6 +279,23 @@ func (p *Parser) processValueReflectWithVisited(val reflect.Value, visited map[u
 		if val.IsNil() {
 			return nil
 		}
+		// A string inside an interface (such as a list element) can't be set through Elem,
+		// so the interface value itself is replaced
+		if str, ok := val.Interface().(string); ok {
+			if !val.CanSet() {
+				return nil
+			}
+			processed, err := p.evaluateExpression(str)
+			if err != nil {
+				return err
+			}
+			if processed == nil {
+				val.Set(reflect.Zero(val.Type()))
+			} else if processed != str {
+				val.Set(reflect.ValueOf(processed))
+			}
+			return nil
+		}
 		return p.processValueReflectWithVisited(val.Elem(), visited)
 	}
 
@@ -256,11 +417,13 @@ func (p *Parser) evaluateExpression(input string) (interface{}, error) {
 
 	// Handle multiple expressions or mixed content
 	result := input
+	searchFrom := 0
 	for {
-		start := strings.Index(result, "${")
+		start := strings.Index(result[searchFrom:], "${")
 		if start == -1 {
 			break
 		}
+		start += searchFrom
 
 		end := strings.Index(result[start:], "}")
 		if end == -1 {
@@ -274,7 +437,10 @@ func (p *Parser) evaluateExpression(input string) (interface{}, error) {
 			return nil, fmt.Errorf("error evaluating expression '%s': %w", exprStr, err)
 		}
 
-		result = result[:start] + fmt.Sprintf("%v", value) + result[end+1:]
+		// Resume after the substitution so deferred expressions aren't evaluated again
+		replacement := fmt.Sprintf("%v", value)
+		result = result[:start] + replacement + result[end+1:]
+		searchFrom = start + len(replacement)
 	}
 
 	return result, nil
@@ -287,13 +453,16 @@ func (p *Parser) evaluateExpr(exprStr string) (interface{}, error) {
 		return val, nil
 	}
 
-	// For variables that might not exist yet (like 'index' during initial parsing),
+	// For variables that might not exist yet (like 'count.index' during initial parsing),
 	// return the expression as-is to be evaluated later during expansion
 	if isSimpleVariable(exprStr) {
-		return "${" + exprStr + "}", nil
+		root := strings.SplitN(exprStr, ".", 2)[0]
+		if _, exists := p.variables[root]; !exists {
+			return "${" + exprStr + "}", nil
+		}
 	}
 
-	program, err := expr.Compile(exprStr, expr.Env(p.variables))
+	program, err := expr.Compile(exprStr, p.exprOptions()...)
 	if err != nil {
 		// If compilation fails due to unknown variables, return the expression as-is
 		// This will be re-evaluated later during resource expansion
@@ -302,6 +471,11 @@ func (p *Parser) evaluateExpr(exprStr string) (interface{}, error) {
 
 	result, err := expr.Run(program, p.variables)
 	if err != nil {
+		// Errors raised by built-in functions won't resolve later, so report them now
+		var fnErr *functionError
+		if errors.As(err, &fnErr) {
+			return nil, fnErr
+		}
 		// If execution fails, return the expression as-is for later evaluation
 		return "${" + exprStr + "}", nil
 	}
@@ -316,24 +490,192 @@ func isSimpleVariable(expr string) bool {
 }
 
 // ExpandResources expands resources with count and for_each into individual instances
+// and checks that every depends_on entry names one of them, so typos are reported with
+// the resource's position before a dependency graph is built
 func (p *Parser) ExpandResources(resources []Resource) ([]ResourceInstance, error) {
 	var instances []ResourceInstance
 
+	// Modules are expanded first so root resources can use their outputs
+	var moduleInstances []ResourceInstance
+	moduleIDs := make(map[string][]string, len(p.modules))
+	if len(p.modules) > 0 {
+		moduleOutputs := make(map[string]interface{}, len(p.modules))
+		for _, module := range p.modules {
+			expanded, outputs, err := p.expandModule(module)
+			if err != nil {
+				return nil, err
+			}
+			for _, instance := range expanded {
+				moduleIDs[module.name] = append(moduleIDs[module.name], instance.ID)
+			}
+			moduleOutputs[module.name] = outputs
+			moduleInstances = append(moduleInstances, expanded...)
+		}
+		p.variables["module"] = moduleOutputs
+	}
+
+	locations := make(map[string]string)
 	for _, resource := range resources {
 		expanded, err := p.expandResource(resource)
 		if err != nil {
 			return nil, fmt.Errorf("error expanding resource %s: %w", resource.Name, err)
 		}
+		if location, exists := p.locations[resource.Kind+"."+resource.Name]; exists {
+			for _, instance := range expanded {
+				locations[instance.ID] = location
+			}
+		}
+
+		// Resources using a module's outputs depend on everything the module creates
+		for _, moduleName := range referencedModules(resource) {
+			ids, exists := moduleIDs[moduleName]
+			if !exists {
+				continue
+			}
+			for i := range expanded {
+				for _, id := range ids {
+					if !containsString(expanded[i].DependsOn, id) {
+						expanded[i].DependsOn = append(expanded[i].DependsOn, id)
+					}
+				}
+			}
+		}
+
 		instances = append(instances, expanded...)
 	}
 
+	if len(p.modules) == 0 {
+		if err := checkDependencies(instances, locations); err != nil {
+			return nil, err
+		}
+		return instances, nil
+	}
+
+	seen := make(map[string]string, len(instances))
+	for _, instance := range instances {
+		seen[instance.ID] = "the root configuration"
+	}
+
+	for _, module := range p.modules {
+		for _, id := range moduleIDs[module.name] {
+			if owner, exists := seen[id]; exists {
+				return nil, fmt.Errorf("module %s: resource %s is already defined in %s", module.name, id, owner)
+			}
+			seen[id] = "module " + module.name
+			locations[id] = "module " + module.name
+		}
+	}
+
+	instances = append(instances, moduleInstances...)
+	if err := checkDependencies(instances, locations); err != nil {
+		return nil, err
+	}
 	return instances, nil
 }
 
+// moduleReferencePattern matches module.<name> inside an expression
+var moduleReferencePattern = regexp.MustCompile(`\bmodule\.([A-Za-z0-9_]+)`)
+
+// referencedModules returns the names of modules whose outputs a resource's expressions use
+func referencedModules(resource Resource) []string {
+	var names []string
+	var visit func(value interface{})
+	visit = func(value interface{}) {
+		switch v := value.(type) {
+		case string:
+			for _, expression := range expressionPattern.FindAllStringSubmatch(v, -1) {
+				for _, match := range moduleReferencePattern.FindAllStringSubmatch(expression[1], -1) {
+					if !containsString(names, match[1]) {
+						names = append(names, match[1])
+					}
+				}
+			}
+		case map[string]interface{}:
+			for _, item := range v {
+				visit(item)
+			}
+		case []interface{}:
+			for _, item := range v {
+				visit(item)
+			}
+		}
+	}
+
+	visit(resource.Name)
+	visit(resource.Count)
+	visit(resource.ForEach)
+	visit(resource.Properties)
+	sort.Strings(names)
+	return names
+}
+
+// expressionPattern matches a ${...} expression
+var expressionPattern = regexp.MustCompile(`\$\{([^}]*)\}`)
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
+// expandModule expands a module's resources and evaluates its outputs using its own variable scope
+func (p *Parser) expandModule(module moduleInstance) ([]ResourceInstance, map[string]interface{}, error) {
+	if module.definition == nil {
+		return nil, map[string]interface{}{}, nil
+	}
+
+	variables := make(map[string]interface{})
+	for name, value := range module.definition.Variables {
+		variables[name] = value
+	}
+	for name, value := range module.inputs {
+		variables[name] = value
+	}
+	variables["environment"] = p.variables["environment"]
+	variables["project"] = p.variables["project"]
+	variables["module"] = map[string]interface{}{"name": module.name}
+
+	moduleParser := &Parser{variables: variables}
+
+	var instances []ResourceInstance
+	for _, resource := range module.definition.Resources {
+		expanded, err := moduleParser.expandResource(resource)
+		if err != nil {
+			return nil, nil, fmt.Errorf("module %s: error expanding resource %s: %w", module.name, resource.Name, err)
+		}
+		instances = append(instances, expanded...)
+	}
+
+	// Outputs referencing resource attributes stay as references and resolve at commit
+	outputs := make(map[string]interface{}, len(module.definition.Outputs))
+	if module.definition.Outputs != nil {
+		outputs = deepCopyValue(module.definition.Outputs).(map[string]interface{})
+		if err := moduleParser.processValue(outputs); err != nil {
+			return nil, nil, fmt.Errorf("module %s: error evaluating outputs: %w", module.name, err)
+		}
+	}
+
+	return instances, outputs, nil
+}
+
 // expandResource expands a single resource based on count or for_each
 func (p *Parser) expandResource(resource Resource) ([]ResourceInstance, error) {
 	var instances []ResourceInstance
 
+	// A disabled resource has no instances, whatever its count or for_each
+	if resource.Enabled != nil {
+		enabled, err := p.resolveEnabled(resource.Enabled)
+		if err != nil {
+			return nil, fmt.Errorf("error resolving enabled: %w", err)
+		}
+		if !enabled {
+			return instances, nil
+		}
+	}
+
 	// Handle count
 	if resource.Count != nil {
 		count, err := p.resolveCount(resource.Count)
@@ -342,7 +684,11 @@ func (p *Parser) expandResource(resource Resource) ([]ResourceInstance, error) {
 		}
 
 		for i := 0; i < count; i++ {
-			instance, err := p.createInstance(resource, map[string]interface{}{"index": i})
+			vars := map[string]interface{}{
+				"count": map[string]interface{}{"index": i},
+				"index": i, // Deprecated: use count.index
+			}
+			instance, err := p.createInstance(resource, vars)
 			if err != nil {
 				return nil, err
 			}
@@ -353,16 +699,19 @@ func (p *Parser) expandResource(resource Resource) ([]ResourceInstance, error) {
 
 	// Handle for_each
 	if resource.ForEach != nil {
-		items, err := p.resolveForEach(resource.ForEach)
+		entries, err := p.resolveForEach(resource.ForEach)
 		if err != nil {
 			return nil, fmt.Errorf("error resolving for_each: %w", err)
 		}
 
-		for _, item := range items {
-			vars := map[string]interface{}{}
-			switch v := item.(type) {
+		for _, entry := range entries {
+			vars := map[string]interface{}{
+				"each": map[string]interface{}{"key": entry.key, "value": entry.value},
+			}
+			// Deprecated: use each.value
+			switch v := entry.value.(type) {
 			case string:
-				vars["region"] = v // Common case for regions
+				vars["region"] = v
 			default:
 				vars["item"] = v
 			}
@@ -400,8 +749,22 @@ func (p *Parser) createInstance(resource Resource, vars map[string]interface{})
 	// Create a temporary parser with instance variables
 	tempParser := &Parser{variables: instanceVars}
 
-	// Process the resource with instance variables
+	// Process a copy of the resource with instance variables; properties are evaluated in
+	// place, so they must not share maps or slices with other instances
 	resourceCopy := resource
+	if resource.Properties != nil {
+		resourceCopy.Properties = deepCopyValue(resource.Properties).(map[string]interface{})
+	}
+	if resource.DependsOn != nil {
+		resourceCopy.DependsOn = append([]string(nil), resource.DependsOn...)
+	}
+	if resource.Sensitive != nil {
+		resourceCopy.Sensitive = append([]string(nil), resource.Sensitive...)
+	}
+	if resource.Timeouts != nil {
+		timeouts := *resource.Timeouts
+		resourceCopy.Timeouts = &timeouts
+	}
 	
 	// Process Name field directly
 	if strings.Contains(resourceCopy.Name, "${") {
@@ -417,6 +780,17 @@ func (p *Parser) createInstance(resource Resource, vars map[string]interface{})
 		return ResourceInstance{}, err
 	}
 
+	if resourceCopy.Timeouts != nil {
+		if err := resourceCopy.Timeouts.validate(); err != nil {
+			return ResourceInstance{}, err
+		}
+	}
+	if resourceCopy.DriftPolicy != nil {
+		if err := resourceCopy.DriftPolicy.validate(); err != nil {
+			return ResourceInstance{}, err
+		}
+	}
+
 	instance := ResourceInstance{
 		ID:          fmt.Sprintf("%s.%s", resourceCopy.Kind, resourceCopy.Name),
 		Kind:        resourceCopy.Kind,
@@ -424,18 +798,45 @@ func (p *Parser) createInstance(resource Resource, vars map[string]interface{})
 		Properties:  resourceCopy.Properties,
 		DriftPolicy: resourceCopy.DriftPolicy,
 		DependsOn:   resourceCopy.DependsOn,
+		Sensitive:   resourceCopy.Sensitive,
+		Provider:    resourceCopy.Provider,
+		Timeouts:    resourceCopy.Timeouts,
 	}
 
 	return instance, nil
 }
 
+// deepCopyValue copies nested maps and slices so they can be modified independently
+func deepCopyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			copied[key] = deepCopyValue(item)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = deepCopyValue(item)
+		}
+		return copied
+	default:
+		return value
+	}
+}
+
 // resolveCount resolves a count value (int or expression)
 func (p *Parser) resolveCount(count interface{}) (int, error) {
 	switch v := count.(type) {
 	case int:
 		return v, nil
 	case string:
-		result, err := p.evaluateExpr(v)
+		exprStr := v
+		if strings.HasPrefix(v, "${") && strings.HasSuffix(v, "}") {
+			exprStr = v[2 : len(v)-1]
+		}
+		result, err := p.evaluateExpr(exprStr)
 		if err != nil {
 			return 0, err
 		}
@@ -451,27 +852,141 @@ func (p *Parser) resolveCount(count interface{}) (int, error) {
 	}
 }
 
-// resolveForEach resolves a for_each value (array or expression)
-func (p *Parser) resolveForEach(forEach interface{}) ([]interface{}, error) {
-	switch v := forEach.(type) {
-	case []interface{}:
+// resolveEnabled resolves an enabled value (bool or expression)
+func (p *Parser) resolveEnabled(enabled interface{}) (bool, error) {
+	switch v := enabled.(type) {
+	case bool:
 		return v, nil
 	case string:
-		// Check if it's an expression
+		exprStr := v
 		if strings.HasPrefix(v, "${") && strings.HasSuffix(v, "}") {
-			exprStr := v[2 : len(v)-1]
-			result, err := p.evaluateExpr(exprStr)
-			if err != nil {
-				return nil, err
-			}
-			if slice, ok := result.([]interface{}); ok {
-				return slice, nil
+			exprStr = v[2 : len(v)-1]
+		}
+		result, err := p.evaluateExpr(exprStr)
+		if err != nil {
+			return false, err
+		}
+		if boolVal, ok := result.(bool); ok {
+			return boolVal, nil
+		}
+		if strVal, ok := result.(string); ok {
+			if boolVal, err := strconv.ParseBool(strVal); err == nil {
+				return boolVal, nil
 			}
-			return nil, fmt.Errorf("for_each expression must evaluate to an array")
 		}
-		// If it's not an expression, treat it as a single-item array
-		return []interface{}{v}, nil
+		return false, fmt.Errorf("enabled expression must evaluate to a boolean")
+	default:
+		return false, fmt.Errorf("enabled must be a boolean or expression")
+	}
+}
+
+// forEachEntry is one iteration of a for_each, exposed to expressions as each.key and each.value
+type forEachEntry struct {
+	key   interface{}
+	value interface{}
+}
+
+// resolveForEach resolves a for_each value (array, map or expression) into its entries.
+// Maps iterate in key order; string list items use the item as their key, other items their index.
+func (p *Parser) resolveForEach(forEach interface{}) ([]forEachEntry, error) {
+	v, isString := forEach.(string)
+	if !isString {
+		if entries, ok := collectionEntries(forEach); ok {
+			return entries, nil
+		}
+		return nil, fmt.Errorf("for_each must be an array, map or expression, got %s", describeType(forEach))
+	}
+
+	// If it's not an expression, treat it as a single-item array
+	if !strings.HasPrefix(v, "${") || !strings.HasSuffix(v, "}") {
+		return listEntries([]interface{}{v}), nil
+	}
+
+	exprStr := v[2 : len(v)-1]
+	result, err := p.evaluateExpr(exprStr)
+	if err != nil {
+		return nil, err
+	}
+	if entries, ok := collectionEntries(result); ok {
+		return entries, nil
+	}
+	// Expressions that can't be evaluated yet come back unchanged
+	if result == v {
+		return nil, fmt.Errorf("for_each expression %s could not be evaluated; check that the variables it uses are defined", v)
+	}
+	return nil, fmt.Errorf("for_each expression %s must evaluate to an array or map, got %s", v, describeType(result))
+}
+
+// collectionEntries returns the entries of a list or of a map with string keys, including
+// typed ones such as the []string returned by split
+func collectionEntries(value interface{}) ([]forEachEntry, bool) {
+	switch collection := value.(type) {
+	case []interface{}:
+		return listEntries(collection), true
+	case map[string]interface{}:
+		return mapEntries(collection), true
+	}
+
+	reflected := reflect.ValueOf(value)
+	switch reflected.Kind() {
+	case reflect.Slice, reflect.Array:
+		items := make([]interface{}, reflected.Len())
+		for i := range items {
+			items[i] = reflected.Index(i).Interface()
+		}
+		return listEntries(items), true
+	case reflect.Map:
+		if reflected.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		items := make(map[string]interface{}, reflected.Len())
+		iter := reflected.MapRange()
+		for iter.Next() {
+			items[iter.Key().String()] = iter.Value().Interface()
+		}
+		return mapEntries(items), true
+	}
+	return nil, false
+}
+
+// describeType names the type of a configuration value the way it is written in YAML
+func describeType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a boolean"
+	case int, int64, float64:
+		return fmt.Sprintf("a number (%v)", value)
+	case string:
+		return fmt.Sprintf("a string (%q)", value)
 	default:
-		return nil, fmt.Errorf("for_each must be an array or expression")
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func listEntries(items []interface{}) []forEachEntry {
+	entries := make([]forEachEntry, len(items))
+	for i, item := range items {
+		var key interface{} = i
+		if str, ok := item.(string); ok {
+			key = str
+		}
+		entries[i] = forEachEntry{key: key, value: item}
+	}
+	return entries
+}
+
+func mapEntries(items map[string]interface{}) []forEachEntry {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	entries := make([]forEachEntry, len(keys))
+	for i, key := range keys {
+		entries[i] = forEachEntry{key: key, value: items[key]}
 	}
+	return entries
 }
@@ -2,6 +2,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -94,6 +95,33 @@ resources:
 				},
 			},
 		},
+		{
+			name: "provider default tags with expressions",
+			yaml: `
+project: test-project
+environment: prod
+providers:
+  aws:
+    region: us-east-1
+    default_tags:
+      Environment: "${environment}"
+      Owner: platform-team
+resources: []
+`,
+			expected: &Config{
+				Project:     "test-project",
+				Environment: "prod",
+				Providers: map[string]Provider{
+					"aws": {
+						Region: "us-east-1",
+						DefaultTags: map[string]string{
+							"Environment": "prod",
+							"Owner":       "platform-team",
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "configuration with ternary expressions",
 			yaml: `
@@ -282,6 +310,145 @@ func TestParser_ExpandResources(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "resource with count.index",
+			resources: []Resource{
+				{
+					Kind:  "aws:ec2:instance",
+					Name:  "web-${count.index}",
+					Count: "${instance_count}",
+					Properties: map[string]interface{}{
+						"tags": map[string]interface{}{"Index": "${count.index}"},
+					},
+				},
+			},
+			variables: map[string]interface{}{"instance_count": 2},
+			expected: []ResourceInstance{
+				{
+					ID:   "aws:ec2:instance.web-0",
+					Kind: "aws:ec2:instance",
+					Name: "web-0",
+					Properties: map[string]interface{}{
+						"tags": map[string]interface{}{"Index": 0},
+					},
+				},
+				{
+					ID:   "aws:ec2:instance.web-1",
+					Kind: "aws:ec2:instance",
+					Name: "web-1",
+					Properties: map[string]interface{}{
+						"tags": map[string]interface{}{"Index": 1},
+					},
+				},
+			},
+		},
+		{
+			name: "resource with for_each over a list using each",
+			resources: []Resource{
+				{
+					Kind:    "aws:s3:bucket",
+					Name:    "logs-${each.key}",
+					ForEach: []interface{}{"us-east-1", "us-west-2"},
+					Properties: map[string]interface{}{
+						"region": "${each.value}",
+					},
+				},
+			},
+			expected: []ResourceInstance{
+				{
+					ID:         "aws:s3:bucket.logs-us-east-1",
+					Kind:       "aws:s3:bucket",
+					Name:       "logs-us-east-1",
+					Properties: map[string]interface{}{"region": "us-east-1"},
+				},
+				{
+					ID:         "aws:s3:bucket.logs-us-west-2",
+					Kind:       "aws:s3:bucket",
+					Name:       "logs-us-west-2",
+					Properties: map[string]interface{}{"region": "us-west-2"},
+				},
+			},
+		},
+		{
+			name: "resource with for_each over a map",
+			resources: []Resource{
+				{
+					Kind:    "aws:s3:bucket",
+					Name:    "data-${each.key}",
+					ForEach: "${buckets}",
+					Properties: map[string]interface{}{
+						"versioning": "${each.value.versioning}",
+					},
+				},
+			},
+			variables: map[string]interface{}{
+				"buckets": map[string]interface{}{
+					"raw":     map[string]interface{}{"versioning": false},
+					"curated": map[string]interface{}{"versioning": true},
+				},
+			},
+			expected: []ResourceInstance{
+				{
+					ID:         "aws:s3:bucket.data-curated",
+					Kind:       "aws:s3:bucket",
+					Name:       "data-curated",
+					Properties: map[string]interface{}{"versioning": true},
+				},
+				{
+					ID:         "aws:s3:bucket.data-raw",
+					Kind:       "aws:s3:bucket",
+					Name:       "data-raw",
+					Properties: map[string]interface{}{"versioning": false},
+				},
+			},
+		},
+		{
+			name: "resource with for_each over a map literal",
+			resources: []Resource{
+				{
+					Kind: "aws:s3:bucket",
+					Name: "data-${each.key}",
+					ForEach: map[string]interface{}{
+						"raw":     "us-east-1",
+						"curated": "eu-west-1",
+					},
+					Properties: map[string]interface{}{
+						"region": "${each.value}",
+					},
+				},
+			},
+			expected: []ResourceInstance{
+				{
+					ID:         "aws:s3:bucket.data-curated",
+					Kind:       "aws:s3:bucket",
+					Name:       "data-curated",
+					Properties: map[string]interface{}{"region": "eu-west-1"},
+				},
+				{
+					ID:         "aws:s3:bucket.data-raw",
+					Kind:       "aws:s3:bucket",
+					Name:       "data-raw",
+					Properties: map[string]interface{}{"region": "us-east-1"},
+				},
+			},
+		},
+		{
+			name: "resource with for_each over a function result",
+			resources: []Resource{
+				{
+					Kind:    "aws:s3:bucket",
+					Name:    "logs-${each.value}",
+					ForEach: `${split(regions, ",")}`,
+				},
+			},
+			variables: map[string]interface{}{
+				"regions": "us-east-1,eu-west-1",
+			},
+			expected: []ResourceInstance{
+				{ID: "aws:s3:bucket.logs-us-east-1", Kind: "aws:s3:bucket", Name: "logs-us-east-1"},
+				{ID: "aws:s3:bucket.logs-eu-west-1", Kind: "aws:s3:bucket", Name: "logs-eu-west-1"},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -314,6 +481,20 @@ func TestParser_ExpandResources(t *testing.T) {
 	}
 }
 
+func TestParser_ExpandResources_ForEachErrors(t *testing.T) {
+	parser := NewParser()
+	parser.variables = map[string]interface{}{"replicas": 3}
+
+	_, err := parser.ExpandResources([]Resource{{Kind: "aws:s3:bucket", Name: "logs", ForEach: "${replicas}"}})
+	assert.ErrorContains(t, err, "error expanding resource logs: error resolving for_each: for_each expression ${replicas} must evaluate to an array or map, got a number (3)")
+
+	_, err = parser.ExpandResources([]Resource{{Kind: "aws:s3:bucket", Name: "logs", ForEach: "${regions}"}})
+	assert.ErrorContains(t, err, "for_each expression ${regions} could not be evaluated")
+
+	_, err = parser.ExpandResources([]Resource{{Kind: "aws:s3:bucket", Name: "logs", ForEach: true}})
+	assert.ErrorContains(t, err, "for_each must be an array, map or expression, got a boolean")
+}
+
 func TestParser_evaluateExpression(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -372,3 +553,480 @@ func TestParser_evaluateExpression(t *testing.T) {
 		})
 	}
 }
+
+func TestParser_Parse_RejectsUnknownFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		yaml     string
+		contains string
+	}{
+		{
+			name: "misspelled top-level key",
+			yaml: `
+project: test-project
+environment: dev
+resouces:
+  - kind: aws:s3:bucket
+    name: logs
+`,
+			contains: "line 4: field resouces not found",
+		},
+		{
+			name: "unknown resource field",
+			yaml: `
+project: test-project
+environment: dev
+resources:
+  - kind: aws:s3:bucket
+    name: logs
+    propeties:
+      versioning: true
+`,
+			contains: "line 7: field propeties not found",
+		},
+		{
+			name: "unknown provider field",
+			yaml: `
+project: test-project
+environment: dev
+providers:
+  aws:
+    regoin: us-east-1
+resources: []
+`,
+			contains: "line 6: field regoin not found",
+		},
+		{
+			name: "unknown module field",
+			yaml: `
+project: test-project
+environment: dev
+modules:
+  network:
+    source: ./modules/network
+    input:
+      cidr: 10.0.0.0/16
+resources: []
+`,
+			contains: "line 7: field input not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewParser()
+			_, err := parser.Parse([]byte(tt.yaml))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.contains)
+		})
+	}
+}
+
+func TestParser_Parse_APIVersion(t *testing.T) {
+	parse := func(yaml string) (*Config, []string, error) {
+		var warnings []string
+		parser := NewParser()
+		parser.SetWarningHandler(func(message string) {
+			warnings = append(warnings, message)
+		})
+		config, err := parser.Parse([]byte(yaml))
+		return config, warnings, err
+	}
+
+	t.Run("supported version", func(t *testing.T) {
+		config, warnings, err := parse(`
+apiVersion: runestone/v1
+project: test-project
+environment: dev
+resources: []
+`)
+		require.NoError(t, err)
+		assert.Equal(t, "runestone/v1", config.APIVersion)
+		assert.Empty(t, warnings)
+	})
+
+	t.Run("missing version defaults with a deprecation warning", func(t *testing.T) {
+		config, warnings, err := parse(`
+project: test-project
+environment: dev
+resources: []
+`)
+		require.NoError(t, err)
+		assert.Equal(t, "runestone/v1", config.APIVersion)
+		require.Len(t, warnings, 1)
+		assert.Contains(t, warnings[0], "no apiVersion")
+		assert.Contains(t, warnings[0], "deprecated")
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		_, _, err := parse(`
+apiVersion: runestone/v2
+project: test-project
+environment: dev
+resources: []
+`)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unsupported apiVersion "runestone/v2"`)
+		assert.Contains(t, err.Error(), "upgrade runestone")
+	})
+}
+
+func TestParser_ExpandResources_ProviderAlias(t *testing.T) {
+	parser := NewParser()
+	config, err := parser.Parse([]byte(`
+apiVersion: runestone/v1
+project: test-project
+environment: dev
+providers:
+  aws:
+    region: us-east-1
+  aws.eu-west-1:
+    region: eu-west-1
+resources:
+  - kind: aws:s3:bucket
+    name: logs
+  - kind: aws:s3:bucket
+    name: logs-eu
+    provider: aws.eu-west-1
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "eu-west-1", config.Providers["aws.eu-west-1"].Region)
+
+	instances, err := parser.ExpandResources(config.Resources)
+	require.NoError(t, err)
+	require.Len(t, instances, 2)
+	assert.Empty(t, instances[0].Provider)
+	assert.Equal(t, "aws.eu-west-1", instances[1].Provider)
+}
+
+func TestParser_ExpandResources_Timeouts(t *testing.T) {
+	parser := NewParser()
+	config, err := parser.Parse([]byte(`
+apiVersion: runestone/v1
+project: test-project
+environment: dev
+variables:
+  db_create_timeout: 60m
+resources:
+  - kind: aws:rds:instance
+    name: db-${each.key}
+    for_each:
+      primary: 40m
+      replica: 20m
+    timeouts:
+      create: ${db_create_timeout}
+      update: ${each.value}
+  - kind: aws:s3:bucket
+    name: logs
+`))
+	require.NoError(t, err)
+
+	instances, err := parser.ExpandResources(config.Resources)
+	require.NoError(t, err)
+	require.Len(t, instances, 3)
+	assert.Equal(t, &Timeouts{Create: "60m", Update: "40m"}, instances[0].Timeouts)
+	assert.Equal(t, &Timeouts{Create: "60m", Update: "20m"}, instances[1].Timeouts, "Each instance should evaluate its own timeouts")
+	assert.Equal(t, time.Hour, instances[1].Timeouts.Timeout(ChangeTypeCreate))
+	assert.Zero(t, instances[1].Timeouts.Timeout(ChangeTypeDelete))
+	assert.Nil(t, instances[2].Timeouts)
+	assert.Zero(t, instances[2].Timeouts.Timeout(ChangeTypeCreate))
+
+	_, err = parser.ExpandResources([]Resource{{Kind: "aws:rds:instance", Name: "bad", Timeouts: &Timeouts{Create: "40 minutes"}}})
+	assert.ErrorContains(t, err, `invalid timeouts.create "40 minutes"`)
+
+	_, err = parser.ExpandResources([]Resource{{Kind: "aws:rds:instance", Name: "bad", Timeouts: &Timeouts{Delete: "0s"}}})
+	assert.ErrorContains(t, err, "timeouts.delete must be positive")
+}
+
+func TestParser_ExpandResources_Enabled(t *testing.T) {
+	parser := NewParser()
+	config, err := parser.Parse([]byte(`
+apiVersion: runestone/v1
+project: test-project
+environment: dev
+variables:
+  enable_cdn: false
+resources:
+  - kind: aws:s3:bucket
+    name: logs
+    enabled: true
+  - kind: aws:s3:bucket
+    name: cdn-logs
+    enabled: ${enable_cdn}
+  - kind: aws:s3:bucket
+    name: prod-backups
+    enabled: ${environment == "prod"}
+  - kind: aws:s3:bucket
+    name: replicas
+    enabled: false
+    count: 3
+`))
+	require.NoError(t, err)
+
+	instances, err := parser.ExpandResources(config.Resources)
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.Equal(t, "aws:s3:bucket.logs", instances[0].ID)
+
+	_, err = parser.ExpandResources([]Resource{{Kind: "aws:s3:bucket", Name: "bad", Enabled: "${project}"}})
+	assert.ErrorContains(t, err, "enabled expression must evaluate to a boolean")
+
+	_, err = parser.ExpandResources([]Resource{{Kind: "aws:s3:bucket", Name: "bad", Enabled: 1}})
+	assert.ErrorContains(t, err, "enabled must be a boolean or expression")
+}
+
+func TestParser_ExpandResources_PreservesResourceReferences(t *testing.T) {
+	parser := NewParser()
+	parser.variables = map[string]interface{}{"project": "myapp"}
+
+	instances, err := parser.ExpandResources([]Resource{
+		{
+			Kind: "aws:ec2:subnet",
+			Name: "public",
+			Properties: map[string]interface{}{
+				"vpc_id": "${aws:ec2:vpc.main.vpc_id}",
+				"name":   "${project}-${aws:ec2:vpc.main.vpc_id}",
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.Equal(t, "${aws:ec2:vpc.main.vpc_id}", instances[0].Properties["vpc_id"])
+	assert.Equal(t, "myapp-${aws:ec2:vpc.main.vpc_id}", instances[0].Properties["name"])
+}
+
+func TestParser_ExpandResources_Modules(t *testing.T) {
+	definition := &ModuleDefinition{
+		Variables: map[string]interface{}{"bucket_count": 1},
+		Resources: []Resource{
+			{
+				Kind:  "aws:s3:bucket",
+				Name:  "${module.name}-${count.index}",
+				Count: "${bucket_count}",
+				Properties: map[string]interface{}{
+					"tags": map[string]interface{}{"Environment": "${environment}"},
+				},
+			},
+		},
+	}
+
+	parser := NewParser()
+	cfg, err := parser.Parse([]byte(`
+project: test-project
+environment: dev
+resources:
+  - kind: aws:s3:bucket
+    name: root
+`))
+	require.NoError(t, err)
+
+	parser.AddModule("logs", definition, map[string]interface{}{"bucket_count": 2})
+	instances, err := parser.ExpandResources(cfg.Resources)
+	require.NoError(t, err)
+
+	ids := make([]string, 0, len(instances))
+	for _, instance := range instances {
+		ids = append(ids, instance.ID)
+	}
+	assert.Equal(t, []string{"aws:s3:bucket.root", "aws:s3:bucket.logs-0", "aws:s3:bucket.logs-1"}, ids)
+	assert.Equal(t, map[string]interface{}{"Environment": "dev"}, instances[1].Properties["tags"])
+
+	// A module resource colliding with a root resource is rejected
+	parser.AddModule("root", &ModuleDefinition{Resources: []Resource{{Kind: "aws:s3:bucket", Name: "root"}}}, nil)
+	_, err = parser.ExpandResources(cfg.Resources)
+	assert.ErrorContains(t, err, "module root: resource aws:s3:bucket.root is already defined in the root configuration")
+}
+
+func TestParser_ExpandResources_ModuleOutputs(t *testing.T) {
+	definition := &ModuleDefinition{
+		Variables: map[string]interface{}{
+			"subnet_cidrs": []interface{}{"10.0.1.0/24", "10.0.2.0/24"},
+		},
+		Resources: []Resource{
+			{
+				Kind:       "aws:ec2:vpc",
+				Name:       "${module.name}-vpc",
+				Properties: map[string]interface{}{"cidr_block": "10.0.0.0/16"},
+			},
+		},
+		Outputs: map[string]interface{}{
+			"vpc_id":       "${aws:ec2:vpc.network-vpc.vpc_id}",
+			"subnet_cidrs": "${subnet_cidrs}",
+			"names":        []interface{}{"${module.name}-a", "${module.name}-b"},
+		},
+	}
+
+	parser := NewParser()
+	cfg, err := parser.Parse([]byte(`
+project: test-project
+environment: dev
+resources:
+  - kind: aws:ec2:subnet
+    name: "app-${count.index}"
+    count: "${length(module.network.subnet_cidrs)}"
+    properties:
+      vpc_id: "${module.network.vpc_id}"
+      cidr_block: "${module.network.subnet_cidrs[count.index]}"
+  - kind: aws:s3:bucket
+    name: logs
+    properties:
+      owner: "${module.network.names[1]}"
+`))
+	require.NoError(t, err)
+
+	parser.AddModule("network", definition, nil)
+	instances, err := parser.ExpandResources(cfg.Resources)
+	require.NoError(t, err)
+	require.Len(t, instances, 4)
+
+	assert.Equal(t, "aws:ec2:subnet.app-0", instances[0].ID)
+	assert.Equal(t, "10.0.1.0/24", instances[0].Properties["cidr_block"])
+	assert.Equal(t, "${aws:ec2:vpc.network-vpc.vpc_id}", instances[0].Properties["vpc_id"])
+	assert.Equal(t, []string{"aws:ec2:vpc.network-vpc"}, instances[0].DependsOn)
+
+	assert.Equal(t, "aws:ec2:subnet.app-1", instances[1].ID)
+	assert.Equal(t, "10.0.2.0/24", instances[1].Properties["cidr_block"])
+	assert.Equal(t, []string{"aws:ec2:vpc.network-vpc"}, instances[1].DependsOn)
+
+	assert.Equal(t, "network-b", instances[2].Properties["owner"])
+	assert.Equal(t, "aws:ec2:vpc.network-vpc", instances[3].ID)
+}
+
+func TestParser_Parse_Outputs(t *testing.T) {
+	config, err := NewParser().Parse([]byte(`
+apiVersion: runestone/v1
+project: shop
+environment: prod
+outputs:
+  bucket_arn:
+    value: ${aws:s3:bucket.site.arn}
+    description: ARN of the site bucket
+  site_name:
+    value: ${project}-${environment}
+  db_password:
+    value: ${aws:rds:instance.db.password}
+    sensitive: true
+resources: []
+`))
+	require.NoError(t, err)
+
+	// Variables are substituted; resource references are resolved after a commit
+	assert.Equal(t, map[string]Output{
+		"bucket_arn":  {Value: "${aws:s3:bucket.site.arn}", Description: "ARN of the site bucket"},
+		"site_name":   {Value: "shop-prod"},
+		"db_password": {Value: "${aws:rds:instance.db.password}", Sensitive: true},
+	}, config.Outputs)
+}
+
+func TestParser_Parse_Notifications(t *testing.T) {
+	t.Setenv("DRIFT_WEBHOOK_URL", "https://hooks.example.com/drift")
+
+	config, err := NewParser().Parse([]byte(`
+apiVersion: runestone/v1
+project: shop
+environment: prod
+notifications:
+  webhook_url: ${env('DRIFT_WEBHOOK_URL')}
+  timeout: 5s
+resources: []
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, &Notifications{WebhookURL: "https://hooks.example.com/drift", Timeout: "5s"}, config.Notifications)
+}
+
+func TestParser_ExpandResources_DriftPolicyValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  DriftPolicy
+		wantErr string
+	}{
+		{name: "valid", policy: DriftPolicy{AutoHeal: true, HealDriftTypes: []string{"modified", "missing"}, MaxChanges: 3}},
+		{name: "unknown drift type", policy: DriftPolicy{HealDriftTypes: []string{"deleted"}}, wantErr: `invalid driftPolicy.heal_drift_types entry "deleted"`},
+		{name: "negative max changes", policy: DriftPolicy{MaxChanges: -1}, wantErr: "driftPolicy.max_changes must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := tt.policy
+			_, err := NewParser().ExpandResources([]Resource{{Kind: "aws:rds:instance", Name: "db", DriftPolicy: &policy}})
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParser_Parse_Moved(t *testing.T) {
+	config, err := NewParser().Parse([]byte(`
+project: shop
+environment: prod
+resources:
+  - kind: aws:ec2:instance
+    name: api
+moved:
+  - from: aws:ec2:instance.web
+    to: aws:ec2:instance.api
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, []Moved{{From: "aws:ec2:instance.web", To: "aws:ec2:instance.api"}}, config.Moved)
+}
+
+func TestValidateMoves(t *testing.T) {
+	instances := []ResourceInstance{
+		{ID: "aws:ec2:instance.api", Kind: "aws:ec2:instance", Name: "api"},
+		{ID: "aws:ec2:vpc.main", Kind: "aws:ec2:vpc", Name: "main"},
+	}
+
+	tests := []struct {
+		name    string
+		moves   []Moved
+		wantErr string
+	}{
+		{name: "valid", moves: []Moved{{From: "aws:ec2:instance.web", To: "aws:ec2:instance.api"}}},
+		{name: "not a resource ID", moves: []Moved{{From: "web", To: "aws:ec2:instance.api"}}, wantErr: "from and to must be resource IDs"},
+		{name: "kind changes", moves: []Moved{{From: "aws:ec2:instance.main", To: "aws:ec2:vpc.main"}}, wantErr: "a resource can't change kind"},
+		{name: "same ID", moves: []Moved{{From: "aws:ec2:instance.api", To: "aws:ec2:instance.api"}}, wantErr: "from and to are the same"},
+		{
+			name:    "moved twice",
+			moves:   []Moved{{From: "aws:ec2:instance.web", To: "aws:ec2:instance.api"}, {From: "aws:ec2:instance.web", To: "aws:ec2:instance.app"}},
+			wantErr: "aws:ec2:instance.web is moved more than once",
+		},
+		{
+			name:    "two resources moved to one",
+			moves:   []Moved{{From: "aws:ec2:instance.web", To: "aws:ec2:instance.api"}, {From: "aws:ec2:instance.app", To: "aws:ec2:instance.api"}},
+			wantErr: "more than one resource is moved to aws:ec2:instance.api",
+		},
+		{name: "still declared", moves: []Moved{{From: "aws:ec2:vpc.main", To: "aws:ec2:vpc.core"}}, wantErr: "aws:ec2:vpc.main is moved to aws:ec2:vpc.core but is still declared"},
+		{
+			name:    "chain",
+			moves:   []Moved{{From: "aws:ec2:instance.web", To: "aws:ec2:instance.app"}, {From: "aws:ec2:instance.app", To: "aws:ec2:instance.api"}},
+			wantErr: "which is moved again",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMoves(tt.moves, instances)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResourceInstance_Renamed(t *testing.T) {
+	instance := ResourceInstance{ID: "aws:ec2:instance.api", Kind: "aws:ec2:instance", Name: "api", Properties: map[string]interface{}{"instance_type": "t3.micro"}}
+
+	renamed := instance.Renamed("aws:ec2:instance.web")
+
+	assert.Equal(t, "aws:ec2:instance.web", renamed.ID)
+	assert.Equal(t, "web", renamed.Name)
+	assert.Equal(t, instance.Properties, renamed.Properties)
+	assert.Equal(t, "api", instance.Name)
+}
@@ -0,0 +1,161 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// secretNamePattern matches Secrets Manager secret names and ARNs
+var secretNamePattern = regexp.MustCompile(`^[A-Za-z0-9/_+=.@:-]+$`)
+
+// secretPattern matches the deferred form that secret() leaves in resource properties
+var secretPattern = regexp.MustCompile(`\$\{secret\("([A-Za-z0-9/_+=.@:-]+)"\)\}`)
+
+// SecretLookup returns the value of a named secret
+type SecretLookup func(name string) (string, error)
+
+// secretFunction defers reading a secret until commit, when provider credentials are
+// available. It returns a marker naming the secret, which the parser leaves in place and
+// ResolveSecrets later replaces with the secret's value.
+func secretFunction(signature string, params []interface{}) (interface{}, error) {
+	args, err := stringArgs(signature, params, 1)
+	if err != nil {
+		return nil, err
+	}
+	name := args[0]
+
+	if !secretNamePattern.MatchString(name) {
+		return nil, signatureError(signature, "invalid secret name %q", name)
+	}
+
+	return secretMarker(name), nil
+}
+
+// secretMarker returns the deferred form of a secret reference
+func secretMarker(name string) string {
+	return fmt.Sprintf(`${secret("%s")}`, name)
+}
+
+// FindSecrets returns the distinct secret names referenced in a set of properties, sorted
+func FindSecrets(properties map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	collectSecrets(properties, seen)
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func collectSecrets(value interface{}, seen map[string]bool) {
+	switch v := value.(type) {
+	case string:
+		for _, match := range secretPattern.FindAllStringSubmatch(v, -1) {
+			seen[match[1]] = true
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			collectSecrets(item, seen)
+		}
+	case []interface{}:
+		for _, item := range v {
+			collectSecrets(item, seen)
+		}
+	}
+}
+
+// SecretPaths returns the paths of the properties that reference a secret, each as the keys
+// leading to it. A list referencing a secret anywhere in it is returned as a whole, since its
+// items have no keys.
+func SecretPaths(properties map[string]interface{}) [][]string {
+	var paths [][]string
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if nested, ok := properties[key].(map[string]interface{}); ok {
+			for _, path := range SecretPaths(nested) {
+				paths = append(paths, append([]string{key}, path...))
+			}
+			continue
+		}
+
+		seen := make(map[string]bool)
+		collectSecrets(properties[key], seen)
+		if len(seen) > 0 {
+			paths = append(paths, []string{key})
+		}
+	}
+	return paths
+}
+
+// ResolveSecrets returns a copy of properties with every secret reference replaced by the
+// secret's value from lookup. Errors name the secret but never include its value.
+func ResolveSecrets(properties map[string]interface{}, lookup SecretLookup) (map[string]interface{}, error) {
+	resolved, err := resolveSecretsIn(properties, lookup)
+	if err != nil {
+		return nil, err
+	}
+	if resolved == nil {
+		return nil, nil
+	}
+	return resolved.(map[string]interface{}), nil
+}
+
+func resolveSecretsIn(value interface{}, lookup SecretLookup) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		if !strings.Contains(v, "${secret(") {
+			return v, nil
+		}
+		var lookupErr error
+		resolved := secretPattern.ReplaceAllStringFunc(v, func(marker string) string {
+			if lookupErr != nil {
+				return marker
+			}
+			name := secretPattern.FindStringSubmatch(marker)[1]
+			secret, err := lookup(name)
+			if err != nil {
+				lookupErr = fmt.Errorf("failed to resolve secret %s: %w", name, err)
+				return marker
+			}
+			return secret
+		})
+		if lookupErr != nil {
+			return nil, lookupErr
+		}
+		return resolved, nil
+	case map[string]interface{}:
+		if v == nil {
+			return nil, nil
+		}
+		resolved := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			resolvedItem, err := resolveSecretsIn(item, lookup)
+			if err != nil {
+				return nil, err
+			}
+			resolved[key] = resolvedItem
+		}
+		return resolved, nil
+	case []interface{}:
+		resolved := make([]interface{}, len(v))
+		for i, item := range v {
+			resolvedItem, err := resolveSecretsIn(item, lookup)
+			if err != nil {
+				return nil, err
+			}
+			resolved[i] = resolvedItem
+		}
+		return resolved, nil
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParser_SecretFunction_Deferred(t *testing.T) {
+	parser := NewParser()
+
+	cfg, err := parser.Parse([]byte(`
+project: test-project
+environment: dev
+resources:
+  - kind: aws:rds:instance
+    name: db
+    properties:
+      master_user_password: "${secret('prod/db/password')}"
+      connection: "admin:${secret('prod/db/password')}@db"
+`))
+	require.NoError(t, err)
+
+	instances, err := parser.ExpandResources(cfg.Resources)
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+
+	properties := instances[0].Properties
+	assert.Equal(t, `${secret("prod/db/password")}`, properties["master_user_password"])
+	assert.Equal(t, `admin:${secret("prod/db/password")}@db`, properties["connection"])
+	assert.Equal(t, []string{"prod/db/password"}, FindSecrets(properties))
+}
+
+func TestParser_SecretFunction_InvalidName(t *testing.T) {
+	parser := NewParser()
+
+	_, err := parser.evaluateExpression("${secret('prod db')}")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid secret name")
+}
+
+func TestResolveSecrets(t *testing.T) {
+	properties := map[string]interface{}{
+		"master_user_password": `${secret("prod/db/password")}`,
+		"environment":          map[string]interface{}{"API_KEY": `key-${secret("prod/api-key")}`},
+		"allocated_storage":    20,
+	}
+
+	var looked []string
+	resolved, err := ResolveSecrets(properties, func(name string) (string, error) {
+		looked = append(looked, name)
+		return "value-of-" + name, nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "value-of-prod/db/password", resolved["master_user_password"])
+	assert.Equal(t, map[string]interface{}{"API_KEY": "key-value-of-prod/api-key"}, resolved["environment"])
+	assert.Equal(t, 20, resolved["allocated_storage"])
+	assert.ElementsMatch(t, []string{"prod/db/password", "prod/api-key"}, looked)
+
+	// The original properties keep the deferred form
+	assert.Equal(t, `${secret("prod/db/password")}`, properties["master_user_password"])
+}
+
+func TestResolveSecrets_Error(t *testing.T) {
+	_, err := ResolveSecrets(map[string]interface{}{
+		"master_user_password": `${secret("prod/db/password")}`,
+	}, func(name string) (string, error) {
+		return "", errors.New("AccessDeniedException")
+	})
+	require.Error(t, err)
+	assert.Equal(t, "failed to resolve secret prod/db/password: AccessDeniedException", err.Error())
+}
+
+func TestSecretPaths(t *testing.T) {
+	assert.Equal(t, [][]string{
+		{"container_env"},
+		{"environment", "API_KEY"},
+		{"master_user_password"},
+	}, SecretPaths(map[string]interface{}{
+		"master_user_password": `${secret("prod/db/password")}`,
+		"environment":          map[string]interface{}{"API_KEY": `key-${secret("prod/api-key")}`, "STAGE": "prod"},
+		"container_env":        []interface{}{"PLAIN=1", `TOKEN=${secret("prod/token")}`},
+		"allocated_storage":    20,
+	}))
+
+	assert.Empty(t, SecretPaths(map[string]interface{}{"name": "app-db"}))
+}
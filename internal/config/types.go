@@ -1,24 +1,161 @@
 package config
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // Config represents the main Runestone configuration
 type Config struct {
-	Project   string                 `yaml:"project"`
-	Environment string               `yaml:"environment"`
-	Variables map[string]interface{} `yaml:"variables,omitempty"`
-	Providers map[string]Provider    `yaml:"providers"`
-	Modules   map[string]Module      `yaml:"modules,omitempty"`
-	Resources []Resource             `yaml:"resources"`
+	APIVersion    string                 `yaml:"apiVersion,omitempty"` // Configuration schema version, e.g. runestone/v1
+	Project       string                 `yaml:"project"`
+	Environment   string                 `yaml:"environment"`
+	Variables     map[string]interface{} `yaml:"variables,omitempty"`
+	Providers     map[string]Provider    `yaml:"providers"`
+	Modules       map[string]Module      `yaml:"modules,omitempty"`
+	Resources     []Resource             `yaml:"resources"`
+	Policy        *PolicyConfig          `yaml:"policy,omitempty"`
+	Exemptions    []PolicyExemption      `yaml:"exemptions,omitempty"`
+	Outputs       map[string]Output      `yaml:"outputs,omitempty"`
+	Notifications *Notifications         `yaml:"notifications,omitempty"`
+	Moved         []Moved                `yaml:"moved,omitempty"`
+}
+
+// Moved records that a resource now declared as To was declared as From, so that commit
+// renames the existing resource instead of replacing it
+type Moved struct {
+	From string `yaml:"from"` // Previous resource ID, e.g. aws:ec2:instance.web
+	To   string `yaml:"to"`   // Resource ID the resource is declared as now
+}
+
+// ValidateMoves checks that each moved block names two resource IDs of the same kind, that
+// no resource is moved twice or moved again, and that no resource is still declared under
+// the ID it was moved from. Moves to resources that aren't in instances are ignored, such
+// as those left out by --target.
+func ValidateMoves(moves []Moved, instances []ResourceInstance) error {
+	declared := make(map[string]bool, len(instances))
+	for _, instance := range instances {
+		declared[instance.ID] = true
+	}
+
+	from := make(map[string]bool, len(moves))
+	to := make(map[string]bool, len(moves))
+	for _, move := range moves {
+		fromKind, fromName, _ := strings.Cut(move.From, ".")
+		toKind, toName, _ := strings.Cut(move.To, ".")
+		if fromName == "" || toName == "" {
+			return fmt.Errorf("invalid moved block from %q to %q: from and to must be resource IDs such as aws:ec2:instance.web", move.From, move.To)
+		}
+		if fromKind != toKind {
+			return fmt.Errorf("invalid moved block from %s to %s: a resource can't change kind", move.From, move.To)
+		}
+		if move.From == move.To {
+			return fmt.Errorf("invalid moved block from %s: from and to are the same", move.From)
+		}
+		if from[move.From] {
+			return fmt.Errorf("%s is moved more than once", move.From)
+		}
+		if to[move.To] {
+			return fmt.Errorf("more than one resource is moved to %s", move.To)
+		}
+		from[move.From] = true
+		to[move.To] = true
+
+		if declared[move.From] {
+			return fmt.Errorf("%s is moved to %s but is still declared; remove the resource or the moved block", move.From, move.To)
+		}
+	}
+
+	// A chain such as a -> b -> c would rename b before it exists
+	for _, move := range moves {
+		if from[move.To] {
+			return fmt.Errorf("%s is moved to %s, which is moved again; move it straight to the final ID", move.From, move.To)
+		}
+	}
+
+	return nil
+}
+
+// Notifications configures where align reports drift it hasn't healed
+type Notifications struct {
+	WebhookURL string `yaml:"webhook_url,omitempty"` // Receives a JSON POST for each alignment pass that finds drift
+	Timeout    string `yaml:"timeout,omitempty"`     // How long to wait for the webhook (default: 10s)
+}
+
+// Output declares a value reported after a commit, usually an attribute of a resource
+// such as ${aws:s3:bucket.site.arn}
+type Output struct {
+	Value       interface{} `yaml:"value"`
+	Description string      `yaml:"description,omitempty"`
+	Sensitive   bool        `yaml:"sensitive,omitempty"` // Hide the value unless explicitly asked for
+}
+
+// PolicyConfig selects the policy engine
+type PolicyConfig struct {
+	Engine  string   `yaml:"engine,omitempty"`  // builtin (default) or rego
+	Files   []string `yaml:"files,omitempty"`   // Rego files or directories, for the rego engine
+	Package string   `yaml:"package,omitempty"` // Rego package to query (default: runestone)
+}
+
+// PolicyExemption waives a policy rule for a single resource
+type PolicyExemption struct {
+	Resource string `yaml:"resource"`          // Resource ID, e.g. aws:s3:bucket.site
+	Rule     string `yaml:"rule"`              // Policy rule name
+	Expires  string `yaml:"expires,omitempty"` // Date (YYYY-MM-DD) or RFC 3339 time after which the waiver no longer applies
+	Reason   string `yaml:"reason,omitempty"`
 }
 
 // Provider represents a cloud provider configuration
 type Provider struct {
-	Region  string `yaml:"region,omitempty"`
-	Profile string `yaml:"profile,omitempty"`
+	Region             string            `yaml:"region,omitempty"`
+	Profile            string            `yaml:"profile,omitempty"`
+	DefaultTags        map[string]string `yaml:"default_tags,omitempty"`         // Tags applied to every resource
+	MaxRetries         *int              `yaml:"max_retries,omitempty"`          // Retries for failed API calls
+	BaseDelayMs        *int              `yaml:"base_delay_ms,omitempty"`        // Initial retry backoff in milliseconds
+	DefaultWaitTimeout string            `yaml:"default_wait_timeout,omitempty"` // How long to wait for resources to become ready, e.g. 30m
+	IgnoreTagPrefixes  []string          `yaml:"ignore_tag_prefixes,omitempty"`  // Tag key prefixes drift detection ignores; [] for none
+	EndpointURL        string            `yaml:"endpoint_url,omitempty"`         // Send every API call here instead, e.g. a LocalStack container
+	AssumeRoleARN      string            `yaml:"assume_role_arn,omitempty"`      // Role to assume, e.g. in another account
+	ExternalID         string            `yaml:"external_id,omitempty"`          // External ID the role's trust policy requires
+	SessionName        string            `yaml:"session_name,omitempty"`         // Session name for the assumed role (default: runestone)
 	// Additional provider-specific fields can be added here
 }
 
+// Settings returns the provider configuration as the map passed to Provider.Initialize
+func (p Provider) Settings() map[string]interface{} {
+	settings := map[string]interface{}{
+		"region":       p.Region,
+		"profile":      p.Profile,
+		"default_tags": p.DefaultTags,
+	}
+	if p.MaxRetries != nil {
+		settings["max_retries"] = *p.MaxRetries
+	}
+	if p.BaseDelayMs != nil {
+		settings["base_delay_ms"] = *p.BaseDelayMs
+	}
+	if p.DefaultWaitTimeout != "" {
+		settings["default_wait_timeout"] = p.DefaultWaitTimeout
+	}
+	if p.IgnoreTagPrefixes != nil {
+		settings["ignore_tag_prefixes"] = p.IgnoreTagPrefixes
+	}
+	if p.EndpointURL != "" {
+		settings["endpoint_url"] = p.EndpointURL
+	}
+	if p.AssumeRoleARN != "" {
+		settings["assume_role_arn"] = p.AssumeRoleARN
+	}
+	if p.ExternalID != "" {
+		settings["external_id"] = p.ExternalID
+	}
+	if p.SessionName != "" {
+		settings["session_name"] = p.SessionName
+	}
+	return settings
+}
+
 // Module represents a reusable module
 type Module struct {
 	Source  string                 `yaml:"source"`
@@ -26,21 +163,131 @@ type Module struct {
 	Inputs  map[string]interface{} `yaml:"inputs,omitempty"`
 }
 
+// ModuleDefinition is the content of a module's YAML files
+type ModuleDefinition struct {
+	Variables map[string]interface{} `yaml:"variables,omitempty"` // Input defaults
+	Resources []Resource             `yaml:"resources"`
+	Outputs   map[string]interface{} `yaml:"outputs,omitempty"` // Values exposed as ${module.<name>.<output>}
+}
+
 // Resource represents an infrastructure resource
 type Resource struct {
 	Kind        string                 `yaml:"kind"`
 	Name        string                 `yaml:"name"`
+	Enabled     interface{}            `yaml:"enabled,omitempty"`     // Can be bool or expression; false expands to no instances
 	Count       interface{}            `yaml:"count,omitempty"`       // Can be int or expression
 	ForEach     interface{}            `yaml:"for_each,omitempty"`    // Can be array or expression
 	Properties  map[string]interface{} `yaml:"properties,omitempty"`
 	DriftPolicy *DriftPolicy           `yaml:"driftPolicy,omitempty"`
 	DependsOn   []string               `yaml:"depends_on,omitempty"`
+	// Provider selects an aliased provider configuration, such as aws.eu-west-1, instead
+	// of the default one for the resource's kind
+	Provider string `yaml:"provider,omitempty"`
+	// Sensitive lists property names whose values are redacted in output, in addition
+	// to those the provider declares sensitive
+	Sensitive []string `yaml:"sensitive,omitempty"`
+	// Timeouts bounds how long creating, updating or deleting the resource may take
+	Timeouts *Timeouts `yaml:"timeouts,omitempty"`
+}
+
+// Timeouts holds a duration, such as "40m", for each operation on a resource. Operations
+// without one are bounded only by the provider's own wait timeouts and --timeout.
+type Timeouts struct {
+	Create string `yaml:"create,omitempty" json:"create,omitempty"`
+	Update string `yaml:"update,omitempty" json:"update,omitempty"`
+	Delete string `yaml:"delete,omitempty" json:"delete,omitempty"`
+}
+
+// Timeout returns the timeout for a change, or zero when none is set. Timeouts are
+// validated when resources are expanded, so a value that doesn't parse is treated as unset.
+func (t *Timeouts) Timeout(change ChangeType) time.Duration {
+	if t == nil {
+		return 0
+	}
+	timeout, _ := time.ParseDuration(t.value(change))
+	return timeout
+}
+
+// value returns the configured duration string for a change
+func (t *Timeouts) value(change ChangeType) string {
+	switch change {
+	case ChangeTypeCreate:
+		return t.Create
+	case ChangeTypeUpdate:
+		return t.Update
+	case ChangeTypeDelete:
+		return t.Delete
+	default:
+		return ""
+	}
+}
+
+// validate checks that every configured timeout is a positive duration
+func (t *Timeouts) validate() error {
+	for _, change := range []ChangeType{ChangeTypeCreate, ChangeTypeUpdate, ChangeTypeDelete} {
+		value := t.value(change)
+		if value == "" {
+			continue
+		}
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid timeouts.%s %q: expected a duration such as 30m", change, value)
+		}
+		if timeout <= 0 {
+			return fmt.Errorf("timeouts.%s must be positive", change)
+		}
+	}
+	return nil
 }
 
 // DriftPolicy defines how to handle drift for a resource
 type DriftPolicy struct {
 	AutoHeal   bool `yaml:"autoHeal"`
 	NotifyOnly bool `yaml:"notifyOnly"`
+	// IgnoreChanges lists property paths whose changes aren't drift; nested map keys are
+	// separated by dots, e.g. tags.LastModifiedBy
+	IgnoreChanges []string `yaml:"ignore_changes,omitempty"`
+	// HealDriftTypes limits auto-heal to drift of these types: added, removed, modified or
+	// missing, a resource that no longer exists. Empty heals every type.
+	HealDriftTypes []string `yaml:"heal_drift_types,omitempty"`
+	// MaxChanges refuses auto-heal when more properties than this differ; 0 is no limit
+	MaxChanges int `yaml:"max_changes,omitempty"`
+}
+
+// DriftTypeMissing is the heal_drift_types entry for a resource that no longer exists
+const DriftTypeMissing = "missing"
+
+// healDriftTypes are the values heal_drift_types accepts
+var healDriftTypes = []string{"added", "removed", "modified", DriftTypeMissing}
+
+// Heals reports whether auto-heal may fix drift of driftType
+func (p *DriftPolicy) Heals(driftType string) bool {
+	if len(p.HealDriftTypes) == 0 {
+		return true
+	}
+	for _, healed := range p.HealDriftTypes {
+		if healed == driftType {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks heal_drift_types and max_changes
+func (p *DriftPolicy) validate() error {
+	for _, driftType := range p.HealDriftTypes {
+		valid := false
+		for _, known := range healDriftTypes {
+			valid = valid || driftType == known
+		}
+		if !valid {
+			return fmt.Errorf("invalid driftPolicy.heal_drift_types entry %q: expected one of %s", driftType, strings.Join(healDriftTypes, ", "))
+		}
+	}
+	if p.MaxChanges < 0 {
+		return fmt.Errorf("driftPolicy.max_changes must not be negative")
+	}
+	return nil
 }
 
 // ResourceInstance represents an expanded resource instance
@@ -51,6 +298,18 @@ type ResourceInstance struct {
 	Properties map[string]interface{}
 	DriftPolicy *DriftPolicy
 	DependsOn  []string
+	Sensitive  []string
+	Provider   string // Aliased provider configuration, if the resource selects one
+	Timeouts   *Timeouts
+}
+
+// Renamed returns a copy of the instance identified as id instead, such as the resource ID
+// a moved block moves it from
+func (i ResourceInstance) Renamed(id string) ResourceInstance {
+	_, name, _ := strings.Cut(id, ".")
+	i.ID = id
+	i.Name = name
+	return i
 }
 
 // ChangeType represents the type of change to be made
@@ -71,6 +330,8 @@ type Change struct {
 	Properties   map[string]interface{}
 	OldValues    map[string]interface{} // For updates
 	NewValues    map[string]interface{} // For updates
+	MovedFrom    string                 // Previous resource ID, for updates that rename the resource
+	Simulated    bool                   // Planned by a dry run and not applied
 }
 
 // ChangeSummary represents a summary of planned changes
@@ -83,8 +344,13 @@ type ChangeSummary struct {
 
 // ExecutionResult represents the result of executing changes
 type ExecutionResult struct {
-	Success   bool
-	Duration  time.Duration
-	Changes   []Change
-	Errors    []error
+	Success    bool
+	Duration   time.Duration            // Time taken to walk the whole execution DAG
+	Durations  map[string]time.Duration // Time taken to apply each resource that was started
+	Changes    []Change
+	Errors     []error
+	Retries    map[string]int                    // Retries made per resource, for resources that needed any
+	RolledBack []string                          // Created resources deleted again after a failure
+	Skipped    []string                          // Resources not applied because a dependency failed
+	States     map[string]map[string]interface{} // State of existing and applied resources, for evaluating outputs
 }
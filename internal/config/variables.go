@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadVariableFile reads a YAML file of top-level variable definitions
+func LoadVariableFile(filename string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read variable file: %w", err)
+	}
+
+	variables := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &variables); err != nil {
+		return nil, fmt.Errorf("failed to parse variable file %s: %w", filename, err)
+	}
+
+	return variables, nil
+}
+
+// ParseVariableOverride parses a key=value override. The value is decoded as YAML so
+// numbers, booleans and lists keep their types.
+func ParseVariableOverride(override string) (string, interface{}, error) {
+	key, raw, found := strings.Cut(override, "=")
+	key = strings.TrimSpace(key)
+	if !found || key == "" {
+		return "", nil, fmt.Errorf("invalid variable override %q: expected key=value", override)
+	}
+
+	if strings.TrimSpace(raw) == "" {
+		return key, "", nil
+	}
+
+	var value interface{}
+	if err := yaml.Unmarshal([]byte(raw), &value); err != nil {
+		return "", nil, fmt.Errorf("invalid value for variable %s: %w", key, err)
+	}
+
+	return key, value, nil
+}
+
+// MergeVariables returns base with overrides applied on top. Where both define a map for
+// the same variable the maps are merged recursively, so an override only needs the keys it
+// changes; any other value, including a list, replaces the base value. Neither input is
+// modified.
+func MergeVariables(base, overrides map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(overrides))
+	for name, value := range base {
+		merged[name] = value
+	}
+	for name, value := range overrides {
+		baseMap, baseIsMap := merged[name].(map[string]interface{})
+		overrideMap, overrideIsMap := value.(map[string]interface{})
+		if baseIsMap && overrideIsMap {
+			merged[name] = MergeVariables(baseMap, overrideMap)
+			continue
+		}
+		merged[name] = value
+	}
+	return merged
+}
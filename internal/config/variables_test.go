@@ -0,0 +1,165 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVariableOverride(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantKey   string
+		wantValue interface{}
+		wantErr   bool
+	}{
+		{name: "string", input: "region=us-east-1", wantKey: "region", wantValue: "us-east-1"},
+		{name: "number", input: "instance_count=3", wantKey: "instance_count", wantValue: 3},
+		{name: "bool", input: "versioning=true", wantKey: "versioning", wantValue: true},
+		{name: "list", input: "regions=[us-east-1, eu-west-1]", wantKey: "regions", wantValue: []interface{}{"us-east-1", "eu-west-1"}},
+		{name: "value containing equals", input: "query=a=b", wantKey: "query", wantValue: "a=b"},
+		{name: "empty value", input: "suffix=", wantKey: "suffix", wantValue: ""},
+		{name: "missing equals", input: "region", wantErr: true},
+		{name: "missing key", input: "=value", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, value, err := ParseVariableOverride(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantKey, key)
+			assert.Equal(t, tt.wantValue, value)
+		})
+	}
+}
+
+func TestLoadVariableFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vars.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("region: eu-west-1\ninstance_count: 2\n"), 0600))
+
+	variables, err := LoadVariableFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"region": "eu-west-1", "instance_count": 2}, variables)
+
+	_, err = LoadVariableFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err)
+}
+
+func TestParser_SetVariableOverrides(t *testing.T) {
+	parser := NewParser()
+	parser.SetVariableOverrides(map[string]interface{}{"region": "eu-west-1"})
+
+	cfg, err := parser.Parse([]byte(`
+project: test-project
+environment: dev
+variables:
+  region: us-east-1
+  owner: platform-team
+providers:
+  aws:
+    region: "${region}"
+resources: []
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "eu-west-1", cfg.Providers["aws"].Region)
+	assert.Equal(t, "platform-team", parser.variables["owner"])
+}
+
+func TestMergeVariables(t *testing.T) {
+	base := map[string]interface{}{
+		"region": "us-east-1",
+		"tags": map[string]interface{}{
+			"team":  "platform",
+			"stage": "dev",
+		},
+		"sizing": map[string]interface{}{
+			"web": map[string]interface{}{"count": 1, "type": "t3.micro"},
+		},
+		"zones": []interface{}{"a", "b"},
+	}
+	overrides := map[string]interface{}{
+		"tags": map[string]interface{}{"stage": "prod"},
+		"sizing": map[string]interface{}{
+			"web": map[string]interface{}{"count": 3},
+		},
+		"zones": []interface{}{"c"},
+		"owner": "ops",
+	}
+
+	merged := MergeVariables(base, overrides)
+	assert.Equal(t, map[string]interface{}{
+		"region": "us-east-1",
+		"tags": map[string]interface{}{
+			"team":  "platform",
+			"stage": "prod",
+		},
+		"sizing": map[string]interface{}{
+			"web": map[string]interface{}{"count": 3, "type": "t3.micro"},
+		},
+		"zones": []interface{}{"c"},
+		"owner": "ops",
+	}, merged)
+
+	// The inputs are left untouched
+	assert.Equal(t, "dev", base["tags"].(map[string]interface{})["stage"])
+	assert.NotContains(t, base, "owner")
+
+	// A scalar replaces a map and a map replaces a scalar
+	merged = MergeVariables(
+		map[string]interface{}{"a": map[string]interface{}{"x": 1}, "b": "flat"},
+		map[string]interface{}{"a": "flat", "b": map[string]interface{}{"y": 2}},
+	)
+	assert.Equal(t, map[string]interface{}{"a": "flat", "b": map[string]interface{}{"y": 2}}, merged)
+}
+
+func TestParser_SetVariableOverrides_NestedMaps(t *testing.T) {
+	dir := t.TempDir()
+	basePath := filepath.Join(dir, "base.yaml")
+	prodPath := filepath.Join(dir, "prod.yaml")
+	require.NoError(t, os.WriteFile(basePath, []byte("tags:\n  team: platform\n  stage: dev\nsizing:\n  count: 1\n"), 0600))
+	require.NoError(t, os.WriteFile(prodPath, []byte("tags:\n  stage: prod\n"), 0600))
+
+	overrides := make(map[string]interface{})
+	for _, path := range []string{basePath, prodPath} {
+		fileVars, err := LoadVariableFile(path)
+		require.NoError(t, err)
+		overrides = MergeVariables(overrides, fileVars)
+	}
+
+	parser := NewParser()
+	parser.SetVariableOverrides(overrides)
+	cfg, err := parser.Parse([]byte(`
+project: test-project
+environment: prod
+variables:
+  tags:
+    owner: ops
+  sizing:
+    count: 2
+    type: t3.small
+resources:
+  - kind: aws:s3:bucket
+    name: logs
+    properties:
+      tags: "${tags}"
+      count: "${sizing.count}"
+      type: "${sizing.type}"
+`))
+	require.NoError(t, err)
+
+	instances, err := parser.ExpandResources(cfg.Resources)
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.Equal(t, map[string]interface{}{"team": "platform", "stage": "prod", "owner": "ops"}, instances[0].Properties["tags"])
+	assert.Equal(t, 1, instances[0].Properties["count"])
+	assert.Equal(t, "t3.small", instances[0].Properties["type"])
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CurrentAPIVersion is the configuration schema version this release reads and documents
+const CurrentAPIVersion = "runestone/v1"
+
+// supportedAPIVersions lists the apiVersion values this release can parse, oldest first
+var supportedAPIVersions = []string{CurrentAPIVersion}
+
+// checkAPIVersion validates the configuration's apiVersion. A missing version is treated
+// as the oldest supported one and returns a deprecation warning.
+func checkAPIVersion(config *Config) (warning string, err error) {
+	if config.APIVersion == "" {
+		config.APIVersion = supportedAPIVersions[0]
+		return fmt.Sprintf("configuration has no apiVersion; assuming %s. Omitting apiVersion is deprecated, add \"apiVersion: %s\" to the configuration", config.APIVersion, CurrentAPIVersion), nil
+	}
+
+	for _, version := range supportedAPIVersions {
+		if config.APIVersion == version {
+			return "", nil
+		}
+	}
+
+	return "", fmt.Errorf("unsupported apiVersion %q: this release of runestone supports %s; upgrade runestone to use a newer configuration version, or migrate the configuration to %s",
+		config.APIVersion, strings.Join(supportedAPIVersions, ", "), CurrentAPIVersion)
+}
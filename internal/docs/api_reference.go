@@ -20,6 +20,10 @@ runestone bootstrap [flags]
 
 **Flags:**
 - ` + "`-c, --config string`" + ` - Path to configuration file (default: "infra.yaml")
+- ` + "`--var-file stringArray`" + ` - Load variables from a YAML file (repeatable, later files win)
+- ` + "`--var stringArray`" + ` - Set a variable as key=value; the value is parsed as YAML (repeatable)
+- ` + "`--policy-file string`" + ` - Load additional policy rules from a YAML file
+- ` + "`-o, --output string`" + ` - Output format: human, json, markdown, yaml or junit (default: "human")
 - ` + "`-h, --help`" + ` - Help for bootstrap
 
 **Example:**
@@ -27,6 +31,63 @@ runestone bootstrap [flags]
 runestone bootstrap --config my-infra.yaml
 ` + "```" + `
 
+### ` + "`runestone validate`" + `
+
+Validates configuration offline: parses it, expands resources and modules, validates each
+resource against its provider and evaluates policies. Providers are never initialized, so no
+cloud credentials are needed, which makes it suitable for CI checks on pull requests. Their
+settings are still read, so checks such as an S3 bucket's region against the AWS provider's
+region run as well.
+
+` + "```bash" + `
+runestone validate [flags]
+` + "```" + `
+
+**Flags:**
+- ` + "`-c, --config string`" + ` - Path to configuration file (default: "infra.yaml")
+- ` + "`-o, --output string`" + ` - Output format: human, json, markdown, yaml or junit (default: "human")
+- ` + "`--var-file stringArray`" + ` - Load variables from a YAML file (repeatable, later files win)
+- ` + "`--var stringArray`" + ` - Set a variable as key=value; the value is parsed as YAML (repeatable)
+- ` + "`--policy-file string`" + ` - Load additional policy rules from a YAML file
+- ` + "`-h, --help`" + ` - Help for validate
+
+**Example:**
+` + "```bash" + `
+runestone validate --config infra.yaml --output json
+` + "```" + `
+
+### ` + "`runestone lint`" + `
+
+Warns about configuration that is valid but risky, offline and without cloud credentials:
+
+- ` + "`lint-missing-tags`" + ` - AWS resources without tags, counting the provider's ` + "`default_tags`" + `
+- ` + "`lint-hardcoded-secret`" + ` - Properties named like credentials (` + "`password`" + `, ` + "`secret`" + `, ` + "`token`" + `,
+  ` + "`api_key`" + `, ...) whose value is written literally instead of using ` + "`${secret(...)}`" + ` or
+  ` + "`${env(...)}`" + `
+- ` + "`lint-ssh-open-to-world`" + ` - Security groups with an ` + "`ingress`" + ` rule allowing port 22 from
+  ` + "`0.0.0.0/0`" + `
+- ` + "`lint-missing-drift-policy`" + ` - Resources without a ` + "`driftPolicy`" + `
+
+Each issue names the resource and includes a remediation hint (` + "`remediation`" + ` in JSON and
+YAML). All lint rules are warnings, so ` + "`lint`" + ` exits with code 0 unless the configuration
+can't be parsed. Exemptions waive lint rules like policy rules.
+
+` + "```bash" + `
+runestone lint [flags]
+` + "```" + `
+
+**Flags:**
+- ` + "`-c, --config string`" + ` - Path to configuration file (default: "infra.yaml")
+- ` + "`-o, --output string`" + ` - Output format: human, json, markdown, yaml or junit (default: "human")
+- ` + "`--var-file stringArray`" + ` - Load variables from a YAML file (repeatable, later files win)
+- ` + "`--var stringArray`" + ` - Set a variable as key=value; the value is parsed as YAML (repeatable)
+- ` + "`-h, --help`" + ` - Help for lint
+
+**Example:**
+` + "```bash" + `
+runestone lint --config infra.yaml
+` + "```" + `
+
 ### ` + "`runestone preview`" + `
 
 Shows what changes would be made without applying them.
@@ -38,6 +99,12 @@ runestone preview [flags]
 **Flags:**
 - ` + "`-c, --config string`" + ` - Path to configuration file (default: "infra.yaml")
 - ` + "`--json`" + ` - Output results in JSON format
+- ` + "`--target stringArray`" + ` - Limit the preview to a resource ID and its dependencies (repeatable)
+- ` + "`--out string`" + ` - Write the computed plan to a file for use with 'commit --plan'
+- ` + "`--var-file stringArray`" + ` - Load variables from a YAML file (repeatable, later files win)
+- ` + "`--var stringArray`" + ` - Set a variable as key=value; the value is parsed as YAML (repeatable)
+- ` + "`--policy-file string`" + ` - Load additional policy rules from a YAML file
+- ` + "`-o, --output string`" + ` - Output format: human, json, markdown, yaml or junit (default: "human")
 - ` + "`-h, --help`" + ` - Help for preview
 
 **Example:**
@@ -49,6 +116,52 @@ runestone preview --json > changes.json
 
 Applies infrastructure changes.
 
+Resources are applied in dependency order, each starting as soon as its dependencies have
+been applied. When a resource fails, the resources that depend on it are skipped and
+reported separately from failures.
+
+With ` + "`--events-format ndjson`" + `, commit writes one JSON object to standard error each time a
+resource starts, completes, fails or is skipped because a dependency failed, so GUIs and CI
+annotators can follow progress without parsing the human output. Standard output is
+unchanged. Each event has a ` + "`type`" + ` (` + "`node_started`" + `, ` + "`node_completed`" + `, ` + "`node_failed`" + ` or
+` + "`node_skipped`" + `), the resource's ` + "`node_id`" + ` and a ` + "`time`" + `; completed and failed resources
+include ` + "`duration_seconds`" + `, and failed and skipped ones an ` + "`error`" + `:
+
+` + "```json" + `
+{"type":"node_started","node_id":"aws:s3:bucket.logs","time":"2025-01-15T10:30:00.123Z"}
+{"type":"node_completed","node_id":"aws:s3:bucket.logs","time":"2025-01-15T10:30:02.456Z","duration_seconds":2.333}
+` + "```" + `
+
+A plan records the live state of every resource it was computed against. By default
+` + "`commit --plan`" + ` reads that state again and refuses the plan if anything has changed since;
+` + "`--refresh=false`" + ` skips those reads and trusts the recorded state, which saves an API call
+per resource on large configurations. Use it only when nothing else changes the
+infrastructure between ` + "`preview`" + ` and ` + "`commit`" + `: a resource changed or deleted in the
+meantime is updated as planned, or fails to update. Without ` + "`--plan`" + ` there is no recorded
+state to decide between creating and updating a resource, so ` + "`--refresh=false`" + ` is an error.
+Commit never deletes resources missing from the configuration, so skipping the refresh can't
+cause a resource to be removed.
+
+Each resource is timed from the moment it starts to the moment its provider call returns,
+and the whole DAG walk is timed as well. Human output shows the time beside each applied
+resource; the other output formats list, for each execution level, the resources that ran,
+the level's duration and each resource's duration (` + "`resource_durations_seconds`" + ` in JSON and
+YAML, the test case ` + "`time`" + ` in JUnit).
+
+After applying, commit evaluates the configuration's ` + "`outputs`" + ` and prints them, or includes
+them as an ` + "`outputs`" + ` object with ` + "`-o json`" + ` and ` + "`-o yaml`" + `:
+
+` + "```json" + `
+{
+  "success": true,
+  "resources_applied": 1,
+  "outputs": {
+    "bucket_arn": "arn:aws:s3:::site-bucket",
+    "db_password": "***"
+  }
+}
+` + "```" + `
+
 ` + "```bash" + `
 runestone commit [flags]
 ` + "```" + `
@@ -57,13 +170,48 @@ runestone commit [flags]
 - ` + "`-c, --config string`" + ` - Path to configuration file (default: "infra.yaml")
 - ` + "`--auto-approve`" + ` - Skip interactive approval
 - ` + "`--graph`" + ` - Show DAG visualization during execution
+- ` + "`--parallelism int`" + ` - Maximum number of resources processed concurrently (default: 10)
+- ` + "`--target stringArray`" + ` - Limit the commit to a resource ID and its dependencies (repeatable)
+- ` + "`--plan string`" + ` - Apply a plan file written by 'preview --out' instead of recomputing changes
+- ` + "`--events-format string`" + ` - Stream progress events to standard error while applying: ndjson (one JSON object per line)
+- ` + "`--refresh`" + ` - Read live state before applying (default: true). ` + "`--refresh=false`" + ` requires ` + "`--plan`" + ` and applies it against the state the plan recorded
+- ` + "`--var-file stringArray`" + ` - Load variables from a YAML file (repeatable, later files win)
+- ` + "`--var stringArray`" + ` - Set a variable as key=value; the value is parsed as YAML (repeatable)
+- ` + "`-o, --output string`" + ` - Output format: human, json, markdown, yaml or junit (default: "human")
+- ` + "`--dry-run`" + ` - Walk the execution DAG and report each change without applying it
+- ` + "`--max-retries int`" + ` - Retries for a resource whose create or update fails with a transient error, such as throttling the provider hasn't already retried (default: 3)
+- ` + "`--retry-delay duration`" + ` - Initial delay before retrying a resource, doubled on each retry (default: 1s)
+- ` + "`--rollback-on-failure`" + ` - Delete the resources this commit created if any resource fails; updates are kept
+- ` + "`--graph-format string`" + ` - Graph format: text (execution levels, shown before applying) or dot (Graphviz, colored by outcome after applying) (default: "text")
+- ` + "`--graph-out string`" + ` - Write the DOT graph to a file instead of standard output
+- ` + "`--show-sensitive`" + ` - Show the values of outputs marked sensitive instead of redacting them
+- ` + "`--continue`" + ` - Resume a failed commit, skipping the resources its journal records as completed
+- ` + "`--journal string`" + ` - Path of the journal recording the resources a commit has completed (default: ".runestone-journal.json")
 - ` + "`-h, --help`" + ` - Help for commit
 
 **Example:**
 ` + "```bash" + `
 runestone commit --auto-approve --graph
+
+# Exercise the DAG ordering and provider dispatch without changing anything
+runestone commit --dry-run --graph
+
+# Apply a reviewed plan without reading every resource's state again
+runestone preview --out plan.json
+runestone commit --plan plan.json --refresh=false --auto-approve
+
+# Render the dependency graph, colored by outcome
+runestone commit --auto-approve --graph-format dot --graph-out graph.dot
+dot -Tpng graph.dot -o graph.png
+
+# Resume a commit that failed part way
+runestone commit --continue --auto-approve
 ` + "```" + `
 
+Runestone keeps no state of its own, so running ` + "`commit`" + ` again is how a failed commit is recovered. When a create finds that the resource already exists in the account, as it does when an earlier attempt created it but failed to finish configuring it, the existing resource is updated to match the configuration instead. This applies to S3 buckets, IAM users and roles, CloudWatch log groups and ECR repositories; an S3 bucket name taken by another account is still an error.
+
+While it runs, ` + "`commit`" + ` records each resource it completes in a journal, ` + "`.runestone-journal.json`" + ` unless ` + "`--journal`" + ` says otherwise, and removes the journal once the commit succeeds. The journal is only written once a resource has been applied, so a commit with nothing to change leaves none behind. The journal is JSON keyed by resource ID, holding a hash of the configuration each resource was applied from. ` + "`commit --continue`" + ` reads it and skips the resources it records whose configuration hash still matches and which still exist, so completed work that may not be safe to repeat isn't applied twice, even if drift detection reports differences for it; everything else is applied as usual. ` + "`--continue`" + ` needs live state, so it can't be combined with ` + "`--refresh=false`" + `.
+
 ### ` + "`runestone align`" + `
 
 Monitors and fixes infrastructure drift.
@@ -76,6 +224,11 @@ runestone align [flags]
 - ` + "`-c, --config string`" + ` - Path to configuration file (default: "infra.yaml")
 - ` + "`--once`" + ` - Run alignment once instead of continuously
 - ` + "`--interval duration`" + ` - Interval between checks (default: 5m0s)
+- ` + "`--var-file stringArray`" + ` - Load variables from a YAML file (repeatable, later files win)
+- ` + "`--var stringArray`" + ` - Set a variable as key=value; the value is parsed as YAML (repeatable)
+- ` + "`-o, --output string`" + ` - Output format: human, json, markdown, yaml or junit (default: "human")
+- ` + "`--max-iterations int`" + ` - Stop after this many alignment passes (0 runs until interrupted)
+- ` + "`--report-only`" + ` - Report drift for every resource once without healing anything (implies ` + "`--once`" + `)
 - ` + "`-h, --help`" + ` - Help for align
 
 **Example:**
@@ -85,8 +238,29 @@ runestone align --once
 
 # Continuous monitoring every 10 minutes
 runestone align --interval 10m
+
+# Read-only drift audit for CI
+runestone align --report-only -o json
 ` + "```" + `
 
+In continuous mode, SIGINT or SIGTERM stops ` + "`align`" + ` once the current pass has finished and
+prints the number of reconciliations performed, so it can run as a long-lived service under
+systemd or Kubernetes. A second Ctrl+C exits immediately. ` + "`--timeout`" + ` applies to each pass.
+
+Each pass is reported in the selected output format, e.g. ` + "`align --once -o json`" + ` writes one JSON
+document with every drifted resource and whether it was ` + "`healed`" + `, left ` + "`drifted`" + `, ` + "`skipped`" + `
+because its drift policy's ` + "`heal_drift_types`" + ` or ` + "`max_changes`" + ` ruled the heal out, or failed with
+` + "`error`" + `. Skipped heals leave drift behind, so ` + "`align --once`" + ` exits with code 2 for them too.
+
+` + "`align --report-only`" + ` is a read-only drift audit. It never heals, whatever a resource's drift
+policy says, and reports every resource as ` + "`aligned`" + ` or ` + "`drifted`" + `, with the same structured
+` + "`differences`" + ` as ` + "`preview`" + ` (` + "`report_only`" + ` is ` + "`true`" + ` in JSON and YAML). Unlike ` + "`preview`" + `, which
+describes the changes a commit would make, it describes the current drift. It exits with
+code 2 when any resource has drifted, so CI can gate on it.
+
+With a ` + "`notifications`" + ` section in the configuration, each pass that leaves drift unhealed also
+posts the drifted resources to a webhook as JSON; see the configuration reference.
+
 ### ` + "`runestone dismantle`" + `
 
 Destroys infrastructure resources.
@@ -99,6 +273,8 @@ runestone dismantle [flags]
 - ` + "`-c, --config string`" + ` - Path to configuration file (default: "infra.yaml")
 - ` + "`--auto-approve`" + ` - Skip interactive approval
 - ` + "`--force`" + ` - Force deletion even with dependencies
+- ` + "`--var-file stringArray`" + ` - Load variables from a YAML file (repeatable, later files win)
+- ` + "`--var stringArray`" + ` - Set a variable as key=value; the value is parsed as YAML (repeatable)
 - ` + "`-h, --help`" + ` - Help for dismantle
 
 **Example:**
@@ -106,13 +282,237 @@ runestone dismantle [flags]
 runestone dismantle --auto-approve
 ` + "```" + `
 
+### ` + "`runestone export`" + `
+
+Prints the live state of every resource in the configuration, keyed by resource ID. No drift
+is computed and nothing is changed, which makes it useful for debugging drift and for seeding
+new configurations from existing infrastructure.
+
+` + "```bash" + `
+runestone export [flags]
+` + "```" + `
+
+**Flags:**
+- ` + "`-c, --config string`" + ` - Path to configuration file (default: "infra.yaml")
+- ` + "`-o, --output string`" + ` - Output format: json, yaml, human, markdown, junit (default: "json")
+- ` + "`--var-file stringArray`" + ` - Load variables from a YAML file (repeatable, later files win)
+- ` + "`--var stringArray`" + ` - Set a variable as key=value; the value is parsed as YAML (repeatable)
+- ` + "`-h, --help`" + ` - Help for export
+
+Resources that don't exist yet are reported with ` + "`\"exists\": false`" + ` and no ` + "`state`" + `. If a
+resource's state can't be read, the error is recorded on that resource, the remaining
+resources are still exported, and the command exits with code ` + "`1`" + `.
+
+**Example:**
+` + "```bash" + `
+runestone export --output yaml > live-state.yaml
+` + "```" + `
+
+` + "```json" + `
+{
+  "success": true,
+  "resource_count": 2,
+  "resources": {
+    "aws:s3:bucket.my-app-logs": {
+      "kind": "aws:s3:bucket",
+      "exists": true,
+      "state": {
+        "versioning": true,
+        "tags": {"owner": "platform-team"}
+      }
+    },
+    "aws:s3:bucket.my-app-assets": {
+      "kind": "aws:s3:bucket",
+      "exists": false
+    }
+  },
+  "duration_seconds": 0.84
+}
+` + "```" + `
+
+### ` + "`runestone import`" + `
+
+Adopts a resource that was created outside Runestone.
+
+` + "```bash" + `
+runestone import <resource-id> <cloud-id> [flags]
+` + "```" + `
+
+EC2 instances, VPCs, subnets, internet gateways, EBS volumes and security groups are found by
+their ` + "`Name`" + ` tag, so resources created elsewhere usually aren't matched. ` + "`import`" + ` looks the
+resource up by its cloud ID, tags it with the ` + "`Name`" + ` the configuration expects and
+` + "`runestone:managed=true`" + `, and checks that drift detection now finds it. It then reports how the configuration differs from the
+resource and prints the resource's live properties for pasting into the configuration.
+
+Security groups are found by group name, which can't be changed, so the resource must be named
+after the existing group. Other resource kinds are found by name and don't need importing.
+
+EC2 instances, VPCs, subnets, internet gateways and EBS volumes that Runestone creates are also
+tagged ` + "`runestone:id`" + ` with their full resource ID, such as ` + "`aws:ec2:instance.web-1`" + `. When several
+resources share a ` + "`Name`" + ` tag, the one tagged with the resource's ID is used, and resources tagged
+for another resource ID are never matched. Imported resources have no ` + "`runestone:id`" + ` tag and are
+matched by ` + "`Name`" + ` alone.
+
+Because EC2 lookups are eventually consistent, creating one of these resources or a security
+group waits, for up to two minutes, until the lookup finds it. A preview or commit run straight
+afterwards therefore sees the new resource instead of planning to create it again.
+
+**Flags:**
+- ` + "`-c, --config string`" + ` - Path to configuration file (default: "infra.yaml")
+- ` + "`--var-file stringArray`" + ` - Load variables from a YAML file (repeatable, later files win)
+- ` + "`--var stringArray`" + ` - Set a variable as key=value; the value is parsed as YAML (repeatable)
+- ` + "`-h, --help`" + ` - Help for import
+
+**Example:**
+` + "```bash" + `
+runestone import aws:ec2:vpc.main vpc-0a1b2c3d
+` + "```" + `
+
+### ` + "`runestone scan`" + `
+
+Reports the resources in the account that Runestone doesn't manage, such as resources created
+by hand in the console.
+
+` + "```bash" + `
+runestone scan [flags]
+` + "```" + `
+
+For each configured provider that supports scanning, ` + "`scan`" + ` lists the real resources of these
+kinds:
+- S3 buckets in the provider's region
+- EC2 instances that haven't been terminated
+- VPCs other than the default VPC
+
+A resource is managed when it is tagged ` + "`runestone:managed`" + `, which ` + "`import`" + ` sets, or
+` + "`runestone:id`" + `, which Runestone sets on the EC2 resources it creates. Other resources, such as S3
+buckets, are only recognised when they are tagged, so add the marker to every resource the
+provider creates with ` + "`default_tags`" + `:
+
+` + "```yaml" + `
+providers:
+  aws:
+    region: us-east-1
+    default_tags:
+      runestone:managed: "true"
+` + "```" + `
+
+Only the providers section of the configuration is read. Human output lists the unmanaged
+resources with their name and creation date, when known; the other formats list every resource
+found with a ` + "`managed`" + ` field. JUnit output reports unmanaged resources as failures. Scan makes no
+changes and exits with code ` + "`0`" + ` whether or not unmanaged resources are found.
+
+**Flags:**
+- ` + "`-c, --config string`" + ` - Path to configuration file (default: "infra.yaml")
+- ` + "`-o, --output string`" + ` - Output format: human, json, markdown, yaml, junit (default: "human")
+- ` + "`--var-file stringArray`" + ` - Load variables from a YAML file (repeatable, later files win)
+- ` + "`--var stringArray`" + ` - Set a variable as key=value; the value is parsed as YAML (repeatable)
+- ` + "`-h, --help`" + ` - Help for scan
+
+**Example:**
+` + "```bash" + `
+runestone scan --output json
+` + "```" + `
+
+` + "```json" + `
+{
+  "success": true,
+  "resource_count": 2,
+  "unmanaged_count": 1,
+  "resources": [
+    {
+      "provider": "aws",
+      "kind": "aws:s3:bucket",
+      "cloud_id": "my-app-logs",
+      "name": "my-app-logs",
+      "created_at": "2024-03-01T12:00:00Z",
+      "managed": true
+    },
+    {
+      "provider": "aws",
+      "kind": "aws:ec2:instance",
+      "cloud_id": "i-0a1b2c3d4e5f67890",
+      "name": "bastion",
+      "created_at": "2024-05-20T08:30:00Z",
+      "managed": false
+    }
+  ],
+  "duration_seconds": 1.27
+}
+` + "```" + `
+
+### ` + "`runestone graph`" + `
+
+Shows the dependency graph of the configured resources.
+
+` + "```bash" + `
+runestone graph [flags]
+` + "```" + `
+
+The graph is built exactly as ` + "`commit`" + ` builds it, from ` + "`depends_on`" + ` and from references in
+properties, but no provider is contacted. The default output lists the execution levels and
+each resource's dependencies. Circular dependencies are reported with the chain of resources
+that forms them, e.g. ` + "`circular dependency detected: a -> b -> a`" + `.
+
+**Flags:**
+- ` + "`-c, --config string`" + ` - Path to configuration file (default: "infra.yaml")
+- ` + "`--var-file stringArray`" + ` - Load variables from a YAML file (repeatable, later files win)
+- ` + "`--var stringArray`" + ` - Set a variable as key=value; the value is parsed as YAML (repeatable)
+- ` + "`-o, --output string`" + ` - Output format: text, dot or json (default: "text")
+- ` + "`--out string`" + ` - Write the graph to a file instead of standard output
+- ` + "`-h, --help`" + ` - Help for graph
+
+**Example:**
+` + "```bash" + `
+runestone graph -o dot --out graph.dot && dot -Tpng graph.dot -o graph.png
+
+# Execution levels and dependencies for tooling
+runestone graph -o json
+` + "```" + `
+
+## Global Flags
+
+- ` + "`--timeout duration`" + ` - Cancel provider operations that run longer than this, e.g. ` + "`30m`" + ` (default: no timeout)
+- ` + "`--log-level string`" + ` - Minimum level of diagnostics logged to stderr: ` + "`debug`" + `, ` + "`info`" + `, ` + "`warn`" + ` or ` + "`error`" + ` (default: ` + "`info`" + `)
+- ` + "`-q, --quiet`" + ` - Log only warnings and errors (same as ` + "`--log-level warn`" + `)
+- ` + "`--no-color`" + ` - Leave colors and symbols out of human output
+
+Human output is colored and decorated with symbols only when stdout is a terminal. When it's
+piped, redirected to a file or captured by a CI log, Runestone writes plain ASCII text instead,
+with severities spelled out (` + "`[warning]`" + `) rather than shown as emoji. ` + "`--no-color`" + `, or setting
+the ` + "`NO_COLOR`" + ` environment variable, makes terminal output plain too. JSON, YAML, Markdown and
+JUnit output are never colored.
+
+Results, summaries and prompts are written to stdout. Diagnostics are logged to stderr as
+` + "`key=value`" + ` records: progress such as each resource being created, updated or deleted, retries
+after transient errors, and warnings. ` + "`--log-level debug`" + ` adds detail such as the status of
+resources being waited on. Since stdout carries only results, ` + "`--output json`" + ` stays parseable
+and progress can be silenced without losing them:
+
+` + "```bash" + `
+runestone commit --auto-approve --quiet
+runestone preview -o json 2>preview.log | jq .changes
+` + "```" + `
+
+Pressing Ctrl+C (or sending SIGTERM) cancels the operations in flight so the command stops
+promptly. ` + "`commit`" + ` and ` + "`dismantle`" + ` don't start another resource once cancelled and report the
+changes already applied before exiting with code ` + "`1`" + `. Press Ctrl+C a second time to exit
+immediately.
+
 ## Exit Codes
 
-- ` + "`0`" + ` - Success
-- ` + "`1`" + ` - General error
-- ` + "`2`" + ` - Configuration error
-- ` + "`3`" + ` - Provider error
-- ` + "`4`" + ` - Resource error
+Exit codes are stable, so scripts and CI pipelines can branch on them:
+
+- ` + "`0`" + ` - Success; no changes or drift
+- ` + "`1`" + ` - Operational failure (invalid configuration, provider or API errors, failed operations)
+- ` + "`2`" + ` - Changes or drift present (` + "`preview`" + `, ` + "`align --once`" + `, ` + "`commit --dry-run`" + `)
+- ` + "`3`" + ` - Error-severity policy violations (` + "`bootstrap`" + `, ` + "`validate`" + `, ` + "`preview`" + `)
+
+` + "`preview`" + ` exits ` + "`2`" + ` whenever it finds changes, so pipelines that only need to know whether the
+run succeeded should treat both ` + "`0`" + ` and ` + "`2`" + ` as success:
+
+` + "```bash" + `
+runestone preview --config infra.yaml || [ $? -eq 2 ]
+` + "```" + `
 
 ## Environment Variables
 
@@ -149,6 +549,68 @@ When using ` + "`--json`" + ` flag with preview command:
   "drift": {}
 }
 ` + "```" + `
+
+` + "`preview -o json`" + ` reports the number of planned changes as ` + "`changes_count`" + `, broken down into
+` + "`create_count`" + `, ` + "`update_count`" + ` and ` + "`delete_count`" + `. The markdown output shows the same counts
+in its summary.
+
+Each entry in ` + "`drift_results`" + ` keeps the human-readable ` + "`changes`" + ` and adds structured
+` + "`differences`" + `:
+
+` + "```json" + `
+{
+  "resource_name": "aws:s3:bucket.my-app-logs",
+  "has_drift": true,
+  "changes": ["Property tags.Environment: staging → production", "Property versioning: false → true"],
+  "differences": [
+    {
+      "property": "tags.Environment",
+      "path": "/tags/Environment",
+      "current_value": "staging",
+      "desired_value": "production",
+      "drift_type": "modified"
+    },
+    {
+      "property": "versioning",
+      "path": "/versioning",
+      "current_value": false,
+      "desired_value": true,
+      "drift_type": "modified"
+    }
+  ]
+}
+` + "```" + `
+
+` + "`drift_type`" + ` is ` + "`added`" + ` (missing from the live resource), ` + "`removed`" + ` (present only on the
+live resource) or ` + "`modified`" + `. Drift inside maps and lists is reported for the nested value
+that changed rather than the whole property. ` + "`property`" + ` is its dotted path, such as
+` + "`tags.Environment`" + ` or ` + "`lifecycle_rules[0].expiration_days`" + `, the form ` + "`ignore_changes`" + ` takes;
+` + "`path`" + ` is the same location as a JSON Pointer (RFC 6901), such as
+` + "`/lifecycle_rules/0/expiration_days`" + `, which stays unambiguous for keys containing dots or
+slashes: the tag ` + "`kubernetes.io/cluster/web`" + ` is at ` + "`/tags/kubernetes.io~1cluster~1web`" + `.
+
+## YAML Output Format
+
+` + "`--output yaml`" + ` produces the same documents as ` + "`--output json`" + `, with the same field names,
+encoded as YAML:
+
+` + "```bash" + `
+runestone preview --output yaml > changes.yaml
+` + "```" + `
+
+## JUnit Output Format
+
+` + "`--output junit`" + ` writes JUnit XML so CI systems can show results as tests. Policy violations
+and drift are separate test suites:
+
+- Error-severity policy violations and drifted resources are failures
+- Warning and info violations, and waived violations, are skipped with a message
+- Resources without problems are passing test cases
+- An operational error adds a failing test case named after the command
+
+` + "```bash" + `
+runestone preview --output junit > runestone-results.xml
+` + "```" + `
 `
 
 func (g *Generator) generateAPIReference() error {
@@ -11,6 +11,7 @@ This document provides a complete reference for Runestone configuration files.
 ## Configuration File Structure
 
 ` + "```yaml" + `
+apiVersion: string           # Configuration schema version, e.g. runestone/v1 (required)
 project: string              # Project name (required)
 environment: string          # Environment name (required)
 variables:                   # Global variables (optional)
@@ -27,10 +28,58 @@ resources:                   # Infrastructure resources (required)
   - kind: string
     name: string
     # Resource-specific configuration
+policy:                      # Policy engine (optional)
+  engine: builtin|rego
+  files: []
+exemptions:                  # Policy exemptions (optional)
+  - resource: string
+    rule: string
+outputs:                     # Values reported after a commit (optional)
+  output_name:
+    value: any
+    description: string
+    sensitive: bool
+notifications:               # Where align reports unhealed drift (optional)
+  webhook_url: string
+  timeout: string
+moved:                       # Resources renamed in place instead of replaced (optional)
+  - from: string
+    to: string
 ` + "```" + `
 
+## Splitting and Piping Configuration
+
+` + "`--config`" + ` accepts more than a single file:
+
+- ` + "`-c -`" + ` reads the configuration from standard input, so it can be generated by another tool
+- ` + "`-c infra/`" + ` merges every ` + "`.yaml`" + ` and ` + "`.yml`" + ` file directly inside the directory
+- ` + "`-c 'infra/*.yaml'`" + ` merges every file matching the glob pattern
+
+Files are merged in name order. Resources and exemptions are concatenated; defining the same
+resource in two files is an error. Variables, providers, modules and outputs are merged by name,
+and an entry defined differently in two files is an error. ` + "`apiVersion`" + `, ` + "`project`" + `,
+` + "`environment`" + ` and ` + "`policy`" + ` may be set in any of the files, but files that set them must agree.
+
+` + "```bash" + `
+runestone preview -c infra/
+render-config | runestone commit -c - --auto-approve
+` + "```" + `
+
+Standard input can't also answer prompts, so ` + "`commit`" + ` and ` + "`dismantle`" + ` require
+` + "`--auto-approve`" + ` when reading from it, and ` + "`align`" + ` requires ` + "`--once`" + `.
+
 ## Top-Level Fields
 
+### ` + "`apiVersion`" + ` (required)
+The configuration schema version. Runestone rejects versions it doesn't support with a
+message saying whether to upgrade Runestone or migrate the file. A file without
+` + "`apiVersion`" + ` is read as ` + "`runestone/v1`" + ` with a deprecation warning; the field will be
+mandatory in a future release.
+
+` + "```yaml" + `
+apiVersion: runestone/v1
+` + "```" + `
+
 ### ` + "`project`" + ` (required)
 The name of your project. Used for resource naming and organization.
 
@@ -60,6 +109,38 @@ variables:
     - "172.16.0.0/12"
 ` + "```" + `
 
+Variables can also be supplied from the command line. ` + "`--var-file`" + ` loads a YAML file of
+variables and ` + "`--var key=value`" + ` sets a single variable, with the value parsed as YAML so
+numbers, booleans and lists keep their types. Later sources win: inline ` + "`variables`" + `, then
+each ` + "`--var-file`" + ` in order, then ` + "`--var`" + ` overrides.
+
+` + "```bash" + `
+runestone preview --var-file prod.vars.yaml --var instance_count=3 --var 'regions=[us-east-1, eu-west-1]'
+` + "```" + `
+
+Each source is deep-merged into the ones before it: when two sources both set a variable to a
+map, the maps are merged key by key, recursively, instead of the later map replacing the earlier
+one. Any other value, including a list, replaces what came before. This lets a shared variable
+file hold the defaults and a per-environment file hold only what differs:
+
+` + "```yaml" + `
+# base.vars.yaml
+tags:
+  team: platform
+  stage: dev
+sizing:
+  web: { count: 1, type: t3.micro }
+
+# prod.vars.yaml
+tags:
+  stage: prod
+sizing:
+  web: { count: 3 }
+` + "```" + `
+
+With ` + "`--var-file base.vars.yaml --var-file prod.vars.yaml`" + `, ` + "`tags`" + ` is
+` + "`{team: platform, stage: prod}`" + ` and ` + "`sizing.web`" + ` is ` + "`{count: 3, type: t3.micro}`" + `.
+
 ## Providers
 
 ### AWS Provider
@@ -69,6 +150,47 @@ providers:
   aws:
     region: string           # AWS region (required)
     profile: string          # AWS profile name (optional)
+    default_tags:            # Tags applied to every resource (optional)
+      key: value
+    ignore_tag_prefixes: [string]  # Tag key prefixes drift detection ignores (optional, default: ["aws:"])
+    max_retries: int         # Retries for failed API calls (optional, default: 3)
+    base_delay_ms: int       # Initial retry backoff in milliseconds (optional, default: 1000)
+    default_wait_timeout: string  # How long to wait for resources to become ready, e.g. 30m (optional)
+    assume_role_arn: string  # IAM role to assume for every API call (optional)
+    external_id: string      # External ID required by the role's trust policy (optional)
+    session_name: string     # Session name for the assumed role (optional, default: runestone)
+    endpoint_url: string     # Send every API call to this URL instead of AWS, e.g. LocalStack (optional)
+` + "```" + `
+
+With ` + "`assume_role_arn`" + `, the provider loads credentials as usual from the profile or
+environment and uses them to assume the role, which is how one set of credentials manages
+resources in other accounts. Credentials for the role are refreshed before they expire, and
+account-dependent values such as generated ARNs use the role's account. Combine it with
+provider aliases to manage several accounts from one configuration.
+
+Resources that take time to become ready, such as RDS instances, EC2 instances and
+DynamoDB tables, are polled until they are ready before dependents run. Each type has
+its own default wait; ` + "`default_wait_timeout`" + ` replaces those defaults for every resource,
+the ` + "`timeouts`" + ` block of an RDS or EC2 instance replaces them for that instance, and a resource's
+` + "`wait_timeout`" + ` property overrides both. Interrupting a commit stops any wait immediately.
+
+AWS and other tools add tags of their own to resources, such as
+` + "`aws:cloudformation:stack-name`" + `. Drift detection ignores live tags whose keys start with one
+of the ` + "`ignore_tag_prefixes`" + ` unless the resource declares them, so they aren't reported as
+removed. The list replaces the default ` + "`[\"aws:\"]`" + `; add prefixes such as ` + "`kubernetes.io/`" + ` to
+it, or set it to ` + "`[]`" + ` to compare every tag.
+
+` + "`endpoint_url`" + ` points every client at a single endpoint that emulates the AWS APIs, such
+as a LocalStack container, so bootstrap, preview and commit can be exercised end to end
+without touching a real account. S3 buckets are then addressed by path rather than by
+subdomain. Credentials are still loaded as usual; mock endpoints generally accept any, so
+setting ` + "`AWS_ACCESS_KEY_ID`" + ` and ` + "`AWS_SECRET_ACCESS_KEY`" + ` to ` + "`test`" + ` is enough:
+
+` + "```yaml" + `
+providers:
+  aws:
+    region: us-east-1
+    endpoint_url: http://localhost:4566
 ` + "```" + `
 
 **Example:**
@@ -77,6 +199,119 @@ providers:
   aws:
     region: "${region}"
     profile: production
+    default_tags:
+      Environment: "${environment}"
+      ManagedBy: runestone
+` + "```" + `
+
+### Provider Aliases
+
+To configure a provider more than once, such as AWS in two regions, key the extra
+configurations as ` + "`<provider>.<alias>`" + `. A resource selects one with its ` + "`provider`" + ` field;
+resources without it use the unaliased configuration, so keep a bare ` + "`aws`" + ` entry for them.
+Each configuration is initialized separately with its own credentials and settings.
+
+` + "```yaml" + `
+providers:
+  aws:
+    region: us-east-1
+  aws.eu-west-1:
+    region: eu-west-1
+
+resources:
+  - kind: aws:s3:bucket
+    name: assets-us
+  - kind: aws:s3:bucket
+    name: assets-eu
+    provider: aws.eu-west-1
+` + "```" + `
+
+Resource IDs don't include the alias, so resources in different regions need different
+names.
+
+### GCP Provider
+
+` + "```yaml" + `
+providers:
+  gcp:
+    project: string            # GCP project ID (required)
+    credentials_file: string   # Service account key file (optional; Application Default Credentials otherwise)
+` + "```" + `
+
+**Example:**
+` + "```yaml" + `
+providers:
+  gcp:
+    project: my-project-123
+` + "```" + `
+
+### Kubernetes Provider
+
+` + "```yaml" + `
+providers:
+  kubernetes:
+    kubeconfig: string   # Path to a kubeconfig file (optional; $KUBECONFIG or ~/.kube/config otherwise)
+    context: string      # Kubeconfig context to use (optional; the current context otherwise)
+` + "```" + `
+
+Kubernetes resources use the ` + "`k8s:`" + ` kind prefix. Runestone creates and updates them with
+server-side apply as the ` + "`runestone`" + ` field manager, so settings removed from the configuration
+are removed from the cluster while fields owned by other tools are left alone.
+
+**Example:**
+` + "```yaml" + `
+providers:
+  kubernetes:
+    kubeconfig: ~/.kube/staging
+    context: staging
+` + "```" + `
+
+## Modules
+
+Modules package resources for reuse. A module is a directory of YAML files, each of which
+may declare ` + "`variables`" + ` (input defaults) and ` + "`resources`" + `:
+
+` + "```yaml" + `
+modules:
+  network:
+    source: ./modules/network                                   # Local directory
+    inputs:
+      cidr_block: 10.0.0.0/16
+  shared:
+    source: git::https://github.com/acme/modules.git//network   # Repository and optional subdirectory
+    version: v1.2.0                                             # Tag or branch to clone
+` + "```" + `
+
+Local sources start with ` + "`./`" + `, ` + "`../`" + ` or ` + "`/`" + `, and relative ones are resolved against the
+directory of the configuration file that declares them. Git sources are cloned into the user
+cache directory; on later runs the cached clone is updated to the latest commit of ` + "`version`" + `,
+or used as is if the update fails. Module resources are added to the root resource set;
+inside a module, ` + "`inputs`" + ` override its variable defaults and ` + "`${module.name}`" + ` is the module's
+name. Resource IDs must be unique across the root configuration and all modules.
+
+A module can expose values to the root configuration through ` + "`outputs`" + `. Outputs are
+evaluated in the module's scope and read from root resources as ` + "`${module.<name>.<output>}`" + `;
+a resource that reads a module output depends on all of that module's resources.
+
+` + "```yaml" + `
+# modules/network/main.yaml
+resources:
+  - kind: aws:ec2:vpc
+    name: "${module.name}-vpc"
+    properties:
+      cidr_block: "${cidr_block}"
+outputs:
+  vpc_id: "${aws:ec2:vpc.network-vpc.vpc_id}"
+  subnet_cidrs: ["10.0.1.0/24", "10.0.2.0/24"]
+
+# infra.yaml
+resources:
+  - kind: aws:ec2:subnet
+    name: app-${count.index}
+    count: "${length(module.network.subnet_cidrs)}"
+    properties:
+      vpc_id: "${module.network.vpc_id}"
+      cidr_block: "${module.network.subnet_cidrs[count.index]}"
 ` + "```" + `
 
 ## Resources
@@ -89,11 +324,51 @@ All resources support these fields:
 resources:
   - kind: string             # Resource type (required)
     name: string             # Resource name (required)
+    enabled: bool            # Whether the resource exists at all (optional, default: true)
     count: int               # Number of instances (optional)
-    for_each: array          # Iterate over array (optional)
+    for_each: array|map      # Iterate over array or map (optional)
     properties: {}           # Resource properties (optional)
     driftPolicy: {}          # Drift handling policy (optional)
     depends_on: []           # Dependencies (optional)
+    provider: string         # Aliased provider configuration, e.g. aws.eu-west-1 (optional)
+    sensitive: []            # Properties whose values are redacted in output (optional)
+    timeouts:                # How long each change may take, e.g. 40m (optional)
+      create: string
+      update: string
+      delete: string
+` + "```" + `
+
+Values of sensitive properties are shown as ` + "`***`" + ` in drift differences and change
+descriptions in every output format, including when the property is nested in a map such as
+` + "`tags`" + `. Providers declare their own sensitive properties, such as ` + "`master_user_password`" + ` of
+` + "`aws:rds:instance`" + `; list any others under ` + "`sensitive`" + `:
+
+` + "```yaml" + `
+- kind: aws:lambda:function
+  name: api-handler
+  properties:
+    # ...
+    tags:
+      api_key: "${env('API_KEY')}"
+  sensitive: [api_key]
+` + "```" + `
+
+` + "`timeouts`" + ` bounds how long creating, updating or deleting the resource may take, so a slow
+resource such as an RDS instance can be given longer without raising ` + "`--timeout`" + ` for
+everything else. A change that runs longer is stopped and fails with an error naming the
+timeout, and isn't retried. Changes without a timeout fall back to the provider's wait for
+the resource type (see ` + "`default_wait_timeout`" + `), bounded only by ` + "`--timeout`" + `. Timeouts accept
+expressions, so they can come from variables.
+
+` + "```yaml" + `
+- kind: aws:rds:instance
+  name: app-db
+  properties:
+    # ...
+  timeouts:
+    create: 60m
+    update: 90m
+    delete: 30m
 ` + "```" + `
 
 ### AWS S3 Bucket
@@ -104,6 +379,9 @@ resources:
   properties:
     versioning: boolean      # Enable versioning (optional)
     tags: {}                 # Resource tags (optional)
+    policy: string           # Bucket policy JSON document (optional; removed when cleared)
+    region: string           # Must match the provider region if set (optional)
+    force_destroy: boolean   # Delete all objects when the bucket is deleted (default: false)
   driftPolicy:
     autoHeal: boolean        # Auto-fix drift (default: false)
     notifyOnly: boolean      # Only notify on drift (default: true)
@@ -123,6 +401,33 @@ resources:
     notifyOnly: false
 ` + "```" + `
 
+The ` + "`policy`" + ` document is compared by content, so AWS's reformatting of it isn't reported as
+drift. A TLS-only policy:
+
+` + "```yaml" + `
+- kind: aws:s3:bucket
+  name: secure-uploads
+  properties:
+    policy: |
+      {
+        "Version": "2012-10-17",
+        "Statement": [{
+          "Effect": "Deny",
+          "Principal": "*",
+          "Action": "s3:*",
+          "Resource": ["arn:aws:s3:::secure-uploads", "arn:aws:s3:::secure-uploads/*"],
+          "Condition": {"Bool": {"aws:SecureTransport": "false"}}
+        }]
+      }
+` + "```" + `
+
+Buckets are created in the AWS provider's ` + "`region`" + ` and report the region they are in, so a
+bucket created elsewhere shows up as drift when ` + "`region`" + ` is set.
+
+A bucket can only be deleted once it is empty. With ` + "`force_destroy: true`" + `, ` + "`dismantle`" + `
+deletes every object first, including old versions and delete markers; otherwise deleting a
+bucket that still holds objects fails.
+
 ### AWS EC2 Instance
 
 ` + "```yaml" + `
@@ -131,22 +436,28 @@ resources:
   properties:
     instance_type: string    # EC2 instance type (required)
     ami: string              # AMI ID (required)
+    desired_state: string    # running or stopped (optional)
+    wait_timeout: string     # How long to wait for the instance to be running, e.g. "15m" or seconds (optional, default: 10m)
     tags: {}                 # Instance tags (optional)
   driftPolicy:
     autoHeal: boolean        # Auto-fix drift (default: false)
     notifyOnly: boolean      # Only notify on drift (default: true)
 ` + "```" + `
 
+Creating an instance waits until it is running, so resources that depend on it can read its ` + "`private_ip`" + ` and ` + "`public_ip`" + `. The create fails if the instance stops or terminates instead, or is still pending after ` + "`wait_timeout`" + `.
+
+Set ` + "`desired_state: stopped`" + ` to keep an instance powered off without destroying it; an instance created stopped is launched and then stopped. Changing ` + "`desired_state`" + ` stops or starts the instance and waits for it to get there. A stopping instance counts as ` + "`stopped`" + ` and a pending one as ` + "`running`" + `.
+
 **Example:**
 ` + "```yaml" + `
 - kind: aws:ec2:instance
-  name: web-server-${index}
+  name: web-server-${count.index}
   count: 3
   properties:
     instance_type: "${environment == 'prod' ? 't3.large' : 't3.micro'}"
     ami: ami-0abcdef1234567890
     tags:
-      Name: "web-server-${index}"
+      Name: "web-server-${count.index}"
       Environment: "${environment}"
       Role: web-server
   driftPolicy:
@@ -154,6 +465,37 @@ resources:
     notifyOnly: false
 ` + "```" + `
 
+### AWS EBS Volume
+
+` + "```yaml" + `
+- kind: aws:ec2:volume
+  name: volume-name
+  properties:
+    size: integer               # Size in GiB (required)
+    availability_zone: string   # Availability zone (required)
+    type: string                # gp2, gp3, io1, io2, st1, sc1 or standard (optional, default: gp3)
+    encrypted: boolean          # Encrypt the volume (optional)
+    iops: integer               # Provisioned IOPS (required for io1 and io2, optional for gp3)
+    instance_id: string         # Instance to attach the volume to (optional, with device_name)
+    device_name: string         # Device name on the instance, e.g. /dev/sdf (optional, with instance_id)
+    tags: {}                    # Volume tags (optional)
+` + "```" + `
+
+The volume is found by its ` + "`Name`" + ` tag. Its state includes the computed ` + "`volume_id`" + ` and ` + "`state`" + `. Changes to ` + "`size`" + `, ` + "`type`" + ` and ` + "`iops`" + ` are applied in place, and changing ` + "`instance_id`" + ` or ` + "`device_name`" + ` detaches the volume and attaches it again; ` + "`availability_zone`" + ` and ` + "`encrypted`" + ` can't be changed without replacing the volume. Deleting an attached volume detaches it first and waits for it to become available.
+
+**Example:**
+` + "```yaml" + `
+- kind: aws:ec2:volume
+  name: web-data
+  properties:
+    size: 100
+    availability_zone: us-east-1a
+    type: gp3
+    encrypted: true
+    instance_id: "${aws:ec2:instance.web.instance_id}"
+    device_name: /dev/sdf
+` + "```" + `
+
 ### AWS VPC
 
 ` + "```yaml" + `
@@ -239,7 +581,10 @@ resources:
     runtime: string          # Lambda runtime (required)
     handler: string          # Function handler (required)
     role: string             # IAM role ARN (required)
-    code_content: string     # Function code (optional)
+    code_content: string     # Inline deployment package (one code source required)
+    filename: string         # Path to a local zip file (one code source required)
+    s3_bucket: string        # Bucket holding the zip file (with s3_key)
+    s3_key: string           # Key of the zip file in s3_bucket (with s3_bucket)
     description: string      # Function description (optional)
     timeout: integer         # Timeout in seconds (optional)
     memory_size: integer     # Memory in MB (optional)
@@ -270,6 +615,291 @@ resources:
     - "aws:iam:role.lambda-role"
 ` + "```" + `
 
+Set exactly one code source: ` + "`code_content`" + `, ` + "`filename`" + `, or ` + "`s3_bucket`" + ` with ` + "`s3_key`" + `. Runestone compares the SHA-256 of the configured package with the function's ` + "`CodeSha256`" + `, so a rebuilt zip file shows up as drift on the code source property and is redeployed by ` + "`commit`" + `. Packages in S3 aren't downloaded; their hash is the SHA-256 checksum S3 stores with the object, so upload them with one, for example ` + "`aws s3 cp --checksum-algorithm SHA256`" + `. Changes to packages uploaded without a checksum, or in parts, aren't detected.
+
+` + "```yaml" + `
+- kind: aws:lambda:function
+  name: api-handler
+  properties:
+    runtime: "nodejs20.x"
+    handler: "index.handler"
+    role: "arn:aws:iam::123456789012:role/lambda-role"
+    filename: "build/api-handler.zip"
+` + "```" + `
+
+### AWS CloudWatch Log Group
+
+` + "```yaml" + `
+- kind: aws:logs:log_group
+  name: /aws/lambda/function-name
+  properties:
+    retention_in_days: integer  # Days to keep log events (optional, default: never expire)
+    tags: {}                    # Log group tags (optional)
+` + "```" + `
+
+` + "`retention_in_days`" + ` must be one of 1, 3, 5, 7, 14, 30, 60, 90, 120, 150, 180, 365, 400, 545, 731, 1096, 1827, 2192, 2557, 2922, 3288 or 3653. Removing it makes log events never expire again. Lambda writes to ` + "`/aws/lambda/<function name>`" + `, so declaring that log group before the function first runs keeps its logs under the configured retention.
+
+**Example:**
+` + "```yaml" + `
+- kind: aws:logs:log_group
+  name: /aws/lambda/api-handler
+  properties:
+    retention_in_days: 30
+    tags:
+      Environment: "${environment}"
+` + "```" + `
+
+### AWS KMS Key
+
+` + "```yaml" + `
+- kind: aws:kms:key
+  name: key-name
+  properties:
+    description: string              # Key description (optional)
+    key_policy: string               # Key policy JSON (optional, default: AWS default key policy)
+    enable_key_rotation: boolean     # Rotate the key material yearly (optional)
+    deletion_window_in_days: integer # Days before a deleted key is destroyed, 7-30 (optional, default: 30)
+    tags: {}                         # Key tags (optional)
+` + "```" + `
+
+Creates a symmetric customer managed key. KMS keys have no name, so the key is tagged ` + "`runestone:id`" + ` with its resource ID and found by that tag; finding it lists the account's keys, so it slows down in accounts with many keys. Its state includes the computed ` + "`key_id`" + `, ` + "`arn`" + ` and ` + "`key_state`" + `.
+
+KMS keys can't be deleted immediately. Deleting the resource schedules the key for deletion after ` + "`deletion_window_in_days`" + ` and prints the date it will be destroyed; until then the key is disabled and can be recovered with ` + "`aws kms cancel-key-deletion`" + `.
+
+**Example:**
+` + "```yaml" + `
+- kind: aws:kms:key
+  name: app-data
+  properties:
+    description: Encrypts application data
+    enable_key_rotation: true
+    deletion_window_in_days: 14
+` + "```" + `
+
+### AWS KMS Alias
+
+` + "```yaml" + `
+- kind: aws:kms:alias
+  name: alias/alias-name
+  properties:
+    target_key_id: string   # ID or ARN of the key the alias points at (required)
+` + "```" + `
+
+The resource name is the alias name, which must start with ` + "`alias/`" + `; the ` + "`alias/aws/`" + ` namespace is reserved for AWS managed keys. Changing ` + "`target_key_id`" + ` points the alias at the new key, and deleting the alias leaves the key in place. Other resources can refer to the key as ` + "`alias/alias-name`" + ` wherever KMS accepts a key ID. Its state includes the computed ` + "`alias_arn`" + `.
+
+**Example:**
+` + "```yaml" + `
+- kind: aws:kms:alias
+  name: alias/app-data
+  properties:
+    target_key_id: "${aws:kms:key.app-data.key_id}"
+` + "```" + `
+
+### AWS ECR Repository
+
+` + "```yaml" + `
+- kind: aws:ecr:repository
+  name: repository-name
+  properties:
+    image_tag_mutability: string  # MUTABLE or IMMUTABLE (optional, default: MUTABLE)
+    scan_on_push: boolean         # Scan images for vulnerabilities when pushed (optional, default: false)
+    lifecycle_policy: string      # Lifecycle policy JSON (optional)
+    force_delete: boolean         # Delete the repository's images along with it (optional, default: false)
+    tags: {}                      # Repository tags (optional)
+` + "```" + `
+
+The resource name is the repository name, which is lowercase and may contain ` + "`/`" + ` to group repositories into namespaces. Its state includes the computed ` + "`repository_arn`" + `, ` + "`repository_uri`" + ` and ` + "`registry_id`" + `; use ` + "`repository_uri`" + ` to tag and push images. A repository that still holds images is only deleted when ` + "`force_delete`" + ` is set.
+
+**Example:**
+` + "```yaml" + `
+- kind: aws:ecr:repository
+  name: team/web-app
+  properties:
+    image_tag_mutability: IMMUTABLE
+    scan_on_push: true
+    lifecycle_policy: |
+      {
+        "rules": [{
+          "rulePriority": 1,
+          "description": "Expire untagged images after 14 days",
+          "selection": {"tagStatus": "untagged", "countType": "sinceImagePushed", "countUnit": "days", "countNumber": 14},
+          "action": {"type": "expire"}
+        }]
+      }
+    tags:
+      Team: web
+` + "```" + `
+
+### AWS IAM Role
+
+` + "```yaml" + `
+- kind: aws:iam:role
+  name: role-name
+  properties:
+    assume_role_policy: string   # Trust policy JSON (required)
+    path: string                 # Role path (default: "/")
+    description: string          # Role description (optional)
+    managed_policy_arns: []      # ARNs of managed policies to attach (optional)
+    inline_policies: {}          # Inline policy name to policy JSON (optional)
+    tags: {}                     # Role tags (optional)
+` + "```" + `
+
+When ` + "`managed_policy_arns`" + ` or ` + "`inline_policies`" + ` is set, Runestone keeps the role's policies in
+line with it: missing policies are attached or put, and policies not listed are detached or
+deleted. Leave a property out to leave that kind of policy unmanaged. Deleting a role detaches
+all of its policies first.
+
+**Example:**
+` + "```yaml" + `
+- kind: aws:iam:role
+  name: lambda-role
+  properties:
+    assume_role_policy: |
+      {
+        "Version": "2012-10-17",
+        "Statement": [{
+          "Effect": "Allow",
+          "Principal": {"Service": "lambda.amazonaws.com"},
+          "Action": "sts:AssumeRole"
+        }]
+      }
+    managed_policy_arns:
+      - "arn:aws:iam::aws:policy/service-role/AWSLambdaBasicExecutionRole"
+    inline_policies:
+      read-data: |
+        {
+          "Version": "2012-10-17",
+          "Statement": [{
+            "Effect": "Allow",
+            "Action": ["s3:GetObject"],
+            "Resource": "arn:aws:s3:::my-data-bucket/*"
+          }]
+        }
+` + "```" + `
+
+### AWS API Gateway REST API
+
+` + "```yaml" + `
+- kind: aws:apigateway:rest_api
+  name: api-name
+  properties:
+    description: string      # API description (optional)
+    routes:                  # Methods to create (optional)
+      - path: string         # Resource path, such as /users/{id} (required)
+        method: string       # GET, POST, PUT, PATCH, DELETE, HEAD, OPTIONS or ANY (required)
+        integration_type: string  # AWS_PROXY, HTTP_PROXY, HTTP, AWS or MOCK (required)
+        integration_uri: string   # Backend URI; a Lambda function ARN for AWS_PROXY (required unless MOCK)
+    stage_name: string       # Stage to deploy the routes to (optional, requires routes)
+` + "```" + `
+
+**Example:**
+` + "```yaml" + `
+- kind: aws:apigateway:rest_api
+  name: public-api
+  properties:
+    description: "Public API"
+    stage_name: prod
+    routes:
+      - path: /users/{id}
+        method: GET
+        integration_type: AWS_PROXY
+        integration_uri: "${aws:lambda:function.api-handler.function_arn}"
+      - path: /health
+        method: GET
+        integration_type: MOCK
+` + "```" + `
+
+Runestone creates a resource for each path segment, a method and integration for each route, and a deployment to ` + "`stage_name`" + `, so the API can be invoked at the ` + "`invoke_url`" + ` in its state. Methods that no route declares are deleted. Each Lambda function an ` + "`AWS_PROXY`" + ` route calls gets a resource policy statement allowing the API to invoke it. When ` + "`routes`" + ` or ` + "`stage_name`" + ` is set, drift detection compares them with the API's deployed routes and stages.
+
+### GCP Storage Bucket
+
+` + "```yaml" + `
+- kind: gcp:storage:bucket
+  name: bucket-name
+  properties:
+    location: string                      # Bucket location, e.g. US, EU, europe-west1 (optional)
+    storage_class: string                 # STANDARD, NEARLINE, COLDLINE or ARCHIVE (optional)
+    versioning: bool                      # Enable object versioning (optional)
+    uniform_bucket_level_access: bool     # Enforce uniform bucket-level access (optional)
+    labels: {}                            # Bucket labels (optional)
+` + "```" + `
+
+A bucket's ` + "`location`" + ` cannot be changed after it is created. Label keys and values may contain
+only lowercase letters, digits, underscores and dashes.
+
+**Example:**
+` + "```yaml" + `
+- kind: gcp:storage:bucket
+  name: my-app-assets
+  properties:
+    location: EU
+    storage_class: STANDARD
+    versioning: true
+    uniform_bucket_level_access: true
+    labels:
+      environment: "${environment}"
+` + "```" + `
+
+### Kubernetes Deployment
+
+` + "```yaml" + `
+- kind: k8s:apps:deployment
+  name: deployment-name
+  properties:
+    namespace: string        # Namespace (default: "default")
+    image: string            # Container image (required)
+    replicas: int            # Number of pods (optional)
+    container_port: int      # Port the container listens on (optional)
+    env: {}                  # Environment variables (optional)
+    labels: {}               # Deployment labels, also applied to its pods (optional)
+` + "```" + `
+
+The deployment runs a single container named after the resource, and selects its pods with an
+` + "`app`" + ` label set to the resource name.
+
+**Example:**
+` + "```yaml" + `
+- kind: k8s:apps:deployment
+  name: web
+  properties:
+    namespace: apps
+    image: nginx:1.27
+    replicas: 3
+    container_port: 80
+    env:
+      MODE: "${environment}"
+` + "```" + `
+
+### Kubernetes Service
+
+` + "```yaml" + `
+- kind: k8s:core:service
+  name: service-name
+  properties:
+    namespace: string        # Namespace (default: "default")
+    type: string             # ClusterIP, NodePort or LoadBalancer (default: ClusterIP)
+    selector: {}             # Labels of the pods to route to (required)
+    ports:                   # Ports to expose (required)
+      - name: string         # Port name (required when there is more than one port)
+        port: int            # Service port (required)
+        target_port: int     # Container port (default: port)
+        protocol: string     # TCP, UDP or SCTP (default: TCP)
+    labels: {}               # Service labels (optional)
+` + "```" + `
+
+**Example:**
+` + "```yaml" + `
+- kind: k8s:core:service
+  name: web
+  properties:
+    namespace: apps
+    selector:
+      app: web
+    ports:
+      - port: 80
+        target_port: 80
+` + "```" + `
+
 ## Expression Language
 
 Runestone supports expressions using ` + "`${}`" + ` syntax:
@@ -287,24 +917,88 @@ backup_enabled: "${environment == 'prod'}"
 storage_size: "${environment == 'prod' ? 100 : 20}"
 ` + "```" + `
 
+### Functions
+Built-in functions can be called inside expressions. A variable with the same name as a
+function takes precedence over it.
+
+- ` + "`env(name string) string`" + ` - Value of an OS environment variable; fails if it is not set
+- ` + "`secret(name string) string`" + ` - Value of an AWS Secrets Manager secret, given its name or ARN; read at commit time
+- ` + "`upper(s string) string`" + ` - Converts a string to upper case
+- ` + "`lower(s string) string`" + ` - Converts a string to lower case
+- ` + "`replace(s string, old string, new string) string`" + ` - Replaces every occurrence of ` + "`old`" + ` with ` + "`new`" + `
+- ` + "`join(list []any, separator string) string`" + ` - Joins list elements with a separator
+- ` + "`split(s string, separator string) []string`" + ` - Splits a string into a list
+- ` + "`format(format string, args ...any) string`" + ` - Formats values using Go ` + "`fmt`" + ` verbs such as ` + "`%s`" + ` and ` + "`%d`" + `
+- ` + "`length(value string|list|map) int`" + ` - Number of characters, list elements or map entries
+
+` + "```yaml" + `
+password: "${env('DB_PASSWORD')}"
+bucket: "${lower(project) + '-' + environment}"
+subnets: "${join(subnet_ids, ',')}"
+` + "```" + `
+
+` + "`secret()`" + ` is resolved only when ` + "`commit`" + `, or ` + "`align`" + ` healing drift, creates or updates the
+resource, using the credentials of the AWS provider the resource uses, such as an ` + "`aws.prod`" + `
+alias; resources of other providers use the ` + "`aws`" + ` provider, which must then be configured.
+Until then the property keeps
+the reference, so ` + "`preview`" + `, plan files and drift reports never contain the secret's value.
+For the same reason, properties that reference a secret aren't compared for drift, so a
+secret rotated outside Runestone isn't applied until the resource changes for another reason.
+Only string secrets are supported, and ` + "`secret()`" + ` can only be used in resource properties.
+
+` + "```yaml" + `
+- kind: aws:rds:instance
+  name: app-db
+  properties:
+    master_user_password: "${secret('prod/app-db/password')}"
+` + "```" + `
+
 ### Loop Variables
 When using ` + "`count`" + ` or ` + "`for_each`" + `, special variables are available:
 
-- ` + "`${index}`" + ` - Current index (0-based) for count
-- ` + "`${item}`" + ` - Current item for for_each
+- ` + "`${count.index}`" + ` - Current index (0-based) for count
+- ` + "`${each.key}`" + ` - Current key for for_each: the map key, the item itself for string lists, or its index otherwise
+- ` + "`${each.value}`" + ` - Current value for for_each
+
+` + "`for_each`" + ` accepts a list or a map, written inline or as an expression such as
+` + "`${split(regions, \",\")}`" + `; maps are iterated in key order. Any other value is an error that
+names the resource and the type the expression produced. The older ` + "`${index}`" + `,
+` + "`${region}`" + ` and ` + "`${item}`" + ` names still work but are deprecated and will be removed in the next release.
 
 ` + "```yaml" + `
 # Using count
 - kind: aws:ec2:instance
-  name: web-${index}
+  name: web-${count.index}
   count: 3
 
 # Using for_each
 - kind: aws:s3:bucket
-  name: logs-${region}
+  name: logs-${each.value}
   for_each: "${regions}"
+
+# Using for_each over a map
+- kind: aws:s3:bucket
+  name: data-${each.key}
+  for_each: "${buckets}"      # e.g. {raw: {versioning: false}, curated: {versioning: true}}
+  properties:
+    versioning: "${each.value.versioning}"
+` + "```" + `
+
+### Conditional Resources
+` + "`enabled`" + ` turns a resource on or off, e.g. per environment. When it is ` + "`false`" + ` the resource
+expands to no instances, whatever its ` + "`count`" + ` or ` + "`for_each`" + `. It takes a boolean or an
+expression that evaluates to one:
+
+` + "```yaml" + `
+- kind: aws:s3:bucket
+  name: backups
+  enabled: ${environment == "prod"}
 ` + "```" + `
 
+Disabling a resource doesn't delete one that already exists: Runestone never deletes
+resources missing from the configuration, so it simply stops managing it. Delete it
+separately if it should go away.
+
 ## Drift Policies
 
 Control how Runestone handles configuration drift:
@@ -313,6 +1007,9 @@ Control how Runestone handles configuration drift:
 driftPolicy:
   autoHeal: boolean          # Automatically fix drift
   notifyOnly: boolean        # Only report drift, don't fix
+  ignore_changes: [string]   # Property paths whose changes are not drift
+  heal_drift_types: [string] # Drift types auto-heal fixes: added, removed, modified, missing (default: all)
+  max_changes: int           # Refuse auto-heal when more properties differ (default: 0, no limit)
 ` + "```" + `
 
 **Behavior:**
@@ -320,6 +1017,45 @@ driftPolicy:
 - ` + "`autoHeal: false, notifyOnly: true`" + ` - Report drift only
 - ` + "`autoHeal: false, notifyOnly: false`" + ` - Report and prompt for action
 
+Values are compared by meaning rather than by type: cloud APIs return tag values and many
+numbers as strings, so ` + "`20`" + `, ` + "`20.0`" + `, ` + "`\"20\"`" + ` and ` + "`\"20.0\"`" + ` are equal, as are ` + "`true`" + `, ` + "`\"true\"`" + `,
+` + "`\"True\"`" + ` and ` + "`\"1\"`" + `. Strings that don't parse as the other value's type, such as ` + "`\"yes\"`" + `, are
+never equal to a boolean or number. Maps such as ` + "`tags`" + ` are compared key by key regardless of
+order; lists are compared in order.
+Differences inside maps and lists are reported by path, such as ` + "`tags.Environment`" + ` or
+` + "`ports[1].port`" + `, so only the values that changed are shown.
+
+Properties that the cloud assigns, such as IDs, ARNs and status, are ignored unless the
+resource configures them. Each provider declares these per resource type (for example
+` + "`function_arn`" + ` for Lambda functions), so a configured property such as a subnet's ` + "`vpc_id`" + ` is
+still compared.
+
+Properties that other tools change on purpose can be left out of drift detection with
+` + "`ignore_changes`" + `. Each entry is a property name, or a dotted path to a key of a map property:
+
+` + "```yaml" + `
+driftPolicy:
+  notifyOnly: true
+  ignore_changes:
+    - tags.LastModifiedBy    # Set by a backup job
+    - desired_count          # Managed by autoscaling
+` + "```" + `
+
+Auto-heal can be limited to the drift it is safe to undo. ` + "`heal_drift_types`" + ` lists the drift
+types it fixes: ` + "`added`" + `, ` + "`removed`" + ` or ` + "`modified`" + ` properties, and ` + "`missing`" + ` for a resource that no
+longer exists and would be recreated. ` + "`max_changes`" + ` is a safety valve for drift so widespread
+that something has gone badly wrong: when more properties differ, nothing is healed. Drift
+either rule leaves alone is reported by ` + "`align`" + ` as ` + "`skipped`" + `, with the reason, counted under
+` + "`heals_skipped`" + ` in JSON and YAML output, and sent to any configured notifications.
+
+` + "```yaml" + `
+driftPolicy:
+  autoHeal: true
+  notifyOnly: false
+  heal_drift_types: [modified]   # Never recreate a deleted database automatically
+  max_changes: 3
+` + "```" + `
+
 ## Dependencies
 
 Specify resource dependencies using ` + "`depends_on`" + `:
@@ -337,9 +1073,213 @@ resources:
     # ... properties
 ` + "```" + `
 
+Every ` + "`depends_on`" + ` entry must be the ID of a declared resource. An unknown ID is reported
+with the file and line of the resource that lists it, and with the closest declared ID
+when it looks like a typo:
+
+` + "```" + `
+resource aws:ec2:subnet.private (main.yaml:12) depends on aws:ec2:vcp.main, which is not declared; did you mean aws:ec2:vpc.main?
+` + "```" + `
+
+### Resource References
+A property can read a computed attribute of another resource with
+` + "`${<kind>.<name>.<attribute>}`" + `. The attribute comes from the referenced resource's state
+after it is created or updated, and the reference adds an implicit ` + "`depends_on`" + `.
+
+` + "```yaml" + `
+resources:
+  - kind: aws:ec2:vpc
+    name: main
+    properties:
+      cidr_block: 10.0.0.0/16
+
+  - kind: aws:ec2:subnet
+    name: public
+    properties:
+      vpc_id: "${aws:ec2:vpc.main.vpc_id}"
+      cidr_block: 10.0.1.0/24
+` + "```" + `
+
+References that form a cycle are rejected.
+
+A property whose value is another resource's ID, such as ` + "`target: aws:ec2:instance.web`" + `,
+also adds an implicit ` + "`depends_on`" + `. So does an IAM role ARN that names an ` + "`aws:iam:role`" + `
+resource, anywhere in a property value (including policy documents such as
+` + "`assume_role_policy`" + `), or a ` + "`role`" + ` property set to that role's name. Dependencies are
+otherwise never inferred from resource names; list any others under ` + "`depends_on`" + `.
+
+## Outputs
+
+Outputs surface values such as a load balancer's DNS name or a bucket's ARN once a commit
+has applied the configuration, for use by other tooling. Each value may reference resource
+attributes with the same ` + "`${<kind>.<name>.<attribute>}`" + ` syntax as properties, and variables
+as anywhere else.
+
+` + "```yaml" + `
+outputs:
+  bucket_arn:
+    value: "${aws:s3:bucket.site.arn}"
+    description: ARN of the site bucket
+  site_url:
+    value: "https://${aws:s3:bucket.site.bucket}.s3.amazonaws.com"
+  db_password:
+    value: "${aws:rds:instance.db.password}"
+    sensitive: true
+` + "```" + `
+
+` + "`commit`" + ` prints outputs after its results, and includes them as ` + "`outputs`" + ` with ` + "`-o json`" + ` or
+` + "`-o yaml`" + `. Sensitive outputs are shown as ` + "`***`" + ` unless ` + "`--show-sensitive`" + ` is given. An output
+that references a resource left out by ` + "`--target`" + ` is skipped with a warning. Dry runs report
+no outputs.
+
+## Moved Resources
+
+Tag-based resources are found by their ` + "`Name`" + ` tag, so renaming one in the configuration would
+otherwise leave the old resource behind and create a new one. A ` + "`moved`" + ` block records the old
+resource ID, and ` + "`commit`" + ` renames the existing resource instead, updating its tags in place:
+
+` + "```yaml" + `
+resources:
+  - kind: aws:ec2:instance
+    name: api-server          # Was web-server
+    properties:
+      instance_type: t3.micro
+
+moved:
+  - from: aws:ec2:instance.web-server
+    to: aws:ec2:instance.api-server
+` + "```" + `
+
+` + "`preview`" + ` shows the rename as an update, along with any other changes to the resource. Once
+the resource has been renamed the block has no effect, so it can stay in the configuration
+until every environment has been committed.
+
+Only resources found by tags can be renamed: EC2 instances, EBS volumes, VPCs, subnets and
+internet gateways. Moving any other kind is an error, since its name is its identity in AWS
+and a new name means a new resource. A resource can't change kind, be moved twice or still be
+declared under the ID it was moved from. ` + "`align`" + ` reports a pending rename but leaves it to
+` + "`commit`" + `.
+
+## Notifications
+
+` + "`align`" + ` can post the drift it finds to a webhook, so that drift reaches someone while it runs
+as a service. Each alignment pass that leaves resources drifted, because their drift policy
+doesn't auto-heal them or auto-heal failed, sends one JSON ` + "`POST`" + ` to ` + "`webhook_url`" + `:
+
+` + "```yaml" + `
+notifications:
+  webhook_url: "${env('DRIFT_WEBHOOK_URL')}"
+  timeout: 5s                # How long to wait for the webhook (optional, default: 10s)
+` + "```" + `
+
+` + "```json" + `
+{
+  "project": "web",
+  "environment": "production",
+  "detected_at": "2024-05-01T12:00:00Z",
+  "resources": [
+    {
+      "id": "aws:s3:bucket.logs",
+      "status": "drifted",
+      "differences": [
+        {"property": "versioning", "path": "/versioning", "current_value": false, "desired_value": true, "drift_type": "modified"}
+      ]
+    }
+  ]
+}
+` + "```" + `
+
+A resource whose auto-heal failed has ` + "`status: error`" + ` and the failure in ` + "`error`" + `; a resource
+that doesn't exist has ` + "`missing: true`" + `. Values of sensitive properties are redacted as they are
+everywhere else. Delivery is best effort: a webhook that fails, answers with a non-2xx status or
+doesn't answer within ` + "`timeout`" + ` is logged as a warning and the pass carries on. Webhook URLs
+usually embed a token, so they are never logged.
+
+## Policies
+
+` + "`bootstrap`" + `, ` + "`validate`" + ` and ` + "`preview`" + ` evaluate the built-in policies against every resource.
+Additional rules can be loaded with ` + "`--policy-file`" + `, which takes a YAML list of rules:
+
+` + "```yaml" + `
+- name: require-environment-tag
+  description: Every resource needs an Environment tag
+  severity: error            # error, warning or info (default: warning)
+  condition: "!tags.Environment"
+  message: Environment tag is required
+  remediation: Add an Environment tag, or set it in the provider's default_tags   # optional
+  metadata:
+    category: governance
+` + "```" + `
+
+` + "`name`" + ` and ` + "`condition`" + ` are required. Error-level violations fail ` + "`bootstrap`" + ` and ` + "`validate`" + `;
+` + "`preview`" + ` reports violations without failing.
+
+A condition is an expression that describes a violation: when it evaluates to true, the rule
+is violated. It can read ` + "`resource.id`" + `, ` + "`resource.kind`" + `, ` + "`resource.name`" + `,
+` + "`resource.drift_policy`" + ` (with ` + "`auto_heal`" + ` and ` + "`notify_only`" + `, or nothing without a drift
+policy), ` + "`properties`" + ` and ` + "`tags`" + `. Missing values, ` + "`false`" + `, zero and empty strings, lists and maps count as false, so
+` + "`!tags.Environment`" + ` matches resources without an Environment tag.
+
+` + "```yaml" + `
+- name: no-large-instances
+  severity: error
+  condition: "resource.kind == 'aws:ec2:instance' && properties.instance_type in ['t3.large', 'm5.large']"
+  message: Large instance types need an exception
+` + "```" + `
+
+### Exemptions
+
+A resource that legitimately can't satisfy a rule can be exempted from it. Exempted
+violations are still reported, marked as waived, and don't fail ` + "`bootstrap`" + ` or ` + "`validate`" + `.
+Once an exemption's ` + "`expires`" + ` date has passed it is ignored and the violation counts again.
+
+` + "```yaml" + `
+exemptions:
+  - resource: aws:s3:bucket.static-site   # Resource ID
+    rule: s3-versioning-enabled           # Policy rule name
+    expires: 2026-12-31                   # Date or RFC 3339 time (optional)
+    reason: Public static site content is rebuilt from source
+` + "```" + `
+
+### Rego Policies
+
+Teams that already maintain OPA policies can use Rego instead of the built-in rules:
+
+` + "```yaml" + `
+policy:
+  engine: rego               # builtin (default) or rego
+  files:                     # .rego files or directories
+    - ./policies
+  package: runestone         # Package to query (default: runestone)
+` + "```" + `
+
+Each resource is passed as ` + "`input`" + ` with ` + "`id`" + `, ` + "`kind`" + `, ` + "`name`" + `, ` + "`properties`" + `, ` + "`tags`" + ` and
+` + "`depends_on`" + `. Every element of the package's ` + "`deny`" + ` set is an error and every element of
+` + "`warn`" + ` is a warning. An element can be a message string or an object with ` + "`msg`" + `, and
+optionally ` + "`severity`" + ` and ` + "`rule`" + `. The rule name defaults to ` + "`<package>.deny`" + ` or
+` + "`<package>.warn`" + ` and is the name exemptions refer to.
+
+` + "```rego" + `
+package runestone
+
+deny[msg] {
+  input.kind == "aws:s3:bucket"
+  not input.properties.versioning
+  msg := sprintf("bucket %s must enable versioning", [input.name])
+}
+
+warn[{"msg": "missing Environment tag", "rule": "environment-tag"}] {
+  not input.tags.Environment
+}
+` + "```" + `
+
+With the Rego engine the built-in rules are not loaded; rules from ` + "`--policy-file`" + ` and
+exemptions still apply.
+
 ## Complete Example
 
 ` + "```yaml" + `
+apiVersion: runestone/v1
 project: ecommerce-platform
 environment: production
 variables:
@@ -368,13 +1308,13 @@ resources:
 
   # Web servers
   - kind: aws:ec2:instance
-    name: web-${index}
+    name: web-${count.index}
     count: "${instance_count}"
     properties:
       instance_type: "${environment == 'prod' ? 't3.large' : 't3.micro'}"
       ami: ami-0abcdef1234567890
       tags:
-        Name: "web-${index}"
+        Name: "web-${count.index}"
         Role: web-server
         Environment: "${environment}"
     driftPolicy:
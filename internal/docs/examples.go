@@ -13,6 +13,7 @@ This document provides practical examples of Runestone configurations for common
 A simple web application with load balancer and database.
 
 ` + "```yaml" + `
+apiVersion: runestone/v1
 project: webapp
 environment: production
 variables:
@@ -37,13 +38,13 @@ resources:
 
   # Web servers
   - kind: aws:ec2:instance
-    name: web-${index}
+    name: web-${count.index}
     count: 2
     properties:
       instance_type: t3.medium
       ami: ami-0abcdef1234567890
       tags:
-        Name: "web-${index}"
+        Name: "web-${count.index}"
         Role: web-server
     driftPolicy:
       autoHeal: true
@@ -54,6 +55,7 @@ resources:
 Complete VPC setup with public and private subnets.
 
 ` + "```yaml" + `
+apiVersion: runestone/v1
 project: vpc-app
 environment: production
 variables:
@@ -130,6 +132,7 @@ resources:
 Complete serverless setup with Lambda functions and IAM roles.
 
 ` + "```yaml" + `
+apiVersion: runestone/v1
 project: serverless-app
 environment: production
 variables:
@@ -200,6 +203,7 @@ resources:
 Configuration that adapts based on environment.
 
 ` + "```yaml" + `
+apiVersion: runestone/v1
 project: api-service
 environment: "${ENV:-dev}"  # Use ENV var or default to dev
 variables:
@@ -230,13 +234,13 @@ resources:
 
   # API servers
   - kind: aws:ec2:instance
-    name: api-${index}
+    name: api-${count.index}
     count: "${instance_count}"
     properties:
       instance_type: "${instance_type}"
       ami: ami-0abcdef1234567890
       tags:
-        Name: "api-${index}"
+        Name: "api-${count.index}"
         Role: api-server
         Environment: "${environment}"
     driftPolicy:
@@ -248,6 +252,7 @@ resources:
 Deploy resources across multiple regions.
 
 ` + "```yaml" + `
+apiVersion: runestone/v1
 project: global-app
 environment: production
 variables:
@@ -267,27 +272,26 @@ providers:
 resources:
   # Regional buckets
   - kind: aws:s3:bucket
-    name: "${project}-${region}-${environment}"
+    name: "${project}-${each.value}-${environment}"
     for_each: "${regions}"
     properties:
       versioning: true
       tags:
-        Region: "${region}"
-        Primary: "${region == 'us-east-1' ? 'true' : 'false'}"
+        Region: "${each.value}"
+        Primary: "${each.value == 'us-east-1' ? 'true' : 'false'}"
     driftPolicy:
       autoHeal: true
 
   # Regional compute
   - kind: aws:ec2:instance
-    name: "${region}-app-${index}"
+    name: "${each.value}-app"
     for_each: "${regions}"
-    count: 2
     properties:
       instance_type: t3.medium
       ami: ami-0abcdef1234567890
       tags:
-        Name: "${region}-app-${index}"
-        Region: "${region}"
+        Name: "${each.value}-app"
+        Region: "${each.value}"
     driftPolicy:
       autoHeal: true
 ` + "```" + `
@@ -298,6 +302,7 @@ Different configurations for different environments.
 
 ` + "```yaml" + `
 # dev.yaml
+apiVersion: runestone/v1
 project: myapp
 environment: dev
 variables:
@@ -334,6 +339,7 @@ resources:
 
 ` + "```yaml" + `
 # prod.yaml
+apiVersion: runestone/v1
 project: myapp
 environment: prod
 variables:
@@ -357,13 +363,13 @@ resources:
       notifyOnly: false
 
   - kind: aws:ec2:instance
-    name: prod-server-${index}
+    name: prod-server-${count.index}
     count: 3  # Multiple instances for HA
     properties:
       instance_type: t3.large  # Larger instances
       ami: ami-0abcdef1234567890
       tags:
-        Name: "prod-server-${index}"
+        Name: "prod-server-${count.index}"
         Environment: prod
     driftPolicy:
       autoHeal: true
@@ -375,6 +381,7 @@ resources:
 Resources with complex dependency relationships.
 
 ` + "```yaml" + `
+apiVersion: runestone/v1
 project: microservices
 environment: production
 variables:
@@ -411,13 +418,13 @@ resources:
 
   # Microservices
   - kind: aws:ec2:instance
-    name: service-${index}
+    name: service-${count.index}
     count: 3
     properties:
       instance_type: t3.small
       ami: ami-0abcdef1234567890
       tags:
-        Name: "service-${index}"
+        Name: "service-${count.index}"
         Role: microservice
     depends_on:
       - aws:ec2:instance.api-gateway
@@ -29,6 +29,7 @@ go install github.com/ataiva-software/runestone@latest
 Create a file called ` + "`infra.yaml`" + `:
 
 ` + "```yaml" + `
+apiVersion: runestone/v1
 project: my-first-project
 environment: dev
 variables:
@@ -55,13 +56,13 @@ resources:
 
   # Multiple web servers using count
   - kind: aws:ec2:instance
-    name: web-${index}
+    name: web-${count.index}
     count: 2
     properties:
       instance_type: t3.micro
       ami: ami-0abcdef1234567890
       tags:
-        Name: "web-${index}"
+        Name: "web-${count.index}"
         Environment: "${environment}"
     driftPolicy:
       autoHeal: true
@@ -120,7 +121,7 @@ Runestone doesn't use state files. Instead, it queries your cloud providers dire
 Use ` + "`${variable}`" + ` syntax for dynamic values:
 - ` + "`${region}`" + ` - Variable substitution
 - ` + "`${environment == 'prod' ? 't3.large' : 't3.micro'}`" + ` - Conditional expressions
-- ` + "`${index}`" + ` - Loop index for count-based resources
+- ` + "`${count.index}`" + ` - Loop index for count-based resources
 
 ### Drift Detection
 Runestone continuously monitors your infrastructure and can automatically fix drift when ` + "`autoHeal: true`" + ` is set.
@@ -0,0 +1,85 @@
+package drift
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/ataiva-software/runestone/internal/providers"
+)
+
+// StateCache holds the live state of resources read during one command run, keyed by
+// resource ID, so that later steps reuse it instead of asking the provider again. A nil
+// state records that the resource doesn't exist. It is safe for concurrent use, as commit
+// applies resources in parallel.
+type StateCache struct {
+	mutex  sync.RWMutex
+	states map[string]map[string]interface{}
+}
+
+// NewStateCache creates an empty state cache
+func NewStateCache() *StateCache {
+	return &StateCache{states: make(map[string]map[string]interface{})}
+}
+
+// Get returns the cached state of a resource, and whether it has been read at all
+func (c *StateCache) Get(id string) (map[string]interface{}, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	state, cached := c.states[id]
+	return state, cached
+}
+
+// Set records the state of a resource; nil records that it doesn't exist
+func (c *StateCache) Set(id string, state map[string]interface{}) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.states[id] = state
+}
+
+// Invalidate forgets the state of resources, so the next lookup reads them again
+func (c *StateCache) Invalidate(ids ...string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, id := range ids {
+		delete(c.states, id)
+	}
+}
+
+// CurrentState returns the live state of a resource, nil if it doesn't exist. It is read
+// from the provider only when drift detection hasn't read it already in this run, or it has
+// been invalidated since.
+func (d *Detector) CurrentState(ctx context.Context, instance config.ResourceInstance) (map[string]interface{}, error) {
+	providerName := extractProviderName(instance)
+	provider, exists := d.providers[providerName]
+	if !exists {
+		return nil, fmt.Errorf("provider %s not found for resource %s", providerName, instance.ID)
+	}
+	return d.currentState(ctx, provider, instance)
+}
+
+// Invalidate forgets the cached state of resources that have just been changed, so that
+// reading them again returns their new state
+func (d *Detector) Invalidate(ids ...string) {
+	d.cache.Invalidate(ids...)
+}
+
+// currentState reads the state of a resource through the cache. Instances without an ID,
+// which only tests build, aren't cached.
+func (d *Detector) currentState(ctx context.Context, provider providers.Provider, instance config.ResourceInstance) (map[string]interface{}, error) {
+	if instance.ID != "" {
+		if state, cached := d.cache.Get(instance.ID); cached {
+			return state, nil
+		}
+	}
+
+	state, err := provider.GetCurrentState(ctx, instance)
+	if err != nil {
+		return nil, err
+	}
+	if instance.ID != "" {
+		d.cache.Set(instance.ID, state)
+	}
+	return state, nil
+}
@@ -0,0 +1,112 @@
+package drift
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/ataiva-software/runestone/internal/providers"
+	"github.com/ataiva-software/runestone/internal/providers/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateCache(t *testing.T) {
+	cache := NewStateCache()
+
+	_, cached := cache.Get("test:bucket.logs")
+	assert.False(t, cached)
+
+	// A resource known not to exist is cached too
+	cache.Set("test:bucket.logs", nil)
+	state, cached := cache.Get("test:bucket.logs")
+	assert.True(t, cached)
+	assert.Nil(t, state)
+
+	cache.Set("test:bucket.logs", map[string]interface{}{"versioning": true})
+	state, _ = cache.Get("test:bucket.logs")
+	assert.Equal(t, true, state["versioning"])
+
+	cache.Invalidate("test:bucket.logs")
+	_, cached = cache.Get("test:bucket.logs")
+	assert.False(t, cached)
+}
+
+func TestStateCache_Concurrent(t *testing.T) {
+	cache := NewStateCache()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id := fmt.Sprintf("test:bucket.b%d", i%5)
+			cache.Set(id, map[string]interface{}{"index": i})
+			cache.Get(id)
+			cache.Invalidate(id)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestDetector_ReusesCachedState(t *testing.T) {
+	ctx := context.Background()
+	testProvider := fake.New("test:bucket")
+	testProvider.SetState("test:bucket.logs", map[string]interface{}{"versioning": true})
+
+	registry := providers.NewRegistry()
+	registry.Register("test", testProvider)
+	detector := NewDetector(registry)
+
+	logs := config.ResourceInstance{ID: "test:bucket.logs", Kind: "test:bucket", Name: "logs", Properties: map[string]interface{}{"versioning": true}}
+	site := config.ResourceInstance{ID: "test:bucket.site", Kind: "test:bucket", Name: "site", Properties: map[string]interface{}{"website": true}}
+
+	_, err := detector.DetectDriftBatch(ctx, []config.ResourceInstance{logs, site})
+	require.NoError(t, err)
+	require.Len(t, testProvider.Calls(), 2)
+	testProvider.Reset()
+
+	// Later lookups in the same run, such as re-checking drift once references are
+	// resolved, reuse what was read
+	result, err := detector.DetectDrift(ctx, logs)
+	require.NoError(t, err)
+	assert.False(t, result.HasDrift)
+	state, err := detector.CurrentState(ctx, site)
+	require.NoError(t, err)
+	assert.Nil(t, state)
+	assert.Empty(t, testProvider.Calls())
+
+	// Once a resource has been changed it is read again
+	require.NoError(t, testProvider.Create(ctx, site))
+	detector.Invalidate(site.ID)
+	testProvider.Reset()
+	state, err = detector.CurrentState(ctx, site)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"website": true}, state)
+	assert.Equal(t, []fake.Call{{Operation: fake.OperationRead, ID: "test:bucket.site"}}, testProvider.Calls())
+}
+
+func TestDetector_AutoHeal_InvalidatesCachedState(t *testing.T) {
+	ctx := context.Background()
+	testProvider := fake.New("test:bucket")
+	registry := providers.NewRegistry()
+	registry.Register("test", testProvider)
+	detector := NewDetector(registry)
+
+	logs := config.ResourceInstance{
+		ID:          "test:bucket.logs",
+		Kind:        "test:bucket",
+		Name:        "logs",
+		Properties:  map[string]interface{}{"versioning": true},
+		DriftPolicy: &config.DriftPolicy{AutoHeal: true},
+	}
+	result, err := detector.DetectDrift(ctx, logs)
+	require.NoError(t, err)
+	require.NoError(t, detector.AutoHeal(ctx, logs, result))
+
+	state, err := detector.CurrentState(ctx, logs)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"versioning": true}, state)
+}
@@ -4,39 +4,115 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/ataiva-software/runestone/internal/config"
 	"github.com/ataiva-software/runestone/internal/providers"
 )
 
+// DefaultParallelism is the default number of resources inspected concurrently
+const DefaultParallelism = 10
+
 // Detector handles drift detection for resources
 type Detector struct {
-	providers map[string]providers.Provider
+	providers   map[string]providers.Provider
+	parallelism int
+	movedFrom   map[string]string // Resource IDs moved blocks move resources from, by the ID moved to
+	cache       *StateCache       // Live state read so far, shared by everything using the detector
 }
 
 // NewDetector creates a new drift detector
 func NewDetector(providerRegistry *providers.ProviderRegistry) *Detector {
 	return &Detector{
-		providers: providerRegistry.GetAll(),
+		providers:   providerRegistry.GetAll(),
+		parallelism: DefaultParallelism,
+		cache:       NewStateCache(),
+	}
+}
+
+// SetParallelism limits how many resources DetectDriftBatch inspects at once
+func (d *Detector) SetParallelism(parallelism int) {
+	if parallelism < 1 {
+		parallelism = 1
 	}
+	d.parallelism = parallelism
 }
 
-// DetectDrift detects drift for a single resource instance
+// SetMoves tells the detector about the configuration's moved blocks, which should have
+// been checked with config.ValidateMoves. A resource that doesn't exist under the ID it is
+// moved to is then looked up under the ID it is moved from, and reported as moved if found.
+func (d *Detector) SetMoves(moves []config.Moved) {
+	d.movedFrom = make(map[string]string, len(moves))
+	for _, move := range moves {
+		d.movedFrom[move.To] = move.From
+	}
+}
+
+// DetectDrift detects drift for a single resource instance, reusing its state if it has
+// already been read in this run
 func (d *Detector) DetectDrift(ctx context.Context, instance config.ResourceInstance) (*providers.DriftResult, error) {
-	// Extract provider name from resource kind (e.g., "aws:s3:bucket" -> "aws")
-	providerName := extractProviderName(instance.Kind)
+	// Find the provider managing the resource (e.g., "aws:s3:bucket" -> "aws")
+	providerName := extractProviderName(instance)
 	provider, exists := d.providers[providerName]
 	if !exists {
 		return nil, fmt.Errorf("provider %s not found for resource %s", providerName, instance.ID)
 	}
 
-	// Get current state from the provider
-	currentState, err := provider.GetCurrentState(ctx, instance)
+	movedFrom, err := d.moveSource(provider, instance)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get current state from the provider, unless it has been read already
+	currentState, err := d.currentState(ctx, provider, instance)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get current state for resource %s: %w", instance.ID, err)
 	}
 
+	if currentState == nil && movedFrom != "" {
+		return d.detectMove(ctx, provider, instance, movedFrom)
+	}
+	return d.driftFromState(provider, instance, currentState), nil
+}
+
+// moveSource returns the ID a moved block moves instance from, or "" when there is none. Moving
+// a resource its provider can't rename is an error, whether or not the move is still pending.
+func (d *Detector) moveSource(provider providers.Provider, instance config.ResourceInstance) (string, error) {
+	movedFrom := d.movedFrom[instance.ID]
+	if movedFrom == "" {
+		return "", nil
+	}
+	if renamer, ok := provider.(providers.Renamer); !ok || !renamer.CanRename(instance.Kind) {
+		return "", fmt.Errorf("can't move %s to %s: %s resources can't be renamed in place, since their name identifies them; remove the moved block to replace the resource", movedFrom, instance.ID, instance.Kind)
+	}
+	return movedFrom, nil
+}
+
+// detectMove looks up a resource that doesn't exist as instance under the ID it is moved
+// from. A resource found there is compared with instance's configuration and reported as
+// moved; otherwise instance is reported as missing.
+func (d *Detector) detectMove(ctx context.Context, provider providers.Provider, instance config.ResourceInstance, movedFrom string) (*providers.DriftResult, error) {
+	previousState, err := d.currentState(ctx, provider, instance.Renamed(movedFrom))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current state for resource %s: %w", movedFrom, err)
+	}
+	if previousState == nil {
+		return d.driftFromState(provider, instance, nil), nil
+	}
+
+	result := d.driftFromState(provider, instance, previousState)
+	result.HasDrift = true
+	result.Changes = append([]string{fmt.Sprintf("Resource was moved from %s", movedFrom)}, result.Changes...)
+	result.MovedFrom = movedFrom
+	return result, nil
+}
+
+// driftFromState compares the current state of a resource, nil if it doesn't exist, with
+// its configuration
+func (d *Detector) driftFromState(provider providers.Provider, instance config.ResourceInstance, currentState map[string]interface{}) *providers.DriftResult {
 	// If resource doesn't exist, it's a drift (should be created)
 	if currentState == nil {
 		return &providers.DriftResult{
@@ -45,12 +121,26 @@ func (d *Detector) DetectDrift(ctx context.Context, instance config.ResourceInst
 			Differences:  map[string]providers.DriftDifference{},
 			CurrentState: nil,
 			DesiredState: instance.Properties,
-		}, nil
+		}
 	}
 
-	// Compare current state with desired state
-	differences := d.compareStates(currentState, instance.Properties)
-	changes := d.differencesToChanges(differences)
+	// Compare current state with desired state, leaving out properties whose changes are ignored
+	compared, desired := currentState, instance.Properties
+	if instance.DriftPolicy != nil {
+		for _, path := range instance.DriftPolicy.IgnoreChanges {
+			compared = withoutPath(compared, strings.Split(path, "."))
+			desired = withoutPath(desired, strings.Split(path, "."))
+		}
+	}
+	// Secrets are read only at commit, so their references can't be compared with live values
+	for _, path := range config.SecretPaths(desired) {
+		compared = withoutPath(compared, path)
+		desired = withoutPath(desired, path)
+	}
+	compared = withoutIgnoredTags(compared, desired, d.ignoredTagPrefixesFor(provider))
+	differences := d.compareStates(compared, desired, d.metadataFieldsFor(provider, instance.Kind))
+	redactDifferences(differences, d.sensitiveFieldsFor(provider, instance))
+	changes := DifferencesToChanges(differences)
 
 	return &providers.DriftResult{
 		HasDrift:     len(differences) > 0,
@@ -58,25 +148,95 @@ func (d *Detector) DetectDrift(ctx context.Context, instance config.ResourceInst
 		Differences:  differences,
 		CurrentState: currentState,
 		DesiredState: instance.Properties,
-	}, nil
+	}
 }
 
-// DetectDriftBatch detects drift for multiple resource instances
+// DetectDriftBatch detects drift for multiple resource instances. Resources whose provider
+// can look them up in bulk are fetched with one GetCurrentStateBatch call per provider; the
+// rest are inspected concurrently, up to the configured parallelism. The states read are
+// cached, so later lookups of the same resources in this run don't call the provider again.
 func (d *Detector) DetectDriftBatch(ctx context.Context, instances []config.ResourceInstance) (map[string]*providers.DriftResult, error) {
 	results := make(map[string]*providers.DriftResult)
 
+	var providerNames []string
+	batches := make(map[string][]config.ResourceInstance)
+	var remaining []config.ResourceInstance
 	for _, instance := range instances {
-		result, err := d.DetectDrift(ctx, instance)
+		// Moved resources are looked up one by one, since they may need a second lookup
+		providerName := extractProviderName(instance)
+		if batcher, ok := d.providers[providerName].(providers.BatchStateProvider); ok && batcher.SupportsBatchState(instance.Kind) && d.movedFrom[instance.ID] == "" {
+			if _, seen := batches[providerName]; !seen {
+				providerNames = append(providerNames, providerName)
+			}
+			batches[providerName] = append(batches[providerName], instance)
+			continue
+		}
+		remaining = append(remaining, instance)
+	}
+
+	for _, providerName := range providerNames {
+		provider := d.providers[providerName]
+		states, err := provider.(providers.BatchStateProvider).GetCurrentStateBatch(ctx, batches[providerName])
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current state of %s resources: %w", providerName, err)
+		}
+		for _, instance := range batches[providerName] {
+			if instance.ID != "" {
+				d.cache.Set(instance.ID, states[instance.ID])
+			}
+			results[instance.ID] = d.driftFromState(provider, instance, states[instance.ID])
+		}
+	}
+
+	errs := make([]error, len(remaining))
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, d.parallelism)
+
+	for i, instance := range remaining {
+		wg.Add(1)
+		go func(i int, instance config.ResourceInstance) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			result, err := d.DetectDrift(ctx, instance)
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to detect drift for resource %s: %w", instance.ID, err)
+				return
+			}
+
+			mutex.Lock()
+			results[instance.ID] = result
+			mutex.Unlock()
+		}(i, instance)
+	}
+	wg.Wait()
+
+	// Report the first failure in configuration order so errors are deterministic
+	for _, err := range errs {
 		if err != nil {
-			return nil, fmt.Errorf("failed to detect drift for resource %s: %w", instance.ID, err)
+			return nil, err
 		}
-		results[instance.ID] = result
 	}
 
 	return results, nil
 }
 
-// AutoHeal attempts to automatically heal drift for resources with auto-heal enabled
+// HealSkippedError is returned by AutoHeal when a resource's drift policy refuses to heal
+// its drift, leaving the resource as it is
+type HealSkippedError struct {
+	Reason string
+}
+
+func (e *HealSkippedError) Error() string {
+	return "auto-heal skipped: " + e.Reason
+}
+
+// AutoHeal attempts to automatically heal drift for resources with auto-heal enabled. Drift
+// the policy's heal_drift_types or max_changes rule out is left alone and reported as a
+// *HealSkippedError.
 func (d *Detector) AutoHeal(ctx context.Context, instance config.ResourceInstance, driftResult *providers.DriftResult) error {
 	// Check if auto-heal is enabled for this resource
 	if instance.DriftPolicy == nil || !instance.DriftPolicy.AutoHeal {
@@ -84,8 +244,12 @@ func (d *Detector) AutoHeal(ctx context.Context, instance config.ResourceInstanc
 		return nil
 	}
 
-	// Extract provider name from resource kind
-	providerName := extractProviderName(instance.Kind)
+	if reason := healRefusal(instance.DriftPolicy, driftResult); reason != "" {
+		return &HealSkippedError{Reason: reason}
+	}
+
+	// Find the provider managing the resource
+	providerName := extractProviderName(instance)
 	provider, exists := d.providers[providerName]
 	if !exists {
 		return fmt.Errorf("provider %s not found for resource %s", providerName, instance.ID)
@@ -93,19 +257,60 @@ func (d *Detector) AutoHeal(ctx context.Context, instance config.ResourceInstanc
 
 	// If resource doesn't exist, create it
 	if driftResult.CurrentState == nil {
-		return provider.Create(ctx, instance)
+		defer d.Invalidate(instance.ID)
+		return providers.RunWithTimeout(ctx, instance, config.ChangeTypeCreate, func(ctx context.Context) error {
+			return provider.Create(ctx, instance)
+		})
 	}
 
 	// If resource exists but has drift, update it
 	if driftResult.HasDrift {
-		return provider.Update(ctx, instance, driftResult.CurrentState)
+		defer d.Invalidate(instance.ID)
+		return providers.RunWithTimeout(ctx, instance, config.ChangeTypeUpdate, func(ctx context.Context) error {
+			return provider.Update(ctx, instance, driftResult.CurrentState)
+		})
 	}
 
 	return nil
 }
 
-// compareStates compares current state with desired state and returns differences
-func (d *Detector) compareStates(current, desired map[string]interface{}) map[string]providers.DriftDifference {
+// healRefusal returns why policy doesn't allow driftResult to be healed, or "" when it does
+func healRefusal(policy *config.DriftPolicy, driftResult *providers.DriftResult) string {
+	// Renaming is left to commit, which reviews it with the rest of the configuration change
+	if driftResult.MovedFrom != "" {
+		return fmt.Sprintf("the resource was moved from %s; run commit to rename it", driftResult.MovedFrom)
+	}
+
+	if driftResult.CurrentState == nil {
+		if !policy.Heals(config.DriftTypeMissing) {
+			return "the resource does not exist and heal_drift_types doesn't include missing"
+		}
+		return ""
+	}
+
+	if policy.MaxChanges > 0 && len(driftResult.Differences) > policy.MaxChanges {
+		return fmt.Sprintf("%d properties differ, more than max_changes (%d)", len(driftResult.Differences), policy.MaxChanges)
+	}
+
+	properties := make([]string, 0, len(driftResult.Differences))
+	for property := range driftResult.Differences {
+		properties = append(properties, property)
+	}
+	sort.Strings(properties)
+	for _, property := range properties {
+		driftType := string(driftResult.Differences[property].DriftType)
+		if !policy.Heals(driftType) {
+			return fmt.Sprintf("%s is %s and heal_drift_types doesn't include %s", property, driftType, driftType)
+		}
+	}
+
+	return ""
+}
+
+// compareStates compares current state with desired state and returns differences.
+// Metadata fields are ignored when they appear only in the current state. Differences
+// inside maps and lists are reported by path, such as tags.Environment or ports[0].port.
+func (d *Detector) compareStates(current, desired map[string]interface{}, metadataFields []string) map[string]providers.DriftDifference {
 	differences := make(map[string]providers.DriftDifference)
 
 	// Check for properties that exist in desired but not in current (added)
@@ -114,6 +319,7 @@ func (d *Detector) compareStates(current, desired map[string]interface{}) map[st
 		if !exists {
 			differences[key] = providers.DriftDifference{
 				Property:     key,
+				Path:         pointerTo("", key),
 				CurrentValue: nil,
 				DesiredValue: desiredValue,
 				DriftType:    providers.DriftTypeAdded,
@@ -122,26 +328,20 @@ func (d *Detector) compareStates(current, desired map[string]interface{}) map[st
 		}
 
 		// Check if values are different (modified)
-		if !d.valuesEqual(currentValue, desiredValue) {
-			differences[key] = providers.DriftDifference{
-				Property:     key,
-				CurrentValue: currentValue,
-				DesiredValue: desiredValue,
-				DriftType:    providers.DriftTypeModified,
-			}
-		}
+		d.diffValues(key, pointerTo("", key), currentValue, desiredValue, differences)
 	}
 
 	// Check for properties that exist in current but not in desired (removed)
 	for key, currentValue := range current {
 		if _, exists := desired[key]; !exists {
 			// Skip certain metadata fields that shouldn't be considered drift
-			if d.isMetadataField(key) {
+			if d.isMetadataField(key, metadataFields) {
 				continue
 			}
 
 			differences[key] = providers.DriftDifference{
 				Property:     key,
+				Path:         pointerTo("", key),
 				CurrentValue: currentValue,
 				DesiredValue: nil,
 				DriftType:    providers.DriftTypeRemoved,
@@ -152,8 +352,124 @@ func (d *Detector) compareStates(current, desired map[string]interface{}) map[st
 	return differences
 }
 
-// differencesToChanges converts differences to human-readable change descriptions
-func (d *Detector) differencesToChanges(differences map[string]providers.DriftDifference) []string {
+// diffValues records the differences between a current and desired value at a property
+// path, given both dotted and as a JSON Pointer. Maps are compared key by key and lists
+// element by element, so only the nested values that changed are reported; other values
+// that differ are reported as a whole.
+func (d *Detector) diffValues(path, pointer string, current, desired interface{}, differences map[string]providers.DriftDifference) {
+	current, desired = indirect(current), indirect(desired)
+	if d.valuesEqual(current, desired) {
+		return
+	}
+
+	currentValue, desiredValue := reflect.ValueOf(current), reflect.ValueOf(desired)
+
+	if current != nil && desired != nil && isStringMap(currentValue) && isStringMap(desiredValue) {
+		for _, key := range desiredValue.MapKeys() {
+			nestedPath, nestedPointer := path+"."+key.String(), pointerTo(pointer, key.String())
+			currentElem := currentValue.MapIndex(reflect.ValueOf(key.String()).Convert(currentValue.Type().Key()))
+			if !currentElem.IsValid() {
+				differences[nestedPath] = providers.DriftDifference{
+					Property:     nestedPath,
+					Path:         nestedPointer,
+					CurrentValue: nil,
+					DesiredValue: desiredValue.MapIndex(key).Interface(),
+					DriftType:    providers.DriftTypeAdded,
+				}
+				continue
+			}
+			d.diffValues(nestedPath, nestedPointer, currentElem.Interface(), desiredValue.MapIndex(key).Interface(), differences)
+		}
+		for _, key := range currentValue.MapKeys() {
+			desiredElem := desiredValue.MapIndex(reflect.ValueOf(key.String()).Convert(desiredValue.Type().Key()))
+			if !desiredElem.IsValid() {
+				nestedPath := path + "." + key.String()
+				differences[nestedPath] = providers.DriftDifference{
+					Property:     nestedPath,
+					Path:         pointerTo(pointer, key.String()),
+					CurrentValue: currentValue.MapIndex(key).Interface(),
+					DesiredValue: nil,
+					DriftType:    providers.DriftTypeRemoved,
+				}
+			}
+		}
+		return
+	}
+
+	// Lists are compared by position; elements past the end of the shorter list are added
+	// or removed
+	if current != nil && desired != nil && isList(currentValue) && isList(desiredValue) {
+		for i := 0; i < currentValue.Len() || i < desiredValue.Len(); i++ {
+			nestedPath, nestedPointer := fmt.Sprintf("%s[%d]", path, i), pointerTo(pointer, strconv.Itoa(i))
+			switch {
+			case i >= currentValue.Len():
+				differences[nestedPath] = providers.DriftDifference{
+					Property:     nestedPath,
+					Path:         nestedPointer,
+					CurrentValue: nil,
+					DesiredValue: desiredValue.Index(i).Interface(),
+					DriftType:    providers.DriftTypeAdded,
+				}
+			case i >= desiredValue.Len():
+				differences[nestedPath] = providers.DriftDifference{
+					Property:     nestedPath,
+					Path:         nestedPointer,
+					CurrentValue: currentValue.Index(i).Interface(),
+					DesiredValue: nil,
+					DriftType:    providers.DriftTypeRemoved,
+				}
+			default:
+				d.diffValues(nestedPath, nestedPointer, currentValue.Index(i).Interface(), desiredValue.Index(i).Interface(), differences)
+			}
+		}
+		return
+	}
+
+	differences[path] = providers.DriftDifference{
+		Property:     path,
+		Path:         pointer,
+		CurrentValue: current,
+		DesiredValue: desired,
+		DriftType:    providers.DriftTypeModified,
+	}
+}
+
+// pointerEscaper escapes a key for use as a JSON Pointer reference token
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// pointerTo returns the JSON Pointer to key within the value at pointer
+func pointerTo(pointer, key string) string {
+	return pointer + "/" + pointerEscaper.Replace(key)
+}
+
+// withoutPath returns a copy of a state with the property at a dotted path removed. Maps
+// along the path are copied so the original state is left unchanged.
+func withoutPath(state map[string]interface{}, path []string) map[string]interface{} {
+	value, exists := state[path[0]]
+	if !exists {
+		return state
+	}
+
+	result := make(map[string]interface{}, len(state))
+	for key, v := range state {
+		result[key] = v
+	}
+
+	if len(path) == 1 {
+		delete(result, path[0])
+		return result
+	}
+
+	nested, ok := value.(map[string]interface{})
+	if !ok {
+		return state
+	}
+	result[path[0]] = withoutPath(nested, path[1:])
+	return result
+}
+
+// DifferencesToChanges converts differences to human-readable change descriptions
+func DifferencesToChanges(differences map[string]providers.DriftDifference) []string {
 	var changes []string
 	
 	for _, diff := range differences {
@@ -170,8 +486,12 @@ func (d *Detector) differencesToChanges(differences map[string]providers.DriftDi
 	return changes
 }
 
-// valuesEqual compares two values for equality, handling different types appropriately
+// valuesEqual compares two values for equality. Scalars are compared after normalizing
+// numbers, booleans and strings, since cloud APIs often return tag values and numbers as
+// strings; maps are compared key by key and slices element by element.
 func (d *Detector) valuesEqual(current, desired interface{}) bool {
+	current, desired = indirect(current), indirect(desired)
+
 	// Handle nil values
 	if current == nil && desired == nil {
 		return true
@@ -180,25 +500,194 @@ func (d *Detector) valuesEqual(current, desired interface{}) bool {
 		return false
 	}
 
-	// Use reflection for deep comparison
+	if currentScalar, ok := normalizeScalar(current); ok {
+		desiredScalar, ok := normalizeScalar(desired)
+		return ok && (currentScalar == desiredScalar || coercedEqual(current, desired))
+	}
+
+	currentValue, desiredValue := reflect.ValueOf(current), reflect.ValueOf(desired)
+
+	if isStringMap(currentValue) && isStringMap(desiredValue) {
+		if currentValue.Len() != desiredValue.Len() {
+			return false
+		}
+		for _, key := range currentValue.MapKeys() {
+			desiredElem := desiredValue.MapIndex(reflect.ValueOf(key.String()).Convert(desiredValue.Type().Key()))
+			if !desiredElem.IsValid() || !d.valuesEqual(currentValue.MapIndex(key).Interface(), desiredElem.Interface()) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if isList(currentValue) && isList(desiredValue) {
+		if currentValue.Len() != desiredValue.Len() {
+			return false
+		}
+		for i := 0; i < currentValue.Len(); i++ {
+			if !d.valuesEqual(currentValue.Index(i).Interface(), desiredValue.Index(i).Interface()) {
+				return false
+			}
+		}
+		return true
+	}
+
 	return reflect.DeepEqual(current, desired)
 }
 
-// isMetadataField checks if a field is metadata that shouldn't be considered for drift
-func (d *Detector) isMetadataField(fieldName string) bool {
-	metadataFields := []string{
-		"arn",
-		"id",
-		"creation_date",
-		"last_modified",
-		"status",
-		"state",
-		"availability_zone",
-		"instance_id",
-		"vpc_id",
-		"subnet_id",
+// indirect returns the value a pointer points to, such as the *int32 fields of AWS SDK
+// types, or nil for a nil pointer. Other values are returned as they are.
+func indirect(value interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
 	}
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Interface()
+}
 
+// normalizeScalar renders a string, boolean or number in a canonical string form, so
+// that 20, 20.0 and "20" compare equal. Other values that implement fmt.Stringer, such
+// as net.IP, are rendered with String.
+func normalizeScalar(value interface{}) (string, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), true
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), true
+	default:
+		if stringer, ok := value.(fmt.Stringer); ok {
+			return stringer.String(), true
+		}
+		return "", false
+	}
+}
+
+// coercedEqual compares a string with a boolean or number whose canonical forms differ
+// only in spelling, such as "True" and true or "20.0" and 20. Strings that don't parse as
+// the other value's type are never equal to it.
+func coercedEqual(current, desired interface{}) bool {
+	currentValue, desiredValue := reflect.ValueOf(current), reflect.ValueOf(desired)
+	if currentValue.Kind() == reflect.String {
+		currentValue, desiredValue = desiredValue, currentValue
+	}
+	if desiredValue.Kind() != reflect.String {
+		return false
+	}
+	text := desiredValue.String()
+
+	switch currentValue.Kind() {
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(text)
+		return err == nil && parsed == currentValue.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if parsed, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return parsed == currentValue.Int()
+		}
+		parsed, err := strconv.ParseFloat(text, 64)
+		return err == nil && parsed == float64(currentValue.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if parsed, err := strconv.ParseUint(text, 10, 64); err == nil {
+			return parsed == currentValue.Uint()
+		}
+		parsed, err := strconv.ParseFloat(text, 64)
+		return err == nil && parsed == float64(currentValue.Uint())
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(text, currentValue.Type().Bits())
+		return err == nil && parsed == currentValue.Float()
+	default:
+		return false
+	}
+}
+
+func isStringMap(v reflect.Value) bool {
+	return v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String
+}
+
+func isList(v reflect.Value) bool {
+	return v.Kind() == reflect.Slice || v.Kind() == reflect.Array
+}
+
+// defaultMetadataFields are treated as computed for providers that don't declare their
+// own computed fields
+var defaultMetadataFields = []string{
+	"arn",
+	"id",
+	"creation_date",
+	"last_modified",
+	"status",
+	"state",
+	"availability_zone",
+	"instance_id",
+	"vpc_id",
+	"subnet_id",
+}
+
+// metadataFieldsFor returns the computed state properties of a resource kind, as declared
+// by its provider or, failing that, the default list
+func (d *Detector) metadataFieldsFor(provider providers.Provider, kind string) []string {
+	if computed, ok := provider.(providers.ComputedFieldsProvider); ok {
+		return computed.GetComputedFields(kind)
+	}
+	return defaultMetadataFields
+}
+
+// ignoredTagPrefixesFor returns the prefixes of tags the cloud adds, as declared by the
+// provider. Providers that don't declare any have every tag compared.
+func (d *Detector) ignoredTagPrefixesFor(provider providers.Provider) []string {
+	if ignored, ok := provider.(providers.IgnoredTagPrefixesProvider); ok {
+		return ignored.GetIgnoredTagPrefixes()
+	}
+	return nil
+}
+
+// withoutIgnoredTags returns the current state without the tags whose keys start with one
+// of prefixes, unless the desired state declares them. Tags are nested under the tags
+// property, so unlike metadata fields they can't be skipped by property name.
+func withoutIgnoredTags(current, desired map[string]interface{}, prefixes []string) map[string]interface{} {
+	currentTags, ok := current["tags"].(map[string]interface{})
+	if !ok || len(prefixes) == 0 {
+		return current
+	}
+	declared, _ := desired["tags"].(map[string]interface{})
+
+	tags := make(map[string]interface{}, len(currentTags))
+	for key, value := range currentTags {
+		if _, isDeclared := declared[key]; !isDeclared && providers.HasTagPrefix(key, prefixes) {
+			continue
+		}
+		tags[key] = value
+	}
+	if len(tags) == len(currentTags) {
+		return current
+	}
+
+	result := make(map[string]interface{}, len(current))
+	for key, value := range current {
+		result[key] = value
+	}
+	if len(tags) == 0 && desired["tags"] == nil {
+		delete(result, "tags")
+	} else {
+		result["tags"] = tags
+	}
+	return result
+}
+
+// isMetadataField checks if a field is metadata that shouldn't be considered for drift
+func (d *Detector) isMetadataField(fieldName string, metadataFields []string) bool {
 	for _, field := range metadataFields {
 		if fieldName == field {
 			return true
@@ -208,13 +697,10 @@ func (d *Detector) isMetadataField(fieldName string) bool {
 	return false
 }
 
-// extractProviderName extracts the provider name from a resource kind
-func extractProviderName(kind string) string {
-	parts := strings.Split(kind, ":")
-	if len(parts) > 0 {
-		return parts[0]
-	}
-	return ""
+// extractProviderName returns the providers entry that manages an instance, which is an
+// alias such as aws.eu-west-1 when the instance selects one
+func extractProviderName(instance config.ResourceInstance) string {
+	return providers.ProviderKey(instance)
 }
 
 // DriftSummary represents a summary of drift detection results
@@ -2,12 +2,21 @@ package drift
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net"
 	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/ataiva-software/runestone/internal/config"
 	"github.com/ataiva-software/runestone/internal/providers"
 	"github.com/ataiva-software/runestone/internal/providers/aws"
+	"github.com/ataiva-software/runestone/internal/providers/fake"
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -79,10 +88,7 @@ func TestDetector_DetectDrift_Integration(t *testing.T) {
 }
 
 func TestDetector_DetectDrift_Unit(t *testing.T) {
-	// Create a test provider that implements the interface without mocks
-	testProvider := &TestProvider{
-		states: make(map[string]map[string]interface{}),
-	}
+	testProvider := fake.New("test:resource:type")
 
 	registry := providers.NewRegistry()
 	registry.Register("test", testProvider)
@@ -180,12 +186,8 @@ func TestDetector_DetectDrift_Unit(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Set up the test provider's state
-			if tt.currentState != nil {
-				testProvider.states[tt.instance.Name] = tt.currentState
-			} else {
-				delete(testProvider.states, tt.instance.Name)
-			}
+			// Set up the test provider's state; a nil state removes the resource
+			testProvider.SetState("test:resource:type."+tt.instance.Name, tt.currentState)
 
 			result, err := detector.DetectDrift(ctx, tt.instance)
 			require.NoError(t, err, "DetectDrift should not return an error")
@@ -201,9 +203,7 @@ func TestDetector_DetectDrift_Unit(t *testing.T) {
 }
 
 func TestDetector_AutoHeal_Unit(t *testing.T) {
-	testProvider := &TestProvider{
-		states: make(map[string]map[string]interface{}),
-	}
+	testProvider := fake.New("test:resource:type")
 
 	registry := providers.NewRegistry()
 	registry.Register("test", testProvider)
@@ -309,25 +309,84 @@ func TestDetector_AutoHeal_Unit(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Reset provider state for each test
-			testProvider.createCalled = false
-			testProvider.updateCalled = false
-			testProvider.states = make(map[string]map[string]interface{})
+			// The provider's state matches what drift detection found
+			testProvider.SetState("test:resource:type."+tt.instance.Name, tt.driftResult.CurrentState)
+			testProvider.Reset()
 
 			err := detector.AutoHeal(ctx, tt.instance, tt.driftResult)
 			require.NoError(t, err, "AutoHeal should not return an error")
 
+			created, updated := testProvider.Created(), testProvider.Updated()
 			if tt.expectAction {
-				actionTaken := testProvider.createCalled || testProvider.updateCalled
-				assert.True(t, actionTaken, "Expected an action to be taken: %s", tt.description)
+				assert.True(t, len(created)+len(updated) > 0, "Expected an action to be taken: %s", tt.description)
 			} else {
-				assert.False(t, testProvider.createCalled, "Expected no create action: %s", tt.description)
-				assert.False(t, testProvider.updateCalled, "Expected no update action: %s", tt.description)
+				assert.Empty(t, created, "Expected no create action: %s", tt.description)
+				assert.Empty(t, updated, "Expected no update action: %s", tt.description)
 			}
 		})
 	}
 }
 
+func TestDetector_AutoHeal_DriftPolicyLimits(t *testing.T) {
+	testProvider := fake.New("test:resource:type")
+	registry := providers.NewRegistry()
+	registry.Register("test", testProvider)
+	detector := NewDetector(registry)
+
+	modified := &providers.DriftResult{
+		HasDrift:     true,
+		CurrentState: map[string]interface{}{"size": 10, "tier": "gold"},
+		Differences: map[string]providers.DriftDifference{
+			"size": {Property: "size", CurrentValue: 10, DesiredValue: 20, DriftType: providers.DriftTypeModified},
+			"tier": {Property: "tier", CurrentValue: "gold", DesiredValue: "silver", DriftType: providers.DriftTypeModified},
+		},
+	}
+	missing := &providers.DriftResult{HasDrift: true}
+
+	tests := []struct {
+		name        string
+		policy      config.DriftPolicy
+		driftResult *providers.DriftResult
+		skipped     string
+	}{
+		{name: "healed type", policy: config.DriftPolicy{HealDriftTypes: []string{"modified"}}, driftResult: modified},
+		{name: "missing not healed", policy: config.DriftPolicy{HealDriftTypes: []string{"modified"}}, driftResult: missing, skipped: "the resource does not exist"},
+		{name: "missing healed", policy: config.DriftPolicy{HealDriftTypes: []string{"missing"}}, driftResult: missing},
+		{name: "type not healed", policy: config.DriftPolicy{HealDriftTypes: []string{"added", "removed"}}, driftResult: modified, skipped: "size is modified"},
+		{name: "within max changes", policy: config.DriftPolicy{MaxChanges: 2}, driftResult: modified},
+		{name: "over max changes", policy: config.DriftPolicy{MaxChanges: 1}, driftResult: modified, skipped: "2 properties differ, more than max_changes (1)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testProvider.SetState("test:resource:type.db", tt.driftResult.CurrentState)
+			testProvider.Reset()
+
+			policy := tt.policy
+			policy.AutoHeal = true
+			err := detector.AutoHeal(context.Background(), config.ResourceInstance{
+				Kind:        "test:resource:type",
+				Name:        "db",
+				DriftPolicy: &policy,
+			}, tt.driftResult)
+
+			if tt.skipped == "" {
+				require.NoError(t, err)
+				if tt.driftResult.CurrentState == nil {
+					assert.Equal(t, []string{"test:resource:type.db"}, testProvider.Created())
+				} else {
+					assert.Equal(t, []string{"test:resource:type.db"}, testProvider.Updated())
+				}
+				return
+			}
+			var skipped *HealSkippedError
+			require.ErrorAs(t, err, &skipped)
+			assert.Contains(t, skipped.Reason, tt.skipped)
+			assert.Empty(t, testProvider.Calls(), "a skipped heal changes nothing")
+		})
+	}
+}
+
 func TestDetector_compareStates(t *testing.T) {
 	detector := &Detector{}
 
@@ -393,7 +452,7 @@ func TestDetector_compareStates(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			differences := detector.compareStates(tt.desired, tt.current)
+			differences := detector.compareStates(tt.desired, tt.current, defaultMetadataFields)
 			hasDrift := len(differences) > 0
 
 			assert.Equal(t, tt.expectDrift, hasDrift, 
@@ -402,6 +461,225 @@ func TestDetector_compareStates(t *testing.T) {
 	}
 }
 
+func TestDetector_compareStates_NestedPaths(t *testing.T) {
+	detector := &Detector{}
+
+	current := map[string]interface{}{
+		"tags": map[string]interface{}{
+			"Environment": "staging",
+			"Team":        "platform",
+			"Temporary":   "yes",
+		},
+		"parameter_group": map[string]interface{}{
+			"settings": map[string]interface{}{"max_connections": "100"},
+		},
+		"ports": []interface{}{
+			map[string]interface{}{"port": 80, "protocol": "TCP"},
+			map[string]interface{}{"port": 443, "protocol": "TCP"},
+		},
+		"subnets": []string{"subnet-a"},
+	}
+	desired := map[string]interface{}{
+		"tags": map[string]interface{}{
+			"Environment": "production",
+			"Team":        "platform",
+			"Owner":       "sre",
+		},
+		"parameter_group": map[string]interface{}{
+			"settings": map[string]interface{}{"max_connections": 200},
+		},
+		"ports": []interface{}{
+			map[string]interface{}{"port": 80, "protocol": "TCP"},
+			map[string]interface{}{"port": 8443, "protocol": "TCP"},
+		},
+		"subnets": []interface{}{"subnet-a", "subnet-b"},
+	}
+
+	differences := detector.compareStates(current, desired, defaultMetadataFields)
+
+	assert.Equal(t, map[string]providers.DriftDifference{
+		"tags.Environment": {
+			Property:     "tags.Environment",
+			Path:         "/tags/Environment",
+			CurrentValue: "staging",
+			DesiredValue: "production",
+			DriftType:    providers.DriftTypeModified,
+		},
+		"tags.Owner": {
+			Property:     "tags.Owner",
+			Path:         "/tags/Owner",
+			DesiredValue: "sre",
+			DriftType:    providers.DriftTypeAdded,
+		},
+		"tags.Temporary": {
+			Property:     "tags.Temporary",
+			Path:         "/tags/Temporary",
+			CurrentValue: "yes",
+			DriftType:    providers.DriftTypeRemoved,
+		},
+		"parameter_group.settings.max_connections": {
+			Property:     "parameter_group.settings.max_connections",
+			Path:         "/parameter_group/settings/max_connections",
+			CurrentValue: "100",
+			DesiredValue: 200,
+			DriftType:    providers.DriftTypeModified,
+		},
+		"ports[1].port": {
+			Property:     "ports[1].port",
+			Path:         "/ports/1/port",
+			CurrentValue: 443,
+			DesiredValue: 8443,
+			DriftType:    providers.DriftTypeModified,
+		},
+		"subnets[1]": {
+			Property:     "subnets[1]",
+			Path:         "/subnets/1",
+			DesiredValue: "subnet-b",
+			DriftType:    providers.DriftTypeAdded,
+		},
+	}, differences)
+}
+
+func TestDetector_compareStates_PointerPaths(t *testing.T) {
+	detector := &Detector{}
+
+	differences := detector.compareStates(
+		map[string]interface{}{"tags": map[string]interface{}{"kubernetes.io/cluster/web": "owned", "a~b": "1"}},
+		map[string]interface{}{"tags": map[string]interface{}{"kubernetes.io/cluster/web": "shared", "a~b": "1"}, "port": 80},
+		defaultMetadataFields,
+	)
+
+	// Keys containing dots, slashes and tildes are escaped as RFC 6901 requires
+	require.Len(t, differences, 2)
+	assert.Equal(t, "/tags/kubernetes.io~1cluster~1web", differences["tags.kubernetes.io/cluster/web"].Path)
+	assert.Equal(t, "/port", differences["port"].Path)
+	assert.Equal(t, "/a~0b", pointerTo("", "a~b"))
+}
+
+func TestDetector_compareStates_MismatchedTypes(t *testing.T) {
+	detector := &Detector{}
+
+	differences := detector.compareStates(
+		map[string]interface{}{"tags": "none"},
+		map[string]interface{}{"tags": map[string]interface{}{"Team": "platform"}},
+		defaultMetadataFields,
+	)
+
+	// A value that isn't a map on both sides is reported as a whole
+	require.Len(t, differences, 1)
+	assert.Equal(t, providers.DriftTypeModified, differences["tags"].DriftType)
+}
+
+func TestDetector_valuesEqual(t *testing.T) {
+	detector := &Detector{}
+
+	tests := []struct {
+		name    string
+		current interface{}
+		desired interface{}
+		equal   bool
+	}{
+		{name: "int and numeric string", current: "20", desired: 20, equal: true},
+		{name: "int32 and int", current: int32(20), desired: 20, equal: true},
+		{name: "whole float and int", current: 20.0, desired: 20, equal: true},
+		{name: "bool and string", current: "true", desired: true, equal: true},
+		{name: "different numbers", current: "21", desired: 20, equal: false},
+		{name: "number and map", current: 20, desired: map[string]interface{}{}, equal: false},
+		// Configuration values as YAML decodes them against state values as AWS SDKs return them
+		{name: "int32 pointer and int", current: awssdk.Int32(20), desired: 20, equal: true},
+		{name: "int64 pointer and int", current: awssdk.Int64(3600), desired: 3600, equal: true},
+		{name: "bool pointer and bool", current: awssdk.Bool(false), desired: false, equal: true},
+		{name: "string pointer and string", current: awssdk.String("gp3"), desired: "gp3", equal: true},
+		{name: "nil pointer and nil", current: (*int32)(nil), desired: nil, equal: true},
+		{name: "nil pointer and int", current: (*int32)(nil), desired: 0, equal: false},
+		{name: "float64 and int", current: float64(8080), desired: 8080, equal: true},
+		{name: "float32 and float64", current: float32(0.5), desired: 0.5, equal: true},
+		{name: "uint and int", current: uint16(443), desired: 443, equal: true},
+		{name: "enum and string", current: rdstypes.ReplicaMode("open-read-only"), desired: "open-read-only", equal: true},
+		{name: "capitalized bool string", current: "True", desired: true, equal: true},
+		{name: "numeric bool string", current: "0", desired: false, equal: true},
+		{name: "bool string that doesn't parse", current: "yes", desired: true, equal: false},
+		{name: "float string and int", current: "20.0", desired: 20, equal: true},
+		{name: "exponent string and float", current: "1e3", desired: 1000.0, equal: true},
+		{name: "large int string", current: "9007199254740993", desired: int64(9007199254740992), equal: false},
+		{name: "string and different float", current: "20.5", desired: 20, equal: false},
+		{name: "stringer and string", current: net.ParseIP("10.0.0.1"), desired: "10.0.0.1", equal: true},
+		{
+			name:    "tags with typed values",
+			current: map[string]interface{}{"Port": "8080", "Public": "false", "Team": "platform"},
+			desired: map[string]interface{}{"Team": "platform", "Public": false, "Port": 8080},
+			equal:   true,
+		},
+		{
+			name:    "string map and interface map",
+			current: map[string]string{"Environment": "prod"},
+			desired: map[string]interface{}{"Environment": "prod"},
+			equal:   true,
+		},
+		{
+			name:    "map with a missing key",
+			current: map[string]interface{}{"Environment": "prod"},
+			desired: map[string]interface{}{"Environment": "prod", "Team": "platform"},
+			equal:   false,
+		},
+		{
+			name:    "nested maps",
+			current: map[string]interface{}{"scaling": map[string]interface{}{"min": "1", "max": "4"}},
+			desired: map[string]interface{}{"scaling": map[string]interface{}{"max": 4, "min": 1}},
+			equal:   true,
+		},
+		{
+			name:    "lists compare in order",
+			current: []interface{}{"80", "443"},
+			desired: []interface{}{443, 80},
+			equal:   false,
+		},
+		{
+			name:    "typed lists",
+			current: []string{"80", "443"},
+			desired: []interface{}{80, 443},
+			equal:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.equal, detector.valuesEqual(tt.current, tt.desired))
+		})
+	}
+}
+
+func TestDetector_compareStates_TagsWithTypedValues(t *testing.T) {
+	detector := &Detector{}
+
+	differences := detector.compareStates(
+		map[string]interface{}{"tags": map[string]interface{}{"MaxSize": "20", "Team": "platform"}},
+		map[string]interface{}{"tags": map[string]interface{}{"Team": "platform", "MaxSize": 20}},
+		defaultMetadataFields,
+	)
+
+	assert.Empty(t, differences)
+}
+
+func TestDetector_compareStates_SDKTypes(t *testing.T) {
+	detector := &Detector{}
+
+	differences := detector.compareStates(
+		map[string]interface{}{"allocated_storage": awssdk.Int32(20), "multi_az": awssdk.Bool(false), "engine_version": "15.4"},
+		map[string]interface{}{"allocated_storage": 20, "multi_az": false, "engine_version": 15.4},
+		defaultMetadataFields,
+	)
+	assert.Empty(t, differences)
+
+	differences = detector.compareStates(
+		map[string]interface{}{"allocated_storage": awssdk.Int32(20)},
+		map[string]interface{}{"allocated_storage": 50},
+		defaultMetadataFields,
+	)
+	require.Len(t, differences, 1)
+	assert.Equal(t, int32(20), differences["allocated_storage"].CurrentValue, "Pointers should be reported by the value they point to")
+}
+
 func TestDetector_isMetadataField(t *testing.T) {
 	detector := &Detector{}
 
@@ -411,7 +689,7 @@ func TestDetector_isMetadataField(t *testing.T) {
 	}
 
 	for _, field := range metadataFields {
-		assert.True(t, detector.isMetadataField(field), 
+		assert.True(t, detector.isMetadataField(field, defaultMetadataFields), 
 			"Field '%s' should be recognized as metadata", field)
 	}
 
@@ -421,53 +699,406 @@ func TestDetector_isMetadataField(t *testing.T) {
 	}
 
 	for _, field := range nonMetadataFields {
-		assert.False(t, detector.isMetadataField(field), 
+		assert.False(t, detector.isMetadataField(field, defaultMetadataFields), 
 			"Field '%s' should not be recognized as metadata", field)
 	}
 }
 
-// TestProvider implements the Provider interface for unit testing without mocks
-type TestProvider struct {
-	states       map[string]map[string]interface{}
-	createCalled bool
-	updateCalled bool
+func TestDetector_DetectDrift_ProviderComputedFields(t *testing.T) {
+	testProvider := &computedFieldsProvider{Provider: fake.New("test:resource:type"), computed: []string{"function_arn"}}
+	testProvider.SetState("test:resource:type.web", map[string]interface{}{
+		"status":       "active",
+		"function_arn": "arn:aws:lambda:us-east-1:123456789012:function:web",
+	})
+
+	registry := providers.NewRegistry()
+	registry.Register("test", testProvider)
+	detector := NewDetector(registry)
+
+	result, err := detector.DetectDrift(context.Background(), config.ResourceInstance{
+		Kind:       "test:resource:type",
+		Name:       "web",
+		Properties: map[string]interface{}{},
+	})
+	require.NoError(t, err)
+
+	// The declared computed field is ignored, while status is only metadata by default
+	assert.True(t, result.HasDrift)
+	assert.Contains(t, result.Differences, "status")
+	assert.NotContains(t, result.Differences, "function_arn")
+}
+
+func TestDetector_DetectDrift_IgnoredTagPrefixes(t *testing.T) {
+	state := map[string]interface{}{
+		"versioning": true,
+		"tags": map[string]interface{}{
+			"Environment":                   "production",
+			"aws:cloudformation:stack-name": "network",
+		},
+	}
+	instance := config.ResourceInstance{
+		Kind: "test:resource:type",
+		Name: "logs",
+		Properties: map[string]interface{}{
+			"versioning": true,
+			"tags":       map[string]interface{}{"Environment": "production"},
+		},
+	}
+
+	testProvider := &ignoredTagsProvider{Provider: fake.New("test:resource:type"), prefixes: []string{"aws:"}}
+	testProvider.SetState("test:resource:type.logs", state)
+
+	registry := providers.NewRegistry()
+	registry.Register("test", testProvider)
+	detector := NewDetector(registry)
+
+	// Tags under a prefix the provider declares, such as AWS's reserved aws:, aren't drift
+	result, err := detector.DetectDrift(context.Background(), instance)
+	require.NoError(t, err)
+	assert.False(t, result.HasDrift, "unexpected drift: %v", result.Changes)
+	assert.Contains(t, result.CurrentState["tags"], "aws:cloudformation:stack-name", "the live state is reported unfiltered")
+
+	// A resource that declares no tags isn't drifted by AWS's tags alone
+	untagged := instance
+	untagged.Properties = map[string]interface{}{"versioning": true}
+	state["tags"] = map[string]interface{}{"aws:cloudformation:stack-name": "network"}
+	testProvider.SetState("test:resource:type.logs", state)
+	result, err = detector.DetectDrift(context.Background(), untagged)
+	require.NoError(t, err)
+	assert.False(t, result.HasDrift, "unexpected drift: %v", result.Changes)
+
+	// A provider that declares no prefixes has every tag compared
+	plainProvider := fake.New("test:resource:type")
+	plainProvider.SetState("test:resource:type.logs", state)
+	registry.Register("test", plainProvider)
+	result, err = NewDetector(registry).DetectDrift(context.Background(), untagged)
+	require.NoError(t, err)
+	assert.Contains(t, result.Differences, "tags")
+}
+
+func TestDetector_DetectDriftBatch_ProviderError(t *testing.T) {
+	testProvider := fake.New("test:resource:type")
+	testProvider.SetState("test:resource:type.logs", map[string]interface{}{"versioning": true})
+	testProvider.FailOn(fake.OperationRead, "test:resource:type.site", errors.New("AccessDenied"))
+
+	registry := providers.NewRegistry()
+	registry.Register("test", testProvider)
+
+	_, err := NewDetector(registry).DetectDriftBatch(context.Background(), []config.ResourceInstance{
+		{ID: "test:resource:type.logs", Kind: "test:resource:type", Name: "logs", Properties: map[string]interface{}{"versioning": true}},
+		{ID: "test:resource:type.site", Kind: "test:resource:type", Name: "site"},
+	})
+	assert.ErrorContains(t, err, "failed to get current state for resource test:resource:type.site: AccessDenied")
+	assert.Empty(t, testProvider.Created(), "drift detection only reads")
+}
+
+func TestDetector_DetectDriftBatch_Moved(t *testing.T) {
+	testProvider := fake.New("test:server", "test:bucket")
+	testProvider.FixNames("test:bucket")
+	testProvider.SetState("test:server.web", map[string]interface{}{"size": "small"})
+	testProvider.SetState("test:server.worker", map[string]interface{}{"size": "large"})
+
+	registry := providers.NewRegistry()
+	registry.Register("test", testProvider)
+	detector := NewDetector(registry)
+	detector.SetMoves([]config.Moved{
+		{From: "test:server.web", To: "test:server.api"},
+		{From: "test:server.old-worker", To: "test:server.worker"},
+		{From: "test:server.gone", To: "test:server.new"},
+	})
+
+	results, err := detector.DetectDriftBatch(context.Background(), []config.ResourceInstance{
+		{ID: "test:server.api", Kind: "test:server", Name: "api", Properties: map[string]interface{}{"size": "medium"}},
+		{ID: "test:server.worker", Kind: "test:server", Name: "worker", Properties: map[string]interface{}{"size": "large"}},
+		{ID: "test:server.new", Kind: "test:server", Name: "new"},
+	})
+	require.NoError(t, err)
+
+	// Still under its old ID, so it's compared there and reported as moved
+	moved := results["test:server.api"]
+	assert.Equal(t, "test:server.web", moved.MovedFrom)
+	assert.True(t, moved.HasDrift)
+	assert.Equal(t, map[string]interface{}{"size": "small"}, moved.CurrentState)
+	assert.Equal(t, "Resource was moved from test:server.web", moved.Changes[0])
+	assert.Contains(t, moved.Differences, "size")
+
+	// Already renamed by an earlier commit
+	assert.Empty(t, results["test:server.worker"].MovedFrom)
+	assert.False(t, results["test:server.worker"].HasDrift)
+
+	// Found under neither ID, so it's created
+	assert.Empty(t, results["test:server.new"].MovedFrom)
+	assert.Nil(t, results["test:server.new"].CurrentState)
+
+	// Kinds that can't be renamed are refused even before anything would be renamed
+	detector.SetMoves([]config.Moved{{From: "test:bucket.logs", To: "test:bucket.assets"}})
+	_, err = detector.DetectDriftBatch(context.Background(), []config.ResourceInstance{
+		{ID: "test:bucket.assets", Kind: "test:bucket", Name: "assets"},
+	})
+	assert.ErrorContains(t, err, "can't move test:bucket.logs to test:bucket.assets: test:bucket resources can't be renamed in place")
+	assert.Empty(t, testProvider.Renamed(), "drift detection only reads")
+}
+
+func TestDetector_AutoHeal_Moved(t *testing.T) {
+	registry := providers.NewRegistry()
+	registry.Register("test", fake.New("test:server"))
+
+	err := NewDetector(registry).AutoHeal(context.Background(), config.ResourceInstance{
+		ID:          "test:server.api",
+		Kind:        "test:server",
+		Name:        "api",
+		DriftPolicy: &config.DriftPolicy{AutoHeal: true},
+	}, &providers.DriftResult{HasDrift: true, CurrentState: map[string]interface{}{}, MovedFrom: "test:server.web"})
+
+	var skipped *HealSkippedError
+	require.ErrorAs(t, err, &skipped)
+	assert.Contains(t, skipped.Reason, "moved from test:server.web; run commit to rename it")
+}
+
+func TestDetector_DetectDrift_ProviderAlias(t *testing.T) {
+	defaultProvider := fake.New("test:resource:type")
+	defaultProvider.SetState("test:resource:type.web", map[string]interface{}{"region": "us-east-1"})
+	euProvider := fake.New("test:resource:type")
+	euProvider.SetState("test:resource:type.web", map[string]interface{}{"region": "eu-west-1"})
+
+	registry := providers.NewRegistry()
+	registry.Register("test", defaultProvider)
+	registry.Register("test.eu", euProvider)
+	detector := NewDetector(registry)
+
+	instance := config.ResourceInstance{
+		Kind:       "test:resource:type",
+		Name:       "web",
+		Properties: map[string]interface{}{"region": "eu-west-1"},
+	}
+
+	result, err := detector.DetectDrift(context.Background(), instance)
+	require.NoError(t, err)
+	assert.True(t, result.HasDrift)
+
+	instance.Provider = "test.eu"
+	result, err = detector.DetectDrift(context.Background(), instance)
+	require.NoError(t, err)
+	assert.False(t, result.HasDrift)
+
+	instance.Provider = "test.ap"
+	_, err = detector.DetectDrift(context.Background(), instance)
+	assert.ErrorContains(t, err, "provider test.ap not found")
+}
+
+func TestDetector_DetectDrift_IgnoreChanges(t *testing.T) {
+	testProvider := fake.New("test:resource:type")
+	testProvider.SetState("test:resource:type.logs", map[string]interface{}{
+		"versioning": true,
+		"tags": map[string]interface{}{
+			"Environment":    "production",
+			"LastModifiedBy": "backup-job",
+		},
+	})
+
+	registry := providers.NewRegistry()
+	registry.Register("test", testProvider)
+	detector := NewDetector(registry)
+
+	instance := config.ResourceInstance{
+		Kind: "test:resource:type",
+		Name: "logs",
+		Properties: map[string]interface{}{
+			"versioning": false,
+			"tags": map[string]interface{}{
+				"Environment":    "production",
+				"LastModifiedBy": "runestone",
+			},
+		},
+		DriftPolicy: &config.DriftPolicy{
+			IgnoreChanges: []string{"versioning", "tags.LastModifiedBy"},
+		},
+	}
+
+	result, err := detector.DetectDrift(context.Background(), instance)
+	require.NoError(t, err)
+	assert.False(t, result.HasDrift)
+	assert.Empty(t, result.Differences)
+
+	// Ignoring changes doesn't alter the reported or configured state
+	assert.Equal(t, "backup-job", result.CurrentState["tags"].(map[string]interface{})["LastModifiedBy"])
+	assert.Equal(t, "runestone", instance.Properties["tags"].(map[string]interface{})["LastModifiedBy"])
+
+	// Other tags are still compared
+	instance.Properties["tags"].(map[string]interface{})["Environment"] = "staging"
+	result, err = detector.DetectDrift(context.Background(), instance)
+	require.NoError(t, err)
+	assert.True(t, result.HasDrift)
+	assert.Contains(t, result.Differences, "tags.Environment")
+	assert.NotContains(t, result.Differences, "tags.LastModifiedBy")
+	assert.NotContains(t, result.Differences, "versioning")
 }
 
-func (tp *TestProvider) Initialize(ctx context.Context, config map[string]interface{}) error {
-	return nil
+func TestWithoutPath(t *testing.T) {
+	state := map[string]interface{}{
+		"name": "logs",
+		"tags": map[string]interface{}{"Owner": "platform", "Team": "infra"},
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"name": "logs",
+		"tags": map[string]interface{}{"Team": "infra"},
+	}, withoutPath(state, []string{"tags", "Owner"}))
+	assert.Equal(t, map[string]interface{}{
+		"tags": map[string]interface{}{"Owner": "platform", "Team": "infra"},
+	}, withoutPath(state, []string{"name"}))
+
+	// Missing paths and paths through non-map values leave the state as it is
+	assert.Equal(t, state, withoutPath(state, []string{"missing", "key"}))
+	assert.Equal(t, state, withoutPath(state, []string{"name", "key"}))
+	assert.Len(t, state["tags"], 2)
 }
 
-func (tp *TestProvider) Create(ctx context.Context, instance config.ResourceInstance) error {
-	tp.createCalled = true
-	tp.states[instance.Name] = instance.Properties
-	return nil
+func TestDetector_DetectDriftBatch_Parallelism(t *testing.T) {
+	testProvider := &concurrencyTrackingProvider{Provider: fake.New("test:resource:type")}
+
+	registry := providers.NewRegistry()
+	registry.Register("test", testProvider)
+
+	detector := NewDetector(registry)
+	detector.SetParallelism(2)
+
+	instances := make([]config.ResourceInstance, 0, 8)
+	for i := 0; i < 8; i++ {
+		name := fmt.Sprintf("resource-%d", i)
+		testProvider.SetState("test:resource:type."+name, map[string]interface{}{"property": "value"})
+		instances = append(instances, config.ResourceInstance{
+			ID:         "test:resource:type." + name,
+			Kind:       "test:resource:type",
+			Name:       name,
+			Properties: map[string]interface{}{"property": "value"},
+		})
+	}
+
+	results, err := detector.DetectDriftBatch(context.Background(), instances)
+	require.NoError(t, err)
+	assert.Len(t, results, len(instances))
+	for _, instance := range instances {
+		assert.False(t, results[instance.ID].HasDrift)
+	}
+
+	assert.LessOrEqual(t, testProvider.maxActive, int32(2), "Should never exceed the configured parallelism")
+	assert.Greater(t, testProvider.maxActive, int32(1), "Should inspect resources concurrently")
 }
 
-func (tp *TestProvider) Update(ctx context.Context, instance config.ResourceInstance, currentState map[string]interface{}) error {
-	tp.updateCalled = true
-	tp.states[instance.Name] = instance.Properties
-	return nil
+func TestDetector_DetectDriftBatch_BatchStateProvider(t *testing.T) {
+	testProvider := &batchingProvider{Provider: fake.New("test:resource:type", "test:resource:batched"), batchKind: "test:resource:batched"}
+	testProvider.SetState("test:resource:batched.web-1", map[string]interface{}{"property": "value"})
+	testProvider.SetState("test:resource:batched.web-2", map[string]interface{}{"property": "changed"})
+	testProvider.SetState("test:resource:type.bucket", map[string]interface{}{"property": "value"})
+
+	registry := providers.NewRegistry()
+	registry.Register("test", testProvider)
+	detector := NewDetector(registry)
+
+	instances := []config.ResourceInstance{
+		{ID: "test:resource:batched.web-1", Kind: "test:resource:batched", Name: "web-1", Properties: map[string]interface{}{"property": "value"}},
+		{ID: "test:resource:batched.web-2", Kind: "test:resource:batched", Name: "web-2", Properties: map[string]interface{}{"property": "value"}},
+		{ID: "test:resource:batched.web-3", Kind: "test:resource:batched", Name: "web-3", Properties: map[string]interface{}{"property": "value"}},
+		{ID: "test:resource:type.bucket", Kind: "test:resource:type", Name: "bucket", Properties: map[string]interface{}{"property": "value"}},
+	}
+
+	results, err := detector.DetectDriftBatch(context.Background(), instances)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+
+	assert.False(t, results["test:resource:batched.web-1"].HasDrift)
+	assert.True(t, results["test:resource:batched.web-2"].HasDrift)
+	assert.Nil(t, results["test:resource:batched.web-3"].CurrentState, "Resources left out of the batch don't exist")
+	assert.False(t, results["test:resource:type.bucket"].HasDrift)
+
+	// Batched kinds are looked up in one call; other kinds one at a time
+	assert.Equal(t, 1, testProvider.batchCalls)
+	assert.Equal(t, []string{"web-1", "web-2", "web-3"}, testProvider.batched)
+	assert.Equal(t, []string{"bucket"}, testProvider.single)
+
+	testProvider.batchErr = errors.New("throttled")
+	_, err = detector.DetectDriftBatch(context.Background(), instances)
+	assert.ErrorContains(t, err, "failed to get current state of test resources: throttled")
+}
+
+// concurrencyTrackingProvider records how many state lookups run at the same time
+type concurrencyTrackingProvider struct {
+	*fake.Provider
+	active    int32
+	maxActive int32
+}
+
+func (cp *concurrencyTrackingProvider) GetCurrentState(ctx context.Context, instance config.ResourceInstance) (map[string]interface{}, error) {
+	active := atomic.AddInt32(&cp.active, 1)
+	defer atomic.AddInt32(&cp.active, -1)
+
+	for {
+		current := atomic.LoadInt32(&cp.maxActive)
+		if active <= current || atomic.CompareAndSwapInt32(&cp.maxActive, current, active) {
+			break
+		}
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	return cp.Provider.GetCurrentState(ctx, instance)
 }
 
-func (tp *TestProvider) Delete(ctx context.Context, instance config.ResourceInstance) error {
-	delete(tp.states, instance.Name)
-	return nil
+// batchingProvider is a fake provider that looks up one kind of resource in bulk
+type batchingProvider struct {
+	*fake.Provider
+	batchKind  string
+	batchErr   error
+	batchCalls int
+	batched    []string
+	single     []string
+	mutex      sync.Mutex
 }
 
-func (tp *TestProvider) GetCurrentState(ctx context.Context, instance config.ResourceInstance) (map[string]interface{}, error) {
-	state, exists := tp.states[instance.Name]
-	if !exists {
-		return nil, nil // Resource doesn't exist
+func (bp *batchingProvider) SupportsBatchState(kind string) bool {
+	return kind == bp.batchKind
+}
+
+func (bp *batchingProvider) GetCurrentStateBatch(ctx context.Context, instances []config.ResourceInstance) (map[string]map[string]interface{}, error) {
+	bp.batchCalls++
+	if bp.batchErr != nil {
+		return nil, bp.batchErr
+	}
+	states := make(map[string]map[string]interface{})
+	for _, instance := range instances {
+		bp.batched = append(bp.batched, instance.Name)
+		if state := bp.State(instance.ID); state != nil {
+			states[instance.ID] = state
+		}
 	}
-	return state, nil
+	return states, nil
+}
+
+func (bp *batchingProvider) GetCurrentState(ctx context.Context, instance config.ResourceInstance) (map[string]interface{}, error) {
+	bp.mutex.Lock()
+	bp.single = append(bp.single, instance.Name)
+	bp.mutex.Unlock()
+	return bp.Provider.GetCurrentState(ctx, instance)
+}
+
+// ignoredTagsProvider is a fake provider that declares the prefixes of tags the cloud adds
+type ignoredTagsProvider struct {
+	*fake.Provider
+	prefixes []string
+}
+
+func (p *ignoredTagsProvider) GetIgnoredTagPrefixes() []string {
+	return p.prefixes
 }
 
-func (tp *TestProvider) ValidateResource(instance config.ResourceInstance) error {
-	return nil
+// computedFieldsProvider is a fake provider that declares its computed fields
+type computedFieldsProvider struct {
+	*fake.Provider
+	computed []string
 }
 
-func (tp *TestProvider) GetSupportedResourceTypes() []string {
-	return []string{"test:resource:type"}
+func (cp *computedFieldsProvider) GetComputedFields(kind string) []string {
+	return cp.computed
 }
 
 // Helper functions
@@ -0,0 +1,94 @@
+package drift
+
+import (
+	"strings"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/ataiva-software/runestone/internal/providers"
+)
+
+// RedactedValue replaces the values of sensitive properties in drift differences
+const RedactedValue = "***"
+
+// sensitiveFieldsFor returns the property names whose values must not be shown: those the
+// provider declares sensitive for the resource's kind and those the resource lists itself
+func (d *Detector) sensitiveFieldsFor(provider providers.Provider, instance config.ResourceInstance) map[string]bool {
+	fields := make(map[string]bool)
+	if declared, ok := provider.(providers.SensitiveFieldsProvider); ok {
+		for _, field := range declared.GetSensitiveFields(instance.Kind) {
+			fields[field] = true
+		}
+	}
+	for _, field := range instance.Sensitive {
+		fields[field] = true
+	}
+	return fields
+}
+
+// redactDifferences masks the values of sensitive properties in place. A difference is
+// masked entirely when any key along its path is sensitive, such as tags.api_key; otherwise
+// sensitive keys nested in its map values are masked.
+func redactDifferences(differences map[string]providers.DriftDifference, sensitive map[string]bool) {
+	if len(sensitive) == 0 {
+		return
+	}
+
+	for key, diff := range differences {
+		if isSensitivePath(diff.Property, sensitive) {
+			diff.CurrentValue = redactValue(diff.CurrentValue)
+			diff.DesiredValue = redactValue(diff.DesiredValue)
+		} else {
+			diff.CurrentValue = redactNested(diff.CurrentValue, sensitive)
+			diff.DesiredValue = redactNested(diff.DesiredValue, sensitive)
+		}
+		differences[key] = diff
+	}
+}
+
+// isSensitivePath reports whether any key of a dotted property path, ignoring list
+// indexes, is sensitive
+func isSensitivePath(path string, sensitive map[string]bool) bool {
+	for _, segment := range strings.Split(path, ".") {
+		if index := strings.Index(segment, "["); index >= 0 {
+			segment = segment[:index]
+		}
+		if sensitive[segment] {
+			return true
+		}
+	}
+	return false
+}
+
+// redactValue masks a value, keeping nil so that added and removed properties still read
+// as missing
+func redactValue(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	return RedactedValue
+}
+
+// redactNested returns a copy of value with the values of sensitive map keys masked at
+// any depth
+func redactNested(value interface{}, sensitive map[string]bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if sensitive[key] {
+				redacted[key] = redactValue(item)
+			} else {
+				redacted[key] = redactNested(item, sensitive)
+			}
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = redactNested(item, sensitive)
+		}
+		return redacted
+	default:
+		return value
+	}
+}
@@ -0,0 +1,127 @@
+package drift
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/ataiva-software/runestone/internal/providers"
+	"github.com/ataiva-software/runestone/internal/providers/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sensitiveTestProvider declares some properties of every kind sensitive
+type sensitiveTestProvider struct {
+	*fake.Provider
+	fields []string
+}
+
+func (p *sensitiveTestProvider) GetSensitiveFields(kind string) []string {
+	return p.fields
+}
+
+func TestDetector_DetectDrift_RedactsSensitiveValues(t *testing.T) {
+	testProvider := &sensitiveTestProvider{Provider: fake.New("test:resource:type"), fields: []string{"master_user_password"}}
+	testProvider.SetState("test:resource:type.db", map[string]interface{}{
+		"master_user_password": "old-password",
+		"allocated_storage":    20,
+		"tags":                 map[string]interface{}{"api_key": "old-key", "Environment": "dev"},
+	})
+
+	registry := providers.NewRegistry()
+	registry.Register("test", testProvider)
+	detector := NewDetector(registry)
+
+	instance := config.ResourceInstance{
+		Kind: "test:resource:type",
+		Name: "db",
+		Properties: map[string]interface{}{
+			"master_user_password": "new-password",
+			"allocated_storage":    50,
+			"tags":                 map[string]interface{}{"api_key": "new-key", "Environment": "prod"},
+		},
+		// Declared by the resource rather than the provider
+		Sensitive: []string{"api_key"},
+	}
+
+	result, err := detector.DetectDrift(context.Background(), instance)
+	require.NoError(t, err)
+	require.True(t, result.HasDrift)
+
+	password := result.Differences["master_user_password"]
+	assert.Equal(t, RedactedValue, password.CurrentValue)
+	assert.Equal(t, RedactedValue, password.DesiredValue)
+
+	apiKey := result.Differences["tags.api_key"]
+	assert.Equal(t, RedactedValue, apiKey.CurrentValue)
+	assert.Equal(t, RedactedValue, apiKey.DesiredValue)
+
+	// Other properties keep their values
+	assert.Equal(t, 20, result.Differences["allocated_storage"].CurrentValue)
+	assert.Equal(t, "prod", result.Differences["tags.Environment"].DesiredValue)
+
+	for _, change := range result.Changes {
+		assert.NotContains(t, change, "-password")
+		assert.NotContains(t, change, "-key")
+	}
+}
+
+func TestRedactDifferences_NestedMapValues(t *testing.T) {
+	differences := map[string]providers.DriftDifference{
+		"environment": {
+			Property:     "environment",
+			CurrentValue: nil,
+			DesiredValue: map[string]interface{}{"DB_PASSWORD": "hunter2", "LOG_LEVEL": "info"},
+			DriftType:    providers.DriftTypeAdded,
+		},
+		"ingress[0].secret": {
+			Property:     "ingress[0].secret",
+			CurrentValue: "a",
+			DesiredValue: "b",
+			DriftType:    providers.DriftTypeModified,
+		},
+	}
+
+	redactDifferences(differences, map[string]bool{"DB_PASSWORD": true, "secret": true})
+
+	assert.Nil(t, differences["environment"].CurrentValue)
+	assert.Equal(t, map[string]interface{}{"DB_PASSWORD": RedactedValue, "LOG_LEVEL": "info"}, differences["environment"].DesiredValue)
+	assert.Equal(t, RedactedValue, differences["ingress[0].secret"].CurrentValue)
+	assert.Equal(t, RedactedValue, differences["ingress[0].secret"].DesiredValue)
+}
+
+func TestDetector_DetectDrift_SkipsSecretReferences(t *testing.T) {
+	testProvider := fake.New("test:resource:type")
+	testProvider.SetState("test:resource:type.db", map[string]interface{}{
+		"master_user_password": "live-password",
+		"allocated_storage":    20,
+		"tags":                 map[string]interface{}{"Token": "live-token", "Environment": "dev"},
+	})
+
+	registry := providers.NewRegistry()
+	registry.Register("test", testProvider)
+	detector := NewDetector(registry)
+
+	instance := config.ResourceInstance{
+		Kind: "test:resource:type",
+		Name: "db",
+		Properties: map[string]interface{}{
+			"master_user_password": `${secret("prod/db/password")}`,
+			"allocated_storage":    20,
+			"tags":                 map[string]interface{}{"Token": `${secret("prod/token")}`, "Environment": "dev"},
+		},
+	}
+
+	// Secret references aren't resolved until commit, so they aren't compared
+	result, err := detector.DetectDrift(context.Background(), instance)
+	require.NoError(t, err)
+	assert.False(t, result.HasDrift, "unexpected drift: %v", result.Changes)
+
+	// Other properties still are
+	instance.Properties["tags"].(map[string]interface{})["Environment"] = "prod"
+	result, err = detector.DetectDrift(context.Background(), instance)
+	require.NoError(t, err)
+	assert.Len(t, result.Differences, 1)
+	assert.Contains(t, result.Differences, "tags.Environment")
+}
@@ -2,9 +2,11 @@ package executor
 
 import (
 	"fmt"
+	"regexp"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/ataiva-software/runestone/internal/config"
 )
@@ -17,6 +19,16 @@ type DAGNode struct {
 	Dependents   []string
 	Status       NodeStatus
 	Error        error
+	StartedAt    time.Time // When Execute started applying the node; zero if it never ran
+	FinishedAt   time.Time // When the node's apply returned; zero if it never ran
+}
+
+// Duration returns how long the node took to apply, or zero if it hasn't finished
+func (n *DAGNode) Duration() time.Duration {
+	if n.StartedAt.IsZero() || n.FinishedAt.IsZero() {
+		return 0
+	}
+	return n.FinishedAt.Sub(n.StartedAt)
 }
 
 // NodeStatus represents the execution status of a node
@@ -28,12 +40,16 @@ const (
 	StatusRunning   NodeStatus = "running"
 	StatusCompleted NodeStatus = "completed"
 	StatusFailed    NodeStatus = "failed"
+	StatusSkipped   NodeStatus = "skipped" // Not run because a dependency failed
 )
 
 // DAG represents a directed acyclic graph of resources
 type DAG struct {
 	nodes map[string]*DAGNode
 	mutex sync.RWMutex
+
+	// eventHandler receives progress events from Execute; see SetEventHandler
+	eventHandler EventHandler
 }
 
 // NewDAG creates a new DAG from resource instances
@@ -65,7 +81,7 @@ func NewDAG(instances []config.ResourceInstance) (*DAG, error) {
 			}
 		}
 
-		// Infer dependencies from resource kinds (e.g., modules before resources)
+		// Infer dependencies from references in properties
 		if err := dag.inferDependencies(node); err != nil {
 			return nil, err
 		}
@@ -79,52 +95,159 @@ func NewDAG(instances []config.ResourceInstance) (*DAG, error) {
 	return dag, nil
 }
 
-// inferDependencies infers implicit dependencies between resources
+// inferDependencies adds the dependencies implied by a resource's properties: a property
+// that references another resource's attribute, or whose value is another resource's ID,
+// depends on that resource. Explicit depends_on entries are kept as they are.
 func (d *DAG) inferDependencies(node *DAGNode) error {
-	// Module resources should be created before other resources that might depend on them
-	if strings.HasPrefix(node.Instance.Kind, "module:") {
-		return nil // Modules typically don't depend on other resources
+	// Resources referencing another resource's outputs depend on it
+	for _, ref := range FindReferences(node.Instance.Properties) {
+		if _, exists := d.nodes[ref.ResourceID]; !exists {
+			return fmt.Errorf("resource %s references unknown resource %s", node.ID, ref.ResourceID)
+		}
+		d.addDependency(node, ref.ResourceID)
 	}
 
-	// Look for potential dependencies based on naming patterns or resource types
-	for _, otherNode := range d.nodes {
-		if otherNode.ID == node.ID {
-			continue
+	// Resources naming another resource by ID depend on it
+	for _, resourceID := range d.findResourceIDs(node.Instance.Properties) {
+		if resourceID != node.ID {
+			d.addDependency(node, resourceID)
 		}
+	}
 
-		// If this is a module, other resources might depend on it
-		if strings.HasPrefix(otherNode.Instance.Kind, "module:") {
-			// Check if the resource name suggests it depends on the module
-			if d.shouldDependOn(node, otherNode) {
-				node.Dependencies = append(node.Dependencies, otherNode.ID)
-				otherNode.Dependents = append(otherNode.Dependents, node.ID)
-			}
+	// Resources referencing an IAM role by ARN depend on the role that produces it
+	for _, resourceID := range d.findIAMRoleReferences(node.Instance.Properties) {
+		if resourceID != node.ID {
+			d.addDependency(node, resourceID)
 		}
 	}
 
 	return nil
 }
 
-// shouldDependOn determines if one resource should depend on another
-func (d *DAG) shouldDependOn(dependent, dependency *DAGNode) bool {
-	// Simple heuristic: if the dependent resource name contains the dependency name
-	// This is a basic implementation - in practice, you'd want more sophisticated logic
-	if strings.HasPrefix(dependency.Instance.Kind, "module:") {
-		// Check if the dependent resource might use outputs from the module
-		return strings.Contains(dependent.Instance.Name, strings.TrimPrefix(dependency.Instance.Kind, "module:"))
+// addDependency records that a node depends on another, unless it already does
+func (d *DAG) addDependency(node *DAGNode, dependencyID string) {
+	if containsString(node.Dependencies, dependencyID) {
+		return
+	}
+	node.Dependencies = append(node.Dependencies, dependencyID)
+	d.nodes[dependencyID].Dependents = append(d.nodes[dependencyID].Dependents, node.ID)
+}
+
+// findResourceIDs returns the IDs of resources in the DAG that appear as whole property
+// values, such as target: aws:ec2:instance.web, sorted for deterministic output
+func (d *DAG) findResourceIDs(properties map[string]interface{}) []string {
+	found := make(map[string]bool)
+	var collect func(value interface{})
+	collect = func(value interface{}) {
+		switch v := value.(type) {
+		case string:
+			if _, exists := d.nodes[v]; exists {
+				found[v] = true
+			}
+		case map[string]interface{}:
+			for _, item := range v {
+				collect(item)
+			}
+		case []interface{}:
+			for _, item := range v {
+				collect(item)
+			}
+		}
+	}
+	collect(properties)
+
+	resourceIDs := make([]string, 0, len(found))
+	for resourceID := range found {
+		resourceIDs = append(resourceIDs, resourceID)
+	}
+	sort.Strings(resourceIDs)
+
+	return resourceIDs
+}
+
+// iamRoleARNPattern matches IAM role ARNs, including ones embedded in policy documents
+var iamRoleARNPattern = regexp.MustCompile(`arn:aws[a-z-]*:iam::\d{12}:role/[\w+=,.@/-]+`)
+
+// findIAMRoleReferences returns the IDs of aws:iam:role resources in the DAG referenced by
+// ARN anywhere in the properties, or by name as the whole value of a role property, sorted
+// for deterministic output
+func (d *DAG) findIAMRoleReferences(properties map[string]interface{}) []string {
+	roles := make(map[string]string)
+	for id, node := range d.nodes {
+		if node.Instance.Kind == "aws:iam:role" {
+			roles[node.Instance.Name] = id
+		}
+	}
+	if len(roles) == 0 {
+		return nil
+	}
+
+	found := make(map[string]bool)
+	var collect func(key string, value interface{})
+	collect = func(key string, value interface{}) {
+		switch v := value.(type) {
+		case string:
+			for _, arn := range iamRoleARNPattern.FindAllString(v, -1) {
+				// Roles can have paths, so the name is the last segment
+				name := arn[strings.LastIndex(arn, "/")+1:]
+				if id, exists := roles[name]; exists {
+					found[id] = true
+				}
+			}
+			if key == "role" {
+				if id, exists := roles[v]; exists {
+					found[id] = true
+				}
+			}
+		case map[string]interface{}:
+			for itemKey, item := range v {
+				collect(itemKey, item)
+			}
+		case []interface{}:
+			for _, item := range v {
+				collect(key, item)
+			}
+		}
+	}
+	for key, value := range properties {
+		collect(key, value)
+	}
+
+	resourceIDs := make([]string, 0, len(found))
+	for resourceID := range found {
+		resourceIDs = append(resourceIDs, resourceID)
+	}
+	sort.Strings(resourceIDs)
+
+	return resourceIDs
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
 	}
 	return false
 }
 
-// validateAcyclic checks if the graph contains cycles
+// validateAcyclic checks if the graph contains cycles. A cycle is reported as the chain of
+// dependencies that forms it, such as a -> b -> c -> a.
 func (d *DAG) validateAcyclic() error {
 	visited := make(map[string]bool)
 	recStack := make(map[string]bool)
 
+	// Visit nodes in a fixed order so the reported cycle is deterministic
+	nodeIDs := make([]string, 0, len(d.nodes))
 	for nodeID := range d.nodes {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	sort.Strings(nodeIDs)
+
+	for _, nodeID := range nodeIDs {
 		if !visited[nodeID] {
-			if d.hasCycle(nodeID, visited, recStack) {
-				return fmt.Errorf("circular dependency detected involving resource %s", nodeID)
+			if cycle := d.hasCycle(nodeID, visited, recStack, nil); cycle != nil {
+				return fmt.Errorf("circular dependency detected: %s", strings.Join(cycle, " -> "))
 			}
 		}
 	}
@@ -132,24 +255,31 @@ func (d *DAG) validateAcyclic() error {
 	return nil
 }
 
-// hasCycle performs DFS to detect cycles
-func (d *DAG) hasCycle(nodeID string, visited, recStack map[string]bool) bool {
+// hasCycle performs DFS to detect cycles. path holds the nodes on the current recursion
+// stack; when a cycle is found it returns the nodes that form it, starting and ending
+// with the same node.
+func (d *DAG) hasCycle(nodeID string, visited, recStack map[string]bool, path []string) []string {
 	visited[nodeID] = true
 	recStack[nodeID] = true
+	path = append(path, nodeID)
 
 	node := d.nodes[nodeID]
 	for _, depID := range node.Dependencies {
 		if !visited[depID] {
-			if d.hasCycle(depID, visited, recStack) {
-				return true
+			if cycle := d.hasCycle(depID, visited, recStack, path); cycle != nil {
+				return cycle
 			}
 		} else if recStack[depID] {
-			return true
+			for i, pathID := range path {
+				if pathID == depID {
+					return append(append([]string(nil), path[i:]...), depID)
+				}
+			}
 		}
 	}
 
 	recStack[nodeID] = false
-	return false
+	return nil
 }
 
 // GetExecutionOrder returns the topological order for execution
@@ -200,6 +330,40 @@ func (d *DAG) GetExecutionOrder() [][]string {
 	return levels
 }
 
+// GetDependencyClosure returns the given node IDs together with every node they
+// transitively depend on, sorted for deterministic output
+func (d *DAG) GetDependencyClosure(nodeIDs []string) ([]string, error) {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	included := make(map[string]bool)
+	stack := make([]string, 0, len(nodeIDs))
+	for _, nodeID := range nodeIDs {
+		if _, exists := d.nodes[nodeID]; !exists {
+			return nil, fmt.Errorf("resource %s not found", nodeID)
+		}
+		stack = append(stack, nodeID)
+	}
+
+	for len(stack) > 0 {
+		nodeID := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if included[nodeID] {
+			continue
+		}
+		included[nodeID] = true
+		stack = append(stack, d.nodes[nodeID].Dependencies...)
+	}
+
+	closure := make([]string, 0, len(included))
+	for nodeID := range included {
+		closure = append(closure, nodeID)
+	}
+	sort.Strings(closure)
+
+	return closure, nil
+}
+
 // GetReadyNodes returns nodes that are ready to execute
 func (d *DAG) GetReadyNodes() []*DAGNode {
 	d.mutex.RLock()
@@ -257,13 +421,14 @@ func (d *DAG) GetAllNodes() map[string]*DAGNode {
 	return result
 }
 
-// IsComplete returns true if all nodes have completed (successfully or with error)
+// IsComplete returns true if all nodes have completed (successfully or with error) or
+// were skipped
 func (d *DAG) IsComplete() bool {
 	d.mutex.RLock()
 	defer d.mutex.RUnlock()
 
 	for _, node := range d.nodes {
-		if node.Status != StatusCompleted && node.Status != StatusFailed {
+		if node.Status != StatusCompleted && node.Status != StatusFailed && node.Status != StatusSkipped {
 			return false
 		}
 	}
@@ -296,3 +461,96 @@ func (d *DAG) GetFailedNodes() []*DAGNode {
 	}
 	return failed
 }
+
+// SkipDependents marks every pending node that transitively depends on a node as skipped,
+// so it is never made ready, and returns their IDs sorted
+func (d *DAG) SkipDependents(nodeID string) []string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	node, exists := d.nodes[nodeID]
+	if !exists {
+		return nil
+	}
+
+	var skipped []string
+	stack := append([]string(nil), node.Dependents...)
+	for len(stack) > 0 {
+		dependent := d.nodes[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+		if dependent.Status != StatusPending {
+			continue
+		}
+		dependent.Status = StatusSkipped
+		dependent.Error = fmt.Errorf("skipped because dependency %s failed", nodeID)
+		skipped = append(skipped, dependent.ID)
+		stack = append(stack, dependent.Dependents...)
+	}
+	sort.Strings(skipped)
+
+	return skipped
+}
+
+// GetSkippedNodes returns all nodes that were skipped because a dependency failed
+func (d *DAG) GetSkippedNodes() []*DAGNode {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	var skipped []*DAGNode
+	for _, node := range d.nodes {
+		if node.Status == StatusSkipped {
+			skipped = append(skipped, node)
+		}
+	}
+	return skipped
+}
+
+// statusColors are the DOT fill colors of nodes by status
+var statusColors = map[NodeStatus]string{
+	StatusPending:   "white",
+	StatusReady:     "white",
+	StatusRunning:   "lightblue",
+	StatusCompleted: "palegreen",
+	StatusFailed:    "lightcoral",
+	StatusSkipped:   "lightgray",
+}
+
+// ToDOT renders the DAG in Graphviz DOT format, with an edge from each dependency to its
+// dependents so the graph reads in execution order. Nodes are filled by status, so a graph
+// rendered after execution shows which resources failed or were skipped.
+func (d *DAG) ToDOT() string {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	nodeIDs := make([]string, 0, len(d.nodes))
+	for nodeID := range d.nodes {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	sort.Strings(nodeIDs)
+
+	var b strings.Builder
+	b.WriteString("digraph runestone {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=filled];\n")
+
+	for _, nodeID := range nodeIDs {
+		node := d.nodes[nodeID]
+		fmt.Fprintf(&b, "  %s [label=%s, fillcolor=%s];\n", dotQuote(nodeID), dotQuote(nodeID), statusColors[node.Status])
+	}
+
+	for _, nodeID := range nodeIDs {
+		dependencies := append([]string(nil), d.nodes[nodeID].Dependencies...)
+		sort.Strings(dependencies)
+		for _, depID := range dependencies {
+			fmt.Fprintf(&b, "  %s -> %s;\n", dotQuote(depID), dotQuote(nodeID))
+		}
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotQuote quotes a string as a DOT identifier
+func dotQuote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
@@ -288,6 +288,7 @@ func TestDAG_validateAcyclic(t *testing.T) {
 		name      string
 		instances []config.ResourceInstance
 		wantErr   bool
+		cycle     string
 	}{
 		{
 			name: "acyclic graph",
@@ -304,6 +305,7 @@ func TestDAG_validateAcyclic(t *testing.T) {
 				{ID: "a", Kind: "test", Name: "a", DependsOn: []string{"a"}},
 			},
 			wantErr: true,
+			cycle:   "a -> a",
 		},
 		{
 			name: "two-node cycle",
@@ -312,6 +314,18 @@ func TestDAG_validateAcyclic(t *testing.T) {
 				{ID: "b", Kind: "test", Name: "b", DependsOn: []string{"a"}},
 			},
 			wantErr: true,
+			cycle:   "a -> b -> a",
+		},
+		{
+			name: "cycle below an acyclic prefix",
+			instances: []config.ResourceInstance{
+				{ID: "a", Kind: "test", Name: "a", DependsOn: []string{"b"}},
+				{ID: "b", Kind: "test", Name: "b", DependsOn: []string{"c"}},
+				{ID: "c", Kind: "test", Name: "c", DependsOn: []string{"d"}},
+				{ID: "d", Kind: "test", Name: "d", DependsOn: []string{"b"}},
+			},
+			wantErr: true,
+			cycle:   "b -> c -> d -> b",
 		},
 	}
 
@@ -322,9 +336,114 @@ func TestDAG_validateAcyclic(t *testing.T) {
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.Contains(t, err.Error(), "circular dependency")
+				assert.Contains(t, err.Error(), tt.cycle)
 			} else {
 				assert.NoError(t, err)
 			}
 		})
 	}
 }
+
+func TestDAG_GetDependencyClosure(t *testing.T) {
+	instances := []config.ResourceInstance{
+		{ID: "aws:ec2:vpc.main", Kind: "aws:ec2:vpc", Name: "main"},
+		{ID: "aws:ec2:subnet.public", Kind: "aws:ec2:subnet", Name: "public", DependsOn: []string{"aws:ec2:vpc.main"}},
+		{ID: "aws:ec2:instance.web", Kind: "aws:ec2:instance", Name: "web", DependsOn: []string{"aws:ec2:subnet.public"}},
+		{ID: "aws:s3:bucket.logs", Kind: "aws:s3:bucket", Name: "logs"},
+	}
+
+	dag, err := NewDAG(instances)
+	require.NoError(t, err)
+
+	closure, err := dag.GetDependencyClosure([]string{"aws:ec2:instance.web"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"aws:ec2:instance.web", "aws:ec2:subnet.public", "aws:ec2:vpc.main"}, closure)
+
+	closure, err = dag.GetDependencyClosure([]string{"aws:s3:bucket.logs"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"aws:s3:bucket.logs"}, closure)
+
+	_, err = dag.GetDependencyClosure([]string{"aws:s3:bucket.missing"})
+	assert.Error(t, err)
+}
+
+func TestNewDAG_InfersIAMRoleDependencies(t *testing.T) {
+	instances := []config.ResourceInstance{
+		{ID: "aws:iam:role.lambda-exec", Kind: "aws:iam:role", Name: "lambda-exec"},
+		{ID: "aws:iam:role.deployer", Kind: "aws:iam:role", Name: "deployer"},
+		{ID: "aws:iam:role.unused", Kind: "aws:iam:role", Name: "unused"},
+		{
+			ID:         "aws:lambda:function.api",
+			Kind:       "aws:lambda:function",
+			Name:       "api",
+			Properties: map[string]interface{}{"role": "arn:aws:iam::123456789012:role/service/lambda-exec"},
+		},
+		{
+			ID:         "aws:ecs:task.worker",
+			Kind:       "aws:ecs:task",
+			Name:       "worker",
+			Properties: map[string]interface{}{"role": "lambda-exec"},
+		},
+		{
+			ID:   "aws:iam:role.ci",
+			Kind: "aws:iam:role",
+			Name: "ci",
+			Properties: map[string]interface{}{
+				"assume_role_policy": `{"Statement":[{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::123456789012:role/deployer"},"Action":"sts:AssumeRole"}]}`,
+			},
+		},
+		{
+			ID:         "aws:s3:bucket.assets",
+			Kind:       "aws:s3:bucket",
+			Name:       "assets",
+			Properties: map[string]interface{}{"description": "unused"},
+		},
+	}
+
+	dag, err := NewDAG(instances)
+	require.NoError(t, err)
+
+	node, _ := dag.GetNode("aws:lambda:function.api")
+	assert.Equal(t, []string{"aws:iam:role.lambda-exec"}, node.Dependencies)
+
+	node, _ = dag.GetNode("aws:ecs:task.worker")
+	assert.Equal(t, []string{"aws:iam:role.lambda-exec"}, node.Dependencies)
+
+	node, _ = dag.GetNode("aws:iam:role.ci")
+	assert.Equal(t, []string{"aws:iam:role.deployer"}, node.Dependencies)
+
+	// Names are only matched as the whole value of a role property
+	node, _ = dag.GetNode("aws:s3:bucket.assets")
+	assert.Empty(t, node.Dependencies)
+}
+
+func TestDAG_ToDOT(t *testing.T) {
+	instances := []config.ResourceInstance{
+		{ID: "aws:ec2:vpc.main", Kind: "aws:ec2:vpc", Name: "main"},
+		{ID: "aws:ec2:subnet.app", Kind: "aws:ec2:subnet", Name: "app", DependsOn: []string{"aws:ec2:vpc.main"}},
+		{ID: "aws:ec2:instance.web", Kind: "aws:ec2:instance", Name: "web", DependsOn: []string{"aws:ec2:subnet.app"}},
+	}
+
+	dag, err := NewDAG(instances)
+	require.NoError(t, err)
+
+	dag.SetNodeStatus("aws:ec2:vpc.main", StatusCompleted, nil)
+	dag.SetNodeStatus("aws:ec2:subnet.app", StatusFailed, assert.AnError)
+	dag.SetNodeStatus("aws:ec2:instance.web", StatusSkipped, nil)
+
+	assert.Equal(t, `digraph runestone {
+  rankdir=LR;
+  node [shape=box, style=filled];
+  "aws:ec2:instance.web" [label="aws:ec2:instance.web", fillcolor=lightgray];
+  "aws:ec2:subnet.app" [label="aws:ec2:subnet.app", fillcolor=lightcoral];
+  "aws:ec2:vpc.main" [label="aws:ec2:vpc.main", fillcolor=palegreen];
+  "aws:ec2:subnet.app" -> "aws:ec2:instance.web";
+  "aws:ec2:vpc.main" -> "aws:ec2:subnet.app";
+}
+`, dag.ToDOT())
+}
+
+func TestDotQuote(t *testing.T) {
+	assert.Equal(t, `"aws:s3:bucket.logs"`, dotQuote("aws:s3:bucket.logs"))
+	assert.Equal(t, `"say \"hi\" \\ bye"`, dotQuote(`say "hi" \ bye`))
+}
@@ -0,0 +1,47 @@
+package executor
+
+import "time"
+
+// EventType identifies what happened to a node during Execute
+type EventType string
+
+const (
+	EventNodeStarted   EventType = "node_started"
+	EventNodeCompleted EventType = "node_completed"
+	EventNodeFailed    EventType = "node_failed"
+	EventNodeSkipped   EventType = "node_skipped" // A dependency failed, so the node never ran
+)
+
+// Event reports progress of a single node while a DAG executes
+type Event struct {
+	Type   EventType `json:"type"`
+	NodeID string    `json:"node_id"`
+	Time   time.Time `json:"time"`
+	// DurationSeconds is how long the node took to apply, for completed and failed nodes
+	DurationSeconds float64 `json:"duration_seconds,omitempty"`
+	Error           string  `json:"error,omitempty"`
+}
+
+// EventHandler receives the events of an Execute call. It is called from the goroutine
+// running Execute, one event at a time and in the order the events happened, so it needs
+// no locking but should return quickly.
+type EventHandler func(event Event)
+
+// SetEventHandler sets the handler that Execute reports node progress to. Execute emits
+// no events when no handler is set.
+func (d *DAG) SetEventHandler(handler EventHandler) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.eventHandler = handler
+}
+
+// emit sends an event to the event handler, if any
+func (d *DAG) emit(event Event) {
+	d.mutex.RLock()
+	handler := d.eventHandler
+	d.mutex.RUnlock()
+
+	if handler != nil {
+		handler(event)
+	}
+}
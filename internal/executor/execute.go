@@ -0,0 +1,94 @@
+package executor
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// NodeFunc applies a single node. It is called concurrently for nodes that don't depend
+// on each other.
+type NodeFunc func(node *DAGNode) error
+
+// Execute calls fn for every node in dependency order. A node starts as soon as all of its
+// dependencies have completed, with at most parallelism nodes running at once. When a node
+// fails, the nodes that transitively depend on it are marked skipped and never run. Once
+// ctx is cancelled no further nodes are started; nodes already running are waited for, and
+// nodes that didn't start are left pending. Progress is reported to the DAG's event
+// handler, if one is set.
+func (d *DAG) Execute(ctx context.Context, parallelism int, fn NodeFunc) {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	type nodeDone struct {
+		nodeID   string
+		err      error
+		started  time.Time
+		finished time.Time
+	}
+	done := make(chan nodeDone)
+	running := 0
+
+	for {
+		if ctx.Err() == nil {
+			ready := d.GetReadyNodes()
+			sort.Slice(ready, func(i, j int) bool { return ready[i].ID < ready[j].ID })
+
+			for _, node := range ready {
+				if running >= parallelism {
+					break
+				}
+				d.SetNodeStatus(node.ID, StatusRunning, nil)
+				running++
+				started := time.Now()
+				d.emit(Event{Type: EventNodeStarted, NodeID: node.ID, Time: started})
+				go func(node *DAGNode) {
+					err := fn(node)
+					done <- nodeDone{nodeID: node.ID, err: err, started: started, finished: time.Now()}
+				}(node)
+			}
+		}
+
+		if running == 0 {
+			return
+		}
+
+		result := <-done
+		running--
+		d.setNodeTiming(result.nodeID, result.started, result.finished)
+		event := Event{
+			Type:            EventNodeCompleted,
+			NodeID:          result.nodeID,
+			Time:            result.finished,
+			DurationSeconds: result.finished.Sub(result.started).Seconds(),
+		}
+		if result.err != nil {
+			d.SetNodeStatus(result.nodeID, StatusFailed, result.err)
+			event.Type = EventNodeFailed
+			event.Error = result.err.Error()
+			d.emit(event)
+			for _, skippedID := range d.SkipDependents(result.nodeID) {
+				skipped := Event{Type: EventNodeSkipped, NodeID: skippedID, Time: result.finished}
+				if node, exists := d.GetNode(skippedID); exists && node.Error != nil {
+					skipped.Error = node.Error.Error()
+				}
+				d.emit(skipped)
+			}
+		} else {
+			d.SetNodeStatus(result.nodeID, StatusCompleted, nil)
+			d.emit(event)
+		}
+	}
+}
+
+// setNodeTiming records when a node's apply started and finished
+func (d *DAG) setNodeTiming(nodeID string, started, finished time.Time) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if node, exists := d.nodes[nodeID]; exists {
+		node.StartedAt = started
+		node.FinishedAt = finished
+	}
+}
@@ -0,0 +1,179 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDAG_Execute_SkipsDependentsOfFailedNode(t *testing.T) {
+	// A linear chain vpc <- subnet <- instance <- alarm, plus an unrelated bucket
+	instances := []config.ResourceInstance{
+		{ID: "aws:ec2:vpc.main", Kind: "aws:ec2:vpc", Name: "main"},
+		{ID: "aws:ec2:subnet.app", Kind: "aws:ec2:subnet", Name: "app", DependsOn: []string{"aws:ec2:vpc.main"}},
+		{ID: "aws:ec2:instance.web", Kind: "aws:ec2:instance", Name: "web", DependsOn: []string{"aws:ec2:subnet.app"}},
+		{ID: "aws:cloudwatch:alarm.cpu", Kind: "aws:cloudwatch:alarm", Name: "cpu", DependsOn: []string{"aws:ec2:instance.web"}},
+		{ID: "aws:s3:bucket.logs", Kind: "aws:s3:bucket", Name: "logs"},
+	}
+
+	dag, err := NewDAG(instances)
+	require.NoError(t, err)
+
+	var mutex sync.Mutex
+	var ran []string
+	dag.Execute(context.Background(), 2, func(node *DAGNode) error {
+		mutex.Lock()
+		ran = append(ran, node.ID)
+		mutex.Unlock()
+
+		if node.ID == "aws:ec2:subnet.app" {
+			return errors.New("subnet CIDR overlaps")
+		}
+		return nil
+	})
+
+	assert.ElementsMatch(t, []string{"aws:ec2:vpc.main", "aws:ec2:subnet.app", "aws:s3:bucket.logs"}, ran)
+
+	statuses := make(map[string]NodeStatus)
+	for id, node := range dag.GetAllNodes() {
+		statuses[id] = node.Status
+	}
+	assert.Equal(t, map[string]NodeStatus{
+		"aws:ec2:vpc.main":         StatusCompleted,
+		"aws:ec2:subnet.app":       StatusFailed,
+		"aws:ec2:instance.web":     StatusSkipped,
+		"aws:cloudwatch:alarm.cpu": StatusSkipped,
+		"aws:s3:bucket.logs":       StatusCompleted,
+	}, statuses)
+
+	failed := dag.GetFailedNodes()
+	require.Len(t, failed, 1)
+	assert.EqualError(t, failed[0].Error, "subnet CIDR overlaps")
+	assert.Len(t, dag.GetSkippedNodes(), 2)
+	assert.True(t, dag.IsComplete())
+}
+
+func TestDAG_Execute_RespectsParallelism(t *testing.T) {
+	instances := []config.ResourceInstance{
+		{ID: "aws:s3:bucket.a", Kind: "aws:s3:bucket", Name: "a"},
+		{ID: "aws:s3:bucket.b", Kind: "aws:s3:bucket", Name: "b"},
+		{ID: "aws:s3:bucket.c", Kind: "aws:s3:bucket", Name: "c"},
+		{ID: "aws:s3:bucket.d", Kind: "aws:s3:bucket", Name: "d"},
+	}
+
+	dag, err := NewDAG(instances)
+	require.NoError(t, err)
+
+	var current, peak int32
+	dag.Execute(context.Background(), 2, func(node *DAGNode) error {
+		running := atomic.AddInt32(&current, 1)
+		for {
+			observed := atomic.LoadInt32(&peak)
+			if running <= observed || atomic.CompareAndSwapInt32(&peak, observed, running) {
+				break
+			}
+		}
+		atomic.AddInt32(&current, -1)
+		return nil
+	})
+
+	assert.LessOrEqual(t, peak, int32(2))
+	assert.True(t, dag.IsComplete())
+}
+
+func TestDAG_Execute_StopsWhenCancelled(t *testing.T) {
+	instances := []config.ResourceInstance{
+		{ID: "aws:ec2:vpc.main", Kind: "aws:ec2:vpc", Name: "main"},
+		{ID: "aws:ec2:subnet.app", Kind: "aws:ec2:subnet", Name: "app", DependsOn: []string{"aws:ec2:vpc.main"}},
+	}
+
+	dag, err := NewDAG(instances)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	dag.Execute(ctx, 1, func(node *DAGNode) error {
+		cancel()
+		return nil
+	})
+
+	vpc, _ := dag.GetNode("aws:ec2:vpc.main")
+	subnet, _ := dag.GetNode("aws:ec2:subnet.app")
+	assert.Equal(t, StatusCompleted, vpc.Status)
+	assert.Equal(t, StatusPending, subnet.Status)
+}
+
+func TestDAG_Execute_RecordsNodeTiming(t *testing.T) {
+	instances := []config.ResourceInstance{
+		{ID: "aws:ec2:vpc.main", Kind: "aws:ec2:vpc", Name: "main"},
+		{ID: "aws:ec2:subnet.app", Kind: "aws:ec2:subnet", Name: "app", DependsOn: []string{"aws:ec2:vpc.main"}},
+		{ID: "aws:ec2:instance.web", Kind: "aws:ec2:instance", Name: "web", DependsOn: []string{"aws:ec2:subnet.app"}},
+	}
+
+	dag, err := NewDAG(instances)
+	require.NoError(t, err)
+
+	dag.Execute(context.Background(), 2, func(node *DAGNode) error {
+		if node.ID == "aws:ec2:subnet.app" {
+			time.Sleep(20 * time.Millisecond)
+			return errors.New("subnet CIDR overlaps")
+		}
+		return nil
+	})
+
+	vpc, _ := dag.GetNode("aws:ec2:vpc.main")
+	subnet, _ := dag.GetNode("aws:ec2:subnet.app")
+	instance, _ := dag.GetNode("aws:ec2:instance.web")
+
+	assert.False(t, vpc.StartedAt.IsZero())
+	assert.False(t, subnet.StartedAt.Before(vpc.FinishedAt), "subnet started before its dependency finished")
+	assert.GreaterOrEqual(t, subnet.Duration(), 20*time.Millisecond, "failed nodes are timed too")
+	assert.True(t, instance.StartedAt.IsZero())
+	assert.Zero(t, instance.Duration())
+}
+
+func TestDAG_Execute_EmitsEvents(t *testing.T) {
+	instances := []config.ResourceInstance{
+		{ID: "aws:ec2:vpc.main", Kind: "aws:ec2:vpc", Name: "main"},
+		{ID: "aws:ec2:subnet.app", Kind: "aws:ec2:subnet", Name: "app", DependsOn: []string{"aws:ec2:vpc.main"}},
+		{ID: "aws:ec2:instance.web", Kind: "aws:ec2:instance", Name: "web", DependsOn: []string{"aws:ec2:subnet.app"}},
+	}
+
+	dag, err := NewDAG(instances)
+	require.NoError(t, err)
+
+	var events []Event
+	dag.SetEventHandler(func(event Event) {
+		events = append(events, event)
+	})
+	dag.Execute(context.Background(), 1, func(node *DAGNode) error {
+		if node.ID == "aws:ec2:subnet.app" {
+			return errors.New("subnet CIDR overlaps")
+		}
+		return nil
+	})
+
+	type summary struct {
+		Type   EventType
+		NodeID string
+		Error  string
+	}
+	var got []summary
+	for _, event := range events {
+		assert.False(t, event.Time.IsZero())
+		got = append(got, summary{event.Type, event.NodeID, event.Error})
+	}
+	assert.Equal(t, []summary{
+		{EventNodeStarted, "aws:ec2:vpc.main", ""},
+		{EventNodeCompleted, "aws:ec2:vpc.main", ""},
+		{EventNodeStarted, "aws:ec2:subnet.app", ""},
+		{EventNodeFailed, "aws:ec2:subnet.app", "subnet CIDR overlaps"},
+		{EventNodeSkipped, "aws:ec2:instance.web", "skipped because dependency aws:ec2:subnet.app failed"},
+	}, got)
+}
@@ -0,0 +1,186 @@
+package executor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ataiva-software/runestone/internal/config"
+)
+
+// JournalFormatVersion is the version of the serialized commit journal format
+const JournalFormatVersion = 1
+
+// DefaultJournalPath is where commit records its progress unless told otherwise
+const DefaultJournalPath = ".runestone-journal.json"
+
+// Journal records the resources a commit has applied, so that a commit that failed or was
+// interrupted part way can continue without applying them again. It is written when the
+// first resource completes and rewritten after every one after that, and is safe for
+// concurrent use.
+type Journal struct {
+	FormatVersion int                     `json:"format_version"`
+	Resources     map[string]JournalEntry `json:"resources"`
+
+	path  string
+	mutex sync.Mutex
+}
+
+// JournalEntry is a resource a commit completed
+type JournalEntry struct {
+	// Hash identifies the configuration the resource was applied from; an entry whose
+	// resource has been reconfigured since doesn't count as completed
+	Hash        string    `json:"hash"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// NewJournal creates an empty journal for path, removing any journal left there by an
+// earlier commit. Nothing is written to path until a resource is recorded, so a commit with
+// nothing to apply leaves no journal behind.
+func NewJournal(path string) (*Journal, error) {
+	j := &Journal{
+		FormatVersion: JournalFormatVersion,
+		Resources:     make(map[string]JournalEntry),
+		path:          path,
+	}
+	if err := j.Remove(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// LoadJournal reads the journal a previous commit left at path
+func LoadJournal(path string) (*Journal, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no commit journal found at %s; there is no failed commit to continue", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit journal: %w", err)
+	}
+
+	var j Journal
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, fmt.Errorf("failed to parse commit journal: %w", err)
+	}
+	if j.FormatVersion != JournalFormatVersion {
+		return nil, fmt.Errorf("unsupported commit journal format version %d (expected %d)", j.FormatVersion, JournalFormatVersion)
+	}
+	if j.Resources == nil {
+		j.Resources = make(map[string]JournalEntry)
+	}
+	j.path = path
+
+	return &j, nil
+}
+
+// Completed reports whether the journal records instance as applied from its current
+// configuration
+func (j *Journal) Completed(instance config.ResourceInstance) bool {
+	hash, err := InstanceHash(instance)
+	if err != nil {
+		return false
+	}
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	entry, exists := j.Resources[instance.ID]
+	return exists && entry.Hash == hash
+}
+
+// Record marks instance as applied and writes the journal
+func (j *Journal) Record(instance config.ResourceInstance) error {
+	hash, err := InstanceHash(instance)
+	if err != nil {
+		return fmt.Errorf("failed to hash %s: %w", instance.ID, err)
+	}
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	j.Resources[instance.ID] = JournalEntry{Hash: hash, CompletedAt: time.Now().UTC()}
+	return j.save()
+}
+
+// Forget removes resources from the journal, such as those a rollback deleted again, and
+// writes the journal
+func (j *Journal) Forget(ids ...string) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	for _, id := range ids {
+		delete(j.Resources, id)
+	}
+	return j.save()
+}
+
+// Remove deletes the journal file once the commit it tracks has finished
+func (j *Journal) Remove() error {
+	if err := os.Remove(j.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove commit journal: %w", err)
+	}
+	return nil
+}
+
+// Empty reports whether the journal records no resources, in which case a new journal
+// hasn't been written
+func (j *Journal) Empty() bool {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return len(j.Resources) == 0
+}
+
+// Path returns the file the journal is written to
+func (j *Journal) Path() string {
+	return j.path
+}
+
+// save writes the journal to a temporary file and renames it into place, so a commit
+// killed mid-write leaves the previous journal intact. The caller must hold the mutex.
+func (j *Journal) save() error {
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize commit journal: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(j.path), filepath.Base(j.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write commit journal: %w", err)
+	}
+	_, writeErr := tmp.Write(append(data, '\n'))
+	closeErr := tmp.Close()
+	if writeErr == nil {
+		writeErr = closeErr
+	}
+	if writeErr == nil {
+		writeErr = os.Rename(tmp.Name(), j.path)
+	}
+	if writeErr != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write commit journal: %w", writeErr)
+	}
+
+	return nil
+}
+
+// InstanceHash returns a content hash of a resource's configuration, before references
+// and secrets are resolved, so it only changes when the configuration does
+func InstanceHash(instance config.ResourceInstance) (string, error) {
+	// Maps marshal with sorted keys, so equal configurations hash the same
+	data, err := json.Marshal(struct {
+		Kind       string                 `json:"kind"`
+		Name       string                 `json:"name"`
+		Provider   string                 `json:"provider,omitempty"`
+		Properties map[string]interface{} `json:"properties"`
+	}{instance.Kind, instance.Name, instance.Provider, instance.Properties})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
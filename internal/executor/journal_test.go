@@ -0,0 +1,96 @@
+package executor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJournal_RecordAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.json")
+	bucket := config.ResourceInstance{
+		ID:         "aws:s3:bucket:logs",
+		Kind:       "aws:s3:bucket",
+		Name:       "logs",
+		Properties: map[string]interface{}{"versioning": true},
+	}
+	vpc := config.ResourceInstance{
+		ID:         "aws:ec2:vpc:main",
+		Kind:       "aws:ec2:vpc",
+		Name:       "main",
+		Properties: map[string]interface{}{"cidr_block": "10.0.0.0/16"},
+	}
+
+	journal, err := NewJournal(path)
+	require.NoError(t, err)
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "the journal isn't written until a resource is recorded")
+	assert.True(t, journal.Empty())
+	require.NoError(t, journal.Record(bucket))
+	assert.False(t, journal.Empty())
+	require.NoError(t, journal.Record(vpc))
+	require.NoError(t, journal.Forget(vpc.ID))
+
+	loaded, err := LoadJournal(path)
+	require.NoError(t, err)
+	assert.True(t, loaded.Completed(bucket))
+	assert.False(t, loaded.Completed(vpc), "forgotten resources aren't completed")
+
+	// A resource reconfigured since it was recorded has to be applied again
+	changed := bucket
+	changed.Properties = map[string]interface{}{"versioning": false}
+	assert.False(t, loaded.Completed(changed))
+
+	// No temporary files are left next to the journal
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	require.NoError(t, loaded.Remove())
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+	assert.NoError(t, loaded.Remove(), "removing a missing journal is not an error")
+}
+
+func TestLoadJournal_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := LoadJournal(filepath.Join(dir, "missing.json"))
+	assert.ErrorContains(t, err, "no commit journal found")
+
+	unsupported := filepath.Join(dir, "unsupported.json")
+	require.NoError(t, os.WriteFile(unsupported, []byte(`{"format_version": 99, "resources": {}}`), 0600))
+	_, err = LoadJournal(unsupported)
+	assert.ErrorContains(t, err, "unsupported commit journal format version 99")
+}
+
+func TestInstanceHash(t *testing.T) {
+	a := config.ResourceInstance{ID: "aws:s3:bucket:logs", Kind: "aws:s3:bucket", Name: "logs", Properties: map[string]interface{}{"a": 1, "b": "x"}}
+	b := config.ResourceInstance{ID: "aws:s3:bucket:logs", Kind: "aws:s3:bucket", Name: "logs", Properties: map[string]interface{}{"b": "x", "a": 1}}
+
+	hashA, err := InstanceHash(a)
+	require.NoError(t, err)
+	hashB, err := InstanceHash(b)
+	require.NoError(t, err)
+	assert.Equal(t, hashA, hashB)
+
+	b.Provider = "aws.west"
+	hashB, err = InstanceHash(b)
+	require.NoError(t, err)
+	assert.NotEqual(t, hashA, hashB, "a resource moved to another provider configuration is a different resource")
+}
+
+func TestNewJournal_RemovesPreviousJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.json")
+	require.NoError(t, os.WriteFile(path, []byte("{}"), 0600))
+
+	journal, err := NewJournal(path)
+	require.NoError(t, err)
+	assert.True(t, journal.Empty())
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,216 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/ataiva-software/runestone/internal/config"
+)
+
+// referencePattern matches ${provider:service:type.name.attribute} references to another
+// resource's computed attributes
+var referencePattern = regexp.MustCompile(`\$\{([a-z0-9_-]+:[a-z0-9_-]+:[a-z0-9_-]+\.[^.}\s]+)\.([A-Za-z0-9_]+)\}`)
+
+// Reference is a property expression that reads an attribute of another resource
+type Reference struct {
+	ResourceID string
+	Attribute  string
+}
+
+// FindReferences returns the distinct resource references in a set of properties, sorted
+// by resource ID and attribute
+func FindReferences(properties map[string]interface{}) []Reference {
+	seen := make(map[Reference]bool)
+	collectReferences(properties, seen)
+
+	references := make([]Reference, 0, len(seen))
+	for ref := range seen {
+		references = append(references, ref)
+	}
+	sort.Slice(references, func(i, j int) bool {
+		if references[i].ResourceID != references[j].ResourceID {
+			return references[i].ResourceID < references[j].ResourceID
+		}
+		return references[i].Attribute < references[j].Attribute
+	})
+
+	return references
+}
+
+func collectReferences(value interface{}, seen map[Reference]bool) {
+	switch v := value.(type) {
+	case string:
+		for _, match := range referencePattern.FindAllStringSubmatch(v, -1) {
+			seen[Reference{ResourceID: match[1], Attribute: match[2]}] = true
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			collectReferences(item, seen)
+		}
+	case []interface{}:
+		for _, item := range v {
+			collectReferences(item, seen)
+		}
+	}
+}
+
+// ResolveReferences returns a copy of properties with every resource reference replaced by
+// the referenced attribute from outputs. A property that is exactly one reference takes the
+// attribute's value as-is, so lists and numbers keep their types.
+func ResolveReferences(properties map[string]interface{}, outputs map[string]map[string]interface{}) (map[string]interface{}, error) {
+	resolved, err := resolveValue(properties, outputs)
+	if err != nil {
+		return nil, err
+	}
+	if resolved == nil {
+		return nil, nil
+	}
+	return resolved.(map[string]interface{}), nil
+}
+
+func resolveValue(value interface{}, outputs map[string]map[string]interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return resolveString(v, outputs)
+	case map[string]interface{}:
+		if v == nil {
+			return nil, nil
+		}
+		resolved := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			resolvedItem, err := resolveValue(item, outputs)
+			if err != nil {
+				return nil, err
+			}
+			resolved[key] = resolvedItem
+		}
+		return resolved, nil
+	case []interface{}:
+		resolved := make([]interface{}, len(v))
+		for i, item := range v {
+			resolvedItem, err := resolveValue(item, outputs)
+			if err != nil {
+				return nil, err
+			}
+			resolved[i] = resolvedItem
+		}
+		return resolved, nil
+	default:
+		return value, nil
+	}
+}
+
+func resolveString(value string, outputs map[string]map[string]interface{}) (interface{}, error) {
+	matches := referencePattern.FindAllStringSubmatchIndex(value, -1)
+	if len(matches) == 0 {
+		return value, nil
+	}
+
+	lookup := func(match []int) (interface{}, error) {
+		resourceID := value[match[2]:match[3]]
+		attribute := value[match[4]:match[5]]
+
+		resourceOutputs, exists := outputs[resourceID]
+		if !exists {
+			return nil, fmt.Errorf("resource %s has no outputs available", resourceID)
+		}
+		attributeValue, exists := resourceOutputs[attribute]
+		if !exists {
+			return nil, fmt.Errorf("resource %s has no attribute %s", resourceID, attribute)
+		}
+		return attributeValue, nil
+	}
+
+	// A lone reference keeps the attribute's original type
+	if len(matches) == 1 && matches[0][0] == 0 && matches[0][1] == len(value) {
+		return lookup(matches[0])
+	}
+
+	result := ""
+	last := 0
+	for _, match := range matches {
+		attributeValue, err := lookup(match)
+		if err != nil {
+			return nil, err
+		}
+		result += value[last:match[0]] + fmt.Sprintf("%v", attributeValue)
+		last = match[1]
+	}
+	result += value[last:]
+
+	return result, nil
+}
+
+// OutputResources returns the IDs of the resources that root outputs reference
+func OutputResources(declared map[string]config.Output) map[string]bool {
+	seen := make(map[Reference]bool)
+	for _, output := range declared {
+		collectReferences(output.Value, seen)
+	}
+
+	resourceIDs := make(map[string]bool, len(seen))
+	for ref := range seen {
+		resourceIDs[ref.ResourceID] = true
+	}
+	return resourceIDs
+}
+
+// EvaluateOutputs resolves the value of each root output against the state of the
+// resources it references. Outputs that can't be resolved, such as those referencing a
+// resource left out by --target, are omitted and reported together in the error.
+func EvaluateOutputs(declared map[string]config.Output, states map[string]map[string]interface{}) (map[string]interface{}, error) {
+	names := make([]string, 0, len(declared))
+	for name := range declared {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := make(map[string]interface{}, len(declared))
+	var errs []error
+	for _, name := range names {
+		value, err := resolveValue(declared[name].Value, states)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("output %s: %w", name, err))
+			continue
+		}
+		values[name] = value
+	}
+	return values, errors.Join(errs...)
+}
+
+// Outputs collects the post-apply state of resources so later resources can reference it.
+// It is safe for concurrent use.
+type Outputs struct {
+	values map[string]map[string]interface{}
+	mutex  sync.RWMutex
+}
+
+// NewOutputs creates an empty output store
+func NewOutputs() *Outputs {
+	return &Outputs{
+		values: make(map[string]map[string]interface{}),
+	}
+}
+
+// Set records the outputs of a resource
+func (o *Outputs) Set(resourceID string, values map[string]interface{}) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.values[resourceID] = values
+}
+
+// Snapshot returns a copy of all recorded outputs
+func (o *Outputs) Snapshot() map[string]map[string]interface{} {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	snapshot := make(map[string]map[string]interface{}, len(o.values))
+	for id, values := range o.values {
+		snapshot[id] = values
+	}
+	return snapshot
+}
@@ -0,0 +1,218 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindReferences(t *testing.T) {
+	properties := map[string]interface{}{
+		"vpc_id":     "${aws:ec2:vpc.main.vpc_id}",
+		"cidr_block": "10.0.1.0/24",
+		"tags": map[string]interface{}{
+			"Name": "subnet-in-${aws:ec2:vpc.main.vpc_id}",
+		},
+		"security_groups": []interface{}{"${aws:ec2:security_group.web.group_id}"},
+	}
+
+	assert.Equal(t, []Reference{
+		{ResourceID: "aws:ec2:security_group.web", Attribute: "group_id"},
+		{ResourceID: "aws:ec2:vpc.main", Attribute: "vpc_id"},
+	}, FindReferences(properties))
+
+	assert.Empty(t, FindReferences(map[string]interface{}{"name": "${project}-bucket"}))
+}
+
+func TestResolveReferences(t *testing.T) {
+	outputs := map[string]map[string]interface{}{
+		"aws:ec2:vpc.main": {
+			"vpc_id":  "vpc-123",
+			"subnets": []interface{}{"subnet-a", "subnet-b"},
+		},
+	}
+
+	properties := map[string]interface{}{
+		"vpc_id":  "${aws:ec2:vpc.main.vpc_id}",
+		"subnets": "${aws:ec2:vpc.main.subnets}",
+		"tags": map[string]interface{}{
+			"Name": "web-${aws:ec2:vpc.main.vpc_id}",
+		},
+		"count": 2,
+	}
+
+	resolved, err := ResolveReferences(properties, outputs)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"vpc_id":  "vpc-123",
+		"subnets": []interface{}{"subnet-a", "subnet-b"},
+		"tags": map[string]interface{}{
+			"Name": "web-vpc-123",
+		},
+		"count": 2,
+	}, resolved)
+
+	// The original properties are left untouched
+	assert.Equal(t, "${aws:ec2:vpc.main.vpc_id}", properties["vpc_id"])
+}
+
+func TestResolveReferences_Errors(t *testing.T) {
+	outputs := map[string]map[string]interface{}{
+		"aws:ec2:vpc.main": {"vpc_id": "vpc-123"},
+	}
+
+	_, err := ResolveReferences(map[string]interface{}{"vpc_id": "${aws:ec2:vpc.other.vpc_id}"}, outputs)
+	assert.ErrorContains(t, err, "resource aws:ec2:vpc.other has no outputs available")
+
+	_, err = ResolveReferences(map[string]interface{}{"arn": "${aws:ec2:vpc.main.arn}"}, outputs)
+	assert.ErrorContains(t, err, "resource aws:ec2:vpc.main has no attribute arn")
+}
+
+func TestEvaluateOutputs_CreatedResource(t *testing.T) {
+	instances := []config.ResourceInstance{
+		{ID: "aws:s3:bucket.site", Kind: "aws:s3:bucket", Name: "site"},
+	}
+	declared := map[string]config.Output{
+		"bucket_arn": {Value: "${aws:s3:bucket.site.arn}"},
+		"site_url":   {Value: "https://${aws:s3:bucket.site.bucket}.s3.amazonaws.com", Description: "Website URL"},
+		"region":     {Value: "us-east-1"},
+	}
+	assert.Equal(t, map[string]bool{"aws:s3:bucket.site": true}, OutputResources(declared))
+
+	dag, err := NewDAG(instances)
+	require.NoError(t, err)
+
+	// The bucket only has state once it has been created during execution
+	outputs := NewOutputs()
+	dag.Execute(context.Background(), 1, func(node *DAGNode) error {
+		outputs.Set(node.ID, map[string]interface{}{
+			"bucket": "site-bucket",
+			"arn":    "arn:aws:s3:::site-bucket",
+		})
+		return nil
+	})
+
+	values, err := EvaluateOutputs(declared, outputs.Snapshot())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"bucket_arn": "arn:aws:s3:::site-bucket",
+		"site_url":   "https://site-bucket.s3.amazonaws.com",
+		"region":     "us-east-1",
+	}, values)
+
+	// Outputs that can't be resolved are left out without hiding the others
+	values, err = EvaluateOutputs(map[string]config.Output{
+		"bucket_arn":  {Value: "${aws:s3:bucket.site.arn}"},
+		"db_endpoint": {Value: "${aws:rds:instance.db.endpoint}"},
+	}, outputs.Snapshot())
+	assert.ErrorContains(t, err, "output db_endpoint: resource aws:rds:instance.db has no outputs available")
+	assert.Equal(t, map[string]interface{}{"bucket_arn": "arn:aws:s3:::site-bucket"}, values)
+}
+
+func TestNewDAG_InfersReferenceDependencies(t *testing.T) {
+	instances := []config.ResourceInstance{
+		{
+			ID:   "aws:ec2:vpc.main",
+			Kind: "aws:ec2:vpc",
+			Name: "main",
+		},
+		{
+			ID:   "aws:ec2:subnet.public",
+			Kind: "aws:ec2:subnet",
+			Name: "public",
+			Properties: map[string]interface{}{
+				"vpc_id": "${aws:ec2:vpc.main.vpc_id}",
+			},
+		},
+	}
+
+	dag, err := NewDAG(instances)
+	require.NoError(t, err)
+
+	subnet, _ := dag.GetNode("aws:ec2:subnet.public")
+	assert.Equal(t, []string{"aws:ec2:vpc.main"}, subnet.Dependencies)
+	assert.Equal(t, [][]string{{"aws:ec2:vpc.main"}, {"aws:ec2:subnet.public"}}, dag.GetExecutionOrder())
+}
+
+func TestNewDAG_InfersResourceIDDependencies(t *testing.T) {
+	instances := []config.ResourceInstance{
+		{ID: "aws:ec2:instance.web", Kind: "aws:ec2:instance", Name: "web"},
+		{ID: "module:vpc.network", Kind: "module:vpc", Name: "network"},
+		{
+			ID:   "aws:cloudwatch:alarm.web-cpu",
+			Kind: "aws:cloudwatch:alarm",
+			Name: "web-cpu",
+			Properties: map[string]interface{}{
+				"targets":     []interface{}{map[string]interface{}{"resource": "aws:ec2:instance.web"}},
+				"description": "CPU alarm for aws:ec2:instance.web",
+			},
+		},
+		{
+			// Names aren't used to infer dependencies, even when they mention a module
+			ID:   "aws:ec2:instance.vpc-bastion",
+			Kind: "aws:ec2:instance",
+			Name: "vpc-bastion",
+		},
+	}
+
+	dag, err := NewDAG(instances)
+	require.NoError(t, err)
+
+	alarm, _ := dag.GetNode("aws:cloudwatch:alarm.web-cpu")
+	assert.Equal(t, []string{"aws:ec2:instance.web"}, alarm.Dependencies)
+
+	bastion, _ := dag.GetNode("aws:ec2:instance.vpc-bastion")
+	assert.Empty(t, bastion.Dependencies)
+}
+
+func TestNewDAG_ExplicitAndInferredDependenciesMerge(t *testing.T) {
+	instances := []config.ResourceInstance{
+		{ID: "aws:ec2:vpc.main", Kind: "aws:ec2:vpc", Name: "main"},
+		{
+			ID:         "aws:ec2:subnet.public",
+			Kind:       "aws:ec2:subnet",
+			Name:       "public",
+			DependsOn:  []string{"aws:ec2:vpc.main"},
+			Properties: map[string]interface{}{"vpc_id": "${aws:ec2:vpc.main.vpc_id}"},
+		},
+	}
+
+	dag, err := NewDAG(instances)
+	require.NoError(t, err)
+
+	subnet, _ := dag.GetNode("aws:ec2:subnet.public")
+	assert.Equal(t, []string{"aws:ec2:vpc.main"}, subnet.Dependencies)
+	vpc, _ := dag.GetNode("aws:ec2:vpc.main")
+	assert.Equal(t, []string{"aws:ec2:subnet.public"}, vpc.Dependents)
+}
+
+func TestNewDAG_ReferenceErrors(t *testing.T) {
+	_, err := NewDAG([]config.ResourceInstance{
+		{
+			ID:         "aws:ec2:subnet.public",
+			Kind:       "aws:ec2:subnet",
+			Name:       "public",
+			Properties: map[string]interface{}{"vpc_id": "${aws:ec2:vpc.missing.vpc_id}"},
+		},
+	})
+	assert.ErrorContains(t, err, "references unknown resource aws:ec2:vpc.missing")
+
+	_, err = NewDAG([]config.ResourceInstance{
+		{
+			ID:         "aws:ec2:security_group.a",
+			Kind:       "aws:ec2:security_group",
+			Name:       "a",
+			Properties: map[string]interface{}{"peer": "${aws:ec2:security_group.b.group_id}"},
+		},
+		{
+			ID:         "aws:ec2:security_group.b",
+			Kind:       "aws:ec2:security_group",
+			Name:       "b",
+			Properties: map[string]interface{}{"peer": "${aws:ec2:security_group.a.group_id}"},
+		},
+	})
+	assert.ErrorContains(t, err, "circular dependency detected: aws:ec2:security_group.a -> aws:ec2:security_group.b -> aws:ec2:security_group.a")
+}
@@ -0,0 +1,51 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ataiva-software/runestone/internal/providers"
+)
+
+// RetryPolicy controls how provider calls that fail with a transient error are retried
+type RetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used by commit unless overridden
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: 3,
+		BaseDelay:  time.Second,
+	}
+}
+
+// IsRetryableError reports whether an error is worth retrying: only errors a provider has
+// marked transient are, so that calls the provider has already retried aren't retried again.
+// Validation, authentication and other errors fail straight away.
+func IsRetryableError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	return providers.IsTransient(err)
+}
+
+// Do calls fn until it succeeds, fails with an error that isn't retryable, the retries run
+// out or the context is cancelled. fn is passed the attempt number, starting at 0. Do
+// returns the number of retries made along with the last error.
+func (p RetryPolicy) Do(ctx context.Context, fn func(attempt int) error) (int, error) {
+	for attempt := 0; ; attempt++ {
+		err := fn(attempt)
+		if err == nil || attempt >= p.MaxRetries || !IsRetryableError(err) {
+			return attempt, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return attempt, err
+		case <-time.After(providers.BackoffDelay(p.BaseDelay, attempt)):
+		}
+	}
+}
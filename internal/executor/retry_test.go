@@ -0,0 +1,91 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ataiva-software/runestone/internal/providers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		retryable bool
+	}{
+		{name: "nil", err: nil, retryable: false},
+		{name: "transient", err: providers.Transient(errors.New("api error Throttling: Rate exceeded")), retryable: true},
+		{name: "wrapped transient", err: fmt.Errorf("create failed: %w", providers.Transient(errors.New("ServiceUnavailable"))), retryable: true},
+		{name: "not marked", err: errors.New("api error Throttling: Rate exceeded"), retryable: false},
+		{name: "validation", err: errors.New("ValidationException: invalid CIDR"), retryable: false},
+		{name: "cancelled", err: providers.Transient(fmt.Errorf("create failed: %w", context.Canceled)), retryable: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.retryable, IsRetryableError(tt.err))
+		})
+	}
+}
+
+func TestRetryPolicy_Do(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond}
+
+	t.Run("succeeds after transient failures", func(t *testing.T) {
+		calls := 0
+		retries, err := policy.Do(context.Background(), func(attempt int) error {
+			calls++
+			if attempt < 2 {
+				return providers.Transient(errors.New("Throttling: Rate exceeded"))
+			}
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, retries)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("stops at the retry limit", func(t *testing.T) {
+		calls := 0
+		retries, err := policy.Do(context.Background(), func(attempt int) error {
+			calls++
+			return providers.Transient(errors.New("Throttling: Rate exceeded"))
+		})
+
+		assert.ErrorContains(t, err, "Throttling")
+		assert.Equal(t, 3, retries)
+		assert.Equal(t, 4, calls)
+	})
+
+	t.Run("doesn't retry permanent errors", func(t *testing.T) {
+		calls := 0
+		retries, err := policy.Do(context.Background(), func(attempt int) error {
+			calls++
+			return errors.New("ValidationException: invalid CIDR")
+		})
+
+		assert.ErrorContains(t, err, "ValidationException")
+		assert.Equal(t, 0, retries)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("stops when cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		calls := 0
+		retries, err := RetryPolicy{MaxRetries: 3, BaseDelay: time.Hour}.Do(ctx, func(attempt int) error {
+			calls++
+			return providers.Transient(errors.New("Throttling: Rate exceeded"))
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, 0, retries)
+		assert.Equal(t, 1, calls)
+	})
+}
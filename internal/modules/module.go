@@ -1,20 +1,39 @@
 package modules
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/ataiva-software/runestone/internal/config"
+	"gopkg.in/yaml.v3"
 )
 
+// gitSourcePrefix marks module sources that are cloned from a git repository
+const gitSourcePrefix = "git::"
+
 // Module represents a reusable infrastructure module
 type Module struct {
 	Name    string                 `yaml:"name"`
 	Source  string                 `yaml:"source"`
 	Version string                 `yaml:"version"`
 	Inputs  map[string]interface{} `yaml:"inputs"`
+
+	// CacheDir is where git sources are cloned; defaults to the user cache directory
+	CacheDir string `yaml:"-"`
+	// Path is the local directory the module was loaded from
+	Path string `yaml:"-"`
+	// Definition holds the module's parsed resources once loaded
+	Definition *config.ModuleDefinition `yaml:"-"`
 }
 
 // ModuleRegistry manages available modules
@@ -63,46 +82,204 @@ func (m *Module) Validate() error {
 	return nil
 }
 
-// Load loads the module from its source
+// Load loads the module from its source. Local sources (./, ../ or absolute paths) are read
+// in place; git::<url>[//subdir] sources are cloned at Version into the cache directory.
 func (m *Module) Load() error {
-	// For now, we support local file-based modules
-	if strings.HasPrefix(m.Source, "./") || strings.HasPrefix(m.Source, "/") {
-		return m.loadLocalModule()
+	var path string
+	var err error
+
+	switch {
+	case isLocalSource(m.Source):
+		path, err = m.loadLocalModule()
+	case strings.HasPrefix(m.Source, gitSourcePrefix):
+		path, err = m.loadGitModule()
+	default:
+		return fmt.Errorf("unsupported module source: %s", m.Source)
+	}
+	if err != nil {
+		return err
 	}
-	
-	return fmt.Errorf("unsupported module source: %s", m.Source)
-}
 
-// loadLocalModule loads a module from the local filesystem
-func (m *Module) loadLocalModule() error {
-	// Check if the source path exists
-	if _, err := os.Stat(m.Source); os.IsNotExist(err) {
-		return fmt.Errorf("module source path does not exist: %s", m.Source)
+	definition, err := loadDefinition(m.Name, path)
+	if err != nil {
+		return err
 	}
-	
-	// For now, just validate that it's a directory
+
+	m.Path = path
+	m.Definition = definition
+	return nil
+}
+
+func isLocalSource(source string) bool {
+	return strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../") || strings.HasPrefix(source, "/")
+}
+
+// loadLocalModule checks that a local module source is a directory
+func (m *Module) loadLocalModule() (string, error) {
 	info, err := os.Stat(m.Source)
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("module source path does not exist: %s", m.Source)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to stat module source: %w", err)
+		return "", fmt.Errorf("failed to stat module source: %w", err)
 	}
-	
+
 	if !info.IsDir() {
-		return fmt.Errorf("module source must be a directory: %s", m.Source)
+		return "", fmt.Errorf("module source must be a directory: %s", m.Source)
+	}
+
+	return m.Source, nil
+}
+
+// loadGitModule clones a git module source into the cache. An existing clone is updated to
+// the latest commit of Version, so a branch that has moved since it was cached is picked up;
+// the cached copy is used as is when the update fails, such as when offline.
+func (m *Module) loadGitModule() (string, error) {
+	repository, subdir := splitGitSource(strings.TrimPrefix(m.Source, gitSourcePrefix))
+
+	cacheDir := m.CacheDir
+	if cacheDir == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to determine module cache directory: %w", err)
+		}
+		cacheDir = filepath.Join(userCacheDir, "runestone", "modules")
+	}
+
+	// Key the clone on repository and version so different versions don't collide
+	sum := sha256.Sum256([]byte(repository + "@" + m.Version))
+	cloneDir := filepath.Join(cacheDir, hex.EncodeToString(sum[:8]))
+
+	if _, err := os.Stat(filepath.Join(cloneDir, ".git")); os.IsNotExist(err) {
+		if err := os.MkdirAll(cacheDir, 0755); err != nil {
+			return "", fmt.Errorf("failed to create module cache directory: %w", err)
+		}
+
+		args := []string{"clone", "--quiet", "--depth", "1"}
+		if m.Version != "" {
+			args = append(args, "--branch", m.Version)
+		}
+		args = append(args, repository, cloneDir)
+
+		if err := runGit(args...); err != nil {
+			os.RemoveAll(cloneDir)
+			return "", fmt.Errorf("failed to clone module source %s: %w", repository, err)
+		}
+	} else if err := updateClone(cloneDir, m.Version); err != nil {
+		slog.Warn("failed to update cached module source; using the cached copy", "module", m.Name, "source", repository, "error", err)
+	}
+
+	path := cloneDir
+	if subdir != "" {
+		path = filepath.Join(cloneDir, subdir)
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			return "", fmt.Errorf("module subdirectory %s not found in %s", subdir, repository)
+		}
+	}
+
+	return path, nil
+}
+
+// updateClone fetches the latest commit of version, or of the remote's default branch when
+// version is empty, into an existing clone and checks it out
+func updateClone(cloneDir, version string) error {
+	ref := version
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	if err := runGit("-C", cloneDir, "fetch", "--quiet", "--depth", "1", "origin", ref); err != nil {
+		return err
+	}
+	return runGit("-C", cloneDir, "reset", "--quiet", "--hard", "FETCH_HEAD")
+}
+
+// runGit runs a git command, including its output in the error when it fails
+func runGit(args ...string) error {
+	output, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
 	}
-	
 	return nil
 }
 
+// splitGitSource separates a repository URL from an optional //subdir suffix
+func splitGitSource(source string) (string, string) {
+	searchFrom := 0
+	if schemeEnd := strings.Index(source, "://"); schemeEnd >= 0 {
+		searchFrom = schemeEnd + len("://")
+	}
+
+	if idx := strings.Index(source[searchFrom:], "//"); idx >= 0 {
+		idx += searchFrom
+		return source[:idx], strings.Trim(source[idx+2:], "/")
+	}
+	return source, ""
+}
+
+// loadDefinition parses every YAML file in a module directory into one definition.
+// Errors name the module and the offending file.
+func loadDefinition(moduleName, dir string) (*config.ModuleDefinition, error) {
+	var files []string
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("module %s: failed to list files: %w", moduleName, err)
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+
+	definition := &config.ModuleDefinition{
+		Variables: make(map[string]interface{}),
+		Outputs:   make(map[string]interface{}),
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("module %s: failed to read %s: %w", moduleName, file, err)
+		}
+
+		var fileDefinition config.ModuleDefinition
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&fileDefinition); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("module %s: invalid definition in %s: %w", moduleName, file, err)
+		}
+
+		for i, resource := range fileDefinition.Resources {
+			if resource.Kind == "" || resource.Name == "" {
+				return nil, fmt.Errorf("module %s: resource %d in %s must have a kind and a name", moduleName, i+1, file)
+			}
+		}
+
+		for name, value := range fileDefinition.Variables {
+			definition.Variables[name] = value
+		}
+		for name, value := range fileDefinition.Outputs {
+			if _, exists := definition.Outputs[name]; exists {
+				return nil, fmt.Errorf("module %s: output %s in %s is already defined", moduleName, name, file)
+			}
+			definition.Outputs[name] = value
+		}
+		definition.Resources = append(definition.Resources, fileDefinition.Resources...)
+	}
+
+	return definition, nil
+}
+
 // LoadModule loads a module from source
 func (r *ModuleRegistry) LoadModule(ctx context.Context, name, source, version string) (*Module, error) {
-	// For now, we'll implement local file-based modules
-	if !strings.HasPrefix(source, "./") && !strings.HasPrefix(source, "/") {
+	if !isLocalSource(source) && !strings.HasPrefix(source, gitSourcePrefix) {
 		return nil, fmt.Errorf("unsupported module source: %s", source)
 	}
 
-	// Check if source exists
-	if _, err := os.Stat(source); os.IsNotExist(err) {
-		return nil, fmt.Errorf("module source not found: %s", source)
+	// Check if a local source exists; git sources are checked when cloned
+	if isLocalSource(source) {
+		if _, err := os.Stat(source); os.IsNotExist(err) {
+			return nil, fmt.Errorf("module source not found: %s", source)
+		}
 	}
 
 	module := &Module{
@@ -117,12 +294,25 @@ func (r *ModuleRegistry) LoadModule(ctx context.Context, name, source, version s
 
 // ExpandModule expands a module into resource instances
 func (r *ModuleRegistry) ExpandModule(ctx context.Context, name string, inputs map[string]interface{}) ([]config.ResourceInstance, error) {
-	_, exists := r.GetModule(name)
+	module, exists := r.GetModule(name)
 	if !exists {
 		return nil, fmt.Errorf("module not found: %s", name)
 	}
 
-	// For now, return empty slice as modules are not fully implemented
-	// In the future, this would parse the module's configuration and expand it
-	return []config.ResourceInstance{}, nil
+	// Modules that haven't been loaded have no resources yet
+	if module.Definition == nil {
+		return []config.ResourceInstance{}, nil
+	}
+
+	parser := config.NewParser()
+	parser.AddModule(name, module.Definition, inputs)
+	instances, err := parser.ExpandResources(nil)
+	if err != nil {
+		return nil, err
+	}
+	if instances == nil {
+		instances = []config.ResourceInstance{}
+	}
+
+	return instances, nil
 }
@@ -2,6 +2,9 @@ package modules
 
 import (
 	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -152,3 +155,147 @@ func TestModule_ExpandModule(t *testing.T) {
 		assert.Contains(t, err.Error(), "module not found")
 	})
 }
+
+func writeModuleFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+}
+
+const testModuleDefinition = `
+variables:
+  cidr_block: 10.0.0.0/16
+resources:
+  - kind: aws:ec2:vpc
+    name: "${module.name}-vpc"
+    properties:
+      cidr_block: "${cidr_block}"
+`
+
+func TestModule_LoadLocal(t *testing.T) {
+	dir := t.TempDir()
+	writeModuleFile(t, dir, "main.yaml", testModuleDefinition)
+
+	module := &Module{Name: "network", Source: dir}
+	require.NoError(t, module.Load())
+
+	assert.Equal(t, dir, module.Path)
+	require.NotNil(t, module.Definition)
+	require.Len(t, module.Definition.Resources, 1)
+	assert.Equal(t, "10.0.0.0/16", module.Definition.Variables["cidr_block"])
+}
+
+func TestModule_LoadReportsOffendingFile(t *testing.T) {
+	dir := t.TempDir()
+	writeModuleFile(t, dir, "main.yaml", testModuleDefinition)
+	writeModuleFile(t, dir, "extra.yaml", "resources:\n  - kind: aws:s3:bucket\n    nmae: logs\n")
+
+	module := &Module{Name: "network", Source: dir}
+	err := module.Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "module network")
+	assert.Contains(t, err.Error(), "extra.yaml")
+	assert.Contains(t, err.Error(), "field nmae not found")
+}
+
+func TestModule_LoadGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repo := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, "modules", "vpc"), 0755))
+	writeModuleFile(t, filepath.Join(repo, "modules", "vpc"), "main.yaml", testModuleDefinition)
+
+	gitCmd := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	gitCmd("init", "--quiet")
+	gitCmd("add", ".")
+	gitCmd("commit", "--quiet", "-m", "initial")
+	gitCmd("tag", "v1.0.0")
+
+	module := &Module{
+		Name:     "network",
+		Source:   "git::file://" + repo + "//modules/vpc",
+		Version:  "v1.0.0",
+		CacheDir: t.TempDir(),
+	}
+	require.NoError(t, module.Load())
+	require.NotNil(t, module.Definition)
+	assert.Len(t, module.Definition.Resources, 1)
+	assert.Equal(t, "vpc", filepath.Base(module.Path))
+
+	missing := &Module{
+		Name:     "network",
+		Source:   "git::file://" + repo,
+		Version:  "v9.9.9",
+		CacheDir: t.TempDir(),
+	}
+	assert.Error(t, missing.Load())
+}
+
+func TestModule_LoadGitUpdatesCachedClone(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repo := t.TempDir()
+	writeModuleFile(t, repo, "main.yaml", testModuleDefinition)
+
+	gitCmd := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	gitCmd("init", "--quiet", "--initial-branch", "main")
+	gitCmd("add", ".")
+	gitCmd("commit", "--quiet", "-m", "initial")
+
+	cacheDir := t.TempDir()
+	module := &Module{Name: "network", Source: "git::file://" + repo, Version: "main", CacheDir: cacheDir}
+	require.NoError(t, module.Load())
+	require.Len(t, module.Definition.Resources, 1)
+
+	// The branch moves on after the module was cached
+	writeModuleFile(t, repo, "extra.yaml", "resources:\n  - kind: aws:s3:bucket\n    name: \"${module.name}-logs\"\n")
+	gitCmd("add", ".")
+	gitCmd("commit", "--quiet", "-m", "add logs bucket")
+
+	updated := &Module{Name: "network", Source: "git::file://" + repo, Version: "main", CacheDir: cacheDir}
+	require.NoError(t, updated.Load())
+	assert.Equal(t, module.Path, updated.Path)
+	assert.Len(t, updated.Definition.Resources, 2)
+}
+
+func TestSplitGitSource(t *testing.T) {
+	repository, subdir := splitGitSource("https://github.com/acme/modules.git//network/vpc")
+	assert.Equal(t, "https://github.com/acme/modules.git", repository)
+	assert.Equal(t, "network/vpc", subdir)
+
+	repository, subdir = splitGitSource("git@github.com:acme/vpc.git")
+	assert.Equal(t, "git@github.com:acme/vpc.git", repository)
+	assert.Empty(t, subdir)
+}
+
+func TestModule_ExpandLoadedModule(t *testing.T) {
+	dir := t.TempDir()
+	writeModuleFile(t, dir, "main.yaml", testModuleDefinition)
+
+	module := &Module{Name: "network", Source: dir}
+	require.NoError(t, module.Load())
+
+	registry := NewModuleRegistry()
+	require.NoError(t, registry.RegisterModule(module))
+
+	instances, err := registry.ExpandModule(context.Background(), "network", map[string]interface{}{
+		"cidr_block": "172.16.0.0/16",
+	})
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.Equal(t, "aws:ec2:vpc.network-vpc", instances[0].ID)
+	assert.Equal(t, "172.16.0.0/16", instances[0].Properties["cidr_block"])
+}
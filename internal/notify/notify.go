@@ -0,0 +1,172 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/ataiva-software/runestone/internal/drift"
+	"github.com/ataiva-software/runestone/internal/providers"
+)
+
+// DefaultTimeout bounds each notification unless the configuration sets timeout
+const DefaultTimeout = 10 * time.Second
+
+// Event is the JSON payload sent when align finds drift it hasn't healed
+type Event struct {
+	Project     string     `json:"project"`
+	Environment string     `json:"environment"`
+	DetectedAt  time.Time  `json:"detected_at"`
+	Resources   []Resource `json:"resources"`
+}
+
+// Resource is a drifted resource in an event
+type Resource struct {
+	ID          string       `json:"id"`
+	Status      string       `json:"status"` // drifted, skipped or error
+	Error       string       `json:"error,omitempty"`
+	Missing     bool         `json:"missing,omitempty"` // The resource doesn't exist
+	Differences []Difference `json:"differences"`
+}
+
+// Difference is a single property that has drifted
+type Difference struct {
+	Property     string      `json:"property"`
+	Path         string      `json:"path,omitempty"` // JSON Pointer to the value
+	CurrentValue interface{} `json:"current_value,omitempty"`
+	DesiredValue interface{} `json:"desired_value,omitempty"`
+	DriftType    string      `json:"drift_type"`
+}
+
+// NewResource describes a drifted resource, with its differences ordered by property.
+// A non-nil healErr marks a resource whose auto-heal failed or, for a
+// *drift.HealSkippedError, was refused by its drift policy.
+func NewResource(id string, driftResult *providers.DriftResult, healErr error) Resource {
+	differences := driftResult.Differences
+	resource := Resource{
+		ID:          id,
+		Status:      "drifted",
+		Missing:     driftResult.CurrentState == nil,
+		Differences: make([]Difference, 0, len(differences)),
+	}
+	var skipped *drift.HealSkippedError
+	if errors.As(healErr, &skipped) {
+		resource.Status = "skipped"
+		resource.Error = healErr.Error()
+	} else if healErr != nil {
+		resource.Status = "error"
+		resource.Error = healErr.Error()
+	}
+
+	properties := make([]string, 0, len(differences))
+	for property := range differences {
+		properties = append(properties, property)
+	}
+	sort.Strings(properties)
+	for _, property := range properties {
+		diff := differences[property]
+		resource.Differences = append(resource.Differences, Difference{
+			Property:     diff.Property,
+			Path:         diff.Path,
+			CurrentValue: diff.CurrentValue,
+			DesiredValue: diff.DesiredValue,
+			DriftType:    string(diff.DriftType),
+		})
+	}
+
+	return resource
+}
+
+// Sink delivers drift events
+type Sink interface {
+	Send(ctx context.Context, event Event) error
+}
+
+// FromConfig returns the sink the notifications section configures, or nil when it
+// configures none
+func FromConfig(notifications *config.Notifications) (Sink, error) {
+	if notifications == nil || notifications.WebhookURL == "" {
+		return nil, nil
+	}
+
+	timeout := DefaultTimeout
+	if notifications.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(notifications.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid notifications.timeout %q: expected a duration such as 10s", notifications.Timeout)
+		}
+		if timeout <= 0 {
+			return nil, fmt.Errorf("notifications.timeout must be positive")
+		}
+	}
+
+	webhook, err := NewWebhook(notifications.WebhookURL, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// Webhook posts events as JSON to a URL, such as a Slack or Teams incoming webhook
+// behind a relay, or an alerting service
+type Webhook struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhook creates a sink posting to webhookURL, giving up on a delivery after timeout
+func NewWebhook(webhookURL string, timeout time.Duration) (*Webhook, error) {
+	parsed, err := url.Parse(webhookURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid notifications.webhook_url: expected an http or https URL")
+	}
+
+	return &Webhook{
+		url:    webhookURL,
+		client: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Send posts event to the webhook. Any response other than 2xx is an error. The URL is
+// left out of errors, since webhook URLs usually embed a secret token.
+func (w *Webhook) Send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to serialize notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "runestone")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		// The client's error quotes the URL
+		if ctx.Err() != nil {
+			return fmt.Errorf("webhook request failed: %w", ctx.Err())
+		}
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/ataiva-software/runestone/internal/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewResource(t *testing.T) {
+	driftResult := &providers.DriftResult{
+		HasDrift:     true,
+		CurrentState: map[string]interface{}{"versioning": false},
+		Differences: map[string]providers.DriftDifference{
+			"versioning":       {Property: "versioning", CurrentValue: false, DesiredValue: true, DriftType: providers.DriftTypeModified},
+			"tags.Environment": {Property: "tags.Environment", DesiredValue: "production", DriftType: providers.DriftTypeAdded},
+		},
+	}
+
+	resource := NewResource("aws:s3:bucket.logs", driftResult, nil)
+	assert.Equal(t, "drifted", resource.Status)
+	assert.False(t, resource.Missing)
+	require.Len(t, resource.Differences, 2)
+	assert.Equal(t, "tags.Environment", resource.Differences[0].Property)
+	assert.Equal(t, "modified", resource.Differences[1].DriftType)
+
+	resource = NewResource("aws:s3:bucket.logs", &providers.DriftResult{HasDrift: true}, errors.New("access denied"))
+	assert.Equal(t, "error", resource.Status)
+	assert.Equal(t, "access denied", resource.Error)
+	assert.True(t, resource.Missing)
+	assert.NotNil(t, resource.Differences, "differences serialize as a list even when empty")
+}
+
+func TestWebhook_Send(t *testing.T) {
+	var received Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	webhook, err := NewWebhook(server.URL, time.Second)
+	require.NoError(t, err)
+
+	event := Event{
+		Project:     "web",
+		Environment: "production",
+		DetectedAt:  time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Resources:   []Resource{{ID: "aws:s3:bucket.logs", Status: "drifted", Differences: []Difference{}}},
+	}
+	require.NoError(t, webhook.Send(context.Background(), event))
+	assert.Equal(t, event, received)
+}
+
+func TestWebhook_SendFailures(t *testing.T) {
+	t.Run("error status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		webhook, err := NewWebhook(server.URL+"/hooks/secret-token", time.Second)
+		require.NoError(t, err)
+		err = webhook.Send(context.Background(), Event{})
+		assert.ErrorContains(t, err, "webhook returned 500")
+		assert.NotContains(t, err.Error(), "secret-token")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer server.Close()
+		defer close(release)
+
+		webhook, err := NewWebhook(server.URL+"/hooks/secret-token", 50*time.Millisecond)
+		require.NoError(t, err)
+
+		start := time.Now()
+		err = webhook.Send(context.Background(), Event{})
+		assert.Error(t, err)
+		assert.NotContains(t, err.Error(), "secret-token")
+		assert.Less(t, time.Since(start), 5*time.Second)
+	})
+}
+
+func TestFromConfig(t *testing.T) {
+	sink, err := FromConfig(nil)
+	require.NoError(t, err)
+	assert.Nil(t, sink)
+
+	sink, err = FromConfig(&config.Notifications{WebhookURL: "https://hooks.example.com/drift", Timeout: "5s"})
+	require.NoError(t, err)
+	require.IsType(t, &Webhook{}, sink)
+	assert.Equal(t, 5*time.Second, sink.(*Webhook).client.Timeout)
+
+	_, err = FromConfig(&config.Notifications{WebhookURL: "hooks.example.com/drift"})
+	assert.ErrorContains(t, err, "expected an http or https URL")
+
+	_, err = FromConfig(&config.Notifications{WebhookURL: "https://hooks.example.com/drift", Timeout: "soon"})
+	assert.ErrorContains(t, err, "invalid notifications.timeout")
+}
@@ -4,14 +4,29 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ANSI escape codes used to color human output
+const (
+	ansiReset  = "\033[0m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiCyan   = "\033[36m"
 )
 
 // HumanFormatter implements the Formatter interface for human-readable output
-type HumanFormatter struct{}
+type HumanFormatter struct {
+	// color decorates output with ANSI colors and symbols; without it output is plain text
+	// suited to files and CI logs
+	color bool
+}
 
-// NewHumanFormatter creates a new human-readable formatter
+// NewHumanFormatter creates a new human-readable formatter with color and symbols
 func NewHumanFormatter() *HumanFormatter {
-	return &HumanFormatter{}
+	return &HumanFormatter{color: true}
 }
 
 // FormatBootstrapResult formats a bootstrap result for human reading
@@ -19,34 +34,131 @@ func (f *HumanFormatter) FormatBootstrapResult(result BootstrapResult) (string,
 	var sb strings.Builder
 
 	if result.Success {
-		sb.WriteString("✔ Bootstrap complete!\n")
+		sb.WriteString(f.headline("✔", ansiGreen, "Bootstrap complete!"))
 	} else {
-		sb.WriteString("❌ Bootstrap failed!\n")
+		sb.WriteString(f.headline("❌", ansiRed, "Bootstrap failed!"))
 	}
 
 	if len(result.ProvidersInstalled) > 0 {
-		sb.WriteString(fmt.Sprintf("✔ Installed %d providers: %s\n", 
-			len(result.ProvidersInstalled), strings.Join(result.ProvidersInstalled, ", ")))
+		sb.WriteString(f.headline("✔", "", fmt.Sprintf("Installed %d providers: %s",
+			len(result.ProvidersInstalled), strings.Join(result.ProvidersInstalled, ", "))))
+	}
+
+	sb.WriteString(f.headline("✔", "", fmt.Sprintf("Found %d resource instances", result.ResourceCount)))
+
+	if result.ModulesLoaded > 0 {
+		sb.WriteString(f.headline("✔", "", fmt.Sprintf("Loaded %d modules", result.ModulesLoaded)))
+	}
+
+	if len(result.PolicyViolations) > 0 {
+		sb.WriteString(f.headline("⚠️ ", ansiYellow, fmt.Sprintf("Found %d policy violations:", len(result.PolicyViolations))))
+		for _, violation := range result.PolicyViolations {
+			icon := f.getSeverityIcon(violation.Severity)
+			sb.WriteString(fmt.Sprintf("  %s %s: %s%s\n", icon, violation.ResourceID, violation.Message, waiverNote(violation)))
+		}
+	} else {
+		sb.WriteString(f.headline("✔", "", "No policy violations found"))
 	}
 
-	sb.WriteString(fmt.Sprintf("✔ Found %d resource instances\n", result.ResourceCount))
+	if result.Error != nil {
+		sb.WriteString(f.headline("❌", ansiRed, "Error: "+result.Error.Error()))
+	}
+
+	return sb.String(), nil
+}
+
+// FormatValidateResult formats a validate result for human reading
+func (f *HumanFormatter) FormatValidateResult(result ValidateResult) (string, error) {
+	var sb strings.Builder
+
+	if result.Success {
+		sb.WriteString(f.headline("✔", ansiGreen, "Configuration is valid"))
+	} else {
+		sb.WriteString(f.headline("❌", ansiRed, "Validation failed!"))
+	}
+
+	sb.WriteString(f.headline("✔", "", fmt.Sprintf("Found %d resource instances", result.ResourceCount)))
 
 	if result.ModulesLoaded > 0 {
-		sb.WriteString(fmt.Sprintf("✔ Loaded %d modules\n", result.ModulesLoaded))
+		sb.WriteString(f.headline("✔", "", fmt.Sprintf("Loaded %d modules", result.ModulesLoaded)))
+	}
+
+	if len(result.ValidationErrors) > 0 {
+		sb.WriteString(f.headline("❌", ansiRed, fmt.Sprintf("Found %d invalid resources:", len(result.ValidationErrors))))
+		for _, validationError := range result.ValidationErrors {
+			sb.WriteString(fmt.Sprintf("  - %s: %s\n", validationError.ResourceID, validationError.Message))
+		}
 	}
 
 	if len(result.PolicyViolations) > 0 {
-		sb.WriteString(fmt.Sprintf("⚠️  Found %d policy violations:\n", len(result.PolicyViolations)))
+		sb.WriteString(f.headline("⚠️ ", ansiYellow, fmt.Sprintf("Found %d policy violations:", len(result.PolicyViolations))))
 		for _, violation := range result.PolicyViolations {
 			icon := f.getSeverityIcon(violation.Severity)
-			sb.WriteString(fmt.Sprintf("  %s %s: %s\n", icon, violation.ResourceID, violation.Message))
+			sb.WriteString(fmt.Sprintf("  %s %s: %s%s\n", icon, violation.ResourceID, violation.Message, waiverNote(violation)))
 		}
 	} else {
-		sb.WriteString("✔ No policy violations found\n")
+		sb.WriteString(f.headline("✔", "", "No policy violations found"))
 	}
 
 	if result.Error != nil {
-		sb.WriteString(fmt.Sprintf("❌ Error: %s\n", result.Error.Error()))
+		sb.WriteString(f.headline("❌", ansiRed, "Error: "+result.Error.Error()))
+	}
+
+	return sb.String(), nil
+}
+
+// FormatLintResult formats a lint result for human reading
+func (f *HumanFormatter) FormatLintResult(result LintResult) (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString(f.headline("🔎", "", fmt.Sprintf("Linted %d resource instances", result.ResourceCount)))
+
+	if len(result.Findings) > 0 {
+		sb.WriteString(f.headline("⚠️ ", ansiYellow, fmt.Sprintf("Found %d issues:", len(result.Findings))))
+		for _, finding := range result.Findings {
+			icon := f.getSeverityIcon(finding.Severity)
+			sb.WriteString(fmt.Sprintf("  %s %s: %s%s\n", icon, finding.ResourceID, finding.Message, waiverNote(finding)))
+			if finding.Remediation != "" {
+				arrow := "→"
+				if !f.color {
+					arrow = "->"
+				}
+				sb.WriteString(fmt.Sprintf("     %s %s\n", arrow, finding.Remediation))
+			}
+		}
+	} else if result.Error == nil {
+		sb.WriteString(f.headline("✔", ansiGreen, "No issues found"))
+	}
+
+	if result.Error != nil {
+		sb.WriteString(f.headline("❌", ansiRed, "Error: "+result.Error.Error()))
+	}
+
+	return sb.String(), nil
+}
+
+// FormatScanResult formats a scan result for human reading. Only unmanaged resources are
+// listed.
+func (f *HumanFormatter) FormatScanResult(result ScanResult) (string, error) {
+	var sb strings.Builder
+
+	unmanaged := result.unmanagedCount()
+	sb.WriteString(f.headline("🔎", "", fmt.Sprintf("Scanned %d resources", len(result.Resources))))
+
+	if unmanaged > 0 {
+		sb.WriteString(f.headline("⚠️ ", ansiYellow, fmt.Sprintf("Found %d unmanaged resources:", unmanaged)))
+		for _, resource := range result.Resources {
+			if resource.Managed {
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("  %s %s %s%s\n", f.getStatusIcon(""), resource.Kind, resource.CloudID, scanDetails(resource)))
+		}
+	} else if result.Error == nil {
+		sb.WriteString(f.headline("✔", ansiGreen, "Every resource is managed"))
+	}
+
+	if result.Error != nil {
+		sb.WriteString(f.headline("❌", ansiRed, "Error: "+result.Error.Error()))
 	}
 
 	return sb.String(), nil
@@ -56,20 +168,21 @@ func (f *HumanFormatter) FormatBootstrapResult(result BootstrapResult) (string,
 func (f *HumanFormatter) FormatPreviewResult(result PreviewResult) (string, error) {
 	var sb strings.Builder
 
-	sb.WriteString("🔍 Inspecting live infrastructure...\n\n")
+	sb.WriteString(f.headline("🔍", "", "Inspecting live infrastructure...") + "\n")
 
 	if result.ChangesCount == 0 {
-		sb.WriteString("✔ No changes detected\n")
+		sb.WriteString(f.headline("✔", ansiGreen, "No changes detected"))
 	} else {
-		sb.WriteString(fmt.Sprintf("Changes detected:\n\n+ %d new resources will be created\n", result.ChangesCount))
-		
+		creates, updates, deletes := CountChanges(result.Changes)
+		sb.WriteString(fmt.Sprintf("Plan: %d to add, %d to change, %d to destroy\n", creates, updates, deletes))
+
 		if len(result.Changes) > 0 {
 			sb.WriteString("\nDetailed changes:\n")
 			for _, change := range result.Changes {
 				icon := f.getChangeIcon(change.Type)
 				title := strings.ToUpper(change.Type[:1]) + strings.ToLower(change.Type[1:])
-				sb.WriteString(fmt.Sprintf("%s %s %s.%s (%s)\n", 
-					icon, title, change.ResourceKind, change.ResourceName, change.ResourceKind))
+				sb.WriteString(f.paint(f.getChangeColor(change.Type), fmt.Sprintf("%s %s %s.%s (%s)",
+					icon, title, change.ResourceKind, change.ResourceName, change.ResourceKind)) + "\n")
 			}
 		}
 	}
@@ -84,7 +197,7 @@ func (f *HumanFormatter) FormatPreviewResult(result PreviewResult) (string, erro
 		}
 
 		if hasDrift {
-			sb.WriteString("\n🔄 Drift detected:\n")
+			sb.WriteString("\n" + f.headline("🔄", ansiYellow, "Drift detected:"))
 			for _, drift := range result.DriftResults {
 				if drift.HasDrift {
 					sb.WriteString(fmt.Sprintf("  - %s: %s\n", drift.ResourceName, strings.Join(drift.Changes, ", ")))
@@ -93,8 +206,16 @@ func (f *HumanFormatter) FormatPreviewResult(result PreviewResult) (string, erro
 		}
 	}
 
+	if len(result.PolicyViolations) > 0 {
+		sb.WriteString("\n" + f.headline("⚠️ ", ansiYellow, fmt.Sprintf("Found %d policy violations:", len(result.PolicyViolations))))
+		for _, violation := range result.PolicyViolations {
+			icon := f.getSeverityIcon(violation.Severity)
+			sb.WriteString(fmt.Sprintf("  %s %s: %s%s\n", icon, violation.ResourceID, violation.Message, waiverNote(violation)))
+		}
+	}
+
 	if result.Error != nil {
-		sb.WriteString(fmt.Sprintf("\n❌ Error: %s\n", result.Error.Error()))
+		sb.WriteString("\n" + f.headline("❌", ansiRed, "Error: "+result.Error.Error()))
 	} else {
 		sb.WriteString("\nNext: run 'runestone commit' to apply these changes.\n")
 	}
@@ -106,43 +227,61 @@ func (f *HumanFormatter) FormatPreviewResult(result PreviewResult) (string, erro
 func (f *HumanFormatter) FormatCommitResult(result CommitResult) (string, error) {
 	var sb strings.Builder
 
-	sb.WriteString("⏳ Committing infrastructure changes...\n\n")
+	sb.WriteString(f.headline("⏳", "", "Committing infrastructure changes...") + "\n")
 
 	for _, level := range result.ExecutionLevels {
 		sb.WriteString(fmt.Sprintf("--- Execution Level %d ---\n", level.Level))
 		for _, resource := range level.Resources {
-			sb.WriteString(fmt.Sprintf("+ Creating %s\n", resource))
+			sb.WriteString(f.paint(ansiGreen, "+ Creating "+resource) + "\n")
 		}
 		for _, resource := range level.Resources {
-			sb.WriteString(fmt.Sprintf("✓ Completed %s\n", resource))
+			if duration, ok := level.ResourceDurations[resource]; ok {
+				sb.WriteString(f.headline("✓", "", fmt.Sprintf("Completed %s (%s)", resource, f.formatDuration(duration))))
+			} else {
+				sb.WriteString(f.headline("✓", "", "Completed "+resource))
+			}
 		}
 		sb.WriteString("\n")
 	}
 
 	sb.WriteString("--- Execution Complete ---\n")
 	if result.Success {
-		sb.WriteString(fmt.Sprintf("✔ Commit complete (duration: %s)\n\n", f.formatDuration(result.TotalDuration)))
+		sb.WriteString(f.headline("✔", ansiGreen, fmt.Sprintf("Commit complete (duration: %s)", f.formatDuration(result.TotalDuration))) + "\n")
 		sb.WriteString("Changes applied:\n")
 		// This would typically show the actual changes applied
 		sb.WriteString(fmt.Sprintf("+ Applied %d resources\n", result.ResourcesApplied))
 	} else {
-		sb.WriteString("❌ Commit failed\n")
+		sb.WriteString(f.headline("❌", ansiRed, "Commit failed"))
 		if result.Error != nil {
 			sb.WriteString(fmt.Sprintf("Error: %s\n", result.Error.Error()))
 		}
 	}
 
+	if len(result.Outputs) > 0 {
+		sb.WriteString("\nOutputs:\n")
+		for _, name := range outputNames(result.Outputs) {
+			sb.WriteString(fmt.Sprintf("  %s = %v\n", name, result.Outputs[name]))
+		}
+	}
+
 	return sb.String(), nil
 }
 
 // FormatAlignResult formats an align result for human reading
 func (f *HumanFormatter) FormatAlignResult(result AlignResult) (string, error) {
+	if result.ReportOnly {
+		return f.formatDriftReport(result), nil
+	}
+
 	var sb strings.Builder
 
-	sb.WriteString("🔄 Aligning desired state with reality...\n")
+	sb.WriteString(f.headline("🔄", "", "Aligning desired state with reality..."))
 
 	if result.DriftDetected {
-		sb.WriteString(fmt.Sprintf("🔄 Drift detected and %d actions applied\n", result.ActionsApplied))
+		sb.WriteString(f.headline("🔄", ansiYellow, fmt.Sprintf("Drift detected and %d actions applied", result.ActionsApplied)))
+		if result.HealsSkipped > 0 {
+			sb.WriteString(f.headline("⏭", ansiYellow, fmt.Sprintf("Auto-heal skipped by drift policy: %d", result.HealsSkipped)))
+		}
 		
 		if len(result.Resources) > 0 {
 			for _, resource := range result.Resources {
@@ -154,11 +293,76 @@ func (f *HumanFormatter) FormatAlignResult(result AlignResult) (string, error) {
 			}
 		}
 	} else {
-		sb.WriteString("✔ Infrastructure aligned (no drift detected)\n")
+		sb.WriteString(f.headline("✔", ansiGreen, "Infrastructure aligned (no drift detected)"))
+	}
+
+	if result.Error != nil {
+		sb.WriteString(f.headline("❌", ansiRed, "Error: "+result.Error.Error()))
+	}
+
+	return sb.String(), nil
+}
+
+// formatDriftReport describes the current drift of every resource, without framing it as
+// changes to apply
+func (f *HumanFormatter) formatDriftReport(result AlignResult) string {
+	var sb strings.Builder
+
+	sb.WriteString(f.headline("🔍", "", "Drift report (read-only, nothing was changed)"))
+
+	drifted := 0
+	for _, resource := range result.Resources {
+		if resource.Status == "drifted" {
+			drifted++
+		}
+		icon := f.getStatusIcon(resource.Status)
+		sb.WriteString(fmt.Sprintf("  %s %s (%s)\n", icon, resource.Name, resource.Status))
+		for _, change := range resource.Changes {
+			sb.WriteString(fmt.Sprintf("    - %s\n", change))
+		}
+	}
+
+	if result.Error != nil {
+		sb.WriteString(f.headline("❌", ansiRed, "Error: "+result.Error.Error()))
+	} else if drifted > 0 {
+		sb.WriteString("\n" + f.paint(ansiYellow, fmt.Sprintf("%d of %d resources drifted", drifted, len(result.Resources))) + "\n")
+	} else {
+		sb.WriteString("\n" + f.headline("✔", ansiGreen, "No drift detected"))
+	}
+
+	return sb.String()
+}
+
+// FormatExportResult formats an export result for human reading
+func (f *HumanFormatter) FormatExportResult(result ExportResult) (string, error) {
+	var sb strings.Builder
+
+	missing := 0
+	for _, resource := range result.Resources {
+		sb.WriteString(fmt.Sprintf("%s\n", resource.ID))
+		switch {
+		case resource.Error != "":
+			sb.WriteString("  " + f.headline("❌", ansiRed, resource.Error))
+		case !resource.Exists:
+			missing++
+			sb.WriteString("  (does not exist)\n")
+		default:
+			state, err := yaml.Marshal(resource.State)
+			if err != nil {
+				return "", err
+			}
+			for _, line := range strings.Split(strings.TrimRight(string(state), "\n"), "\n") {
+				sb.WriteString(fmt.Sprintf("  %s\n", line))
+			}
+		}
+		sb.WriteString("\n")
 	}
 
 	if result.Error != nil {
-		sb.WriteString(fmt.Sprintf("❌ Error: %s\n", result.Error.Error()))
+		sb.WriteString(f.headline("❌", ansiRed, "Error: "+result.Error.Error()))
+	} else {
+		sb.WriteString(f.headline("✔", ansiGreen, fmt.Sprintf("Exported %d resources (%d not found, duration: %s)",
+			len(result.Resources), missing, f.formatDuration(result.Duration))))
 	}
 
 	return sb.String(), nil
@@ -166,6 +370,23 @@ func (f *HumanFormatter) FormatAlignResult(result AlignResult) (string, error) {
 
 // Helper methods
 
+// headline returns a line of text led by a decorative symbol and, if color is set, colored.
+// Without color the symbol is left out and the text returned as is.
+func (f *HumanFormatter) headline(symbol, color, text string) string {
+	if !f.color {
+		return text + "\n"
+	}
+	return f.paint(color, symbol+" "+text) + "\n"
+}
+
+// paint colors text with an ANSI color, unless color is off or empty
+func (f *HumanFormatter) paint(color, text string) string {
+	if !f.color || color == "" {
+		return text
+	}
+	return color + text + ansiReset
+}
+
 func (f *HumanFormatter) formatDuration(d time.Duration) string {
 	if d < time.Second {
 		return fmt.Sprintf("%dms", d.Milliseconds())
@@ -177,6 +398,9 @@ func (f *HumanFormatter) formatDuration(d time.Duration) string {
 }
 
 func (f *HumanFormatter) getSeverityIcon(severity string) string {
+	if !f.color {
+		return "[" + severity + "]"
+	}
 	switch severity {
 	case "error":
 		return "❌"
@@ -198,11 +422,30 @@ func (f *HumanFormatter) getChangeIcon(changeType string) string {
 	case "delete":
 		return "-"
 	default:
+		if !f.color {
+			return "*"
+		}
 		return "•"
 	}
 }
 
+func (f *HumanFormatter) getChangeColor(changeType string) string {
+	switch changeType {
+	case "create":
+		return ansiGreen
+	case "update":
+		return ansiYellow
+	case "delete":
+		return ansiRed
+	default:
+		return ""
+	}
+}
+
 func (f *HumanFormatter) getStatusIcon(status string) string {
+	if !f.color {
+		return "-"
+	}
 	switch status {
 	case "aligned":
 		return "✔"
@@ -210,6 +453,8 @@ func (f *HumanFormatter) getStatusIcon(status string) string {
 		return "🔄"
 	case "healed":
 		return "🔧"
+	case "skipped":
+		return "⏭"
 	case "error":
 		return "❌"
 	default:
@@ -0,0 +1,138 @@
+package output
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"unicode"
+
+	"github.com/ataiva-software/runestone/internal/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHumanFormatter_Color(t *testing.T) {
+	result := ValidateResult{
+		Success:       false,
+		ResourceCount: 2,
+		PolicyViolations: []policy.PolicyViolation{
+			{ResourceID: "aws:s3:bucket.logs", Message: "Bucket has no tags", Severity: "warning"},
+		},
+		Error: errors.New("validation failed"),
+	}
+
+	colored, err := NewFormatter(FormatHuman).FormatValidateResult(result)
+	require.NoError(t, err)
+	assert.Contains(t, colored, ansiRed+"❌ Validation failed!"+ansiReset)
+	assert.Contains(t, colored, "⚠️ aws:s3:bucket.logs: Bucket has no tags")
+
+	plain, err := NewFormatter(FormatHuman, WithColor(false)).FormatValidateResult(result)
+	require.NoError(t, err)
+	assert.Equal(t, "Validation failed!\n"+
+		"Found 2 resource instances\n"+
+		"Found 1 policy violations:\n"+
+		"  [warning] aws:s3:bucket.logs: Bucket has no tags\n"+
+		"Error: validation failed\n", plain)
+}
+
+func TestHumanFormatter_PlainHasNoDecoration(t *testing.T) {
+	formatter := NewFormatter(FormatHuman, WithColor(false))
+
+	outputs := make([]string, 0)
+	preview, err := formatter.FormatPreviewResult(PreviewResult{
+		ChangesCount: 1,
+		Changes:      []Change{{Type: "create", ResourceKind: "aws:s3:bucket", ResourceName: "logs"}},
+		DriftResults: []DriftResult{{ResourceName: "aws:s3:bucket.site", HasDrift: true, Changes: []string{"versioning"}}},
+	})
+	require.NoError(t, err)
+	outputs = append(outputs, preview)
+
+	align, err := formatter.FormatAlignResult(AlignResult{
+		DriftDetected: true,
+		Resources: []ResourceStatus{
+			{Name: "aws:s3:bucket.logs", Status: "healed"},
+			{Name: "aws:s3:bucket.site", Status: "drifted"},
+		},
+	})
+	require.NoError(t, err)
+	outputs = append(outputs, align)
+
+	lint, err := formatter.FormatLintResult(LintResult{
+		ResourceCount: 1,
+		Findings: []policy.PolicyViolation{
+			{ResourceID: "aws:s3:bucket.logs", Message: "Bucket has no tags", Severity: "warning", Remediation: "Add tags"},
+		},
+	})
+	require.NoError(t, err)
+	outputs = append(outputs, lint)
+
+	scan, err := formatter.FormatScanResult(ScanResult{
+		Resources: []ScannedResource{
+			{Kind: "aws:s3:bucket", CloudID: "logs", Name: "logs", Managed: true},
+			{Kind: "aws:ec2:instance", CloudID: "i-1", Name: "bastion", CreatedAt: time.Date(2024, 5, 20, 8, 30, 0, 0, time.UTC)},
+		},
+	})
+	require.NoError(t, err)
+	outputs = append(outputs, scan)
+
+	for _, output := range outputs {
+		assert.NotContains(t, output, "\033[")
+		for _, r := range output {
+			assert.True(t, r < unicode.MaxASCII, "Plain output should be ASCII, found %q in:\n%s", r, output)
+		}
+	}
+	assert.Contains(t, align, "  - aws:s3:bucket.logs (healed)")
+	assert.Contains(t, lint, "     -> Add tags")
+	assert.Equal(t, "Scanned 2 resources\n"+
+		"Found 1 unmanaged resources:\n"+
+		"  - aws:ec2:instance i-1 (name: bastion, created 2024-05-20)\n", scan)
+}
+
+func TestHumanFormatter_FormatPreviewResult_PlanSummary(t *testing.T) {
+	formatter := NewFormatter(FormatHuman, WithColor(false))
+
+	preview, err := formatter.FormatPreviewResult(PreviewResult{
+		ChangesCount: 3,
+		Changes: []Change{
+			{Type: "create", ResourceKind: "aws:s3:bucket", ResourceName: "logs"},
+			{Type: "create", ResourceKind: "aws:s3:bucket", ResourceName: "assets"},
+			{Type: "update", ResourceKind: "aws:ec2:instance", ResourceName: "web"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, preview, "Plan: 2 to add, 1 to change, 0 to destroy\n")
+	assert.NotContains(t, preview, "new resources", "Updates shouldn't be described as new resources")
+}
+
+func TestNewFormatter_ColorIgnoredByOtherFormats(t *testing.T) {
+	result := ValidateResult{Success: true, ResourceCount: 1}
+	for _, format := range []OutputFormat{FormatJSON, FormatMarkdown, FormatYAML, FormatJUnit} {
+		colored, err := NewFormatter(format).FormatValidateResult(result)
+		require.NoError(t, err)
+		plain, err := NewFormatter(format, WithColor(false)).FormatValidateResult(result)
+		require.NoError(t, err)
+		assert.Equal(t, colored, plain, "Format %s should not depend on color", format)
+		assert.NotContains(t, colored, "\033[", "Format %s should never contain ANSI codes", format)
+	}
+}
+
+func TestUseColor(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "output.txt"))
+	require.NoError(t, err)
+	defer file.Close()
+
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("TERM", "xterm-256color")
+	assert.False(t, UseColor(file, false), "Output redirected to a file should be plain")
+
+	if tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0); err == nil {
+		defer tty.Close()
+		assert.True(t, UseColor(tty, false))
+		assert.False(t, UseColor(tty, true), "--no-color should win over a terminal")
+
+		t.Setenv("NO_COLOR", "1")
+		assert.False(t, UseColor(tty, false), "NO_COLOR should win over a terminal")
+	}
+}
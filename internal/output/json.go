@@ -2,6 +2,7 @@ package output
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/ataiva-software/runestone/internal/policy"
 )
@@ -16,6 +17,11 @@ func NewJSONFormatter() *JSONFormatter {
 
 // FormatBootstrapResult formats a bootstrap result as JSON
 func (f *JSONFormatter) FormatBootstrapResult(result BootstrapResult) (string, error) {
+	return f.marshal(f.bootstrapOutput(result))
+}
+
+// bootstrapOutput builds the output document for a bootstrap result
+func (f *JSONFormatter) bootstrapOutput(result BootstrapResult) map[string]interface{} {
 	output := map[string]interface{}{
 		"success":             result.Success,
 		"providers_installed": result.ProvidersInstalled,
@@ -30,39 +36,126 @@ func (f *JSONFormatter) FormatBootstrapResult(result BootstrapResult) (string, e
 		output["error"] = result.Error.Error()
 	}
 
-	data, err := json.MarshalIndent(output, "", "  ")
-	if err != nil {
-		return "", err
+	return output
+}
+
+// FormatValidateResult formats a validate result as JSON
+func (f *JSONFormatter) FormatValidateResult(result ValidateResult) (string, error) {
+	return f.marshal(f.validateOutput(result))
+}
+
+// validateOutput builds the output document for a validate result
+func (f *JSONFormatter) validateOutput(result ValidateResult) map[string]interface{} {
+	output := map[string]interface{}{
+		"success":           result.Success,
+		"resource_count":    result.ResourceCount,
+		"modules_loaded":    result.ModulesLoaded,
+		"validation_errors": f.formatValidationErrors(result.ValidationErrors),
+		"policy_violations": f.formatPolicyViolations(result.PolicyViolations),
+		"duration_seconds":  result.Duration.Seconds(),
+		"has_errors":        len(result.ValidationErrors) > 0 || f.hasErrors(result.PolicyViolations),
 	}
 
-	return string(data), nil
+	if result.Error != nil {
+		output["error"] = result.Error.Error()
+	}
+
+	return output
+}
+
+// FormatLintResult formats a lint result as JSON
+func (f *JSONFormatter) FormatLintResult(result LintResult) (string, error) {
+	return f.marshal(f.lintOutput(result))
+}
+
+// lintOutput builds the output document for a lint result
+func (f *JSONFormatter) lintOutput(result LintResult) map[string]interface{} {
+	output := map[string]interface{}{
+		"success":          result.Success,
+		"resource_count":   result.ResourceCount,
+		"findings":         f.formatPolicyViolations(result.Findings),
+		"duration_seconds": result.Duration.Seconds(),
+	}
+
+	if result.Error != nil {
+		output["error"] = result.Error.Error()
+	}
+
+	return output
+}
+
+// FormatScanResult formats a scan result as JSON
+func (f *JSONFormatter) FormatScanResult(result ScanResult) (string, error) {
+	return f.marshal(f.scanOutput(result))
+}
+
+// scanOutput builds the output document for a scan result
+func (f *JSONFormatter) scanOutput(result ScanResult) map[string]interface{} {
+	resources := make([]map[string]interface{}, len(result.Resources))
+	for i, resource := range result.Resources {
+		resources[i] = map[string]interface{}{
+			"provider": resource.Provider,
+			"kind":     resource.Kind,
+			"cloud_id": resource.CloudID,
+			"managed":  resource.Managed,
+		}
+		if resource.Name != "" {
+			resources[i]["name"] = resource.Name
+		}
+		if !resource.CreatedAt.IsZero() {
+			resources[i]["created_at"] = resource.CreatedAt.UTC().Format(time.RFC3339)
+		}
+	}
+
+	output := map[string]interface{}{
+		"success":          result.Success,
+		"resource_count":   len(result.Resources),
+		"unmanaged_count":  result.unmanagedCount(),
+		"resources":        resources,
+		"duration_seconds": result.Duration.Seconds(),
+	}
+
+	if result.Error != nil {
+		output["error"] = result.Error.Error()
+	}
+
+	return output
 }
 
 // FormatPreviewResult formats a preview result as JSON
 func (f *JSONFormatter) FormatPreviewResult(result PreviewResult) (string, error) {
+	return f.marshal(f.previewOutput(result))
+}
+
+// previewOutput builds the output document for a preview result
+func (f *JSONFormatter) previewOutput(result PreviewResult) map[string]interface{} {
 	output := map[string]interface{}{
 		"success":          result.Success,
 		"changes_count":    result.ChangesCount,
+		"create_count":     result.CreateCount,
+		"update_count":     result.UpdateCount,
+		"delete_count":     result.DeleteCount,
 		"changes":          f.formatChanges(result.Changes),
-		"drift_results":    f.formatDriftResults(result.DriftResults),
-		"duration_seconds": result.Duration.Seconds(),
-		"has_drift":        f.hasDrift(result.DriftResults),
+		"drift_results":     f.formatDriftResults(result.DriftResults),
+		"policy_violations": f.formatPolicyViolations(result.PolicyViolations),
+		"duration_seconds":  result.Duration.Seconds(),
+		"has_drift":         f.hasDrift(result.DriftResults),
 	}
 
 	if result.Error != nil {
 		output["error"] = result.Error.Error()
 	}
 
-	data, err := json.MarshalIndent(output, "", "  ")
-	if err != nil {
-		return "", err
-	}
-
-	return string(data), nil
+	return output
 }
 
 // FormatCommitResult formats a commit result as JSON
 func (f *JSONFormatter) FormatCommitResult(result CommitResult) (string, error) {
+	return f.marshal(f.commitOutput(result))
+}
+
+// commitOutput builds the output document for a commit result
+func (f *JSONFormatter) commitOutput(result CommitResult) map[string]interface{} {
 	output := map[string]interface{}{
 		"success":                result.Success,
 		"resources_applied":      result.ResourcesApplied,
@@ -70,32 +163,83 @@ func (f *JSONFormatter) FormatCommitResult(result CommitResult) (string, error)
 		"total_duration_seconds": result.TotalDuration.Seconds(),
 	}
 
-	if result.Error != nil {
-		output["error"] = result.Error.Error()
+	if len(result.Outputs) > 0 {
+		output["outputs"] = result.Outputs
 	}
 
-	data, err := json.MarshalIndent(output, "", "  ")
-	if err != nil {
-		return "", err
+	if result.Error != nil {
+		output["error"] = result.Error.Error()
 	}
 
-	return string(data), nil
+	return output
 }
 
 // FormatAlignResult formats an align result as JSON
 func (f *JSONFormatter) FormatAlignResult(result AlignResult) (string, error) {
+	return f.marshal(f.alignOutput(result))
+}
+
+// alignOutput builds the output document for an align result
+func (f *JSONFormatter) alignOutput(result AlignResult) map[string]interface{} {
 	output := map[string]interface{}{
 		"success":          result.Success,
 		"drift_detected":   result.DriftDetected,
 		"actions_applied":  result.ActionsApplied,
+		"heals_skipped":    result.HealsSkipped,
 		"resources":        f.formatResourceStatuses(result.Resources),
 		"duration_seconds": result.Duration.Seconds(),
 	}
 
+	if result.ReportOnly {
+		output["report_only"] = true
+	}
+
+	if result.Error != nil {
+		output["error"] = result.Error.Error()
+	}
+
+	return output
+}
+
+// FormatExportResult formats an export result as JSON
+func (f *JSONFormatter) FormatExportResult(result ExportResult) (string, error) {
+	return f.marshal(f.exportOutput(result))
+}
+
+// exportOutput builds the output document for an export result. Resources are keyed by ID.
+func (f *JSONFormatter) exportOutput(result ExportResult) map[string]interface{} {
+	resources := make(map[string]interface{}, len(result.Resources))
+	for _, resource := range result.Resources {
+		entry := map[string]interface{}{
+			"kind":   resource.Kind,
+			"exists": resource.Exists,
+		}
+		if resource.State != nil {
+			entry["state"] = resource.State
+		}
+		if resource.Error != "" {
+			entry["error"] = resource.Error
+		}
+		resources[resource.ID] = entry
+	}
+
+	output := map[string]interface{}{
+		"success":          result.Success,
+		"resource_count":   len(result.Resources),
+		"resources":        resources,
+		"duration_seconds": result.Duration.Seconds(),
+	}
+
 	if result.Error != nil {
 		output["error"] = result.Error.Error()
 	}
 
+	return output
+}
+
+// Helper methods
+
+func (f *JSONFormatter) marshal(output map[string]interface{}) (string, error) {
 	data, err := json.MarshalIndent(output, "", "  ")
 	if err != nil {
 		return "", err
@@ -104,8 +248,6 @@ func (f *JSONFormatter) FormatAlignResult(result AlignResult) (string, error) {
 	return string(data), nil
 }
 
-// Helper methods
-
 func (f *JSONFormatter) formatPolicyViolations(violations []policy.PolicyViolation) []map[string]interface{} {
 	result := make([]map[string]interface{}, len(violations))
 	for i, v := range violations {
@@ -114,6 +256,24 @@ func (f *JSONFormatter) formatPolicyViolations(violations []policy.PolicyViolati
 			"rule_name":     v.Rule.Name,
 			"message":       v.Message,
 			"severity":      v.Severity,
+			"waived":        v.Waived,
+		}
+		if v.WaiverReason != "" {
+			result[i]["waiver_reason"] = v.WaiverReason
+		}
+		if v.Remediation != "" {
+			result[i]["remediation"] = v.Remediation
+		}
+	}
+	return result
+}
+
+func (f *JSONFormatter) formatValidationErrors(validationErrors []ValidationError) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(validationErrors))
+	for i, v := range validationErrors {
+		result[i] = map[string]interface{}{
+			"resource_name": v.ResourceID,
+			"message":       v.Message,
 		}
 	}
 	return result
@@ -139,6 +299,23 @@ func (f *JSONFormatter) formatDriftResults(driftResults []DriftResult) []map[str
 			"resource_name": d.ResourceName,
 			"has_drift":     d.HasDrift,
 			"changes":       d.Changes,
+			"differences":   f.formatDriftDifferences(d.Differences),
+		}
+	}
+	return result
+}
+
+func (f *JSONFormatter) formatDriftDifferences(differences []DriftDifference) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(differences))
+	for i, difference := range differences {
+		result[i] = map[string]interface{}{
+			"property":      difference.Property,
+			"current_value": difference.CurrentValue,
+			"desired_value": difference.DesiredValue,
+			"drift_type":    difference.DriftType,
+		}
+		if difference.Path != "" {
+			result[i]["path"] = difference.Path
 		}
 	}
 	return result
@@ -152,6 +329,13 @@ func (f *JSONFormatter) formatExecutionLevels(levels []ExecutionLevel) []map[str
 			"resources":        l.Resources,
 			"duration_seconds": l.Duration.Seconds(),
 		}
+		if len(l.ResourceDurations) > 0 {
+			durations := make(map[string]float64, len(l.ResourceDurations))
+			for resource, duration := range l.ResourceDurations {
+				durations[resource] = duration.Seconds()
+			}
+			result[i]["resource_durations_seconds"] = durations
+		}
 	}
 	return result
 }
@@ -165,13 +349,16 @@ func (f *JSONFormatter) formatResourceStatuses(resources []ResourceStatus) []map
 			"changes":          r.Changes,
 			"duration_seconds": r.Duration.Seconds(),
 		}
+		if len(r.Differences) > 0 {
+			result[i]["differences"] = f.formatDriftDifferences(r.Differences)
+		}
 	}
 	return result
 }
 
 func (f *JSONFormatter) hasErrors(violations []policy.PolicyViolation) bool {
 	for _, v := range violations {
-		if v.Severity == "error" {
+		if v.Severity == "error" && !v.Waived {
 			return true
 		}
 	}
@@ -95,6 +95,35 @@ func TestJSONFormatter_FormatBootstrapResult(t *testing.T) {
 	}
 }
 
+func TestJSONFormatter_FormatValidateResult(t *testing.T) {
+	formatter := NewJSONFormatter()
+
+	output, err := formatter.FormatValidateResult(ValidateResult{
+		Success:       false,
+		ResourceCount: 2,
+		ValidationErrors: []ValidationError{
+			{ResourceID: "aws:s3:bucket.logs", Message: "bucket name cannot be empty"},
+		},
+		PolicyViolations: []policy.PolicyViolation{},
+		Duration:         time.Millisecond * 500,
+	})
+	require.NoError(t, err)
+
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+
+	assert.Equal(t, false, result["success"])
+	assert.Equal(t, float64(2), result["resource_count"])
+	assert.Equal(t, true, result["has_errors"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"resource_name": "aws:s3:bucket.logs",
+			"message":       "bucket name cannot be empty",
+		},
+	}, result["validation_errors"])
+	assert.Equal(t, 0.5, result["duration_seconds"])
+}
+
 func TestJSONFormatter_FormatPreviewResult(t *testing.T) {
 	formatter := NewJSONFormatter()
 
@@ -115,6 +144,9 @@ func TestJSONFormatter_FormatPreviewResult(t *testing.T) {
 			expected: map[string]interface{}{
 				"success":       true,
 				"changes_count": float64(0),
+				"create_count":  float64(0),
+				"update_count":  float64(0),
+				"delete_count":  float64(0),
 				"changes":       []interface{}{},
 				"drift_results": []interface{}{},
 				"duration_seconds": float64(1),
@@ -126,6 +158,8 @@ func TestJSONFormatter_FormatPreviewResult(t *testing.T) {
 			result: PreviewResult{
 				Success:      true,
 				ChangesCount: 2,
+				CreateCount:  1,
+				UpdateCount:  1,
 				Changes: []Change{
 					{
 						Type:         "create",
@@ -152,6 +186,9 @@ func TestJSONFormatter_FormatPreviewResult(t *testing.T) {
 			expected: map[string]interface{}{
 				"success":       true,
 				"changes_count": float64(2),
+				"create_count":  float64(1),
+				"update_count":  float64(1),
+				"delete_count":  float64(0),
 				"changes": []interface{}{
 					map[string]interface{}{
 						"type":          "create",
@@ -190,12 +227,64 @@ func TestJSONFormatter_FormatPreviewResult(t *testing.T) {
 
 			assert.Equal(t, tt.expected["success"], result["success"])
 			assert.Equal(t, tt.expected["changes_count"], result["changes_count"])
+			assert.Equal(t, tt.expected["create_count"], result["create_count"])
+			assert.Equal(t, tt.expected["update_count"], result["update_count"])
+			assert.Equal(t, tt.expected["delete_count"], result["delete_count"])
 			assert.Equal(t, tt.expected["duration_seconds"], result["duration_seconds"])
 			assert.Equal(t, tt.expected["has_drift"], result["has_drift"])
 		})
 	}
 }
 
+func TestJSONFormatter_FormatPreviewResult_DriftDifferences(t *testing.T) {
+	formatter := NewJSONFormatter()
+
+	output, err := formatter.FormatPreviewResult(PreviewResult{
+		Success: true,
+		DriftResults: []DriftResult{
+			{
+				ResourceName: "aws:s3:bucket.logs",
+				HasDrift:     true,
+				Changes:      []string{"Property versioning: false → true"},
+				Differences: []DriftDifference{
+					{Property: "versioning", CurrentValue: false, DesiredValue: true, DriftType: "modified"},
+					{Property: "tags.Owner", CurrentValue: nil, DesiredValue: "platform", DriftType: "added"},
+				},
+			},
+			{
+				ResourceName: "aws:s3:bucket.data",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+
+	driftResults := result["drift_results"].([]interface{})
+	require.Len(t, driftResults, 2)
+
+	drifted := driftResults[0].(map[string]interface{})
+	assert.Equal(t, []interface{}{"Property versioning: false → true"}, drifted["changes"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"property":      "versioning",
+			"current_value": false,
+			"desired_value": true,
+			"drift_type":    "modified",
+		},
+		map[string]interface{}{
+			"property":      "tags.Owner",
+			"current_value": nil,
+			"desired_value": "platform",
+			"drift_type":    "added",
+		},
+	}, drifted["differences"])
+
+	clean := driftResults[1].(map[string]interface{})
+	assert.Equal(t, []interface{}{}, clean["differences"])
+}
+
 func TestJSONFormatter_FormatCommitResult(t *testing.T) {
 	formatter := NewJSONFormatter()
 
@@ -209,9 +298,12 @@ func TestJSONFormatter_FormatCommitResult(t *testing.T) {
 				Duration:  time.Second * 30,
 			},
 			{
-				Level: 2,
+				Level:     2,
 				Resources: []string{"aws:ec2:instance.web-1"},
 				Duration:  time.Second * 45,
+				ResourceDurations: map[string]time.Duration{
+					"aws:ec2:instance.web-1": time.Second * 44,
+				},
 			},
 		},
 		TotalDuration: time.Second * 75,
@@ -234,6 +326,191 @@ func TestJSONFormatter_FormatCommitResult(t *testing.T) {
 	level1 := levels[0].(map[string]interface{})
 	assert.Equal(t, float64(1), level1["level"])
 	assert.Equal(t, float64(30), level1["duration_seconds"])
+	assert.NotContains(t, level1, "resource_durations_seconds")
+
+	level2 := levels[1].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"aws:ec2:instance.web-1": float64(44)}, level2["resource_durations_seconds"])
+}
+
+func TestJSONFormatter_FormatCommitResult_Outputs(t *testing.T) {
+	formatter := NewJSONFormatter()
+
+	output, err := formatter.FormatCommitResult(CommitResult{
+		Success: true,
+		Outputs: map[string]interface{}{
+			"bucket_arn":  "arn:aws:s3:::site-bucket",
+			"db_password": "***",
+		},
+	})
+	require.NoError(t, err)
+
+	var jsonResult map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(output), &jsonResult))
+	assert.Equal(t, map[string]interface{}{
+		"bucket_arn":  "arn:aws:s3:::site-bucket",
+		"db_password": "***",
+	}, jsonResult["outputs"])
+
+	// Commits without outputs leave the key out
+	output, err = formatter.FormatCommitResult(CommitResult{Success: true})
+	require.NoError(t, err)
+	assert.NotContains(t, output, "outputs")
+}
+
+func TestJSONFormatter_FormatAlignResult_ReportOnly(t *testing.T) {
+	formatter := NewJSONFormatter()
+
+	output, err := formatter.FormatAlignResult(AlignResult{
+		Success:       true,
+		DriftDetected: true,
+		ReportOnly:    true,
+		Resources: []ResourceStatus{
+			{Name: "aws:s3:bucket.logs", Status: "aligned"},
+			{
+				Name:    "aws:s3:bucket.site",
+				Status:  "drifted",
+				Changes: []string{"Property versioning: false → true"},
+				Differences: []DriftDifference{
+					{Property: "versioning", Path: "/versioning", CurrentValue: false, DesiredValue: true, DriftType: "modified"},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	var jsonResult map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(output), &jsonResult))
+	assert.Equal(t, true, jsonResult["report_only"])
+
+	resources := jsonResult["resources"].([]interface{})
+	require.Len(t, resources, 2)
+	assert.NotContains(t, resources[0].(map[string]interface{}), "differences")
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"property":      "versioning",
+			"path":          "/versioning",
+			"current_value": false,
+			"desired_value": true,
+			"drift_type":    "modified",
+		},
+	}, resources[1].(map[string]interface{})["differences"])
+}
+
+func TestHumanFormatter_FormatAlignResult_ReportOnly(t *testing.T) {
+	formatter := NewHumanFormatter()
+
+	output, err := formatter.FormatAlignResult(AlignResult{
+		Success:       true,
+		DriftDetected: true,
+		ReportOnly:    true,
+		Resources: []ResourceStatus{
+			{Name: "aws:s3:bucket.logs", Status: "aligned"},
+			{Name: "aws:s3:bucket.site", Status: "drifted", Changes: []string{"Property versioning: false → true"}},
+		},
+	})
+	require.NoError(t, err)
+
+	// Drift is framed as the current state, not as actions taken
+	assert.Contains(t, output, "Drift report")
+	assert.Contains(t, output, "aws:s3:bucket.logs (aligned)")
+	assert.Contains(t, output, "- Property versioning: false → true")
+	assert.Contains(t, output, "1 of 2 resources drifted")
+	assert.NotContains(t, output, "actions applied")
+}
+
+func TestHumanFormatter_FormatAlignResult_HealsSkipped(t *testing.T) {
+	formatter := NewHumanFormatter()
+
+	output, err := formatter.FormatAlignResult(AlignResult{
+		Success:        true,
+		DriftDetected:  true,
+		ActionsApplied: 1,
+		HealsSkipped:   1,
+		Resources: []ResourceStatus{
+			{Name: "aws:s3:bucket.logs", Status: "healed"},
+			{Name: "aws:rds:instance.db", Status: "skipped", Changes: []string{"Resource does not exist", "Auto-heal skipped: the resource does not exist and heal_drift_types doesn't include missing"}},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, output, "Auto-heal skipped by drift policy: 1")
+	assert.Contains(t, output, "aws:rds:instance.db (skipped)")
+
+	jsonOutput, err := NewJSONFormatter().FormatAlignResult(AlignResult{HealsSkipped: 1})
+	require.NoError(t, err)
+	var jsonResult map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(jsonOutput), &jsonResult))
+	assert.Equal(t, float64(1), jsonResult["heals_skipped"])
+}
+
+func TestJSONFormatter_FormatExportResult(t *testing.T) {
+	formatter := NewJSONFormatter()
+
+	output, err := formatter.FormatExportResult(ExportResult{
+		Success: true,
+		Resources: []ExportedResource{
+			{
+				ID:     "aws:s3:bucket.logs",
+				Kind:   "aws:s3:bucket",
+				Exists: true,
+				State:  map[string]interface{}{"versioning": true},
+			},
+			{ID: "aws:s3:bucket.assets", Kind: "aws:s3:bucket"},
+		},
+		Duration: time.Second * 2,
+	})
+	require.NoError(t, err)
+
+	var jsonResult map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(output), &jsonResult))
+
+	assert.Equal(t, true, jsonResult["success"])
+	assert.Equal(t, float64(2), jsonResult["resource_count"])
+
+	resources := jsonResult["resources"].(map[string]interface{})
+	logs := resources["aws:s3:bucket.logs"].(map[string]interface{})
+	assert.Equal(t, true, logs["exists"])
+	assert.Equal(t, map[string]interface{}{"versioning": true}, logs["state"])
+
+	assets := resources["aws:s3:bucket.assets"].(map[string]interface{})
+	assert.Equal(t, false, assets["exists"])
+	assert.NotContains(t, assets, "state")
+}
+
+func TestJSONFormatter_FormatScanResult(t *testing.T) {
+	formatter := NewJSONFormatter()
+
+	output, err := formatter.FormatScanResult(ScanResult{
+		Success: true,
+		Resources: []ScannedResource{
+			{Provider: "aws", Kind: "aws:s3:bucket", CloudID: "logs", Name: "logs", CreatedAt: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), Managed: true},
+			{Provider: "aws", Kind: "aws:ec2:vpc", CloudID: "vpc-1"},
+		},
+		Duration: time.Second,
+	})
+	require.NoError(t, err)
+
+	var jsonResult map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(output), &jsonResult))
+
+	assert.Equal(t, float64(2), jsonResult["resource_count"])
+	assert.Equal(t, float64(1), jsonResult["unmanaged_count"])
+
+	resources := jsonResult["resources"].([]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"provider":   "aws",
+		"kind":       "aws:s3:bucket",
+		"cloud_id":   "logs",
+		"name":       "logs",
+		"created_at": "2024-03-01T12:00:00Z",
+		"managed":    true,
+	}, resources[0])
+	assert.Equal(t, map[string]interface{}{
+		"provider": "aws",
+		"kind":     "aws:ec2:vpc",
+		"cloud_id": "vpc-1",
+		"managed":  false,
+	}, resources[1], "Unknown names and creation dates should be left out")
 }
 
 func TestMarkdownFormatter_FormatPreviewResult(t *testing.T) {
@@ -294,9 +571,12 @@ func TestMarkdownFormatter_FormatCommitResult(t *testing.T) {
 				Duration:  time.Second * 30,
 			},
 			{
-				Level: 2,
+				Level:     2,
 				Resources: []string{"aws:ec2:instance.web-1"},
 				Duration:  time.Second * 45,
+				ResourceDurations: map[string]time.Duration{
+					"aws:ec2:instance.web-1": time.Second * 44,
+				},
 			},
 		},
 		TotalDuration: time.Second * 75,
@@ -316,5 +596,5 @@ func TestMarkdownFormatter_FormatCommitResult(t *testing.T) {
 	assert.Contains(t, output, "- aws:s3:bucket.logs")
 	assert.Contains(t, output, "- aws:rds:instance.db")
 	assert.Contains(t, output, "### Level 2 (45")
-	assert.Contains(t, output, "- aws:ec2:instance.web-1")
+	assert.Contains(t, output, "- aws:ec2:instance.web-1 (44.0s)")
 }
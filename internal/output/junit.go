@@ -0,0 +1,296 @@
+package output
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ataiva-software/runestone/internal/policy"
+)
+
+// JUnitFormatter implements the Formatter interface for JUnit XML output, so CI systems can
+// show policy violations, drift and failed resources as test results.
+//
+// Error-severity policy violations, drifted resources and failed resources are failures.
+// Warning and info violations, and waived violations, are skipped with a message. Resources
+// without problems are passing test cases.
+type JUnitFormatter struct{}
+
+// NewJUnitFormatter creates a new JUnit XML formatter
+func NewJUnitFormatter() *JUnitFormatter {
+	return &JUnitFormatter{}
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr,omitempty"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// FormatBootstrapResult formats a bootstrap result as JUnit XML
+func (f *JUnitFormatter) FormatBootstrapResult(result BootstrapResult) (string, error) {
+	suites := []junitTestSuite{f.policySuite(result.PolicyViolations, nil)}
+	return f.marshal("bootstrap", result.Duration, result.Error, suites)
+}
+
+// FormatValidateResult formats a validate result as JUnit XML
+func (f *JUnitFormatter) FormatValidateResult(result ValidateResult) (string, error) {
+	validation := junitTestSuite{Name: "validation"}
+	for _, validationError := range result.ValidationErrors {
+		validation.TestCases = append(validation.TestCases, junitTestCase{
+			Name:      validationError.ResourceID,
+			ClassName: "validation",
+			Failure: &junitFailure{
+				Message: validationError.Message,
+				Type:    "validation",
+			},
+		})
+	}
+
+	suites := []junitTestSuite{validation, f.policySuite(result.PolicyViolations, nil)}
+	return f.marshal("validate", result.Duration, result.Error, suites)
+}
+
+// FormatLintResult formats a lint result as JUnit XML, with a test case per issue
+func (f *JUnitFormatter) FormatLintResult(result LintResult) (string, error) {
+	suites := []junitTestSuite{f.policySuite(result.Findings, nil)}
+	return f.marshal("lint", result.Duration, result.Error, suites)
+}
+
+// FormatScanResult formats a scan result as JUnit XML, with a test case per resource.
+// Unmanaged resources are failures.
+func (f *JUnitFormatter) FormatScanResult(result ScanResult) (string, error) {
+	suite := junitTestSuite{Name: "scan"}
+	for _, resource := range result.Resources {
+		testCase := junitTestCase{
+			Name:      resource.CloudID,
+			ClassName: "scan." + resource.Kind,
+		}
+		if !resource.Managed {
+			testCase.Failure = &junitFailure{
+				Message: "resource is not managed by Runestone",
+				Type:    "unmanaged",
+			}
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	return f.marshal("scan", result.Duration, result.Error, []junitTestSuite{suite})
+}
+
+// FormatPreviewResult formats a preview result as JUnit XML
+func (f *JUnitFormatter) FormatPreviewResult(result PreviewResult) (string, error) {
+	resources := make([]string, 0, len(result.DriftResults))
+	driftSuite := junitTestSuite{Name: "drift"}
+	for _, drift := range result.DriftResults {
+		resources = append(resources, drift.ResourceName)
+
+		testCase := junitTestCase{
+			Name:      drift.ResourceName,
+			ClassName: "drift",
+		}
+		if drift.HasDrift {
+			testCase.Failure = &junitFailure{
+				Message: "drift detected",
+				Type:    "drift",
+				Text:    strings.Join(drift.Changes, "\n"),
+			}
+		}
+		driftSuite.TestCases = append(driftSuite.TestCases, testCase)
+	}
+
+	suites := []junitTestSuite{f.policySuite(result.PolicyViolations, resources), driftSuite}
+	return f.marshal("preview", result.Duration, result.Error, suites)
+}
+
+// FormatCommitResult formats a commit result as JUnit XML
+func (f *JUnitFormatter) FormatCommitResult(result CommitResult) (string, error) {
+	suites := make([]junitTestSuite, 0, len(result.ExecutionLevels))
+	for _, level := range result.ExecutionLevels {
+		suite := junitTestSuite{Name: fmt.Sprintf("level-%d", level.Level)}
+		for _, resource := range level.Resources {
+			suite.TestCases = append(suite.TestCases, junitTestCase{
+				Name:      resource,
+				ClassName: "commit",
+				Time:      f.seconds(level.ResourceDurations[resource]),
+			})
+		}
+		suites = append(suites, suite)
+	}
+
+	return f.marshal("commit", result.TotalDuration, result.Error, suites)
+}
+
+// FormatAlignResult formats an align result as JUnit XML
+func (f *JUnitFormatter) FormatAlignResult(result AlignResult) (string, error) {
+	suite := junitTestSuite{Name: "align"}
+	for _, resource := range result.Resources {
+		testCase := junitTestCase{
+			Name:      resource.Name,
+			ClassName: "align",
+			Time:      f.seconds(resource.Duration),
+		}
+		switch resource.Status {
+		case "drifted", "skipped", "error":
+			testCase.Failure = &junitFailure{
+				Message: resource.Status,
+				Type:    resource.Status,
+				Text:    strings.Join(resource.Changes, "\n"),
+			}
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	return f.marshal("align", result.Duration, result.Error, []junitTestSuite{suite})
+}
+
+// FormatExportResult formats an export result as JUnit XML. Resources whose state couldn't
+// be read are failures and resources that don't exist are skipped.
+func (f *JUnitFormatter) FormatExportResult(result ExportResult) (string, error) {
+	suite := junitTestSuite{Name: "export"}
+	for _, resource := range result.Resources {
+		testCase := junitTestCase{
+			Name:      resource.ID,
+			ClassName: "export",
+		}
+		switch {
+		case resource.Error != "":
+			testCase.Failure = &junitFailure{
+				Message: resource.Error,
+				Type:    "error",
+			}
+		case !resource.Exists:
+			testCase.Skipped = &junitSkipped{Message: "resource does not exist"}
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	return f.marshal("export", result.Duration, result.Error, []junitTestSuite{suite})
+}
+
+// policySuite turns violations into test cases. Resources listed without violations are
+// added as passing test cases.
+func (f *JUnitFormatter) policySuite(violations []policy.PolicyViolation, resources []string) junitTestSuite {
+	suite := junitTestSuite{Name: "policy"}
+
+	violated := make(map[string]bool)
+	for _, violation := range violations {
+		violated[violation.ResourceID] = true
+
+		ruleName := ""
+		if violation.Rule != nil {
+			ruleName = violation.Rule.Name
+		}
+		testCase := junitTestCase{
+			Name:      fmt.Sprintf("%s: %s", violation.ResourceID, ruleName),
+			ClassName: "policy." + ruleName,
+		}
+
+		switch {
+		case violation.Waived:
+			testCase.Skipped = &junitSkipped{Message: violation.Message + waiverNote(violation)}
+		case violation.Severity == "error":
+			testCase.Failure = &junitFailure{
+				Message: violation.Message,
+				Type:    violation.Severity,
+			}
+		default:
+			testCase.Skipped = &junitSkipped{Message: fmt.Sprintf("%s: %s", violation.Severity, violation.Message)}
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	for _, resource := range resources {
+		if violated[resource] {
+			continue
+		}
+		suite.TestCases = append(suite.TestCases, junitTestCase{
+			Name:      resource,
+			ClassName: "policy",
+		})
+	}
+
+	return suite
+}
+
+// marshal adds an error test case for operational failures, computes totals and encodes the document
+func (f *JUnitFormatter) marshal(command string, duration time.Duration, operationErr error, suites []junitTestSuite) (string, error) {
+	if operationErr != nil {
+		suites = append(suites, junitTestSuite{
+			Name: command,
+			TestCases: []junitTestCase{{
+				Name:      command,
+				ClassName: "runestone",
+				Failure: &junitFailure{
+					Message: operationErr.Error(),
+					Type:    "error",
+				},
+			}},
+		})
+	}
+
+	document := junitTestSuites{
+		Name: "runestone " + command,
+		Time: f.seconds(duration),
+	}
+	for _, suite := range suites {
+		for _, testCase := range suite.TestCases {
+			suite.Tests++
+			if testCase.Failure != nil {
+				suite.Failures++
+			}
+			if testCase.Skipped != nil {
+				suite.Skipped++
+			}
+		}
+		document.Tests += suite.Tests
+		document.Failures += suite.Failures
+		document.Skipped += suite.Skipped
+		document.Suites = append(document.Suites, suite)
+	}
+
+	data, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return xml.Header + string(data) + "\n", nil
+}
+
+func (f *JUnitFormatter) seconds(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,163 @@
+package output
+
+import (
+	"encoding/xml"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ataiva-software/runestone/internal/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseJUnit(t *testing.T, output string) junitTestSuites {
+	var document junitTestSuites
+	require.NoError(t, xml.Unmarshal([]byte(output), &document))
+	return document
+}
+
+func findSuite(t *testing.T, document junitTestSuites, name string) junitTestSuite {
+	for _, suite := range document.Suites {
+		if suite.Name == name {
+			return suite
+		}
+	}
+	t.Fatalf("test suite %s not found", name)
+	return junitTestSuite{}
+}
+
+func TestJUnitFormatter_FormatPreviewResult(t *testing.T) {
+	formatter := NewJUnitFormatter()
+
+	output, err := formatter.FormatPreviewResult(PreviewResult{
+		Success: true,
+		DriftResults: []DriftResult{
+			{ResourceName: "aws:s3:bucket.logs", HasDrift: true, Changes: []string{"Property versioning: false → true"}},
+			{ResourceName: "aws:s3:bucket.data"},
+			{ResourceName: "aws:ec2:instance.web"},
+		},
+		PolicyViolations: []policy.PolicyViolation{
+			{
+				ResourceID: "aws:s3:bucket.logs",
+				Rule:       &policy.PolicyRule{Name: "s3-versioning"},
+				Message:    "S3 bucket should have versioning enabled",
+				Severity:   "error",
+			},
+			{
+				ResourceID: "aws:ec2:instance.web",
+				Rule:       &policy.PolicyRule{Name: "environment-tag"},
+				Message:    "Resource must have an Environment tag",
+				Severity:   "warning",
+			},
+			{
+				ResourceID:   "aws:ec2:instance.web",
+				Rule:         &policy.PolicyRule{Name: "large-instances"},
+				Message:      "Large instances are not allowed",
+				Severity:     "error",
+				Waived:       true,
+				WaiverReason: "batch workload",
+			},
+		},
+		Duration: time.Millisecond * 1500,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, output, `<?xml version="1.0" encoding="UTF-8"?>`)
+
+	document := parseJUnit(t, output)
+	assert.Equal(t, "runestone preview", document.Name)
+	assert.Equal(t, "1.500", document.Time)
+	assert.Equal(t, 7, document.Tests)
+	assert.Equal(t, 2, document.Failures)
+	assert.Equal(t, 2, document.Skipped)
+
+	policySuite := findSuite(t, document, "policy")
+	assert.Equal(t, 4, policySuite.Tests)
+	assert.Equal(t, 1, policySuite.Failures)
+	assert.Equal(t, 2, policySuite.Skipped)
+
+	errorCase := policySuite.TestCases[0]
+	assert.Equal(t, "aws:s3:bucket.logs: s3-versioning", errorCase.Name)
+	require.NotNil(t, errorCase.Failure)
+	assert.Equal(t, "S3 bucket should have versioning enabled", errorCase.Failure.Message)
+
+	warningCase := policySuite.TestCases[1]
+	assert.Nil(t, warningCase.Failure)
+	require.NotNil(t, warningCase.Skipped)
+	assert.Equal(t, "warning: Resource must have an Environment tag", warningCase.Skipped.Message)
+
+	waivedCase := policySuite.TestCases[2]
+	assert.Nil(t, waivedCase.Failure)
+	require.NotNil(t, waivedCase.Skipped)
+	assert.Contains(t, waivedCase.Skipped.Message, "waived: batch workload")
+
+	cleanCase := policySuite.TestCases[3]
+	assert.Equal(t, "aws:s3:bucket.data", cleanCase.Name)
+	assert.Nil(t, cleanCase.Failure)
+	assert.Nil(t, cleanCase.Skipped)
+
+	driftSuite := findSuite(t, document, "drift")
+	assert.Equal(t, 3, driftSuite.Tests)
+	assert.Equal(t, 1, driftSuite.Failures)
+	require.NotNil(t, driftSuite.TestCases[0].Failure)
+	assert.Equal(t, "Property versioning: false → true", driftSuite.TestCases[0].Failure.Text)
+	assert.Nil(t, driftSuite.TestCases[1].Failure)
+}
+
+func TestJUnitFormatter_OperationError(t *testing.T) {
+	formatter := NewJUnitFormatter()
+
+	output, err := formatter.FormatBootstrapResult(BootstrapResult{
+		Error: errors.New("failed to parse configuration"),
+	})
+	require.NoError(t, err)
+
+	document := parseJUnit(t, output)
+	assert.Equal(t, 1, document.Tests)
+	assert.Equal(t, 1, document.Failures)
+
+	suite := findSuite(t, document, "bootstrap")
+	require.Len(t, suite.TestCases, 1)
+	assert.Equal(t, "failed to parse configuration", suite.TestCases[0].Failure.Message)
+}
+
+func TestJUnitFormatter_FormatAlignResult(t *testing.T) {
+	formatter := NewJUnitFormatter()
+
+	output, err := formatter.FormatAlignResult(AlignResult{
+		Resources: []ResourceStatus{
+			{Name: "aws:s3:bucket.logs", Status: "healed"},
+			{Name: "aws:s3:bucket.data", Status: "drifted", Changes: []string{"versioning"}},
+			{Name: "aws:s3:bucket.site", Status: "aligned"},
+		},
+	})
+	require.NoError(t, err)
+
+	document := parseJUnit(t, output)
+	assert.Equal(t, 3, document.Tests)
+	assert.Equal(t, 1, document.Failures)
+}
+
+func TestJUnitFormatter_FormatExportResult(t *testing.T) {
+	formatter := NewJUnitFormatter()
+
+	output, err := formatter.FormatExportResult(ExportResult{
+		Resources: []ExportedResource{
+			{ID: "aws:s3:bucket.logs", Exists: true, State: map[string]interface{}{}},
+			{ID: "aws:s3:bucket.assets"},
+			{ID: "aws:s3:bucket.data", Error: "access denied"},
+		},
+		Error: errors.New("failed to read the state of 1 resources"),
+	})
+	require.NoError(t, err)
+
+	document := parseJUnit(t, output)
+	suite := findSuite(t, document, "export")
+	assert.Equal(t, 3, suite.Tests)
+	assert.Equal(t, 1, suite.Failures)
+	assert.Equal(t, 1, suite.Skipped)
+}
+
+func TestNewFormatter_JUnit(t *testing.T) {
+	assert.IsType(t, &JUnitFormatter{}, NewFormatter(FormatJUnit))
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 // MarkdownFormatter implements the Formatter interface for Markdown output
@@ -46,8 +48,55 @@ func (f *MarkdownFormatter) FormatBootstrapResult(result BootstrapResult) (strin
 		sb.WriteString("## Policy Violations\n\n")
 		for _, violation := range result.PolicyViolations {
 			icon := f.getSeverityIcon(violation.Severity)
-			sb.WriteString(fmt.Sprintf("- %s **%s** (%s): %s\n", 
-				icon, violation.ResourceID, violation.Rule.Name, violation.Message))
+			sb.WriteString(fmt.Sprintf("- %s **%s** (%s): %s%s\n",
+				icon, violation.ResourceID, violation.Rule.Name, violation.Message, waiverNote(violation)))
+		}
+		sb.WriteString("\n")
+	}
+
+	// Error
+	if result.Error != nil {
+		sb.WriteString("## Error\n\n")
+		sb.WriteString(fmt.Sprintf("```\n%s\n```\n\n", result.Error.Error()))
+	}
+
+	return sb.String(), nil
+}
+
+// FormatValidateResult formats a validate result as Markdown
+func (f *MarkdownFormatter) FormatValidateResult(result ValidateResult) (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString("# Configuration Validation\n\n")
+
+	// Summary
+	sb.WriteString("## Summary\n\n")
+	if result.Success {
+		sb.WriteString("**Status:** ✅ Valid\n")
+	} else {
+		sb.WriteString("**Status:** ❌ Invalid\n")
+	}
+	sb.WriteString(fmt.Sprintf("**Duration:** %s\n", f.formatDuration(result.Duration)))
+	sb.WriteString(fmt.Sprintf("**Resources:** %d\n", result.ResourceCount))
+	sb.WriteString(fmt.Sprintf("**Modules loaded:** %d\n", result.ModulesLoaded))
+	sb.WriteString("\n")
+
+	// Validation errors
+	if len(result.ValidationErrors) > 0 {
+		sb.WriteString("## Validation Errors\n\n")
+		for _, validationError := range result.ValidationErrors {
+			sb.WriteString(fmt.Sprintf("- ❌ **%s**: %s\n", validationError.ResourceID, validationError.Message))
+		}
+		sb.WriteString("\n")
+	}
+
+	// Policy violations
+	if len(result.PolicyViolations) > 0 {
+		sb.WriteString("## Policy Violations\n\n")
+		for _, violation := range result.PolicyViolations {
+			icon := f.getSeverityIcon(violation.Severity)
+			sb.WriteString(fmt.Sprintf("- %s **%s** (%s): %s%s\n",
+				icon, violation.ResourceID, violation.Rule.Name, violation.Message, waiverNote(violation)))
 		}
 		sb.WriteString("\n")
 	}
@@ -75,7 +124,8 @@ func (f *MarkdownFormatter) FormatPreviewResult(result PreviewResult) (string, e
 		sb.WriteString("**Status:** ❌ Failed\n")
 	}
 	sb.WriteString(fmt.Sprintf("**Duration:** %s\n", f.formatDuration(result.Duration)))
-	sb.WriteString(fmt.Sprintf("**Changes detected:** %d\n", result.ChangesCount))
+	sb.WriteString(fmt.Sprintf("**Changes detected:** %d (%d to create, %d to update, %d to delete)\n",
+		result.ChangesCount, result.CreateCount, result.UpdateCount, result.DeleteCount))
 	sb.WriteString(fmt.Sprintf("**Drift detected:** %t\n", f.hasDrift(result.DriftResults)))
 	sb.WriteString("\n")
 
@@ -110,6 +160,17 @@ func (f *MarkdownFormatter) FormatPreviewResult(result PreviewResult) (string, e
 		sb.WriteString("\n")
 	}
 
+	// Policy violations
+	if len(result.PolicyViolations) > 0 {
+		sb.WriteString("## Policy Violations\n\n")
+		for _, violation := range result.PolicyViolations {
+			icon := f.getSeverityIcon(violation.Severity)
+			sb.WriteString(fmt.Sprintf("- %s **%s** (%s): %s%s\n",
+				icon, violation.ResourceID, violation.Rule.Name, violation.Message, waiverNote(violation)))
+		}
+		sb.WriteString("\n")
+	}
+
 	// Error
 	if result.Error != nil {
 		sb.WriteString("## Error\n\n")
@@ -143,12 +204,102 @@ func (f *MarkdownFormatter) FormatCommitResult(result CommitResult) (string, err
 			sb.WriteString(fmt.Sprintf("### Level %d (%s)\n\n", 
 				level.Level, f.formatDuration(level.Duration)))
 			for _, resource := range level.Resources {
-				sb.WriteString(fmt.Sprintf("- %s\n", resource))
+				if duration, ok := level.ResourceDurations[resource]; ok {
+					sb.WriteString(fmt.Sprintf("- %s (%s)\n", resource, f.formatDuration(duration)))
+				} else {
+					sb.WriteString(fmt.Sprintf("- %s\n", resource))
+				}
 			}
 			sb.WriteString("\n")
 		}
 	}
 
+	// Outputs
+	if len(result.Outputs) > 0 {
+		sb.WriteString("## Outputs\n\n")
+		sb.WriteString("| Name | Value |\n")
+		sb.WriteString("|------|-------|\n")
+		for _, name := range outputNames(result.Outputs) {
+			sb.WriteString(fmt.Sprintf("| %s | `%v` |\n", name, result.Outputs[name]))
+		}
+		sb.WriteString("\n")
+	}
+
+	// Error
+	if result.Error != nil {
+		sb.WriteString("## Error\n\n")
+		sb.WriteString(fmt.Sprintf("```\n%s\n```\n\n", result.Error.Error()))
+	}
+
+	return sb.String(), nil
+}
+
+// FormatLintResult formats a lint result as Markdown
+func (f *MarkdownFormatter) FormatLintResult(result LintResult) (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString("# Configuration Lint\n\n")
+
+	// Summary
+	sb.WriteString("## Summary\n\n")
+	sb.WriteString(fmt.Sprintf("**Resources:** %d\n", result.ResourceCount))
+	sb.WriteString(fmt.Sprintf("**Issues:** %d\n", len(result.Findings)))
+	sb.WriteString("\n")
+
+	// Findings
+	if len(result.Findings) > 0 {
+		sb.WriteString("## Issues\n\n")
+		for _, finding := range result.Findings {
+			icon := f.getSeverityIcon(finding.Severity)
+			sb.WriteString(fmt.Sprintf("- %s **%s** (%s): %s%s\n",
+				icon, finding.ResourceID, finding.Rule.Name, finding.Message, waiverNote(finding)))
+			if finding.Remediation != "" {
+				sb.WriteString(fmt.Sprintf("  - Fix: %s\n", finding.Remediation))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	// Error
+	if result.Error != nil {
+		sb.WriteString("## Error\n\n")
+		sb.WriteString(fmt.Sprintf("```\n%s\n```\n\n", result.Error.Error()))
+	}
+
+	return sb.String(), nil
+}
+
+// FormatScanResult formats a scan result as Markdown
+func (f *MarkdownFormatter) FormatScanResult(result ScanResult) (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString("# Unmanaged Resources\n\n")
+
+	// Summary
+	sb.WriteString("## Summary\n\n")
+	sb.WriteString(fmt.Sprintf("**Resources:** %d\n", len(result.Resources)))
+	sb.WriteString(fmt.Sprintf("**Unmanaged:** %d\n", result.unmanagedCount()))
+	sb.WriteString("\n")
+
+	// Resources
+	if len(result.Resources) > 0 {
+		sb.WriteString("## Resources\n\n")
+		sb.WriteString("| Kind | ID | Name | Created | Managed |\n")
+		sb.WriteString("|------|----|------|---------|---------|\n")
+		for _, resource := range result.Resources {
+			created := ""
+			if !resource.CreatedAt.IsZero() {
+				created = resource.CreatedAt.UTC().Format("2006-01-02")
+			}
+			managed := "❌"
+			if resource.Managed {
+				managed = "✅"
+			}
+			sb.WriteString(fmt.Sprintf("| %s | `%s` | %s | %s | %s |\n", resource.Kind, resource.CloudID, resource.Name, created, managed))
+		}
+		sb.WriteString("\n")
+	}
+
 	// Error
 	if result.Error != nil {
 		sb.WriteString("## Error\n\n")
@@ -162,7 +313,11 @@ func (f *MarkdownFormatter) FormatCommitResult(result CommitResult) (string, err
 func (f *MarkdownFormatter) FormatAlignResult(result AlignResult) (string, error) {
 	var sb strings.Builder
 
-	sb.WriteString("# Infrastructure Alignment\n\n")
+	if result.ReportOnly {
+		sb.WriteString("# Drift Report\n\n")
+	} else {
+		sb.WriteString("# Infrastructure Alignment\n\n")
+	}
 
 	// Summary
 	sb.WriteString("## Summary\n\n")
@@ -173,7 +328,14 @@ func (f *MarkdownFormatter) FormatAlignResult(result AlignResult) (string, error
 	}
 	sb.WriteString(fmt.Sprintf("**Duration:** %s\n", f.formatDuration(result.Duration)))
 	sb.WriteString(fmt.Sprintf("**Drift detected:** %t\n", result.DriftDetected))
-	sb.WriteString(fmt.Sprintf("**Actions applied:** %d\n", result.ActionsApplied))
+	if result.ReportOnly {
+		sb.WriteString("**Mode:** report only, nothing was changed\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("**Actions applied:** %d\n", result.ActionsApplied))
+		if result.HealsSkipped > 0 {
+			sb.WriteString(fmt.Sprintf("**Heals skipped:** %d\n", result.HealsSkipped))
+		}
+	}
 	sb.WriteString("\n")
 
 	// Resource status
@@ -199,6 +361,52 @@ func (f *MarkdownFormatter) FormatAlignResult(result AlignResult) (string, error
 	return sb.String(), nil
 }
 
+// FormatExportResult formats an export result as Markdown
+func (f *MarkdownFormatter) FormatExportResult(result ExportResult) (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString("# Live Resource State\n\n")
+
+	// Summary
+	sb.WriteString("## Summary\n\n")
+	if result.Success {
+		sb.WriteString("**Status:** ✅ Success\n")
+	} else {
+		sb.WriteString("**Status:** ❌ Failed\n")
+	}
+	sb.WriteString(fmt.Sprintf("**Duration:** %s\n", f.formatDuration(result.Duration)))
+	sb.WriteString(fmt.Sprintf("**Resources:** %d\n", len(result.Resources)))
+	sb.WriteString("\n")
+
+	// Resources
+	if len(result.Resources) > 0 {
+		sb.WriteString("## Resources\n\n")
+		for _, resource := range result.Resources {
+			sb.WriteString(fmt.Sprintf("### %s\n\n", resource.ID))
+			switch {
+			case resource.Error != "":
+				sb.WriteString(fmt.Sprintf("❌ %s\n\n", resource.Error))
+			case !resource.Exists:
+				sb.WriteString("_Does not exist_\n\n")
+			default:
+				state, err := yaml.Marshal(resource.State)
+				if err != nil {
+					return "", err
+				}
+				sb.WriteString(fmt.Sprintf("```yaml\n%s```\n\n", state))
+			}
+		}
+	}
+
+	// Error
+	if result.Error != nil {
+		sb.WriteString("## Error\n\n")
+		sb.WriteString(fmt.Sprintf("```\n%s\n```\n\n", result.Error.Error()))
+	}
+
+	return sb.String(), nil
+}
+
 // Helper methods
 
 func (f *MarkdownFormatter) formatDuration(d time.Duration) string {
@@ -245,6 +453,8 @@ func (f *MarkdownFormatter) getStatusIcon(status string) string {
 		return "🔄"
 	case "healed":
 		return "🔧"
+	case "skipped":
+		return "⏭️"
 	case "error":
 		return "❌"
 	default:
@@ -1,6 +1,10 @@
 package output
 
 import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/ataiva-software/runestone/internal/policy"
@@ -12,6 +16,10 @@ type Formatter interface {
 	FormatPreviewResult(result PreviewResult) (string, error)
 	FormatCommitResult(result CommitResult) (string, error)
 	FormatAlignResult(result AlignResult) (string, error)
+	FormatValidateResult(result ValidateResult) (string, error)
+	FormatExportResult(result ExportResult) (string, error)
+	FormatLintResult(result LintResult) (string, error)
+	FormatScanResult(result ScanResult) (string, error)
 }
 
 // BootstrapResult represents the result of a bootstrap operation
@@ -25,14 +33,44 @@ type BootstrapResult struct {
 	Error              error
 }
 
+// ValidateResult represents the result of a validate operation
+type ValidateResult struct {
+	Success          bool
+	ResourceCount    int
+	ModulesLoaded    int
+	ValidationErrors []ValidationError
+	PolicyViolations []policy.PolicyViolation
+	Duration         time.Duration
+	Error            error
+}
+
+// LintResult represents the result of a lint operation
+type LintResult struct {
+	Success       bool
+	ResourceCount int
+	Findings      []policy.PolicyViolation
+	Duration      time.Duration
+	Error         error
+}
+
+// ValidationError represents a resource that failed provider validation
+type ValidationError struct {
+	ResourceID string
+	Message    string
+}
+
 // PreviewResult represents the result of a preview operation
 type PreviewResult struct {
-	Success      bool
-	ChangesCount int
-	Changes      []Change
-	DriftResults []DriftResult
-	Duration     time.Duration
-	Error        error
+	Success          bool
+	ChangesCount     int
+	CreateCount      int
+	UpdateCount      int
+	DeleteCount      int
+	Changes          []Change
+	DriftResults     []DriftResult
+	PolicyViolations []policy.PolicyViolation
+	Duration         time.Duration
+	Error            error
 }
 
 // CommitResult represents the result of a commit operation
@@ -40,18 +78,67 @@ type CommitResult struct {
 	Success          bool
 	ResourcesApplied int
 	ExecutionLevels  []ExecutionLevel
+	Outputs          map[string]interface{} // Root output values, with sensitive ones already redacted
 	TotalDuration    time.Duration
 	Error            error
 }
 
 // AlignResult represents the result of an align operation
 type AlignResult struct {
-	Success       bool
-	DriftDetected bool
+	Success        bool
+	DriftDetected  bool
+	ReportOnly     bool // Drift was reported for every resource and nothing was healed
 	ActionsApplied int
-	Resources     []ResourceStatus
-	Duration      time.Duration
-	Error         error
+	HealsSkipped   int // Drifted resources whose drift policy refused auto-heal
+	Resources      []ResourceStatus
+	Duration       time.Duration
+	Error          error
+}
+
+// ExportResult represents the result of an export operation
+type ExportResult struct {
+	Success   bool
+	Resources []ExportedResource
+	Duration  time.Duration
+	Error     error
+}
+
+// ExportedResource is the live state of a resource, or why it couldn't be read
+type ExportedResource struct {
+	ID     string
+	Kind   string
+	Exists bool
+	State  map[string]interface{}
+	Error  string
+}
+
+// ScanResult represents the result of a scan operation
+type ScanResult struct {
+	Success   bool
+	Resources []ScannedResource
+	Duration  time.Duration
+	Error     error
+}
+
+// ScannedResource is a resource found in an account, and whether Runestone manages it
+type ScannedResource struct {
+	Provider  string
+	Kind      string
+	CloudID   string
+	Name      string
+	CreatedAt time.Time // Zero when the kind's APIs don't report it
+	Managed   bool
+}
+
+// unmanagedCount returns how many scanned resources aren't managed
+func (r ScanResult) unmanagedCount() int {
+	count := 0
+	for _, resource := range r.Resources {
+		if !resource.Managed {
+			count++
+		}
+	}
+	return count
 }
 
 // Change represents a planned infrastructure change
@@ -62,26 +149,90 @@ type Change struct {
 	Description  string
 }
 
+// CountChanges returns how many of the changes create, update and delete resources
+func CountChanges(changes []Change) (creates, updates, deletes int) {
+	for _, change := range changes {
+		switch change.Type {
+		case "create":
+			creates++
+		case "update":
+			updates++
+		case "delete":
+			deletes++
+		}
+	}
+	return creates, updates, deletes
+}
+
 // DriftResult represents drift detection results for a resource
 type DriftResult struct {
 	ResourceName string
 	HasDrift     bool
-	Changes      []string
+	Changes      []string // Human-readable summaries of Differences
+	Differences  []DriftDifference
+}
+
+// DriftDifference is a single property that differs between live and desired state
+type DriftDifference struct {
+	Property     string
+	Path         string // JSON Pointer to the value, e.g. /tags/Environment
+	CurrentValue interface{}
+	DesiredValue interface{}
+	DriftType    string // added, removed, modified
 }
 
 // ExecutionLevel represents a level in the DAG execution
 type ExecutionLevel struct {
-	Level     int
-	Resources []string
-	Duration  time.Duration
+	Level             int
+	Resources         []string
+	Duration          time.Duration            // From the first resource starting to the last finishing
+	ResourceDurations map[string]time.Duration // How long each resource took to apply
 }
 
 // ResourceStatus represents the status of a resource during alignment
 type ResourceStatus struct {
-	Name     string
-	Status   string // aligned, drifted, healed, error
-	Changes  []string
-	Duration time.Duration
+	Name        string
+	Status      string // aligned, drifted, healed, skipped, error
+	Changes     []string
+	Differences []DriftDifference
+	Duration    time.Duration
+}
+
+// waiverNote describes the exemption covering a violation, if any
+func waiverNote(violation policy.PolicyViolation) string {
+	if !violation.Waived {
+		return ""
+	}
+	if violation.WaiverReason == "" {
+		return " (waived)"
+	}
+	return fmt.Sprintf(" (waived: %s)", violation.WaiverReason)
+}
+
+// scanDetails describes a scanned resource's name and creation date, when known, for
+// text formats
+func scanDetails(resource ScannedResource) string {
+	var details []string
+	if resource.Name != "" && resource.Name != resource.CloudID {
+		details = append(details, "name: "+resource.Name)
+	}
+	if !resource.CreatedAt.IsZero() {
+		details = append(details, "created "+resource.CreatedAt.UTC().Format("2006-01-02"))
+	}
+	if len(details) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(details, ", ") + ")"
+}
+
+// outputNames returns the names of commit outputs in sorted order
+func outputNames(outputs map[string]interface{}) []string {
+	names := make([]string, 0, len(outputs))
+	for name := range outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 // OutputFormat represents the supported output formats
@@ -91,16 +242,58 @@ const (
 	FormatHuman    OutputFormat = "human"
 	FormatJSON     OutputFormat = "json"
 	FormatMarkdown OutputFormat = "markdown"
+	FormatYAML     OutputFormat = "yaml"
+	FormatJUnit    OutputFormat = "junit"
 )
 
+// FormatterOption configures a formatter created by NewFormatter
+type FormatterOption func(*formatterOptions)
+
+// formatterOptions holds the settings FormatterOptions apply
+type formatterOptions struct {
+	color bool
+}
+
+// WithColor sets whether human output is decorated with ANSI colors and symbols such as
+// emoji. It is on by default. The other formats are never decorated, so they ignore it.
+func WithColor(enabled bool) FormatterOption {
+	return func(options *formatterOptions) {
+		options.color = enabled
+	}
+}
+
+// UseColor reports whether human output written to file should be decorated: file must be a
+// terminal, noColor (from --no-color) unset, and the NO_COLOR environment variable empty or
+// unset. Output piped to another program, redirected to a file or captured by a CI log is
+// left plain, as is output to terminals that set TERM=dumb.
+func UseColor(file *os.File, noColor bool) bool {
+	if noColor || os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
 // NewFormatter creates a new formatter based on the specified format
-func NewFormatter(format OutputFormat) Formatter {
+func NewFormatter(format OutputFormat, options ...FormatterOption) Formatter {
+	settings := formatterOptions{color: true}
+	for _, option := range options {
+		option(&settings)
+	}
+
 	switch format {
 	case FormatJSON:
 		return NewJSONFormatter()
 	case FormatMarkdown:
 		return NewMarkdownFormatter()
+	case FormatYAML:
+		return NewYAMLFormatter()
+	case FormatJUnit:
+		return NewJUnitFormatter()
 	default:
-		return NewHumanFormatter()
+		return &HumanFormatter{color: settings.color}
 	}
 }
@@ -0,0 +1,67 @@
+package output
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// YAMLFormatter implements the Formatter interface for YAML output. Documents use the
+// same fields as the JSON formatter.
+type YAMLFormatter struct {
+	json *JSONFormatter
+}
+
+// NewYAMLFormatter creates a new YAML formatter
+func NewYAMLFormatter() *YAMLFormatter {
+	return &YAMLFormatter{
+		json: NewJSONFormatter(),
+	}
+}
+
+// FormatBootstrapResult formats a bootstrap result as YAML
+func (f *YAMLFormatter) FormatBootstrapResult(result BootstrapResult) (string, error) {
+	return f.marshal(f.json.bootstrapOutput(result))
+}
+
+// FormatValidateResult formats a validate result as YAML
+func (f *YAMLFormatter) FormatValidateResult(result ValidateResult) (string, error) {
+	return f.marshal(f.json.validateOutput(result))
+}
+
+// FormatLintResult formats a lint result as YAML
+func (f *YAMLFormatter) FormatLintResult(result LintResult) (string, error) {
+	return f.marshal(f.json.lintOutput(result))
+}
+
+// FormatScanResult formats a scan result as YAML
+func (f *YAMLFormatter) FormatScanResult(result ScanResult) (string, error) {
+	return f.marshal(f.json.scanOutput(result))
+}
+
+// FormatPreviewResult formats a preview result as YAML
+func (f *YAMLFormatter) FormatPreviewResult(result PreviewResult) (string, error) {
+	return f.marshal(f.json.previewOutput(result))
+}
+
+// FormatCommitResult formats a commit result as YAML
+func (f *YAMLFormatter) FormatCommitResult(result CommitResult) (string, error) {
+	return f.marshal(f.json.commitOutput(result))
+}
+
+// FormatAlignResult formats an align result as YAML
+func (f *YAMLFormatter) FormatAlignResult(result AlignResult) (string, error) {
+	return f.marshal(f.json.alignOutput(result))
+}
+
+// FormatExportResult formats an export result as YAML
+func (f *YAMLFormatter) FormatExportResult(result ExportResult) (string, error) {
+	return f.marshal(f.json.exportOutput(result))
+}
+
+func (f *YAMLFormatter) marshal(output map[string]interface{}) (string, error) {
+	data, err := yaml.Marshal(output)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,178 @@
+package output
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ataiva-software/runestone/internal/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestYAMLFormatter_FormatBootstrapResult(t *testing.T) {
+	formatter := NewYAMLFormatter()
+
+	output, err := formatter.FormatBootstrapResult(BootstrapResult{
+		Success:            true,
+		ProvidersInstalled: []string{"aws"},
+		ResourceCount:      3,
+		ModulesLoaded:      1,
+		PolicyViolations: []policy.PolicyViolation{
+			{
+				ResourceID: "test-bucket",
+				Rule:       &policy.PolicyRule{Name: "s3-versioning"},
+				Message:    "S3 bucket should have versioning enabled",
+				Severity:   "warning",
+			},
+		},
+		Duration: time.Millisecond * 1500,
+	})
+	require.NoError(t, err)
+
+	var result map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(output), &result))
+
+	assert.Equal(t, true, result["success"])
+	assert.Equal(t, []interface{}{"aws"}, result["providers_installed"])
+	assert.Equal(t, 3, result["resource_count"])
+	assert.Equal(t, 1, result["modules_loaded"])
+	assert.Equal(t, 1.5, result["duration_seconds"])
+	assert.Equal(t, false, result["has_errors"])
+
+	violations := result["policy_violations"].([]interface{})
+	require.Len(t, violations, 1)
+	violation := violations[0].(map[string]interface{})
+	assert.Equal(t, "test-bucket", violation["resource_name"])
+	assert.Equal(t, "s3-versioning", violation["rule_name"])
+	assert.Equal(t, "warning", violation["severity"])
+}
+
+func TestYAMLFormatter_FormatPreviewResult(t *testing.T) {
+	formatter := NewYAMLFormatter()
+
+	output, err := formatter.FormatPreviewResult(PreviewResult{
+		Success:      true,
+		ChangesCount: 1,
+		Changes: []Change{
+			{
+				Type:         "create",
+				ResourceKind: "aws:s3:bucket",
+				ResourceName: "new-bucket",
+				Description:  "Create S3 bucket new-bucket",
+			},
+		},
+		DriftResults: []DriftResult{
+			{
+				ResourceName: "existing-bucket",
+				HasDrift:     true,
+				Changes:      []string{"versioning changed from false to true"},
+			},
+		},
+		Duration: time.Millisecond * 2500,
+	})
+	require.NoError(t, err)
+
+	var result map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(output), &result))
+
+	assert.Equal(t, true, result["success"])
+	assert.Equal(t, 1, result["changes_count"])
+	assert.Equal(t, 2.5, result["duration_seconds"])
+	assert.Equal(t, true, result["has_drift"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"type":          "create",
+			"resource_kind": "aws:s3:bucket",
+			"resource_name": "new-bucket",
+			"description":   "Create S3 bucket new-bucket",
+		},
+	}, result["changes"])
+}
+
+func TestYAMLFormatter_FormatCommitResult(t *testing.T) {
+	formatter := NewYAMLFormatter()
+
+	output, err := formatter.FormatCommitResult(CommitResult{
+		Success:          true,
+		ResourcesApplied: 3,
+		ExecutionLevels: []ExecutionLevel{
+			{
+				Level:     1,
+				Resources: []string{"aws:s3:bucket.logs"},
+				Duration:  time.Millisecond * 500,
+			},
+		},
+		TotalDuration: time.Millisecond * 1500,
+	})
+	require.NoError(t, err)
+
+	var result map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(output), &result))
+
+	assert.Equal(t, true, result["success"])
+	assert.Equal(t, 3, result["resources_applied"])
+	assert.Equal(t, 1.5, result["total_duration_seconds"])
+
+	levels := result["execution_levels"].([]interface{})
+	require.Len(t, levels, 1)
+	level := levels[0].(map[string]interface{})
+	assert.Equal(t, 1, level["level"])
+	assert.Equal(t, []interface{}{"aws:s3:bucket.logs"}, level["resources"])
+}
+
+func TestYAMLFormatter_FormatAlignResult(t *testing.T) {
+	formatter := NewYAMLFormatter()
+
+	output, err := formatter.FormatAlignResult(AlignResult{
+		Success:        true,
+		DriftDetected:  true,
+		ActionsApplied: 1,
+		Resources: []ResourceStatus{
+			{Name: "aws:s3:bucket.logs", Status: "healed", Changes: []string{"versioning"}},
+		},
+		Duration: time.Millisecond * 500,
+	})
+	require.NoError(t, err)
+
+	var result map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(output), &result))
+
+	assert.Equal(t, true, result["success"])
+	assert.Equal(t, true, result["drift_detected"])
+	assert.Equal(t, 1, result["actions_applied"])
+	assert.Equal(t, 0.5, result["duration_seconds"])
+
+	resources := result["resources"].([]interface{})
+	require.Len(t, resources, 1)
+	assert.Equal(t, "healed", resources[0].(map[string]interface{})["status"])
+}
+
+func TestYAMLFormatter_FormatExportResult(t *testing.T) {
+	formatter := NewYAMLFormatter()
+
+	output, err := formatter.FormatExportResult(ExportResult{
+		Success: true,
+		Resources: []ExportedResource{
+			{
+				ID:     "aws:s3:bucket.logs",
+				Kind:   "aws:s3:bucket",
+				Exists: true,
+				State:  map[string]interface{}{"versioning": true},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	var result map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(output), &result))
+
+	resources := result["resources"].(map[string]interface{})
+	logs := resources["aws:s3:bucket.logs"].(map[string]interface{})
+	assert.Equal(t, "aws:s3:bucket", logs["kind"])
+	assert.Equal(t, map[string]interface{}{"versioning": true}, logs["state"])
+}
+
+func TestNewFormatter_YAML(t *testing.T) {
+	assert.IsType(t, &YAMLFormatter{}, NewFormatter(FormatYAML))
+}
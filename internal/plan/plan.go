@@ -0,0 +1,335 @@
+package plan
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/ataiva-software/runestone/internal/drift"
+	"github.com/ataiva-software/runestone/internal/providers"
+)
+
+// FormatVersion is the version of the serialized plan format
+const FormatVersion = 1
+
+// Plan is a serialized set of changes produced by preview and applied by commit
+type Plan struct {
+	FormatVersion int        `json:"format_version"`
+	CreatedAt     time.Time  `json:"created_at"`
+	ConfigFile    string     `json:"config_file,omitempty"`
+	Summary       Summary    `json:"summary"`
+	Resources     []Resource `json:"resources"`
+}
+
+// Summary counts the planned changes by type
+type Summary struct {
+	Create int `json:"create"`
+	Update int `json:"update"`
+	Delete int `json:"delete"`
+}
+
+// Resource is a planned resource along with the live state it was planned against
+type Resource struct {
+	ID               string                 `json:"id"`
+	Kind             string                 `json:"kind"`
+	Name             string                 `json:"name"`
+	Properties       map[string]interface{} `json:"properties,omitempty"`
+	DependsOn        []string               `json:"depends_on,omitempty"`
+	DriftPolicy      *config.DriftPolicy    `json:"drift_policy,omitempty"`
+	Sensitive        []string               `json:"sensitive,omitempty"`
+	Provider         string                 `json:"provider,omitempty"`
+	Timeouts         *config.Timeouts       `json:"timeouts,omitempty"`
+	Action           string                 `json:"action"`
+	MovedFrom        string                 `json:"moved_from,omitempty"` // Resource ID the update renames the resource from
+	Differences      []Difference           `json:"differences,omitempty"`
+	StateFingerprint string                 `json:"state_fingerprint"`
+	// State is the live state the resource was planned against, or nil when it didn't
+	// exist; commit --refresh=false applies the plan against it instead of re-reading it
+	State map[string]interface{} `json:"state"`
+}
+
+// Difference is a single property change planned for an update
+type Difference struct {
+	Property     string      `json:"property"`
+	Path         string      `json:"path,omitempty"` // JSON Pointer to the value
+	CurrentValue interface{} `json:"current_value,omitempty"`
+	DesiredValue interface{} `json:"desired_value,omitempty"`
+	DriftType    string      `json:"drift_type"`
+}
+
+// Actions recorded for each planned resource
+const (
+	ActionNone   = "none"
+	ActionCreate = string(config.ChangeTypeCreate)
+	ActionUpdate = string(config.ChangeTypeUpdate)
+)
+
+// New builds a plan from expanded instances and their drift results
+func New(configFile string, instances []config.ResourceInstance, driftResults map[string]*providers.DriftResult) (*Plan, error) {
+	p := &Plan{
+		FormatVersion: FormatVersion,
+		CreatedAt:     time.Now().UTC(),
+		ConfigFile:    configFile,
+		Resources:     make([]Resource, 0, len(instances)),
+	}
+
+	for _, instance := range instances {
+		driftResult, exists := driftResults[instance.ID]
+		if !exists {
+			return nil, fmt.Errorf("no drift result for resource %s", instance.ID)
+		}
+
+		fingerprint, err := Fingerprint(driftResult.CurrentState)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fingerprint state of %s: %w", instance.ID, err)
+		}
+
+		resource := Resource{
+			ID:               instance.ID,
+			Kind:             instance.Kind,
+			Name:             instance.Name,
+			Properties:       instance.Properties,
+			DependsOn:        instance.DependsOn,
+			DriftPolicy:      instance.DriftPolicy,
+			Sensitive:        instance.Sensitive,
+			Provider:         instance.Provider,
+			Timeouts:         instance.Timeouts,
+			Action:           ActionNone,
+			StateFingerprint: fingerprint,
+			State:            driftResult.CurrentState,
+		}
+
+		if driftResult.CurrentState == nil {
+			resource.Action = ActionCreate
+			p.Summary.Create++
+		} else if driftResult.HasDrift {
+			resource.Action = ActionUpdate
+			resource.Differences = sortedDifferences(driftResult.Differences)
+			resource.MovedFrom = driftResult.MovedFrom
+			p.Summary.Update++
+		}
+
+		p.Resources = append(p.Resources, resource)
+	}
+
+	return p, nil
+}
+
+// Save writes the plan to path as indented JSON
+func (p *Plan) Save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize plan: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write plan file: %w", err)
+	}
+
+	return nil
+}
+
+// Load reads a plan from path, rejecting unsupported format versions
+func Load(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan file: %w", err)
+	}
+
+	// Decode numbers as json.Number so integer properties come back as ints, as they do from YAML
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var p Plan
+	if err := decoder.Decode(&p); err != nil {
+		return nil, fmt.Errorf("failed to parse plan file: %w", err)
+	}
+
+	if p.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("unsupported plan format version %d (expected %d)", p.FormatVersion, FormatVersion)
+	}
+
+	for i := range p.Resources {
+		if p.Resources[i].Properties != nil {
+			p.Resources[i].Properties = normalizeNumbers(p.Resources[i].Properties).(map[string]interface{})
+		}
+		if p.Resources[i].State != nil {
+			p.Resources[i].State = normalizeNumbers(p.Resources[i].State).(map[string]interface{})
+		}
+		for j := range p.Resources[i].Differences {
+			diff := &p.Resources[i].Differences[j]
+			diff.CurrentValue = normalizeNumbers(diff.CurrentValue)
+			diff.DesiredValue = normalizeNumbers(diff.DesiredValue)
+		}
+	}
+
+	return &p, nil
+}
+
+// normalizeNumbers converts json.Number values to int where integral and float64 otherwise
+func normalizeNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return int(i)
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeNumbers(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeNumbers(item)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// Instances returns the resource instances captured in the plan
+func (p *Plan) Instances() []config.ResourceInstance {
+	instances := make([]config.ResourceInstance, 0, len(p.Resources))
+	for _, resource := range p.Resources {
+		instances = append(instances, config.ResourceInstance{
+			ID:          resource.ID,
+			Kind:        resource.Kind,
+			Name:        resource.Name,
+			Properties:  resource.Properties,
+			DriftPolicy: resource.DriftPolicy,
+			DependsOn:   resource.DependsOn,
+			Sensitive:   resource.Sensitive,
+			Provider:    resource.Provider,
+			Timeouts:    resource.Timeouts,
+		})
+	}
+	return instances
+}
+
+// DriftResults rebuilds the drift results the plan was built from, using the state it
+// recorded for each resource, so the plan can be applied without reading live state again
+func (p *Plan) DriftResults() (map[string]*providers.DriftResult, error) {
+	results := make(map[string]*providers.DriftResult, len(p.Resources))
+	for _, resource := range p.Resources {
+		if resource.Action == ActionCreate {
+			results[resource.ID] = &providers.DriftResult{
+				HasDrift:     true,
+				Changes:      []string{"Resource does not exist"},
+				Differences:  map[string]providers.DriftDifference{},
+				DesiredState: resource.Properties,
+			}
+			continue
+		}
+
+		if resource.State == nil {
+			return nil, fmt.Errorf("plan has no recorded state for %s; write a new plan with 'preview --out' to apply it without refreshing", resource.ID)
+		}
+
+		differences := make(map[string]providers.DriftDifference, len(resource.Differences))
+		for _, diff := range resource.Differences {
+			differences[diff.Property] = providers.DriftDifference{
+				Property:     diff.Property,
+				Path:         diff.Path,
+				CurrentValue: diff.CurrentValue,
+				DesiredValue: diff.DesiredValue,
+				DriftType:    providers.DriftType(diff.DriftType),
+			}
+		}
+		changes := drift.DifferencesToChanges(differences)
+		if resource.MovedFrom != "" {
+			changes = append([]string{fmt.Sprintf("Resource was moved from %s", resource.MovedFrom)}, changes...)
+		}
+		results[resource.ID] = &providers.DriftResult{
+			HasDrift:     resource.Action == ActionUpdate,
+			Changes:      changes,
+			Differences:  differences,
+			CurrentState: resource.State,
+			DesiredState: resource.Properties,
+			MovedFrom:    resource.MovedFrom,
+		}
+	}
+
+	return results, nil
+}
+
+// Verify checks that the live drift results still match the state the plan was built against
+func (p *Plan) Verify(driftResults map[string]*providers.DriftResult) error {
+	var stale []string
+
+	for _, resource := range p.Resources {
+		driftResult, exists := driftResults[resource.ID]
+		if !exists {
+			return fmt.Errorf("no drift result for resource %s", resource.ID)
+		}
+
+		fingerprint, err := Fingerprint(driftResult.CurrentState)
+		if err != nil {
+			return fmt.Errorf("failed to fingerprint state of %s: %w", resource.ID, err)
+		}
+
+		if fingerprint != resource.StateFingerprint {
+			stale = append(stale, resource.ID)
+		}
+	}
+
+	if len(stale) > 0 {
+		return fmt.Errorf("live state has changed since the plan was created for: %s; run preview again", strings.Join(stale, ", "))
+	}
+
+	return nil
+}
+
+// Fingerprint returns a stable hash of a resource's live state; a nil state hashes to "absent"
+func Fingerprint(state map[string]interface{}) (string, error) {
+	if state == nil {
+		return "absent", nil
+	}
+
+	// Round-trip through JSON so values loaded from a plan file hash the same as live values
+	data, err := json.Marshal(state)
+	if err != nil {
+		return "", err
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return "", err
+	}
+	data, err = json.Marshal(normalized)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func sortedDifferences(differences map[string]providers.DriftDifference) []Difference {
+	keys := make([]string, 0, len(differences))
+	for key := range differences {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	sorted := make([]Difference, 0, len(keys))
+	for _, key := range keys {
+		diff := differences[key]
+		sorted = append(sorted, Difference{
+			Property:     diff.Property,
+			Path:         diff.Path,
+			CurrentValue: diff.CurrentValue,
+			DesiredValue: diff.DesiredValue,
+			DriftType:    string(diff.DriftType),
+		})
+	}
+	return sorted
+}
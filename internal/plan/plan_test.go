@@ -0,0 +1,202 @@
+package plan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/ataiva-software/runestone/internal/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPlanInputs() ([]config.ResourceInstance, map[string]*providers.DriftResult) {
+	instances := []config.ResourceInstance{
+		{
+			ID:         "aws:s3:bucket.logs",
+			Kind:       "aws:s3:bucket",
+			Name:       "logs",
+			Properties: map[string]interface{}{"versioning": true},
+		},
+		{
+			ID:   "aws:rds:instance.db",
+			Kind: "aws:rds:instance",
+			Name: "db",
+			Properties: map[string]interface{}{
+				"allocated_storage": 100,
+				"instance_class":    "db.t3.micro",
+			},
+			DependsOn: []string{"aws:s3:bucket.logs"},
+		},
+	}
+
+	driftResults := map[string]*providers.DriftResult{
+		"aws:s3:bucket.logs": {
+			HasDrift:     true,
+			CurrentState: nil,
+		},
+		"aws:rds:instance.db": {
+			HasDrift: true,
+			Differences: map[string]providers.DriftDifference{
+				"allocated_storage": {
+					Property:     "allocated_storage",
+					Path:         "/allocated_storage",
+					CurrentValue: 20,
+					DesiredValue: 100,
+					DriftType:    providers.DriftTypeModified,
+				},
+			},
+			CurrentState: map[string]interface{}{
+				"allocated_storage": 20,
+				"instance_class":    "db.t3.micro",
+			},
+		},
+	}
+
+	return instances, driftResults
+}
+
+func TestNew(t *testing.T) {
+	instances, driftResults := testPlanInputs()
+
+	p, err := New("infra.yaml", instances, driftResults)
+	require.NoError(t, err)
+
+	assert.Equal(t, FormatVersion, p.FormatVersion)
+	assert.Equal(t, Summary{Create: 1, Update: 1}, p.Summary)
+	require.Len(t, p.Resources, 2)
+	assert.Equal(t, ActionCreate, p.Resources[0].Action)
+	assert.Equal(t, "absent", p.Resources[0].StateFingerprint)
+	assert.Equal(t, ActionUpdate, p.Resources[1].Action)
+	require.Len(t, p.Resources[1].Differences, 1)
+	assert.Equal(t, "allocated_storage", p.Resources[1].Differences[0].Property)
+
+	_, err = New("infra.yaml", instances, map[string]*providers.DriftResult{})
+	assert.Error(t, err)
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	instances, driftResults := testPlanInputs()
+	path := filepath.Join(t.TempDir(), "plan.json")
+
+	p, err := New("infra.yaml", instances, driftResults)
+	require.NoError(t, err)
+	require.NoError(t, p.Save(path))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "infra.yaml", loaded.ConfigFile)
+	assert.Equal(t, instances, loaded.Instances())
+	assert.Equal(t, 100, loaded.Instances()[1].Properties["allocated_storage"])
+	assert.NoError(t, loaded.Verify(driftResults))
+}
+
+func TestLoad_RejectsUnsupportedVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"format_version": 99, "resources": []}`), 0600))
+
+	_, err := Load(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported plan format version 99")
+}
+
+func TestVerify_DetectsChangedState(t *testing.T) {
+	instances, driftResults := testPlanInputs()
+
+	p, err := New("infra.yaml", instances, driftResults)
+	require.NoError(t, err)
+
+	changed := map[string]*providers.DriftResult{
+		"aws:s3:bucket.logs": {
+			CurrentState: map[string]interface{}{"versioning": false},
+		},
+		"aws:rds:instance.db": driftResults["aws:rds:instance.db"],
+	}
+
+	err = p.Verify(changed)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "aws:s3:bucket.logs")
+	assert.NotContains(t, err.Error(), "aws:rds:instance.db")
+}
+
+func TestFingerprint_NormalizesNumbers(t *testing.T) {
+	fromProvider, err := Fingerprint(map[string]interface{}{"size": 20, "name": "db"})
+	require.NoError(t, err)
+
+	fromPlan, err := Fingerprint(map[string]interface{}{"name": "db", "size": float64(20)})
+	require.NoError(t, err)
+
+	assert.Equal(t, fromProvider, fromPlan)
+}
+
+func TestDriftResults_FromRecordedState(t *testing.T) {
+	instances, driftResults := testPlanInputs()
+	path := filepath.Join(t.TempDir(), "plan.json")
+
+	p, err := New("infra.yaml", instances, driftResults)
+	require.NoError(t, err)
+	require.NoError(t, p.Save(path))
+	loaded, err := Load(path)
+	require.NoError(t, err)
+
+	results, err := loaded.DriftResults()
+	require.NoError(t, err)
+
+	created := results["aws:s3:bucket.logs"]
+	assert.True(t, created.HasDrift)
+	assert.Nil(t, created.CurrentState)
+
+	updated := results["aws:rds:instance.db"]
+	assert.True(t, updated.HasDrift)
+	assert.Equal(t, driftResults["aws:rds:instance.db"].CurrentState, updated.CurrentState)
+	assert.Equal(t, driftResults["aws:rds:instance.db"].Differences, updated.Differences)
+	assert.Equal(t, []string{"Property 'allocated_storage' changed from '20' to '100'"}, updated.Changes)
+}
+
+func TestDriftResults_RequiresRecordedState(t *testing.T) {
+	instances, driftResults := testPlanInputs()
+
+	p, err := New("infra.yaml", instances, driftResults)
+	require.NoError(t, err)
+
+	// Plans written before state was recorded only have a fingerprint
+	p.Resources[1].State = nil
+
+	_, err = p.DriftResults()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no recorded state for aws:rds:instance.db")
+}
+
+func TestDriftResults_Moved(t *testing.T) {
+	instances := []config.ResourceInstance{
+		{ID: "aws:ec2:instance.api", Kind: "aws:ec2:instance", Name: "api", Properties: map[string]interface{}{"instance_type": "t3.micro"}},
+	}
+	driftResults := map[string]*providers.DriftResult{
+		"aws:ec2:instance.api": {
+			HasDrift:     true,
+			Differences:  map[string]providers.DriftDifference{},
+			CurrentState: map[string]interface{}{"instance_type": "t3.micro"},
+			MovedFrom:    "aws:ec2:instance.web",
+		},
+	}
+	path := filepath.Join(t.TempDir(), "plan.json")
+
+	p, err := New("infra.yaml", instances, driftResults)
+	require.NoError(t, err)
+	assert.Equal(t, ActionUpdate, p.Resources[0].Action)
+	assert.Equal(t, "aws:ec2:instance.web", p.Resources[0].MovedFrom)
+	assert.Equal(t, 1, p.Summary.Update)
+	require.NoError(t, p.Save(path))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	results, err := loaded.DriftResults()
+	require.NoError(t, err)
+
+	moved := results["aws:ec2:instance.api"]
+	assert.True(t, moved.HasDrift)
+	assert.Equal(t, "aws:ec2:instance.web", moved.MovedFrom)
+	assert.Equal(t, []string{"Resource was moved from aws:ec2:instance.web"}, moved.Changes)
+}
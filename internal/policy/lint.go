@@ -0,0 +1,144 @@
+package policy
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ataiva-software/runestone/internal/config"
+)
+
+// secretKeyPattern matches property names that usually hold credentials
+var secretKeyPattern = regexp.MustCompile(`(?i)(password|passwd|secret|token|api_?key|private_?key|access_?key)`)
+
+// secretReferencePattern matches property names that identify a credential rather than hold
+// it, such as secret_arn or kms_key_id
+var secretReferencePattern = regexp.MustCompile(`(?i)(_arn|_id|_name)$`)
+
+// HardcodedSecretRule is the lint rule reported by PolicyEngine.FindHardcodedSecrets
+var HardcodedSecretRule = PolicyRule{
+	Name:        "lint-hardcoded-secret",
+	Description: "Credentials should not be written into the configuration",
+	Severity:    "warning",
+	Message:     "Property %s holds a hardcoded value that looks like a credential",
+	Remediation: `Read it at apply time with ${secret("name")}, or from the environment with ${env("NAME")}`,
+	Metadata: map[string]interface{}{
+		"category": "security",
+	},
+}
+
+// LoadLintRules loads the opinionated checks run by 'runestone lint'. They flag common
+// mistakes rather than hard requirements, so every rule is a warning; exemptions waive them
+// like any other rule.
+func (e *PolicyEngine) LoadLintRules() error {
+	lintRules := []PolicyRule{
+		{
+			Name:        "lint-missing-tags",
+			Description: "AWS resources should be tagged",
+			Severity:    "warning",
+			Condition:   "resource.kind startsWith 'aws:' && resource.kind != 'aws:kms:alias' && !tags",
+			Message:     "Resource has no tags, so it can't be attributed to an owner or cost center",
+			Remediation: "Add tags to the resource, or default_tags to its provider to tag every resource",
+			Metadata: map[string]interface{}{
+				"category": "governance",
+			},
+		},
+		{
+			Name:        "lint-ssh-open-to-world",
+			Description: "SSH should not be reachable from the whole internet",
+			Severity:    "warning",
+			Condition: "resource.kind == 'aws:ec2:security_group' && any(properties.ingress ?? [], {" +
+				"'0.0.0.0/0' in (#.cidr_blocks ?? []) && " +
+				"(string(#.protocol ?? '') in ['-1', 'all'] || (#.from_port ?? 0) <= 22 && (#.to_port ?? #.from_port ?? 0) >= 22)" +
+				"})",
+			Message:     "Security group allows SSH (port 22) from 0.0.0.0/0",
+			Remediation: "Restrict cidr_blocks to known address ranges, or use SSM Session Manager instead of SSH",
+			Metadata: map[string]interface{}{
+				"category": "security",
+			},
+		},
+		{
+			Name:        "lint-missing-drift-policy",
+			Description: "Resources should declare how drift is handled",
+			Severity:    "warning",
+			Condition:   "!resource.drift_policy",
+			Message:     "Resource has no driftPolicy, so drift is reported but never healed",
+			Remediation: "Add a driftPolicy with autoHeal: true, or notifyOnly: true to make the choice explicit",
+			Metadata: map[string]interface{}{
+				"category": "operations",
+			},
+		},
+	}
+
+	for _, rule := range lintRules {
+		if err := e.AddRule(rule); err != nil {
+			return fmt.Errorf("failed to add lint rule %s: %w", rule.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// FindHardcodedSecrets reports properties whose names suggest a credential and whose value
+// is written literally in the configuration. It inspects resources as written, before
+// expansion, because once env() has been evaluated a literal can't be told apart from a
+// value read from the environment. Values using ${...} expressions are never reported, and
+// exemptions for the rule waive violations as usual.
+func (e *PolicyEngine) FindHardcodedSecrets(resources []config.Resource) []PolicyViolation {
+	violations := make([]PolicyViolation, 0)
+	for _, resource := range resources {
+		resourceID := fmt.Sprintf("%s.%s", resource.Kind, resource.Name)
+
+		sensitive := make(map[string]bool, len(resource.Sensitive))
+		for _, name := range resource.Sensitive {
+			sensitive[name] = true
+		}
+
+		for _, path := range hardcodedSecretPaths(resource.Properties, "", sensitive) {
+			rule := HardcodedSecretRule
+			violation := PolicyViolation{
+				Rule:         &rule,
+				ResourceID:   resourceID,
+				ResourceKind: resource.Kind,
+				Message:      fmt.Sprintf(rule.Message, path),
+				Severity:     rule.Severity,
+				Remediation:  rule.Remediation,
+				Metadata:     rule.Metadata,
+			}
+			if waiver, exempt := e.findExemption(resourceID, rule.Name); exempt {
+				violation.Waived = true
+				violation.WaiverReason = waiver.reason
+			}
+			violations = append(violations, violation)
+		}
+	}
+	return violations
+}
+
+// looksLikeSecret reports whether a property name suggests it holds a credential
+func looksLikeSecret(key string) bool {
+	return secretKeyPattern.MatchString(key) && !secretReferencePattern.MatchString(key)
+}
+
+// hardcodedSecretPaths returns the dotted paths of literal credential-like values, sorted
+func hardcodedSecretPaths(properties map[string]interface{}, prefix string, sensitive map[string]bool) []string {
+	var paths []string
+	for key, value := range properties {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		switch v := value.(type) {
+		case map[string]interface{}:
+			paths = append(paths, hardcodedSecretPaths(v, path, sensitive)...)
+		case string:
+			if v != "" && !strings.Contains(v, "${") && (sensitive[key] || looksLikeSecret(key)) {
+				paths = append(paths, path)
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,139 @@
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyEngine_LintRules(t *testing.T) {
+	engine := NewPolicyEngine()
+	require.NoError(t, engine.LoadLintRules())
+
+	ruleNames := func(instance config.ResourceInstance) []string {
+		violations, err := engine.EvaluateResource(context.Background(), instance)
+		require.NoError(t, err)
+
+		names := make([]string, 0, len(violations))
+		for _, violation := range violations {
+			assert.NotEmpty(t, violation.Remediation, violation.Rule.Name)
+			names = append(names, violation.Rule.Name)
+		}
+		return names
+	}
+
+	healed := &config.DriftPolicy{AutoHeal: true}
+	tags := map[string]interface{}{"Owner": "platform"}
+
+	t.Run("untagged resource without a drift policy", func(t *testing.T) {
+		assert.Equal(t, []string{"lint-missing-tags", "lint-missing-drift-policy"}, ruleNames(config.ResourceInstance{
+			ID:   "aws:s3:bucket.logs",
+			Kind: "aws:s3:bucket",
+			Name: "logs",
+		}))
+	})
+
+	t.Run("tagged resource with a drift policy", func(t *testing.T) {
+		assert.Empty(t, ruleNames(config.ResourceInstance{
+			ID:          "aws:s3:bucket.logs",
+			Kind:        "aws:s3:bucket",
+			Name:        "logs",
+			Properties:  map[string]interface{}{"tags": tags},
+			DriftPolicy: healed,
+		}))
+	})
+
+	t.Run("kinds without tags aren't asked for them", func(t *testing.T) {
+		assert.Empty(t, ruleNames(config.ResourceInstance{
+			ID:          "aws:kms:alias.app",
+			Kind:        "aws:kms:alias",
+			Name:        "app",
+			DriftPolicy: healed,
+		}))
+	})
+
+	securityGroup := func(ingress ...interface{}) config.ResourceInstance {
+		return config.ResourceInstance{
+			ID:          "aws:ec2:security_group.web",
+			Kind:        "aws:ec2:security_group",
+			Name:        "web",
+			Properties:  map[string]interface{}{"tags": tags, "ingress": ingress},
+			DriftPolicy: healed,
+		}
+	}
+	rule := func(fromPort, toPort int, protocol string, cidrs ...interface{}) map[string]interface{} {
+		return map[string]interface{}{"from_port": fromPort, "to_port": toPort, "protocol": protocol, "cidr_blocks": cidrs}
+	}
+
+	t.Run("ssh open to the world", func(t *testing.T) {
+		assert.Equal(t, []string{"lint-ssh-open-to-world"}, ruleNames(securityGroup(rule(22, 22, "tcp", "0.0.0.0/0"))))
+		assert.Equal(t, []string{"lint-ssh-open-to-world"}, ruleNames(securityGroup(rule(0, 1024, "tcp", "10.0.0.0/8", "0.0.0.0/0"))))
+		assert.Equal(t, []string{"lint-ssh-open-to-world"}, ruleNames(securityGroup(rule(0, 0, "-1", "0.0.0.0/0"))))
+	})
+
+	t.Run("ssh restricted or other ports open", func(t *testing.T) {
+		assert.Empty(t, ruleNames(securityGroup(rule(22, 22, "tcp", "10.0.0.0/8"))))
+		assert.Empty(t, ruleNames(securityGroup(rule(443, 443, "tcp", "0.0.0.0/0"))))
+		assert.Empty(t, ruleNames(securityGroup()))
+	})
+}
+
+func TestFindHardcodedSecrets(t *testing.T) {
+	resources := []config.Resource{
+		{
+			Kind: "aws:rds:instance",
+			Name: "db",
+			Properties: map[string]interface{}{
+				"master_username":      "admin",
+				"master_user_password": "hunter2",
+				"secret_arn":           "arn:aws:secretsmanager:us-east-1:123456789012:secret:db",
+			},
+		},
+		{
+			Kind: "aws:lambda:function",
+			Name: "api",
+			Properties: map[string]interface{}{
+				"environment": map[string]interface{}{
+					"API_KEY":   "abc123",
+					"DB_PASS":   "s3cret",
+					"LOG_LEVEL": "info",
+				},
+			},
+			Sensitive: []string{"DB_PASS"},
+		},
+		{
+			Kind: "aws:lambda:function",
+			Name: "worker",
+			Properties: map[string]interface{}{
+				"environment": map[string]interface{}{
+					"API_KEY":     `${env("API_KEY")}`,
+					"DB_PASSWORD": `${secret("db-password")}`,
+				},
+			},
+		},
+	}
+
+	engine := NewPolicyEngine()
+	require.NoError(t, engine.AddExemptions([]config.PolicyExemption{
+		{Resource: "aws:lambda:function.api", Rule: "lint-hardcoded-secret", Reason: "test fixture"},
+	}))
+
+	violations := engine.FindHardcodedSecrets(resources)
+	require.Len(t, violations, 3)
+
+	assert.Equal(t, "aws:rds:instance.db", violations[0].ResourceID)
+	assert.Equal(t, "Property master_user_password holds a hardcoded value that looks like a credential", violations[0].Message)
+	assert.Equal(t, "lint-hardcoded-secret", violations[0].Rule.Name)
+	assert.Equal(t, "warning", violations[0].Severity)
+	assert.NotEmpty(t, violations[0].Remediation)
+
+	assert.Equal(t, "aws:lambda:function.api", violations[1].ResourceID)
+	assert.Contains(t, violations[1].Message, "environment.API_KEY")
+	assert.Contains(t, violations[2].Message, "environment.DB_PASS")
+	assert.False(t, violations[0].Waived)
+	assert.True(t, violations[1].Waived)
+	assert.Equal(t, "test fixture", violations[1].WaiverReason)
+}
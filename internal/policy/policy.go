@@ -1,11 +1,20 @@
 package policy
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
-	"strings"
+	"io"
+	"os"
+	"reflect"
+	"time"
 
 	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/ast"
+	"github.com/expr-lang/expr/vm"
+	"gopkg.in/yaml.v3"
 )
 
 // PolicyRule represents a single policy rule
@@ -15,6 +24,7 @@ type PolicyRule struct {
 	Severity    string                 `yaml:"severity"` // error, warning, info
 	Condition   string                 `yaml:"condition"`
 	Message     string                 `yaml:"message"`
+	Remediation string                 `yaml:"remediation"` // How to fix a violation, shown alongside it
 	Metadata    map[string]interface{} `yaml:"metadata"`
 }
 
@@ -25,18 +35,35 @@ type PolicyViolation struct {
 	ResourceKind string
 	Message      string
 	Severity     string
+	Remediation  string // How to fix the violation, if the rule says
 	Metadata     map[string]interface{}
+	Waived       bool   // An active exemption covers this violation
+	WaiverReason string // Reason given by the exemption
 }
 
 // PolicyEngine evaluates policies against resources
 type PolicyEngine struct {
-	rules []PolicyRule
+	rules      []PolicyRule
+	programs   []*vm.Program
+	exemptions []exemption
+	rego       *regoPolicies // Set by LoadRegoPolicies
+	now        func() time.Time
+}
+
+// exemption is a parsed config.PolicyExemption
+type exemption struct {
+	resourceID string
+	rule       string
+	expires    time.Time // Zero if the exemption never expires
+	reason     string
 }
 
 // NewPolicyEngine creates a new policy engine
 func NewPolicyEngine() *PolicyEngine {
 	return &PolicyEngine{
-		rules: make([]PolicyRule, 0),
+		rules:    make([]PolicyRule, 0),
+		programs: make([]*vm.Program, 0),
+		now:      time.Now,
 	}
 }
 
@@ -67,73 +94,228 @@ func (e *PolicyEngine) AddRule(rule PolicyRule) error {
 	if !valid {
 		return fmt.Errorf("invalid severity: %s", rule.Severity)
 	}
+
+	program, err := compileCondition(rule.Condition)
+	if err != nil {
+		return fmt.Errorf("invalid policy rule condition: %w", err)
+	}
 	
 	e.rules = append(e.rules, rule)
+	e.programs = append(e.programs, program)
+	return nil
+}
+
+// AddExemptions registers policy exemptions. Violations they cover are still reported but
+// marked as waived; exemptions past their expiry are ignored.
+func (e *PolicyEngine) AddExemptions(exemptions []config.PolicyExemption) error {
+	for i, configured := range exemptions {
+		if configured.Resource == "" {
+			return fmt.Errorf("exemption %d: resource cannot be empty", i+1)
+		}
+		if configured.Rule == "" {
+			return fmt.Errorf("exemption %d: rule cannot be empty", i+1)
+		}
+
+		var expires time.Time
+		if configured.Expires != "" {
+			parsed, err := parseExpiry(configured.Expires)
+			if err != nil {
+				return fmt.Errorf("exemption %d: %w", i+1, err)
+			}
+			expires = parsed
+		}
+
+		e.exemptions = append(e.exemptions, exemption{
+			resourceID: configured.Resource,
+			rule:       configured.Rule,
+			expires:    expires,
+			reason:     configured.Reason,
+		})
+	}
+
 	return nil
 }
 
+// parseExpiry accepts a date, which expires at the end of that day in UTC, or an RFC 3339 time
+func parseExpiry(value string) (time.Time, error) {
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date.AddDate(0, 0, 1), nil
+	}
+	if instant, err := time.Parse(time.RFC3339, value); err == nil {
+		return instant, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid expiry %q: expected YYYY-MM-DD or an RFC 3339 time", value)
+}
+
+// findExemption returns the active exemption for a resource and rule, if any
+func (e *PolicyEngine) findExemption(resourceID, rule string) (exemption, bool) {
+	now := e.now()
+	for _, candidate := range e.exemptions {
+		if candidate.resourceID != resourceID || candidate.rule != rule {
+			continue
+		}
+		if !candidate.expires.IsZero() && !now.Before(candidate.expires) {
+			continue
+		}
+		return candidate, true
+	}
+	return exemption{}, false
+}
+
 // EvaluateResource evaluates all policies against a resource
 func (e *PolicyEngine) EvaluateResource(ctx context.Context, instance config.ResourceInstance) ([]PolicyViolation, error) {
 	violations := make([]PolicyViolation, 0)
 	
-	for _, rule := range e.rules {
-		violated, err := e.evaluateRule(ctx, rule, instance)
+	env := conditionEnv(instance)
+	for i, rule := range e.rules {
+		violated, err := e.evaluateRule(e.programs[i], env)
 		if err != nil {
 			return nil, fmt.Errorf("error evaluating rule %s: %w", rule.Name, err)
 		}
 		
 		if violated {
-			violation := PolicyViolation{
+			violations = append(violations, PolicyViolation{
 				Rule:         &rule,
 				ResourceID:   instance.ID,
 				ResourceKind: instance.Kind,
 				Message:      rule.Message,
 				Severity:     rule.Severity,
+				Remediation:  rule.Remediation,
 				Metadata:     rule.Metadata,
-			}
-			violations = append(violations, violation)
+			})
+		}
+	}
+
+	if e.rego != nil {
+		regoViolations, err := e.rego.evaluate(ctx, instance)
+		if err != nil {
+			return nil, err
+		}
+		violations = append(violations, regoViolations...)
+	}
+
+	for i := range violations {
+		if waiver, exempt := e.findExemption(instance.ID, violations[i].Rule.Name); exempt {
+			violations[i].Waived = true
+			violations[i].WaiverReason = waiver.reason
 		}
 	}
 	
 	return violations, nil
 }
 
-// evaluateRule evaluates a single rule against a resource
-func (e *PolicyEngine) evaluateRule(ctx context.Context, rule PolicyRule, instance config.ResourceInstance) (bool, error) {
-	// Simple condition evaluation - in a real implementation, this would use a proper expression evaluator
-	condition := rule.Condition
-	
-	// Replace placeholders with actual values
-	condition = strings.ReplaceAll(condition, "${resource.kind}", instance.Kind)
-	condition = strings.ReplaceAll(condition, "${resource.name}", instance.Name)
-	
-	// Simple built-in policy checks
-	switch {
-	case strings.Contains(condition, "resource.kind == 'aws:s3:bucket' && !properties.versioning"):
-		if instance.Kind == "aws:s3:bucket" {
-			if versioning, ok := instance.Properties["versioning"].(bool); !ok || !versioning {
-				return true, nil
-			}
+// evaluateRule runs a compiled condition; a truthy result means the rule is violated
+func (e *PolicyEngine) evaluateRule(program *vm.Program, env map[string]interface{}) (bool, error) {
+	result, err := expr.Run(program, env)
+	if err != nil {
+		return false, err
+	}
+	return truthy(result), nil
+}
+
+// compileCondition compiles a rule condition. Operands of !, && and || are converted with
+// truthy, so conditions like "!tags.Environment" hold when the tag is missing or empty.
+func compileCondition(condition string) (*vm.Program, error) {
+	return expr.Compile(condition,
+		expr.Env(conditionEnv(config.ResourceInstance{})),
+		expr.Function("truthy", func(params ...interface{}) (interface{}, error) {
+			return truthy(params[0]), nil
+		}),
+		expr.Patch(&truthinessPatcher{}),
+	)
+}
+
+// conditionEnv builds the variables a condition is evaluated against: resource.id,
+// resource.kind, resource.name and resource.drift_policy (auto_heal and notify_only, or nil
+// without one), the resource's properties, and its tags
+func conditionEnv(instance config.ResourceInstance) map[string]interface{} {
+	properties := instance.Properties
+	if properties == nil {
+		properties = map[string]interface{}{}
+	}
+
+	tags := map[string]interface{}{}
+	switch resourceTags := properties["tags"].(type) {
+	case map[string]interface{}:
+		tags = resourceTags
+	case map[string]string:
+		for key, value := range resourceTags {
+			tags[key] = value
 		}
-		
-	case strings.Contains(condition, "resource.kind == 'aws:ec2:instance' && properties.instance_type == 't3.large'"):
-		if instance.Kind == "aws:ec2:instance" {
-			if instanceType, ok := instance.Properties["instance_type"].(string); ok && instanceType == "t3.large" {
-				return true, nil
-			}
+	}
+
+	var driftPolicy interface{}
+	if instance.DriftPolicy != nil {
+		driftPolicy = map[string]interface{}{
+			"auto_heal":   instance.DriftPolicy.AutoHeal,
+			"notify_only": instance.DriftPolicy.NotifyOnly,
 		}
-		
-	case strings.Contains(condition, "!tags.Environment"):
-		if tags, ok := instance.Properties["tags"].(map[string]interface{}); ok {
-			if _, hasEnv := tags["Environment"]; !hasEnv {
-				return true, nil
-			}
-		} else {
-			return true, nil // No tags at all
+	}
+
+	return map[string]interface{}{
+		"resource": map[string]interface{}{
+			"id":           instance.ID,
+			"kind":         instance.Kind,
+			"name":         instance.Name,
+			"drift_policy": driftPolicy,
+		},
+		"properties": properties,
+		"tags":       tags,
+	}
+}
+
+// truthinessPatcher wraps the operands of logical operators in truthy() calls
+type truthinessPatcher struct{}
+
+func (p *truthinessPatcher) Visit(node *ast.Node) {
+	switch n := (*node).(type) {
+	case *ast.UnaryNode:
+		if n.Operator == "!" || n.Operator == "not" {
+			n.Node = truthyCall(n.Node)
+		}
+	case *ast.BinaryNode:
+		switch n.Operator {
+		case "&&", "||", "and", "or":
+			n.Left = truthyCall(n.Left)
+			n.Right = truthyCall(n.Right)
 		}
 	}
-	
-	return false, nil
+}
+
+func truthyCall(node ast.Node) ast.Node {
+	call := &ast.CallNode{
+		Callee:    &ast.IdentifierNode{Value: "truthy"},
+		Arguments: []ast.Node{node},
+	}
+	call.SetLocation(node.Location())
+	return call
+}
+
+// truthy reports whether a value counts as true: nil, false, zero numbers and empty
+// strings, lists and maps are false
+func truthy(value interface{}) bool {
+	if value == nil {
+		return false
+	}
+	if b, ok := value.(bool); ok {
+		return b
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() > 0
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() != 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint() != 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() != 0
+	case reflect.Ptr, reflect.Interface:
+		return !v.IsNil()
+	default:
+		return true
+	}
 }
 
 // GetViolationsByResource returns violations grouped by resource
@@ -160,10 +342,10 @@ func (e *PolicyEngine) GetViolationsBySeverity(violations []PolicyViolation) map
 	return result
 }
 
-// HasErrors returns true if there are any error-level violations
+// HasErrors returns true if there are any error-level violations that are not waived
 func (e *PolicyEngine) HasErrors(violations []PolicyViolation) bool {
 	for _, violation := range violations {
-		if violation.Severity == "error" {
+		if violation.Severity == "error" && !violation.Waived {
 			return true
 		}
 	}
@@ -214,3 +396,28 @@ func (e *PolicyEngine) LoadBuiltinPolicies() error {
 	
 	return nil
 }
+
+// LoadPoliciesFromFile loads custom policy rules from a YAML file containing a list of rules.
+// Each rule goes through AddRule, so the same name, condition and severity checks apply.
+func (e *PolicyEngine) LoadPoliciesFromFile(filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read policy file %s: %w", filename, err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var rules []PolicyRule
+	if err := decoder.Decode(&rules); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse policy file %s: %w", filename, err)
+	}
+
+	for i, rule := range rules {
+		if err := e.AddRule(rule); err != nil {
+			return fmt.Errorf("invalid policy rule %d in %s: %w", i+1, filename, err)
+		}
+	}
+
+	return nil
+}
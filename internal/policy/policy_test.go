@@ -2,6 +2,9 @@ package policy
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"time"
 	"testing"
 
 	"github.com/ataiva-software/runestone/internal/config"
@@ -153,6 +156,79 @@ func TestPolicyEngine_EvaluateResource(t *testing.T) {
 	})
 }
 
+func TestPolicyEngine_EvaluateResource_Expressions(t *testing.T) {
+	ctx := context.Background()
+
+	instance := config.ResourceInstance{
+		ID:   "aws:ec2:instance.web",
+		Kind: "aws:ec2:instance",
+		Name: "web",
+		Properties: map[string]interface{}{
+			"instance_type": "m5.large",
+			"volume_size":   200,
+			"ports":         []interface{}{22, 443},
+			"tags": map[string]interface{}{
+				"Environment": "dev",
+				"Owner":       "",
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		condition string
+		violated  bool
+	}{
+		{"membership", "properties.instance_type in ['t3.large', 'm5.large']", true},
+		{"membership miss", "properties.instance_type in ['t3.micro']", false},
+		{"numeric comparison", "properties.volume_size > 100", true},
+		{"list contains", "22 in properties.ports", true},
+		{"resource name", "resource.name startsWith 'we'", true},
+		{"resource id", "resource.id == 'aws:ec2:instance.web'", true},
+		{"tag comparison", "tags.Environment == 'dev' && properties.instance_type != 't3.micro'", true},
+		{"missing property is falsy", "!properties.monitoring", true},
+		{"empty tag is falsy", "!tags.Owner", true},
+		{"present tag is truthy", "!tags.Environment", false},
+		{"kind guard", "resource.kind == 'aws:s3:bucket' && !properties.versioning", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewPolicyEngine()
+			require.NoError(t, engine.AddRule(PolicyRule{
+				Name:      "rule",
+				Condition: tt.condition,
+				Message:   "violated",
+			}))
+
+			violations, err := engine.EvaluateResource(ctx, instance)
+			require.NoError(t, err)
+			assert.Equal(t, tt.violated, len(violations) == 1)
+		})
+	}
+
+	t.Run("invalid condition", func(t *testing.T) {
+		err := NewPolicyEngine().AddRule(PolicyRule{
+			Name:      "broken",
+			Condition: "properties.instance_type ==",
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid policy rule condition")
+	})
+
+	t.Run("runtime error", func(t *testing.T) {
+		engine := NewPolicyEngine()
+		require.NoError(t, engine.AddRule(PolicyRule{
+			Name:      "bad-comparison",
+			Condition: "properties.instance_type > 5",
+		}))
+
+		_, err := engine.EvaluateResource(ctx, instance)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "error evaluating rule bad-comparison")
+	})
+}
+
 func TestPolicyEngine_BuiltinPolicies(t *testing.T) {
 	engine := NewPolicyEngine()
 	
@@ -236,3 +312,168 @@ func TestPolicyEngine_Utilities(t *testing.T) {
 		assert.False(t, engine.HasErrors(warningOnly))
 	})
 }
+
+func TestPolicyEngine_LoadPoliciesFromFile(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	t.Run("loads rules", func(t *testing.T) {
+		path := filepath.Join(dir, "policies.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`
+- name: require-environment-tag
+  description: Every resource needs an Environment tag
+  severity: error
+  condition: "!tags.Environment"
+  message: Environment tag is required
+  metadata:
+    owner: platform
+- name: s3-versioning
+  condition: "resource.kind == 'aws:s3:bucket' && !properties.versioning"
+  message: Enable versioning
+`), 0644))
+
+		engine := NewPolicyEngine()
+		require.NoError(t, engine.LoadPoliciesFromFile(path))
+		require.Len(t, engine.rules, 2)
+		assert.Equal(t, "error", engine.rules[0].Severity)
+		assert.Equal(t, "platform", engine.rules[0].Metadata["owner"])
+		assert.Equal(t, "warning", engine.rules[1].Severity)
+
+		violations, err := engine.EvaluateResource(ctx, config.ResourceInstance{
+			ID:         "aws:s3:bucket.logs",
+			Kind:       "aws:s3:bucket",
+			Name:       "logs",
+			Properties: map[string]interface{}{},
+		})
+		require.NoError(t, err)
+		assert.Len(t, violations, 2)
+		assert.True(t, engine.HasErrors(violations))
+	})
+
+	t.Run("rejects invalid rules", func(t *testing.T) {
+		path := filepath.Join(dir, "invalid.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`
+- name: bad-severity
+  severity: critical
+  condition: "!tags.Environment"
+`), 0644))
+
+		err := NewPolicyEngine().LoadPoliciesFromFile(path)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid policy rule 1")
+		assert.Contains(t, err.Error(), "invalid severity: critical")
+	})
+
+	t.Run("rejects unknown fields", func(t *testing.T) {
+		path := filepath.Join(dir, "unknown.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`
+- name: typo
+  condtion: "!tags.Environment"
+`), 0644))
+
+		err := NewPolicyEngine().LoadPoliciesFromFile(path)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "condtion")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		err := NewPolicyEngine().LoadPoliciesFromFile(filepath.Join(dir, "missing.yaml"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to read policy file")
+	})
+}
+
+func TestPolicyEngine_Exemptions(t *testing.T) {
+	ctx := context.Background()
+
+	newEngine := func(t *testing.T, exemptions []config.PolicyExemption) *PolicyEngine {
+		engine := NewPolicyEngine()
+		engine.now = func() time.Time { return time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC) }
+		require.NoError(t, engine.AddRule(PolicyRule{
+			Name:      "s3-versioning-required",
+			Severity:  "error",
+			Condition: "resource.kind == 'aws:s3:bucket' && !properties.versioning",
+			Message:   "S3 bucket must have versioning enabled",
+		}))
+		require.NoError(t, engine.AddExemptions(exemptions))
+		return engine
+	}
+
+	site := config.ResourceInstance{
+		ID:         "aws:s3:bucket.site",
+		Kind:       "aws:s3:bucket",
+		Name:       "site",
+		Properties: map[string]interface{}{},
+	}
+
+	t.Run("active exemption waives violation", func(t *testing.T) {
+		engine := newEngine(t, []config.PolicyExemption{
+			{Resource: "aws:s3:bucket.site", Rule: "s3-versioning-required", Expires: "2026-06-15", Reason: "static site"},
+		})
+
+		violations, err := engine.EvaluateResource(ctx, site)
+		require.NoError(t, err)
+		require.Len(t, violations, 1)
+		assert.True(t, violations[0].Waived)
+		assert.Equal(t, "static site", violations[0].WaiverReason)
+		assert.False(t, engine.HasErrors(violations))
+	})
+
+	t.Run("expired exemption is ignored", func(t *testing.T) {
+		engine := newEngine(t, []config.PolicyExemption{
+			{Resource: "aws:s3:bucket.site", Rule: "s3-versioning-required", Expires: "2026-06-14"},
+		})
+
+		violations, err := engine.EvaluateResource(ctx, site)
+		require.NoError(t, err)
+		require.Len(t, violations, 1)
+		assert.False(t, violations[0].Waived)
+		assert.True(t, engine.HasErrors(violations))
+	})
+
+	t.Run("exemption without expiry", func(t *testing.T) {
+		engine := newEngine(t, []config.PolicyExemption{
+			{Resource: "aws:s3:bucket.site", Rule: "s3-versioning-required"},
+		})
+
+		violations, err := engine.EvaluateResource(ctx, site)
+		require.NoError(t, err)
+		assert.True(t, violations[0].Waived)
+	})
+
+	t.Run("exemption for another resource", func(t *testing.T) {
+		engine := newEngine(t, []config.PolicyExemption{
+			{Resource: "aws:s3:bucket.other", Rule: "s3-versioning-required"},
+		})
+
+		violations, err := engine.EvaluateResource(ctx, site)
+		require.NoError(t, err)
+		assert.False(t, violations[0].Waived)
+	})
+
+	t.Run("RFC 3339 expiry", func(t *testing.T) {
+		engine := newEngine(t, []config.PolicyExemption{
+			{Resource: "aws:s3:bucket.site", Rule: "s3-versioning-required", Expires: "2026-06-15T11:00:00Z"},
+		})
+
+		violations, err := engine.EvaluateResource(ctx, site)
+		require.NoError(t, err)
+		assert.False(t, violations[0].Waived)
+	})
+
+	t.Run("invalid exemptions", func(t *testing.T) {
+		engine := NewPolicyEngine()
+
+		err := engine.AddExemptions([]config.PolicyExemption{{Rule: "rule"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "resource cannot be empty")
+
+		err = engine.AddExemptions([]config.PolicyExemption{{Resource: "aws:s3:bucket.site"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "rule cannot be empty")
+
+		err = engine.AddExemptions([]config.PolicyExemption{{Resource: "aws:s3:bucket.site", Rule: "rule", Expires: "next week"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid expiry")
+	})
+}
@@ -0,0 +1,170 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/open-policy-agent/opa/rego"
+)
+
+// DefaultRegoPackage is the Rego package queried when none is configured
+const DefaultRegoPackage = "runestone"
+
+// regoPolicies evaluates resources against a prepared Rego query.
+//
+// Each ResourceInstance is passed as the input document:
+//
+//	{"id": ..., "kind": ..., "name": ..., "properties": {...}, "tags": {...}, "depends_on": [...]}
+//
+// and the package's deny and warn sets are read. Every element becomes a PolicyViolation:
+//   - deny elements default to severity "error" and warn elements to "warning"
+//   - a string element is the violation message
+//   - an object element may set "msg" (or "message"), "severity" (error, warning or info) and
+//     "rule" (or "name"); other fields are copied into the violation metadata
+//   - the rule name defaults to "<package>.deny" or "<package>.warn", which is also the name
+//     exemptions must use
+type regoPolicies struct {
+	packageName string
+	query       rego.PreparedEvalQuery
+}
+
+// LoadRegoPolicies loads .rego files (or directories of them) and evaluates their deny and
+// warn rules in EvaluateResource, alongside any condition rules added to the engine
+func (e *PolicyEngine) LoadRegoPolicies(ctx context.Context, packageName string, paths []string) error {
+	if len(paths) == 0 {
+		return fmt.Errorf("no Rego policy files specified")
+	}
+	if packageName == "" {
+		packageName = DefaultRegoPackage
+	}
+
+	query, err := rego.New(
+		rego.Query("data."+packageName),
+		rego.Load(paths, nil),
+	).PrepareForEval(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load Rego policies: %w", err)
+	}
+
+	e.rego = &regoPolicies{
+		packageName: packageName,
+		query:       query,
+	}
+	return nil
+}
+
+// evaluate returns the deny and warn results for a resource
+func (r *regoPolicies) evaluate(ctx context.Context, instance config.ResourceInstance) ([]PolicyViolation, error) {
+	env := conditionEnv(instance)
+	dependsOn := make([]interface{}, len(instance.DependsOn))
+	for i, dependency := range instance.DependsOn {
+		dependsOn[i] = dependency
+	}
+	input := map[string]interface{}{
+		"id":         instance.ID,
+		"kind":       instance.Kind,
+		"name":       instance.Name,
+		"properties": env["properties"],
+		"tags":       env["tags"],
+		"depends_on": dependsOn,
+	}
+
+	results, err := r.query.Eval(ctx, rego.EvalInput(input))
+	if err != nil {
+		return nil, fmt.Errorf("error evaluating Rego policies: %w", err)
+	}
+	if len(results) == 0 || len(results[0].Expressions) == 0 {
+		return nil, nil
+	}
+
+	document, ok := results[0].Expressions[0].Value.(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	violations := make([]PolicyViolation, 0)
+	for _, set := range []struct {
+		name     string
+		severity string
+	}{
+		{"deny", "error"},
+		{"warn", "warning"},
+	} {
+		value, exists := document[set.name]
+		if !exists {
+			continue
+		}
+		elements, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("data.%s.%s must be a set or array, got %T", r.packageName, set.name, value)
+		}
+
+		for _, element := range elements {
+			violation, err := r.toViolation(element, r.packageName+"."+set.name, set.severity, instance)
+			if err != nil {
+				return nil, err
+			}
+			violations = append(violations, violation)
+		}
+	}
+
+	// Rego sets are unordered, so sort for stable output
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].Rule.Name != violations[j].Rule.Name {
+			return violations[i].Rule.Name < violations[j].Rule.Name
+		}
+		return violations[i].Message < violations[j].Message
+	})
+
+	return violations, nil
+}
+
+// toViolation maps one deny or warn element to a PolicyViolation
+func (r *regoPolicies) toViolation(element interface{}, ruleName, severity string, instance config.ResourceInstance) (PolicyViolation, error) {
+	message := ""
+	metadata := map[string]interface{}{}
+
+	switch value := element.(type) {
+	case string:
+		message = value
+	case map[string]interface{}:
+		for key, field := range value {
+			switch key {
+			case "msg", "message":
+				message = fmt.Sprintf("%v", field)
+			case "severity":
+				severity = fmt.Sprintf("%v", field)
+			case "rule", "name":
+				ruleName = fmt.Sprintf("%v", field)
+			default:
+				metadata[key] = field
+			}
+		}
+	default:
+		message = fmt.Sprintf("%v", value)
+	}
+
+	switch severity {
+	case "error", "warning", "info":
+	default:
+		return PolicyViolation{}, fmt.Errorf("rule %s returned invalid severity: %s", ruleName, severity)
+	}
+
+	rule := &PolicyRule{
+		Name:     ruleName,
+		Severity: severity,
+		Message:  message,
+		Metadata: metadata,
+	}
+
+	return PolicyViolation{
+		Rule:         rule,
+		ResourceID:   instance.ID,
+		ResourceKind: instance.Kind,
+		Message:      message,
+		Severity:     severity,
+		Metadata:     metadata,
+	}, nil
+}
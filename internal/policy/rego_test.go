@@ -0,0 +1,156 @@
+package policy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRegoPolicy = `package runestone
+
+deny[msg] {
+	input.kind == "aws:s3:bucket"
+	not input.properties.versioning
+	msg := sprintf("bucket %s must enable versioning", [input.name])
+}
+
+deny[{"msg": "instance type is too large", "rule": "instance-size", "limit": "t3.medium"}] {
+	input.kind == "aws:ec2:instance"
+	input.properties.instance_type == "m5.xlarge"
+}
+
+warn[{"msg": "missing Environment tag", "severity": "info"}] {
+	not input.tags.Environment
+}
+`
+
+func writeRegoPolicy(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "policy.rego")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestPolicyEngine_RegoPolicies(t *testing.T) {
+	ctx := context.Background()
+	path := writeRegoPolicy(t, testRegoPolicy)
+
+	engine := NewPolicyEngine()
+	require.NoError(t, engine.LoadRegoPolicies(ctx, "", []string{path}))
+
+	t.Run("string and object results", func(t *testing.T) {
+		violations, err := engine.EvaluateResource(ctx, config.ResourceInstance{
+			ID:         "aws:s3:bucket.logs",
+			Kind:       "aws:s3:bucket",
+			Name:       "logs",
+			Properties: map[string]interface{}{"versioning": false},
+		})
+		require.NoError(t, err)
+		require.Len(t, violations, 2)
+
+		assert.Equal(t, "runestone.deny", violations[0].Rule.Name)
+		assert.Equal(t, "error", violations[0].Severity)
+		assert.Equal(t, "bucket logs must enable versioning", violations[0].Message)
+		assert.Equal(t, "aws:s3:bucket.logs", violations[0].ResourceID)
+
+		assert.Equal(t, "runestone.warn", violations[1].Rule.Name)
+		assert.Equal(t, "info", violations[1].Severity)
+		assert.Equal(t, "missing Environment tag", violations[1].Message)
+	})
+
+	t.Run("custom rule name and metadata", func(t *testing.T) {
+		violations, err := engine.EvaluateResource(ctx, config.ResourceInstance{
+			ID:   "aws:ec2:instance.web",
+			Kind: "aws:ec2:instance",
+			Name: "web",
+			Properties: map[string]interface{}{
+				"instance_type": "m5.xlarge",
+				"tags":          map[string]interface{}{"Environment": "prod"},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, violations, 1)
+		assert.Equal(t, "instance-size", violations[0].Rule.Name)
+		assert.Equal(t, "error", violations[0].Severity)
+		assert.Equal(t, "t3.medium", violations[0].Metadata["limit"])
+		assert.True(t, engine.HasErrors(violations))
+	})
+
+	t.Run("compliant resource", func(t *testing.T) {
+		violations, err := engine.EvaluateResource(ctx, config.ResourceInstance{
+			ID:   "aws:s3:bucket.data",
+			Kind: "aws:s3:bucket",
+			Name: "data",
+			Properties: map[string]interface{}{
+				"versioning": true,
+				"tags":       map[string]interface{}{"Environment": "prod"},
+			},
+		})
+		require.NoError(t, err)
+		assert.Empty(t, violations)
+	})
+
+	t.Run("exemptions apply to Rego rules", func(t *testing.T) {
+		exempted := NewPolicyEngine()
+		require.NoError(t, exempted.LoadRegoPolicies(ctx, "", []string{path}))
+		require.NoError(t, exempted.AddExemptions([]config.PolicyExemption{
+			{Resource: "aws:ec2:instance.web", Rule: "instance-size", Reason: "batch workload"},
+		}))
+
+		violations, err := exempted.EvaluateResource(ctx, config.ResourceInstance{
+			ID:         "aws:ec2:instance.web",
+			Kind:       "aws:ec2:instance",
+			Name:       "web",
+			Properties: map[string]interface{}{"instance_type": "m5.xlarge", "tags": map[string]interface{}{"Environment": "prod"}},
+		})
+		require.NoError(t, err)
+		require.Len(t, violations, 1)
+		assert.True(t, violations[0].Waived)
+		assert.False(t, exempted.HasErrors(violations))
+	})
+}
+
+func TestPolicyEngine_RegoPolicies_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("custom package", func(t *testing.T) {
+		path := writeRegoPolicy(t, "package main\n\ndeny[\"always\"] { true }\n")
+
+		engine := NewPolicyEngine()
+		require.NoError(t, engine.LoadRegoPolicies(ctx, "main", []string{path}))
+
+		violations, err := engine.EvaluateResource(ctx, config.ResourceInstance{ID: "aws:s3:bucket.a", Kind: "aws:s3:bucket", Name: "a"})
+		require.NoError(t, err)
+		require.Len(t, violations, 1)
+		assert.Equal(t, "main.deny", violations[0].Rule.Name)
+	})
+
+	t.Run("no files", func(t *testing.T) {
+		err := NewPolicyEngine().LoadRegoPolicies(ctx, "", nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no Rego policy files")
+	})
+
+	t.Run("syntax error", func(t *testing.T) {
+		path := writeRegoPolicy(t, "package runestone\n\ndeny[msg] {\n")
+
+		err := NewPolicyEngine().LoadRegoPolicies(ctx, "", []string{path})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to load Rego policies")
+	})
+
+	t.Run("invalid severity", func(t *testing.T) {
+		path := writeRegoPolicy(t, "package runestone\n\nwarn[{\"msg\": \"x\", \"severity\": \"critical\"}] { true }\n")
+
+		engine := NewPolicyEngine()
+		require.NoError(t, engine.LoadRegoPolicies(ctx, "", []string{path}))
+
+		_, err := engine.EvaluateResource(ctx, config.ResourceInstance{ID: "aws:s3:bucket.a", Kind: "aws:s3:bucket", Name: "a"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid severity: critical")
+	})
+}
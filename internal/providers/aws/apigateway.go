@@ -3,16 +3,187 @@ package aws
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"sort"
+	"strings"
 
+	"github.com/ataiva-software/runestone/internal/config"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/apigateway"
-	"github.com/ataiva-software/runestone/internal/config"
+	apigatewaytypes "github.com/aws/aws-sdk-go-v2/service/apigateway/types"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
 )
 
+// apiGatewayMethods are the HTTP methods a route can use
+var apiGatewayMethods = map[string]bool{
+	"GET":     true,
+	"POST":    true,
+	"PUT":     true,
+	"PATCH":   true,
+	"DELETE":  true,
+	"HEAD":    true,
+	"OPTIONS": true,
+	"ANY":     true,
+}
+
+// lambdaFunctionARNPattern matches an unqualified or qualified Lambda function ARN,
+// capturing its partition, region and account ID
+var lambdaFunctionARNPattern = regexp.MustCompile(`^arn:(aws[a-z-]*):lambda:([a-z0-9-]+):(\d{12}):function:[A-Za-z0-9_-]{1,64}(:[A-Za-z0-9_$-]+)?$`)
+
+// lambdaInvocationURIPattern matches the integration URI API Gateway uses to invoke a Lambda
+// function, capturing the function ARN
+var lambdaInvocationURIPattern = regexp.MustCompile(`^arn:aws[a-z-]*:apigateway:[a-z0-9-]+:lambda:path/2015-03-31/functions/(.+)/invocations$`)
+
+// apiGatewayRoute is a method on a REST API resource path and the integration it calls
+type apiGatewayRoute struct {
+	Path            string
+	Method          string
+	IntegrationType apigatewaytypes.IntegrationType
+	IntegrationURI  string
+}
+
+// key identifies a route by its method and path
+func (r apiGatewayRoute) key() string {
+	return r.Method + " " + r.Path
+}
+
+// state returns the route as it is written in configuration
+func (r apiGatewayRoute) state() map[string]interface{} {
+	route := map[string]interface{}{
+		"path":             r.Path,
+		"method":           r.Method,
+		"integration_type": string(r.IntegrationType),
+	}
+	if r.IntegrationURI != "" {
+		route["integration_uri"] = r.IntegrationURI
+	}
+	return route
+}
+
+// integrationURI returns the URI API Gateway calls for the route. Lambda proxy routes are
+// configured with a function ARN, which is wrapped in the function's invocation URI.
+func (r apiGatewayRoute) integrationURI() string {
+	if r.IntegrationType != apigatewaytypes.IntegrationTypeAwsProxy {
+		return r.IntegrationURI
+	}
+	match := lambdaFunctionARNPattern.FindStringSubmatch(r.IntegrationURI)
+	if match == nil {
+		return r.IntegrationURI
+	}
+	return fmt.Sprintf("arn:%s:apigateway:%s:lambda:path/2015-03-31/functions/%s/invocations", match[1], match[2], r.IntegrationURI)
+}
+
+// integrationHTTPMethod returns the method API Gateway uses to call the route's backend.
+// Lambda is always invoked with POST, while HTTP backends receive the route's own method.
+func (r apiGatewayRoute) integrationHTTPMethod() *string {
+	switch r.IntegrationType {
+	case apigatewaytypes.IntegrationTypeAws, apigatewaytypes.IntegrationTypeAwsProxy:
+		return aws.String("POST")
+	case apigatewaytypes.IntegrationTypeHttp, apigatewaytypes.IntegrationTypeHttpProxy:
+		return aws.String(r.Method)
+	default:
+		return nil
+	}
+}
+
+// parseAPIGatewayRoutes reads the routes property
+func parseAPIGatewayRoutes(properties map[string]interface{}) ([]apiGatewayRoute, error) {
+	routesVal, exists := properties["routes"]
+	if !exists {
+		return nil, nil
+	}
+
+	routesList, ok := routesVal.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("routes must be a list")
+	}
+
+	routes := make([]apiGatewayRoute, 0, len(routesList))
+	seen := make(map[string]bool)
+	for i, routeVal := range routesList {
+		routeMap, ok := routeVal.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("route %d must have a path, method and integration_type", i)
+		}
+
+		path, _ := routeMap["path"].(string)
+		method, _ := routeMap["method"].(string)
+		integrationType, _ := routeMap["integration_type"].(string)
+		integrationURI, _ := routeMap["integration_uri"].(string)
+		route := apiGatewayRoute{
+			Path:            path,
+			Method:          method,
+			IntegrationType: apigatewaytypes.IntegrationType(integrationType),
+			IntegrationURI:  integrationURI,
+		}
+
+		if err := validateAPIGatewayPath(path); err != nil {
+			return nil, fmt.Errorf("route %d: %w", i, err)
+		}
+		if !apiGatewayMethods[method] {
+			return nil, fmt.Errorf("route %d: invalid method '%s': must be GET, POST, PUT, PATCH, DELETE, HEAD, OPTIONS or ANY", i, method)
+		}
+		if seen[route.key()] {
+			return nil, fmt.Errorf("route %d: %s is declared more than once", i, route.key())
+		}
+		seen[route.key()] = true
+
+		switch route.IntegrationType {
+		case apigatewaytypes.IntegrationTypeMock:
+		case apigatewaytypes.IntegrationTypeAwsProxy:
+			// References to a function created in the same commit are resolved later
+			if !strings.Contains(integrationURI, "${") && !lambdaFunctionARNPattern.MatchString(integrationURI) {
+				return nil, fmt.Errorf("route %d: integration_uri '%s' is not a Lambda function ARN", i, integrationURI)
+			}
+		case apigatewaytypes.IntegrationTypeAws, apigatewaytypes.IntegrationTypeHttp, apigatewaytypes.IntegrationTypeHttpProxy:
+			if integrationURI == "" {
+				return nil, fmt.Errorf("route %d: integration_uri is required for %s integrations", i, integrationType)
+			}
+		default:
+			return nil, fmt.Errorf("route %d: invalid integration_type '%s': must be AWS_PROXY, HTTP_PROXY, HTTP, AWS or MOCK", i, integrationType)
+		}
+
+		routes = append(routes, route)
+	}
+
+	return routes, nil
+}
+
+// validateAPIGatewayPath checks that a route path is absolute and has no empty segments
+func validateAPIGatewayPath(path string) error {
+	if !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("path '%s' must start with /", path)
+	}
+	if path == "/" {
+		return nil
+	}
+	for _, part := range strings.Split(path[1:], "/") {
+		if part == "" {
+			return fmt.Errorf("path '%s' has an empty segment", path)
+		}
+	}
+	return nil
+}
+
 func (p *Provider) validateAPIGateway(instance config.ResourceInstance) error {
 	if instance.Name == "" {
 		return fmt.Errorf("API Gateway name cannot be empty")
 	}
+
+	routes, err := parseAPIGatewayRoutes(instance.Properties)
+	if err != nil {
+		return err
+	}
+
+	if stageVal, exists := instance.Properties["stage_name"]; exists {
+		if stage, ok := stageVal.(string); !ok || stage == "" {
+			return fmt.Errorf("stage_name must be a non-empty string")
+		}
+		if len(routes) == 0 {
+			return fmt.Errorf("stage_name requires at least one route to deploy")
+		}
+	}
+
 	return nil
 }
 
@@ -26,17 +197,120 @@ func (p *Provider) getAPIGatewayState(ctx context.Context, instance config.Resou
 
 	for _, api := range result.Items {
 		if api.Name != nil && *api.Name == instance.Name {
-			return map[string]interface{}{
+			state := map[string]interface{}{
 				"id":          *api.Id,
 				"name":        *api.Name,
 				"description": aws.ToString(api.Description),
-			}, nil
+			}
+			if err := p.readAPIGatewayDeployment(ctx, client, instance, *api.Id, state); err != nil {
+				return nil, err
+			}
+			return state, nil
 		}
 	}
 
 	return nil, nil
 }
 
+// readAPIGatewayDeployment adds the routes and stage of a REST API to its state, for the
+// properties the configuration manages
+func (p *Provider) readAPIGatewayDeployment(ctx context.Context, client *apigateway.Client, instance config.ResourceInstance, apiID string, state map[string]interface{}) error {
+	if _, exists := instance.Properties["routes"]; exists {
+		deployed, err := getAPIGatewayRoutes(ctx, client, apiID)
+		if err != nil {
+			return err
+		}
+		// Invalid routes are reported by validation; drift is measured against the valid ones
+		desired, _ := parseAPIGatewayRoutes(instance.Properties)
+		state["routes"] = orderAPIGatewayRoutes(deployed, desired)
+	}
+
+	if stageName, ok := instance.Properties["stage_name"].(string); ok {
+		result, err := client.GetStages(ctx, &apigateway.GetStagesInput{RestApiId: aws.String(apiID)})
+		if err != nil {
+			return fmt.Errorf("failed to get stages of REST API %s: %w", instance.Name, err)
+		}
+		for _, stage := range result.Item {
+			if aws.ToString(stage.StageName) == stageName {
+				state["stage_name"] = stageName
+				state["invoke_url"] = fmt.Sprintf("https://%s.execute-api.%s.amazonaws.com/%s", apiID, p.awsConfig.Region, stageName)
+			}
+		}
+	}
+
+	return nil
+}
+
+// getAPIGatewayRoutes lists the methods of every resource of a REST API with the integration
+// each one calls
+func getAPIGatewayRoutes(ctx context.Context, client apigateway.GetResourcesAPIClient, apiID string) ([]apiGatewayRoute, error) {
+	resources, err := getAPIGatewayResources(ctx, client, apiID)
+	if err != nil {
+		return nil, err
+	}
+
+	var routes []apiGatewayRoute
+	for _, resource := range resources {
+		for method, details := range resource.ResourceMethods {
+			route := apiGatewayRoute{Path: aws.ToString(resource.Path), Method: method}
+			if integration := details.MethodIntegration; integration != nil {
+				route.IntegrationType = integration.Type
+				route.IntegrationURI = aws.ToString(integration.Uri)
+				if match := lambdaInvocationURIPattern.FindStringSubmatch(route.IntegrationURI); match != nil && integration.Type == apigatewaytypes.IntegrationTypeAwsProxy {
+					route.IntegrationURI = match[1]
+				}
+			}
+			routes = append(routes, route)
+		}
+	}
+	return routes, nil
+}
+
+// getAPIGatewayResources lists the resources of a REST API with their methods embedded
+func getAPIGatewayResources(ctx context.Context, client apigateway.GetResourcesAPIClient, apiID string) ([]apigatewaytypes.Resource, error) {
+	var resources []apigatewaytypes.Resource
+	paginator := apigateway.NewGetResourcesPaginator(client, &apigateway.GetResourcesInput{
+		RestApiId: aws.String(apiID),
+		Embed:     []string{"methods"},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get resources of REST API %s: %w", apiID, err)
+		}
+		resources = append(resources, page.Items...)
+	}
+	return resources, nil
+}
+
+// orderAPIGatewayRoutes orders deployed routes to match the desired list, followed by routes
+// the configuration doesn't declare in a stable order, so that ordering isn't reported as drift
+func orderAPIGatewayRoutes(deployed []apiGatewayRoute, desired []apiGatewayRoute) []interface{} {
+	byKey := make(map[string]apiGatewayRoute, len(deployed))
+	for _, route := range deployed {
+		byKey[route.key()] = route
+	}
+
+	ordered := make([]interface{}, 0, len(deployed))
+	for _, route := range desired {
+		if found, exists := byKey[route.key()]; exists {
+			ordered = append(ordered, found.state())
+			delete(byKey, route.key())
+		}
+	}
+
+	remaining := make([]string, 0, len(byKey))
+	for key := range byKey {
+		remaining = append(remaining, key)
+	}
+	sort.Strings(remaining)
+	for _, key := range remaining {
+		ordered = append(ordered, byKey[key].state())
+	}
+
+	return ordered
+}
+
 func (p *Provider) createAPIGateway(ctx context.Context, instance config.ResourceInstance) error {
 	client := apigateway.NewFromConfig(p.awsConfig)
 
@@ -50,16 +324,226 @@ func (p *Provider) createAPIGateway(ctx context.Context, instance config.Resourc
 		}
 	}
 
-	_, err := client.CreateRestApi(ctx, input)
+	result, err := client.CreateRestApi(ctx, input)
 	if err != nil {
 		return fmt.Errorf("failed to create API Gateway %s: %w", instance.Name, err)
 	}
 
+	return p.deployAPIGateway(ctx, client, instance, *result.Id)
+}
+
+func (p *Provider) updateAPIGateway(ctx context.Context, instance config.ResourceInstance, currentState map[string]interface{}) error {
+	client := apigateway.NewFromConfig(p.awsConfig)
+
+	apiID, _ := currentState["id"].(string)
+	if apiID == "" {
+		return fmt.Errorf("REST API %s not found", instance.Name)
+	}
+
+	if desc, ok := instance.Properties["description"].(string); ok && desc != currentState["description"] {
+		_, err := client.UpdateRestApi(ctx, &apigateway.UpdateRestApiInput{
+			RestApiId: aws.String(apiID),
+			PatchOperations: []apigatewaytypes.PatchOperation{
+				{
+					Op:    apigatewaytypes.OpReplace,
+					Path:  aws.String("/description"),
+					Value: aws.String(desc),
+				},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update API Gateway %s: %w", instance.Name, err)
+		}
+	}
+
+	return p.deployAPIGateway(ctx, client, instance, apiID)
+}
+
+// deployAPIGateway creates the configured routes of a REST API, lets API Gateway invoke the
+// Lambda functions they call and deploys the API to its stage so it can be invoked
+func (p *Provider) deployAPIGateway(ctx context.Context, client *apigateway.Client, instance config.ResourceInstance, apiID string) error {
+	if _, exists := instance.Properties["routes"]; !exists {
+		return nil
+	}
+
+	routes, err := parseAPIGatewayRoutes(instance.Properties)
+	if err != nil {
+		return err
+	}
+
+	if err := syncAPIGatewayRoutes(ctx, client, apiID, routes); err != nil {
+		return fmt.Errorf("failed to configure routes of API Gateway %s: %w", instance.Name, err)
+	}
+
+	lambdaClient := lambda.NewFromConfig(p.awsConfig)
+	if err := grantAPIGatewayInvoke(ctx, lambdaClient, apiID, routes); err != nil {
+		return err
+	}
+
+	if stageName, ok := instance.Properties["stage_name"].(string); ok {
+		_, err := client.CreateDeployment(ctx, &apigateway.CreateDeploymentInput{
+			RestApiId: aws.String(apiID),
+			StageName: aws.String(stageName),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to deploy API Gateway %s to stage %s: %w", instance.Name, stageName, err)
+		}
+		p.log().Info("deployed API Gateway", "api", instance.Name, "stage", stageName)
+	}
+
 	return nil
 }
 
-func (p *Provider) updateAPIGateway(ctx context.Context, instance config.ResourceInstance) error {
-	return nil // Basic implementation
+// apiGatewayRoutesAPI is the subset of the API Gateway API used to manage routes
+type apiGatewayRoutesAPI interface {
+	apigateway.GetResourcesAPIClient
+	CreateResource(ctx context.Context, params *apigateway.CreateResourceInput, optFns ...func(*apigateway.Options)) (*apigateway.CreateResourceOutput, error)
+	PutMethod(ctx context.Context, params *apigateway.PutMethodInput, optFns ...func(*apigateway.Options)) (*apigateway.PutMethodOutput, error)
+	PutIntegration(ctx context.Context, params *apigateway.PutIntegrationInput, optFns ...func(*apigateway.Options)) (*apigateway.PutIntegrationOutput, error)
+	DeleteMethod(ctx context.Context, params *apigateway.DeleteMethodInput, optFns ...func(*apigateway.Options)) (*apigateway.DeleteMethodOutput, error)
+}
+
+// syncAPIGatewayRoutes makes the methods of a REST API match the routes. Resources are
+// created for missing path segments, integrations that differ are replaced and methods that
+// no route declares are deleted.
+func syncAPIGatewayRoutes(ctx context.Context, client apiGatewayRoutesAPI, apiID string, routes []apiGatewayRoute) error {
+	resources, err := getAPIGatewayResources(ctx, client, apiID)
+	if err != nil {
+		return err
+	}
+
+	byPath := make(map[string]apigatewaytypes.Resource, len(resources))
+	for _, resource := range resources {
+		byPath[aws.ToString(resource.Path)] = resource
+	}
+
+	desired := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		desired[route.key()] = true
+
+		resourceID, err := ensureAPIGatewayResource(ctx, client, apiID, route.Path, byPath)
+		if err != nil {
+			return err
+		}
+
+		existing, hasMethod := byPath[route.Path].ResourceMethods[route.Method]
+		if hasMethod && existing.MethodIntegration != nil &&
+			existing.MethodIntegration.Type == route.IntegrationType &&
+			aws.ToString(existing.MethodIntegration.Uri) == route.integrationURI() {
+			continue
+		}
+
+		if !hasMethod {
+			_, err := client.PutMethod(ctx, &apigateway.PutMethodInput{
+				RestApiId:         aws.String(apiID),
+				ResourceId:        aws.String(resourceID),
+				HttpMethod:        aws.String(route.Method),
+				AuthorizationType: aws.String("NONE"),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create method %s: %w", route.key(), err)
+			}
+		}
+
+		input := &apigateway.PutIntegrationInput{
+			RestApiId:             aws.String(apiID),
+			ResourceId:            aws.String(resourceID),
+			HttpMethod:            aws.String(route.Method),
+			Type:                  route.IntegrationType,
+			IntegrationHttpMethod: route.integrationHTTPMethod(),
+		}
+		if uri := route.integrationURI(); uri != "" {
+			input.Uri = aws.String(uri)
+		}
+		if _, err := client.PutIntegration(ctx, input); err != nil {
+			return fmt.Errorf("failed to set integration of %s: %w", route.key(), err)
+		}
+	}
+
+	for _, resource := range resources {
+		for method := range resource.ResourceMethods {
+			route := apiGatewayRoute{Path: aws.ToString(resource.Path), Method: method}
+			if desired[route.key()] {
+				continue
+			}
+			_, err := client.DeleteMethod(ctx, &apigateway.DeleteMethodInput{
+				RestApiId:  aws.String(apiID),
+				ResourceId: resource.Id,
+				HttpMethod: aws.String(method),
+			})
+			if err != nil && !isResourceNotFound(err) {
+				return fmt.Errorf("failed to delete method %s: %w", route.key(), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ensureAPIGatewayResource returns the ID of the resource at a path, creating it and any
+// missing parent resources. Created resources are added to byPath.
+func ensureAPIGatewayResource(ctx context.Context, client apiGatewayRoutesAPI, apiID, path string, byPath map[string]apigatewaytypes.Resource) (string, error) {
+	if resource, exists := byPath[path]; exists {
+		return aws.ToString(resource.Id), nil
+	}
+	if path == "/" {
+		return "", fmt.Errorf("REST API %s has no root resource", apiID)
+	}
+
+	parentPath := "/"
+	if i := strings.LastIndex(path, "/"); i > 0 {
+		parentPath = path[:i]
+	}
+	parentID, err := ensureAPIGatewayResource(ctx, client, apiID, parentPath, byPath)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := client.CreateResource(ctx, &apigateway.CreateResourceInput{
+		RestApiId: aws.String(apiID),
+		ParentId:  aws.String(parentID),
+		PathPart:  aws.String(path[strings.LastIndex(path, "/")+1:]),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create resource %s: %w", path, err)
+	}
+
+	byPath[path] = apigatewaytypes.Resource{Id: result.Id, ParentId: result.ParentId, Path: aws.String(path)}
+	return aws.ToString(result.Id), nil
+}
+
+// lambdaPermissionAPI is the subset of the Lambda API used to let API Gateway invoke a function
+type lambdaPermissionAPI interface {
+	AddPermission(ctx context.Context, params *lambda.AddPermissionInput, optFns ...func(*lambda.Options)) (*lambda.AddPermissionOutput, error)
+}
+
+// grantAPIGatewayInvoke adds a resource policy statement to each Lambda function the routes
+// proxy to, allowing any method of the REST API to invoke it. Statements that already exist
+// are left in place.
+func grantAPIGatewayInvoke(ctx context.Context, client lambdaPermissionAPI, apiID string, routes []apiGatewayRoute) error {
+	granted := make(map[string]bool)
+	for _, route := range routes {
+		if route.IntegrationType != apigatewaytypes.IntegrationTypeAwsProxy || granted[route.IntegrationURI] {
+			continue
+		}
+		match := lambdaFunctionARNPattern.FindStringSubmatch(route.IntegrationURI)
+		if match == nil {
+			return fmt.Errorf("integration_uri '%s' of route %s is not a Lambda function ARN", route.IntegrationURI, route.key())
+		}
+		granted[route.IntegrationURI] = true
+
+		_, err := client.AddPermission(ctx, &lambda.AddPermissionInput{
+			FunctionName: aws.String(route.IntegrationURI),
+			StatementId:  aws.String("runestone-apigateway-" + apiID),
+			Action:       aws.String("lambda:InvokeFunction"),
+			Principal:    aws.String("apigateway.amazonaws.com"),
+			SourceArn:    aws.String(fmt.Sprintf("arn:%s:execute-api:%s:%s:%s/*", match[1], match[2], match[3], apiID)),
+		})
+		if err != nil && !strings.Contains(err.Error(), "ResourceConflictException") {
+			return fmt.Errorf("failed to allow API Gateway to invoke %s: %w", route.IntegrationURI, err)
+		}
+	}
+	return nil
 }
 
 func (p *Provider) deleteAPIGateway(ctx context.Context, instance config.ResourceInstance) error {
@@ -1,12 +1,21 @@
 package aws
 
 import (
+	"context"
+	"fmt"
 	"testing"
 
 	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/apigateway"
+	apigatewaytypes "github.com/aws/aws-sdk-go-v2/service/apigateway/types"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+const testFunctionARN = "arn:aws:lambda:us-east-1:123456789012:function:api-handler"
+
 func TestValidateAPIGateway(t *testing.T) {
 	provider := NewProvider()
 
@@ -39,6 +48,107 @@ func TestValidateAPIGateway(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "routes with a stage",
+			instance: config.ResourceInstance{
+				ID:   "aws:apigateway:rest_api.test-api",
+				Kind: "aws:apigateway:rest_api",
+				Name: "test-api",
+				Properties: map[string]interface{}{
+					"stage_name": "prod",
+					"routes": []interface{}{
+						map[string]interface{}{"path": "/users/{id}", "method": "GET", "integration_type": "AWS_PROXY", "integration_uri": testFunctionARN},
+						map[string]interface{}{"path": "/orders", "method": "ANY", "integration_type": "AWS_PROXY", "integration_uri": "${aws:lambda:function.orders.function_arn}"},
+						map[string]interface{}{"path": "/health", "method": "GET", "integration_type": "MOCK"},
+						map[string]interface{}{"path": "/legacy", "method": "ANY", "integration_type": "HTTP_PROXY", "integration_uri": "https://legacy.example.com"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Lambda proxy route without a function ARN",
+			instance: config.ResourceInstance{
+				ID:   "aws:apigateway:rest_api.test-api",
+				Kind: "aws:apigateway:rest_api",
+				Name: "test-api",
+				Properties: map[string]interface{}{
+					"routes": []interface{}{
+						map[string]interface{}{"path": "/users", "method": "GET", "integration_type": "AWS_PROXY", "integration_uri": "api-handler"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "route with an invalid method",
+			instance: config.ResourceInstance{
+				ID:   "aws:apigateway:rest_api.test-api",
+				Kind: "aws:apigateway:rest_api",
+				Name: "test-api",
+				Properties: map[string]interface{}{
+					"routes": []interface{}{
+						map[string]interface{}{"path": "/users", "method": "FETCH", "integration_type": "MOCK"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "route with a relative path",
+			instance: config.ResourceInstance{
+				ID:   "aws:apigateway:rest_api.test-api",
+				Kind: "aws:apigateway:rest_api",
+				Name: "test-api",
+				Properties: map[string]interface{}{
+					"routes": []interface{}{
+						map[string]interface{}{"path": "users", "method": "GET", "integration_type": "MOCK"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "HTTP route without a URI",
+			instance: config.ResourceInstance{
+				ID:   "aws:apigateway:rest_api.test-api",
+				Kind: "aws:apigateway:rest_api",
+				Name: "test-api",
+				Properties: map[string]interface{}{
+					"routes": []interface{}{
+						map[string]interface{}{"path": "/users", "method": "GET", "integration_type": "HTTP"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate routes",
+			instance: config.ResourceInstance{
+				ID:   "aws:apigateway:rest_api.test-api",
+				Kind: "aws:apigateway:rest_api",
+				Name: "test-api",
+				Properties: map[string]interface{}{
+					"routes": []interface{}{
+						map[string]interface{}{"path": "/users", "method": "GET", "integration_type": "MOCK"},
+						map[string]interface{}{"path": "/users", "method": "GET", "integration_type": "MOCK"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "stage without routes",
+			instance: config.ResourceInstance{
+				ID:   "aws:apigateway:rest_api.test-api",
+				Kind: "aws:apigateway:rest_api",
+				Name: "test-api",
+				Properties: map[string]interface{}{
+					"stage_name": "prod",
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -52,3 +162,167 @@ func TestValidateAPIGateway(t *testing.T) {
 		})
 	}
 }
+
+// fakeAPIGateway keeps the resources of one REST API in memory
+type fakeAPIGateway struct {
+	resources    []apigatewaytypes.Resource
+	integrations []*apigateway.PutIntegrationInput
+	deleted      []string
+	nextID       int
+}
+
+func (f *fakeAPIGateway) GetResources(ctx context.Context, params *apigateway.GetResourcesInput, optFns ...func(*apigateway.Options)) (*apigateway.GetResourcesOutput, error) {
+	return &apigateway.GetResourcesOutput{Items: f.resources}, nil
+}
+
+func (f *fakeAPIGateway) CreateResource(ctx context.Context, params *apigateway.CreateResourceInput, optFns ...func(*apigateway.Options)) (*apigateway.CreateResourceOutput, error) {
+	f.nextID++
+	id := fmt.Sprintf("res-%d", f.nextID)
+	path := "/" + *params.PathPart
+	for _, resource := range f.resources {
+		if *resource.Id == *params.ParentId && *resource.Path != "/" {
+			path = *resource.Path + path
+		}
+	}
+	f.resources = append(f.resources, apigatewaytypes.Resource{Id: aws.String(id), ParentId: params.ParentId, Path: aws.String(path)})
+	return &apigateway.CreateResourceOutput{Id: aws.String(id), ParentId: params.ParentId, Path: aws.String(path)}, nil
+}
+
+func (f *fakeAPIGateway) PutMethod(ctx context.Context, params *apigateway.PutMethodInput, optFns ...func(*apigateway.Options)) (*apigateway.PutMethodOutput, error) {
+	for i, resource := range f.resources {
+		if *resource.Id == *params.ResourceId {
+			if resource.ResourceMethods == nil {
+				f.resources[i].ResourceMethods = make(map[string]apigatewaytypes.Method)
+			}
+			f.resources[i].ResourceMethods[*params.HttpMethod] = apigatewaytypes.Method{HttpMethod: params.HttpMethod}
+		}
+	}
+	return &apigateway.PutMethodOutput{}, nil
+}
+
+func (f *fakeAPIGateway) PutIntegration(ctx context.Context, params *apigateway.PutIntegrationInput, optFns ...func(*apigateway.Options)) (*apigateway.PutIntegrationOutput, error) {
+	f.integrations = append(f.integrations, params)
+	return &apigateway.PutIntegrationOutput{}, nil
+}
+
+func (f *fakeAPIGateway) DeleteMethod(ctx context.Context, params *apigateway.DeleteMethodInput, optFns ...func(*apigateway.Options)) (*apigateway.DeleteMethodOutput, error) {
+	f.deleted = append(f.deleted, *params.HttpMethod+" "+*params.ResourceId)
+	return &apigateway.DeleteMethodOutput{}, nil
+}
+
+// testResource returns a resource with methods integrated as given
+func testResource(id, path string, integrations map[string]*apigatewaytypes.Integration) apigatewaytypes.Resource {
+	methods := make(map[string]apigatewaytypes.Method)
+	for method, integration := range integrations {
+		methods[method] = apigatewaytypes.Method{HttpMethod: aws.String(method), MethodIntegration: integration}
+	}
+	return apigatewaytypes.Resource{Id: aws.String(id), Path: aws.String(path), ResourceMethods: methods}
+}
+
+func TestAPIGatewayRoute_IntegrationURI(t *testing.T) {
+	route := apiGatewayRoute{Path: "/users", Method: "GET", IntegrationType: apigatewaytypes.IntegrationTypeAwsProxy, IntegrationURI: testFunctionARN}
+	assert.Equal(t, "arn:aws:apigateway:us-east-1:lambda:path/2015-03-31/functions/"+testFunctionARN+"/invocations", route.integrationURI())
+	assert.Equal(t, "POST", *route.integrationHTTPMethod(), "Lambda is invoked with POST whatever the route's method")
+
+	route = apiGatewayRoute{Path: "/legacy", Method: "ANY", IntegrationType: apigatewaytypes.IntegrationTypeHttpProxy, IntegrationURI: "https://legacy.example.com"}
+	assert.Equal(t, "https://legacy.example.com", route.integrationURI())
+	assert.Equal(t, "ANY", *route.integrationHTTPMethod())
+
+	route = apiGatewayRoute{Path: "/health", Method: "GET", IntegrationType: apigatewaytypes.IntegrationTypeMock}
+	assert.Nil(t, route.integrationHTTPMethod())
+}
+
+func TestSyncAPIGatewayRoutes(t *testing.T) {
+	unchanged := apiGatewayRoute{Path: "/users", Method: "GET", IntegrationType: apigatewaytypes.IntegrationTypeAwsProxy, IntegrationURI: testFunctionARN}
+	client := &fakeAPIGateway{resources: []apigatewaytypes.Resource{
+		testResource("root", "/", nil),
+		testResource("users", "/users", map[string]*apigatewaytypes.Integration{
+			"GET":    {Type: apigatewaytypes.IntegrationTypeAwsProxy, Uri: aws.String(unchanged.integrationURI())},
+			"DELETE": {Type: apigatewaytypes.IntegrationTypeMock},
+			"POST":   {Type: apigatewaytypes.IntegrationTypeMock},
+		}),
+	}}
+
+	routes := []apiGatewayRoute{
+		unchanged,
+		{Path: "/users", Method: "POST", IntegrationType: apigatewaytypes.IntegrationTypeAwsProxy, IntegrationURI: testFunctionARN},
+		{Path: "/users/{id}/orders", Method: "GET", IntegrationType: apigatewaytypes.IntegrationTypeHttp, IntegrationURI: "https://orders.example.com"},
+	}
+
+	require.NoError(t, syncAPIGatewayRoutes(context.Background(), client, "api-1", routes))
+
+	paths := make(map[string]string)
+	for _, resource := range client.resources {
+		paths[*resource.Path] = *resource.Id
+	}
+	assert.Contains(t, paths, "/users/{id}")
+	assert.Contains(t, paths, "/users/{id}/orders")
+	assert.Len(t, client.resources, 4, "Existing resources should be reused")
+
+	require.Len(t, client.integrations, 2, "Only routes whose integration differs should be updated")
+	assert.Equal(t, "POST", *client.integrations[0].HttpMethod)
+	assert.Equal(t, paths["/users"], *client.integrations[0].ResourceId)
+	assert.Equal(t, apigatewaytypes.IntegrationTypeAwsProxy, client.integrations[0].Type)
+	assert.Equal(t, paths["/users/{id}/orders"], *client.integrations[1].ResourceId)
+	assert.Equal(t, "https://orders.example.com", *client.integrations[1].Uri)
+	assert.Equal(t, "GET", *client.integrations[1].IntegrationHttpMethod)
+
+	assert.Equal(t, []string{"DELETE users"}, client.deleted, "Methods no route declares should be deleted")
+}
+
+func TestGetAPIGatewayRoutes(t *testing.T) {
+	route := apiGatewayRoute{Path: "/users", Method: "GET", IntegrationType: apigatewaytypes.IntegrationTypeAwsProxy, IntegrationURI: testFunctionARN}
+	client := &fakeAPIGateway{resources: []apigatewaytypes.Resource{
+		testResource("root", "/", map[string]*apigatewaytypes.Integration{
+			"GET": {Type: apigatewaytypes.IntegrationTypeMock},
+		}),
+		testResource("users", "/users", map[string]*apigatewaytypes.Integration{
+			"GET": {Type: apigatewaytypes.IntegrationTypeAwsProxy, Uri: aws.String(route.integrationURI())},
+		}),
+		testResource("legacy", "/legacy", map[string]*apigatewaytypes.Integration{
+			"ANY": {Type: apigatewaytypes.IntegrationTypeHttpProxy, Uri: aws.String("https://legacy.example.com")},
+		}),
+	}}
+
+	deployed, err := getAPIGatewayRoutes(context.Background(), client, "api-1")
+	require.NoError(t, err)
+
+	desired := []apiGatewayRoute{route, {Path: "/", Method: "GET", IntegrationType: apigatewaytypes.IntegrationTypeMock}}
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"path": "/users", "method": "GET", "integration_type": "AWS_PROXY", "integration_uri": testFunctionARN},
+		map[string]interface{}{"path": "/", "method": "GET", "integration_type": "MOCK"},
+		map[string]interface{}{"path": "/legacy", "method": "ANY", "integration_type": "HTTP_PROXY", "integration_uri": "https://legacy.example.com"},
+	}, orderAPIGatewayRoutes(deployed, desired), "Routes should follow the configured order with undeclared routes last")
+}
+
+// fakeLambdaPermissions records the permissions added to functions
+type fakeLambdaPermissions struct {
+	added []*lambda.AddPermissionInput
+	err   error
+}
+
+func (f *fakeLambdaPermissions) AddPermission(ctx context.Context, params *lambda.AddPermissionInput, optFns ...func(*lambda.Options)) (*lambda.AddPermissionOutput, error) {
+	f.added = append(f.added, params)
+	return &lambda.AddPermissionOutput{}, f.err
+}
+
+func TestGrantAPIGatewayInvoke(t *testing.T) {
+	routes := []apiGatewayRoute{
+		{Path: "/users", Method: "GET", IntegrationType: apigatewaytypes.IntegrationTypeAwsProxy, IntegrationURI: testFunctionARN},
+		{Path: "/users", Method: "POST", IntegrationType: apigatewaytypes.IntegrationTypeAwsProxy, IntegrationURI: testFunctionARN},
+		{Path: "/health", Method: "GET", IntegrationType: apigatewaytypes.IntegrationTypeMock},
+	}
+
+	client := &fakeLambdaPermissions{}
+	require.NoError(t, grantAPIGatewayInvoke(context.Background(), client, "api-1", routes))
+	require.Len(t, client.added, 1, "Each function should be granted once")
+	assert.Equal(t, testFunctionARN, *client.added[0].FunctionName)
+	assert.Equal(t, "apigateway.amazonaws.com", *client.added[0].Principal)
+	assert.Equal(t, "arn:aws:execute-api:us-east-1:123456789012:api-1/*", *client.added[0].SourceArn)
+
+	client = &fakeLambdaPermissions{err: fmt.Errorf("ResourceConflictException: The statement id provided already exists")}
+	assert.NoError(t, grantAPIGatewayInvoke(context.Background(), client, "api-1", routes), "An existing statement should be kept")
+
+	client = &fakeLambdaPermissions{err: fmt.Errorf("AccessDeniedException")}
+	assert.Error(t, grantAPIGatewayInvoke(context.Background(), client, "api-1", routes))
+}
@@ -0,0 +1,69 @@
+package aws
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// defaultAssumeRoleSessionName identifies Runestone's sessions in CloudTrail when
+// session_name isn't set
+const defaultAssumeRoleSessionName = "runestone"
+
+// roleARNPattern matches IAM role ARNs
+var roleARNPattern = regexp.MustCompile(`^arn:aws[a-z-]*:iam::\d{12}:role/[\w+=,.@/-]+$`)
+
+// sessionNamePattern matches the role session names STS accepts
+var sessionNamePattern = regexp.MustCompile(`^[\w+=,.@-]{2,64}$`)
+
+// assumeRoleConfig is the role the provider assumes instead of using the loaded
+// credentials directly
+type assumeRoleConfig struct {
+	roleARN     string
+	externalID  string
+	sessionName string
+}
+
+// assumeRoleFromProviderConfig reads assume_role_arn, external_id and session_name. It
+// returns nil when no role is configured.
+func assumeRoleFromProviderConfig(providerConfig map[string]interface{}) (*assumeRoleConfig, error) {
+	roleARN, _ := providerConfig["assume_role_arn"].(string)
+	externalID, _ := providerConfig["external_id"].(string)
+	sessionName, _ := providerConfig["session_name"].(string)
+
+	if roleARN == "" {
+		if externalID != "" || sessionName != "" {
+			return nil, fmt.Errorf("external_id and session_name require assume_role_arn")
+		}
+		return nil, nil
+	}
+
+	if !roleARNPattern.MatchString(roleARN) {
+		return nil, fmt.Errorf("invalid assume_role_arn '%s': must be an IAM role ARN such as arn:aws:iam::123456789012:role/deployer", roleARN)
+	}
+	if sessionName == "" {
+		sessionName = defaultAssumeRoleSessionName
+	}
+	if !sessionNamePattern.MatchString(sessionName) {
+		return nil, fmt.Errorf("invalid session_name '%s': use 2 to 64 letters, digits and '+=,.@_-'", sessionName)
+	}
+
+	return &assumeRoleConfig{roleARN: roleARN, externalID: externalID, sessionName: sessionName}, nil
+}
+
+// withAssumedRole returns a copy of cfg whose credentials come from assuming the role with
+// cfg's original credentials. Credentials are fetched on first use and refreshed before
+// they expire, so no network calls are made here.
+func withAssumedRole(cfg aws.Config, role assumeRoleConfig) aws.Config {
+	assumed := cfg.Copy()
+	assumed.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), role.roleARN, func(options *stscreds.AssumeRoleOptions) {
+		options.RoleSessionName = role.sessionName
+		if role.externalID != "" {
+			options.ExternalID = aws.String(role.externalID)
+		}
+	}))
+	return assumed
+}
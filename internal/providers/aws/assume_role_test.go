@@ -0,0 +1,111 @@
+package aws
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssumeRoleFromProviderConfig(t *testing.T) {
+	tests := []struct {
+		name           string
+		providerConfig map[string]interface{}
+		expected       *assumeRoleConfig
+		wantErr        string
+	}{
+		{
+			name:           "no role",
+			providerConfig: map[string]interface{}{"region": "us-east-1"},
+		},
+		{
+			name: "role with defaults",
+			providerConfig: map[string]interface{}{
+				"assume_role_arn": "arn:aws:iam::123456789012:role/deployer",
+			},
+			expected: &assumeRoleConfig{roleARN: "arn:aws:iam::123456789012:role/deployer", sessionName: "runestone"},
+		},
+		{
+			name: "role with external ID and session name",
+			providerConfig: map[string]interface{}{
+				"assume_role_arn": "arn:aws:iam::123456789012:role/ci/deployer",
+				"external_id":     "shared-secret",
+				"session_name":    "ci-pipeline",
+			},
+			expected: &assumeRoleConfig{roleARN: "arn:aws:iam::123456789012:role/ci/deployer", externalID: "shared-secret", sessionName: "ci-pipeline"},
+		},
+		{
+			name:           "external ID without role",
+			providerConfig: map[string]interface{}{"external_id": "shared-secret"},
+			wantErr:        "external_id and session_name require assume_role_arn",
+		},
+		{
+			name:           "not a role ARN",
+			providerConfig: map[string]interface{}{"assume_role_arn": "arn:aws:iam::123456789012:user/deployer"},
+			wantErr:        "invalid assume_role_arn",
+		},
+		{
+			name: "invalid session name",
+			providerConfig: map[string]interface{}{
+				"assume_role_arn": "arn:aws:iam::123456789012:role/deployer",
+				"session_name":    "has spaces",
+			},
+			wantErr: "invalid session_name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			role, err := assumeRoleFromProviderConfig(tt.providerConfig)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, role)
+		})
+	}
+}
+
+func TestWithAssumedRole_ReplacesCredentials(t *testing.T) {
+	base := aws.Config{Region: "us-east-1", Credentials: aws.AnonymousCredentials{}}
+
+	assumed := withAssumedRole(base, assumeRoleConfig{roleARN: "arn:aws:iam::123456789012:role/deployer", sessionName: "runestone"})
+
+	assert.IsType(t, &aws.CredentialsCache{}, assumed.Credentials)
+	assert.Equal(t, "us-east-1", assumed.Region)
+	assert.IsType(t, aws.AnonymousCredentials{}, base.Credentials)
+}
+
+func TestProvider_Initialize_AssumeRole(t *testing.T) {
+	// Credentials are only fetched on first use, so initialization succeeds without them
+	provider := NewProvider()
+	err := provider.Initialize(context.Background(), map[string]interface{}{
+		"region":          "us-east-1",
+		"assume_role_arn": "arn:aws:iam::123456789012:role/deployer",
+		"external_id":     "shared-secret",
+	})
+	require.NoError(t, err)
+	assert.IsType(t, &aws.CredentialsCache{}, provider.awsConfig.Credentials)
+
+	// Assuming a real role needs credentials that are allowed to assume it
+	roleARN := os.Getenv("RUNESTONE_TEST_ASSUME_ROLE_ARN")
+	if roleARN == "" || testing.Short() {
+		t.Skip("set RUNESTONE_TEST_ASSUME_ROLE_ARN to test assuming a real role")
+	}
+
+	provider = NewProvider()
+	require.NoError(t, provider.Initialize(context.Background(), map[string]interface{}{
+		"region":          "us-east-1",
+		"assume_role_arn": roleARN,
+		"external_id":     os.Getenv("RUNESTONE_TEST_ASSUME_ROLE_EXTERNAL_ID"),
+	}))
+
+	accountID, err := provider.getAccountID(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, roleARN, ":"+accountID+":")
+}
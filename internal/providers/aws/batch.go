@@ -0,0 +1,184 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// maxFilterValues is the number of values EC2 accepts in a single describe filter
+const maxFilterValues = 200
+
+// batchStateKinds are the resource kinds GetCurrentStateBatch looks up in bulk
+var batchStateKinds = map[string]bool{
+	"aws:ec2:instance": true,
+	"aws:ec2:vpc":      true,
+}
+
+// SupportsBatchState reports whether GetCurrentStateBatch looks up a kind in bulk
+func (p *Provider) SupportsBatchState(kind string) bool {
+	return batchStateKinds[kind]
+}
+
+// GetCurrentStateBatch retrieves the state of many AWS resources. EC2 instances and VPCs are
+// found with one paginated describe call per kind, filtered by every Name they might have;
+// other kinds are looked up one at a time with GetCurrentState.
+func (p *Provider) GetCurrentStateBatch(ctx context.Context, instances []config.ResourceInstance) (map[string]map[string]interface{}, error) {
+	byKind := make(map[string][]config.ResourceInstance)
+	for _, instance := range instances {
+		byKind[instance.Kind] = append(byKind[instance.Kind], instance)
+	}
+
+	states := make(map[string]map[string]interface{}, len(instances))
+	for _, instance := range instances {
+		if batchStateKinds[instance.Kind] {
+			continue
+		}
+		state, err := p.GetCurrentState(ctx, instance)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current state for resource %s: %w", instance.ID, err)
+		}
+		if state != nil {
+			states[instance.ID] = state
+		}
+	}
+
+	batched := make(map[string]map[string]interface{})
+	if group := byKind["aws:ec2:instance"]; len(group) > 0 {
+		found, err := getEC2InstanceStates(ctx, p.ec2Client, group)
+		if err != nil {
+			return nil, err
+		}
+		for id, state := range found {
+			batched[id] = state
+		}
+	}
+	if group := byKind["aws:ec2:vpc"]; len(group) > 0 {
+		found, err := getVPCStates(ctx, p.ec2Client, group)
+		if err != nil {
+			return nil, err
+		}
+		for id, state := range found {
+			batched[id] = state
+		}
+	}
+
+	for _, instance := range instances {
+		if state, exists := batched[instance.ID]; exists {
+			states[instance.ID] = p.stripDefaultTags(instance, state)
+		}
+	}
+
+	return states, nil
+}
+
+// getEC2InstanceStates looks up EC2 instance resources with as few DescribeInstances calls as
+// the filter value limit allows, matching each to an instance the same way
+// getEC2InstanceState does
+func getEC2InstanceStates(ctx context.Context, client ec2.DescribeInstancesAPIClient, instances []config.ResourceInstance) (map[string]map[string]interface{}, error) {
+	byName := make(map[string][]types.Instance)
+	for _, names := range chunkNames(instances) {
+		paginator := ec2.NewDescribeInstancesPaginator(client, &ec2.DescribeInstancesInput{
+			Filters: []types.Filter{
+				{
+					Name:   aws.String("tag:Name"),
+					Values: names,
+				},
+				{
+					Name:   aws.String("instance-state-name"),
+					Values: []string{"running", "pending", "stopping", "stopped"},
+				},
+			},
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to describe EC2 instances: %w", err)
+			}
+			for _, reservation := range page.Reservations {
+				for _, inst := range reservation.Instances {
+					name, _ := ec2TagValue(inst.Tags, "Name")
+					byName[name] = append(byName[name], inst)
+				}
+			}
+		}
+	}
+
+	states := make(map[string]map[string]interface{})
+	for _, instance := range instances {
+		found := byName[instance.Name]
+		candidates := make([][]types.Tag, len(found))
+		for i, inst := range found {
+			candidates[i] = inst.Tags
+		}
+		if match := matchResourceID(instance, candidates); match >= 0 {
+			states[instance.ID] = ec2InstanceState(instance, found[match])
+		}
+	}
+	return states, nil
+}
+
+// getVPCStates looks up VPC resources with as few DescribeVpcs calls as the filter value
+// limit allows, matching each to a VPC the same way getVPCState does
+func getVPCStates(ctx context.Context, client ec2.DescribeVpcsAPIClient, instances []config.ResourceInstance) (map[string]map[string]interface{}, error) {
+	byName := make(map[string][]types.Vpc)
+	for _, names := range chunkNames(instances) {
+		paginator := ec2.NewDescribeVpcsPaginator(client, &ec2.DescribeVpcsInput{
+			Filters: []types.Filter{
+				{
+					Name:   aws.String("tag:Name"),
+					Values: names,
+				},
+			},
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to describe VPCs: %w", err)
+			}
+			for _, vpc := range page.Vpcs {
+				name, _ := ec2TagValue(vpc.Tags, "Name")
+				byName[name] = append(byName[name], vpc)
+			}
+		}
+	}
+
+	states := make(map[string]map[string]interface{})
+	for _, instance := range instances {
+		found := byName[instance.Name]
+		candidates := make([][]types.Tag, len(found))
+		for i, vpc := range found {
+			candidates[i] = vpc.Tags
+		}
+		if match := matchResourceID(instance, candidates); match >= 0 {
+			states[instance.ID] = vpcState(found[match])
+		}
+	}
+	return states, nil
+}
+
+// chunkNames returns the distinct names of instances in groups small enough for one filter
+func chunkNames(instances []config.ResourceInstance) [][]string {
+	seen := make(map[string]bool)
+	var chunks [][]string
+	var current []string
+	for _, instance := range instances {
+		if seen[instance.Name] {
+			continue
+		}
+		seen[instance.Name] = true
+		current = append(current, instance.Name)
+		if len(current) == maxFilterValues {
+			chunks = append(chunks, current)
+			current = nil
+		}
+	}
+	if len(current) > 0 {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
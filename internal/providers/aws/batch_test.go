@@ -0,0 +1,139 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEC2Batch serves described instances and VPCs one per page and records each call
+type fakeEC2Batch struct {
+	instances     []types.Instance
+	vpcs          []types.Vpc
+	instanceCalls int
+	vpcCalls      int
+}
+
+func (f *fakeEC2Batch) DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	f.instanceCalls++
+	page := 0
+	if params.NextToken != nil {
+		fmt.Sscanf(*params.NextToken, "%d", &page)
+	}
+	output := &ec2.DescribeInstancesOutput{}
+	if page < len(f.instances) {
+		output.Reservations = []types.Reservation{{Instances: []types.Instance{f.instances[page]}}}
+	}
+	if page+1 < len(f.instances) {
+		output.NextToken = aws.String(fmt.Sprint(page + 1))
+	}
+	return output, nil
+}
+
+func (f *fakeEC2Batch) DescribeVpcs(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error) {
+	f.vpcCalls++
+	return &ec2.DescribeVpcsOutput{Vpcs: f.vpcs}, nil
+}
+
+// batchTestTags returns EC2 tags with the given Name and, if set, resource ID
+func batchTestTags(name, resourceID string) []types.Tag {
+	tags := []types.Tag{{Key: aws.String("Name"), Value: aws.String(name)}}
+	if resourceID != "" {
+		tags = append(tags, types.Tag{Key: aws.String(resourceIDTagKey), Value: aws.String(resourceID)})
+	}
+	return tags
+}
+
+func TestGetEC2InstanceStates(t *testing.T) {
+	client := &fakeEC2Batch{instances: []types.Instance{
+		{
+			InstanceId:   aws.String("i-1"),
+			InstanceType: types.InstanceTypeT3Micro,
+			ImageId:      aws.String("ami-1"),
+			State:        &types.InstanceState{Name: types.InstanceStateNameRunning},
+			Tags:         batchTestTags("web", "aws:ec2:instance.web"),
+		},
+		{
+			InstanceId:   aws.String("i-2"),
+			InstanceType: types.InstanceTypeT3Small,
+			ImageId:      aws.String("ami-2"),
+			State:        &types.InstanceState{Name: types.InstanceStateNameStopped},
+			Tags:         batchTestTags("worker", ""),
+		},
+		{
+			InstanceId:   aws.String("i-3"),
+			InstanceType: types.InstanceTypeT3Micro,
+			ImageId:      aws.String("ami-1"),
+			State:        &types.InstanceState{Name: types.InstanceStateNameRunning},
+			Tags:         batchTestTags("worker", "aws:ec2:instance.other-worker"),
+		},
+	}}
+
+	instances := []config.ResourceInstance{
+		{ID: "aws:ec2:instance.web", Kind: "aws:ec2:instance", Name: "web"},
+		{ID: "aws:ec2:instance.worker", Kind: "aws:ec2:instance", Name: "worker", Properties: map[string]interface{}{"desired_state": "stopped"}},
+		{ID: "aws:ec2:instance.missing", Kind: "aws:ec2:instance", Name: "missing"},
+	}
+
+	states, err := getEC2InstanceStates(context.Background(), client, instances)
+	require.NoError(t, err)
+	require.Len(t, states, 2)
+
+	assert.Equal(t, "i-1", states["aws:ec2:instance.web"]["instance_id"])
+	assert.Equal(t, "i-2", states["aws:ec2:instance.worker"]["instance_id"], "An instance created for another resource should not match")
+	assert.Equal(t, "stopped", states["aws:ec2:instance.worker"]["desired_state"])
+	assert.NotContains(t, states, "aws:ec2:instance.missing")
+
+	// One describe call per page rather than per resource
+	assert.Equal(t, 3, client.instanceCalls)
+}
+
+func TestGetVPCStates(t *testing.T) {
+	client := &fakeEC2Batch{vpcs: []types.Vpc{
+		{VpcId: aws.String("vpc-1"), CidrBlock: aws.String("10.0.0.0/16"), State: types.VpcStateAvailable, Tags: batchTestTags("main", "")},
+		{VpcId: aws.String("vpc-2"), CidrBlock: aws.String("10.1.0.0/16"), State: types.VpcStateAvailable, Tags: batchTestTags("edge", "")},
+	}}
+
+	instances := []config.ResourceInstance{
+		{ID: "aws:ec2:vpc.main", Kind: "aws:ec2:vpc", Name: "main"},
+		{ID: "aws:ec2:vpc.edge", Kind: "aws:ec2:vpc", Name: "edge"},
+		{ID: "aws:ec2:vpc.missing", Kind: "aws:ec2:vpc", Name: "missing"},
+	}
+
+	states, err := getVPCStates(context.Background(), client, instances)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]map[string]interface{}{
+		"aws:ec2:vpc.main": {"vpc_id": "vpc-1", "cidr_block": "10.0.0.0/16", "state": "available", "tags": map[string]interface{}{"Name": "main"}},
+		"aws:ec2:vpc.edge": {"vpc_id": "vpc-2", "cidr_block": "10.1.0.0/16", "state": "available", "tags": map[string]interface{}{"Name": "edge"}},
+	}, states)
+	assert.Equal(t, 1, client.vpcCalls)
+}
+
+func TestChunkNames(t *testing.T) {
+	var instances []config.ResourceInstance
+	for i := 0; i < 450; i++ {
+		instances = append(instances, config.ResourceInstance{Name: fmt.Sprintf("web-%d", i)})
+	}
+	instances = append(instances, config.ResourceInstance{Name: "web-0"})
+
+	chunks := chunkNames(instances)
+	require.Len(t, chunks, 3)
+	assert.Len(t, chunks[0], maxFilterValues)
+	assert.Len(t, chunks[1], maxFilterValues)
+	assert.Len(t, chunks[2], 50, "Duplicate names should be sent once")
+	assert.Equal(t, "web-449", chunks[2][49])
+}
+
+func TestProvider_SupportsBatchState(t *testing.T) {
+	provider := NewProvider()
+	assert.True(t, provider.SupportsBatchState("aws:ec2:instance"))
+	assert.True(t, provider.SupportsBatchState("aws:ec2:vpc"))
+	assert.False(t, provider.SupportsBatchState("aws:s3:bucket"))
+}
@@ -2,7 +2,11 @@ package aws
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"sort"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
@@ -10,6 +14,31 @@ import (
 	"github.com/ataiva-software/runestone/internal/config"
 )
 
+const (
+	// defaultDynamoDBWaitTimeout bounds how long index changes may take to become active
+	defaultDynamoDBWaitTimeout = 30 * time.Minute
+)
+
+// dynamoDBPollInterval is the delay between status checks while waiting on a table
+var dynamoDBPollInterval = 5 * time.Second
+
+// dynamoDBTableAPI is the subset of the DynamoDB API used to update a table
+type dynamoDBTableAPI interface {
+	DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)
+	UpdateTable(ctx context.Context, params *dynamodb.UpdateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateTableOutput, error)
+}
+
+// dynamoDBIndex describes a global secondary index declared on a table
+type dynamoDBIndex struct {
+	name             string
+	hashKey          string
+	rangeKey         string
+	projectionType   string
+	nonKeyAttributes []string
+	readCapacity     int
+	writeCapacity    int
+}
+
 func (p *Provider) validateDynamoDBTable(instance config.ResourceInstance) error {
 	if instance.Name == "" {
 		return fmt.Errorf("DynamoDB table name cannot be empty")
@@ -19,6 +48,53 @@ func (p *Provider) validateDynamoDBTable(instance config.ResourceInstance) error
 		return fmt.Errorf("hash_key is required for DynamoDB table")
 	}
 
+	billingMode, err := dynamoDBBillingMode(instance)
+	if err != nil {
+		return err
+	}
+
+	if billingMode == types.BillingModeProvisioned {
+		if _, ok := instance.Properties["read_capacity"].(int); !ok {
+			return fmt.Errorf("read_capacity is required when billing_mode is PROVISIONED")
+		}
+		if _, ok := instance.Properties["write_capacity"].(int); !ok {
+			return fmt.Errorf("write_capacity is required when billing_mode is PROVISIONED")
+		}
+	}
+
+	attributes, err := dynamoDBAttributes(instance)
+	if err != nil {
+		return err
+	}
+	defined := make(map[string]bool)
+	for _, attr := range attributes {
+		defined[aws.ToString(attr.AttributeName)] = true
+	}
+
+	indexes, err := parseDynamoDBIndexes(instance)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	for _, index := range indexes {
+		if seen[index.name] {
+			return fmt.Errorf("duplicate global secondary index '%s'", index.name)
+		}
+		seen[index.name] = true
+
+		// Index keys must be declared as attributes so their types are known
+		for _, key := range []string{index.hashKey, index.rangeKey} {
+			if key != "" && !defined[key] {
+				return fmt.Errorf("global secondary index '%s' uses key '%s' which is not declared in attributes", index.name, key)
+			}
+		}
+
+		if billingMode == types.BillingModeProvisioned && (index.readCapacity == 0 || index.writeCapacity == 0) {
+			return fmt.Errorf("global secondary index '%s' requires read_capacity and write_capacity when billing_mode is PROVISIONED", index.name)
+		}
+	}
+
 	return nil
 }
 
@@ -38,11 +114,76 @@ func (p *Provider) getDynamoDBTableState(ctx context.Context, instance config.Re
 	}
 
 	table := result.Table
-	return map[string]interface{}{
+	state := map[string]interface{}{
 		"table_name":   *table.TableName,
 		"table_status": string(table.TableStatus),
 		"table_arn":    *table.TableArn,
-	}, nil
+		"billing_mode": string(types.BillingModeProvisioned),
+	}
+
+	if table.BillingModeSummary != nil && table.BillingModeSummary.BillingMode != "" {
+		state["billing_mode"] = string(table.BillingModeSummary.BillingMode)
+	}
+
+	if state["billing_mode"] == string(types.BillingModeProvisioned) && table.ProvisionedThroughput != nil {
+		state["read_capacity"] = int(aws.ToInt64(table.ProvisionedThroughput.ReadCapacityUnits))
+		state["write_capacity"] = int(aws.ToInt64(table.ProvisionedThroughput.WriteCapacityUnits))
+	}
+
+	for _, key := range table.KeySchema {
+		switch key.KeyType {
+		case types.KeyTypeHash:
+			state["hash_key"] = aws.ToString(key.AttributeName)
+		case types.KeyTypeRange:
+			state["range_key"] = aws.ToString(key.AttributeName)
+		}
+	}
+
+	if _, declared := instance.Properties["attributes"]; declared {
+		attributes := make([]map[string]interface{}, 0, len(table.AttributeDefinitions))
+		for _, attr := range table.AttributeDefinitions {
+			attributes = append(attributes, map[string]interface{}{
+				"name": aws.ToString(attr.AttributeName),
+				"type": string(attr.AttributeType),
+			})
+		}
+		state["attributes"] = alignNamedEntries(attributes, instance.Properties["attributes"])
+	}
+
+	if len(table.GlobalSecondaryIndexes) > 0 {
+		indexes := make([]map[string]interface{}, 0, len(table.GlobalSecondaryIndexes))
+		for _, gsi := range table.GlobalSecondaryIndexes {
+			index := map[string]interface{}{
+				"name": aws.ToString(gsi.IndexName),
+			}
+			for _, key := range gsi.KeySchema {
+				switch key.KeyType {
+				case types.KeyTypeHash:
+					index["hash_key"] = aws.ToString(key.AttributeName)
+				case types.KeyTypeRange:
+					index["range_key"] = aws.ToString(key.AttributeName)
+				}
+			}
+			if gsi.Projection != nil {
+				index["projection_type"] = string(gsi.Projection.ProjectionType)
+				if len(gsi.Projection.NonKeyAttributes) > 0 {
+					nonKeyAttributes := make([]interface{}, 0, len(gsi.Projection.NonKeyAttributes))
+					for _, attr := range gsi.Projection.NonKeyAttributes {
+						nonKeyAttributes = append(nonKeyAttributes, attr)
+					}
+					index["non_key_attributes"] = nonKeyAttributes
+				}
+			}
+			if gsi.ProvisionedThroughput != nil && aws.ToInt64(gsi.ProvisionedThroughput.ReadCapacityUnits) > 0 {
+				index["read_capacity"] = int(aws.ToInt64(gsi.ProvisionedThroughput.ReadCapacityUnits))
+				index["write_capacity"] = int(aws.ToInt64(gsi.ProvisionedThroughput.WriteCapacityUnits))
+			}
+			indexes = append(indexes, index)
+		}
+		state["global_secondary_indexes"] = alignNamedEntries(indexes, instance.Properties["global_secondary_indexes"])
+	}
+
+	return state, nil
 }
 
 func (p *Provider) createDynamoDBTable(ctx context.Context, instance config.ResourceInstance) error {
@@ -50,29 +191,19 @@ func (p *Provider) createDynamoDBTable(ctx context.Context, instance config.Reso
 
 	hashKey := instance.Properties["hash_key"].(string)
 
-	// Default attribute definition
-	attributes := []types.AttributeDefinition{
-		{
-			AttributeName: aws.String(hashKey),
-			AttributeType: types.ScalarAttributeTypeS,
-		},
+	attributes, err := dynamoDBAttributes(instance)
+	if err != nil {
+		return err
 	}
 
-	// Override with custom attributes if provided
-	if attrsVal, exists := instance.Properties["attributes"]; exists {
-		if attrsList, ok := attrsVal.([]interface{}); ok {
-			attributes = make([]types.AttributeDefinition, 0, len(attrsList))
-			for _, attr := range attrsList {
-				if attrMap, ok := attr.(map[string]interface{}); ok {
-					name := attrMap["name"].(string)
-					attrType := attrMap["type"].(string)
-					attributes = append(attributes, types.AttributeDefinition{
-						AttributeName: aws.String(name),
-						AttributeType: types.ScalarAttributeType(attrType),
-					})
-				}
-			}
-		}
+	billingMode, err := dynamoDBBillingMode(instance)
+	if err != nil {
+		return err
+	}
+
+	indexes, err := parseDynamoDBIndexes(instance)
+	if err != nil {
+		return err
 	}
 
 	keySchema := []types.KeySchemaElement{
@@ -96,10 +227,18 @@ func (p *Provider) createDynamoDBTable(ctx context.Context, instance config.Reso
 		TableName:            aws.String(instance.Name),
 		AttributeDefinitions: attributes,
 		KeySchema:            keySchema,
-		BillingMode:          types.BillingModePayPerRequest,
+		BillingMode:          billingMode,
 	}
 
-	_, err := client.CreateTable(ctx, input)
+	if billingMode == types.BillingModeProvisioned {
+		input.ProvisionedThroughput = dynamoDBThroughput(instance.Properties["read_capacity"], instance.Properties["write_capacity"])
+	}
+
+	for _, index := range indexes {
+		input.GlobalSecondaryIndexes = append(input.GlobalSecondaryIndexes, index.toGlobalSecondaryIndex(billingMode))
+	}
+
+	_, err = client.CreateTable(ctx, input)
 	if err != nil {
 		return fmt.Errorf("failed to create DynamoDB table %s: %w", instance.Name, err)
 	}
@@ -108,10 +247,190 @@ func (p *Provider) createDynamoDBTable(ctx context.Context, instance config.Reso
 }
 
 func (p *Provider) updateDynamoDBTable(ctx context.Context, instance config.ResourceInstance) error {
-	// DynamoDB tables have limited update capabilities
+	return p.updateDynamoDBTableWith(ctx, dynamodb.NewFromConfig(p.awsConfig), instance)
+}
+
+// updateDynamoDBTableWith brings a table's billing mode, capacity and global secondary
+// indexes in line with the configuration. Indexes whose keys or projection changed can't
+// be modified, so they are deleted and created again.
+func (p *Provider) updateDynamoDBTableWith(ctx context.Context, client dynamoDBTableAPI, instance config.ResourceInstance) error {
+	attributes, err := dynamoDBAttributes(instance)
+	if err != nil {
+		return err
+	}
+
+	billingMode, err := dynamoDBBillingMode(instance)
+	if err != nil {
+		return err
+	}
+
+	desiredIndexes, err := parseDynamoDBIndexes(instance)
+	if err != nil {
+		return err
+	}
+
+	result, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(instance.Name),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe DynamoDB table %s: %w", instance.Name, err)
+	}
+	table := result.Table
+
+	existing := make(map[string]types.GlobalSecondaryIndexDescription)
+	for _, gsi := range table.GlobalSecondaryIndexes {
+		existing[aws.ToString(gsi.IndexName)] = gsi
+	}
+
+	// Indexes that are no longer declared, or whose definition changed, are deleted first
+	// so the billing mode and capacity updates only need to cover the indexes that remain
+	kept := make(map[string]dynamoDBIndex)
+	for _, index := range desiredIndexes {
+		if gsi, exists := existing[index.name]; exists && index.sameDefinition(gsi) {
+			kept[index.name] = index
+		}
+	}
+	var removed []string
+	for name := range existing {
+		if _, keep := kept[name]; !keep {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+
+	// UpdateTable accepts a single index creation or deletion per call, and the
+	// table must be active again before the next one is submitted
+	for _, name := range removed {
+		input := &dynamodb.UpdateTableInput{
+			TableName: aws.String(instance.Name),
+			GlobalSecondaryIndexUpdates: []types.GlobalSecondaryIndexUpdate{
+				{Delete: &types.DeleteGlobalSecondaryIndexAction{IndexName: aws.String(name)}},
+			},
+		}
+		if err := p.applyDynamoDBTableUpdate(ctx, client, instance.Name, input); err != nil {
+			return fmt.Errorf("failed to delete index %s from DynamoDB table %s: %w", name, instance.Name, err)
+		}
+	}
+
+	var keptNames []string
+	for name := range kept {
+		keptNames = append(keptNames, name)
+	}
+	sort.Strings(keptNames)
+
+	currentBillingMode := types.BillingModeProvisioned
+	if table.BillingModeSummary != nil && table.BillingModeSummary.BillingMode != "" {
+		currentBillingMode = table.BillingModeSummary.BillingMode
+	}
+
+	// Key schema cannot change after creation, but billing mode and capacity can. Switching
+	// to provisioned capacity has to set the capacity of every index at the same time.
+	if billingMode != currentBillingMode {
+		input := &dynamodb.UpdateTableInput{
+			TableName:   aws.String(instance.Name),
+			BillingMode: billingMode,
+		}
+		if billingMode == types.BillingModeProvisioned {
+			input.ProvisionedThroughput = dynamoDBThroughput(instance.Properties["read_capacity"], instance.Properties["write_capacity"])
+			for _, name := range keptNames {
+				input.GlobalSecondaryIndexUpdates = append(input.GlobalSecondaryIndexUpdates, kept[name].throughputUpdate())
+			}
+		}
+		if err := p.applyDynamoDBTableUpdate(ctx, client, instance.Name, input); err != nil {
+			return fmt.Errorf("failed to update billing mode for DynamoDB table %s: %w", instance.Name, err)
+		}
+	} else if billingMode == types.BillingModeProvisioned {
+		throughput := dynamoDBThroughput(instance.Properties["read_capacity"], instance.Properties["write_capacity"])
+		if !sameThroughput(throughput, table.ProvisionedThroughput) {
+			input := &dynamodb.UpdateTableInput{
+				TableName:             aws.String(instance.Name),
+				ProvisionedThroughput: throughput,
+			}
+			if err := p.applyDynamoDBTableUpdate(ctx, client, instance.Name, input); err != nil {
+				return fmt.Errorf("failed to update capacity of DynamoDB table %s: %w", instance.Name, err)
+			}
+		}
+
+		for _, name := range keptNames {
+			index := kept[name]
+			if sameThroughput(dynamoDBThroughput(index.readCapacity, index.writeCapacity), existing[name].ProvisionedThroughput) {
+				continue
+			}
+			input := &dynamodb.UpdateTableInput{
+				TableName:                   aws.String(instance.Name),
+				GlobalSecondaryIndexUpdates: []types.GlobalSecondaryIndexUpdate{index.throughputUpdate()},
+			}
+			if err := p.applyDynamoDBTableUpdate(ctx, client, instance.Name, input); err != nil {
+				return fmt.Errorf("failed to update capacity of index %s on DynamoDB table %s: %w", name, instance.Name, err)
+			}
+		}
+	}
+
+	for _, index := range desiredIndexes {
+		if _, exists := kept[index.name]; exists {
+			continue
+		}
+
+		gsi := index.toGlobalSecondaryIndex(billingMode)
+		input := &dynamodb.UpdateTableInput{
+			TableName:            aws.String(instance.Name),
+			AttributeDefinitions: attributes,
+			GlobalSecondaryIndexUpdates: []types.GlobalSecondaryIndexUpdate{
+				{Create: &types.CreateGlobalSecondaryIndexAction{
+					IndexName:             gsi.IndexName,
+					KeySchema:             gsi.KeySchema,
+					Projection:            gsi.Projection,
+					ProvisionedThroughput: gsi.ProvisionedThroughput,
+				}},
+			},
+		}
+		if err := p.applyDynamoDBTableUpdate(ctx, client, instance.Name, input); err != nil {
+			return fmt.Errorf("failed to create index %s on DynamoDB table %s: %w", index.name, instance.Name, err)
+		}
+	}
+
 	return nil
 }
 
+// applyDynamoDBTableUpdate submits a table update and waits for the table and its indexes to settle
+func (p *Provider) applyDynamoDBTableUpdate(ctx context.Context, client dynamoDBTableAPI, tableName string, input *dynamodb.UpdateTableInput) error {
+	if _, err := client.UpdateTable(ctx, input); err != nil {
+		return err
+	}
+	return p.waitForDynamoDBTableActive(ctx, client, tableName)
+}
+
+// waitForDynamoDBTableActive polls until the table and all of its indexes are active
+func (p *Provider) waitForDynamoDBTableActive(ctx context.Context, client dynamoDBTableAPI, tableName string) error {
+	poll := func(ctx context.Context) (*dynamodb.DescribeTableOutput, error) {
+		result, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+			TableName: aws.String(tableName),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe DynamoDB table %s while waiting: %w", tableName, err)
+		}
+		return result, nil
+	}
+	isReady := func(result *dynamodb.DescribeTableOutput) (bool, error) {
+		if result.Table.TableStatus != types.TableStatusActive {
+			return false, nil
+		}
+		for _, gsi := range result.Table.GlobalSecondaryIndexes {
+			if gsi.IndexStatus != types.IndexStatusActive {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+
+	timeout := p.defaultWaitTimeoutOr(defaultDynamoDBWaitTimeout)
+	err := waitForState(ctx, poll, isReady, timeout, dynamoDBPollInterval)
+	if errors.Is(err, errWaitTimeout) {
+		return fmt.Errorf("timed out after %v waiting for DynamoDB table %s to become active", timeout, tableName)
+	}
+	return err
+}
+
 func (p *Provider) deleteDynamoDBTable(ctx context.Context, instance config.ResourceInstance) error {
 	client := dynamodb.NewFromConfig(p.awsConfig)
 
@@ -127,3 +446,260 @@ func (p *Provider) deleteDynamoDBTable(ctx context.Context, instance config.Reso
 
 	return nil
 }
+
+// dynamoDBAttributes builds the attribute definitions, defaulting to a string hash key
+func dynamoDBAttributes(instance config.ResourceInstance) ([]types.AttributeDefinition, error) {
+	attrsVal, exists := instance.Properties["attributes"]
+	if !exists {
+		hashKey, _ := instance.Properties["hash_key"].(string)
+		return []types.AttributeDefinition{
+			{
+				AttributeName: aws.String(hashKey),
+				AttributeType: types.ScalarAttributeTypeS,
+			},
+		}, nil
+	}
+
+	attrsList, ok := attrsVal.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("attributes must be a list")
+	}
+
+	attributes := make([]types.AttributeDefinition, 0, len(attrsList))
+	for _, attr := range attrsList {
+		attrMap, ok := attr.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("each attribute must have a name and type")
+		}
+		name, _ := attrMap["name"].(string)
+		attrType, _ := attrMap["type"].(string)
+		if name == "" || attrType == "" {
+			return nil, fmt.Errorf("each attribute must have a name and type")
+		}
+		switch types.ScalarAttributeType(attrType) {
+		case types.ScalarAttributeTypeS, types.ScalarAttributeTypeN, types.ScalarAttributeTypeB:
+		default:
+			return nil, fmt.Errorf("invalid type '%s' for attribute '%s': must be S, N or B", attrType, name)
+		}
+		attributes = append(attributes, types.AttributeDefinition{
+			AttributeName: aws.String(name),
+			AttributeType: types.ScalarAttributeType(attrType),
+		})
+	}
+
+	return attributes, nil
+}
+
+// dynamoDBBillingMode returns the billing_mode property, defaulting to on-demand
+func dynamoDBBillingMode(instance config.ResourceInstance) (types.BillingMode, error) {
+	modeVal, exists := instance.Properties["billing_mode"]
+	if !exists {
+		return types.BillingModePayPerRequest, nil
+	}
+
+	mode, _ := modeVal.(string)
+	switch types.BillingMode(mode) {
+	case types.BillingModePayPerRequest, types.BillingModeProvisioned:
+		return types.BillingMode(mode), nil
+	default:
+		return "", fmt.Errorf("invalid billing_mode '%v': must be PAY_PER_REQUEST or PROVISIONED", modeVal)
+	}
+}
+
+// parseDynamoDBIndexes reads the global_secondary_indexes property
+func parseDynamoDBIndexes(instance config.ResourceInstance) ([]dynamoDBIndex, error) {
+	indexesVal, exists := instance.Properties["global_secondary_indexes"]
+	if !exists {
+		return nil, nil
+	}
+
+	indexesList, ok := indexesVal.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("global_secondary_indexes must be a list")
+	}
+
+	indexes := make([]dynamoDBIndex, 0, len(indexesList))
+	for i, indexVal := range indexesList {
+		indexMap, ok := indexVal.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("global secondary index %d must be a map", i)
+		}
+
+		index := dynamoDBIndex{
+			projectionType: string(types.ProjectionTypeAll),
+		}
+		index.name, _ = indexMap["name"].(string)
+		index.hashKey, _ = indexMap["hash_key"].(string)
+		index.rangeKey, _ = indexMap["range_key"].(string)
+		index.readCapacity, _ = indexMap["read_capacity"].(int)
+		index.writeCapacity, _ = indexMap["write_capacity"].(int)
+
+		if index.name == "" {
+			return nil, fmt.Errorf("global secondary index %d requires a name", i)
+		}
+		if index.hashKey == "" {
+			return nil, fmt.Errorf("global secondary index '%s' requires a hash_key", index.name)
+		}
+
+		if projection, ok := indexMap["projection_type"].(string); ok {
+			switch types.ProjectionType(projection) {
+			case types.ProjectionTypeAll, types.ProjectionTypeKeysOnly, types.ProjectionTypeInclude:
+				index.projectionType = projection
+			default:
+				return nil, fmt.Errorf("invalid projection_type '%s' for index '%s': must be ALL, KEYS_ONLY or INCLUDE", projection, index.name)
+			}
+		}
+
+		if attrs, ok := indexMap["non_key_attributes"].([]interface{}); ok {
+			for _, attr := range attrs {
+				index.nonKeyAttributes = append(index.nonKeyAttributes, fmt.Sprintf("%v", attr))
+			}
+		}
+		if index.projectionType == string(types.ProjectionTypeInclude) && len(index.nonKeyAttributes) == 0 {
+			return nil, fmt.Errorf("index '%s' uses INCLUDE projection but has no non_key_attributes", index.name)
+		}
+
+		indexes = append(indexes, index)
+	}
+
+	return indexes, nil
+}
+
+// toGlobalSecondaryIndex converts the index into its API representation
+func (i dynamoDBIndex) toGlobalSecondaryIndex(billingMode types.BillingMode) types.GlobalSecondaryIndex {
+	keySchema := []types.KeySchemaElement{
+		{
+			AttributeName: aws.String(i.hashKey),
+			KeyType:       types.KeyTypeHash,
+		},
+	}
+	if i.rangeKey != "" {
+		keySchema = append(keySchema, types.KeySchemaElement{
+			AttributeName: aws.String(i.rangeKey),
+			KeyType:       types.KeyTypeRange,
+		})
+	}
+
+	gsi := types.GlobalSecondaryIndex{
+		IndexName: aws.String(i.name),
+		KeySchema: keySchema,
+		Projection: &types.Projection{
+			ProjectionType: types.ProjectionType(i.projectionType),
+		},
+	}
+	if len(i.nonKeyAttributes) > 0 {
+		gsi.Projection.NonKeyAttributes = i.nonKeyAttributes
+	}
+	if billingMode == types.BillingModeProvisioned {
+		gsi.ProvisionedThroughput = dynamoDBThroughput(i.readCapacity, i.writeCapacity)
+	}
+
+	return gsi
+}
+
+// sameDefinition reports whether an existing index has the keys and projection declared
+// for the index, which can't be changed without recreating it
+func (i dynamoDBIndex) sameDefinition(gsi types.GlobalSecondaryIndexDescription) bool {
+	desired := i.toGlobalSecondaryIndex(types.BillingModePayPerRequest)
+	if len(desired.KeySchema) != len(gsi.KeySchema) {
+		return false
+	}
+	for j, key := range desired.KeySchema {
+		if aws.ToString(key.AttributeName) != aws.ToString(gsi.KeySchema[j].AttributeName) || key.KeyType != gsi.KeySchema[j].KeyType {
+			return false
+		}
+	}
+
+	if gsi.Projection == nil {
+		return false
+	}
+	if desired.Projection.ProjectionType != gsi.Projection.ProjectionType {
+		return false
+	}
+	desiredAttributes := append([]string(nil), desired.Projection.NonKeyAttributes...)
+	currentAttributes := append([]string(nil), gsi.Projection.NonKeyAttributes...)
+	sort.Strings(desiredAttributes)
+	sort.Strings(currentAttributes)
+	return slices.Equal(desiredAttributes, currentAttributes)
+}
+
+// throughputUpdate returns the update setting the index's provisioned capacity
+func (i dynamoDBIndex) throughputUpdate() types.GlobalSecondaryIndexUpdate {
+	return types.GlobalSecondaryIndexUpdate{
+		Update: &types.UpdateGlobalSecondaryIndexAction{
+			IndexName:             aws.String(i.name),
+			ProvisionedThroughput: dynamoDBThroughput(i.readCapacity, i.writeCapacity),
+		},
+	}
+}
+
+// dynamoDBThroughput builds provisioned throughput from capacity values
+func dynamoDBThroughput(read, write interface{}) *types.ProvisionedThroughput {
+	readCapacity, _ := read.(int)
+	writeCapacity, _ := write.(int)
+	return &types.ProvisionedThroughput{
+		ReadCapacityUnits:  aws.Int64(int64(readCapacity)),
+		WriteCapacityUnits: aws.Int64(int64(writeCapacity)),
+	}
+}
+
+// sameThroughput reports whether the current capacity of a table or index matches the
+// desired capacity
+func sameThroughput(desired *types.ProvisionedThroughput, current *types.ProvisionedThroughputDescription) bool {
+	if current == nil {
+		return false
+	}
+	return aws.ToInt64(desired.ReadCapacityUnits) == aws.ToInt64(current.ReadCapacityUnits) &&
+		aws.ToInt64(desired.WriteCapacityUnits) == aws.ToInt64(current.WriteCapacityUnits)
+}
+
+// alignNamedEntries orders entries identified by "name" to match the desired list
+// and trims each matched entry to the keys the desired entry declares, so that
+// ordering and server-side defaults are not reported as drift
+func alignNamedEntries(current []map[string]interface{}, desired interface{}) []interface{} {
+	byName := make(map[string]map[string]interface{})
+	for _, entry := range current {
+		name, _ := entry["name"].(string)
+		byName[name] = entry
+	}
+
+	result := make([]interface{}, 0, len(current))
+	used := make(map[string]bool)
+
+	if desiredList, ok := desired.([]interface{}); ok {
+		for _, desiredVal := range desiredList {
+			desiredEntry, ok := desiredVal.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := desiredEntry["name"].(string)
+			entry, exists := byName[name]
+			if !exists || used[name] {
+				continue
+			}
+
+			trimmed := make(map[string]interface{})
+			for key := range desiredEntry {
+				if value, ok := entry[key]; ok {
+					trimmed[key] = value
+				}
+			}
+			result = append(result, trimmed)
+			used[name] = true
+		}
+	}
+
+	// Entries the config doesn't know about follow in a stable order
+	var remaining []string
+	for name := range byName {
+		if !used[name] {
+			remaining = append(remaining, name)
+		}
+	}
+	sort.Strings(remaining)
+	for _, name := range remaining {
+		result = append(result, byName[name])
+	}
+
+	return result
+}
@@ -1,10 +1,16 @@
 package aws
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestValidateDynamoDBTable(t *testing.T) {
@@ -55,6 +61,76 @@ func TestValidateDynamoDBTable(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "DynamoDB table with global secondary index",
+			instance: config.ResourceInstance{
+				ID:   "aws:dynamodb:table.test-table",
+				Kind: "aws:dynamodb:table",
+				Name: "test-table",
+				Properties: map[string]interface{}{
+					"hash_key": "id",
+					"attributes": []interface{}{
+						map[string]interface{}{"name": "id", "type": "S"},
+						map[string]interface{}{"name": "email", "type": "S"},
+					},
+					"global_secondary_indexes": []interface{}{
+						map[string]interface{}{
+							"name":            "by-email",
+							"hash_key":        "email",
+							"projection_type": "KEYS_ONLY",
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "DynamoDB index key missing from attributes",
+			instance: config.ResourceInstance{
+				ID:   "aws:dynamodb:table.test-table",
+				Kind: "aws:dynamodb:table",
+				Name: "test-table",
+				Properties: map[string]interface{}{
+					"hash_key": "id",
+					"attributes": []interface{}{
+						map[string]interface{}{"name": "id", "type": "S"},
+					},
+					"global_secondary_indexes": []interface{}{
+						map[string]interface{}{
+							"name":     "by-email",
+							"hash_key": "email",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "DynamoDB provisioned table missing capacity",
+			instance: config.ResourceInstance{
+				ID:   "aws:dynamodb:table.test-table",
+				Kind: "aws:dynamodb:table",
+				Name: "test-table",
+				Properties: map[string]interface{}{
+					"hash_key":     "id",
+					"billing_mode": "PROVISIONED",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "DynamoDB table with invalid billing mode",
+			instance: config.ResourceInstance{
+				ID:   "aws:dynamodb:table.test-table",
+				Kind: "aws:dynamodb:table",
+				Name: "test-table",
+				Properties: map[string]interface{}{
+					"hash_key":     "id",
+					"billing_mode": "ON_DEMAND",
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -68,3 +144,149 @@ func TestValidateDynamoDBTable(t *testing.T) {
 		})
 	}
 }
+
+func TestAlignNamedEntries(t *testing.T) {
+	current := []map[string]interface{}{
+		{"name": "by-status", "hash_key": "status", "projection_type": "ALL"},
+		{"name": "by-email", "hash_key": "email", "projection_type": "ALL"},
+		{"name": "unmanaged", "hash_key": "other", "projection_type": "ALL"},
+	}
+	desired := []interface{}{
+		map[string]interface{}{"name": "by-email", "hash_key": "email"},
+		map[string]interface{}{"name": "by-status", "hash_key": "status", "projection_type": "ALL"},
+	}
+
+	aligned := alignNamedEntries(current, desired)
+
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "by-email", "hash_key": "email"},
+		map[string]interface{}{"name": "by-status", "hash_key": "status", "projection_type": "ALL"},
+		map[string]interface{}{"name": "unmanaged", "hash_key": "other", "projection_type": "ALL"},
+	}, aligned)
+}
+
+// fakeDynamoDBTable serves a fixed table description and records the updates submitted
+type fakeDynamoDBTable struct {
+	table   types.TableDescription
+	updates []*dynamodb.UpdateTableInput
+}
+
+func (f *fakeDynamoDBTable) DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	table := f.table
+	return &dynamodb.DescribeTableOutput{Table: &table}, nil
+}
+
+func (f *fakeDynamoDBTable) UpdateTable(ctx context.Context, params *dynamodb.UpdateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateTableOutput, error) {
+	f.updates = append(f.updates, params)
+	return &dynamodb.UpdateTableOutput{}, nil
+}
+
+// provisionedTable returns an active provisioned table with a by-email index
+func provisionedTable(read, write, indexRead, indexWrite int64, projection types.ProjectionType) types.TableDescription {
+	return types.TableDescription{
+		TableName:             aws.String("users"),
+		TableStatus:           types.TableStatusActive,
+		BillingModeSummary:    &types.BillingModeSummary{BillingMode: types.BillingModeProvisioned},
+		ProvisionedThroughput: &types.ProvisionedThroughputDescription{ReadCapacityUnits: aws.Int64(read), WriteCapacityUnits: aws.Int64(write)},
+		GlobalSecondaryIndexes: []types.GlobalSecondaryIndexDescription{{
+			IndexName:             aws.String("by-email"),
+			IndexStatus:           types.IndexStatusActive,
+			KeySchema:             []types.KeySchemaElement{{AttributeName: aws.String("email"), KeyType: types.KeyTypeHash}},
+			Projection:            &types.Projection{ProjectionType: projection},
+			ProvisionedThroughput: &types.ProvisionedThroughputDescription{ReadCapacityUnits: aws.Int64(indexRead), WriteCapacityUnits: aws.Int64(indexWrite)},
+		}},
+	}
+}
+
+// usersTable returns a users table instance with a by-email index
+func usersTable(billingMode string, read, write, indexRead, indexWrite int, projection string) config.ResourceInstance {
+	return config.ResourceInstance{
+		ID:   "aws:dynamodb:table.users",
+		Kind: "aws:dynamodb:table",
+		Name: "users",
+		Properties: map[string]interface{}{
+			"hash_key":       "id",
+			"billing_mode":   billingMode,
+			"read_capacity":  read,
+			"write_capacity": write,
+			"attributes": []interface{}{
+				map[string]interface{}{"name": "id", "type": "S"},
+				map[string]interface{}{"name": "email", "type": "S"},
+			},
+			"global_secondary_indexes": []interface{}{
+				map[string]interface{}{
+					"name":            "by-email",
+					"hash_key":        "email",
+					"projection_type": projection,
+					"read_capacity":   indexRead,
+					"write_capacity":  indexWrite,
+				},
+			},
+		},
+	}
+}
+
+func TestUpdateDynamoDBTable(t *testing.T) {
+	original := dynamoDBPollInterval
+	dynamoDBPollInterval = time.Millisecond
+	defer func() { dynamoDBPollInterval = original }()
+
+	provider := NewProvider()
+	ctx := context.Background()
+
+	t.Run("unchanged table", func(t *testing.T) {
+		fake := &fakeDynamoDBTable{table: provisionedTable(5, 5, 2, 2, types.ProjectionTypeAll)}
+		require.NoError(t, provider.updateDynamoDBTableWith(ctx, fake, usersTable("PROVISIONED", 5, 5, 2, 2, "ALL")))
+		assert.Empty(t, fake.updates)
+	})
+
+	t.Run("table capacity", func(t *testing.T) {
+		fake := &fakeDynamoDBTable{table: provisionedTable(5, 5, 2, 2, types.ProjectionTypeAll)}
+		require.NoError(t, provider.updateDynamoDBTableWith(ctx, fake, usersTable("PROVISIONED", 10, 8, 2, 2, "ALL")))
+
+		require.Len(t, fake.updates, 1)
+		assert.Empty(t, fake.updates[0].BillingMode)
+		assert.Equal(t, int64(10), aws.ToInt64(fake.updates[0].ProvisionedThroughput.ReadCapacityUnits))
+		assert.Equal(t, int64(8), aws.ToInt64(fake.updates[0].ProvisionedThroughput.WriteCapacityUnits))
+	})
+
+	t.Run("index capacity", func(t *testing.T) {
+		fake := &fakeDynamoDBTable{table: provisionedTable(5, 5, 2, 2, types.ProjectionTypeAll)}
+		require.NoError(t, provider.updateDynamoDBTableWith(ctx, fake, usersTable("PROVISIONED", 5, 5, 4, 3, "ALL")))
+
+		require.Len(t, fake.updates, 1)
+		require.Len(t, fake.updates[0].GlobalSecondaryIndexUpdates, 1)
+		update := fake.updates[0].GlobalSecondaryIndexUpdates[0].Update
+		require.NotNil(t, update)
+		assert.Equal(t, "by-email", aws.ToString(update.IndexName))
+		assert.Equal(t, int64(4), aws.ToInt64(update.ProvisionedThroughput.ReadCapacityUnits))
+		assert.Equal(t, int64(3), aws.ToInt64(update.ProvisionedThroughput.WriteCapacityUnits))
+	})
+
+	t.Run("switch to provisioned", func(t *testing.T) {
+		table := provisionedTable(0, 0, 0, 0, types.ProjectionTypeAll)
+		table.BillingModeSummary.BillingMode = types.BillingModePayPerRequest
+		fake := &fakeDynamoDBTable{table: table}
+		require.NoError(t, provider.updateDynamoDBTableWith(ctx, fake, usersTable("PROVISIONED", 5, 5, 2, 2, "ALL")))
+
+		// The table and its indexes get their capacity in the same call
+		require.Len(t, fake.updates, 1)
+		assert.Equal(t, types.BillingModeProvisioned, fake.updates[0].BillingMode)
+		assert.Equal(t, int64(5), aws.ToInt64(fake.updates[0].ProvisionedThroughput.ReadCapacityUnits))
+		require.Len(t, fake.updates[0].GlobalSecondaryIndexUpdates, 1)
+		assert.Equal(t, int64(2), aws.ToInt64(fake.updates[0].GlobalSecondaryIndexUpdates[0].Update.ProvisionedThroughput.ReadCapacityUnits))
+	})
+
+	t.Run("index definition", func(t *testing.T) {
+		fake := &fakeDynamoDBTable{table: provisionedTable(5, 5, 2, 2, types.ProjectionTypeAll)}
+		require.NoError(t, provider.updateDynamoDBTableWith(ctx, fake, usersTable("PROVISIONED", 5, 5, 2, 2, "KEYS_ONLY")))
+
+		// A changed projection can't be updated, so the index is recreated
+		require.Len(t, fake.updates, 2)
+		require.NotNil(t, fake.updates[0].GlobalSecondaryIndexUpdates[0].Delete)
+		create := fake.updates[1].GlobalSecondaryIndexUpdates[0].Create
+		require.NotNil(t, create)
+		assert.Equal(t, types.ProjectionTypeKeysOnly, create.Projection.ProjectionType)
+		assert.Equal(t, int64(2), aws.ToInt64(create.ProvisionedThroughput.ReadCapacityUnits))
+	})
+}
@@ -2,10 +2,15 @@ package aws
 
 import (
 	"context"
+	"log/slog"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -101,3 +106,110 @@ func TestEC2InstanceStateRetrieval(t *testing.T) {
 		assert.Contains(t, err.Error(), "ami is required")
 	})
 }
+
+// fakeEC2InstanceStates returns the next of a sequence of instance states on each call
+type fakeEC2InstanceStates struct {
+	states []types.InstanceStateName
+	calls  int
+}
+
+func (f *fakeEC2InstanceStates) DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	state := f.states[len(f.states)-1]
+	if f.calls < len(f.states) {
+		state = f.states[f.calls]
+	}
+	f.calls++
+	return &ec2.DescribeInstancesOutput{
+		Reservations: []types.Reservation{{
+			Instances: []types.Instance{{InstanceId: aws.String(params.InstanceIds[0]), State: &types.InstanceState{Name: state}}},
+		}},
+	}, nil
+}
+
+func TestWaitForEC2InstanceState(t *testing.T) {
+	original := ec2PollInterval
+	ec2PollInterval = time.Millisecond
+	defer func() { ec2PollInterval = original }()
+
+	t.Run("returns once running", func(t *testing.T) {
+		fake := &fakeEC2InstanceStates{states: []types.InstanceStateName{types.InstanceStateNamePending, types.InstanceStateNamePending, types.InstanceStateNameRunning}}
+		require.NoError(t, waitForEC2InstanceState(context.Background(), slog.Default(), fake, "i-0123", types.InstanceStateNameRunning, time.Second))
+		assert.Equal(t, 3, fake.calls)
+	})
+
+	t.Run("fails when the instance terminates", func(t *testing.T) {
+		fake := &fakeEC2InstanceStates{states: []types.InstanceStateName{types.InstanceStateNamePending, types.InstanceStateNameTerminated}}
+		err := waitForEC2InstanceState(context.Background(), slog.Default(), fake, "i-0123", types.InstanceStateNameRunning, time.Second)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "entered state terminated")
+	})
+
+	t.Run("times out while pending", func(t *testing.T) {
+		fake := &fakeEC2InstanceStates{states: []types.InstanceStateName{types.InstanceStateNamePending}}
+		err := waitForEC2InstanceState(context.Background(), slog.Default(), fake, "i-0123", types.InstanceStateNameRunning, 20*time.Millisecond)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "timed out")
+		assert.Contains(t, err.Error(), "last state: pending")
+	})
+
+	t.Run("waits through running while stopping", func(t *testing.T) {
+		fake := &fakeEC2InstanceStates{states: []types.InstanceStateName{types.InstanceStateNameRunning, types.InstanceStateNameStopping, types.InstanceStateNameStopped}}
+		require.NoError(t, waitForEC2InstanceState(context.Background(), slog.Default(), fake, "i-0123", types.InstanceStateNameStopped, time.Second))
+		assert.Equal(t, 3, fake.calls)
+	})
+
+	t.Run("fails when a starting instance stops", func(t *testing.T) {
+		fake := &fakeEC2InstanceStates{states: []types.InstanceStateName{types.InstanceStateNamePending, types.InstanceStateNameStopped}}
+		err := waitForEC2InstanceState(context.Background(), slog.Default(), fake, "i-0123", types.InstanceStateNameRunning, time.Second)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "entered state stopped instead of running")
+	})
+}
+
+func TestEC2PowerState(t *testing.T) {
+	assert.Equal(t, "running", ec2PowerState(types.InstanceStateNamePending))
+	assert.Equal(t, "running", ec2PowerState(types.InstanceStateNameRunning))
+	assert.Equal(t, "stopped", ec2PowerState(types.InstanceStateNameStopping))
+	assert.Equal(t, "stopped", ec2PowerState(types.InstanceStateNameStopped))
+	assert.Equal(t, "terminated", ec2PowerState(types.InstanceStateNameTerminated))
+}
+
+func TestValidateEC2Instance_WaitTimeout(t *testing.T) {
+	provider := NewProvider()
+	instance := config.ResourceInstance{
+		ID:   "aws:ec2:instance.web",
+		Kind: "aws:ec2:instance",
+		Name: "web",
+		Properties: map[string]interface{}{
+			"instance_type": "t3.micro",
+			"ami":           "ami-0abcdef1234567890",
+			"wait_timeout":  "15m",
+		},
+	}
+	assert.NoError(t, provider.ValidateResource(instance))
+
+	instance.Properties["wait_timeout"] = "soon"
+	err := provider.ValidateResource(instance)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid wait_timeout")
+}
+
+func TestValidateEC2Instance_DesiredState(t *testing.T) {
+	provider := NewProvider()
+	instance := config.ResourceInstance{
+		ID:   "aws:ec2:instance.web",
+		Kind: "aws:ec2:instance",
+		Name: "web",
+		Properties: map[string]interface{}{
+			"instance_type": "t3.micro",
+			"ami":           "ami-0abcdef1234567890",
+			"desired_state": "stopped",
+		},
+	}
+	assert.NoError(t, provider.ValidateResource(instance))
+
+	instance.Properties["desired_state"] = "hibernated"
+	err := provider.ValidateResource(instance)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid desired_state hibernated")
+}
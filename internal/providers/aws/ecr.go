@@ -0,0 +1,284 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	ecrtypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
+)
+
+// ecrRepositoryNamePattern matches the repository names ECR accepts: lowercase components
+// separated by '/', each made of letters and digits joined by '.', '_' or '-'
+var ecrRepositoryNamePattern = regexp.MustCompile(`^(?:[a-z0-9]+(?:[._-][a-z0-9]+)*/)*[a-z0-9]+(?:[._-][a-z0-9]+)*$`)
+
+// ecrRepositoryAPI is the subset of the ECR API used to read a repository's state
+type ecrRepositoryAPI interface {
+	DescribeRepositories(ctx context.Context, params *ecr.DescribeRepositoriesInput, optFns ...func(*ecr.Options)) (*ecr.DescribeRepositoriesOutput, error)
+	GetLifecyclePolicy(ctx context.Context, params *ecr.GetLifecyclePolicyInput, optFns ...func(*ecr.Options)) (*ecr.GetLifecyclePolicyOutput, error)
+	ListTagsForResource(ctx context.Context, params *ecr.ListTagsForResourceInput, optFns ...func(*ecr.Options)) (*ecr.ListTagsForResourceOutput, error)
+}
+
+// validateECRRepository validates ECR repository configuration
+func (p *Provider) validateECRRepository(instance config.ResourceInstance) error {
+	if instance.Name == "" {
+		return fmt.Errorf("ECR repository name cannot be empty")
+	}
+
+	if len(instance.Name) < 2 || len(instance.Name) > 256 || !ecrRepositoryNamePattern.MatchString(instance.Name) {
+		return fmt.Errorf("invalid ECR repository name '%s': use 2 to 256 lowercase letters, digits and '._-/'", instance.Name)
+	}
+
+	if mutabilityVal, exists := instance.Properties["image_tag_mutability"]; exists {
+		mutability, _ := mutabilityVal.(string)
+		switch ecrtypes.ImageTagMutability(mutability) {
+		case ecrtypes.ImageTagMutabilityMutable, ecrtypes.ImageTagMutabilityImmutable:
+		default:
+			return fmt.Errorf("invalid image_tag_mutability '%v': must be MUTABLE or IMMUTABLE", mutabilityVal)
+		}
+	}
+
+	if scanVal, exists := instance.Properties["scan_on_push"]; exists {
+		if _, ok := scanVal.(bool); !ok {
+			return fmt.Errorf("scan_on_push must be a boolean")
+		}
+	}
+
+	if policyVal, exists := instance.Properties["lifecycle_policy"]; exists {
+		policy, ok := policyVal.(string)
+		if !ok {
+			return fmt.Errorf("lifecycle_policy must be a string")
+		}
+
+		var policyDoc struct {
+			Rules []interface{} `json:"rules"`
+		}
+		if err := json.Unmarshal([]byte(policy), &policyDoc); err != nil {
+			return fmt.Errorf("invalid lifecycle_policy JSON: %w", err)
+		}
+		if len(policyDoc.Rules) == 0 {
+			return fmt.Errorf("lifecycle_policy must have at least one rule")
+		}
+	}
+
+	if forceVal, exists := instance.Properties["force_delete"]; exists {
+		if _, ok := forceVal.(bool); !ok {
+			return fmt.Errorf("force_delete must be a boolean")
+		}
+	}
+
+	if tagsVal, exists := instance.Properties["tags"]; exists {
+		if _, ok := tagsVal.(map[string]interface{}); !ok {
+			return fmt.Errorf("tags must be a map")
+		}
+	}
+
+	return nil
+}
+
+// getECRRepositoryState retrieves the current state of an ECR repository
+func (p *Provider) getECRRepositoryState(ctx context.Context, instance config.ResourceInstance) (map[string]interface{}, error) {
+	return ecrRepositoryState(ctx, ecr.NewFromConfig(p.awsConfig), instance)
+}
+
+// ecrRepositoryState describes a repository. Tag mutability, scanning and the lifecycle
+// policy are reported only when configured, since ECR fills in defaults for them.
+func ecrRepositoryState(ctx context.Context, client ecrRepositoryAPI, instance config.ResourceInstance) (map[string]interface{}, error) {
+	result, err := client.DescribeRepositories(ctx, &ecr.DescribeRepositoriesInput{
+		RepositoryNames: []string{instance.Name},
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "RepositoryNotFoundException") {
+			return nil, nil // Repository doesn't exist
+		}
+		return nil, fmt.Errorf("failed to describe ECR repository %s: %w", instance.Name, err)
+	}
+	if len(result.Repositories) == 0 {
+		return nil, nil
+	}
+	repository := result.Repositories[0]
+
+	state := map[string]interface{}{
+		"repository_name": aws.ToString(repository.RepositoryName),
+		"repository_arn":  aws.ToString(repository.RepositoryArn),
+		"repository_uri":  aws.ToString(repository.RepositoryUri),
+		"registry_id":     aws.ToString(repository.RegistryId),
+	}
+
+	if _, configured := instance.Properties["image_tag_mutability"]; configured {
+		state["image_tag_mutability"] = string(repository.ImageTagMutability)
+	}
+
+	if _, configured := instance.Properties["scan_on_push"]; configured {
+		state["scan_on_push"] = repository.ImageScanningConfiguration != nil && repository.ImageScanningConfiguration.ScanOnPush
+	}
+
+	if desiredPolicy, configured := instance.Properties["lifecycle_policy"]; configured {
+		policyResult, err := client.GetLifecyclePolicy(ctx, &ecr.GetLifecyclePolicyInput{
+			RepositoryName: aws.String(instance.Name),
+		})
+		if err != nil && !strings.Contains(err.Error(), "LifecyclePolicyNotFoundException") {
+			return nil, fmt.Errorf("failed to get lifecycle policy of ECR repository %s: %w", instance.Name, err)
+		}
+		if err == nil {
+			state["lifecycle_policy"] = policyDocumentState(aws.ToString(policyResult.LifecyclePolicyText), desiredPolicy)
+		}
+	}
+
+	// force_delete only affects deletion, so it's reported as configured
+	if force, exists := instance.Properties["force_delete"]; exists {
+		state["force_delete"] = force
+	}
+
+	tagsResult, err := client.ListTagsForResource(ctx, &ecr.ListTagsForResourceInput{
+		ResourceArn: repository.RepositoryArn,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags for ECR repository %s: %w", instance.Name, err)
+	}
+	tags := make(map[string]interface{}, len(tagsResult.Tags))
+	for _, tag := range tagsResult.Tags {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	if len(tags) > 0 {
+		state["tags"] = tags
+	}
+
+	return state, nil
+}
+
+// ecrTags converts the tags property to ECR tags
+func ecrTags(properties map[string]interface{}) []ecrtypes.Tag {
+	var tags []ecrtypes.Tag
+	for key, value := range stringTags(properties) {
+		tags = append(tags, ecrtypes.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	return tags
+}
+
+// createECRRepository creates an ECR repository and sets its lifecycle policy
+func (p *Provider) createECRRepository(ctx context.Context, instance config.ResourceInstance) error {
+	client := ecr.NewFromConfig(p.awsConfig)
+
+	input := &ecr.CreateRepositoryInput{
+		RepositoryName: aws.String(instance.Name),
+		Tags:           ecrTags(instance.Properties),
+	}
+	if mutability, ok := instance.Properties["image_tag_mutability"].(string); ok {
+		input.ImageTagMutability = ecrtypes.ImageTagMutability(mutability)
+	}
+	if scanOnPush, ok := instance.Properties["scan_on_push"].(bool); ok {
+		input.ImageScanningConfiguration = &ecrtypes.ImageScanningConfiguration{ScanOnPush: scanOnPush}
+	}
+
+	if _, err := client.CreateRepository(ctx, input); err != nil {
+		if isAlreadyOwnedError(err) {
+			return p.updateExisting(ctx, instance, p.updateECRRepository)
+		}
+		return fmt.Errorf("failed to create ECR repository %s: %w", instance.Name, err)
+	}
+
+	if policy, ok := instance.Properties["lifecycle_policy"].(string); ok {
+		_, err := client.PutLifecyclePolicy(ctx, &ecr.PutLifecyclePolicyInput{
+			RepositoryName:      aws.String(instance.Name),
+			LifecyclePolicyText: aws.String(policy),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to set lifecycle policy for ECR repository %s: %w", instance.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// updateECRRepository brings the tag mutability, scanning, lifecycle policy and tags of an
+// ECR repository in line with the configuration
+func (p *Provider) updateECRRepository(ctx context.Context, instance config.ResourceInstance, currentState map[string]interface{}) error {
+	client := ecr.NewFromConfig(p.awsConfig)
+
+	if mutability, ok := instance.Properties["image_tag_mutability"].(string); ok && mutability != currentState["image_tag_mutability"] {
+		_, err := client.PutImageTagMutability(ctx, &ecr.PutImageTagMutabilityInput{
+			RepositoryName:     aws.String(instance.Name),
+			ImageTagMutability: ecrtypes.ImageTagMutability(mutability),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update image tag mutability of ECR repository %s: %w", instance.Name, err)
+		}
+	}
+
+	if scanOnPush, ok := instance.Properties["scan_on_push"].(bool); ok && scanOnPush != currentState["scan_on_push"] {
+		_, err := client.PutImageScanningConfiguration(ctx, &ecr.PutImageScanningConfigurationInput{
+			RepositoryName:             aws.String(instance.Name),
+			ImageScanningConfiguration: &ecrtypes.ImageScanningConfiguration{ScanOnPush: scanOnPush},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update image scanning of ECR repository %s: %w", instance.Name, err)
+		}
+	}
+
+	if policy, ok := instance.Properties["lifecycle_policy"].(string); ok && policy != currentState["lifecycle_policy"] {
+		_, err := client.PutLifecyclePolicy(ctx, &ecr.PutLifecyclePolicyInput{
+			RepositoryName:      aws.String(instance.Name),
+			LifecyclePolicyText: aws.String(policy),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update lifecycle policy of ECR repository %s: %w", instance.Name, err)
+		}
+	}
+
+	arn, _ := currentState["repository_arn"].(string)
+	if arn == "" {
+		return fmt.Errorf("failed to update tags for ECR repository %s: ARN not found in current state", instance.Name)
+	}
+
+	if tags := ecrTags(instance.Properties); len(tags) > 0 {
+		_, err := client.TagResource(ctx, &ecr.TagResourceInput{
+			ResourceArn: aws.String(arn),
+			Tags:        tags,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update tags for ECR repository %s: %w", instance.Name, err)
+		}
+	}
+
+	currentTags, _ := currentState["tags"].(map[string]interface{})
+	if removed := p.removedTagKeys(currentTags, stringTags(instance.Properties)); len(removed) > 0 {
+		_, err := client.UntagResource(ctx, &ecr.UntagResourceInput{
+			ResourceArn: aws.String(arn),
+			TagKeys:     removed,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to remove tags from ECR repository %s: %w", instance.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// deleteECRRepository deletes an ECR repository. Repositories that still hold images are
+// only deleted, along with their images, when force_delete is set.
+func (p *Provider) deleteECRRepository(ctx context.Context, instance config.ResourceInstance) error {
+	client := ecr.NewFromConfig(p.awsConfig)
+
+	force, _ := instance.Properties["force_delete"].(bool)
+	_, err := client.DeleteRepository(ctx, &ecr.DeleteRepositoryInput{
+		RepositoryName: aws.String(instance.Name),
+		Force:          force,
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "RepositoryNotFoundException") {
+			return nil // Repository already deleted
+		}
+		if strings.Contains(err.Error(), "RepositoryNotEmptyException") {
+			return fmt.Errorf("ECR repository %s still contains images; set force_delete to delete them with the repository", instance.Name)
+		}
+		return fmt.Errorf("failed to delete ECR repository %s: %w", instance.Name, err)
+	}
+
+	return nil
+}
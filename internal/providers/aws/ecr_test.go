@@ -0,0 +1,189 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	ecrtypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLifecyclePolicy = `{"rules":[{"rulePriority":1,"selection":{"tagStatus":"untagged","countType":"sinceImagePushed","countUnit":"days","countNumber":14},"action":{"type":"expire"}}]}`
+
+func TestValidateECRRepository(t *testing.T) {
+	provider := NewProvider()
+
+	tests := []struct {
+		name       string
+		repository string
+		properties map[string]interface{}
+		wantErr    bool
+	}{
+		{
+			name:       "valid repository",
+			repository: "team/web-app",
+			properties: map[string]interface{}{
+				"image_tag_mutability": "IMMUTABLE",
+				"scan_on_push":         true,
+				"lifecycle_policy":     testLifecyclePolicy,
+				"force_delete":         true,
+				"tags":                 map[string]interface{}{"Team": "web"},
+			},
+			wantErr: false,
+		},
+		{
+			name:       "minimal repository",
+			repository: "web-app",
+			properties: map[string]interface{}{},
+			wantErr:    false,
+		},
+		{
+			name:       "uppercase name",
+			repository: "WebApp",
+			properties: map[string]interface{}{},
+			wantErr:    true,
+		},
+		{
+			name:       "invalid mutability",
+			repository: "web-app",
+			properties: map[string]interface{}{"image_tag_mutability": "LOCKED"},
+			wantErr:    true,
+		},
+		{
+			name:       "non-boolean scan_on_push",
+			repository: "web-app",
+			properties: map[string]interface{}{"scan_on_push": "yes"},
+			wantErr:    true,
+		},
+		{
+			name:       "lifecycle policy that isn't JSON",
+			repository: "web-app",
+			properties: map[string]interface{}{"lifecycle_policy": "expire untagged"},
+			wantErr:    true,
+		},
+		{
+			name:       "lifecycle policy without rules",
+			repository: "web-app",
+			properties: map[string]interface{}{"lifecycle_policy": `{"rules":[]}`},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := provider.ValidateResource(config.ResourceInstance{
+				ID:         "aws:ecr:repository." + tt.repository,
+				Kind:       "aws:ecr:repository",
+				Name:       tt.repository,
+				Properties: tt.properties,
+			})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// fakeECRRepositories serves a fixed set of repositories
+type fakeECRRepositories struct {
+	repositories []ecrtypes.Repository
+	policies     map[string]string
+	tags         map[string][]ecrtypes.Tag
+}
+
+func (f *fakeECRRepositories) DescribeRepositories(ctx context.Context, params *ecr.DescribeRepositoriesInput, optFns ...func(*ecr.Options)) (*ecr.DescribeRepositoriesOutput, error) {
+	for _, repository := range f.repositories {
+		if aws.ToString(repository.RepositoryName) == params.RepositoryNames[0] {
+			return &ecr.DescribeRepositoriesOutput{Repositories: []ecrtypes.Repository{repository}}, nil
+		}
+	}
+	return nil, errors.New("RepositoryNotFoundException: The repository does not exist")
+}
+
+func (f *fakeECRRepositories) GetLifecyclePolicy(ctx context.Context, params *ecr.GetLifecyclePolicyInput, optFns ...func(*ecr.Options)) (*ecr.GetLifecyclePolicyOutput, error) {
+	policy, exists := f.policies[aws.ToString(params.RepositoryName)]
+	if !exists {
+		return nil, errors.New("LifecyclePolicyNotFoundException: Lifecycle policy does not exist")
+	}
+	return &ecr.GetLifecyclePolicyOutput{LifecyclePolicyText: aws.String(policy)}, nil
+}
+
+func (f *fakeECRRepositories) ListTagsForResource(ctx context.Context, params *ecr.ListTagsForResourceInput, optFns ...func(*ecr.Options)) (*ecr.ListTagsForResourceOutput, error) {
+	return &ecr.ListTagsForResourceOutput{Tags: f.tags[aws.ToString(params.ResourceArn)]}, nil
+}
+
+func TestECRRepositoryState(t *testing.T) {
+	arn := "arn:aws:ecr:us-east-1:123456789012:repository/web-app"
+	client := &fakeECRRepositories{
+		repositories: []ecrtypes.Repository{{
+			RepositoryName:             aws.String("web-app"),
+			RepositoryArn:              aws.String(arn),
+			RepositoryUri:              aws.String("123456789012.dkr.ecr.us-east-1.amazonaws.com/web-app"),
+			RegistryId:                 aws.String("123456789012"),
+			ImageTagMutability:         ecrtypes.ImageTagMutabilityImmutable,
+			ImageScanningConfiguration: &ecrtypes.ImageScanningConfiguration{ScanOnPush: true},
+		}},
+		// ECR returns the policy reformatted
+		policies: map[string]string{"web-app": "{\n  \"rules\": [{\"rulePriority\": 1, \"selection\": {\"tagStatus\": \"untagged\", \"countType\": \"sinceImagePushed\", \"countUnit\": \"days\", \"countNumber\": 14}, \"action\": {\"type\": \"expire\"}}]\n}"},
+		tags:     map[string][]ecrtypes.Tag{arn: {{Key: aws.String("Team"), Value: aws.String("web")}}},
+	}
+
+	instance := config.ResourceInstance{
+		ID:   "aws:ecr:repository.web-app",
+		Kind: "aws:ecr:repository",
+		Name: "web-app",
+		Properties: map[string]interface{}{
+			"image_tag_mutability": "IMMUTABLE",
+			"scan_on_push":         true,
+			"lifecycle_policy":     testLifecyclePolicy,
+			"force_delete":         true,
+		},
+	}
+
+	state, err := ecrRepositoryState(context.Background(), client, instance)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"repository_name":      "web-app",
+		"repository_arn":       arn,
+		"repository_uri":       "123456789012.dkr.ecr.us-east-1.amazonaws.com/web-app",
+		"registry_id":          "123456789012",
+		"image_tag_mutability": "IMMUTABLE",
+		"scan_on_push":         true,
+		"lifecycle_policy":     testLifecyclePolicy,
+		"force_delete":         true,
+		"tags":                 map[string]interface{}{"Team": "web"},
+	}, state)
+
+	// Settings the configuration leaves out aren't reported
+	instance.Properties = map[string]interface{}{}
+	state, err = ecrRepositoryState(context.Background(), client, instance)
+	require.NoError(t, err)
+	assert.NotContains(t, state, "image_tag_mutability")
+	assert.NotContains(t, state, "scan_on_push")
+	assert.NotContains(t, state, "lifecycle_policy")
+
+	// A configured policy that was removed from the repository is missing from the state
+	instance.Properties = map[string]interface{}{"lifecycle_policy": testLifecyclePolicy}
+	delete(client.policies, "web-app")
+	state, err = ecrRepositoryState(context.Background(), client, instance)
+	require.NoError(t, err)
+	assert.NotContains(t, state, "lifecycle_policy")
+
+	// A repository without tags reports none
+	delete(client.tags, arn)
+	state, err = ecrRepositoryState(context.Background(), client, instance)
+	require.NoError(t, err)
+	assert.NotContains(t, state, "tags")
+
+	instance.Name = "missing"
+	state, err = ecrRepositoryState(context.Background(), client, instance)
+	require.NoError(t, err)
+	assert.Nil(t, state)
+}
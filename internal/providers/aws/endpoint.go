@@ -0,0 +1,40 @@
+package aws
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// endpointFromProviderConfig reads endpoint_url, the base URL every AWS API call is sent to
+// instead of the real service endpoints, such as a LocalStack container. It returns an empty
+// string when no endpoint is configured.
+func endpointFromProviderConfig(providerConfig map[string]interface{}) (string, error) {
+	endpoint, _ := providerConfig["endpoint_url"].(string)
+	if endpoint == "" {
+		return "", nil
+	}
+
+	parsed, err := url.Parse(endpoint)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", fmt.Errorf("invalid endpoint_url '%s': must be an http or https URL such as http://localhost:4566", endpoint)
+	}
+
+	return strings.TrimSuffix(endpoint, "/"), nil
+}
+
+// withEndpoint returns a copy of cfg whose clients send every request to endpoint
+func withEndpoint(cfg aws.Config, endpoint string) aws.Config {
+	overridden := cfg.Copy()
+	overridden.BaseEndpoint = aws.String(endpoint)
+	return overridden
+}
+
+// s3PathStyle addresses buckets as endpoint/bucket rather than bucket.endpoint, which
+// mock endpoints such as LocalStack need because their host name has no bucket subdomains
+func s3PathStyle(options *s3.Options) {
+	options.UsePathStyle = true
+}
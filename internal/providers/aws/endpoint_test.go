@@ -0,0 +1,77 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointFromProviderConfig(t *testing.T) {
+	tests := []struct {
+		name           string
+		providerConfig map[string]interface{}
+		expected       string
+		wantErr        string
+	}{
+		{
+			name:           "no endpoint",
+			providerConfig: map[string]interface{}{"region": "us-east-1"},
+		},
+		{
+			name:           "local endpoint",
+			providerConfig: map[string]interface{}{"endpoint_url": "http://localhost:4566"},
+			expected:       "http://localhost:4566",
+		},
+		{
+			name:           "trailing slash is trimmed",
+			providerConfig: map[string]interface{}{"endpoint_url": "https://mock.internal:4566/"},
+			expected:       "https://mock.internal:4566",
+		},
+		{
+			name:           "missing scheme",
+			providerConfig: map[string]interface{}{"endpoint_url": "localhost:4566"},
+			wantErr:        "invalid endpoint_url",
+		},
+		{
+			name:           "unsupported scheme",
+			providerConfig: map[string]interface{}{"endpoint_url": "ftp://localhost:4566"},
+			wantErr:        "invalid endpoint_url",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint, err := endpointFromProviderConfig(tt.providerConfig)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, endpoint)
+		})
+	}
+}
+
+func TestProvider_Initialize_EndpointURL(t *testing.T) {
+	provider := NewProvider()
+	err := provider.Initialize(context.Background(), map[string]interface{}{
+		"region":       "us-east-1",
+		"endpoint_url": "http://localhost:4566",
+	})
+	require.NoError(t, err)
+
+	require.NotNil(t, provider.awsConfig.BaseEndpoint)
+	assert.Equal(t, "http://localhost:4566", aws.ToString(provider.awsConfig.BaseEndpoint))
+	s3Client, ok := provider.s3Client.(*s3.Client)
+	require.True(t, ok)
+	assert.True(t, s3Client.Options().UsePathStyle)
+
+	err = NewProvider().Initialize(context.Background(), map[string]interface{}{"endpoint_url": "not a url"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid endpoint_url")
+}
@@ -79,6 +79,11 @@ func (p *Provider) createIAMUser(ctx context.Context, instance config.ResourceIn
 
 	_, err := client.CreateUser(ctx, input)
 	if err != nil {
+		if isAlreadyOwnedError(err) {
+			return p.updateExisting(ctx, instance, func(ctx context.Context, instance config.ResourceInstance, _ map[string]interface{}) error {
+				return p.updateIAMUser(ctx, instance)
+			})
+		}
 		return fmt.Errorf("failed to create IAM user %s: %w", instance.Name, err)
 	}
 
@@ -177,6 +182,14 @@ func (p *Provider) updateIAMUser(ctx context.Context, instance config.ResourceIn
 func (p *Provider) deleteIAMUser(ctx context.Context, instance config.ResourceInstance) error {
 	client := iam.NewFromConfig(p.awsConfig)
 
+	// DeleteUser fails with DeleteConflict while anything is still attached to the user
+	if err := removeIAMUserAttachments(ctx, client, instance.Name); err != nil {
+		if isResourceNotFound(err) {
+			return nil // User already deleted
+		}
+		return err
+	}
+
 	input := &iam.DeleteUserInput{
 		UserName: aws.String(instance.Name),
 	}
@@ -192,6 +205,89 @@ func (p *Provider) deleteIAMUser(ctx context.Context, instance config.ResourceIn
 	return nil
 }
 
+// removeIAMUserAttachments detaches managed policies, deletes inline policies and access
+// keys, and removes group memberships, as the AWS console does before deleting a user.
+// Each step only acts on what is there, so a user without attachments is left untouched.
+func removeIAMUserAttachments(ctx context.Context, client *iam.Client, userName string) error {
+	attachedPolicies := iam.NewListAttachedUserPoliciesPaginator(client, &iam.ListAttachedUserPoliciesInput{
+		UserName: aws.String(userName),
+	})
+	for attachedPolicies.HasMorePages() {
+		page, err := attachedPolicies.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list attached policies for IAM user %s: %w", userName, err)
+		}
+		for _, policy := range page.AttachedPolicies {
+			_, err := client.DetachUserPolicy(ctx, &iam.DetachUserPolicyInput{
+				UserName:  aws.String(userName),
+				PolicyArn: policy.PolicyArn,
+			})
+			if err != nil && !isResourceNotFound(err) {
+				return fmt.Errorf("failed to detach policy %s from IAM user %s: %w", aws.ToString(policy.PolicyArn), userName, err)
+			}
+		}
+	}
+
+	inlinePolicies := iam.NewListUserPoliciesPaginator(client, &iam.ListUserPoliciesInput{
+		UserName: aws.String(userName),
+	})
+	for inlinePolicies.HasMorePages() {
+		page, err := inlinePolicies.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list inline policies for IAM user %s: %w", userName, err)
+		}
+		for _, policyName := range page.PolicyNames {
+			_, err := client.DeleteUserPolicy(ctx, &iam.DeleteUserPolicyInput{
+				UserName:   aws.String(userName),
+				PolicyName: aws.String(policyName),
+			})
+			if err != nil && !isResourceNotFound(err) {
+				return fmt.Errorf("failed to delete inline policy %s from IAM user %s: %w", policyName, userName, err)
+			}
+		}
+	}
+
+	accessKeys := iam.NewListAccessKeysPaginator(client, &iam.ListAccessKeysInput{
+		UserName: aws.String(userName),
+	})
+	for accessKeys.HasMorePages() {
+		page, err := accessKeys.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list access keys for IAM user %s: %w", userName, err)
+		}
+		for _, key := range page.AccessKeyMetadata {
+			_, err := client.DeleteAccessKey(ctx, &iam.DeleteAccessKeyInput{
+				UserName:    aws.String(userName),
+				AccessKeyId: key.AccessKeyId,
+			})
+			if err != nil && !isResourceNotFound(err) {
+				return fmt.Errorf("failed to delete access key %s of IAM user %s: %w", aws.ToString(key.AccessKeyId), userName, err)
+			}
+		}
+	}
+
+	groups := iam.NewListGroupsForUserPaginator(client, &iam.ListGroupsForUserInput{
+		UserName: aws.String(userName),
+	})
+	for groups.HasMorePages() {
+		page, err := groups.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list groups for IAM user %s: %w", userName, err)
+		}
+		for _, group := range page.Groups {
+			_, err := client.RemoveUserFromGroup(ctx, &iam.RemoveUserFromGroupInput{
+				UserName:  aws.String(userName),
+				GroupName: group.GroupName,
+			})
+			if err != nil && !isResourceNotFound(err) {
+				return fmt.Errorf("failed to remove IAM user %s from group %s: %w", userName, aws.ToString(group.GroupName), err)
+			}
+		}
+	}
+
+	return nil
+}
+
 // validateIAMUser validates IAM user configuration
 func (p *Provider) validateIAMUser(instance config.ResourceInstance) error {
 	if instance.Name == "" {
@@ -271,6 +367,10 @@ func (p *Provider) getIAMRoleState(ctx context.Context, instance config.Resource
 		state["description"] = *result.Role.Description
 	}
 
+	if err := rolePolicyState(ctx, client, instance, state); err != nil {
+		return nil, err
+	}
+
 	return state, nil
 }
 
@@ -328,9 +428,39 @@ func (p *Provider) createIAMRole(ctx context.Context, instance config.ResourceIn
 
 	_, err := client.CreateRole(ctx, input)
 	if err != nil {
+		if isAlreadyOwnedError(err) {
+			return p.updateExisting(ctx, instance, func(ctx context.Context, instance config.ResourceInstance, _ map[string]interface{}) error {
+				return p.updateIAMRole(ctx, instance)
+			})
+		}
 		return fmt.Errorf("failed to create IAM role %s: %w", instance.Name, err)
 	}
 
+	return p.syncIAMRolePolicies(ctx, client, instance)
+}
+
+// syncIAMRolePolicies applies managed_policy_arns and inline_policies when they are set
+func (p *Provider) syncIAMRolePolicies(ctx context.Context, client *iam.Client, instance config.ResourceInstance) error {
+	managedPolicyARNs, manageARNs, err := managedPolicyARNsFromProperties(instance.Properties)
+	if err != nil {
+		return err
+	}
+	if manageARNs {
+		if err := syncRoleManagedPolicies(ctx, client, instance.Name, managedPolicyARNs); err != nil {
+			return err
+		}
+	}
+
+	inlinePolicies, manageInline, err := inlinePoliciesFromProperties(instance.Properties)
+	if err != nil {
+		return err
+	}
+	if manageInline {
+		if err := syncRoleInlinePolicies(ctx, client, instance.Name, inlinePolicies); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -338,6 +468,14 @@ func (p *Provider) createIAMRole(ctx context.Context, instance config.ResourceIn
 func (p *Provider) deleteIAMRole(ctx context.Context, instance config.ResourceInstance) error {
 	client := iam.NewFromConfig(p.awsConfig)
 
+	// A role can't be deleted while policies are attached to it
+	if err := removeAllRolePolicies(ctx, client, instance.Name); err != nil {
+		if isResourceNotFound(err) {
+			return nil // Role already deleted
+		}
+		return err
+	}
+
 	input := &iam.DeleteRoleInput{
 		RoleName: aws.String(instance.Name),
 	}
@@ -386,6 +524,13 @@ func (p *Provider) validateIAMRole(instance config.ResourceInstance) error {
 		return fmt.Errorf("invalid assume_role_policy JSON: %w", err)
 	}
 
+	if _, _, err := managedPolicyARNsFromProperties(instance.Properties); err != nil {
+		return err
+	}
+	if _, _, err := inlinePoliciesFromProperties(instance.Properties); err != nil {
+		return err
+	}
+
 	// Validate path if specified
 	if pathVal, exists := instance.Properties["path"]; exists {
 		if pathStr, ok := pathVal.(string); ok {
@@ -677,7 +822,7 @@ func (p *Provider) updateIAMRole(ctx context.Context, instance config.ResourceIn
 		}
 	}
 
-	return nil
+	return p.syncIAMRolePolicies(ctx, client, instance)
 }
 
 // updateIAMPolicy updates an existing IAM policy
@@ -702,6 +847,11 @@ func (p *Provider) updateIAMPolicy(ctx context.Context, instance config.Resource
 	// Update policy document if specified
 	if policyVal, exists := instance.Properties["policy"]; exists {
 		if policy, ok := policyVal.(string); ok {
+			// Make room for the new version
+			if err := prunePolicyVersions(ctx, client, policyArn); err != nil {
+				return fmt.Errorf("failed to update policy document for %s: %w", instance.Name, err)
+			}
+
 			// Create a new policy version
 			createVersionInput := &iam.CreatePolicyVersionInput{
 				PolicyArn:      aws.String(policyArn),
@@ -773,6 +923,52 @@ func (p *Provider) updateIAMPolicy(ctx context.Context, instance config.Resource
 	return nil
 }
 
+// maxPolicyVersions is the number of versions IAM keeps for a managed policy
+const maxPolicyVersions = 5
+
+// iamPolicyVersionAPI is the subset of the IAM client used to manage policy versions
+type iamPolicyVersionAPI interface {
+	ListPolicyVersions(ctx context.Context, params *iam.ListPolicyVersionsInput, optFns ...func(*iam.Options)) (*iam.ListPolicyVersionsOutput, error)
+	DeletePolicyVersion(ctx context.Context, params *iam.DeletePolicyVersionInput, optFns ...func(*iam.Options)) (*iam.DeletePolicyVersionOutput, error)
+}
+
+// prunePolicyVersions deletes the oldest non-default version of a policy when it is at the
+// version limit, so CreatePolicyVersion doesn't fail with LimitExceeded
+func prunePolicyVersions(ctx context.Context, client iamPolicyVersionAPI, policyArn string) error {
+	result, err := client.ListPolicyVersions(ctx, &iam.ListPolicyVersionsInput{
+		PolicyArn: aws.String(policyArn),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list policy versions: %w", err)
+	}
+	if len(result.Versions) < maxPolicyVersions {
+		return nil
+	}
+
+	var oldest *types.PolicyVersion
+	for i, version := range result.Versions {
+		if version.IsDefaultVersion {
+			continue
+		}
+		if oldest == nil || aws.ToTime(version.CreateDate).Before(aws.ToTime(oldest.CreateDate)) {
+			oldest = &result.Versions[i]
+		}
+	}
+	if oldest == nil {
+		return nil
+	}
+
+	_, err = client.DeletePolicyVersion(ctx, &iam.DeletePolicyVersionInput{
+		PolicyArn: aws.String(policyArn),
+		VersionId: oldest.VersionId,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete policy version %s: %w", aws.ToString(oldest.VersionId), err)
+	}
+
+	return nil
+}
+
 // deleteIAMPolicy deletes an IAM policy
 func (p *Provider) deleteIAMPolicy(ctx context.Context, instance config.ResourceInstance) error {
 	client := iam.NewFromConfig(p.awsConfig)
@@ -807,8 +1003,16 @@ func (p *Provider) deleteIAMPolicy(ctx context.Context, instance config.Resource
 	return nil
 }
 
-// getAccountID retrieves the AWS account ID
+// getAccountID retrieves the AWS account ID. The result of the first successful
+// GetCallerIdentity call is cached until the provider is initialized again.
 func (p *Provider) getAccountID(ctx context.Context) (string, error) {
+	p.accountMu.Lock()
+	defer p.accountMu.Unlock()
+
+	if p.accountID != "" {
+		return p.accountID, nil
+	}
+
 	// Use STS to get caller identity
 	stsClient := p.stsClient
 	if stsClient == nil {
@@ -820,5 +1024,6 @@ func (p *Provider) getAccountID(ctx context.Context) (string, error) {
 		return "", fmt.Errorf("failed to get caller identity: %w", err)
 	}
 
-	return *result.Account, nil
+	p.accountID = *result.Account
+	return p.accountID, nil
 }
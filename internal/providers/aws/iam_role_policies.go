@@ -0,0 +1,287 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+)
+
+// managedPolicyARNsFromProperties returns the managed_policy_arns property and whether it is set
+func managedPolicyARNsFromProperties(properties map[string]interface{}) ([]string, bool, error) {
+	value, exists := properties["managed_policy_arns"]
+	if !exists || value == nil {
+		return nil, false, nil
+	}
+
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, true, fmt.Errorf("managed_policy_arns must be a list of policy ARNs")
+	}
+
+	arns := make([]string, 0, len(list))
+	for _, item := range list {
+		arn, ok := item.(string)
+		if !ok || !strings.HasPrefix(arn, "arn:") {
+			return nil, true, fmt.Errorf("managed_policy_arns must contain policy ARNs, got %v", item)
+		}
+		arns = append(arns, arn)
+	}
+
+	return arns, true, nil
+}
+
+// inlinePoliciesFromProperties returns the inline_policies property, policy name to JSON
+// document, and whether it is set
+func inlinePoliciesFromProperties(properties map[string]interface{}) (map[string]string, bool, error) {
+	value, exists := properties["inline_policies"]
+	if !exists || value == nil {
+		return nil, false, nil
+	}
+
+	policies, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, true, fmt.Errorf("inline_policies must be a map of policy names to policy documents")
+	}
+
+	documents := make(map[string]string, len(policies))
+	for name, documentVal := range policies {
+		document, ok := documentVal.(string)
+		if !ok {
+			return nil, true, fmt.Errorf("inline policy %s must be a JSON string", name)
+		}
+
+		var policyDoc interface{}
+		if err := json.Unmarshal([]byte(document), &policyDoc); err != nil {
+			return nil, true, fmt.Errorf("invalid JSON in inline policy %s: %w", name, err)
+		}
+		documents[name] = document
+	}
+
+	return documents, true, nil
+}
+
+// listAttachedRolePolicyARNs returns the ARNs of the managed policies attached to a role
+func listAttachedRolePolicyARNs(ctx context.Context, client *iam.Client, roleName string) ([]string, error) {
+	arns := make([]string, 0)
+	paginator := iam.NewListAttachedRolePoliciesPaginator(client, &iam.ListAttachedRolePoliciesInput{
+		RoleName: aws.String(roleName),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list attached policies for IAM role %s: %w", roleName, err)
+		}
+		for _, policy := range page.AttachedPolicies {
+			arns = append(arns, aws.ToString(policy.PolicyArn))
+		}
+	}
+
+	sort.Strings(arns)
+	return arns, nil
+}
+
+// listRoleInlinePolicies returns a role's inline policies, policy name to JSON document
+func listRoleInlinePolicies(ctx context.Context, client *iam.Client, roleName string) (map[string]string, error) {
+	documents := make(map[string]string)
+	paginator := iam.NewListRolePoliciesPaginator(client, &iam.ListRolePoliciesInput{
+		RoleName: aws.String(roleName),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list inline policies for IAM role %s: %w", roleName, err)
+		}
+
+		for _, policyName := range page.PolicyNames {
+			result, err := client.GetRolePolicy(ctx, &iam.GetRolePolicyInput{
+				RoleName:   aws.String(roleName),
+				PolicyName: aws.String(policyName),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get inline policy %s of IAM role %s: %w", policyName, roleName, err)
+			}
+
+			// IAM returns policy documents URL-encoded
+			document, err := url.QueryUnescape(aws.ToString(result.PolicyDocument))
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode inline policy %s of IAM role %s: %w", policyName, roleName, err)
+			}
+			documents[policyName] = document
+		}
+	}
+
+	return documents, nil
+}
+
+// rolePolicyState adds the configured policy attachment properties to a role's state. Values
+// are reported in the configuration's form when they are equivalent, so that ordering of
+// ARNs and formatting of JSON documents aren't reported as drift.
+func rolePolicyState(ctx context.Context, client *iam.Client, instance config.ResourceInstance, state map[string]interface{}) error {
+	desiredARNs, manageARNs, _ := managedPolicyARNsFromProperties(instance.Properties)
+	if manageARNs {
+		attached, err := listAttachedRolePolicyARNs(ctx, client, instance.Name)
+		if err != nil {
+			return err
+		}
+		state["managed_policy_arns"] = orderLike(attached, desiredARNs)
+	}
+
+	desiredPolicies, manageInline, _ := inlinePoliciesFromProperties(instance.Properties)
+	if manageInline {
+		current, err := listRoleInlinePolicies(ctx, client, instance.Name)
+		if err != nil {
+			return err
+		}
+
+		inline := make(map[string]interface{}, len(current))
+		for name, document := range current {
+			if desired, exists := desiredPolicies[name]; exists && equivalentPolicyDocuments(document, desired) {
+				document = desired
+			}
+			inline[name] = document
+		}
+		state["inline_policies"] = inline
+	}
+
+	return nil
+}
+
+// syncRoleManagedPolicies attaches and detaches managed policies so exactly the desired
+// ones are attached to a role
+func syncRoleManagedPolicies(ctx context.Context, client *iam.Client, roleName string, desired []string) error {
+	attached, err := listAttachedRolePolicyARNs(ctx, client, roleName)
+	if err != nil {
+		return err
+	}
+
+	wanted := make(map[string]bool, len(desired))
+	for _, arn := range desired {
+		wanted[arn] = true
+	}
+	current := make(map[string]bool, len(attached))
+	for _, arn := range attached {
+		current[arn] = true
+	}
+
+	for _, arn := range attached {
+		if wanted[arn] {
+			continue
+		}
+		_, err := client.DetachRolePolicy(ctx, &iam.DetachRolePolicyInput{
+			RoleName:  aws.String(roleName),
+			PolicyArn: aws.String(arn),
+		})
+		if err != nil && !isResourceNotFound(err) {
+			return fmt.Errorf("failed to detach policy %s from IAM role %s: %w", arn, roleName, err)
+		}
+	}
+
+	for _, arn := range desired {
+		if current[arn] {
+			continue
+		}
+		_, err := client.AttachRolePolicy(ctx, &iam.AttachRolePolicyInput{
+			RoleName:  aws.String(roleName),
+			PolicyArn: aws.String(arn),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to attach policy %s to IAM role %s: %w", arn, roleName, err)
+		}
+	}
+
+	return nil
+}
+
+// syncRoleInlinePolicies puts the desired inline policies on a role and deletes any others
+func syncRoleInlinePolicies(ctx context.Context, client *iam.Client, roleName string, desired map[string]string) error {
+	current, err := listRoleInlinePolicies(ctx, client, roleName)
+	if err != nil {
+		return err
+	}
+
+	for name := range current {
+		if _, exists := desired[name]; exists {
+			continue
+		}
+		_, err := client.DeleteRolePolicy(ctx, &iam.DeleteRolePolicyInput{
+			RoleName:   aws.String(roleName),
+			PolicyName: aws.String(name),
+		})
+		if err != nil && !isResourceNotFound(err) {
+			return fmt.Errorf("failed to delete inline policy %s from IAM role %s: %w", name, roleName, err)
+		}
+	}
+
+	for name, document := range desired {
+		if existing, exists := current[name]; exists && equivalentPolicyDocuments(existing, document) {
+			continue
+		}
+		_, err := client.PutRolePolicy(ctx, &iam.PutRolePolicyInput{
+			RoleName:       aws.String(roleName),
+			PolicyName:     aws.String(name),
+			PolicyDocument: aws.String(document),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to put inline policy %s on IAM role %s: %w", name, roleName, err)
+		}
+	}
+
+	return nil
+}
+
+// removeAllRolePolicies detaches every managed policy and deletes every inline policy of a
+// role; IAM refuses to delete a role that still has policies
+func removeAllRolePolicies(ctx context.Context, client *iam.Client, roleName string) error {
+	if err := syncRoleManagedPolicies(ctx, client, roleName, nil); err != nil {
+		return err
+	}
+	return syncRoleInlinePolicies(ctx, client, roleName, nil)
+}
+
+// orderLike returns values ordered as they appear in reference, followed by any values not
+// in reference in sorted order
+func orderLike(values []string, reference []string) []interface{} {
+	remaining := make(map[string]bool, len(values))
+	for _, value := range values {
+		remaining[value] = true
+	}
+
+	ordered := make([]interface{}, 0, len(values))
+	for _, value := range reference {
+		if remaining[value] {
+			ordered = append(ordered, value)
+			delete(remaining, value)
+		}
+	}
+
+	rest := make([]string, 0, len(remaining))
+	for value := range remaining {
+		rest = append(rest, value)
+	}
+	sort.Strings(rest)
+	for _, value := range rest {
+		ordered = append(ordered, value)
+	}
+
+	return ordered
+}
+
+// equivalentPolicyDocuments reports whether two JSON policy documents are the same once parsed
+func equivalentPolicyDocuments(a, b string) bool {
+	var docA, docB interface{}
+	if err := json.Unmarshal([]byte(a), &docA); err != nil {
+		return a == b
+	}
+	if err := json.Unmarshal([]byte(b), &docB); err != nil {
+		return a == b
+	}
+	return reflect.DeepEqual(docA, docB)
+}
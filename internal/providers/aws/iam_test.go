@@ -1,10 +1,20 @@
 package aws
 
 import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestValidateIAMUser(t *testing.T) {
@@ -208,3 +218,172 @@ func TestValidateIAMPolicy(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateIAMRole_PolicyAttachments(t *testing.T) {
+	provider := NewProvider()
+	assumeRolePolicy := `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Principal": {"Service": "lambda.amazonaws.com"}, "Action": "sts:AssumeRole"}]}`
+
+	tests := []struct {
+		name       string
+		properties map[string]interface{}
+		wantErr    string
+	}{
+		{
+			name: "managed and inline policies",
+			properties: map[string]interface{}{
+				"managed_policy_arns": []interface{}{"arn:aws:iam::aws:policy/service-role/AWSLambdaBasicExecutionRole"},
+				"inline_policies": map[string]interface{}{
+					"read-bucket": `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"}]}`,
+				},
+			},
+		},
+		{
+			name: "managed policy that isn't an ARN",
+			properties: map[string]interface{}{
+				"managed_policy_arns": []interface{}{"AWSLambdaBasicExecutionRole"},
+			},
+			wantErr: "managed_policy_arns must contain policy ARNs",
+		},
+		{
+			name: "managed policies that aren't a list",
+			properties: map[string]interface{}{
+				"managed_policy_arns": "arn:aws:iam::aws:policy/ReadOnlyAccess",
+			},
+			wantErr: "managed_policy_arns must be a list",
+		},
+		{
+			name: "inline policy with invalid JSON",
+			properties: map[string]interface{}{
+				"inline_policies": map[string]interface{}{"broken": "{"},
+			},
+			wantErr: "invalid JSON in inline policy broken",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.properties["assume_role_policy"] = assumeRolePolicy
+			err := provider.ValidateResource(config.ResourceInstance{
+				ID:         "aws:iam:role.app",
+				Kind:       "aws:iam:role",
+				Name:       "app",
+				Properties: tt.properties,
+			})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestOrderLike(t *testing.T) {
+	attached := []string{"arn:a", "arn:b", "arn:c"}
+
+	assert.Equal(t, []interface{}{"arn:c", "arn:a", "arn:b"}, orderLike(attached, []string{"arn:c", "arn:a", "arn:b"}))
+	assert.Equal(t, []interface{}{"arn:b", "arn:a", "arn:c"}, orderLike(attached, []string{"arn:b"}))
+	assert.Equal(t, []interface{}{"arn:a"}, orderLike([]string{"arn:a"}, []string{"arn:z", "arn:a"}))
+}
+
+func TestEquivalentPolicyDocuments(t *testing.T) {
+	compact := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`
+	indented := `{
+		"Statement": [{"Resource": "*", "Action": "s3:GetObject", "Effect": "Allow"}],
+		"Version": "2012-10-17"
+	}`
+
+	assert.True(t, equivalentPolicyDocuments(compact, indented))
+	assert.False(t, equivalentPolicyDocuments(compact, `{"Version":"2012-10-17","Statement":[]}`))
+}
+
+// countingSTS answers GetCallerIdentity with a fixed account and counts the calls
+type countingSTS struct {
+	calls int32
+}
+
+func (c *countingSTS) GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error) {
+	atomic.AddInt32(&c.calls, 1)
+	return &sts.GetCallerIdentityOutput{Account: aws.String("123456789012")}, nil
+}
+
+func TestGetAccountID_CachesCallerIdentity(t *testing.T) {
+	fake := &countingSTS{}
+	provider := &Provider{stsClient: fake}
+
+	// Policy operations for many resources run concurrently during commit
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			accountID, err := provider.getAccountID(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, "123456789012", accountID)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fake.calls))
+}
+
+func TestGetAccountID_ResetOnInitialize(t *testing.T) {
+	provider := NewProvider()
+	provider.accountID = "111111111111"
+
+	require.NoError(t, provider.Initialize(context.Background(), map[string]interface{}{"region": "us-east-1"}))
+
+	assert.Empty(t, provider.accountID)
+}
+
+// fakePolicyVersions holds a policy's versions in memory
+type fakePolicyVersions struct {
+	versions []iamtypes.PolicyVersion
+	deleted  []string
+}
+
+func (f *fakePolicyVersions) ListPolicyVersions(ctx context.Context, params *iam.ListPolicyVersionsInput, optFns ...func(*iam.Options)) (*iam.ListPolicyVersionsOutput, error) {
+	return &iam.ListPolicyVersionsOutput{Versions: f.versions}, nil
+}
+
+func (f *fakePolicyVersions) DeletePolicyVersion(ctx context.Context, params *iam.DeletePolicyVersionInput, optFns ...func(*iam.Options)) (*iam.DeletePolicyVersionOutput, error) {
+	f.deleted = append(f.deleted, aws.ToString(params.VersionId))
+	return &iam.DeletePolicyVersionOutput{}, nil
+}
+
+func policyVersions(count int, defaultVersion string) []iamtypes.PolicyVersion {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	versions := make([]iamtypes.PolicyVersion, 0, count)
+	// Listed newest first, as IAM does
+	for i := count; i >= 1; i-- {
+		versionID := fmt.Sprintf("v%d", i)
+		versions = append(versions, iamtypes.PolicyVersion{
+			VersionId:        aws.String(versionID),
+			IsDefaultVersion: versionID == defaultVersion,
+			CreateDate:       aws.Time(created.AddDate(0, 0, i)),
+		})
+	}
+	return versions
+}
+
+func TestPrunePolicyVersions(t *testing.T) {
+	const policyArn = "arn:aws:iam::123456789012:policy/app"
+
+	t.Run("below the limit", func(t *testing.T) {
+		fake := &fakePolicyVersions{versions: policyVersions(4, "v4")}
+		require.NoError(t, prunePolicyVersions(context.Background(), fake, policyArn))
+		assert.Empty(t, fake.deleted)
+	})
+
+	t.Run("at the limit deletes the oldest version", func(t *testing.T) {
+		fake := &fakePolicyVersions{versions: policyVersions(maxPolicyVersions, "v5")}
+		require.NoError(t, prunePolicyVersions(context.Background(), fake, policyArn))
+		assert.Equal(t, []string{"v1"}, fake.deleted)
+	})
+
+	t.Run("never deletes the default version", func(t *testing.T) {
+		fake := &fakePolicyVersions{versions: policyVersions(maxPolicyVersions, "v1")}
+		require.NoError(t, prunePolicyVersions(context.Background(), fake, policyArn))
+		assert.Equal(t, []string{"v2"}, fake.deleted)
+	})
+}
@@ -0,0 +1,137 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// managedTagKey marks resources that were adopted with import
+const managedTagKey = "runestone:managed"
+
+// ec2ImportAPI is the subset of the EC2 API used to adopt existing resources
+type ec2ImportAPI interface {
+	DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error)
+	DescribeVpcs(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error)
+	DescribeSubnets(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error)
+	DescribeInternetGateways(ctx context.Context, params *ec2.DescribeInternetGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInternetGatewaysOutput, error)
+	DescribeSecurityGroups(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error)
+	DescribeVolumes(ctx context.Context, params *ec2.DescribeVolumesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error)
+	CreateTags(ctx context.Context, params *ec2.CreateTagsInput, optFns ...func(*ec2.Options)) (*ec2.CreateTagsOutput, error)
+}
+
+// Import adopts an existing resource by its cloud ID. The resource is tagged with the Name
+// the configuration expects and the runestone:managed marker, and then looked up again
+// to confirm that state lookups now find it. The returned properties are the resource's
+// state without its ID and other computed properties.
+func (p *Provider) Import(ctx context.Context, instance config.ResourceInstance, cloudID string) (map[string]interface{}, error) {
+	idProperty, supported := ec2IDProperties[instance.Kind]
+	if !supported {
+		return nil, fmt.Errorf("import is not supported for %s: resources of this kind are found by name, so name the resource after the existing one instead", instance.Kind)
+	}
+
+	// Refuse to tag a second resource with a Name that already matches another one
+	state, err := p.GetCurrentState(ctx, instance)
+	if err != nil {
+		return nil, err
+	}
+	if state != nil && state[idProperty] != cloudID {
+		return nil, fmt.Errorf("%s already matches %s; remove its Name tag or rename the resource before importing %s", instance.ID, state[idProperty], cloudID)
+	}
+
+	if err := importEC2Resource(ctx, p.ec2Client, instance, cloudID); err != nil {
+		return nil, err
+	}
+
+	state, err = p.GetCurrentState(ctx, instance)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify import of %s: %w", cloudID, err)
+	}
+	if state == nil || state[idProperty] != cloudID {
+		return nil, fmt.Errorf("imported %s, but %s still doesn't match it; check that no other resource has the Name tag %s", cloudID, instance.ID, instance.Name)
+	}
+
+	properties := make(map[string]interface{}, len(state))
+	for key, value := range state {
+		properties[key] = value
+	}
+	for _, key := range p.GetComputedFields(instance.Kind) {
+		delete(properties, key)
+	}
+
+	return properties, nil
+}
+
+// importEC2Resource checks that an EC2 resource exists and tags it so lookups by Name find it
+func importEC2Resource(ctx context.Context, client ec2ImportAPI, instance config.ResourceInstance, cloudID string) error {
+	found := false
+	switch instance.Kind {
+	case "aws:ec2:instance":
+		result, err := client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{InstanceIds: []string{cloudID}})
+		if err != nil {
+			return fmt.Errorf("failed to describe EC2 instance %s: %w", cloudID, err)
+		}
+		for _, reservation := range result.Reservations {
+			found = found || len(reservation.Instances) > 0
+		}
+	case "aws:ec2:vpc":
+		result, err := client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{VpcIds: []string{cloudID}})
+		if err != nil {
+			return fmt.Errorf("failed to describe VPC %s: %w", cloudID, err)
+		}
+		found = len(result.Vpcs) > 0
+	case "aws:ec2:subnet":
+		result, err := client.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{SubnetIds: []string{cloudID}})
+		if err != nil {
+			return fmt.Errorf("failed to describe subnet %s: %w", cloudID, err)
+		}
+		found = len(result.Subnets) > 0
+	case "aws:ec2:internet_gateway":
+		result, err := client.DescribeInternetGateways(ctx, &ec2.DescribeInternetGatewaysInput{InternetGatewayIds: []string{cloudID}})
+		if err != nil {
+			return fmt.Errorf("failed to describe internet gateway %s: %w", cloudID, err)
+		}
+		found = len(result.InternetGateways) > 0
+	case "aws:ec2:volume":
+		result, err := client.DescribeVolumes(ctx, &ec2.DescribeVolumesInput{VolumeIds: []string{cloudID}})
+		if err != nil {
+			return fmt.Errorf("failed to describe EBS volume %s: %w", cloudID, err)
+		}
+		found = len(result.Volumes) > 0
+	case "aws:ec2:security_group":
+		// Security groups are found by group name, which can't be changed
+		result, err := client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{GroupIds: []string{cloudID}})
+		if err != nil {
+			return fmt.Errorf("failed to describe security group %s: %w", cloudID, err)
+		}
+		if len(result.SecurityGroups) > 0 {
+			found = true
+			if groupName := aws.ToString(result.SecurityGroups[0].GroupName); groupName != instance.Name {
+				return fmt.Errorf("security group %s is named %s; security groups can't be renamed, so name the resource %s", cloudID, groupName, groupName)
+			}
+		}
+	default:
+		return fmt.Errorf("import is not supported for %s", instance.Kind)
+	}
+
+	if !found {
+		return fmt.Errorf("%s %s not found", instance.Kind, cloudID)
+	}
+
+	_, err := client.CreateTags(ctx, &ec2.CreateTagsInput{
+		Resources: []string{cloudID},
+		Tags: []types.Tag{
+			{Key: aws.String("Name"), Value: aws.String(instance.Name)},
+			{Key: aws.String(managedTagKey), Value: aws.String("true")},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to tag %s: %w", cloudID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEC2Import holds existing VPCs and security groups in memory and records tagging
+type fakeEC2Import struct {
+	vpcs           []types.Vpc
+	securityGroups []types.SecurityGroup
+	tagged         map[string]map[string]string
+}
+
+func (f *fakeEC2Import) DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	return &ec2.DescribeInstancesOutput{}, nil
+}
+
+func (f *fakeEC2Import) DescribeVpcs(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error) {
+	output := &ec2.DescribeVpcsOutput{}
+	for _, vpc := range f.vpcs {
+		for _, id := range params.VpcIds {
+			if aws.ToString(vpc.VpcId) == id {
+				output.Vpcs = append(output.Vpcs, vpc)
+			}
+		}
+	}
+	return output, nil
+}
+
+func (f *fakeEC2Import) DescribeSubnets(ctx context.Context, params *ec2.DescribeSubnetsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+	return &ec2.DescribeSubnetsOutput{}, nil
+}
+
+func (f *fakeEC2Import) DescribeInternetGateways(ctx context.Context, params *ec2.DescribeInternetGatewaysInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInternetGatewaysOutput, error) {
+	return &ec2.DescribeInternetGatewaysOutput{}, nil
+}
+
+func (f *fakeEC2Import) DescribeSecurityGroups(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
+	output := &ec2.DescribeSecurityGroupsOutput{}
+	for _, group := range f.securityGroups {
+		for _, id := range params.GroupIds {
+			if aws.ToString(group.GroupId) == id {
+				output.SecurityGroups = append(output.SecurityGroups, group)
+			}
+		}
+	}
+	return output, nil
+}
+
+func (f *fakeEC2Import) DescribeVolumes(ctx context.Context, params *ec2.DescribeVolumesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+	return &ec2.DescribeVolumesOutput{}, nil
+}
+
+func (f *fakeEC2Import) CreateTags(ctx context.Context, params *ec2.CreateTagsInput, optFns ...func(*ec2.Options)) (*ec2.CreateTagsOutput, error) {
+	if f.tagged == nil {
+		f.tagged = make(map[string]map[string]string)
+	}
+	for _, resource := range params.Resources {
+		if f.tagged[resource] == nil {
+			f.tagged[resource] = make(map[string]string)
+		}
+		for _, tag := range params.Tags {
+			f.tagged[resource][aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+	}
+	return &ec2.CreateTagsOutput{}, nil
+}
+
+func TestImportEC2Resource_TagsExistingVPC(t *testing.T) {
+	fake := &fakeEC2Import{vpcs: []types.Vpc{{VpcId: aws.String("vpc-0a1b2c3d")}}}
+
+	err := importEC2Resource(context.Background(), fake, config.ResourceInstance{
+		Kind: "aws:ec2:vpc",
+		Name: "main",
+	}, "vpc-0a1b2c3d")
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"Name":              "main",
+		"runestone:managed": "true",
+	}, fake.tagged["vpc-0a1b2c3d"])
+}
+
+func TestImportEC2Resource_MissingResource(t *testing.T) {
+	fake := &fakeEC2Import{}
+
+	err := importEC2Resource(context.Background(), fake, config.ResourceInstance{
+		Kind: "aws:ec2:vpc",
+		Name: "main",
+	}, "vpc-missing")
+
+	assert.ErrorContains(t, err, "aws:ec2:vpc vpc-missing not found")
+	assert.Empty(t, fake.tagged)
+}
+
+func TestImportEC2Resource_SecurityGroupNameMustMatch(t *testing.T) {
+	fake := &fakeEC2Import{securityGroups: []types.SecurityGroup{
+		{GroupId: aws.String("sg-0a1b2c3d"), GroupName: aws.String("legacy-web")},
+	}}
+
+	err := importEC2Resource(context.Background(), fake, config.ResourceInstance{
+		Kind: "aws:ec2:security_group",
+		Name: "web",
+	}, "sg-0a1b2c3d")
+
+	assert.ErrorContains(t, err, "name the resource legacy-web")
+	assert.Empty(t, fake.tagged)
+}
+
+func TestProvider_ImportUnsupportedKind(t *testing.T) {
+	provider := NewProvider()
+
+	_, err := provider.Import(context.Background(), config.ResourceInstance{
+		Kind: "aws:s3:bucket",
+		Name: "logs",
+	}, "logs")
+
+	assert.ErrorContains(t, err, "import is not supported for aws:s3:bucket")
+}
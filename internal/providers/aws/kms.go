@@ -0,0 +1,398 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+)
+
+// defaultKMSDeletionWindowDays is how long a deleted key can still be recovered when
+// deletion_window_in_days isn't set
+const defaultKMSDeletionWindowDays = 30
+
+// kmsKeyPolicyName is the only policy name KMS keys support
+const kmsKeyPolicyName = "default"
+
+// kmsKeyLookupAPI is the subset of the KMS API used to find the key created for a resource
+type kmsKeyLookupAPI interface {
+	ListKeys(ctx context.Context, params *kms.ListKeysInput, optFns ...func(*kms.Options)) (*kms.ListKeysOutput, error)
+	DescribeKey(ctx context.Context, params *kms.DescribeKeyInput, optFns ...func(*kms.Options)) (*kms.DescribeKeyOutput, error)
+	ListResourceTags(ctx context.Context, params *kms.ListResourceTagsInput, optFns ...func(*kms.Options)) (*kms.ListResourceTagsOutput, error)
+}
+
+// validateKMSKey validates KMS key configuration
+func (p *Provider) validateKMSKey(instance config.ResourceInstance) error {
+	if instance.Name == "" {
+		return fmt.Errorf("KMS key name cannot be empty")
+	}
+
+	if descVal, exists := instance.Properties["description"]; exists {
+		if _, ok := descVal.(string); !ok {
+			return fmt.Errorf("description must be a string")
+		}
+	}
+
+	if policyVal, exists := instance.Properties["key_policy"]; exists {
+		policy, ok := policyVal.(string)
+		if !ok {
+			return fmt.Errorf("key_policy must be a string")
+		}
+
+		var policyDoc interface{}
+		if err := json.Unmarshal([]byte(policy), &policyDoc); err != nil {
+			return fmt.Errorf("invalid key_policy JSON: %w", err)
+		}
+	}
+
+	if rotationVal, exists := instance.Properties["enable_key_rotation"]; exists {
+		if _, ok := rotationVal.(bool); !ok {
+			return fmt.Errorf("enable_key_rotation must be a boolean")
+		}
+	}
+
+	if windowVal, exists := instance.Properties["deletion_window_in_days"]; exists {
+		window, ok := windowVal.(int)
+		if !ok || window < 7 || window > 30 {
+			return fmt.Errorf("deletion_window_in_days must be an integer between 7 and 30")
+		}
+	}
+
+	if tagsVal, exists := instance.Properties["tags"]; exists {
+		if _, ok := tagsVal.(map[string]interface{}); !ok {
+			return fmt.Errorf("tags must be a map")
+		}
+	}
+
+	return nil
+}
+
+// findKMSKey returns the metadata of the customer managed key tagged with a resource ID,
+// or nil if there is none. Keys pending deletion are ignored, so a deleted key's
+// resource can be created again. knownKeyID, when set, is checked first, and the account's
+// keys are only searched when it no longer matches.
+func findKMSKey(ctx context.Context, client kmsKeyLookupAPI, resourceID, knownKeyID string) (*kmstypes.KeyMetadata, error) {
+	if knownKeyID != "" {
+		// A key that has since been deleted can't be read at all, which isn't an error here
+		if metadata, err := matchKMSKey(ctx, client, knownKeyID, resourceID); err == nil && metadata != nil {
+			return metadata, nil
+		}
+	}
+
+	paginator := kms.NewListKeysPaginator(client, &kms.ListKeysInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list KMS keys: %w", err)
+		}
+
+		for _, key := range page.Keys {
+			metadata, err := matchKMSKey(ctx, client, aws.ToString(key.KeyId), resourceID)
+			if err != nil {
+				return nil, err
+			}
+			if metadata != nil {
+				return metadata, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// matchKMSKey returns the metadata of a key if it is a customer managed key tagged with
+// resourceID and not pending deletion, or nil otherwise. Tags are checked first, so only
+// keys created for the resource are described.
+func matchKMSKey(ctx context.Context, client kmsKeyLookupAPI, keyID, resourceID string) (*kmstypes.KeyMetadata, error) {
+	tags, err := kmsKeyTags(ctx, client, keyID)
+	if err != nil {
+		return nil, err
+	}
+	if tags[resourceIDTagKey] != resourceID {
+		return nil, nil
+	}
+
+	described, err := client.DescribeKey(ctx, &kms.DescribeKeyInput{KeyId: aws.String(keyID)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe KMS key %s: %w", keyID, err)
+	}
+	metadata := described.KeyMetadata
+	if metadata.KeyManager != kmstypes.KeyManagerTypeCustomer || metadata.KeyState == kmstypes.KeyStatePendingDeletion {
+		return nil, nil
+	}
+	return metadata, nil
+}
+
+// lookupKMSKey finds the key of a KMS key resource, starting from the key found for it
+// last time, and remembers the result
+func (p *Provider) lookupKMSKey(ctx context.Context, client kmsKeyLookupAPI, resourceID string) (*kmstypes.KeyMetadata, error) {
+	p.kmsKeysMu.Lock()
+	knownKeyID := p.kmsKeyIDs[resourceID]
+	p.kmsKeysMu.Unlock()
+
+	metadata, err := findKMSKey(ctx, client, resourceID, knownKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if metadata == nil {
+		p.rememberKMSKey(resourceID, "")
+	} else {
+		p.rememberKMSKey(resourceID, aws.ToString(metadata.KeyId))
+	}
+	return metadata, nil
+}
+
+// rememberKMSKey records the key of a KMS key resource, or forgets it when keyID is empty
+func (p *Provider) rememberKMSKey(resourceID, keyID string) {
+	p.kmsKeysMu.Lock()
+	defer p.kmsKeysMu.Unlock()
+
+	if keyID == "" {
+		delete(p.kmsKeyIDs, resourceID)
+		return
+	}
+	if p.kmsKeyIDs == nil {
+		p.kmsKeyIDs = make(map[string]string)
+	}
+	p.kmsKeyIDs[resourceID] = keyID
+}
+
+// kmsKeyTags returns all tags of a KMS key
+func kmsKeyTags(ctx context.Context, client kmsKeyLookupAPI, keyID string) (map[string]string, error) {
+	tags := make(map[string]string)
+	input := &kms.ListResourceTagsInput{KeyId: aws.String(keyID)}
+	for {
+		result, err := client.ListResourceTags(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tags for KMS key %s: %w", keyID, err)
+		}
+		for _, tag := range result.Tags {
+			tags[aws.ToString(tag.TagKey)] = aws.ToString(tag.TagValue)
+		}
+		if !result.Truncated {
+			return tags, nil
+		}
+		input.Marker = result.NextMarker
+	}
+}
+
+// getKMSKeyState retrieves the current state of a KMS key. KMS keys have no name, so the
+// key is found by the runestone:id tag written when it was created. The key policy and
+// rotation setting are reported only when configured, since AWS fills in defaults for them.
+func (p *Provider) getKMSKeyState(ctx context.Context, instance config.ResourceInstance) (map[string]interface{}, error) {
+	client := kms.NewFromConfig(p.awsConfig)
+
+	metadata, err := p.lookupKMSKey(ctx, client, instance.ID)
+	if err != nil {
+		return nil, err
+	}
+	if metadata == nil {
+		return nil, nil // Key doesn't exist
+	}
+	keyID := aws.ToString(metadata.KeyId)
+
+	state := map[string]interface{}{
+		"key_id":    keyID,
+		"arn":       aws.ToString(metadata.Arn),
+		"key_state": string(metadata.KeyState),
+	}
+	if description := aws.ToString(metadata.Description); description != "" {
+		state["description"] = description
+	}
+
+	if desiredPolicy, configured := instance.Properties["key_policy"]; configured {
+		policyResult, err := client.GetKeyPolicy(ctx, &kms.GetKeyPolicyInput{
+			KeyId:      aws.String(keyID),
+			PolicyName: aws.String(kmsKeyPolicyName),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get policy of KMS key %s: %w", instance.Name, err)
+		}
+		state["key_policy"] = policyDocumentState(aws.ToString(policyResult.Policy), desiredPolicy)
+	}
+
+	if _, configured := instance.Properties["enable_key_rotation"]; configured {
+		rotation, err := client.GetKeyRotationStatus(ctx, &kms.GetKeyRotationStatusInput{
+			KeyId: aws.String(keyID),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rotation status of KMS key %s: %w", instance.Name, err)
+		}
+		state["enable_key_rotation"] = rotation.KeyRotationEnabled
+	}
+
+	// deletion_window_in_days only affects deletion, so it's reported as configured
+	if window, exists := instance.Properties["deletion_window_in_days"]; exists {
+		state["deletion_window_in_days"] = window
+	}
+
+	tags, err := kmsKeyTags(ctx, client, keyID)
+	if err != nil {
+		return nil, err
+	}
+	stateTags := make(map[string]interface{}, len(tags))
+	for key, value := range tags {
+		if key != resourceIDTagKey {
+			stateTags[key] = value
+		}
+	}
+	if len(stateTags) > 0 {
+		state["tags"] = stateTags
+	}
+
+	return state, nil
+}
+
+// createKMSKey creates a symmetric customer managed KMS key, tagged with its resource ID
+// so it can be found again, and enables rotation when requested
+func (p *Provider) createKMSKey(ctx context.Context, instance config.ResourceInstance) error {
+	client := kms.NewFromConfig(p.awsConfig)
+
+	input := &kms.CreateKeyInput{
+		Tags: kmsTags(instance),
+	}
+	if description, ok := instance.Properties["description"].(string); ok {
+		input.Description = aws.String(description)
+	}
+	if policy, ok := instance.Properties["key_policy"].(string); ok {
+		input.Policy = aws.String(policy)
+	}
+
+	result, err := client.CreateKey(ctx, input)
+	if err != nil {
+		return fmt.Errorf("failed to create KMS key %s: %w", instance.Name, err)
+	}
+	p.rememberKMSKey(instance.ID, aws.ToString(result.KeyMetadata.KeyId))
+
+	if rotate, _ := instance.Properties["enable_key_rotation"].(bool); rotate {
+		_, err := client.EnableKeyRotation(ctx, &kms.EnableKeyRotationInput{
+			KeyId: result.KeyMetadata.KeyId,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to enable rotation for KMS key %s: %w", instance.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// updateKMSKey brings the description, key policy, rotation setting and tags of a KMS key
+// in line with the configuration
+func (p *Provider) updateKMSKey(ctx context.Context, instance config.ResourceInstance, currentState map[string]interface{}) error {
+	client := kms.NewFromConfig(p.awsConfig)
+
+	keyID, ok := currentState["key_id"].(string)
+	if !ok {
+		return fmt.Errorf("key_id not found in current state")
+	}
+
+	description, _ := instance.Properties["description"].(string)
+	if currentDescription, _ := currentState["description"].(string); description != currentDescription {
+		_, err := client.UpdateKeyDescription(ctx, &kms.UpdateKeyDescriptionInput{
+			KeyId:       aws.String(keyID),
+			Description: aws.String(description),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update description of KMS key %s: %w", instance.Name, err)
+		}
+	}
+
+	if policy, ok := instance.Properties["key_policy"].(string); ok && policy != currentState["key_policy"] {
+		_, err := client.PutKeyPolicy(ctx, &kms.PutKeyPolicyInput{
+			KeyId:      aws.String(keyID),
+			PolicyName: aws.String(kmsKeyPolicyName),
+			Policy:     aws.String(policy),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update policy of KMS key %s: %w", instance.Name, err)
+		}
+	}
+
+	if rotate, ok := instance.Properties["enable_key_rotation"].(bool); ok && rotate != currentState["enable_key_rotation"] {
+		var err error
+		if rotate {
+			_, err = client.EnableKeyRotation(ctx, &kms.EnableKeyRotationInput{KeyId: aws.String(keyID)})
+		} else {
+			_, err = client.DisableKeyRotation(ctx, &kms.DisableKeyRotationInput{KeyId: aws.String(keyID)})
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update rotation for KMS key %s: %w", instance.Name, err)
+		}
+	}
+
+	if _, err := client.TagResource(ctx, &kms.TagResourceInput{
+		KeyId: aws.String(keyID),
+		Tags:  kmsTags(instance),
+	}); err != nil {
+		return fmt.Errorf("failed to update tags for KMS key %s: %w", instance.Name, err)
+	}
+
+	currentTags, _ := currentState["tags"].(map[string]interface{})
+	if removed := p.removedTagKeys(currentTags, stringTags(instance.Properties)); len(removed) > 0 {
+		_, err := client.UntagResource(ctx, &kms.UntagResourceInput{
+			KeyId:   aws.String(keyID),
+			TagKeys: removed,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to remove tags from KMS key %s: %w", instance.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// deleteKMSKey schedules a KMS key for deletion. KMS doesn't delete keys immediately: the
+// key is disabled and can be recovered with CancelKeyDeletion until the reported date.
+func (p *Provider) deleteKMSKey(ctx context.Context, instance config.ResourceInstance) error {
+	client := kms.NewFromConfig(p.awsConfig)
+
+	metadata, err := p.lookupKMSKey(ctx, client, instance.ID)
+	if err != nil {
+		return err
+	}
+	if metadata == nil {
+		return nil // Key already deleted or scheduled for deletion
+	}
+
+	window := defaultKMSDeletionWindowDays
+	if days, ok := instance.Properties["deletion_window_in_days"].(int); ok {
+		window = days
+	}
+
+	result, err := client.ScheduleKeyDeletion(ctx, &kms.ScheduleKeyDeletionInput{
+		KeyId:               metadata.KeyId,
+		PendingWindowInDays: aws.Int32(int32(window)),
+	})
+	if err != nil {
+		if isResourceNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to schedule deletion of KMS key %s: %w", instance.Name, err)
+	}
+	p.rememberKMSKey(instance.ID, "")
+
+	deletion := slog.Int("pending_window_days", window)
+	if result.DeletionDate != nil {
+		deletion = slog.String("deletion_date", result.DeletionDate.Format("2006-01-02T15:04:05Z"))
+	}
+	p.log().Info("KMS key scheduled for deletion; it can be recovered until then",
+		"resource", instance.ID, "key_id", aws.ToString(metadata.KeyId), deletion)
+
+	return nil
+}
+
+// kmsTags returns the tags to set on a KMS key, including the resource ID tag that
+// identifies it
+func kmsTags(instance config.ResourceInstance) []kmstypes.Tag {
+	tags := []kmstypes.Tag{{TagKey: aws.String(resourceIDTagKey), TagValue: aws.String(instance.ID)}}
+	for key, value := range stringTags(instance.Properties) {
+		tags = append(tags, kmstypes.Tag{TagKey: aws.String(key), TagValue: aws.String(value)})
+	}
+	return tags
+}
@@ -0,0 +1,133 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+)
+
+// kmsAliasNamePattern matches the alias names KMS accepts
+var kmsAliasNamePattern = regexp.MustCompile(`^alias/[a-zA-Z0-9/_-]{1,250}$`)
+
+// validateKMSAlias validates KMS alias configuration
+func (p *Provider) validateKMSAlias(instance config.ResourceInstance) error {
+	if instance.Name == "" {
+		return fmt.Errorf("KMS alias name cannot be empty")
+	}
+
+	if !strings.HasPrefix(instance.Name, "alias/") {
+		return fmt.Errorf("invalid KMS alias name '%s': must start with alias/", instance.Name)
+	}
+	if strings.HasPrefix(instance.Name, "alias/aws/") {
+		return fmt.Errorf("invalid KMS alias name '%s': alias/aws/ is reserved for AWS managed keys", instance.Name)
+	}
+	if !kmsAliasNamePattern.MatchString(instance.Name) {
+		return fmt.Errorf("invalid KMS alias name '%s': use up to 256 letters, digits and '/_-'", instance.Name)
+	}
+
+	if target, ok := instance.Properties["target_key_id"].(string); !ok || target == "" {
+		return fmt.Errorf("target_key_id is required for KMS alias")
+	}
+
+	return nil
+}
+
+// findKMSAlias returns the alias with the given name, or nil if it doesn't exist
+func findKMSAlias(ctx context.Context, client kms.ListAliasesAPIClient, aliasName string) (*kmstypes.AliasListEntry, error) {
+	paginator := kms.NewListAliasesPaginator(client, &kms.ListAliasesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list KMS aliases: %w", err)
+		}
+
+		for _, alias := range page.Aliases {
+			if aws.ToString(alias.AliasName) == aliasName {
+				return &alias, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// getKMSAliasState retrieves the current state of a KMS alias
+func (p *Provider) getKMSAliasState(ctx context.Context, instance config.ResourceInstance) (map[string]interface{}, error) {
+	client := kms.NewFromConfig(p.awsConfig)
+
+	alias, err := findKMSAlias(ctx, client, instance.Name)
+	if err != nil {
+		return nil, err
+	}
+	if alias == nil {
+		return nil, nil // Alias doesn't exist
+	}
+
+	return map[string]interface{}{
+		"alias_name":    instance.Name,
+		"alias_arn":     aws.ToString(alias.AliasArn),
+		"target_key_id": kmsTargetKeyState(aws.ToString(alias.TargetKeyId), instance.Properties["target_key_id"]),
+	}, nil
+}
+
+// kmsTargetKeyState returns the target key to report in an alias's state. ListAliases
+// returns the key ID, so a configured key ARN is reported when it names the same key.
+func kmsTargetKeyState(liveKeyID string, desired interface{}) string {
+	if desiredKey, ok := desired.(string); ok && strings.HasPrefix(desiredKey, "arn:") && strings.HasSuffix(desiredKey, ":key/"+liveKeyID) {
+		return desiredKey
+	}
+	return liveKeyID
+}
+
+// createKMSAlias creates an alias pointing at a KMS key
+func (p *Provider) createKMSAlias(ctx context.Context, instance config.ResourceInstance) error {
+	client := kms.NewFromConfig(p.awsConfig)
+
+	_, err := client.CreateAlias(ctx, &kms.CreateAliasInput{
+		AliasName:   aws.String(instance.Name),
+		TargetKeyId: aws.String(instance.Properties["target_key_id"].(string)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create KMS alias %s: %w", instance.Name, err)
+	}
+
+	return nil
+}
+
+// updateKMSAlias points an alias at the configured key
+func (p *Provider) updateKMSAlias(ctx context.Context, instance config.ResourceInstance) error {
+	client := kms.NewFromConfig(p.awsConfig)
+
+	_, err := client.UpdateAlias(ctx, &kms.UpdateAliasInput{
+		AliasName:   aws.String(instance.Name),
+		TargetKeyId: aws.String(instance.Properties["target_key_id"].(string)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update KMS alias %s: %w", instance.Name, err)
+	}
+
+	return nil
+}
+
+// deleteKMSAlias deletes an alias, leaving the key it points at in place
+func (p *Provider) deleteKMSAlias(ctx context.Context, instance config.ResourceInstance) error {
+	client := kms.NewFromConfig(p.awsConfig)
+
+	_, err := client.DeleteAlias(ctx, &kms.DeleteAliasInput{
+		AliasName: aws.String(instance.Name),
+	})
+	if err != nil {
+		if isResourceNotFound(err) {
+			return nil // Alias already deleted
+		}
+		return fmt.Errorf("failed to delete KMS alias %s: %w", instance.Name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKMSAliases serves aliases one page at a time
+type fakeKMSAliases struct {
+	pages [][]kmstypes.AliasListEntry
+}
+
+func (f *fakeKMSAliases) ListAliases(ctx context.Context, params *kms.ListAliasesInput, optFns ...func(*kms.Options)) (*kms.ListAliasesOutput, error) {
+	page := 0
+	if params.Marker != nil {
+		fmt.Sscanf(aws.ToString(params.Marker), "page-%d", &page)
+	}
+	output := &kms.ListAliasesOutput{Aliases: f.pages[page]}
+	if page+1 < len(f.pages) {
+		output.Truncated = true
+		output.NextMarker = aws.String(fmt.Sprintf("page-%d", page+1))
+	}
+	return output, nil
+}
+
+func TestFindKMSAlias_SearchesAllPages(t *testing.T) {
+	fake := &fakeKMSAliases{pages: [][]kmstypes.AliasListEntry{
+		{{AliasName: aws.String("alias/aws/s3"), TargetKeyId: aws.String("aws-key")}},
+		{{AliasName: aws.String("alias/app-data"), TargetKeyId: aws.String("1234abcd")}},
+	}}
+
+	alias, err := findKMSAlias(context.Background(), fake, "alias/app-data")
+	require.NoError(t, err)
+	require.NotNil(t, alias)
+	assert.Equal(t, "1234abcd", aws.ToString(alias.TargetKeyId))
+
+	alias, err = findKMSAlias(context.Background(), fake, "alias/missing")
+	require.NoError(t, err)
+	assert.Nil(t, alias)
+}
+
+func TestKMSTargetKeyState(t *testing.T) {
+	arn := "arn:aws:kms:us-east-1:123456789012:key/1234abcd"
+
+	assert.Equal(t, arn, kmsTargetKeyState("1234abcd", arn))
+	assert.Equal(t, "1234abcd", kmsTargetKeyState("1234abcd", "1234abcd"))
+	assert.Equal(t, "5678efgh", kmsTargetKeyState("5678efgh", arn))
+}
+
+func TestValidateKMSAlias(t *testing.T) {
+	provider := NewProvider()
+
+	tests := []struct {
+		name    string
+		alias   string
+		target  interface{}
+		wantErr string
+	}{
+		{name: "valid alias", alias: "alias/app-data", target: "${aws:kms:key.app-data.key_id}"},
+		{name: "missing prefix", alias: "app-data", target: "1234abcd", wantErr: "must start with alias/"},
+		{name: "reserved namespace", alias: "alias/aws/s3", target: "1234abcd", wantErr: "reserved for AWS managed keys"},
+		{name: "invalid characters", alias: "alias/app data", target: "1234abcd", wantErr: "invalid KMS alias name"},
+		{name: "missing target", alias: "alias/app-data", wantErr: "target_key_id is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			properties := map[string]interface{}{}
+			if tt.target != nil {
+				properties["target_key_id"] = tt.target
+			}
+			err := provider.ValidateResource(config.ResourceInstance{
+				ID:         "aws:kms:alias." + tt.alias,
+				Kind:       "aws:kms:alias",
+				Name:       tt.alias,
+				Properties: properties,
+			})
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
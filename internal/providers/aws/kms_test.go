@@ -0,0 +1,201 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKMSKeys serves key metadata and tags for a fixed set of keys, counting the calls
+// that list and describe keys
+type fakeKMSKeys struct {
+	keys      []kmstypes.KeyMetadata
+	tags      map[string]map[string]string
+	lists     int
+	describes int
+}
+
+func (f *fakeKMSKeys) ListKeys(ctx context.Context, params *kms.ListKeysInput, optFns ...func(*kms.Options)) (*kms.ListKeysOutput, error) {
+	f.lists++
+	output := &kms.ListKeysOutput{}
+	for _, key := range f.keys {
+		output.Keys = append(output.Keys, kmstypes.KeyListEntry{KeyId: key.KeyId})
+	}
+	return output, nil
+}
+
+func (f *fakeKMSKeys) DescribeKey(ctx context.Context, params *kms.DescribeKeyInput, optFns ...func(*kms.Options)) (*kms.DescribeKeyOutput, error) {
+	f.describes++
+	for _, key := range f.keys {
+		if aws.ToString(key.KeyId) == aws.ToString(params.KeyId) {
+			metadata := key
+			return &kms.DescribeKeyOutput{KeyMetadata: &metadata}, nil
+		}
+	}
+	return nil, assert.AnError
+}
+
+func (f *fakeKMSKeys) ListResourceTags(ctx context.Context, params *kms.ListResourceTagsInput, optFns ...func(*kms.Options)) (*kms.ListResourceTagsOutput, error) {
+	known := false
+	for _, key := range f.keys {
+		known = known || aws.ToString(key.KeyId) == aws.ToString(params.KeyId)
+	}
+	if !known {
+		return nil, &kmstypes.NotFoundException{Message: aws.String("key not found")}
+	}
+
+	output := &kms.ListResourceTagsOutput{}
+	for key, value := range f.tags[aws.ToString(params.KeyId)] {
+		output.Tags = append(output.Tags, kmstypes.Tag{TagKey: aws.String(key), TagValue: aws.String(value)})
+	}
+	return output, nil
+}
+
+func TestFindKMSKey(t *testing.T) {
+	fake := &fakeKMSKeys{
+		keys: []kmstypes.KeyMetadata{
+			{KeyId: aws.String("aws-managed"), KeyManager: kmstypes.KeyManagerTypeAws, KeyState: kmstypes.KeyStateEnabled},
+			{KeyId: aws.String("old"), KeyManager: kmstypes.KeyManagerTypeCustomer, KeyState: kmstypes.KeyStatePendingDeletion},
+			{KeyId: aws.String("other"), KeyManager: kmstypes.KeyManagerTypeCustomer, KeyState: kmstypes.KeyStateEnabled},
+			{KeyId: aws.String("current"), KeyManager: kmstypes.KeyManagerTypeCustomer, KeyState: kmstypes.KeyStateEnabled},
+		},
+		tags: map[string]map[string]string{
+			"old":     {resourceIDTagKey: "aws:kms:key.data"},
+			"other":   {resourceIDTagKey: "aws:kms:key.logs"},
+			"current": {resourceIDTagKey: "aws:kms:key.data", "Environment": "prod"},
+		},
+	}
+
+	metadata, err := findKMSKey(context.Background(), fake, "aws:kms:key.data", "")
+	require.NoError(t, err)
+	require.NotNil(t, metadata)
+	assert.Equal(t, "current", aws.ToString(metadata.KeyId))
+	// Only the keys tagged with the resource ID are described
+	assert.Equal(t, 2, fake.describes)
+
+	metadata, err = findKMSKey(context.Background(), fake, "aws:kms:key.missing", "")
+	require.NoError(t, err)
+	assert.Nil(t, metadata)
+}
+
+func TestFindKMSKey_KnownKey(t *testing.T) {
+	fake := &fakeKMSKeys{
+		keys: []kmstypes.KeyMetadata{
+			{KeyId: aws.String("other"), KeyManager: kmstypes.KeyManagerTypeCustomer, KeyState: kmstypes.KeyStateEnabled},
+			{KeyId: aws.String("current"), KeyManager: kmstypes.KeyManagerTypeCustomer, KeyState: kmstypes.KeyStateEnabled},
+		},
+		tags: map[string]map[string]string{
+			"other":   {resourceIDTagKey: "aws:kms:key.logs"},
+			"current": {resourceIDTagKey: "aws:kms:key.data"},
+		},
+	}
+
+	// A known key that still matches is used without searching the account
+	metadata, err := findKMSKey(context.Background(), fake, "aws:kms:key.data", "current")
+	require.NoError(t, err)
+	require.NotNil(t, metadata)
+	assert.Equal(t, "current", aws.ToString(metadata.KeyId))
+	assert.Equal(t, 0, fake.lists)
+	assert.Equal(t, 1, fake.describes)
+
+	// A key that no longer exists falls back to the search
+	metadata, err = findKMSKey(context.Background(), fake, "aws:kms:key.data", "deleted")
+	require.NoError(t, err)
+	require.NotNil(t, metadata)
+	assert.Equal(t, "current", aws.ToString(metadata.KeyId))
+	assert.Equal(t, 1, fake.lists)
+}
+
+func TestProvider_LookupKMSKey_RemembersKey(t *testing.T) {
+	fake := &fakeKMSKeys{
+		keys: []kmstypes.KeyMetadata{
+			{KeyId: aws.String("current"), KeyManager: kmstypes.KeyManagerTypeCustomer, KeyState: kmstypes.KeyStateEnabled},
+		},
+		tags: map[string]map[string]string{"current": {resourceIDTagKey: "aws:kms:key.data"}},
+	}
+	provider := NewProvider()
+
+	for i := 0; i < 3; i++ {
+		metadata, err := provider.lookupKMSKey(context.Background(), fake, "aws:kms:key.data")
+		require.NoError(t, err)
+		require.NotNil(t, metadata)
+	}
+	assert.Equal(t, 1, fake.lists)
+}
+
+func TestKMSTags_IncludeResourceID(t *testing.T) {
+	instance := config.ResourceInstance{
+		ID:         "aws:kms:key.data",
+		Kind:       "aws:kms:key",
+		Name:       "data",
+		Properties: map[string]interface{}{"tags": map[string]interface{}{"Environment": "prod"}},
+	}
+
+	tags := make(map[string]string)
+	for _, tag := range kmsTags(instance) {
+		tags[aws.ToString(tag.TagKey)] = aws.ToString(tag.TagValue)
+	}
+	assert.Equal(t, map[string]string{resourceIDTagKey: "aws:kms:key.data", "Environment": "prod"}, tags)
+}
+
+func TestValidateKMSKey(t *testing.T) {
+	provider := NewProvider()
+
+	tests := []struct {
+		name       string
+		properties map[string]interface{}
+		wantErr    string
+	}{
+		{
+			name: "valid key",
+			properties: map[string]interface{}{
+				"description":             "Encrypts application data",
+				"key_policy":              `{"Version":"2012-10-17","Statement":[]}`,
+				"enable_key_rotation":     true,
+				"deletion_window_in_days": 7,
+			},
+		},
+		{
+			name:       "no properties",
+			properties: map[string]interface{}{},
+		},
+		{
+			name:       "invalid policy JSON",
+			properties: map[string]interface{}{"key_policy": "{not json"},
+			wantErr:    "invalid key_policy JSON",
+		},
+		{
+			name:       "rotation not a boolean",
+			properties: map[string]interface{}{"enable_key_rotation": "yes"},
+			wantErr:    "enable_key_rotation must be a boolean",
+		},
+		{
+			name:       "deletion window too short",
+			properties: map[string]interface{}{"deletion_window_in_days": 3},
+			wantErr:    "deletion_window_in_days must be an integer between 7 and 30",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := provider.ValidateResource(config.ResourceInstance{
+				ID:         "aws:kms:key.data",
+				Kind:       "aws:kms:key",
+				Name:       "data",
+				Properties: tt.properties,
+			})
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,15 +2,25 @@ package aws
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/lambda"
 	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/ataiva-software/runestone/internal/config"
 )
 
+// lambdaUpdateWaitTimeout bounds how long a code update waits for a configuration update
+// to finish
+const lambdaUpdateWaitTimeout = 5 * time.Minute
+
 var validRuntimes = map[string]bool{
 	"nodejs18.x":   true,
 	"nodejs20.x":   true,
@@ -81,9 +91,141 @@ func (p *Provider) validateLambdaFunction(instance config.ResourceInstance) erro
 		return fmt.Errorf("invalid role ARN format: %s", role)
 	}
 
+	return validateLambdaCodeSource(instance.Properties)
+}
+
+// lambdaCode is a function's deployment package, taken from exactly one of code_content,
+// filename, or s3_bucket and s3_key
+type lambdaCode struct {
+	// property is the property that identifies the package, and value its configured value
+	property string
+	value    string
+
+	zipFile  []byte
+	s3Bucket string
+	s3Key    string
+}
+
+// validateLambdaCodeSource checks that exactly one code source is configured
+func validateLambdaCodeSource(properties map[string]interface{}) error {
+	var sources []string
+	for _, key := range []string{"code_content", "filename", "s3_bucket", "s3_key"} {
+		value, exists := properties[key]
+		if !exists {
+			continue
+		}
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s must be a string", key)
+		}
+		if str == "" {
+			return fmt.Errorf("%s cannot be empty", key)
+		}
+		if key != "s3_key" {
+			sources = append(sources, key)
+		}
+	}
+
+	_, hasBucket := properties["s3_bucket"]
+	_, hasKey := properties["s3_key"]
+	if hasBucket != hasKey {
+		return fmt.Errorf("s3_bucket and s3_key must be set together")
+	}
+
+	switch len(sources) {
+	case 0:
+		return fmt.Errorf("Lambda function code is required: set one of code_content, filename, or s3_bucket and s3_key")
+	case 1:
+	default:
+		return fmt.Errorf("Lambda function code must come from exactly one source, got %s", strings.Join(sources, " and "))
+	}
+
+	if filename, ok := properties["filename"].(string); ok {
+		if _, err := os.Stat(filename); err != nil {
+			return fmt.Errorf("invalid filename: %w", err)
+		}
+	}
+
 	return nil
 }
 
+// loadLambdaCode reads the configured code source, loading a local zip file from disk
+func loadLambdaCode(properties map[string]interface{}) (*lambdaCode, error) {
+	if bucket, ok := properties["s3_bucket"].(string); ok {
+		key, _ := properties["s3_key"].(string)
+		return &lambdaCode{property: "s3_key", value: key, s3Bucket: bucket, s3Key: key}, nil
+	}
+
+	if filename, ok := properties["filename"].(string); ok {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Lambda package %s: %w", filename, err)
+		}
+		return &lambdaCode{property: "filename", value: filename, zipFile: data}, nil
+	}
+
+	if content, ok := properties["code_content"].(string); ok {
+		return &lambdaCode{property: "code_content", value: content, zipFile: []byte(content)}, nil
+	}
+
+	return nil, fmt.Errorf("Lambda function code is required: set one of code_content, filename, or s3_bucket and s3_key")
+}
+
+// functionCode returns the package in the form CreateFunction expects
+func (c *lambdaCode) functionCode() *types.FunctionCode {
+	if c.s3Bucket != "" {
+		return &types.FunctionCode{S3Bucket: aws.String(c.s3Bucket), S3Key: aws.String(c.s3Key)}
+	}
+	return &types.FunctionCode{ZipFile: c.zipFile}
+}
+
+// lambdaCodeSHA256 returns the SHA-256 of a deployment package, base64 encoded the way
+// Lambda reports CodeSha256
+func lambdaCodeSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// codeSHA256 hashes the configured package. Packages in S3 aren't downloaded: their SHA-256
+// checksum is read from the object's metadata, and "" is returned when the object was
+// uploaded without one, or in parts, whose checksum doesn't cover the whole package.
+func (p *Provider) codeSHA256(ctx context.Context, code *lambdaCode) (string, error) {
+	if code.s3Bucket == "" {
+		return lambdaCodeSHA256(code.zipFile), nil
+	}
+
+	object, err := p.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:       aws.String(code.s3Bucket),
+		Key:          aws.String(code.s3Key),
+		ChecksumMode: s3types.ChecksumModeEnabled,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get Lambda package s3://%s/%s: %w", code.s3Bucket, code.s3Key, err)
+	}
+
+	checksum := aws.ToString(object.ChecksumSHA256)
+	if strings.Contains(checksum, "-") {
+		return "", nil
+	}
+	return checksum, nil
+}
+
+// lambdaCodeState reports the configured code source when the deployed package has the
+// same hash, and the deployed hash otherwise, so that code changes show up as drift. A
+// package whose hash isn't known is assumed to be deployed.
+func lambdaCodeState(code *lambdaCode, configuredSHA256, deployedSHA256 string) map[string]interface{} {
+	state := make(map[string]interface{})
+	if code.s3Bucket != "" {
+		state["s3_bucket"] = code.s3Bucket
+	}
+	if configuredSHA256 == "" || configuredSHA256 == deployedSHA256 {
+		state[code.property] = code.value
+	} else {
+		state[code.property] = fmt.Sprintf("deployed code with SHA-256 %s", deployedSHA256)
+	}
+	return state
+}
+
 // getLambdaFunctionState retrieves the current state of a Lambda function
 func (p *Provider) getLambdaFunctionState(ctx context.Context, instance config.ResourceInstance) (map[string]interface{}, error) {
 	client := lambda.NewFromConfig(p.awsConfig)
@@ -132,6 +274,21 @@ func (p *Provider) getLambdaFunctionState(ctx context.Context, instance config.R
 		state["memory_size"] = *function.MemorySize
 	}
 
+	// Functions imported or read without a code source have nothing to compare against
+	if validateLambdaCodeSource(instance.Properties) == nil {
+		code, err := loadLambdaCode(instance.Properties)
+		if err != nil {
+			return nil, err
+		}
+		configuredSHA256, err := p.codeSHA256(ctx, code)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range lambdaCodeState(code, configuredSHA256, aws.ToString(function.CodeSha256)) {
+			state[key] = value
+		}
+	}
+
 	return state, nil
 }
 
@@ -143,12 +300,9 @@ func (p *Provider) createLambdaFunction(ctx context.Context, instance config.Res
 	handler := instance.Properties["handler"].(string)
 	role := instance.Properties["role"].(string)
 
-	// Default code if not provided
-	codeContent := "def handler(event, context):\n    return 'Hello from Lambda!'"
-	if codeVal, exists := instance.Properties["code_content"]; exists {
-		if code, ok := codeVal.(string); ok {
-			codeContent = code
-		}
+	code, err := loadLambdaCode(instance.Properties)
+	if err != nil {
+		return err
 	}
 
 	input := &lambda.CreateFunctionInput{
@@ -156,9 +310,7 @@ func (p *Provider) createLambdaFunction(ctx context.Context, instance config.Res
 		Runtime:      types.Runtime(runtime),
 		Handler:      aws.String(handler),
 		Role:         aws.String(role),
-		Code: &types.FunctionCode{
-			ZipFile: []byte(codeContent),
-		},
+		Code:         code.functionCode(),
 	}
 
 	// Add optional properties
@@ -181,21 +333,11 @@ func (p *Provider) createLambdaFunction(ctx context.Context, instance config.Res
 	}
 
 	// Add tags if specified
-	if tagsVal, exists := instance.Properties["tags"]; exists {
-		if tagsMap, ok := tagsVal.(map[string]interface{}); ok {
-			tags := make(map[string]string)
-			for key, value := range tagsMap {
-				if valueStr, ok := value.(string); ok {
-					tags[key] = valueStr
-				}
-			}
-			if len(tags) > 0 {
-				input.Tags = tags
-			}
-		}
+	if tags := stringTags(instance.Properties); len(tags) > 0 {
+		input.Tags = tags
 	}
 
-	_, err := client.CreateFunction(ctx, input)
+	_, err = client.CreateFunction(ctx, input)
 	if err != nil {
 		return fmt.Errorf("failed to create Lambda function %s: %w", instance.Name, err)
 	}
@@ -204,7 +346,7 @@ func (p *Provider) createLambdaFunction(ctx context.Context, instance config.Res
 }
 
 // updateLambdaFunction updates an existing Lambda function
-func (p *Provider) updateLambdaFunction(ctx context.Context, instance config.ResourceInstance) error {
+func (p *Provider) updateLambdaFunction(ctx context.Context, instance config.ResourceInstance, currentState map[string]interface{}) error {
 	client := lambda.NewFromConfig(p.awsConfig)
 
 	// Update function configuration
@@ -253,48 +395,68 @@ func (p *Provider) updateLambdaFunction(ctx context.Context, instance config.Res
 		return fmt.Errorf("failed to update Lambda function configuration %s: %w", instance.Name, err)
 	}
 
-	// Update code if provided
-	if codeVal, exists := instance.Properties["code_content"]; exists {
-		if code, ok := codeVal.(string); ok {
-			codeInput := &lambda.UpdateFunctionCodeInput{
-				FunctionName: aws.String(instance.Name),
-				ZipFile:      []byte(code),
-			}
+	// Update code only when the deployed package differs, as reported by the current state
+	code, err := loadLambdaCode(instance.Properties)
+	if err != nil {
+		return err
+	}
+	if currentState[code.property] != code.value {
+		// Lambda rejects a code update while the configuration update is still in progress
+		waiter := lambda.NewFunctionUpdatedV2Waiter(client)
+		if err := waiter.Wait(ctx, &lambda.GetFunctionInput{FunctionName: aws.String(instance.Name)}, p.defaultWaitTimeoutOr(lambdaUpdateWaitTimeout)); err != nil {
+			return fmt.Errorf("failed waiting for Lambda function %s to finish updating: %w", instance.Name, err)
+		}
 
-			_, err := client.UpdateFunctionCode(ctx, codeInput)
-			if err != nil {
-				return fmt.Errorf("failed to update Lambda function code %s: %w", instance.Name, err)
-			}
+		codeInput := &lambda.UpdateFunctionCodeInput{
+			FunctionName: aws.String(instance.Name),
+		}
+		if code.s3Bucket != "" {
+			codeInput.S3Bucket = aws.String(code.s3Bucket)
+			codeInput.S3Key = aws.String(code.s3Key)
+		} else {
+			codeInput.ZipFile = code.zipFile
+		}
+
+		_, err := client.UpdateFunctionCode(ctx, codeInput)
+		if err != nil {
+			return fmt.Errorf("failed to update Lambda function code %s: %w", instance.Name, err)
 		}
 	}
 
 	// Update tags if specified
-	if tagsVal, exists := instance.Properties["tags"]; exists {
-		if tagsMap, ok := tagsVal.(map[string]interface{}); ok {
-			tags := make(map[string]string)
-			for key, value := range tagsMap {
-				if valueStr, ok := value.(string); ok {
-					tags[key] = valueStr
-				}
-			}
-
-			if len(tags) > 0 {
-				tagInput := &lambda.TagResourceInput{
-					Resource: aws.String(fmt.Sprintf("arn:aws:lambda:%s:%s:function:%s", 
-						p.awsConfig.Region, "123456789012", instance.Name)), // Placeholder ARN
-					Tags: tags,
-				}
-				_, err := client.TagResource(ctx, tagInput)
-				if err != nil {
-					return fmt.Errorf("failed to update tags for Lambda function %s: %w", instance.Name, err)
-				}
-			}
+	if tags := stringTags(instance.Properties); len(tags) > 0 {
+		if err := tagLambdaFunction(ctx, client, instance.Name, tags); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+// lambdaTaggingAPI is the subset of the Lambda API used to tag an existing function
+type lambdaTaggingAPI interface {
+	GetFunction(ctx context.Context, params *lambda.GetFunctionInput, optFns ...func(*lambda.Options)) (*lambda.GetFunctionOutput, error)
+	TagResource(ctx context.Context, params *lambda.TagResourceInput, optFns ...func(*lambda.Options)) (*lambda.TagResourceOutput, error)
+}
+
+// tagLambdaFunction tags a function by the ARN that Lambda reports for it, which carries
+// the function's real account ID and partition
+func tagLambdaFunction(ctx context.Context, client lambdaTaggingAPI, functionName string, tags map[string]string) error {
+	result, err := client.GetFunction(ctx, &lambda.GetFunctionInput{FunctionName: aws.String(functionName)})
+	if err != nil {
+		return fmt.Errorf("failed to describe Lambda function %s: %w", functionName, err)
+	}
+
+	_, err = client.TagResource(ctx, &lambda.TagResourceInput{
+		Resource: result.Configuration.FunctionArn,
+		Tags:     tags,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update tags for Lambda function %s: %w", functionName, err)
+	}
+	return nil
+}
+
 // deleteLambdaFunction deletes a Lambda function
 func (p *Provider) deleteLambdaFunction(ctx context.Context, instance config.ResourceInstance) error {
 	client := lambda.NewFromConfig(p.awsConfig)
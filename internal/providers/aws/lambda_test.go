@@ -1,10 +1,19 @@
 package aws
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestValidateLambdaFunction(t *testing.T) {
@@ -114,6 +123,83 @@ func TestValidateLambdaFunction(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Lambda function code from S3",
+			instance: config.ResourceInstance{
+				ID:   "aws:lambda:function.test-function",
+				Kind: "aws:lambda:function",
+				Name: "test-function",
+				Properties: map[string]interface{}{
+					"runtime":   "python3.9",
+					"handler":   "index.handler",
+					"role":      "arn:aws:iam::123456789012:role/lambda-role",
+					"s3_bucket": "my-artifacts",
+					"s3_key":    "functions/test-function.zip",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Lambda function without code",
+			instance: config.ResourceInstance{
+				ID:   "aws:lambda:function.test-function",
+				Kind: "aws:lambda:function",
+				Name: "test-function",
+				Properties: map[string]interface{}{
+					"runtime": "python3.9",
+					"handler": "index.handler",
+					"role":    "arn:aws:iam::123456789012:role/lambda-role",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Lambda function with two code sources",
+			instance: config.ResourceInstance{
+				ID:   "aws:lambda:function.test-function",
+				Kind: "aws:lambda:function",
+				Name: "test-function",
+				Properties: map[string]interface{}{
+					"runtime":      "python3.9",
+					"handler":      "index.handler",
+					"role":         "arn:aws:iam::123456789012:role/lambda-role",
+					"code_content": "def handler(event, context): return 'Hello World'",
+					"s3_bucket":    "my-artifacts",
+					"s3_key":       "functions/test-function.zip",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Lambda function with s3_bucket but no s3_key",
+			instance: config.ResourceInstance{
+				ID:   "aws:lambda:function.test-function",
+				Kind: "aws:lambda:function",
+				Name: "test-function",
+				Properties: map[string]interface{}{
+					"runtime":   "python3.9",
+					"handler":   "index.handler",
+					"role":      "arn:aws:iam::123456789012:role/lambda-role",
+					"s3_bucket": "my-artifacts",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Lambda function with missing zip file",
+			instance: config.ResourceInstance{
+				ID:   "aws:lambda:function.test-function",
+				Kind: "aws:lambda:function",
+				Name: "test-function",
+				Properties: map[string]interface{}{
+					"runtime":  "python3.9",
+					"handler":  "index.handler",
+					"role":     "arn:aws:iam::123456789012:role/lambda-role",
+					"filename": "does-not-exist.zip",
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -127,3 +213,125 @@ func TestValidateLambdaFunction(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadLambdaCode_Filename(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "function.zip")
+	require.NoError(t, os.WriteFile(path, []byte("zip bytes"), 0644))
+
+	properties := map[string]interface{}{"filename": path}
+	require.NoError(t, validateLambdaCodeSource(properties))
+
+	code, err := loadLambdaCode(properties)
+	require.NoError(t, err)
+	assert.Equal(t, "filename", code.property)
+	assert.Equal(t, path, code.value)
+
+	functionCode := code.functionCode()
+	assert.Equal(t, []byte("zip bytes"), functionCode.ZipFile)
+	assert.Nil(t, functionCode.S3Bucket)
+}
+
+func TestLoadLambdaCode_S3(t *testing.T) {
+	code, err := loadLambdaCode(map[string]interface{}{
+		"s3_bucket": "my-artifacts",
+		"s3_key":    "functions/api.zip",
+	})
+	require.NoError(t, err)
+
+	functionCode := code.functionCode()
+	assert.Equal(t, "my-artifacts", *functionCode.S3Bucket)
+	assert.Equal(t, "functions/api.zip", *functionCode.S3Key)
+	assert.Nil(t, functionCode.ZipFile)
+}
+
+func TestLambdaCodeSHA256(t *testing.T) {
+	// Lambda reports CodeSha256 as the base64-encoded SHA-256 digest of the package
+	assert.Equal(t, "47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=", lambdaCodeSHA256([]byte{}))
+}
+
+func TestLambdaCodeState(t *testing.T) {
+	code := &lambdaCode{property: "s3_key", value: "functions/api.zip", s3Bucket: "my-artifacts", s3Key: "functions/api.zip"}
+
+	assert.Equal(t, map[string]interface{}{
+		"s3_bucket": "my-artifacts",
+		"s3_key":    "functions/api.zip",
+	}, lambdaCodeState(code, "abc=", "abc="))
+
+	assert.Equal(t, map[string]interface{}{
+		"s3_bucket": "my-artifacts",
+		"s3_key":    "deployed code with SHA-256 old=",
+	}, lambdaCodeState(code, "abc=", "old="))
+
+	// Without a checksum on the S3 object, the deployed package can't be compared
+	assert.Equal(t, map[string]interface{}{
+		"s3_bucket": "my-artifacts",
+		"s3_key":    "functions/api.zip",
+	}, lambdaCodeState(code, "", "old="))
+}
+
+// fakeLambdaPackage answers HeadObject for a Lambda package in S3 with a fixed checksum
+type fakeLambdaPackage struct {
+	s3API
+	checksum string
+	input    *s3.HeadObjectInput
+}
+
+func (f *fakeLambdaPackage) HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error) {
+	f.input = params
+	output := &s3.HeadObjectOutput{}
+	if f.checksum != "" {
+		output.ChecksumSHA256 = aws.String(f.checksum)
+	}
+	return output, nil
+}
+
+func TestCodeSHA256_ReadsS3Checksum(t *testing.T) {
+	code := &lambdaCode{property: "s3_key", value: "functions/api.zip", s3Bucket: "my-artifacts", s3Key: "functions/api.zip"}
+
+	fake := &fakeLambdaPackage{checksum: "abc="}
+	checksum, err := (&Provider{s3Client: fake}).codeSHA256(context.Background(), code)
+	require.NoError(t, err)
+	assert.Equal(t, "abc=", checksum)
+	assert.Equal(t, s3types.ChecksumModeEnabled, fake.input.ChecksumMode)
+
+	// Multipart checksums cover the parts rather than the package
+	checksum, err = (&Provider{s3Client: &fakeLambdaPackage{checksum: "abc=-3"}}).codeSHA256(context.Background(), code)
+	require.NoError(t, err)
+	assert.Empty(t, checksum)
+
+	checksum, err = (&Provider{s3Client: &fakeLambdaPackage{}}).codeSHA256(context.Background(), code)
+	require.NoError(t, err)
+	assert.Empty(t, checksum)
+}
+
+// fakeLambdaTagging reports a function in a fixed account and records the tagged ARN
+type fakeLambdaTagging struct {
+	taggedARN string
+	tags      map[string]string
+}
+
+func (f *fakeLambdaTagging) GetFunction(ctx context.Context, params *lambda.GetFunctionInput, optFns ...func(*lambda.Options)) (*lambda.GetFunctionOutput, error) {
+	return &lambda.GetFunctionOutput{
+		Configuration: &lambdatypes.FunctionConfiguration{
+			FunctionName: params.FunctionName,
+			FunctionArn:  aws.String("arn:aws:lambda:eu-west-1:210987654321:function:" + aws.ToString(params.FunctionName)),
+		},
+	}, nil
+}
+
+func (f *fakeLambdaTagging) TagResource(ctx context.Context, params *lambda.TagResourceInput, optFns ...func(*lambda.Options)) (*lambda.TagResourceOutput, error) {
+	f.taggedARN = aws.ToString(params.Resource)
+	f.tags = params.Tags
+	return &lambda.TagResourceOutput{}, nil
+}
+
+func TestTagLambdaFunction_UsesFunctionARN(t *testing.T) {
+	fake := &fakeLambdaTagging{}
+
+	err := tagLambdaFunction(context.Background(), fake, "api-handler", map[string]string{"Environment": "prod"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "arn:aws:lambda:eu-west-1:210987654321:function:api-handler", fake.taggedARN)
+	assert.Contains(t, fake.taggedARN, ":210987654321:")
+	assert.Equal(t, map[string]string{"Environment": "prod"}, fake.tags)
+}
@@ -0,0 +1,224 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/ataiva-software/runestone/internal/providers"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+)
+
+// validLogRetentionDays lists the retention periods CloudWatch Logs accepts
+var validLogRetentionDays = []int{1, 3, 5, 7, 14, 30, 60, 90, 120, 150, 180, 365, 400, 545, 731, 1096, 1827, 2192, 2557, 2922, 3288, 3653}
+
+// logGroupNamePattern matches the characters CloudWatch Logs allows in log group names
+var logGroupNamePattern = regexp.MustCompile(`^[.\-_/#A-Za-z0-9]{1,512}$`)
+
+// validateLogGroup validates CloudWatch log group configuration
+func (p *Provider) validateLogGroup(instance config.ResourceInstance) error {
+	if instance.Name == "" {
+		return fmt.Errorf("log group name cannot be empty")
+	}
+
+	if !logGroupNamePattern.MatchString(instance.Name) {
+		return fmt.Errorf("invalid log group name '%s': use up to 512 letters, digits and '.-_/#'", instance.Name)
+	}
+
+	if retentionVal, exists := instance.Properties["retention_in_days"]; exists {
+		retention, ok := retentionVal.(int)
+		if !ok {
+			return fmt.Errorf("retention_in_days must be an integer")
+		}
+		if !isValidLogRetention(retention) {
+			return fmt.Errorf("invalid retention_in_days %d: must be one of %v", retention, validLogRetentionDays)
+		}
+	}
+
+	if tagsVal, exists := instance.Properties["tags"]; exists {
+		if _, ok := tagsVal.(map[string]interface{}); !ok {
+			return fmt.Errorf("tags must be a map")
+		}
+	}
+
+	return nil
+}
+
+// isValidLogRetention reports whether CloudWatch Logs accepts a retention period
+func isValidLogRetention(days int) bool {
+	for _, valid := range validLogRetentionDays {
+		if days == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// getLogGroupState retrieves the current state of a CloudWatch log group
+func (p *Provider) getLogGroupState(ctx context.Context, instance config.ResourceInstance) (map[string]interface{}, error) {
+	client := cloudwatchlogs.NewFromConfig(p.awsConfig)
+
+	// DescribeLogGroups matches by prefix, so look for the exact name
+	paginator := cloudwatchlogs.NewDescribeLogGroupsPaginator(client, &cloudwatchlogs.DescribeLogGroupsInput{
+		LogGroupNamePrefix: aws.String(instance.Name),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe log group %s: %w", instance.Name, err)
+		}
+
+		for _, group := range page.LogGroups {
+			if aws.ToString(group.LogGroupName) != instance.Name {
+				continue
+			}
+
+			state := map[string]interface{}{
+				"log_group_name": instance.Name,
+				"arn":            aws.ToString(group.LogGroupArn),
+			}
+			if group.RetentionInDays != nil {
+				state["retention_in_days"] = int(*group.RetentionInDays)
+			}
+
+			tagsResult, err := client.ListTagsForResource(ctx, &cloudwatchlogs.ListTagsForResourceInput{
+				ResourceArn: group.LogGroupArn,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get tags for log group %s: %w", instance.Name, err)
+			}
+			tags := make(map[string]interface{})
+			for key, value := range tagsResult.Tags {
+				tags[key] = value
+			}
+			if len(tags) > 0 {
+				state["tags"] = tags
+			}
+
+			return state, nil
+		}
+	}
+
+	return nil, nil // Log group doesn't exist
+}
+
+// createLogGroup creates a CloudWatch log group and sets its retention period
+func (p *Provider) createLogGroup(ctx context.Context, instance config.ResourceInstance) error {
+	client := cloudwatchlogs.NewFromConfig(p.awsConfig)
+
+	input := &cloudwatchlogs.CreateLogGroupInput{
+		LogGroupName: aws.String(instance.Name),
+	}
+	if tags := stringTags(instance.Properties); len(tags) > 0 {
+		input.Tags = tags
+	}
+
+	if _, err := client.CreateLogGroup(ctx, input); err != nil {
+		if isAlreadyOwnedError(err) {
+			return p.updateExisting(ctx, instance, p.updateLogGroup)
+		}
+		return fmt.Errorf("failed to create log group %s: %w", instance.Name, err)
+	}
+
+	if retention, ok := instance.Properties["retention_in_days"].(int); ok {
+		_, err := client.PutRetentionPolicy(ctx, &cloudwatchlogs.PutRetentionPolicyInput{
+			LogGroupName:    aws.String(instance.Name),
+			RetentionInDays: aws.Int32(int32(retention)),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to set retention for log group %s: %w", instance.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// updateLogGroup brings the retention period and tags of a log group in line with the
+// configuration. Removing retention_in_days makes log events never expire again.
+func (p *Provider) updateLogGroup(ctx context.Context, instance config.ResourceInstance, currentState map[string]interface{}) error {
+	client := cloudwatchlogs.NewFromConfig(p.awsConfig)
+
+	if retention, ok := instance.Properties["retention_in_days"].(int); ok {
+		_, err := client.PutRetentionPolicy(ctx, &cloudwatchlogs.PutRetentionPolicyInput{
+			LogGroupName:    aws.String(instance.Name),
+			RetentionInDays: aws.Int32(int32(retention)),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to set retention for log group %s: %w", instance.Name, err)
+		}
+	} else if _, hasRetention := currentState["retention_in_days"]; hasRetention {
+		_, err := client.DeleteRetentionPolicy(ctx, &cloudwatchlogs.DeleteRetentionPolicyInput{
+			LogGroupName: aws.String(instance.Name),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to remove retention for log group %s: %w", instance.Name, err)
+		}
+	}
+
+	arn, _ := currentState["arn"].(string)
+	if arn == "" {
+		return fmt.Errorf("failed to update tags for log group %s: ARN not found in current state", instance.Name)
+	}
+
+	tags := stringTags(instance.Properties)
+	if len(tags) > 0 {
+		_, err := client.TagResource(ctx, &cloudwatchlogs.TagResourceInput{
+			ResourceArn: aws.String(arn),
+			Tags:        tags,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update tags for log group %s: %w", instance.Name, err)
+		}
+	}
+
+	currentTags, _ := currentState["tags"].(map[string]interface{})
+	if removed := p.removedTagKeys(currentTags, tags); len(removed) > 0 {
+		_, err := client.UntagResource(ctx, &cloudwatchlogs.UntagResourceInput{
+			ResourceArn: aws.String(arn),
+			TagKeys:     removed,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to remove tags from log group %s: %w", instance.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// deleteLogGroup deletes a CloudWatch log group along with its log events
+func (p *Provider) deleteLogGroup(ctx context.Context, instance config.ResourceInstance) error {
+	client := cloudwatchlogs.NewFromConfig(p.awsConfig)
+
+	_, err := client.DeleteLogGroup(ctx, &cloudwatchlogs.DeleteLogGroupInput{
+		LogGroupName: aws.String(instance.Name),
+	})
+	if err != nil {
+		if isResourceNotFound(err) {
+			return nil // Log group already deleted
+		}
+		return fmt.Errorf("failed to delete log group %s: %w", instance.Name, err)
+	}
+
+	return nil
+}
+
+// removedTagKeys returns the sorted keys of current tags that are no longer desired. Tags
+// under the ignored prefixes belong to AWS or other tools, and the runestone tags mark the
+// resource for lookups and scans, so none of them are removed.
+func (p *Provider) removedTagKeys(current map[string]interface{}, desired map[string]string) []string {
+	var removed []string
+	for key := range current {
+		if _, keep := desired[key]; keep {
+			continue
+		}
+		if key == resourceIDTagKey || key == managedTagKey || providers.HasTagPrefix(key, p.ignoredTagPrefixes) {
+			continue
+		}
+		removed = append(removed, key)
+	}
+	sort.Strings(removed)
+	return removed
+}
@@ -0,0 +1,122 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateLogGroup(t *testing.T) {
+	provider := NewProvider()
+
+	tests := []struct {
+		name     string
+		instance config.ResourceInstance
+		wantErr  bool
+	}{
+		{
+			name: "valid log group",
+			instance: config.ResourceInstance{
+				ID:   "aws:logs:log_group./aws/lambda/api-handler",
+				Kind: "aws:logs:log_group",
+				Name: "/aws/lambda/api-handler",
+				Properties: map[string]interface{}{
+					"retention_in_days": 30,
+					"tags": map[string]interface{}{
+						"Environment": "prod",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "log group without retention",
+			instance: config.ResourceInstance{
+				ID:         "aws:logs:log_group.app",
+				Kind:       "aws:logs:log_group",
+				Name:       "app",
+				Properties: map[string]interface{}{},
+			},
+			wantErr: false,
+		},
+		{
+			name: "log group with empty name",
+			instance: config.ResourceInstance{
+				ID:         "aws:logs:log_group.",
+				Kind:       "aws:logs:log_group",
+				Name:       "",
+				Properties: map[string]interface{}{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "log group with invalid characters",
+			instance: config.ResourceInstance{
+				ID:         "aws:logs:log_group.app logs",
+				Kind:       "aws:logs:log_group",
+				Name:       "app logs",
+				Properties: map[string]interface{}{},
+			},
+			wantErr: true,
+		},
+		{
+			name: "log group with unsupported retention",
+			instance: config.ResourceInstance{
+				ID:   "aws:logs:log_group.app",
+				Kind: "aws:logs:log_group",
+				Name: "app",
+				Properties: map[string]interface{}{
+					"retention_in_days": 10,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "log group with non-integer retention",
+			instance: config.ResourceInstance{
+				ID:   "aws:logs:log_group.app",
+				Kind: "aws:logs:log_group",
+				Name: "app",
+				Properties: map[string]interface{}{
+					"retention_in_days": "30",
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := provider.ValidateResource(tt.instance)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRemovedTagKeys(t *testing.T) {
+	provider := NewProvider()
+	current := map[string]interface{}{"Environment": "prod", "Team": "data", "Owner": "ops"}
+	desired := map[string]string{"Environment": "staging"}
+
+	assert.Equal(t, []string{"Owner", "Team"}, provider.removedTagKeys(current, desired))
+	assert.Empty(t, provider.removedTagKeys(nil, desired))
+
+	// Reserved AWS tags and the tags runestone relies on are never removed
+	current = map[string]interface{}{
+		"Team":                          "data",
+		"aws:cloudformation:stack-name": "network",
+		resourceIDTagKey:                "aws:logs:log_group.app",
+		managedTagKey:                   "true",
+	}
+	assert.Equal(t, []string{"Team"}, provider.removedTagKeys(current, desired))
+
+	// Only the configured prefixes are kept
+	provider.ignoredTagPrefixes = []string{"team:"}
+	current = map[string]interface{}{"team:owner": "data", "aws:cloudformation:stack-name": "network"}
+	assert.Equal(t, []string{"aws:cloudformation:stack-name"}, provider.removedTagKeys(current, desired))
+}
@@ -2,8 +2,12 @@ package aws
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -17,17 +21,63 @@ import (
 	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/ataiva-software/runestone/internal/providers"
 )
 
 // Provider implements the AWS provider
 type Provider struct {
 	awsConfig aws.Config
-	s3Client  *s3.Client
+	s3Client  s3API
 	ec2Client *ec2.Client
-	rdsClient *rds.Client
+	rdsClient rdsAPI
 	iamClient *iam.Client
-	stsClient *sts.Client
+	stsClient stsAPI
 	region    string
+
+	// profile, assumeRole and endpoint are the settings Initialize creates clients with
+	profile    string
+	assumeRole *assumeRoleConfig
+	endpoint   string
+
+	// accountID caches the caller's account ID; guarded by accountMu because changes are
+	// applied from parallel goroutines
+	accountMu sync.Mutex
+	accountID string
+
+	// kmsKeyIDs remembers the key found for each KMS key resource, so later lookups check
+	// that key directly instead of searching the account; guarded by kmsKeysMu
+	kmsKeysMu sync.Mutex
+	kmsKeyIDs map[string]string
+
+	// retry controls retryWithBackoff; set from max_retries and base_delay_ms
+	retry retryConfig
+
+	// defaultTags are merged into the tags of every resource the provider manages
+	defaultTags map[string]string
+
+	// ignoredTagPrefixes are the prefixes of tags AWS and other tools add to resources,
+	// which drift detection ignores; from ignore_tag_prefixes
+	ignoredTagPrefixes []string
+
+	// defaultWaitTimeout overrides each resource type's default wait timeout when set,
+	// from default_wait_timeout
+	defaultWaitTimeout time.Duration
+
+	// logger receives retries and progress while waiting on resources; slog.Default when nil
+	logger *slog.Logger
+}
+
+// rdsAPI is the subset of the RDS client the provider uses
+type rdsAPI interface {
+	CreateDBInstance(ctx context.Context, params *rds.CreateDBInstanceInput, optFns ...func(*rds.Options)) (*rds.CreateDBInstanceOutput, error)
+	DescribeDBInstances(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error)
+	ModifyDBInstance(ctx context.Context, params *rds.ModifyDBInstanceInput, optFns ...func(*rds.Options)) (*rds.ModifyDBInstanceOutput, error)
+	DeleteDBInstance(ctx context.Context, params *rds.DeleteDBInstanceInput, optFns ...func(*rds.Options)) (*rds.DeleteDBInstanceOutput, error)
+}
+
+// stsAPI is the subset of the STS client the provider uses
+type stsAPI interface {
+	GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error)
 }
 
 // retryConfig defines retry behavior
@@ -44,6 +94,30 @@ func defaultRetryConfig() retryConfig {
 	}
 }
 
+// retryConfigFromProviderConfig overrides the defaults with max_retries and base_delay_ms
+func retryConfigFromProviderConfig(providerConfig map[string]interface{}) (retryConfig, error) {
+	config := defaultRetryConfig()
+
+	if value, exists := providerConfig["max_retries"]; exists && value != nil {
+		maxRetries, ok := value.(int)
+		if !ok || maxRetries < 0 {
+			return config, fmt.Errorf("max_retries must be a non-negative integer")
+		}
+		config.maxRetries = maxRetries
+	}
+
+	if value, exists := providerConfig["base_delay_ms"]; exists && value != nil {
+		baseDelayMs, ok := value.(int)
+		if !ok || baseDelayMs <= 0 {
+			return config, fmt.Errorf("base_delay_ms must be a positive integer")
+		}
+		config.baseDelay = time.Duration(baseDelayMs) * time.Millisecond
+	}
+
+	return config, nil
+}
+
+
 // isResourceNotFound checks if an error indicates a resource was not found
 func isResourceNotFound(err error) bool {
 	if err == nil {
@@ -63,9 +137,44 @@ func isResourceNotFound(err error) bool {
 		strings.Contains(errStr, "does not exist")
 }
 
+// isAlreadyOwnedError checks if a create failed because the resource already exists in the
+// caller's account. Names that are unique across accounts, such as bucket names taken by
+// someone else, don't count.
+func isAlreadyOwnedError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	errStr := err.Error()
+	return strings.Contains(errStr, "BucketAlreadyOwnedByYou") ||
+		strings.Contains(errStr, "EntityAlreadyExists") ||
+		strings.Contains(errStr, "ResourceAlreadyExistsException") ||
+		strings.Contains(errStr, "RepositoryAlreadyExistsException")
+}
+
+// updateExisting adopts a resource that a create found already exists, reading its current
+// state and updating it to match the configuration. The provider keeps no state of its own,
+// so a create that failed part way through is recovered by running it again, which must
+// then finish configuring the resource rather than fail because it exists.
+func (p *Provider) updateExisting(ctx context.Context, instance config.ResourceInstance, update func(context.Context, config.ResourceInstance, map[string]interface{}) error) error {
+	state, err := p.getResourceState(ctx, instance)
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		return fmt.Errorf("%s already exists but its current state could not be found", instance.ID)
+	}
+
+	p.log().Info("resource already exists, updating it to match the configuration", "resource", instance.ID)
+	return update(ctx, instance, state)
+}
+
 // retryWithBackoff executes a function with exponential backoff retry
 func (p *Provider) retryWithBackoff(ctx context.Context, operation string, fn func() error) error {
-	config := defaultRetryConfig()
+	config := p.retry
+	if config.baseDelay == 0 {
+		config = defaultRetryConfig()
+	}
 	
 	for attempt := 0; attempt <= config.maxRetries; attempt++ {
 		err := fn()
@@ -73,18 +182,18 @@ func (p *Provider) retryWithBackoff(ctx context.Context, operation string, fn fu
 			return nil
 		}
 
-		// Don't retry on certain errors
-		if isNonRetryableError(err) {
+		// Don't retry on certain errors, unless the request was throttled
+		if !isThrottlingError(err) && isNonRetryableError(err) {
 			return fmt.Errorf("%s failed (non-retryable): %w", operation, err)
 		}
 
 		if attempt == config.maxRetries {
-			return fmt.Errorf("%s failed after %d attempts: %w", operation, config.maxRetries+1, err)
+			return &retriesExhaustedError{operation: operation, attempts: config.maxRetries + 1, err: err}
 		}
 
-		// Calculate delay with exponential backoff
-		delay := config.baseDelay * time.Duration(1<<attempt)
-		fmt.Printf("  Retrying %s in %v (attempt %d/%d)...\n", operation, delay, attempt+2, config.maxRetries+1)
+		// Calculate delay with exponential backoff and jitter
+		delay := providers.BackoffDelay(config.baseDelay, attempt)
+		p.log().Info("retrying AWS request", "operation", operation, "delay", delay, "attempt", attempt+2, "max_attempts", config.maxRetries+1, "error", err)
 		
 		select {
 		case <-ctx.Done():
@@ -97,6 +206,60 @@ func (p *Provider) retryWithBackoff(ctx context.Context, operation string, fn fu
 	return nil // Should never reach here
 }
 
+// retriesExhaustedError is returned once retryWithBackoff gives up on an operation
+type retriesExhaustedError struct {
+	operation string
+	attempts  int
+	err       error
+}
+
+func (e *retriesExhaustedError) Error() string {
+	return fmt.Sprintf("%s failed after %d attempts: %v", e.operation, e.attempts, e.err)
+}
+
+func (e *retriesExhaustedError) Unwrap() error {
+	return e.err
+}
+
+// transientErrorPatterns mark errors caused by throttling or a temporary service problem
+var transientErrorPatterns = []string{
+	"Throttling",
+	"RequestLimitExceeded",
+	"TooManyRequests",
+	"SlowDown",
+	"RequestTimeout",
+	"ServiceUnavailable",
+	"InternalError",
+	"InternalFailure",
+	"connection reset",
+	"i/o timeout",
+}
+
+// markTransient marks a temporary failure for the executor to retry the change, unless
+// retryWithBackoff has already retried it
+func markTransient(err error) error {
+	var exhausted *retriesExhaustedError
+	if err == nil || errors.As(err, &exhausted) {
+		return err
+	}
+
+	errStr := err.Error()
+	for _, pattern := range transientErrorPatterns {
+		if strings.Contains(errStr, pattern) {
+			return providers.Transient(err)
+		}
+	}
+	return err
+}
+
+// isThrottlingError determines if an error is caused by API rate limiting, which is always retried
+func isThrottlingError(err error) bool {
+	errStr := err.Error()
+	return strings.Contains(errStr, "Throttling") ||
+		strings.Contains(errStr, "RequestLimitExceeded") ||
+		strings.Contains(errStr, "TooManyRequests")
+}
+
 // isNonRetryableError determines if an error should not be retried
 func isNonRetryableError(err error) bool {
 	errStr := err.Error()
@@ -121,6 +284,14 @@ func isNonRetryableError(err error) bool {
 
 // RDS Instance operations
 
+const (
+	// defaultRDSWaitTimeout bounds how long create/update wait for an instance to become available
+	defaultRDSWaitTimeout = 20 * time.Minute
+)
+
+// rdsPollInterval is the delay between status checks while waiting on an RDS instance
+var rdsPollInterval = 15 * time.Second
+
 func (p *Provider) createRDSInstance(ctx context.Context, instance config.ResourceInstance) error {
 	dbInstanceIdentifier := instance.Name
 
@@ -173,6 +344,10 @@ func (p *Provider) createRDSInstance(ctx context.Context, instance config.Resour
 		input.BackupRetentionPeriod = aws.Int32(int32(backupRetentionPeriod))
 	}
 
+	if deletionProtection, ok := instance.Properties["deletion_protection"].(bool); ok {
+		input.DeletionProtection = aws.Bool(deletionProtection)
+	}
+
 	// Add tags if specified
 	if tags, ok := instance.Properties["tags"].(map[string]interface{}); ok && len(tags) > 0 {
 		var tagList []rdstypes.Tag
@@ -185,13 +360,22 @@ func (p *Provider) createRDSInstance(ctx context.Context, instance config.Resour
 		input.Tags = tagList
 	}
 
+	waitTimeout, err := p.waitTimeout(instance, config.ChangeTypeCreate, defaultRDSWaitTimeout)
+	if err != nil {
+		return err
+	}
+
 	// Create RDS instance with retry
-	err := p.retryWithBackoff(ctx, fmt.Sprintf("create RDS instance %s", dbInstanceIdentifier), func() error {
+	err = p.retryWithBackoff(ctx, fmt.Sprintf("create RDS instance %s", dbInstanceIdentifier), func() error {
 		_, err := p.rdsClient.CreateDBInstance(ctx, input)
 		return err
 	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	// Dependent resources need the instance to be reachable, so block until it is available
+	return p.waitForRDSInstanceAvailable(ctx, dbInstanceIdentifier, waitTimeout)
 }
 
 func (p *Provider) updateRDSInstance(ctx context.Context, instance config.ResourceInstance, currentState map[string]interface{}) error {
@@ -202,43 +386,149 @@ func (p *Provider) updateRDSInstance(ctx context.Context, instance config.Resour
 		ApplyImmediately:     aws.Bool(true),
 	}
 
-	// Check for changes that can be modified
+	// Check for changes that can be modified; the current state holds AWS's int32 values
+	modified := false
 	if dbInstanceClass, ok := instance.Properties["db_instance_class"].(string); ok {
 		if currentClass, exists := currentState["db_instance_class"]; !exists || currentClass != dbInstanceClass {
 			input.DBInstanceClass = aws.String(dbInstanceClass)
+			modified = true
 		}
 	}
 
 	if allocatedStorage, ok := instance.Properties["allocated_storage"].(int); ok {
-		if currentStorage, exists := currentState["allocated_storage"]; !exists || currentStorage != allocatedStorage {
+		if currentStorage, exists := currentState["allocated_storage"]; !exists || currentStorage != int32(allocatedStorage) {
 			input.AllocatedStorage = aws.Int32(int32(allocatedStorage))
+			modified = true
 		}
 	}
 
 	if backupRetentionPeriod, ok := instance.Properties["backup_retention_period"].(int); ok {
-		if currentRetention, exists := currentState["backup_retention_period"]; !exists || currentRetention != backupRetentionPeriod {
+		if currentRetention, exists := currentState["backup_retention_period"]; !exists || currentRetention != int32(backupRetentionPeriod) {
 			input.BackupRetentionPeriod = aws.Int32(int32(backupRetentionPeriod))
+			modified = true
 		}
 	}
 
+	if deletionProtection, ok := instance.Properties["deletion_protection"].(bool); ok {
+		if currentProtection, exists := currentState["deletion_protection"]; !exists || currentProtection != deletionProtection {
+			input.DeletionProtection = aws.Bool(deletionProtection)
+			modified = true
+		}
+	}
+
+	// Nothing ModifyDBInstance can change differs, so there is nothing to apply or wait on
+	if !modified {
+		return nil
+	}
+
+	waitTimeout, err := p.waitTimeout(instance, config.ChangeTypeUpdate, defaultRDSWaitTimeout)
+	if err != nil {
+		return err
+	}
+
 	// Update RDS instance with retry
-	err := p.retryWithBackoff(ctx, fmt.Sprintf("update RDS instance %s", dbInstanceIdentifier), func() error {
+	err = p.retryWithBackoff(ctx, fmt.Sprintf("update RDS instance %s", dbInstanceIdentifier), func() error {
 		_, err := p.rdsClient.ModifyDBInstance(ctx, input)
 		return err
 	})
+	if err != nil {
+		return err
+	}
+
+	// The modification is applied asynchronously, so wait for it to settle
+	return p.waitForRDSInstanceAvailable(ctx, dbInstanceIdentifier, waitTimeout)
+}
+
+// waitForRDSInstanceAvailable polls the instance until its status returns to available
+func (p *Provider) waitForRDSInstanceAvailable(ctx context.Context, dbInstanceIdentifier string, timeout time.Duration) error {
+	lastStatus := "unknown"
+	poll := func(ctx context.Context) (*rds.DescribeDBInstancesOutput, error) {
+		result, err := p.rdsClient.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{
+			DBInstanceIdentifier: aws.String(dbInstanceIdentifier),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe RDS instance %s while waiting: %w", dbInstanceIdentifier, err)
+		}
+		return result, nil
+	}
+	isReady := func(result *rds.DescribeDBInstancesOutput) (bool, error) {
+		if len(result.DBInstances) == 0 {
+			return false, nil
+		}
 
+		status := aws.ToString(result.DBInstances[0].DBInstanceStatus)
+		if status != lastStatus {
+			p.log().Debug("waiting for RDS instance", "instance", dbInstanceIdentifier, "status", status)
+			lastStatus = status
+		}
+
+		switch status {
+		case "available":
+			return true, nil
+		case "failed", "incompatible-parameters", "incompatible-restore", "storage-full":
+			return false, fmt.Errorf("RDS instance %s entered terminal status %s", dbInstanceIdentifier, status)
+		}
+		return false, nil
+	}
+
+	err := waitForState(ctx, poll, isReady, timeout, rdsPollInterval)
+	if errors.Is(err, errWaitTimeout) {
+		return fmt.Errorf("timed out after %v waiting for RDS instance %s to become available (last status: %s)", timeout, dbInstanceIdentifier, lastStatus)
+	}
 	return err
 }
 
 func (p *Provider) deleteRDSInstance(ctx context.Context, instance config.ResourceInstance) error {
 	dbInstanceIdentifier := instance.Name
 
+	if protected, _ := instance.Properties["deletion_protection"].(bool); protected {
+		if !providers.IsForceDelete(ctx) {
+			return fmt.Errorf("RDS instance %s has deletion_protection enabled; use --force to delete it", dbInstanceIdentifier)
+		}
+
+		waitTimeout, err := p.waitTimeout(instance, config.ChangeTypeDelete, defaultRDSWaitTimeout)
+		if err != nil {
+			return err
+		}
+
+		// Deletion protection is also enforced by AWS, so lift it before deleting
+		err = p.retryWithBackoff(ctx, fmt.Sprintf("disable deletion protection for RDS instance %s", dbInstanceIdentifier), func() error {
+			_, err := p.rdsClient.ModifyDBInstance(ctx, &rds.ModifyDBInstanceInput{
+				DBInstanceIdentifier: aws.String(dbInstanceIdentifier),
+				DeletionProtection:   aws.Bool(false),
+				ApplyImmediately:     aws.Bool(true),
+			})
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		// AWS rejects the delete while the modification is still being applied
+		if err := p.waitForRDSInstanceAvailable(ctx, dbInstanceIdentifier, waitTimeout); err != nil {
+			return err
+		}
+	}
+
+	skipFinalSnapshot := true
+	if skip, ok := instance.Properties["skip_final_snapshot"].(bool); ok {
+		skipFinalSnapshot = skip
+	}
+
 	input := &rds.DeleteDBInstanceInput{
 		DBInstanceIdentifier:   aws.String(dbInstanceIdentifier),
-		SkipFinalSnapshot:      aws.Bool(true),
+		SkipFinalSnapshot:      aws.Bool(skipFinalSnapshot),
 		DeleteAutomatedBackups: aws.Bool(true),
 	}
 
+	if !skipFinalSnapshot {
+		snapshotIdentifier, _ := instance.Properties["final_snapshot_identifier"].(string)
+		if snapshotIdentifier == "" {
+			return fmt.Errorf("final_snapshot_identifier is required when skip_final_snapshot is false")
+		}
+		input.FinalDBSnapshotIdentifier = aws.String(snapshotIdentifier)
+	}
+
 	// Delete RDS instance with retry
 	err := p.retryWithBackoff(ctx, fmt.Sprintf("delete RDS instance %s", dbInstanceIdentifier), func() error {
 		_, err := p.rdsClient.DeleteDBInstance(ctx, input)
@@ -293,6 +583,18 @@ func (p *Provider) getRDSInstanceState(ctx context.Context, instance config.Reso
 		state["backup_retention_period"] = aws.ToInt32(dbInstance.BackupRetentionPeriod)
 	}
 
+	if dbInstance.DeletionProtection != nil {
+		state["deletion_protection"] = aws.ToBool(dbInstance.DeletionProtection)
+	}
+
+	// wait_timeout only controls how long changes are waited on, and the final snapshot
+	// settings only affect deletion, so they're reported as configured
+	for _, property := range []string{"wait_timeout", "skip_final_snapshot", "final_snapshot_identifier"} {
+		if value, exists := instance.Properties[property]; exists {
+			state[property] = value
+		}
+	}
+
 	// Add tags
 	if len(dbInstance.TagList) > 0 {
 		tags := make(map[string]interface{})
@@ -342,16 +644,48 @@ func (p *Provider) validateRDSInstance(instance config.ResourceInstance) error {
 		}
 	}
 
+	if _, err := waitTimeoutProperty(instance, defaultRDSWaitTimeout); err != nil {
+		return err
+	}
+
+	// A final snapshot needs a name to be taken under
+	if skip, ok := instance.Properties["skip_final_snapshot"].(bool); ok && !skip {
+		if snapshotIdentifier, _ := instance.Properties["final_snapshot_identifier"].(string); snapshotIdentifier == "" {
+			return fmt.Errorf("final_snapshot_identifier is required when skip_final_snapshot is false")
+		}
+	}
+
 	return nil
 }
 
+func init() {
+	providers.RegisterFactory("aws", func() providers.Provider { return NewProvider() })
+}
+
 // NewProvider creates a new AWS provider
 func NewProvider() *Provider {
-	return &Provider{}
+	return &Provider{
+		retry:              defaultRetryConfig(),
+		ignoredTagPrefixes: defaultIgnoredTagPrefixes,
+	}
 }
 
-// Initialize sets up the AWS provider with configuration
-func (p *Provider) Initialize(ctx context.Context, providerConfig map[string]interface{}) error {
+// SetLogger sets the logger that receives retries and progress while waiting on resources
+func (p *Provider) SetLogger(logger *slog.Logger) {
+	p.logger = logger
+}
+
+// log returns the provider's logger
+func (p *Provider) log() *slog.Logger {
+	if p.logger == nil {
+		return slog.Default()
+	}
+	return p.logger
+}
+
+// Configure applies the provider's settings without creating clients or reading credentials,
+// so that resources can be validated against them offline. Initialize calls it first.
+func (p *Provider) Configure(providerConfig map[string]interface{}) error {
 	// Extract region and profile from config
 	region, _ := providerConfig["region"].(string)
 	if region == "" {
@@ -359,7 +693,59 @@ func (p *Provider) Initialize(ctx context.Context, providerConfig map[string]int
 	}
 	p.region = region
 
-	profile, _ := providerConfig["profile"].(string)
+	p.profile, _ = providerConfig["profile"].(string)
+
+	retry, err := retryConfigFromProviderConfig(providerConfig)
+	if err != nil {
+		return fmt.Errorf("invalid retry configuration: %w", err)
+	}
+	p.retry = retry
+
+	defaultWaitTimeout, err := defaultWaitTimeoutFromProviderConfig(providerConfig)
+	if err != nil {
+		return fmt.Errorf("invalid provider configuration: %w", err)
+	}
+	p.defaultWaitTimeout = defaultWaitTimeout
+
+	assumeRole, err := assumeRoleFromProviderConfig(providerConfig)
+	if err != nil {
+		return fmt.Errorf("invalid provider configuration: %w", err)
+	}
+	p.assumeRole = assumeRole
+
+	ignoredTagPrefixes, err := ignoredTagPrefixesFromProviderConfig(providerConfig)
+	if err != nil {
+		return fmt.Errorf("invalid provider configuration: %w", err)
+	}
+	p.ignoredTagPrefixes = ignoredTagPrefixes
+
+	endpoint, err := endpointFromProviderConfig(providerConfig)
+	if err != nil {
+		return fmt.Errorf("invalid provider configuration: %w", err)
+	}
+	p.endpoint = endpoint
+
+	p.defaultTags = make(map[string]string)
+	switch tags := providerConfig["default_tags"].(type) {
+	case map[string]string:
+		for key, value := range tags {
+			p.defaultTags[key] = value
+		}
+	case map[string]interface{}:
+		for key, value := range tags {
+			p.defaultTags[key] = fmt.Sprintf("%v", value)
+		}
+	}
+
+	return nil
+}
+
+// Initialize sets up the AWS provider with configuration
+func (p *Provider) Initialize(ctx context.Context, providerConfig map[string]interface{}) error {
+	if err := p.Configure(providerConfig); err != nil {
+		return err
+	}
+	region, profile, endpoint := p.region, p.profile, p.endpoint
 
 	// Load AWS configuration with timeout - but don't make any network calls
 	var opts []func(*awsconfig.LoadOptions) error
@@ -377,31 +763,55 @@ func (p *Provider) Initialize(ctx context.Context, providerConfig map[string]int
 		return fmt.Errorf("failed to load AWS config (region: %s, profile: %s): %w", region, profile, err)
 	}
 
+	// Set before assuming a role so STS calls go to the same endpoint as every other client
+	var s3Options []func(*s3.Options)
+	if endpoint != "" {
+		cfg = withEndpoint(cfg, endpoint)
+		s3Options = append(s3Options, s3PathStyle)
+	}
+
+	// Every client, including STS for the account ID, then acts as the assumed role
+	if p.assumeRole != nil {
+		cfg = withAssumedRole(cfg, *p.assumeRole)
+	}
+
 	p.awsConfig = cfg
-	p.s3Client = s3.NewFromConfig(cfg)
+	p.s3Client = s3.NewFromConfig(cfg, s3Options...)
 	p.ec2Client = ec2.NewFromConfig(cfg)
 	p.rdsClient = rds.NewFromConfig(cfg)
 	p.iamClient = iam.NewFromConfig(cfg)
 	p.stsClient = sts.NewFromConfig(cfg)
 
+	// The credentials may belong to a different account now
+	p.accountMu.Lock()
+	p.accountID = ""
+	p.accountMu.Unlock()
+	p.kmsKeysMu.Lock()
+	p.kmsKeyIDs = nil
+	p.kmsKeysMu.Unlock()
+
 	return nil
 }
 
-// Create creates a new AWS resource
+// Create creates a new AWS resource. Temporary failures are marked for the executor to retry.
 func (p *Provider) Create(ctx context.Context, instance config.ResourceInstance) error {
+	return markTransient(p.create(ctx, p.withDefaultTags(instance)))
+}
+
+func (p *Provider) create(ctx context.Context, instance config.ResourceInstance) error {
 	switch instance.Kind {
 	case "aws:s3:bucket":
 		return p.createS3Bucket(ctx, instance)
 	case "aws:ec2:instance":
-		return p.createEC2Instance(ctx, instance)
+		return p.createVisible(ctx, instance, p.createEC2Instance)
 	case "aws:ec2:vpc":
-		return p.createVPC(ctx, instance)
+		return p.createVisible(ctx, instance, p.createVPC)
 	case "aws:ec2:subnet":
-		return p.createSubnet(ctx, instance)
+		return p.createVisible(ctx, instance, p.createSubnet)
 	case "aws:ec2:internet_gateway":
-		return p.createInternetGateway(ctx, instance)
+		return p.createVisible(ctx, instance, p.createInternetGateway)
 	case "aws:ec2:security_group":
-		return p.createSecurityGroup(ctx, instance)
+		return p.createVisible(ctx, instance, p.createSecurityGroup)
 	case "aws:lambda:function":
 		return p.createLambdaFunction(ctx, instance)
 	case "aws:dynamodb:table":
@@ -416,13 +826,28 @@ func (p *Provider) Create(ctx context.Context, instance config.ResourceInstance)
 		return p.createIAMRole(ctx, instance)
 	case "aws:iam:policy":
 		return p.createIAMPolicy(ctx, instance)
+	case "aws:logs:log_group":
+		return p.createLogGroup(ctx, instance)
+	case "aws:ec2:volume":
+		return p.createVolume(ctx, instance)
+	case "aws:kms:key":
+		return p.createKMSKey(ctx, instance)
+	case "aws:kms:alias":
+		return p.createKMSAlias(ctx, instance)
+	case "aws:ecr:repository":
+		return p.createECRRepository(ctx, instance)
 	default:
 		return fmt.Errorf("unsupported resource type: %s", instance.Kind)
 	}
 }
 
-// Update updates an existing AWS resource
+// Update updates an existing AWS resource. Temporary failures are marked for the executor
+// to retry.
 func (p *Provider) Update(ctx context.Context, instance config.ResourceInstance, currentState map[string]interface{}) error {
+	return markTransient(p.update(ctx, p.withDefaultTags(instance), currentState))
+}
+
+func (p *Provider) update(ctx context.Context, instance config.ResourceInstance, currentState map[string]interface{}) error {
 	switch instance.Kind {
 	case "aws:s3:bucket":
 		return p.updateS3Bucket(ctx, instance, currentState)
@@ -437,11 +862,11 @@ func (p *Provider) Update(ctx context.Context, instance config.ResourceInstance,
 	case "aws:ec2:security_group":
 		return p.updateSecurityGroup(ctx, instance)
 	case "aws:lambda:function":
-		return p.updateLambdaFunction(ctx, instance)
+		return p.updateLambdaFunction(ctx, instance, currentState)
 	case "aws:dynamodb:table":
 		return p.updateDynamoDBTable(ctx, instance)
 	case "aws:apigateway:rest_api":
-		return p.updateAPIGateway(ctx, instance)
+		return p.updateAPIGateway(ctx, instance, currentState)
 	case "aws:rds:instance":
 		return p.updateRDSInstance(ctx, instance, currentState)
 	case "aws:iam:user":
@@ -450,6 +875,16 @@ func (p *Provider) Update(ctx context.Context, instance config.ResourceInstance,
 		return p.updateIAMRole(ctx, instance)
 	case "aws:iam:policy":
 		return p.updateIAMPolicy(ctx, instance)
+	case "aws:logs:log_group":
+		return p.updateLogGroup(ctx, instance, currentState)
+	case "aws:ec2:volume":
+		return p.updateVolume(ctx, instance, currentState)
+	case "aws:kms:key":
+		return p.updateKMSKey(ctx, instance, currentState)
+	case "aws:kms:alias":
+		return p.updateKMSAlias(ctx, instance)
+	case "aws:ecr:repository":
+		return p.updateECRRepository(ctx, instance, currentState)
 	default:
 		return fmt.Errorf("unsupported resource type: %s", instance.Kind)
 	}
@@ -484,6 +919,16 @@ func (p *Provider) Delete(ctx context.Context, instance config.ResourceInstance)
 		return p.deleteIAMRole(ctx, instance)
 	case "aws:iam:policy":
 		return p.deleteIAMPolicy(ctx, instance)
+	case "aws:logs:log_group":
+		return p.deleteLogGroup(ctx, instance)
+	case "aws:ec2:volume":
+		return p.deleteVolume(ctx, instance)
+	case "aws:kms:key":
+		return p.deleteKMSKey(ctx, instance)
+	case "aws:kms:alias":
+		return p.deleteKMSAlias(ctx, instance)
+	case "aws:ecr:repository":
+		return p.deleteECRRepository(ctx, instance)
 	default:
 		return fmt.Errorf("unsupported resource type: %s", instance.Kind)
 	}
@@ -491,6 +936,16 @@ func (p *Provider) Delete(ctx context.Context, instance config.ResourceInstance)
 
 // GetCurrentState retrieves the current state of an AWS resource
 func (p *Provider) GetCurrentState(ctx context.Context, instance config.ResourceInstance) (map[string]interface{}, error) {
+	state, err := p.getResourceState(ctx, instance)
+	if err != nil || state == nil {
+		return state, err
+	}
+
+	return p.stripDefaultTags(instance, state), nil
+}
+
+// getResourceState dispatches state retrieval to the handler for the resource kind
+func (p *Provider) getResourceState(ctx context.Context, instance config.ResourceInstance) (map[string]interface{}, error) {
 	switch instance.Kind {
 	case "aws:s3:bucket":
 		return p.getS3BucketState(ctx, instance)
@@ -518,6 +973,16 @@ func (p *Provider) GetCurrentState(ctx context.Context, instance config.Resource
 		return p.getIAMRoleState(ctx, instance)
 	case "aws:iam:policy":
 		return p.getIAMPolicyState(ctx, instance)
+	case "aws:logs:log_group":
+		return p.getLogGroupState(ctx, instance)
+	case "aws:ec2:volume":
+		return p.getVolumeState(ctx, instance)
+	case "aws:kms:key":
+		return p.getKMSKeyState(ctx, instance)
+	case "aws:kms:alias":
+		return p.getKMSAliasState(ctx, instance)
+	case "aws:ecr:repository":
+		return p.getECRRepositoryState(ctx, instance)
 	default:
 		return nil, fmt.Errorf("unsupported resource type: %s", instance.Kind)
 	}
@@ -552,6 +1017,16 @@ func (p *Provider) ValidateResource(instance config.ResourceInstance) error {
 		return p.validateIAMRole(instance)
 	case "aws:iam:policy":
 		return p.validateIAMPolicy(instance)
+	case "aws:logs:log_group":
+		return p.validateLogGroup(instance)
+	case "aws:ec2:volume":
+		return p.validateVolume(instance)
+	case "aws:kms:key":
+		return p.validateKMSKey(instance)
+	case "aws:kms:alias":
+		return p.validateKMSAlias(instance)
+	case "aws:ecr:repository":
+		return p.validateECRRepository(instance)
 	default:
 		return fmt.Errorf("unsupported resource type: %s", instance.Kind)
 	}
@@ -573,9 +1048,58 @@ func (p *Provider) GetSupportedResourceTypes() []string {
 		"aws:iam:user",
 		"aws:iam:role",
 		"aws:iam:policy",
+		"aws:logs:log_group",
+		"aws:ec2:volume",
+		"aws:kms:key",
+		"aws:kms:alias",
+		"aws:ecr:repository",
 	}
 }
 
+// computedFields lists the state properties of each resource type that AWS assigns,
+// such as IDs, ARNs and status, and that configuration doesn't set
+var computedFields = map[string][]string{
+	"aws:s3:bucket":            {"name", "region"},
+	"aws:ec2:instance":         {"instance_id", "state", "public_ip", "private_ip", "launch_time"},
+	"aws:ec2:vpc":              {"vpc_id", "state"},
+	"aws:ec2:subnet":           {"subnet_id", "state"},
+	"aws:ec2:internet_gateway": {"internet_gateway_id"},
+	"aws:ec2:security_group":   {"group_id", "group_name"},
+	"aws:lambda:function":      {"function_name", "function_arn", "state"},
+	"aws:dynamodb:table":       {"table_name", "table_status", "table_arn"},
+	"aws:apigateway:rest_api":  {"id", "name", "invoke_url"},
+	"aws:rds:instance":         {"db_instance_identifier", "db_instance_status"},
+	"aws:iam:user":             {"user_name", "user_id", "arn", "create_date"},
+	"aws:iam:role":             {"role_name", "role_id", "arn", "create_date"},
+	"aws:iam:policy":           {"policy_name", "policy_id", "arn", "create_date"},
+	"aws:logs:log_group":       {"log_group_name", "arn"},
+	"aws:ec2:volume":           {"volume_id", "state"},
+	"aws:kms:key":              {"key_id", "arn", "key_state"},
+	"aws:kms:alias":            {"alias_name", "alias_arn"},
+	"aws:ecr:repository":       {"repository_name", "repository_arn", "repository_uri", "registry_id"},
+}
+
+// GetComputedFields returns the state properties of a resource type that AWS assigns
+func (p *Provider) GetComputedFields(kind string) []string {
+	return computedFields[kind]
+}
+
+// GetIgnoredTagPrefixes returns the prefixes of tags drift detection ignores unless a
+// resource declares them
+func (p *Provider) GetIgnoredTagPrefixes() []string {
+	return p.ignoredTagPrefixes
+}
+
+// sensitiveFields lists the properties of each resource type that hold secrets
+var sensitiveFields = map[string][]string{
+	"aws:rds:instance": {"master_user_password"},
+}
+
+// GetSensitiveFields returns the properties of a resource type whose values are secret
+func (p *Provider) GetSensitiveFields(kind string) []string {
+	return sensitiveFields[kind]
+}
+
 // S3 Bucket operations
 
 func (p *Provider) createS3Bucket(ctx context.Context, instance config.ResourceInstance) error {
@@ -584,10 +1108,14 @@ func (p *Provider) createS3Bucket(ctx context.Context, instance config.ResourceI
 	// Create bucket with retry
 	err := p.retryWithBackoff(ctx, fmt.Sprintf("create S3 bucket %s", bucketName), func() error {
 		_, err := p.s3Client.CreateBucket(ctx, &s3.CreateBucketInput{
-			Bucket: aws.String(bucketName),
+			Bucket:                    aws.String(bucketName),
+			CreateBucketConfiguration: createBucketConfiguration(p.region),
 		})
 		return err
 	})
+	if isAlreadyOwnedError(err) {
+		return p.updateExisting(ctx, instance, p.updateS3Bucket)
+	}
 	if err != nil {
 		return err
 	}
@@ -632,6 +1160,20 @@ func (p *Provider) createS3Bucket(ctx context.Context, instance config.ResourceI
 		}
 	}
 
+	// Apply the bucket policy if specified
+	if policy, _ := instance.Properties["policy"].(string); policy != "" {
+		err = p.retryWithBackoff(ctx, fmt.Sprintf("apply policy to S3 bucket %s", bucketName), func() error {
+			_, err := p.s3Client.PutBucketPolicy(ctx, &s3.PutBucketPolicyInput{
+				Bucket: aws.String(bucketName),
+				Policy: aws.String(policy),
+			})
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -680,15 +1222,51 @@ func (p *Provider) updateS3Bucket(ctx context.Context, instance config.ResourceI
 		}
 	}
 
+	// Update the bucket policy if changed, removing it when it's no longer configured
+	policy, _ := instance.Properties["policy"].(string)
+	currentPolicy, _ := currentState["policy"].(string)
+	if policy == "" && currentPolicy != "" {
+		err := p.retryWithBackoff(ctx, fmt.Sprintf("remove policy from S3 bucket %s", bucketName), func() error {
+			_, err := p.s3Client.DeleteBucketPolicy(ctx, &s3.DeleteBucketPolicyInput{
+				Bucket: aws.String(bucketName),
+			})
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete policy of S3 bucket %s: %w", bucketName, err)
+		}
+	} else if policy != "" && !equivalentPolicyDocuments(policy, currentPolicy) {
+		err := p.retryWithBackoff(ctx, fmt.Sprintf("apply policy to S3 bucket %s", bucketName), func() error {
+			_, err := p.s3Client.PutBucketPolicy(ctx, &s3.PutBucketPolicyInput{
+				Bucket: aws.String(bucketName),
+				Policy: aws.String(policy),
+			})
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update policy of S3 bucket %s: %w", bucketName, err)
+		}
+	}
+
 	return nil
 }
 
 func (p *Provider) deleteS3Bucket(ctx context.Context, instance config.ResourceInstance) error {
 	bucketName := instance.Name
 
+	// A bucket must be empty before it can be deleted
+	if forceDestroy, _ := instance.Properties["force_destroy"].(bool); forceDestroy {
+		if err := emptyS3Bucket(ctx, p.s3Client, bucketName); err != nil {
+			return err
+		}
+	}
+
 	_, err := p.s3Client.DeleteBucket(ctx, &s3.DeleteBucketInput{
 		Bucket: aws.String(bucketName),
 	})
+	if err != nil && strings.Contains(err.Error(), "BucketNotEmpty") {
+		return fmt.Errorf("S3 bucket %s is not empty; set force_destroy: true to delete its objects along with the bucket, or empty it first", bucketName)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to delete S3 bucket %s: %w", bucketName, err)
 	}
@@ -711,6 +1289,20 @@ func (p *Provider) getS3BucketState(ctx context.Context, instance config.Resourc
 
 	state["name"] = bucketName
 
+	// Get the region the bucket is in
+	locationOutput, err := p.s3Client.GetBucketLocation(ctx, &s3.GetBucketLocationInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get location of S3 bucket %s: %w", bucketName, err)
+	}
+	state["region"] = bucketRegion(locationOutput.LocationConstraint)
+
+	// force_destroy only affects deletion, so it's reported as configured
+	if forceDestroy, exists := instance.Properties["force_destroy"]; exists {
+		state["force_destroy"] = forceDestroy
+	}
+
 	// Get versioning status
 	versioningOutput, err := p.s3Client.GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{
 		Bucket: aws.String(bucketName),
@@ -733,9 +1325,57 @@ func (p *Provider) getS3BucketState(ctx context.Context, instance config.Resourc
 		state["tags"] = tags
 	}
 
+	// Get the bucket policy; a bucket without one reports NoSuchBucketPolicy
+	policyOutput, err := p.s3Client.GetBucketPolicy(ctx, &s3.GetBucketPolicyInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil && !strings.Contains(err.Error(), "NoSuchBucketPolicy") {
+		return nil, fmt.Errorf("failed to get policy of S3 bucket %s: %w", bucketName, err)
+	}
+	if err == nil && aws.ToString(policyOutput.Policy) != "" {
+		state["policy"] = policyDocumentState(aws.ToString(policyOutput.Policy), instance.Properties["policy"])
+	} else if _, declared := instance.Properties["policy"]; declared {
+		// A cleared policy matches a bucket that has none
+		state["policy"] = ""
+	}
+
 	return state, nil
 }
 
+// createBucketConfiguration places a new bucket in the provider's region. us-east-1 is the
+// default location and rejects an explicit location constraint.
+func createBucketConfiguration(region string) *s3types.CreateBucketConfiguration {
+	if region == "" || region == "us-east-1" {
+		return nil
+	}
+	return &s3types.CreateBucketConfiguration{
+		LocationConstraint: s3types.BucketLocationConstraint(region),
+	}
+}
+
+// bucketRegion converts a bucket's location constraint to its region. Buckets in us-east-1
+// have no location constraint, and old buckets in eu-west-1 report EU.
+func bucketRegion(constraint s3types.BucketLocationConstraint) string {
+	switch constraint {
+	case "":
+		return "us-east-1"
+	case s3types.BucketLocationConstraintEu:
+		return "eu-west-1"
+	default:
+		return string(constraint)
+	}
+}
+
+// policyDocumentState returns the policy document to report in a resource's state. AWS
+// reformats policy documents, so the configured policy is reported when it is equivalent
+// to the live one.
+func policyDocumentState(livePolicy string, desired interface{}) string {
+	if desiredPolicy, ok := desired.(string); ok && equivalentPolicyDocuments(livePolicy, desiredPolicy) {
+		return desiredPolicy
+	}
+	return livePolicy
+}
+
 func (p *Provider) validateS3Bucket(instance config.ResourceInstance) error {
 	if instance.Name == "" {
 		return fmt.Errorf("S3 bucket name is required")
@@ -750,11 +1390,54 @@ func (p *Provider) validateS3Bucket(instance config.ResourceInstance) error {
 		return fmt.Errorf("S3 bucket name cannot contain underscores")
 	}
 
+	// Buckets are created in the provider's region
+	if regionVal, exists := instance.Properties["region"]; exists && regionVal != nil {
+		region, ok := regionVal.(string)
+		if !ok {
+			return fmt.Errorf("region must be a string")
+		}
+		if p.region != "" && region != p.region {
+			return fmt.Errorf("S3 bucket %s requests region %s, but the AWS provider is configured for %s; configure a provider for %s instead", instance.Name, region, p.region, region)
+		}
+	}
+
+	if forceDestroy, exists := instance.Properties["force_destroy"]; exists && forceDestroy != nil {
+		if _, ok := forceDestroy.(bool); !ok {
+			return fmt.Errorf("force_destroy must be a boolean")
+		}
+	}
+
+	// Validate the policy is a JSON document
+	if policyVal, exists := instance.Properties["policy"]; exists && policyVal != nil {
+		policy, ok := policyVal.(string)
+		if !ok {
+			return fmt.Errorf("policy must be a JSON string")
+		}
+		var policyDoc interface{}
+		if err := json.Unmarshal([]byte(policy), &policyDoc); policy != "" && err != nil {
+			return fmt.Errorf("invalid policy JSON: %w", err)
+		}
+	}
+
 	return nil
 }
 
 // EC2 Instance operations (simplified implementation)
 
+const (
+	// defaultEC2WaitTimeout bounds how long create waits for an instance to be running
+	defaultEC2WaitTimeout = 10 * time.Minute
+)
+
+// ec2PollInterval is the delay between state checks while waiting on an EC2 instance
+var ec2PollInterval = 5 * time.Second
+
+// Values of an EC2 instance's desired_state property
+const (
+	ec2StateRunning = "running"
+	ec2StateStopped = "stopped"
+)
+
 func (p *Provider) createEC2Instance(ctx context.Context, instance config.ResourceInstance) error {
 	instanceType, ok := instance.Properties["instance_type"].(string)
 	if !ok {
@@ -773,34 +1456,130 @@ func (p *Provider) createEC2Instance(ctx context.Context, instance config.Resour
 		MaxCount:     aws.Int32(1),
 	}
 
-	// Add tags if specified
-	if tags, ok := instance.Properties["tags"].(map[string]interface{}); ok && len(tags) > 0 {
-		tagSpecs := make([]types.TagSpecification, 1)
-		tagSpecs[0] = types.TagSpecification{
-			ResourceType: types.ResourceTypeInstance,
-			Tags:         make([]types.Tag, 0, len(tags)),
-		}
-
+	// Tag the instance with its resource ID, plus any tags specified
+	tagSpec := types.TagSpecification{
+		ResourceType: types.ResourceTypeInstance,
+		Tags:         []types.Tag{resourceIDTag(instance)},
+	}
+	if tags, ok := instance.Properties["tags"].(map[string]interface{}); ok {
 		for key, value := range tags {
-			tagSpecs[0].Tags = append(tagSpecs[0].Tags, types.Tag{
+			tagSpec.Tags = append(tagSpec.Tags, types.Tag{
 				Key:   aws.String(key),
 				Value: aws.String(fmt.Sprintf("%v", value)),
 			})
 		}
+	}
+	input.TagSpecifications = []types.TagSpecification{tagSpec}
 
-		input.TagSpecifications = tagSpecs
+	waitTimeout, err := p.waitTimeout(instance, config.ChangeTypeCreate, defaultEC2WaitTimeout)
+	if err != nil {
+		return err
 	}
 
-	_, err := p.ec2Client.RunInstances(ctx, input)
+	result, err := p.ec2Client.RunInstances(ctx, input)
 	if err != nil {
 		return fmt.Errorf("failed to create EC2 instance %s: %w", instance.Name, err)
 	}
+	if len(result.Instances) == 0 {
+		return fmt.Errorf("failed to create EC2 instance %s: no instance was launched", instance.Name)
+	}
+
+	// Until the instance is running its IP addresses may be unset, so wait before
+	// dependents read them
+	instanceID := aws.ToString(result.Instances[0].InstanceId)
+	if err := waitForEC2InstanceState(ctx, p.log(), p.ec2Client, instanceID, types.InstanceStateNameRunning, waitTimeout); err != nil {
+		return err
+	}
+
+	// An instance can't be launched stopped, so stop it once it has started
+	if desiredState, _ := instance.Properties["desired_state"].(string); desiredState == ec2StateStopped {
+		return p.setEC2InstancePower(ctx, instanceID, desiredState, waitTimeout)
+	}
 
 	return nil
 }
 
+// setEC2InstancePower starts or stops an instance and waits for it to reach the
+// desired state
+func (p *Provider) setEC2InstancePower(ctx context.Context, instanceID, desiredState string, timeout time.Duration) error {
+	if desiredState == ec2StateStopped {
+		if _, err := p.ec2Client.StopInstances(ctx, &ec2.StopInstancesInput{InstanceIds: []string{instanceID}}); err != nil {
+			return fmt.Errorf("failed to stop EC2 instance %s: %w", instanceID, err)
+		}
+		return waitForEC2InstanceState(ctx, p.log(), p.ec2Client, instanceID, types.InstanceStateNameStopped, timeout)
+	}
+
+	if _, err := p.ec2Client.StartInstances(ctx, &ec2.StartInstancesInput{InstanceIds: []string{instanceID}}); err != nil {
+		return fmt.Errorf("failed to start EC2 instance %s: %w", instanceID, err)
+	}
+	return waitForEC2InstanceState(ctx, p.log(), p.ec2Client, instanceID, types.InstanceStateNameRunning, timeout)
+}
+
+// ec2PowerState returns the desired_state value an instance state corresponds to, treating
+// instances that are starting as running and instances that are stopping as stopped
+func ec2PowerState(state types.InstanceStateName) string {
+	switch state {
+	case types.InstanceStateNamePending, types.InstanceStateNameRunning:
+		return ec2StateRunning
+	case types.InstanceStateNameStopping, types.InstanceStateNameStopped:
+		return ec2StateStopped
+	default:
+		return string(state)
+	}
+}
+
+// waitForEC2InstanceState polls an instance until it reaches the target state, which is
+// either running or stopped
+func waitForEC2InstanceState(ctx context.Context, logger *slog.Logger, client ec2.DescribeInstancesAPIClient, instanceID string, target types.InstanceStateName, timeout time.Duration) error {
+	lastState := "unknown"
+	poll := func(ctx context.Context) (*ec2.DescribeInstancesOutput, error) {
+		result, err := client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
+			InstanceIds: []string{instanceID},
+		})
+		if err != nil {
+			// A newly launched instance may not be visible to DescribeInstances yet
+			if isResourceNotFound(err) {
+				return &ec2.DescribeInstancesOutput{}, nil
+			}
+			return nil, fmt.Errorf("failed to describe EC2 instance %s while waiting: %w", instanceID, err)
+		}
+		return result, nil
+	}
+	isReady := func(result *ec2.DescribeInstancesOutput) (bool, error) {
+		if len(result.Reservations) == 0 || len(result.Reservations[0].Instances) == 0 {
+			return false, nil
+		}
+
+		state := "unknown"
+		if current := result.Reservations[0].Instances[0].State; current != nil {
+			state = string(current.Name)
+		}
+		if state != lastState {
+			logger.Debug("waiting for EC2 instance", "instance", instanceID, "state", state, "target", string(target))
+			lastState = state
+		}
+
+		switch current := types.InstanceStateName(state); {
+		case current == target:
+			return true, nil
+		case current == types.InstanceStateNameShuttingDown, current == types.InstanceStateNameTerminated:
+			return false, fmt.Errorf("EC2 instance %s entered state %s instead of %s", instanceID, state, target)
+		case target == types.InstanceStateNameRunning && ec2PowerState(current) == ec2StateStopped:
+			return false, fmt.Errorf("EC2 instance %s entered state %s instead of %s", instanceID, state, target)
+		}
+		return false, nil
+	}
+
+	err := waitForState(ctx, poll, isReady, timeout, ec2PollInterval)
+	if errors.Is(err, errWaitTimeout) {
+		return fmt.Errorf("timed out after %v waiting for EC2 instance %s to be %s (last state: %s)", timeout, instanceID, target, lastState)
+	}
+	return err
+}
+
 func (p *Provider) updateEC2Instance(ctx context.Context, instance config.ResourceInstance, currentState map[string]interface{}) error {
-	// EC2 instance updates are limited - for now, just handle tags
+	// EC2 instance updates are limited - for now, just handle tags and starting or
+	// stopping the instance
 	instanceID, ok := currentState["instance_id"].(string)
 	if !ok {
 		return fmt.Errorf("instance_id not found in current state")
@@ -824,6 +1603,14 @@ func (p *Provider) updateEC2Instance(ctx context.Context, instance config.Resour
 		}
 	}
 
+	if desiredState, ok := instance.Properties["desired_state"].(string); ok && desiredState != currentState["desired_state"] {
+		waitTimeout, err := p.waitTimeout(instance, config.ChangeTypeUpdate, defaultEC2WaitTimeout)
+		if err != nil {
+			return err
+		}
+		return p.setEC2InstancePower(ctx, instanceID, desiredState, waitTimeout)
+	}
+
 	return nil
 }
 
@@ -877,32 +1664,29 @@ func (p *Provider) getEC2InstanceState(ctx context.Context, instance config.Reso
 		return nil, fmt.Errorf("failed to describe EC2 instances: %w", err)
 	}
 
-	// Check if any instances were found
-	var foundInstance *types.Instance
+	// Collect the instances whose Name tag matches exactly, then pick the one created
+	// for this resource
+	var instances []types.Instance
+	var candidates [][]types.Tag
 	for _, reservation := range result.Reservations {
 		for _, inst := range reservation.Instances {
-			// Double-check the Name tag matches exactly
-			for _, tag := range inst.Tags {
-				if tag.Key != nil && *tag.Key == "Name" && tag.Value != nil && *tag.Value == instanceName {
-					foundInstance = &inst
-					break
-				}
+			if name, _ := ec2TagValue(inst.Tags, "Name"); name == instanceName {
+				instances = append(instances, inst)
+				candidates = append(candidates, inst.Tags)
 			}
-			if foundInstance != nil {
-				break
-			}
-		}
-		if foundInstance != nil {
-			break
 		}
 	}
 
 	// If no instance found, return nil (resource doesn't exist)
-	if foundInstance == nil {
+	match := matchResourceID(instance, candidates)
+	if match < 0 {
 		return nil, nil
 	}
+	return ec2InstanceState(instance, instances[match]), nil
+}
 
-	// Build state map
+// ec2InstanceState builds the state of an EC2 instance resource from the described instance
+func ec2InstanceState(instance config.ResourceInstance, foundInstance types.Instance) map[string]interface{} {
 	state := make(map[string]interface{})
 	state["instance_id"] = *foundInstance.InstanceId
 	state["instance_type"] = string(foundInstance.InstanceType)
@@ -910,13 +1694,7 @@ func (p *Provider) getEC2InstanceState(ctx context.Context, instance config.Reso
 	state["state"] = string(foundInstance.State.Name)
 
 	// Extract tags
-	if len(foundInstance.Tags) > 0 {
-		tags := make(map[string]interface{})
-		for _, tag := range foundInstance.Tags {
-			if tag.Key != nil && tag.Value != nil {
-				tags[*tag.Key] = *tag.Value
-			}
-		}
+	if tags := ec2StateTags(foundInstance.Tags); len(tags) > 0 {
 		state["tags"] = tags
 	}
 
@@ -931,7 +1709,17 @@ func (p *Provider) getEC2InstanceState(ctx context.Context, instance config.Reso
 		state["launch_time"] = foundInstance.LaunchTime.Format("2006-01-02T15:04:05Z")
 	}
 
-	return state, nil
+	// desired_state is compared with whether the instance is running or stopped
+	if _, exists := instance.Properties["desired_state"]; exists {
+		state["desired_state"] = ec2PowerState(foundInstance.State.Name)
+	}
+
+	// wait_timeout only affects creation, so it's reported as configured
+	if waitTimeout, exists := instance.Properties["wait_timeout"]; exists {
+		state["wait_timeout"] = waitTimeout
+	}
+
+	return state
 }
 
 func (p *Provider) validateEC2Instance(instance config.ResourceInstance) error {
@@ -947,5 +1735,15 @@ func (p *Provider) validateEC2Instance(instance config.ResourceInstance) error {
 		return fmt.Errorf("ami is required for EC2 instance")
 	}
 
+	if desiredState, exists := instance.Properties["desired_state"]; exists {
+		if desiredState != ec2StateRunning && desiredState != ec2StateStopped {
+			return fmt.Errorf("invalid desired_state %v: must be %s or %s", desiredState, ec2StateRunning, ec2StateStopped)
+		}
+	}
+
+	if _, err := waitTimeoutProperty(instance, defaultEC2WaitTimeout); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -2,9 +2,13 @@ package aws
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/ataiva-software/runestone/internal/providers"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -227,7 +231,26 @@ func TestProvider_GetSupportedResourceTypes(t *testing.T) {
 	assert.Contains(t, types, "aws:iam:user")
 	assert.Contains(t, types, "aws:iam:role")
 	assert.Contains(t, types, "aws:iam:policy")
-	assert.Len(t, types, 13) // Should have exactly 13 supported types
+	assert.Contains(t, types, "aws:logs:log_group")
+	assert.Contains(t, types, "aws:ec2:volume")
+	assert.Contains(t, types, "aws:kms:key")
+	assert.Contains(t, types, "aws:kms:alias")
+	assert.Contains(t, types, "aws:ecr:repository")
+	assert.Len(t, types, 18) // Should have exactly 18 supported types
+}
+
+func TestProvider_GetComputedFields(t *testing.T) {
+	provider := NewProvider()
+
+	// Every resource type declares its computed fields, so drift detection never falls
+	// back to the generic list for AWS
+	for _, kind := range provider.GetSupportedResourceTypes() {
+		assert.NotEmpty(t, provider.GetComputedFields(kind), kind)
+	}
+
+	// Configurable properties that share a name with another type's ID aren't computed
+	assert.NotContains(t, provider.GetComputedFields("aws:ec2:subnet"), "vpc_id")
+	assert.NotContains(t, provider.GetComputedFields("aws:ec2:subnet"), "availability_zone")
 }
 
 func TestProvider_validateS3Bucket(t *testing.T) {
@@ -273,6 +296,40 @@ func TestProvider_validateS3Bucket(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "bucket with a policy",
+			instance: config.ResourceInstance{
+				Name: "my-test-bucket",
+				Properties: map[string]interface{}{
+					"policy": `{"Version": "2012-10-17", "Statement": []}`,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "bucket with a cleared policy",
+			instance: config.ResourceInstance{
+				Name:       "my-test-bucket",
+				Properties: map[string]interface{}{"policy": ""},
+			},
+			wantErr: false,
+		},
+		{
+			name: "bucket with malformed policy JSON",
+			instance: config.ResourceInstance{
+				Name:       "my-test-bucket",
+				Properties: map[string]interface{}{"policy": `{"Version": "2012-10-17",`},
+			},
+			wantErr: true,
+		},
+		{
+			name: "bucket with a policy that isn't a string",
+			instance: config.ResourceInstance{
+				Name:       "my-test-bucket",
+				Properties: map[string]interface{}{"policy": map[string]interface{}{"Version": "2012-10-17"}},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -288,6 +345,70 @@ func TestProvider_validateS3Bucket(t *testing.T) {
 	}
 }
 
+func TestPolicyDocumentState(t *testing.T) {
+	configured := `{
+  "Version": "2012-10-17",
+  "Statement": [{"Effect": "Deny", "Principal": "*", "Action": "s3:*", "Resource": "arn:aws:s3:::logs/*"}]
+}`
+	live := `{"Statement":[{"Action":"s3:*","Effect":"Deny","Principal":"*","Resource":"arn:aws:s3:::logs/*"}],"Version":"2012-10-17"}`
+
+	// AWS's re-serialization of the configured policy isn't reported as drift
+	assert.Equal(t, configured, policyDocumentState(live, configured))
+
+	// A policy that really differs, or isn't configured, is reported as it is
+	changed := `{"Statement":[{"Action":"s3:GetObject","Effect":"Allow","Principal":"*","Resource":"arn:aws:s3:::logs/*"}],"Version":"2012-10-17"}`
+	assert.Equal(t, changed, policyDocumentState(changed, configured))
+	assert.Equal(t, live, policyDocumentState(live, nil))
+}
+
+func TestCreateBucketConfiguration(t *testing.T) {
+	assert.Nil(t, createBucketConfiguration("us-east-1"))
+	assert.Nil(t, createBucketConfiguration(""))
+	assert.Equal(t, s3types.BucketLocationConstraint("eu-west-1"), createBucketConfiguration("eu-west-1").LocationConstraint)
+}
+
+func TestBucketRegion(t *testing.T) {
+	assert.Equal(t, "us-east-1", bucketRegion(""))
+	assert.Equal(t, "eu-west-1", bucketRegion(s3types.BucketLocationConstraintEu))
+	assert.Equal(t, "ap-southeast-2", bucketRegion(s3types.BucketLocationConstraintApSoutheast2))
+}
+
+func TestProvider_validateS3Bucket_Region(t *testing.T) {
+	provider := NewProvider()
+	provider.region = "eu-west-1"
+
+	err := provider.validateS3Bucket(config.ResourceInstance{
+		Name:       "my-test-bucket",
+		Properties: map[string]interface{}{"region": "eu-west-1"},
+	})
+	assert.NoError(t, err)
+
+	err = provider.validateS3Bucket(config.ResourceInstance{
+		Name:       "my-test-bucket",
+		Properties: map[string]interface{}{"region": "us-west-2"},
+	})
+	assert.ErrorContains(t, err, "requests region us-west-2, but the AWS provider is configured for eu-west-1")
+}
+
+func TestProvider_Configure(t *testing.T) {
+	provider := NewProvider()
+	err := provider.Configure(map[string]interface{}{"region": "eu-west-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "eu-west-1", provider.region)
+	assert.Nil(t, provider.s3Client)
+
+	err = provider.validateS3Bucket(config.ResourceInstance{
+		Name:       "my-test-bucket",
+		Properties: map[string]interface{}{"region": "us-west-2"},
+	})
+	assert.ErrorContains(t, err, "configured for eu-west-1")
+
+	err = NewProvider().Configure(map[string]interface{}{"max_retries": "many"})
+	assert.ErrorContains(t, err, "invalid retry configuration")
+
+	var _ providers.OfflineConfigurer = provider
+}
+
 func TestProvider_validateEC2Instance(t *testing.T) {
 	provider := NewProvider()
 
@@ -352,3 +473,94 @@ func TestProvider_validateEC2Instance(t *testing.T) {
 		})
 	}
 }
+
+func TestRetryConfigFromProviderConfig(t *testing.T) {
+	tests := []struct {
+		name           string
+		providerConfig map[string]interface{}
+		expected       retryConfig
+		wantErr        bool
+	}{
+		{
+			name:           "defaults when unset",
+			providerConfig: map[string]interface{}{},
+			expected:       defaultRetryConfig(),
+		},
+		{
+			name: "overrides from provider config",
+			providerConfig: map[string]interface{}{
+				"max_retries":   5,
+				"base_delay_ms": 250,
+			},
+			expected: retryConfig{maxRetries: 5, baseDelay: 250 * time.Millisecond},
+		},
+		{
+			name: "zero retries allowed",
+			providerConfig: map[string]interface{}{
+				"max_retries": 0,
+			},
+			expected: retryConfig{maxRetries: 0, baseDelay: time.Second},
+		},
+		{
+			name: "negative retries rejected",
+			providerConfig: map[string]interface{}{
+				"max_retries": -1,
+			},
+			wantErr: true,
+		},
+		{
+			name: "non-positive base delay rejected",
+			providerConfig: map[string]interface{}{
+				"base_delay_ms": 0,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := retryConfigFromProviderConfig(tt.providerConfig)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, config)
+		})
+	}
+}
+
+func TestMarkTransient(t *testing.T) {
+	assert.NoError(t, markTransient(nil))
+	assert.True(t, providers.IsTransient(markTransient(errors.New("operation error EC2: RunInstances, api error RequestLimitExceeded"))))
+	assert.False(t, providers.IsTransient(markTransient(errors.New("InvalidParameterValue: invalid CIDR"))))
+
+	// Errors retryWithBackoff has already retried aren't retried again by the executor
+	provider := NewProvider()
+	provider.retry = retryConfig{maxRetries: 1, baseDelay: time.Millisecond}
+	attempts := 0
+	err := provider.retryWithBackoff(context.Background(), "test operation", func() error {
+		attempts++
+		return errors.New("Throttling: Rate exceeded")
+	})
+	assert.EqualError(t, err, "test operation failed after 2 attempts: Throttling: Rate exceeded")
+	assert.Equal(t, 2, attempts)
+	assert.False(t, providers.IsTransient(markTransient(err)))
+}
+
+func TestRetryWithBackoff_RetriesThrottling(t *testing.T) {
+	provider := NewProvider()
+	provider.retry = retryConfig{maxRetries: 2, baseDelay: time.Millisecond}
+
+	attempts := 0
+	err := provider.retryWithBackoff(context.Background(), "test operation", func() error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("ThrottlingException: Rate exceeded (ValidationException)")
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+}
@@ -4,8 +4,13 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/ataiva-software/runestone/internal/providers"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -164,3 +169,165 @@ func TestRDSInstanceOperations(t *testing.T) {
 		assert.Contains(t, err.Error(), "name cannot be empty")
 	})
 }
+
+func TestRDSInstanceDeletionSafeguards(t *testing.T) {
+	provider := NewProvider()
+
+	t.Run("ValidateRDSInstance_FinalSnapshotRequiresIdentifier", func(t *testing.T) {
+		instance := config.ResourceInstance{
+			Kind: "aws:rds:instance",
+			Name: "test-db",
+			Properties: map[string]interface{}{
+				"db_instance_class":    "db.t3.micro",
+				"engine":               "mysql",
+				"master_username":      "admin",
+				"master_user_password": "password123",
+				"skip_final_snapshot":  false,
+			},
+		}
+
+		err := provider.ValidateResource(instance)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "final_snapshot_identifier is required")
+
+		instance.Properties["final_snapshot_identifier"] = "test-db-final"
+		assert.NoError(t, provider.ValidateResource(instance))
+	})
+
+	t.Run("DeleteRDSInstance_RefusesProtectedInstance", func(t *testing.T) {
+		instance := config.ResourceInstance{
+			Kind: "aws:rds:instance",
+			Name: "test-db",
+			Properties: map[string]interface{}{
+				"deletion_protection": true,
+			},
+		}
+
+		err := provider.Delete(context.Background(), instance)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "deletion_protection enabled")
+	})
+}
+
+// fakeRDSInstance records the calls made for one instance and reports statuses in order,
+// repeating the last
+type fakeRDSInstance struct {
+	rdsAPI
+	statuses []string
+	calls    []string
+}
+
+func (f *fakeRDSInstance) DescribeDBInstances(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+	status := f.statuses[0]
+	if len(f.statuses) > 1 {
+		f.statuses = f.statuses[1:]
+	}
+	f.calls = append(f.calls, "describe:"+status)
+	return &rds.DescribeDBInstancesOutput{
+		DBInstances: []rdstypes.DBInstance{{DBInstanceIdentifier: params.DBInstanceIdentifier, DBInstanceStatus: aws.String(status)}},
+	}, nil
+}
+
+func (f *fakeRDSInstance) ModifyDBInstance(ctx context.Context, params *rds.ModifyDBInstanceInput, optFns ...func(*rds.Options)) (*rds.ModifyDBInstanceOutput, error) {
+	f.calls = append(f.calls, "modify")
+	return &rds.ModifyDBInstanceOutput{}, nil
+}
+
+func (f *fakeRDSInstance) DeleteDBInstance(ctx context.Context, params *rds.DeleteDBInstanceInput, optFns ...func(*rds.Options)) (*rds.DeleteDBInstanceOutput, error) {
+	f.calls = append(f.calls, "delete")
+	return &rds.DeleteDBInstanceOutput{}, nil
+}
+
+func TestDeleteRDSInstance_WaitsAfterDisablingProtection(t *testing.T) {
+	original := rdsPollInterval
+	rdsPollInterval = time.Millisecond
+	defer func() { rdsPollInterval = original }()
+
+	instance := config.ResourceInstance{
+		Kind:       "aws:rds:instance",
+		Name:       "test-db",
+		Properties: map[string]interface{}{"deletion_protection": true},
+	}
+	ctx := providers.WithForceDelete(context.Background())
+
+	t.Run("deletes once the modification is applied", func(t *testing.T) {
+		fake := &fakeRDSInstance{statuses: []string{"modifying", "modifying", "available"}}
+		provider := &Provider{rdsClient: fake}
+
+		require.NoError(t, provider.deleteRDSInstance(ctx, instance))
+		assert.Equal(t, []string{"modify", "describe:modifying", "describe:modifying", "describe:available", "delete"}, fake.calls)
+	})
+
+	t.Run("does not delete when the wait times out", func(t *testing.T) {
+		fake := &fakeRDSInstance{statuses: []string{"modifying"}}
+		provider := &Provider{rdsClient: fake}
+		timed := instance
+		timed.Properties = map[string]interface{}{"deletion_protection": true, "wait_timeout": "20ms"}
+
+		err := provider.deleteRDSInstance(ctx, timed)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "timed out")
+		assert.NotContains(t, fake.calls, "delete")
+	})
+}
+
+func TestGetRDSInstanceState_ReportsWaitTimeout(t *testing.T) {
+	provider := &Provider{rdsClient: &fakeRDSInstance{statuses: []string{"available"}}}
+
+	state, err := provider.getRDSInstanceState(context.Background(), config.ResourceInstance{
+		Kind:       "aws:rds:instance",
+		Name:       "test-db",
+		Properties: map[string]interface{}{"wait_timeout": "30m"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "30m", state["wait_timeout"])
+
+	state, err = provider.getRDSInstanceState(context.Background(), config.ResourceInstance{Kind: "aws:rds:instance", Name: "test-db"})
+	require.NoError(t, err)
+	assert.NotContains(t, state, "wait_timeout")
+}
+
+func TestGetRDSInstanceState_ReportsFinalSnapshotSettings(t *testing.T) {
+	provider := &Provider{rdsClient: &fakeRDSInstance{statuses: []string{"available"}}}
+
+	state, err := provider.getRDSInstanceState(context.Background(), config.ResourceInstance{
+		Kind: "aws:rds:instance",
+		Name: "test-db",
+		Properties: map[string]interface{}{
+			"skip_final_snapshot":       false,
+			"final_snapshot_identifier": "test-db-final",
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, false, state["skip_final_snapshot"])
+	assert.Equal(t, "test-db-final", state["final_snapshot_identifier"])
+}
+
+func TestUpdateRDSInstance_SkipsUnchangedInstance(t *testing.T) {
+	currentState := map[string]interface{}{
+		"db_instance_class":       "db.t3.micro",
+		"allocated_storage":       int32(20),
+		"backup_retention_period": int32(7),
+		"deletion_protection":     false,
+	}
+	instance := config.ResourceInstance{
+		Kind: "aws:rds:instance",
+		Name: "test-db",
+		Properties: map[string]interface{}{
+			"db_instance_class":       "db.t3.micro",
+			"allocated_storage":       20,
+			"backup_retention_period": 7,
+			"deletion_protection":     false,
+			"skip_final_snapshot":     true,
+		},
+	}
+
+	fake := &fakeRDSInstance{statuses: []string{"available"}}
+	provider := &Provider{rdsClient: fake}
+	require.NoError(t, provider.updateRDSInstance(context.Background(), instance, currentState))
+	assert.Empty(t, fake.calls)
+
+	instance.Properties["allocated_storage"] = 50
+	require.NoError(t, provider.updateRDSInstance(context.Background(), instance, currentState))
+	assert.Equal(t, []string{"modify", "describe:available"}, fake.calls)
+}
@@ -0,0 +1,73 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// renameIDProperty returns the state property holding the cloud ID of a kind that is found
+// by its Name and resource ID tags, and so can be renamed by retagging it. Other kinds are
+// identified by their name, such as S3 buckets and IAM roles, or by a name that can't be
+// changed, such as security groups.
+func renameIDProperty(kind string) (string, bool) {
+	if kind == "aws:ec2:security_group" {
+		return "", false
+	}
+	idProperty, supported := ec2IDProperties[kind]
+	return idProperty, supported
+}
+
+// ec2TagAPI is the subset of the EC2 API used to retag resources
+type ec2TagAPI interface {
+	CreateTags(ctx context.Context, params *ec2.CreateTagsInput, optFns ...func(*ec2.Options)) (*ec2.CreateTagsOutput, error)
+}
+
+// CanRename reports whether resources of kind are found by tags, so that a moved block can
+// rename them without replacing them
+func (p *Provider) CanRename(kind string) bool {
+	_, supported := renameIDProperty(kind)
+	return supported
+}
+
+// Rename retags the resource created as from with the Name and resource ID tags of to, so
+// that lookups for to find it. Nothing else about the resource changes.
+func (p *Provider) Rename(ctx context.Context, from, to config.ResourceInstance) error {
+	idProperty, supported := renameIDProperty(from.Kind)
+	if !supported {
+		return fmt.Errorf("%s resources can't be renamed in place, since their name identifies them", from.Kind)
+	}
+
+	state, err := p.GetCurrentState(ctx, from)
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		return fmt.Errorf("%s not found", from.ID)
+	}
+	cloudID, _ := state[idProperty].(string)
+	if cloudID == "" {
+		return fmt.Errorf("%s not found in the state of %s", idProperty, from.ID)
+	}
+
+	return renameEC2Resource(ctx, p.ec2Client, cloudID, to)
+}
+
+// renameEC2Resource tags an EC2 resource as the resource instance to
+func renameEC2Resource(ctx context.Context, client ec2TagAPI, cloudID string, to config.ResourceInstance) error {
+	_, err := client.CreateTags(ctx, &ec2.CreateTagsInput{
+		Resources: []string{cloudID},
+		Tags: []types.Tag{
+			{Key: aws.String("Name"), Value: aws.String(to.Name)},
+			resourceIDTag(to),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to tag %s: %w", cloudID, err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/ataiva-software/runestone/internal/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ providers.Renamer = (*Provider)(nil)
+
+func TestProvider_CanRename(t *testing.T) {
+	provider := NewProvider()
+
+	assert.True(t, provider.CanRename("aws:ec2:instance"))
+	assert.True(t, provider.CanRename("aws:ec2:volume"))
+	assert.False(t, provider.CanRename("aws:s3:bucket"), "bucket names are their identity")
+	assert.False(t, provider.CanRename("aws:ec2:security_group"), "group names can't be changed")
+}
+
+func TestRenameEC2Resource_RetagsResource(t *testing.T) {
+	fake := &fakeEC2Import{}
+	to := config.ResourceInstance{ID: "aws:ec2:instance.api", Kind: "aws:ec2:instance", Name: "api"}
+
+	require.NoError(t, renameEC2Resource(context.Background(), fake, "i-0a1b2c3d", to))
+
+	assert.Equal(t, map[string]string{
+		"Name":         "api",
+		"runestone:id": "aws:ec2:instance.api",
+	}, fake.tagged["i-0a1b2c3d"])
+}
+
+func TestProvider_Rename_UnsupportedKind(t *testing.T) {
+	bucket := config.ResourceInstance{ID: "aws:s3:bucket.assets", Kind: "aws:s3:bucket", Name: "assets"}
+
+	err := NewProvider().Rename(context.Background(), bucket.Renamed("aws:s3:bucket.logs"), bucket)
+	assert.ErrorContains(t, err, "aws:s3:bucket resources can't be renamed in place")
+}
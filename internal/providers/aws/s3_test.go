@@ -0,0 +1,252 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3Objects holds object versions in memory and serves them in pages
+type fakeS3Objects struct {
+	versions  []s3types.ObjectVersion
+	markers   []s3types.DeleteMarkerEntry
+	pageSize  int
+	deleted   []s3types.ObjectIdentifier
+	failKey   string
+	listCalls int
+}
+
+func (f *fakeS3Objects) ListObjectVersions(ctx context.Context, params *s3.ListObjectVersionsInput, optFns ...func(*s3.Options)) (*s3.ListObjectVersionsOutput, error) {
+	f.listCalls++
+	start := 0
+	if params.KeyMarker != nil {
+		fmt.Sscanf(aws.ToString(params.KeyMarker), "page-%d", &start)
+	}
+
+	output := &s3.ListObjectVersionsOutput{}
+	end := start + f.pageSize
+	if end >= len(f.versions) {
+		end = len(f.versions)
+		output.DeleteMarkers = f.markers
+	} else {
+		output.IsTruncated = aws.Bool(true)
+		output.NextKeyMarker = aws.String(fmt.Sprintf("page-%d", end))
+		output.NextVersionIdMarker = aws.String("v")
+	}
+	output.Versions = f.versions[start:end]
+	return output, nil
+}
+
+func (f *fakeS3Objects) DeleteObjects(ctx context.Context, params *s3.DeleteObjectsInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error) {
+	output := &s3.DeleteObjectsOutput{}
+	for _, object := range params.Delete.Objects {
+		if aws.ToString(object.Key) == f.failKey {
+			output.Errors = append(output.Errors, s3types.Error{Key: object.Key, Message: aws.String("Access Denied")})
+			continue
+		}
+		f.deleted = append(f.deleted, object)
+	}
+	return output, nil
+}
+
+func TestEmptyS3Bucket_DeletesVersionsAndMarkers(t *testing.T) {
+	fake := &fakeS3Objects{
+		versions: []s3types.ObjectVersion{
+			{Key: aws.String("a.log"), VersionId: aws.String("1")},
+			{Key: aws.String("a.log"), VersionId: aws.String("2")},
+			{Key: aws.String("b.log"), VersionId: aws.String("null")},
+		},
+		markers:  []s3types.DeleteMarkerEntry{{Key: aws.String("c.log"), VersionId: aws.String("3")}},
+		pageSize: 2,
+	}
+
+	err := emptyS3Bucket(context.Background(), fake, "logs")
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, fake.listCalls)
+	assert.Equal(t, []s3types.ObjectIdentifier{
+		{Key: aws.String("a.log"), VersionId: aws.String("1")},
+		{Key: aws.String("a.log"), VersionId: aws.String("2")},
+		{Key: aws.String("b.log"), VersionId: aws.String("null")},
+		{Key: aws.String("c.log"), VersionId: aws.String("3")},
+	}, fake.deleted)
+}
+
+func TestEmptyS3Bucket_ReportsFailedDeletions(t *testing.T) {
+	fake := &fakeS3Objects{
+		versions: []s3types.ObjectVersion{{Key: aws.String("locked.log"), VersionId: aws.String("1")}},
+		pageSize: 10,
+		failKey:  "locked.log",
+	}
+
+	err := emptyS3Bucket(context.Background(), fake, "logs")
+	assert.ErrorContains(t, err, "failed to delete 1 objects in S3 bucket logs, including locked.log: Access Denied")
+}
+
+// fakeS3Bucket serves a single bucket and records the calls that change it
+type fakeS3Bucket struct {
+	fakeS3Objects
+	createErr  error
+	versioning s3types.BucketVersioningStatus
+	tags       []s3types.Tag
+	policyErrs []error
+	calls      []string
+}
+
+func (f *fakeS3Bucket) ListBuckets(ctx context.Context, params *s3.ListBucketsInput, optFns ...func(*s3.Options)) (*s3.ListBucketsOutput, error) {
+	return &s3.ListBucketsOutput{}, nil
+}
+
+func (f *fakeS3Bucket) HeadBucket(ctx context.Context, params *s3.HeadBucketInput, optFns ...func(*s3.Options)) (*s3.HeadBucketOutput, error) {
+	return &s3.HeadBucketOutput{}, nil
+}
+
+func (f *fakeS3Bucket) CreateBucket(ctx context.Context, params *s3.CreateBucketInput, optFns ...func(*s3.Options)) (*s3.CreateBucketOutput, error) {
+	f.calls = append(f.calls, "CreateBucket")
+	return &s3.CreateBucketOutput{}, f.createErr
+}
+
+func (f *fakeS3Bucket) DeleteBucket(ctx context.Context, params *s3.DeleteBucketInput, optFns ...func(*s3.Options)) (*s3.DeleteBucketOutput, error) {
+	f.calls = append(f.calls, "DeleteBucket")
+	return &s3.DeleteBucketOutput{}, nil
+}
+
+func (f *fakeS3Bucket) GetBucketLocation(ctx context.Context, params *s3.GetBucketLocationInput, optFns ...func(*s3.Options)) (*s3.GetBucketLocationOutput, error) {
+	return &s3.GetBucketLocationOutput{}, nil
+}
+
+func (f *fakeS3Bucket) GetBucketVersioning(ctx context.Context, params *s3.GetBucketVersioningInput, optFns ...func(*s3.Options)) (*s3.GetBucketVersioningOutput, error) {
+	return &s3.GetBucketVersioningOutput{Status: f.versioning}, nil
+}
+
+func (f *fakeS3Bucket) PutBucketVersioning(ctx context.Context, params *s3.PutBucketVersioningInput, optFns ...func(*s3.Options)) (*s3.PutBucketVersioningOutput, error) {
+	f.calls = append(f.calls, "PutBucketVersioning")
+	f.versioning = params.VersioningConfiguration.Status
+	return &s3.PutBucketVersioningOutput{}, nil
+}
+
+func (f *fakeS3Bucket) GetBucketTagging(ctx context.Context, params *s3.GetBucketTaggingInput, optFns ...func(*s3.Options)) (*s3.GetBucketTaggingOutput, error) {
+	return &s3.GetBucketTaggingOutput{TagSet: f.tags}, nil
+}
+
+func (f *fakeS3Bucket) PutBucketTagging(ctx context.Context, params *s3.PutBucketTaggingInput, optFns ...func(*s3.Options)) (*s3.PutBucketTaggingOutput, error) {
+	f.calls = append(f.calls, "PutBucketTagging")
+	f.tags = params.Tagging.TagSet
+	return &s3.PutBucketTaggingOutput{}, nil
+}
+
+func (f *fakeS3Bucket) GetBucketPolicy(ctx context.Context, params *s3.GetBucketPolicyInput, optFns ...func(*s3.Options)) (*s3.GetBucketPolicyOutput, error) {
+	return nil, errors.New("NoSuchBucketPolicy: The bucket policy does not exist")
+}
+
+func (f *fakeS3Bucket) PutBucketPolicy(ctx context.Context, params *s3.PutBucketPolicyInput, optFns ...func(*s3.Options)) (*s3.PutBucketPolicyOutput, error) {
+	f.calls = append(f.calls, "PutBucketPolicy")
+	if len(f.policyErrs) > 0 {
+		err := f.policyErrs[0]
+		f.policyErrs = f.policyErrs[1:]
+		return nil, err
+	}
+	return &s3.PutBucketPolicyOutput{}, nil
+}
+
+func (f *fakeS3Bucket) DeleteBucketPolicy(ctx context.Context, params *s3.DeleteBucketPolicyInput, optFns ...func(*s3.Options)) (*s3.DeleteBucketPolicyOutput, error) {
+	f.calls = append(f.calls, "DeleteBucketPolicy")
+	return &s3.DeleteBucketPolicyOutput{}, nil
+}
+
+func (f *fakeS3Bucket) HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error) {
+	return nil, errors.New("NoSuchKey")
+}
+
+func TestCreateS3Bucket_AlreadyOwned(t *testing.T) {
+	// A previous attempt created the bucket but failed before enabling versioning
+	fake := &fakeS3Bucket{
+		createErr:  errors.New("BucketAlreadyOwnedByYou: Your previous request to create the named bucket succeeded and you already own it"),
+		versioning: s3types.BucketVersioningStatusSuspended,
+	}
+	provider := &Provider{s3Client: fake, retry: retryConfig{maxRetries: 3, baseDelay: time.Millisecond}}
+
+	err := provider.createS3Bucket(context.Background(), config.ResourceInstance{
+		ID:   "aws:s3:bucket.app-data",
+		Kind: "aws:s3:bucket",
+		Name: "app-data",
+		Properties: map[string]interface{}{
+			"versioning": true,
+			"tags":       map[string]interface{}{"Team": "data"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"CreateBucket", "PutBucketVersioning", "PutBucketTagging"}, fake.calls, "The existing bucket should be configured without retrying the create")
+	assert.Equal(t, s3types.BucketVersioningStatusEnabled, fake.versioning)
+	assert.Equal(t, []s3types.Tag{{Key: aws.String("Team"), Value: aws.String("data")}}, fake.tags)
+}
+
+func TestCreateS3Bucket_OwnedByAnotherAccount(t *testing.T) {
+	fake := &fakeS3Bucket{createErr: errors.New("BucketAlreadyExists: The requested bucket name is not available")}
+	provider := &Provider{s3Client: fake, retry: retryConfig{maxRetries: 3, baseDelay: time.Millisecond}}
+
+	err := provider.createS3Bucket(context.Background(), config.ResourceInstance{
+		ID:         "aws:s3:bucket.app-data",
+		Kind:       "aws:s3:bucket",
+		Name:       "app-data",
+		Properties: map[string]interface{}{"versioning": true},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "BucketAlreadyExists")
+	assert.Equal(t, []string{"CreateBucket"}, fake.calls, "Another account's bucket should be left alone")
+}
+
+func TestGetS3BucketState_ClearedPolicy(t *testing.T) {
+	provider := &Provider{s3Client: &fakeS3Bucket{}}
+	instance := config.ResourceInstance{
+		ID:         "aws:s3:bucket.app-data",
+		Kind:       "aws:s3:bucket",
+		Name:       "app-data",
+		Properties: map[string]interface{}{"policy": ""},
+	}
+
+	// A cleared policy matches a bucket without one, so it doesn't drift
+	state, err := provider.getS3BucketState(context.Background(), instance)
+	require.NoError(t, err)
+	assert.Equal(t, "", state["policy"])
+
+	// Buckets that don't declare a policy don't report one
+	delete(instance.Properties, "policy")
+	state, err = provider.getS3BucketState(context.Background(), instance)
+	require.NoError(t, err)
+	assert.NotContains(t, state, "policy")
+}
+
+func TestUpdateS3Bucket_RetriesPolicy(t *testing.T) {
+	fake := &fakeS3Bucket{policyErrs: []error{errors.New("SlowDown: Please reduce your request rate")}}
+	provider := &Provider{s3Client: fake, retry: retryConfig{maxRetries: 3, baseDelay: time.Millisecond}}
+
+	err := provider.updateS3Bucket(context.Background(), config.ResourceInstance{
+		ID:         "aws:s3:bucket.app-data",
+		Kind:       "aws:s3:bucket",
+		Name:       "app-data",
+		Properties: map[string]interface{}{"policy": `{"Version":"2012-10-17","Statement":[]}`},
+	}, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"PutBucketPolicy", "PutBucketPolicy"}, fake.calls)
+}
+
+func TestIsAlreadyOwnedError(t *testing.T) {
+	assert.True(t, isAlreadyOwnedError(errors.New("BucketAlreadyOwnedByYou: you already own it")))
+	assert.True(t, isAlreadyOwnedError(fmt.Errorf("create S3 bucket logs failed (non-retryable): %w", errors.New("BucketAlreadyOwnedByYou"))))
+	assert.True(t, isAlreadyOwnedError(errors.New("EntityAlreadyExists: Role with name app already exists")))
+	assert.True(t, isAlreadyOwnedError(errors.New("ResourceAlreadyExistsException: The specified log group already exists")))
+	assert.True(t, isAlreadyOwnedError(errors.New("RepositoryAlreadyExistsException: The repository already exists")))
+	assert.False(t, isAlreadyOwnedError(errors.New("BucketAlreadyExists: The requested bucket name is not available")))
+	assert.False(t, isAlreadyOwnedError(nil))
+}
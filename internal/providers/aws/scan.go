@@ -0,0 +1,171 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/ataiva-software/runestone/internal/providers"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// s3ScanAPI is the subset of the S3 API used to list buckets
+type s3ScanAPI interface {
+	ListBuckets(ctx context.Context, params *s3.ListBucketsInput, optFns ...func(*s3.Options)) (*s3.ListBucketsOutput, error)
+	GetBucketLocation(ctx context.Context, params *s3.GetBucketLocationInput, optFns ...func(*s3.Options)) (*s3.GetBucketLocationOutput, error)
+	GetBucketTagging(ctx context.Context, params *s3.GetBucketTaggingInput, optFns ...func(*s3.Options)) (*s3.GetBucketTaggingOutput, error)
+}
+
+// Scan lists the S3 buckets in the provider's region, and the EC2 instances and VPCs that
+// aren't terminated or the default VPC. Resources tagged runestone:managed, which import
+// and default_tags can set, or runestone:id, which Runestone sets on the EC2 resources it
+// creates, are reported as managed.
+func (p *Provider) Scan(ctx context.Context) ([]providers.ScannedResource, error) {
+	buckets, err := scanS3Buckets(ctx, p.log(), p.s3Client, p.region)
+	if err != nil {
+		return nil, err
+	}
+	instances, err := scanEC2Instances(ctx, p.ec2Client)
+	if err != nil {
+		return nil, err
+	}
+	vpcs, err := scanVPCs(ctx, p.ec2Client)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := append(buckets, instances...)
+	return append(resources, vpcs...), nil
+}
+
+// scanS3Buckets lists the buckets in region. ListBuckets returns the buckets of every
+// region, so each bucket's location is looked up to leave out the others. A bucket whose
+// tags can't be read, such as one a bucket policy denies access to, is left out with a
+// warning rather than failing the scan.
+func scanS3Buckets(ctx context.Context, logger *slog.Logger, client s3ScanAPI, region string) ([]providers.ScannedResource, error) {
+	output, err := client.ListBuckets(ctx, &s3.ListBucketsInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list S3 buckets: %w", err)
+	}
+
+	var resources []providers.ScannedResource
+	for _, bucket := range output.Buckets {
+		bucketName := aws.ToString(bucket.Name)
+
+		location, err := client.GetBucketLocation(ctx, &s3.GetBucketLocationInput{Bucket: bucket.Name})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get location of S3 bucket %s: %w", bucketName, err)
+		}
+		if bucketRegion(location.LocationConstraint) != region {
+			continue
+		}
+
+		// A bucket without tags reports NoSuchTagSet
+		var tags []s3types.Tag
+		tagging, err := client.GetBucketTagging(ctx, &s3.GetBucketTaggingInput{Bucket: bucket.Name})
+		if err != nil && !strings.Contains(err.Error(), "NoSuchTagSet") {
+			logger.Warn("skipping S3 bucket whose tags can't be read", "bucket", bucketName, "error", err)
+			continue
+		}
+		if err == nil {
+			tags = tagging.TagSet
+		}
+
+		managed := false
+		for _, tag := range tags {
+			managed = managed || isManagedTag(aws.ToString(tag.Key))
+		}
+
+		resources = append(resources, providers.ScannedResource{
+			Kind:      "aws:s3:bucket",
+			CloudID:   bucketName,
+			Name:      bucketName,
+			CreatedAt: aws.ToTime(bucket.CreationDate),
+			Managed:   managed,
+		})
+	}
+
+	return resources, nil
+}
+
+// scanEC2Instances lists the EC2 instances that haven't been terminated
+func scanEC2Instances(ctx context.Context, client ec2.DescribeInstancesAPIClient) ([]providers.ScannedResource, error) {
+	var resources []providers.ScannedResource
+
+	paginator := ec2.NewDescribeInstancesPaginator(client, &ec2.DescribeInstancesInput{
+		Filters: []types.Filter{{
+			Name:   aws.String("instance-state-name"),
+			Values: []string{"pending", "running", "stopping", "stopped"},
+		}},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe EC2 instances: %w", err)
+		}
+
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				name, _ := ec2TagValue(instance.Tags, "Name")
+				resources = append(resources, providers.ScannedResource{
+					Kind:      "aws:ec2:instance",
+					CloudID:   aws.ToString(instance.InstanceId),
+					Name:      name,
+					CreatedAt: aws.ToTime(instance.LaunchTime),
+					Managed:   hasManagedEC2Tag(instance.Tags),
+				})
+			}
+		}
+	}
+
+	return resources, nil
+}
+
+// scanVPCs lists the VPCs other than the region's default VPC, which AWS creates. The
+// EC2 API doesn't report when a VPC was created.
+func scanVPCs(ctx context.Context, client ec2.DescribeVpcsAPIClient) ([]providers.ScannedResource, error) {
+	var resources []providers.ScannedResource
+
+	paginator := ec2.NewDescribeVpcsPaginator(client, &ec2.DescribeVpcsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe VPCs: %w", err)
+		}
+
+		for _, vpc := range page.Vpcs {
+			if aws.ToBool(vpc.IsDefault) {
+				continue
+			}
+			name, _ := ec2TagValue(vpc.Tags, "Name")
+			resources = append(resources, providers.ScannedResource{
+				Kind:    "aws:ec2:vpc",
+				CloudID: aws.ToString(vpc.VpcId),
+				Name:    name,
+				Managed: hasManagedEC2Tag(vpc.Tags),
+			})
+		}
+	}
+
+	return resources, nil
+}
+
+// hasManagedEC2Tag reports whether EC2 tags mark a resource as managed by Runestone
+func hasManagedEC2Tag(tags []types.Tag) bool {
+	for _, tag := range tags {
+		if isManagedTag(aws.ToString(tag.Key)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isManagedTag reports whether a tag key marks a resource as managed by Runestone
+func isManagedTag(key string) bool {
+	return key == managedTagKey || key == resourceIDTagKey
+}
@@ -0,0 +1,116 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/ataiva-software/runestone/internal/providers"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3Buckets serves buckets from several regions, keyed by name
+type fakeS3Buckets struct {
+	buckets []s3types.Bucket
+	regions map[string]s3types.BucketLocationConstraint
+	tags    map[string][]s3types.Tag
+	tagErrs map[string]error
+}
+
+func (f *fakeS3Buckets) ListBuckets(ctx context.Context, params *s3.ListBucketsInput, optFns ...func(*s3.Options)) (*s3.ListBucketsOutput, error) {
+	return &s3.ListBucketsOutput{Buckets: f.buckets}, nil
+}
+
+func (f *fakeS3Buckets) GetBucketLocation(ctx context.Context, params *s3.GetBucketLocationInput, optFns ...func(*s3.Options)) (*s3.GetBucketLocationOutput, error) {
+	return &s3.GetBucketLocationOutput{LocationConstraint: f.regions[aws.ToString(params.Bucket)]}, nil
+}
+
+func (f *fakeS3Buckets) GetBucketTagging(ctx context.Context, params *s3.GetBucketTaggingInput, optFns ...func(*s3.Options)) (*s3.GetBucketTaggingOutput, error) {
+	if err := f.tagErrs[aws.ToString(params.Bucket)]; err != nil {
+		return nil, err
+	}
+	tags, exists := f.tags[aws.ToString(params.Bucket)]
+	if !exists {
+		return nil, errors.New("NoSuchTagSet: The TagSet does not exist")
+	}
+	return &s3.GetBucketTaggingOutput{TagSet: tags}, nil
+}
+
+func TestScanS3Buckets(t *testing.T) {
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	client := &fakeS3Buckets{
+		buckets: []s3types.Bucket{
+			{Name: aws.String("app-data"), CreationDate: aws.Time(created)},
+			{Name: aws.String("old-logs"), CreationDate: aws.Time(created)},
+			{Name: aws.String("eu-backups"), CreationDate: aws.Time(created)},
+		},
+		regions: map[string]s3types.BucketLocationConstraint{"eu-backups": s3types.BucketLocationConstraintEuWest1},
+		tags: map[string][]s3types.Tag{
+			"app-data": {{Key: aws.String(managedTagKey), Value: aws.String("true")}},
+		},
+	}
+
+	resources, err := scanS3Buckets(context.Background(), slog.Default(), client, "us-east-1")
+	require.NoError(t, err)
+	assert.Equal(t, []providers.ScannedResource{
+		{Kind: "aws:s3:bucket", CloudID: "app-data", Name: "app-data", CreatedAt: created, Managed: true},
+		{Kind: "aws:s3:bucket", CloudID: "old-logs", Name: "old-logs", CreatedAt: created, Managed: false},
+	}, resources, "Buckets in other regions should be left out")
+}
+
+func TestScanS3Buckets_SkipsUnreadableTags(t *testing.T) {
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	client := &fakeS3Buckets{
+		buckets: []s3types.Bucket{
+			{Name: aws.String("locked"), CreationDate: aws.Time(created)},
+			{Name: aws.String("app-data"), CreationDate: aws.Time(created)},
+		},
+		tagErrs: map[string]error{"locked": errors.New("AccessDenied: Access Denied")},
+	}
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	resources, err := scanS3Buckets(context.Background(), logger, client, "us-east-1")
+	require.NoError(t, err)
+	assert.Equal(t, []providers.ScannedResource{
+		{Kind: "aws:s3:bucket", CloudID: "app-data", Name: "app-data", CreatedAt: created, Managed: false},
+	}, resources)
+	assert.Contains(t, logs.String(), "bucket=locked")
+}
+
+func TestScanEC2InstancesAndVPCs(t *testing.T) {
+	launched := time.Date(2024, 5, 20, 8, 30, 0, 0, time.UTC)
+	client := &fakeEC2Batch{
+		instances: []types.Instance{
+			{InstanceId: aws.String("i-1"), LaunchTime: aws.Time(launched), Tags: batchTestTags("web", "aws:ec2:instance.web")},
+			{InstanceId: aws.String("i-2"), LaunchTime: aws.Time(launched), Tags: batchTestTags("bastion", "")},
+		},
+		vpcs: []types.Vpc{
+			{VpcId: aws.String("vpc-default"), IsDefault: aws.Bool(true)},
+			{VpcId: aws.String("vpc-1"), Tags: []types.Tag{{Key: aws.String(managedTagKey), Value: aws.String("true")}}},
+			{VpcId: aws.String("vpc-2"), Tags: batchTestTags("legacy", "")},
+		},
+	}
+
+	instances, err := scanEC2Instances(context.Background(), client)
+	require.NoError(t, err)
+	assert.Equal(t, []providers.ScannedResource{
+		{Kind: "aws:ec2:instance", CloudID: "i-1", Name: "web", CreatedAt: launched, Managed: true},
+		{Kind: "aws:ec2:instance", CloudID: "i-2", Name: "bastion", CreatedAt: launched, Managed: false},
+	}, instances)
+
+	vpcs, err := scanVPCs(context.Background(), client)
+	require.NoError(t, err)
+	assert.Equal(t, []providers.ScannedResource{
+		{Kind: "aws:ec2:vpc", CloudID: "vpc-1", Managed: true},
+		{Kind: "aws:ec2:vpc", CloudID: "vpc-2", Name: "legacy", Managed: false},
+	}, vpcs, "The default VPC should be left out")
+}
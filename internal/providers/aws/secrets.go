@@ -0,0 +1,35 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// secretsManagerAPI is the subset of the Secrets Manager API used to read secrets
+type secretsManagerAPI interface {
+	GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+// ResolveSecret returns the current value of a Secrets Manager secret, named by its name
+// or ARN
+func (p *Provider) ResolveSecret(ctx context.Context, name string) (string, error) {
+	return getSecretString(ctx, secretsmanager.NewFromConfig(p.awsConfig), name)
+}
+
+// getSecretString reads a secret's string value. Binary secrets can't be placed in
+// properties, so they are rejected.
+func getSecretString(ctx context.Context, client secretsManagerAPI, name string) (string, error) {
+	result, err := client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(name),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret %s from Secrets Manager: %w", name, err)
+	}
+	if result.SecretString == nil {
+		return "", fmt.Errorf("secret %s has no string value", name)
+	}
+	return *result.SecretString, nil
+}
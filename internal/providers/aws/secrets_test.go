@@ -0,0 +1,41 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSecrets serves secret values from memory
+type fakeSecrets struct {
+	values map[string]*secretsmanager.GetSecretValueOutput
+}
+
+func (f *fakeSecrets) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	value, exists := f.values[aws.ToString(params.SecretId)]
+	if !exists {
+		return nil, assert.AnError
+	}
+	return value, nil
+}
+
+func TestGetSecretString(t *testing.T) {
+	fake := &fakeSecrets{values: map[string]*secretsmanager.GetSecretValueOutput{
+		"prod/db/password": {SecretString: aws.String("s3cret")},
+		"prod/tls/key":     {SecretBinary: []byte{0x01}},
+	}}
+
+	value, err := getSecretString(context.Background(), fake, "prod/db/password")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", value)
+
+	_, err = getSecretString(context.Background(), fake, "prod/tls/key")
+	assert.EqualError(t, err, "secret prod/tls/key has no string value")
+
+	_, err = getSecretString(context.Background(), fake, "prod/missing")
+	assert.ErrorContains(t, err, "failed to read secret prod/missing from Secrets Manager")
+}
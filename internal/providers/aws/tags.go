@@ -0,0 +1,186 @@
+package aws
+
+import (
+	"fmt"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// resourceIDTagKey tags EC2 resources with the ID of the resource instance that created
+// them, so that resources sharing a Name tag can be told apart
+const resourceIDTagKey = "runestone:id"
+
+// ec2IDProperties names the state property holding the cloud ID of each EC2 kind that is
+// found by its tags rather than its cloud ID, so a resource created elsewhere is adopted,
+// and a resource is renamed, by tagging it
+var ec2IDProperties = map[string]string{
+	"aws:ec2:instance":         "instance_id",
+	"aws:ec2:vpc":              "vpc_id",
+	"aws:ec2:subnet":           "subnet_id",
+	"aws:ec2:internet_gateway": "internet_gateway_id",
+	"aws:ec2:security_group":   "group_id",
+	"aws:ec2:volume":           "volume_id",
+}
+
+// defaultIgnoredTagPrefixes are ignored by drift detection unless ignore_tag_prefixes
+// replaces them. AWS reserves the aws: prefix for tags it adds, such as
+// aws:cloudformation:stack-name.
+var defaultIgnoredTagPrefixes = []string{"aws:"}
+
+// ignoredTagPrefixesFromProviderConfig reads ignore_tag_prefixes, a list of tag key prefixes
+// that replaces the default; an empty list ignores no tags
+func ignoredTagPrefixesFromProviderConfig(providerConfig map[string]interface{}) ([]string, error) {
+	value, exists := providerConfig["ignore_tag_prefixes"]
+	if !exists || value == nil {
+		return defaultIgnoredTagPrefixes, nil
+	}
+
+	switch prefixes := value.(type) {
+	case []string:
+		return prefixes, nil
+	case []interface{}:
+		result := make([]string, 0, len(prefixes))
+		for _, prefix := range prefixes {
+			prefixStr, ok := prefix.(string)
+			if !ok || prefixStr == "" {
+				return nil, fmt.Errorf("ignore_tag_prefixes must be a list of non-empty strings")
+			}
+			result = append(result, prefixStr)
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("ignore_tag_prefixes must be a list of non-empty strings")
+	}
+}
+
+// withDefaultTags returns a copy of the instance whose tags include the provider's
+// default tags, with tags declared on the resource taking precedence
+func (p *Provider) withDefaultTags(instance config.ResourceInstance) config.ResourceInstance {
+	if len(p.defaultTags) == 0 {
+		return instance
+	}
+
+	merged := make(map[string]interface{}, len(p.defaultTags))
+	for key, value := range p.defaultTags {
+		merged[key] = value
+	}
+	if tags, ok := instance.Properties["tags"].(map[string]interface{}); ok {
+		for key, value := range tags {
+			merged[key] = value
+		}
+	}
+
+	properties := make(map[string]interface{}, len(instance.Properties)+1)
+	for key, value := range instance.Properties {
+		properties[key] = value
+	}
+	properties["tags"] = merged
+
+	instance.Properties = properties
+	return instance
+}
+
+// stripDefaultTags removes tags from the live state that were injected from the
+// provider's default tags, so drift detection compares only the tags the resource
+// declares. Default tags whose live value no longer matches are kept so the
+// difference still surfaces as drift.
+func (p *Provider) stripDefaultTags(instance config.ResourceInstance, state map[string]interface{}) map[string]interface{} {
+	if len(p.defaultTags) == 0 {
+		return state
+	}
+
+	currentTags, ok := state["tags"].(map[string]interface{})
+	if !ok {
+		return state
+	}
+
+	declared, _ := instance.Properties["tags"].(map[string]interface{})
+
+	tags := make(map[string]interface{}, len(currentTags))
+	for key, value := range currentTags {
+		if _, isDeclared := declared[key]; !isDeclared {
+			if defaultValue, isDefault := p.defaultTags[key]; isDefault && fmt.Sprintf("%v", value) == defaultValue {
+				continue
+			}
+		}
+		tags[key] = value
+	}
+
+	result := make(map[string]interface{}, len(state))
+	for key, value := range state {
+		result[key] = value
+	}
+
+	if len(tags) == 0 && declared == nil {
+		delete(result, "tags")
+	} else {
+		result["tags"] = tags
+	}
+
+	return result
+}
+
+// stringTags converts the tags property to the map of strings that AWS tagging APIs
+// expect, skipping values that aren't strings
+func stringTags(properties map[string]interface{}) map[string]string {
+	tags := make(map[string]string)
+	if tagsMap, ok := properties["tags"].(map[string]interface{}); ok {
+		for key, value := range tagsMap {
+			if valueStr, ok := value.(string); ok {
+				tags[key] = valueStr
+			}
+		}
+	}
+	return tags
+}
+
+// resourceIDTag returns the tag recording which resource instance created a resource
+func resourceIDTag(instance config.ResourceInstance) types.Tag {
+	return types.Tag{Key: aws.String(resourceIDTagKey), Value: aws.String(instance.ID)}
+}
+
+// matchResourceID returns the index of the candidate, given by its tags, that was created
+// for instance. A candidate tagged with the instance's ID is preferred; otherwise the first
+// candidate without an ID tag is matched, such as one that was imported. Candidates created
+// for another instance never match. It returns -1 when no candidate matches.
+func matchResourceID(instance config.ResourceInstance, candidates [][]types.Tag) int {
+	untagged := -1
+	for i, tags := range candidates {
+		id, tagged := ec2TagValue(tags, resourceIDTagKey)
+		if !tagged {
+			if untagged < 0 {
+				untagged = i
+			}
+			continue
+		}
+		if id == instance.ID {
+			return i
+		}
+	}
+	return untagged
+}
+
+// ec2TagValue returns the value of the tag with the given key
+func ec2TagValue(tags []types.Tag, key string) (string, bool) {
+	for _, tag := range tags {
+		if aws.ToString(tag.Key) == key {
+			return aws.ToString(tag.Value), true
+		}
+	}
+	return "", false
+}
+
+// ec2StateTags converts EC2 tags to the tags property of a resource's state, leaving
+// out the resource ID and managed marker tags that Runestone manages itself
+func ec2StateTags(tags []types.Tag) map[string]interface{} {
+	result := make(map[string]interface{})
+	for _, tag := range tags {
+		if tag.Key == nil || tag.Value == nil || isManagedTag(*tag.Key) {
+			continue
+		}
+		result[*tag.Key] = *tag.Value
+	}
+	return result
+}
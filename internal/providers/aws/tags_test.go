@@ -0,0 +1,164 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithDefaultTags(t *testing.T) {
+	provider := NewProvider()
+	provider.defaultTags = map[string]string{
+		"Environment": "production",
+		"Team":        "platform",
+	}
+
+	instance := config.ResourceInstance{
+		Kind: "aws:s3:bucket",
+		Name: "test-bucket",
+		Properties: map[string]interface{}{
+			"tags": map[string]interface{}{
+				"Team": "data",
+			},
+		},
+	}
+
+	merged := provider.withDefaultTags(instance)
+
+	assert.Equal(t, map[string]interface{}{
+		"Environment": "production",
+		"Team":        "data",
+	}, merged.Properties["tags"])
+
+	// The original instance must not be modified
+	assert.Equal(t, map[string]interface{}{"Team": "data"}, instance.Properties["tags"])
+}
+
+func TestStripDefaultTags(t *testing.T) {
+	provider := NewProvider()
+	provider.defaultTags = map[string]string{
+		"Environment": "production",
+		"Team":        "platform",
+	}
+
+	t.Run("injected defaults are hidden", func(t *testing.T) {
+		instance := config.ResourceInstance{
+			Properties: map[string]interface{}{
+				"tags": map[string]interface{}{"Name": "web"},
+			},
+		}
+		state := map[string]interface{}{
+			"tags": map[string]interface{}{
+				"Name":        "web",
+				"Environment": "production",
+				"Team":        "platform",
+			},
+		}
+
+		stripped := provider.stripDefaultTags(instance, state)
+		assert.Equal(t, map[string]interface{}{"Name": "web"}, stripped["tags"])
+	})
+
+	t.Run("changed defaults are kept", func(t *testing.T) {
+		instance := config.ResourceInstance{
+			Properties: map[string]interface{}{},
+		}
+		state := map[string]interface{}{
+			"tags": map[string]interface{}{
+				"Environment": "staging",
+				"Team":        "platform",
+			},
+		}
+
+		stripped := provider.stripDefaultTags(instance, state)
+		assert.Equal(t, map[string]interface{}{"Environment": "staging"}, stripped["tags"])
+	})
+
+	t.Run("tags removed when only defaults remain", func(t *testing.T) {
+		instance := config.ResourceInstance{
+			Properties: map[string]interface{}{},
+		}
+		state := map[string]interface{}{
+			"name": "test-bucket",
+			"tags": map[string]interface{}{
+				"Environment": "production",
+			},
+		}
+
+		stripped := provider.stripDefaultTags(instance, state)
+		_, hasTags := stripped["tags"]
+		assert.False(t, hasTags)
+		assert.Equal(t, "test-bucket", stripped["name"])
+	})
+}
+
+func TestStringTags(t *testing.T) {
+	assert.Equal(t, map[string]string{"Environment": "prod"}, stringTags(map[string]interface{}{
+		"tags": map[string]interface{}{"Environment": "prod", "Count": 3},
+	}))
+	assert.Empty(t, stringTags(map[string]interface{}{}))
+}
+
+func TestMatchResourceID(t *testing.T) {
+	instance := config.ResourceInstance{ID: "aws:ec2:instance.web-1", Kind: "aws:ec2:instance", Name: "web"}
+	tagged := func(id string) []types.Tag {
+		return []types.Tag{
+			{Key: aws.String("Name"), Value: aws.String("web")},
+			{Key: aws.String(resourceIDTagKey), Value: aws.String(id)},
+		}
+	}
+	untagged := []types.Tag{{Key: aws.String("Name"), Value: aws.String("web")}}
+
+	tests := []struct {
+		name       string
+		candidates [][]types.Tag
+		expected   int
+	}{
+		{name: "prefers the candidate with the instance ID", candidates: [][]types.Tag{tagged("aws:ec2:instance.web-0"), untagged, tagged("aws:ec2:instance.web-1")}, expected: 2},
+		{name: "falls back to a candidate without an ID", candidates: [][]types.Tag{tagged("aws:ec2:instance.web-0"), untagged}, expected: 1},
+		{name: "never matches another instance's resource", candidates: [][]types.Tag{tagged("aws:ec2:instance.web-0")}, expected: -1},
+		{name: "no candidates", candidates: nil, expected: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, matchResourceID(instance, tt.candidates))
+		})
+	}
+}
+
+func TestEC2StateTags_OmitsRunestoneTags(t *testing.T) {
+	tags := []types.Tag{
+		{Key: aws.String("Name"), Value: aws.String("web")},
+		{Key: aws.String(resourceIDTagKey), Value: aws.String("aws:ec2:instance.web")},
+		{Key: aws.String(managedTagKey), Value: aws.String("true")},
+	}
+
+	assert.Equal(t, map[string]interface{}{"Name": "web"}, ec2StateTags(tags))
+}
+
+func TestIgnoredTagPrefixesFromProviderConfig(t *testing.T) {
+	prefixes, err := ignoredTagPrefixesFromProviderConfig(map[string]interface{}{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"aws:"}, prefixes)
+
+	prefixes, err = ignoredTagPrefixesFromProviderConfig(map[string]interface{}{
+		"ignore_tag_prefixes": []interface{}{"aws:", "kubernetes.io/"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"aws:", "kubernetes.io/"}, prefixes)
+
+	prefixes, err = ignoredTagPrefixesFromProviderConfig(map[string]interface{}{
+		"ignore_tag_prefixes": []interface{}{},
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, prefixes)
+
+	_, err = ignoredTagPrefixesFromProviderConfig(map[string]interface{}{
+		"ignore_tag_prefixes": "aws:",
+	})
+	assert.ErrorContains(t, err, "ignore_tag_prefixes must be a list")
+}
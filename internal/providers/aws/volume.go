@@ -0,0 +1,413 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// volumeWaitTimeout bounds how long to wait for a volume to become available before it
+// is attached or after it is detached
+const volumeWaitTimeout = 5 * time.Minute
+
+// validVolumeTypes lists the EBS volume types that can be created
+var validVolumeTypes = []string{"gp2", "gp3", "io1", "io2", "st1", "sc1", "standard"}
+
+// ec2VolumeAPI is the subset of the EC2 API used to manage EBS volumes
+type ec2VolumeAPI interface {
+	DescribeVolumes(ctx context.Context, params *ec2.DescribeVolumesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error)
+	AttachVolume(ctx context.Context, params *ec2.AttachVolumeInput, optFns ...func(*ec2.Options)) (*ec2.AttachVolumeOutput, error)
+	DetachVolume(ctx context.Context, params *ec2.DetachVolumeInput, optFns ...func(*ec2.Options)) (*ec2.DetachVolumeOutput, error)
+	DeleteVolume(ctx context.Context, params *ec2.DeleteVolumeInput, optFns ...func(*ec2.Options)) (*ec2.DeleteVolumeOutput, error)
+}
+
+// validateVolume validates EBS volume configuration
+func (p *Provider) validateVolume(instance config.ResourceInstance) error {
+	if instance.Name == "" {
+		return fmt.Errorf("volume name cannot be empty")
+	}
+
+	size, err := volumeSize(instance)
+	if err != nil {
+		return err
+	}
+	if size <= 0 {
+		return fmt.Errorf("size must be positive")
+	}
+
+	if zone, ok := instance.Properties["availability_zone"].(string); !ok || zone == "" {
+		return fmt.Errorf("availability_zone is required for volume")
+	}
+
+	volumeType := "gp3"
+	if typeVal, exists := instance.Properties["type"]; exists {
+		typeStr, ok := typeVal.(string)
+		if !ok || !isValidVolumeType(typeStr) {
+			return fmt.Errorf("invalid volume type %v: must be one of %v", typeVal, validVolumeTypes)
+		}
+		volumeType = typeStr
+	}
+
+	if encryptedVal, exists := instance.Properties["encrypted"]; exists {
+		if _, ok := encryptedVal.(bool); !ok {
+			return fmt.Errorf("encrypted must be a boolean")
+		}
+	}
+
+	if iopsVal, exists := instance.Properties["iops"]; exists {
+		iops, ok := iopsVal.(int)
+		if !ok || iops <= 0 {
+			return fmt.Errorf("iops must be a positive integer")
+		}
+		switch volumeType {
+		case "gp3", "io1", "io2":
+		default:
+			return fmt.Errorf("iops can only be set for gp3, io1 and io2 volumes, not %s", volumeType)
+		}
+	} else if volumeType == "io1" || volumeType == "io2" {
+		return fmt.Errorf("iops is required for %s volumes", volumeType)
+	}
+
+	_, hasInstance := instance.Properties["instance_id"]
+	_, hasDevice := instance.Properties["device_name"]
+	if hasInstance != hasDevice {
+		return fmt.Errorf("instance_id and device_name must be set together to attach the volume")
+	}
+	if hasInstance {
+		if id, ok := instance.Properties["instance_id"].(string); !ok || id == "" {
+			return fmt.Errorf("instance_id must be a non-empty string")
+		}
+		if device, ok := instance.Properties["device_name"].(string); !ok || device == "" {
+			return fmt.Errorf("device_name must be a non-empty string")
+		}
+	}
+
+	if tagsVal, exists := instance.Properties["tags"]; exists {
+		if _, ok := tagsVal.(map[string]interface{}); !ok {
+			return fmt.Errorf("tags must be a map")
+		}
+	}
+
+	return nil
+}
+
+// isValidVolumeType reports whether an EBS volume type can be created
+func isValidVolumeType(volumeType string) bool {
+	for _, valid := range validVolumeTypes {
+		if volumeType == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// getVolumeState retrieves the current state of an EBS volume, found by its Name and
+// resource ID tags. The type, encryption and IOPS are reported only when configured,
+// since AWS fills in defaults for them.
+func (p *Provider) getVolumeState(ctx context.Context, instance config.ResourceInstance) (map[string]interface{}, error) {
+	result, err := p.ec2Client.DescribeVolumes(ctx, &ec2.DescribeVolumesInput{
+		Filters: []types.Filter{
+			{
+				Name:   aws.String("tag:Name"),
+				Values: []string{instance.Name},
+			},
+			{
+				Name:   aws.String("status"),
+				Values: []string{"creating", "available", "in-use", "error"},
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe volume %s: %w", instance.Name, err)
+	}
+
+	candidates := make([][]types.Tag, len(result.Volumes))
+	for i, volume := range result.Volumes {
+		candidates[i] = volume.Tags
+	}
+	match := matchResourceID(instance, candidates)
+	if match < 0 {
+		return nil, nil // Volume doesn't exist
+	}
+
+	return volumeState(instance, result.Volumes[match]), nil
+}
+
+// volumeState builds the state of a volume as configured by instance
+func volumeState(instance config.ResourceInstance, volume types.Volume) map[string]interface{} {
+	state := map[string]interface{}{
+		"volume_id":         aws.ToString(volume.VolumeId),
+		"state":             string(volume.State),
+		"size":              int(aws.ToInt32(volume.Size)),
+		"availability_zone": aws.ToString(volume.AvailabilityZone),
+	}
+
+	if _, configured := instance.Properties["type"]; configured {
+		state["type"] = string(volume.VolumeType)
+	}
+	if _, configured := instance.Properties["encrypted"]; configured {
+		state["encrypted"] = aws.ToBool(volume.Encrypted)
+	}
+	if _, configured := instance.Properties["iops"]; configured && volume.Iops != nil {
+		state["iops"] = int(*volume.Iops)
+	}
+
+	for _, attachment := range volume.Attachments {
+		switch attachment.State {
+		case types.VolumeAttachmentStateAttached, types.VolumeAttachmentStateAttaching:
+			state["instance_id"] = aws.ToString(attachment.InstanceId)
+			state["device_name"] = aws.ToString(attachment.Device)
+		}
+	}
+
+	// The Name tag identifies the volume, so it's only reported when declared
+	declared, _ := instance.Properties["tags"].(map[string]interface{})
+	tags := ec2StateTags(volume.Tags)
+	if _, isDeclared := declared["Name"]; !isDeclared {
+		delete(tags, "Name")
+	}
+	if len(tags) > 0 {
+		state["tags"] = tags
+	}
+
+	return state
+}
+
+// volumeSize returns a volume's size in GiB. commit and preview don't validate resources
+// first, so a missing or non-integer size is reported rather than assumed.
+func volumeSize(instance config.ResourceInstance) (int, error) {
+	size, ok := instance.Properties["size"].(int)
+	if !ok {
+		return 0, fmt.Errorf("size is required for volume and must be an integer number of GiB")
+	}
+	return size, nil
+}
+
+// createVolume creates an EBS volume and, when instance_id and device_name are set,
+// attaches it to the instance once it is available
+func (p *Provider) createVolume(ctx context.Context, instance config.ResourceInstance) error {
+	size, err := volumeSize(instance)
+	if err != nil {
+		return err
+	}
+	zone, ok := instance.Properties["availability_zone"].(string)
+	if !ok {
+		return fmt.Errorf("availability_zone is required for volume")
+	}
+
+	volumeType := "gp3"
+	if typeStr, ok := instance.Properties["type"].(string); ok {
+		volumeType = typeStr
+	}
+
+	input := &ec2.CreateVolumeInput{
+		AvailabilityZone: aws.String(zone),
+		Size:             aws.Int32(int32(size)),
+		VolumeType:       types.VolumeType(volumeType),
+		TagSpecifications: []types.TagSpecification{
+			{
+				ResourceType: types.ResourceTypeVolume,
+				Tags:         volumeTags(instance),
+			},
+		},
+	}
+	if encrypted, ok := instance.Properties["encrypted"].(bool); ok {
+		input.Encrypted = aws.Bool(encrypted)
+	}
+	if iops, ok := instance.Properties["iops"].(int); ok {
+		input.Iops = aws.Int32(int32(iops))
+	}
+
+	result, err := p.ec2Client.CreateVolume(ctx, input)
+	if err != nil {
+		return fmt.Errorf("failed to create volume %s: %w", instance.Name, err)
+	}
+
+	instanceID, device := volumeAttachment(instance)
+	if instanceID == "" {
+		return nil
+	}
+	return attachVolume(ctx, p.ec2Client, aws.ToString(result.VolumeId), instanceID, device, p.defaultWaitTimeoutOr(volumeWaitTimeout))
+}
+
+// updateVolume resizes or retypes a volume in place, brings its tags in line with the
+// configuration and moves its attachment when instance_id or device_name change. The
+// availability zone and encryption can't be changed without replacing the volume.
+func (p *Provider) updateVolume(ctx context.Context, instance config.ResourceInstance, currentState map[string]interface{}) error {
+	volumeID, ok := currentState["volume_id"].(string)
+	if !ok {
+		return fmt.Errorf("volume_id not found in current state")
+	}
+
+	if zone := instance.Properties["availability_zone"]; zone != currentState["availability_zone"] {
+		return fmt.Errorf("volume %s can't be moved from %v to %v; replace it instead", instance.Name, currentState["availability_zone"], zone)
+	}
+	if encrypted, ok := instance.Properties["encrypted"]; ok && encrypted != currentState["encrypted"] {
+		return fmt.Errorf("encryption of volume %s can't be changed in place; replace it instead", instance.Name)
+	}
+
+	modify := &ec2.ModifyVolumeInput{VolumeId: aws.String(volumeID)}
+	size, err := volumeSize(instance)
+	if err != nil {
+		return err
+	}
+	modified := false
+	if size != currentState["size"] {
+		modify.Size = aws.Int32(int32(size))
+		modified = true
+	}
+	if volumeType, ok := instance.Properties["type"].(string); ok && volumeType != currentState["type"] {
+		modify.VolumeType = types.VolumeType(volumeType)
+		modified = true
+	}
+	if iops, ok := instance.Properties["iops"].(int); ok && iops != currentState["iops"] {
+		modify.Iops = aws.Int32(int32(iops))
+		modified = true
+	}
+	if modified {
+		if _, err := p.ec2Client.ModifyVolume(ctx, modify); err != nil {
+			return fmt.Errorf("failed to modify volume %s: %w", instance.Name, err)
+		}
+	}
+
+	if _, err := p.ec2Client.CreateTags(ctx, &ec2.CreateTagsInput{
+		Resources: []string{volumeID},
+		Tags:      volumeTags(instance),
+	}); err != nil {
+		return fmt.Errorf("failed to update tags for volume %s: %w", instance.Name, err)
+	}
+
+	currentTags, _ := currentState["tags"].(map[string]interface{})
+	if removed := p.removedTagKeys(currentTags, stringTags(instance.Properties)); len(removed) > 0 {
+		deleteTags := make([]types.Tag, 0, len(removed))
+		for _, key := range removed {
+			deleteTags = append(deleteTags, types.Tag{Key: aws.String(key)})
+		}
+		if _, err := p.ec2Client.DeleteTags(ctx, &ec2.DeleteTagsInput{
+			Resources: []string{volumeID},
+			Tags:      deleteTags,
+		}); err != nil {
+			return fmt.Errorf("failed to remove tags from volume %s: %w", instance.Name, err)
+		}
+	}
+
+	instanceID, device := volumeAttachment(instance)
+	if instanceID == currentState["instance_id"] && device == currentState["device_name"] {
+		return nil
+	}
+	if _, attached := currentState["instance_id"]; attached {
+		if err := detachVolume(ctx, p.ec2Client, volumeID, p.defaultWaitTimeoutOr(volumeWaitTimeout)); err != nil {
+			return err
+		}
+	}
+	if instanceID == "" {
+		return nil
+	}
+	return attachVolume(ctx, p.ec2Client, volumeID, instanceID, device, p.defaultWaitTimeoutOr(volumeWaitTimeout))
+}
+
+// deleteVolume detaches an EBS volume if it is attached and then deletes it
+func (p *Provider) deleteVolume(ctx context.Context, instance config.ResourceInstance) error {
+	state, err := p.getVolumeState(ctx, instance)
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		return nil // Volume already deleted
+	}
+
+	return deleteVolumeFromState(ctx, p.ec2Client, state, p.defaultWaitTimeoutOr(volumeWaitTimeout))
+}
+
+// deleteVolumeFromState deletes the volume described by state, detaching it first and
+// waiting for it to become available when it is attached to an instance
+func deleteVolumeFromState(ctx context.Context, client ec2VolumeAPI, state map[string]interface{}, timeout time.Duration) error {
+	volumeID := state["volume_id"].(string)
+
+	if _, attached := state["instance_id"]; attached {
+		if err := detachVolume(ctx, client, volumeID, timeout); err != nil {
+			return err
+		}
+	}
+
+	_, err := client.DeleteVolume(ctx, &ec2.DeleteVolumeInput{
+		VolumeId: aws.String(volumeID),
+	})
+	if err != nil {
+		if isResourceNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to delete volume %s: %w", volumeID, err)
+	}
+
+	return nil
+}
+
+// attachVolume waits for a volume to become available and attaches it to an instance
+func attachVolume(ctx context.Context, client ec2VolumeAPI, volumeID, instanceID, device string, timeout time.Duration) error {
+	if err := waitForVolumeAvailable(ctx, client, volumeID, timeout); err != nil {
+		return err
+	}
+
+	_, err := client.AttachVolume(ctx, &ec2.AttachVolumeInput{
+		VolumeId:   aws.String(volumeID),
+		InstanceId: aws.String(instanceID),
+		Device:     aws.String(device),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to attach volume %s to %s as %s: %w", volumeID, instanceID, device, err)
+	}
+
+	return nil
+}
+
+// detachVolume detaches a volume from its instance and waits until it is available
+func detachVolume(ctx context.Context, client ec2VolumeAPI, volumeID string, timeout time.Duration) error {
+	_, err := client.DetachVolume(ctx, &ec2.DetachVolumeInput{
+		VolumeId: aws.String(volumeID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to detach volume %s: %w", volumeID, err)
+	}
+
+	return waitForVolumeAvailable(ctx, client, volumeID, timeout)
+}
+
+// waitForVolumeAvailable polls a volume until its state is available
+func waitForVolumeAvailable(ctx context.Context, client ec2VolumeAPI, volumeID string, timeout time.Duration) error {
+	waiter := ec2.NewVolumeAvailableWaiter(client, func(options *ec2.VolumeAvailableWaiterOptions) {
+		options.MinDelay = 5 * time.Second
+	})
+	err := waiter.Wait(ctx, &ec2.DescribeVolumesInput{
+		VolumeIds: []string{volumeID},
+	}, timeout)
+	if err != nil {
+		return fmt.Errorf("failed waiting for volume %s to become available: %w", volumeID, err)
+	}
+	return nil
+}
+
+// volumeAttachment returns the instance and device a volume is configured to attach to
+func volumeAttachment(instance config.ResourceInstance) (string, string) {
+	instanceID, _ := instance.Properties["instance_id"].(string)
+	device, _ := instance.Properties["device_name"].(string)
+	return instanceID, device
+}
+
+// volumeTags returns the tags to set on a volume, including the Name and resource ID
+// tags that identify it
+func volumeTags(instance config.ResourceInstance) []types.Tag {
+	tags := []types.Tag{{Key: aws.String("Name"), Value: aws.String(instance.Name)}, resourceIDTag(instance)}
+	for key, value := range stringTags(instance.Properties) {
+		if key == "Name" {
+			continue
+		}
+		tags = append(tags, types.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	return tags
+}
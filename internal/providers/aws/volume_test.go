@@ -0,0 +1,209 @@
+package aws
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEC2Volumes tracks a single volume's state and records the calls made against it
+type fakeEC2Volumes struct {
+	state types.VolumeState
+	calls []string
+}
+
+func (f *fakeEC2Volumes) DescribeVolumes(ctx context.Context, params *ec2.DescribeVolumesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVolumesOutput, error) {
+	f.calls = append(f.calls, "DescribeVolumes")
+	return &ec2.DescribeVolumesOutput{
+		Volumes: []types.Volume{{VolumeId: aws.String("vol-0abc"), State: f.state}},
+	}, nil
+}
+
+func (f *fakeEC2Volumes) AttachVolume(ctx context.Context, params *ec2.AttachVolumeInput, optFns ...func(*ec2.Options)) (*ec2.AttachVolumeOutput, error) {
+	f.calls = append(f.calls, "AttachVolume "+aws.ToString(params.InstanceId)+" "+aws.ToString(params.Device))
+	f.state = types.VolumeStateInUse
+	return &ec2.AttachVolumeOutput{}, nil
+}
+
+func (f *fakeEC2Volumes) DetachVolume(ctx context.Context, params *ec2.DetachVolumeInput, optFns ...func(*ec2.Options)) (*ec2.DetachVolumeOutput, error) {
+	f.calls = append(f.calls, "DetachVolume")
+	f.state = types.VolumeStateAvailable
+	return &ec2.DetachVolumeOutput{}, nil
+}
+
+func (f *fakeEC2Volumes) DeleteVolume(ctx context.Context, params *ec2.DeleteVolumeInput, optFns ...func(*ec2.Options)) (*ec2.DeleteVolumeOutput, error) {
+	f.calls = append(f.calls, "DeleteVolume "+aws.ToString(params.VolumeId))
+	return &ec2.DeleteVolumeOutput{}, nil
+}
+
+func TestDeleteVolume_DetachesAttachedVolumeFirst(t *testing.T) {
+	fake := &fakeEC2Volumes{state: types.VolumeStateInUse}
+	state := map[string]interface{}{
+		"volume_id":   "vol-0abc",
+		"state":       "in-use",
+		"instance_id": "i-0123",
+		"device_name": "/dev/sdf",
+	}
+
+	err := deleteVolumeFromState(context.Background(), fake, state, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DetachVolume", "DescribeVolumes", "DeleteVolume vol-0abc"}, fake.calls)
+}
+
+func TestDeleteVolume_DeletesDetachedVolumeDirectly(t *testing.T) {
+	fake := &fakeEC2Volumes{state: types.VolumeStateAvailable}
+	state := map[string]interface{}{"volume_id": "vol-0abc", "state": "available"}
+
+	err := deleteVolumeFromState(context.Background(), fake, state, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DeleteVolume vol-0abc"}, fake.calls)
+}
+
+func TestAttachVolume_WaitsUntilAvailable(t *testing.T) {
+	fake := &fakeEC2Volumes{state: types.VolumeStateAvailable}
+
+	err := attachVolume(context.Background(), fake, "vol-0abc", "i-0123", "/dev/sdf", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DescribeVolumes", "AttachVolume i-0123 /dev/sdf"}, fake.calls)
+}
+
+func TestVolumeState(t *testing.T) {
+	instance := config.ResourceInstance{
+		Kind: "aws:ec2:volume",
+		Name: "data",
+		Properties: map[string]interface{}{
+			"size":              100,
+			"availability_zone": "us-east-1a",
+			"type":              "gp3",
+			"tags":              map[string]interface{}{"Environment": "prod"},
+		},
+	}
+	volume := types.Volume{
+		VolumeId:         aws.String("vol-0abc"),
+		State:            types.VolumeStateInUse,
+		Size:             aws.Int32(100),
+		AvailabilityZone: aws.String("us-east-1a"),
+		VolumeType:       types.VolumeTypeGp3,
+		Iops:             aws.Int32(3000),
+		Encrypted:        aws.Bool(true),
+		Attachments: []types.VolumeAttachment{
+			{InstanceId: aws.String("i-0123"), Device: aws.String("/dev/sdf"), State: types.VolumeAttachmentStateAttached},
+		},
+		Tags: []types.Tag{
+			{Key: aws.String("Name"), Value: aws.String("data")},
+			{Key: aws.String("Environment"), Value: aws.String("prod")},
+		},
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"volume_id":         "vol-0abc",
+		"state":             "in-use",
+		"size":              100,
+		"availability_zone": "us-east-1a",
+		"type":              "gp3",
+		"instance_id":       "i-0123",
+		"device_name":       "/dev/sdf",
+		"tags":              map[string]interface{}{"Environment": "prod"},
+	}, volumeState(instance, volume))
+
+	// A volume with only its Name tag reports no tags, matching a configuration without any
+	delete(instance.Properties, "tags")
+	volume.Tags = volume.Tags[:1]
+	assert.NotContains(t, volumeState(instance, volume), "tags")
+}
+
+func TestValidateVolume(t *testing.T) {
+	provider := NewProvider()
+
+	tests := []struct {
+		name       string
+		properties map[string]interface{}
+		wantErr    string
+	}{
+		{
+			name: "valid attached volume",
+			properties: map[string]interface{}{
+				"size":              100,
+				"type":              "io2",
+				"iops":              4000,
+				"availability_zone": "us-east-1a",
+				"encrypted":         true,
+				"instance_id":       "i-0123",
+				"device_name":       "/dev/sdf",
+			},
+		},
+		{
+			name:       "missing size",
+			properties: map[string]interface{}{"availability_zone": "us-east-1a"},
+			wantErr:    "size is required",
+		},
+		{
+			name:       "missing availability zone",
+			properties: map[string]interface{}{"size": 10},
+			wantErr:    "availability_zone is required",
+		},
+		{
+			name:       "unknown type",
+			properties: map[string]interface{}{"size": 10, "availability_zone": "us-east-1a", "type": "gp9"},
+			wantErr:    "invalid volume type gp9",
+		},
+		{
+			name:       "iops on gp2",
+			properties: map[string]interface{}{"size": 10, "availability_zone": "us-east-1a", "type": "gp2", "iops": 3000},
+			wantErr:    "iops can only be set for gp3, io1 and io2 volumes",
+		},
+		{
+			name:       "io1 without iops",
+			properties: map[string]interface{}{"size": 10, "availability_zone": "us-east-1a", "type": "io1"},
+			wantErr:    "iops is required for io1 volumes",
+		},
+		{
+			name:       "instance without device",
+			properties: map[string]interface{}{"size": 10, "availability_zone": "us-east-1a", "instance_id": "i-0123"},
+			wantErr:    "instance_id and device_name must be set together",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := provider.ValidateResource(config.ResourceInstance{
+				ID:         "aws:ec2:volume.data",
+				Kind:       "aws:ec2:volume",
+				Name:       "data",
+				Properties: tt.properties,
+			})
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateVolume_RejectsInvalidSize(t *testing.T) {
+	provider := &Provider{}
+	currentState := map[string]interface{}{"volume_id": "vol-0abc", "availability_zone": "us-east-1a", "size": 20}
+
+	for _, size := range []interface{}{nil, 20.5, "${aws:ec2:instance.web.volume_size}"} {
+		instance := config.ResourceInstance{
+			Kind:       "aws:ec2:volume",
+			Name:       "data",
+			Properties: map[string]interface{}{"availability_zone": "us-east-1a", "size": size},
+		}
+
+		err := provider.updateVolume(context.Background(), instance, currentState)
+		assert.EqualError(t, err, "size is required for volume and must be an integer number of GiB")
+
+		err = provider.createVolume(context.Background(), instance)
+		assert.EqualError(t, err, "size is required for volume and must be an integer number of GiB")
+	}
+}
@@ -55,28 +55,26 @@ func (p *Provider) getVPCState(ctx context.Context, instance config.ResourceInst
 		return nil, fmt.Errorf("failed to describe VPC %s: %w", instance.Name, err)
 	}
 
-	if len(result.Vpcs) == 0 {
+	candidates := make([][]types.Tag, len(result.Vpcs))
+	for i, candidate := range result.Vpcs {
+		candidates[i] = candidate.Tags
+	}
+	match := matchResourceID(instance, candidates)
+	if match < 0 {
 		return nil, nil // VPC doesn't exist
 	}
 
-	vpc := result.Vpcs[0]
-
-	// Convert tags to map
-	tags := make(map[string]interface{})
-	for _, tag := range vpc.Tags {
-		if tag.Key != nil && tag.Value != nil {
-			tags[*tag.Key] = *tag.Value
-		}
-	}
+	return vpcState(result.Vpcs[match]), nil
+}
 
-	state := map[string]interface{}{
+// vpcState builds the state of a VPC resource from the described VPC
+func vpcState(vpc types.Vpc) map[string]interface{} {
+	return map[string]interface{}{
 		"vpc_id":     *vpc.VpcId,
 		"cidr_block": *vpc.CidrBlock,
 		"state":      string(vpc.State),
-		"tags":       tags,
+		"tags":       ec2StateTags(vpc.Tags),
 	}
-
-	return state, nil
 }
 
 // createVPC creates a new VPC
@@ -104,6 +102,7 @@ func (p *Provider) createVPC(ctx context.Context, instance config.ResourceInstan
 				Key:   aws.String("Name"),
 				Value: aws.String(instance.Name),
 			},
+			resourceIDTag(instance),
 		},
 	}
 
@@ -229,19 +228,17 @@ func (p *Provider) getSubnetState(ctx context.Context, instance config.ResourceI
 		return nil, fmt.Errorf("failed to describe subnet %s: %w", instance.Name, err)
 	}
 
-	if len(result.Subnets) == 0 {
+	candidates := make([][]types.Tag, len(result.Subnets))
+	for i, candidate := range result.Subnets {
+		candidates[i] = candidate.Tags
+	}
+	match := matchResourceID(instance, candidates)
+	if match < 0 {
 		return nil, nil // Subnet doesn't exist
 	}
 
-	subnet := result.Subnets[0]
-
-	// Convert tags to map
-	tags := make(map[string]interface{})
-	for _, tag := range subnet.Tags {
-		if tag.Key != nil && tag.Value != nil {
-			tags[*tag.Key] = *tag.Value
-		}
-	}
+	subnet := result.Subnets[match]
+	tags := ec2StateTags(subnet.Tags)
 
 	state := map[string]interface{}{
 		"subnet_id":         *subnet.SubnetId,
@@ -289,6 +286,7 @@ func (p *Provider) createSubnet(ctx context.Context, instance config.ResourceIns
 				Key:   aws.String("Name"),
 				Value: aws.String(instance.Name),
 			},
+			resourceIDTag(instance),
 		},
 	}
 
@@ -383,19 +381,17 @@ func (p *Provider) getInternetGatewayState(ctx context.Context, instance config.
 		return nil, fmt.Errorf("failed to describe internet gateway %s: %w", instance.Name, err)
 	}
 
-	if len(result.InternetGateways) == 0 {
+	candidates := make([][]types.Tag, len(result.InternetGateways))
+	for i, candidate := range result.InternetGateways {
+		candidates[i] = candidate.Tags
+	}
+	match := matchResourceID(instance, candidates)
+	if match < 0 {
 		return nil, nil // Internet gateway doesn't exist
 	}
 
-	igw := result.InternetGateways[0]
-
-	// Convert tags to map
-	tags := make(map[string]interface{})
-	for _, tag := range igw.Tags {
-		if tag.Key != nil && tag.Value != nil {
-			tags[*tag.Key] = *tag.Value
-		}
-	}
+	igw := result.InternetGateways[match]
+	tags := ec2StateTags(igw.Tags)
 
 	state := map[string]interface{}{
 		"internet_gateway_id": *igw.InternetGatewayId,
@@ -426,6 +422,7 @@ func (p *Provider) createInternetGateway(ctx context.Context, instance config.Re
 				Key:   aws.String("Name"),
 				Value: aws.String(instance.Name),
 			},
+			resourceIDTag(instance),
 		},
 	}
 
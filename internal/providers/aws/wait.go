@@ -0,0 +1,151 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ataiva-software/runestone/internal/config"
+)
+
+const (
+	// visibilityTimeout bounds how long a created resource may take to show up in lookups
+	visibilityTimeout = 2 * time.Minute
+)
+
+// visibilityPollInterval is the delay between lookups while waiting for a created resource
+var visibilityPollInterval = 2 * time.Second
+
+// errWaitTimeout is returned by waitForState when a resource isn't ready before the timeout
+var errWaitTimeout = errors.New("timed out waiting for resource")
+
+// waitForState polls a resource every interval until isReady reports it is ready. It stops
+// with isReady's error when the resource can never become ready, with poll's error when
+// polling fails, with errWaitTimeout once timeout elapses, and with ctx.Err() as soon as
+// ctx is canceled, so an interrupt ends the wait immediately.
+func waitForState[T any](ctx context.Context, poll func(context.Context) (T, error), isReady func(T) (bool, error), timeout, interval time.Duration) error {
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		select {
+		case <-waitCtx.Done():
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return errWaitTimeout
+		case <-time.After(interval):
+		}
+
+		value, err := poll(waitCtx)
+		if err != nil {
+			// A poll cut short by the timeout is reported as a timeout on the next pass
+			if waitCtx.Err() != nil {
+				continue
+			}
+			return err
+		}
+
+		ready, err := isReady(value)
+		if err != nil {
+			return err
+		}
+		if ready {
+			return nil
+		}
+	}
+}
+
+// createVisible creates a resource that is looked up by its tags or name, then waits until
+// the lookup finds it. EC2 is eventually consistent, so a lookup right after the create
+// returns (such as a preview straight after a commit) could otherwise miss the resource and
+// plan to create it a second time.
+func (p *Provider) createVisible(ctx context.Context, instance config.ResourceInstance, create func(context.Context, config.ResourceInstance) error) error {
+	if err := create(ctx, instance); err != nil {
+		return err
+	}
+
+	return waitUntilVisible(ctx, instance.ID, func(ctx context.Context) (map[string]interface{}, error) {
+		return p.getResourceState(ctx, instance)
+	})
+}
+
+// waitUntilVisible polls lookup until it returns the state of a resource just created
+func waitUntilVisible(ctx context.Context, resourceID string, lookup func(context.Context) (map[string]interface{}, error)) error {
+	isVisible := func(state map[string]interface{}) (bool, error) {
+		return state != nil, nil
+	}
+
+	err := waitForState(ctx, lookup, isVisible, visibilityTimeout, visibilityPollInterval)
+	if errors.Is(err, errWaitTimeout) {
+		return fmt.Errorf("%s was created but still wasn't found after %v; wait before running preview or commit again, or it may be created twice", resourceID, visibilityTimeout)
+	}
+	if err != nil && ctx.Err() == nil {
+		return fmt.Errorf("%s was created but looking it up failed: %w", resourceID, err)
+	}
+	return err
+}
+
+// defaultWaitTimeoutFromProviderConfig returns the provider's default_wait_timeout, or
+// zero when each resource type should use its own default
+func defaultWaitTimeoutFromProviderConfig(providerConfig map[string]interface{}) (time.Duration, error) {
+	value, exists := providerConfig["default_wait_timeout"]
+	if !exists || value == nil || value == "" {
+		return 0, nil
+	}
+	return parseWaitTimeout("default_wait_timeout", value)
+}
+
+// waitTimeout returns how long to wait for a resource to become ready after a change: its
+// wait_timeout property, else its timeouts block's value for the change, else the
+// provider's default_wait_timeout, else fallback
+func (p *Provider) waitTimeout(instance config.ResourceInstance, change config.ChangeType, fallback time.Duration) (time.Duration, error) {
+	if timeout := instance.Timeouts.Timeout(change); timeout > 0 {
+		fallback = timeout
+	} else {
+		fallback = p.defaultWaitTimeoutOr(fallback)
+	}
+	return waitTimeoutProperty(instance, fallback)
+}
+
+// defaultWaitTimeoutOr returns the provider's default_wait_timeout, or fallback when it
+// isn't set
+func (p *Provider) defaultWaitTimeoutOr(fallback time.Duration) time.Duration {
+	if p.defaultWaitTimeout > 0 {
+		return p.defaultWaitTimeout
+	}
+	return fallback
+}
+
+// waitTimeoutProperty returns the wait_timeout property of a resource, accepting either
+// a duration string ("30m") or a number of seconds, or fallback when it isn't set
+func waitTimeoutProperty(instance config.ResourceInstance, fallback time.Duration) (time.Duration, error) {
+	value, exists := instance.Properties["wait_timeout"]
+	if !exists {
+		return fallback, nil
+	}
+	return parseWaitTimeout("wait_timeout", value)
+}
+
+// parseWaitTimeout parses a timeout given as a duration string or a number of seconds
+func parseWaitTimeout(name string, value interface{}) (time.Duration, error) {
+	switch v := value.(type) {
+	case int:
+		if v <= 0 {
+			return 0, fmt.Errorf("%s must be positive", name)
+		}
+		return time.Duration(v) * time.Second, nil
+	case string:
+		timeout, err := time.ParseDuration(v)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s '%s': %w", name, v, err)
+		}
+		if timeout <= 0 {
+			return 0, fmt.Errorf("%s must be positive", name)
+		}
+		return timeout, nil
+	default:
+		return 0, fmt.Errorf("%s must be a duration string or a number of seconds", name)
+	}
+}
@@ -0,0 +1,185 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePoll returns the given statuses in order, repeating the last one
+type fakePoll struct {
+	statuses []string
+	err      error
+	calls    int
+}
+
+func (f *fakePoll) poll(ctx context.Context) (string, error) {
+	f.calls++
+	if f.err != nil {
+		return "", f.err
+	}
+	if f.calls > len(f.statuses) {
+		return f.statuses[len(f.statuses)-1], nil
+	}
+	return f.statuses[f.calls-1], nil
+}
+
+func isAvailable(status string) (bool, error) {
+	if status == "failed" {
+		return false, errors.New("entered status failed")
+	}
+	return status == "available", nil
+}
+
+func TestWaitForState(t *testing.T) {
+	t.Run("returns once ready", func(t *testing.T) {
+		fake := &fakePoll{statuses: []string{"creating", "creating", "available"}}
+		require.NoError(t, waitForState(context.Background(), fake.poll, isAvailable, time.Second, time.Millisecond))
+		assert.Equal(t, 3, fake.calls)
+	})
+
+	t.Run("stops when the resource can't become ready", func(t *testing.T) {
+		fake := &fakePoll{statuses: []string{"creating", "failed", "available"}}
+		err := waitForState(context.Background(), fake.poll, isAvailable, time.Second, time.Millisecond)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "entered status failed")
+		assert.Equal(t, 2, fake.calls)
+	})
+
+	t.Run("returns poll errors", func(t *testing.T) {
+		fake := &fakePoll{err: assert.AnError}
+		err := waitForState(context.Background(), fake.poll, isAvailable, time.Second, time.Millisecond)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, 1, fake.calls)
+	})
+
+	t.Run("times out", func(t *testing.T) {
+		fake := &fakePoll{statuses: []string{"creating"}}
+		err := waitForState(context.Background(), fake.poll, isAvailable, 20*time.Millisecond, time.Millisecond)
+		assert.ErrorIs(t, err, errWaitTimeout)
+	})
+
+	t.Run("stops when the context is canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		fake := &fakePoll{statuses: []string{"creating"}}
+		err := waitForState(ctx, fake.poll, isAvailable, time.Minute, time.Hour)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Zero(t, fake.calls)
+	})
+}
+
+func TestProvider_WaitTimeout(t *testing.T) {
+	instance := config.ResourceInstance{Kind: "aws:rds:instance", Name: "db", Properties: map[string]interface{}{}}
+
+	provider := &Provider{}
+	timeout, err := provider.waitTimeout(instance, config.ChangeTypeCreate, 20*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 20*time.Minute, timeout)
+
+	provider.defaultWaitTimeout = 45 * time.Minute
+	timeout, err = provider.waitTimeout(instance, config.ChangeTypeCreate, 20*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 45*time.Minute, timeout)
+
+	// The timeouts block applies only to its own operation
+	instance.Timeouts = &config.Timeouts{Create: "1h"}
+	timeout, err = provider.waitTimeout(instance, config.ChangeTypeCreate, 20*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, timeout)
+	timeout, err = provider.waitTimeout(instance, config.ChangeTypeUpdate, 20*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 45*time.Minute, timeout)
+
+	instance.Properties["wait_timeout"] = "5m"
+	timeout, err = provider.waitTimeout(instance, config.ChangeTypeCreate, 20*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, timeout)
+}
+
+func TestWaitTimeoutProperty(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected time.Duration
+		wantErr  bool
+	}{
+		{name: "fallback when unset", value: nil, expected: defaultRDSWaitTimeout},
+		{name: "duration string", value: "45m", expected: 45 * time.Minute},
+		{name: "seconds as integer", value: 600, expected: 10 * time.Minute},
+		{name: "invalid duration string", value: "soon", wantErr: true},
+		{name: "negative seconds", value: -5, wantErr: true},
+		{name: "unsupported type", value: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance := config.ResourceInstance{
+				Kind:       "aws:rds:instance",
+				Name:       "test-db",
+				Properties: map[string]interface{}{},
+			}
+			if tt.value != nil {
+				instance.Properties["wait_timeout"] = tt.value
+			}
+
+			timeout, err := waitTimeoutProperty(instance, defaultRDSWaitTimeout)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, timeout)
+		})
+	}
+}
+
+func TestDefaultWaitTimeoutFromProviderConfig(t *testing.T) {
+	timeout, err := defaultWaitTimeoutFromProviderConfig(map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Zero(t, timeout)
+
+	timeout, err = defaultWaitTimeoutFromProviderConfig(map[string]interface{}{"default_wait_timeout": "45m"})
+	require.NoError(t, err)
+	assert.Equal(t, 45*time.Minute, timeout)
+
+	timeout, err = defaultWaitTimeoutFromProviderConfig(map[string]interface{}{"default_wait_timeout": 600})
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, timeout)
+
+	_, err = defaultWaitTimeoutFromProviderConfig(map[string]interface{}{"default_wait_timeout": "-1m"})
+	assert.EqualError(t, err, "default_wait_timeout must be positive")
+}
+
+func TestWaitUntilVisible(t *testing.T) {
+	original := visibilityPollInterval
+	visibilityPollInterval = time.Millisecond
+	defer func() { visibilityPollInterval = original }()
+
+	t.Run("waits for the lookup to find the resource", func(t *testing.T) {
+		lookups := 0
+		err := waitUntilVisible(context.Background(), "aws:ec2:vpc.main", func(ctx context.Context) (map[string]interface{}, error) {
+			lookups++
+			if lookups < 3 {
+				return nil, nil
+			}
+			return map[string]interface{}{"vpc_id": "vpc-0abc"}, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 3, lookups)
+	})
+
+	t.Run("reports lookup failures", func(t *testing.T) {
+		err := waitUntilVisible(context.Background(), "aws:ec2:vpc.main", func(ctx context.Context) (map[string]interface{}, error) {
+			return nil, assert.AnError
+		})
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Contains(t, err.Error(), "aws:ec2:vpc.main was created but looking it up failed")
+	})
+}
@@ -0,0 +1,260 @@
+package fake
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ataiva-software/runestone/internal/config"
+)
+
+// Operation is a Provider method a call was made to, or an error is injected into
+type Operation string
+
+// Operations recorded by the fake provider
+const (
+	OperationCreate   Operation = "create"
+	OperationUpdate   Operation = "update"
+	OperationDelete   Operation = "delete"
+	OperationRead     Operation = "read"
+	OperationValidate Operation = "validate"
+	OperationRename   Operation = "rename"
+)
+
+// Call is a recorded call to the provider
+type Call struct {
+	Operation Operation
+	ID        string
+}
+
+// Provider is an in-memory providers.Provider for tests. Resources are keyed by instance
+// ID, or <kind>.<name> for instances built without one, as the parser would set it. Create
+// and Update store the instance's properties as its state, Delete removes it, and every
+// call is recorded. It is safe for concurrent use, as the executor applies resources in
+// parallel.
+type Provider struct {
+	kinds []string
+
+	mutex      sync.Mutex
+	states     map[string]map[string]interface{}
+	errors     map[Operation]map[string]error
+	calls      []Call
+	fixedNames map[string]bool
+}
+
+// New creates a fake provider supporting kinds, with no resources
+func New(kinds ...string) *Provider {
+	return &Provider{
+		kinds:      kinds,
+		states:     make(map[string]map[string]interface{}),
+		errors:     make(map[Operation]map[string]error),
+		fixedNames: make(map[string]bool),
+	}
+}
+
+// SetState seeds the live state of a resource, as if it already existed. A nil state
+// removes the resource.
+func (p *Provider) SetState(id string, state map[string]interface{}) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if state == nil {
+		delete(p.states, id)
+		return
+	}
+	p.states[id] = copyState(state)
+}
+
+// State returns the live state of a resource, or nil when it doesn't exist
+func (p *Provider) State(id string) map[string]interface{} {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return copyState(p.states[id])
+}
+
+// FailOn makes every later call of operation for the resource id return err. A nil err
+// clears the failure.
+func (p *Provider) FailOn(operation Operation, id string, err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if err == nil {
+		delete(p.errors[operation], id)
+		return
+	}
+	if p.errors[operation] == nil {
+		p.errors[operation] = make(map[string]error)
+	}
+	p.errors[operation][id] = err
+}
+
+// FixNames makes resources of kinds impossible to rename, like kinds whose name is their
+// identity in the cloud
+func (p *Provider) FixNames(kinds ...string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for _, kind := range kinds {
+		p.fixedNames[kind] = true
+	}
+}
+
+// Calls returns every call made so far, in order
+func (p *Provider) Calls() []Call {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return append([]Call(nil), p.calls...)
+}
+
+// Created returns the IDs of the resources Create was called for, in order
+func (p *Provider) Created() []string {
+	return p.callsTo(OperationCreate)
+}
+
+// Updated returns the IDs of the resources Update was called for, in order
+func (p *Provider) Updated() []string {
+	return p.callsTo(OperationUpdate)
+}
+
+// Deleted returns the IDs of the resources Delete was called for, in order
+func (p *Provider) Deleted() []string {
+	return p.callsTo(OperationDelete)
+}
+
+// Renamed returns the IDs of the resources Rename was called for, in order, by the ID they
+// were renamed from
+func (p *Provider) Renamed() []string {
+	return p.callsTo(OperationRename)
+}
+
+// Reset forgets the recorded calls, keeping states and injected errors
+func (p *Provider) Reset() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.calls = nil
+}
+
+// Initialize accepts any configuration
+func (p *Provider) Initialize(ctx context.Context, providerConfig map[string]interface{}) error {
+	return nil
+}
+
+// Create stores the instance's properties as its state
+func (p *Provider) Create(ctx context.Context, instance config.ResourceInstance) error {
+	return p.call(OperationCreate, instance, func(id string) error {
+		if _, exists := p.states[id]; exists {
+			return fmt.Errorf("resource %s already exists", id)
+		}
+		p.states[id] = copyState(instance.Properties)
+		return nil
+	})
+}
+
+// Update replaces the state with the instance's properties
+func (p *Provider) Update(ctx context.Context, instance config.ResourceInstance, currentState map[string]interface{}) error {
+	return p.call(OperationUpdate, instance, func(id string) error {
+		if _, exists := p.states[id]; !exists {
+			return fmt.Errorf("resource %s does not exist", id)
+		}
+		p.states[id] = copyState(instance.Properties)
+		return nil
+	})
+}
+
+// Delete removes the resource; deleting a resource that doesn't exist succeeds
+func (p *Provider) Delete(ctx context.Context, instance config.ResourceInstance) error {
+	return p.call(OperationDelete, instance, func(id string) error {
+		delete(p.states, id)
+		return nil
+	})
+}
+
+// GetCurrentState returns a copy of the resource's state, or nil when it doesn't exist
+func (p *Provider) GetCurrentState(ctx context.Context, instance config.ResourceInstance) (map[string]interface{}, error) {
+	var state map[string]interface{}
+	err := p.call(OperationRead, instance, func(id string) error {
+		state = copyState(p.states[id])
+		return nil
+	})
+	return state, err
+}
+
+// ValidateResource accepts every instance unless an error is injected
+func (p *Provider) ValidateResource(instance config.ResourceInstance) error {
+	return p.call(OperationValidate, instance, func(id string) error { return nil })
+}
+
+// CanRename reports whether resources of kind can be renamed; every kind can unless
+// FixNames was called for it
+func (p *Provider) CanRename(kind string) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return !p.fixedNames[kind]
+}
+
+// Rename moves the state of from to to; the call is recorded under from's ID
+func (p *Provider) Rename(ctx context.Context, from, to config.ResourceInstance) error {
+	return p.call(OperationRename, from, func(id string) error {
+		if p.fixedNames[from.Kind] {
+			return fmt.Errorf("resources of kind %s can't be renamed", from.Kind)
+		}
+		state, exists := p.states[id]
+		if !exists {
+			return fmt.Errorf("resource %s does not exist", id)
+		}
+		if _, exists := p.states[instanceID(to)]; exists {
+			return fmt.Errorf("resource %s already exists", instanceID(to))
+		}
+		delete(p.states, id)
+		p.states[instanceID(to)] = state
+		return nil
+	})
+}
+
+// GetSupportedResourceTypes returns the kinds the provider was created with
+func (p *Provider) GetSupportedResourceTypes() []string {
+	return p.kinds
+}
+
+// call records a call and returns its injected error, or runs fn with the lock held
+func (p *Provider) call(operation Operation, instance config.ResourceInstance, fn func(id string) error) error {
+	id := instanceID(instance)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.calls = append(p.calls, Call{Operation: operation, ID: id})
+	if err := p.errors[operation][id]; err != nil {
+		return err
+	}
+	return fn(id)
+}
+
+// callsTo returns the IDs of the resources operation was called for
+func (p *Provider) callsTo(operation Operation) []string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	var ids []string
+	for _, call := range p.calls {
+		if call.Operation == operation {
+			ids = append(ids, call.ID)
+		}
+	}
+	return ids
+}
+
+// instanceID returns the key a resource is stored under
+func instanceID(instance config.ResourceInstance) string {
+	if instance.ID != "" {
+		return instance.ID
+	}
+	return instance.Kind + "." + instance.Name
+}
+
+// copyState copies the top level of a state, so callers can't change stored states
+func copyState(state map[string]interface{}) map[string]interface{} {
+	if state == nil {
+		return nil
+	}
+	copied := make(map[string]interface{}, len(state))
+	for key, value := range state {
+		copied[key] = value
+	}
+	return copied
+}
@@ -0,0 +1,99 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/ataiva-software/runestone/internal/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ providers.Provider = (*Provider)(nil)
+var _ providers.Renamer = (*Provider)(nil)
+
+func TestProvider_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	provider := New("test:bucket")
+	bucket := config.ResourceInstance{
+		ID:         "test:bucket.logs",
+		Kind:       "test:bucket",
+		Name:       "logs",
+		Properties: map[string]interface{}{"versioning": true},
+	}
+
+	state, err := provider.GetCurrentState(ctx, bucket)
+	require.NoError(t, err)
+	assert.Nil(t, state)
+
+	require.NoError(t, provider.Create(ctx, bucket))
+	assert.Error(t, provider.Create(ctx, bucket), "creating an existing resource fails")
+	assert.Equal(t, map[string]interface{}{"versioning": true}, provider.State("test:bucket.logs"))
+
+	bucket.Properties = map[string]interface{}{"versioning": false}
+	require.NoError(t, provider.Update(ctx, bucket, nil))
+	state, err = provider.GetCurrentState(ctx, bucket)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"versioning": false}, state)
+
+	// Returned states are copies
+	state["versioning"] = true
+	assert.Equal(t, false, provider.State("test:bucket.logs")["versioning"])
+
+	require.NoError(t, provider.Delete(ctx, bucket))
+	assert.Nil(t, provider.State("test:bucket.logs"))
+	provider.SetState("test:bucket.logs", map[string]interface{}{})
+	provider.SetState("test:bucket.logs", nil)
+	assert.Nil(t, provider.State("test:bucket.logs"), "seeding a nil state removes the resource")
+
+	assert.Equal(t, []string{"test:bucket.logs", "test:bucket.logs"}, provider.Created())
+	assert.Equal(t, []string{"test:bucket.logs"}, provider.Updated())
+	assert.Equal(t, []string{"test:bucket.logs"}, provider.Deleted())
+	assert.Len(t, provider.Calls(), 6)
+
+	provider.Reset()
+	assert.Empty(t, provider.Calls())
+}
+
+func TestProvider_FailOn(t *testing.T) {
+	ctx := context.Background()
+	provider := New("test:bucket")
+	provider.SetState("test:bucket.logs", map[string]interface{}{"versioning": true})
+
+	// Instances without an ID are keyed as the parser would key them
+	bucket := config.ResourceInstance{Kind: "test:bucket", Name: "logs"}
+	throttled := errors.New("Throttling: rate exceeded")
+	provider.FailOn(OperationRead, "test:bucket.logs", throttled)
+
+	_, err := provider.GetCurrentState(ctx, bucket)
+	assert.ErrorIs(t, err, throttled)
+	assert.Equal(t, []Call{{Operation: OperationRead, ID: "test:bucket.logs"}}, provider.Calls())
+
+	provider.FailOn(OperationRead, "test:bucket.logs", nil)
+	state, err := provider.GetCurrentState(ctx, bucket)
+	require.NoError(t, err)
+	assert.Equal(t, true, state["versioning"])
+}
+
+func TestProvider_Rename(t *testing.T) {
+	ctx := context.Background()
+	provider := New("test:server", "test:bucket")
+	provider.FixNames("test:bucket")
+	provider.SetState("test:server.web", map[string]interface{}{"size": "small"})
+
+	server := config.ResourceInstance{ID: "test:server.api", Kind: "test:server", Name: "api"}
+	require.NoError(t, provider.Rename(ctx, server.Renamed("test:server.web"), server))
+	assert.Nil(t, provider.State("test:server.web"))
+	assert.Equal(t, map[string]interface{}{"size": "small"}, provider.State("test:server.api"))
+	assert.Equal(t, []string{"test:server.web"}, provider.Renamed())
+
+	// The old resource is gone now
+	assert.ErrorContains(t, provider.Rename(ctx, server.Renamed("test:server.web"), server), "does not exist")
+
+	assert.True(t, provider.CanRename("test:server"))
+	assert.False(t, provider.CanRename("test:bucket"))
+	bucket := config.ResourceInstance{ID: "test:bucket.assets", Kind: "test:bucket", Name: "assets"}
+	assert.ErrorContains(t, provider.Rename(ctx, bucket.Renamed("test:bucket.logs"), bucket), "can't be renamed")
+}
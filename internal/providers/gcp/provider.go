@@ -0,0 +1,106 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/storage"
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/ataiva-software/runestone/internal/providers"
+	"google.golang.org/api/option"
+)
+
+// Provider implements the GCP provider
+type Provider struct {
+	project       string
+	storageClient *storage.Client
+}
+
+func init() {
+	providers.RegisterFactory("gcp", func() providers.Provider { return NewProvider() })
+}
+
+// NewProvider creates a new GCP provider
+func NewProvider() *Provider {
+	return &Provider{}
+}
+
+// Initialize sets up the GCP provider. The project setting is required; credentials come
+// from credentials_file if set, and otherwise from Application Default Credentials.
+func (p *Provider) Initialize(ctx context.Context, providerConfig map[string]interface{}) error {
+	project, _ := providerConfig["project"].(string)
+	if project == "" {
+		return fmt.Errorf("project is required for the GCP provider")
+	}
+	p.project = project
+
+	var opts []option.ClientOption
+	if credentialsFile, _ := providerConfig["credentials_file"].(string); credentialsFile != "" {
+		opts = append(opts, option.WithCredentialsFile(credentialsFile))
+	}
+
+	client, err := storage.NewClient(ctx, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create GCP storage client (project: %s): %w", project, err)
+	}
+	p.storageClient = client
+
+	return nil
+}
+
+// Create creates a new GCP resource
+func (p *Provider) Create(ctx context.Context, instance config.ResourceInstance) error {
+	switch instance.Kind {
+	case "gcp:storage:bucket":
+		return p.createBucket(ctx, instance)
+	default:
+		return fmt.Errorf("unsupported resource type: %s", instance.Kind)
+	}
+}
+
+// Update updates an existing GCP resource
+func (p *Provider) Update(ctx context.Context, instance config.ResourceInstance, currentState map[string]interface{}) error {
+	switch instance.Kind {
+	case "gcp:storage:bucket":
+		return p.updateBucket(ctx, instance, currentState)
+	default:
+		return fmt.Errorf("unsupported resource type: %s", instance.Kind)
+	}
+}
+
+// Delete deletes a GCP resource
+func (p *Provider) Delete(ctx context.Context, instance config.ResourceInstance) error {
+	switch instance.Kind {
+	case "gcp:storage:bucket":
+		return p.deleteBucket(ctx, instance)
+	default:
+		return fmt.Errorf("unsupported resource type: %s", instance.Kind)
+	}
+}
+
+// GetCurrentState retrieves the current state of a GCP resource, or nil if it doesn't exist
+func (p *Provider) GetCurrentState(ctx context.Context, instance config.ResourceInstance) (map[string]interface{}, error) {
+	switch instance.Kind {
+	case "gcp:storage:bucket":
+		return p.getBucketState(ctx, instance)
+	default:
+		return nil, fmt.Errorf("unsupported resource type: %s", instance.Kind)
+	}
+}
+
+// ValidateResource validates a GCP resource configuration
+func (p *Provider) ValidateResource(instance config.ResourceInstance) error {
+	switch instance.Kind {
+	case "gcp:storage:bucket":
+		return p.validateBucket(instance)
+	default:
+		return fmt.Errorf("unsupported resource type: %s", instance.Kind)
+	}
+}
+
+// GetSupportedResourceTypes returns the resource types supported by the GCP provider
+func (p *Provider) GetSupportedResourceTypes() []string {
+	return []string{
+		"gcp:storage:bucket",
+	}
+}
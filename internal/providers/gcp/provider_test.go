@@ -0,0 +1,114 @@
+package gcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/ataiva-software/runestone/internal/providers"
+	"github.com/stretchr/testify/assert"
+)
+
+// The GCP provider must be usable wherever an AWS provider is
+var _ providers.Provider = (*Provider)(nil)
+
+func TestProvider_InitializeRequiresProject(t *testing.T) {
+	provider := NewProvider()
+
+	err := provider.Initialize(context.Background(), map[string]interface{}{})
+
+	assert.ErrorContains(t, err, "project is required")
+}
+
+func TestProvider_GetSupportedResourceTypes(t *testing.T) {
+	provider := NewProvider()
+
+	assert.Contains(t, provider.GetSupportedResourceTypes(), "gcp:storage:bucket")
+}
+
+func TestProvider_ValidateBucket(t *testing.T) {
+	provider := NewProvider()
+
+	tests := []struct {
+		name       string
+		bucketName string
+		properties map[string]interface{}
+		wantErr    string
+	}{
+		{
+			name:       "valid bucket",
+			bucketName: "my-app-assets",
+			properties: map[string]interface{}{
+				"location":                    "EU",
+				"storage_class":               "NEARLINE",
+				"versioning":                  true,
+				"uniform_bucket_level_access": true,
+				"labels": map[string]interface{}{
+					"environment": "dev",
+				},
+			},
+		},
+		{
+			name:       "name with upper case letters",
+			bucketName: "My-Bucket",
+			wantErr:    "invalid GCS bucket name",
+		},
+		{
+			name:       "name containing google",
+			bucketName: "my-google-bucket",
+			wantErr:    "cannot begin with goog or contain google",
+		},
+		{
+			name:       "unknown storage class",
+			bucketName: "my-bucket",
+			properties: map[string]interface{}{"storage_class": "GLACIER"},
+			wantErr:    "invalid storage_class",
+		},
+		{
+			name:       "versioning that isn't a boolean",
+			bucketName: "my-bucket",
+			properties: map[string]interface{}{"versioning": "yes"},
+			wantErr:    "versioning must be a boolean",
+		},
+		{
+			name:       "label with upper case key",
+			bucketName: "my-bucket",
+			properties: map[string]interface{}{
+				"labels": map[string]interface{}{"Environment": "dev"},
+			},
+			wantErr: "invalid label key 'Environment'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			properties := tt.properties
+			if properties == nil {
+				properties = map[string]interface{}{}
+			}
+
+			err := provider.ValidateResource(config.ResourceInstance{
+				ID:         "gcp:storage:bucket." + tt.bucketName,
+				Kind:       "gcp:storage:bucket",
+				Name:       tt.bucketName,
+				Properties: properties,
+			})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestProvider_ValidateUnsupportedKind(t *testing.T) {
+	provider := NewProvider()
+
+	err := provider.ValidateResource(config.ResourceInstance{
+		Kind: "gcp:compute:instance",
+		Name: "web",
+	})
+
+	assert.ErrorContains(t, err, "unsupported resource type")
+}
@@ -0,0 +1,210 @@
+package gcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"github.com/ataiva-software/runestone/internal/config"
+)
+
+// Bucket names are 3-63 characters of lowercase letters, digits, dashes, underscores and
+// dots, starting and ending with a letter or digit
+var bucketNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{1,61}[a-z0-9]$`)
+
+// Label keys start with a lowercase letter; keys and values use lowercase letters, digits,
+// underscores and dashes
+var (
+	labelKeyRegex   = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,62}$`)
+	labelValueRegex = regexp.MustCompile(`^[a-z0-9_-]{0,63}$`)
+)
+
+var validStorageClasses = []string{"STANDARD", "NEARLINE", "COLDLINE", "ARCHIVE"}
+
+// createBucket creates a GCS bucket
+func (p *Provider) createBucket(ctx context.Context, instance config.ResourceInstance) error {
+	attrs := &storage.BucketAttrs{
+		Labels: labelsFromProperties(instance.Properties),
+	}
+
+	if location, ok := instance.Properties["location"].(string); ok {
+		attrs.Location = location
+	}
+	if storageClass, ok := instance.Properties["storage_class"].(string); ok {
+		attrs.StorageClass = storageClass
+	}
+	if versioning, ok := instance.Properties["versioning"].(bool); ok {
+		attrs.VersioningEnabled = versioning
+	}
+	if uniformAccess, ok := instance.Properties["uniform_bucket_level_access"].(bool); ok {
+		attrs.UniformBucketLevelAccess = storage.UniformBucketLevelAccess{Enabled: uniformAccess}
+	}
+
+	if err := p.storageClient.Bucket(instance.Name).Create(ctx, p.project, attrs); err != nil {
+		return fmt.Errorf("failed to create GCS bucket %s: %w", instance.Name, err)
+	}
+
+	return nil
+}
+
+// updateBucket applies the configured properties to an existing GCS bucket. A bucket's
+// location is fixed when it is created.
+func (p *Provider) updateBucket(ctx context.Context, instance config.ResourceInstance, currentState map[string]interface{}) error {
+	if location, ok := instance.Properties["location"].(string); ok {
+		if current, _ := currentState["location"].(string); current != "" && !strings.EqualFold(location, current) {
+			return fmt.Errorf("location of GCS bucket %s cannot be changed from %s to %s", instance.Name, current, location)
+		}
+	}
+
+	update := storage.BucketAttrsToUpdate{}
+	if storageClass, ok := instance.Properties["storage_class"].(string); ok {
+		update.StorageClass = storageClass
+	}
+	if versioning, ok := instance.Properties["versioning"].(bool); ok {
+		update.VersioningEnabled = versioning
+	}
+	if uniformAccess, ok := instance.Properties["uniform_bucket_level_access"].(bool); ok {
+		update.UniformBucketLevelAccess = &storage.UniformBucketLevelAccess{Enabled: uniformAccess}
+	}
+
+	// Replace labels only when they are configured
+	if _, exists := instance.Properties["labels"]; exists {
+		desired := labelsFromProperties(instance.Properties)
+		if current, ok := currentState["labels"].(map[string]interface{}); ok {
+			for key := range current {
+				if _, keep := desired[key]; !keep {
+					update.DeleteLabel(key)
+				}
+			}
+		}
+		for key, value := range desired {
+			update.SetLabel(key, value)
+		}
+	}
+
+	if _, err := p.storageClient.Bucket(instance.Name).Update(ctx, update); err != nil {
+		return fmt.Errorf("failed to update GCS bucket %s: %w", instance.Name, err)
+	}
+
+	return nil
+}
+
+// deleteBucket deletes a GCS bucket; the bucket must be empty
+func (p *Provider) deleteBucket(ctx context.Context, instance config.ResourceInstance) error {
+	err := p.storageClient.Bucket(instance.Name).Delete(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrBucketNotExist) {
+			return nil // Bucket already deleted
+		}
+		return fmt.Errorf("failed to delete GCS bucket %s: %w", instance.Name, err)
+	}
+
+	return nil
+}
+
+// getBucketState retrieves the current state of a GCS bucket
+func (p *Provider) getBucketState(ctx context.Context, instance config.ResourceInstance) (map[string]interface{}, error) {
+	attrs, err := p.storageClient.Bucket(instance.Name).Attrs(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrBucketNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get GCS bucket %s: %w", instance.Name, err)
+	}
+
+	// GCS reports locations in upper case; keep the configured spelling when it matches
+	location := attrs.Location
+	if desired, ok := instance.Properties["location"].(string); ok && strings.EqualFold(desired, location) {
+		location = desired
+	}
+
+	state := map[string]interface{}{
+		"location":                    location,
+		"storage_class":               attrs.StorageClass,
+		"versioning":                  attrs.VersioningEnabled,
+		"uniform_bucket_level_access": attrs.UniformBucketLevelAccess.Enabled,
+	}
+
+	if len(attrs.Labels) > 0 {
+		labels := make(map[string]interface{}, len(attrs.Labels))
+		for key, value := range attrs.Labels {
+			labels[key] = value
+		}
+		state["labels"] = labels
+	}
+
+	return state, nil
+}
+
+// validateBucket validates GCS bucket configuration
+func (p *Provider) validateBucket(instance config.ResourceInstance) error {
+	if instance.Name == "" {
+		return fmt.Errorf("GCS bucket name is required")
+	}
+
+	if !bucketNameRegex.MatchString(instance.Name) {
+		return fmt.Errorf("invalid GCS bucket name '%s': must be 3-63 lowercase letters, digits, dashes, underscores or dots, starting and ending with a letter or digit", instance.Name)
+	}
+
+	if strings.HasPrefix(instance.Name, "goog") || strings.Contains(instance.Name, "google") {
+		return fmt.Errorf("GCS bucket name '%s' cannot begin with goog or contain google", instance.Name)
+	}
+
+	if storageClassVal, exists := instance.Properties["storage_class"]; exists {
+		storageClass, ok := storageClassVal.(string)
+		if !ok || !isValidStorageClass(storageClass) {
+			return fmt.Errorf("invalid storage_class %v: must be one of %s", storageClassVal, strings.Join(validStorageClasses, ", "))
+		}
+	}
+
+	for _, property := range []string{"versioning", "uniform_bucket_level_access"} {
+		if value, exists := instance.Properties[property]; exists {
+			if _, ok := value.(bool); !ok {
+				return fmt.Errorf("%s must be a boolean", property)
+			}
+		}
+	}
+
+	if labelsVal, exists := instance.Properties["labels"]; exists {
+		labels, ok := labelsVal.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("labels must be a map of strings")
+		}
+		for key, value := range labels {
+			if !labelKeyRegex.MatchString(key) {
+				return fmt.Errorf("invalid label key '%s': must start with a lowercase letter and contain only lowercase letters, digits, underscores and dashes", key)
+			}
+			valueStr, ok := value.(string)
+			if !ok || !labelValueRegex.MatchString(valueStr) {
+				return fmt.Errorf("invalid value for label '%s': must contain only lowercase letters, digits, underscores and dashes", key)
+			}
+		}
+	}
+
+	return nil
+}
+
+// labelsFromProperties returns the string labels configured on a resource
+func labelsFromProperties(properties map[string]interface{}) map[string]string {
+	labels := make(map[string]string)
+	if labelsMap, ok := properties["labels"].(map[string]interface{}); ok {
+		for key, value := range labelsMap {
+			if valueStr, ok := value.(string); ok {
+				labels[key] = valueStr
+			}
+		}
+	}
+	return labels
+}
+
+func isValidStorageClass(storageClass string) bool {
+	for _, valid := range validStorageClasses {
+		if storageClass == valid {
+			return true
+		}
+	}
+	return false
+}
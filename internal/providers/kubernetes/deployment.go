@@ -0,0 +1,193 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	appsv1ac "k8s.io/client-go/applyconfigurations/apps/v1"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	metav1ac "k8s.io/client-go/applyconfigurations/meta/v1"
+)
+
+// applyDeployment creates or updates a Deployment with server-side apply. Pods are
+// selected by an app label set to the resource name.
+func (p *Provider) applyDeployment(ctx context.Context, instance config.ResourceInstance) error {
+	namespace := namespaceFor(instance)
+	image, _ := instance.Properties["image"].(string)
+
+	container := corev1ac.Container().
+		WithName(instance.Name).
+		WithImage(image)
+	if port, ok := instance.Properties["container_port"].(int); ok {
+		container.WithPorts(corev1ac.ContainerPort().WithContainerPort(int32(port)))
+	}
+	env := stringMapFromProperty(instance.Properties, "env")
+	for _, name := range sortedKeys(env) {
+		container.WithEnv(corev1ac.EnvVar().WithName(name).WithValue(env[name]))
+	}
+
+	labels := stringMapFromProperty(instance.Properties, "labels")
+	podLabels := make(map[string]string, len(labels)+1)
+	for key, value := range labels {
+		podLabels[key] = value
+	}
+	podLabels["app"] = instance.Name
+
+	spec := appsv1ac.DeploymentSpec().
+		WithSelector(metav1ac.LabelSelector().WithMatchLabels(map[string]string{"app": instance.Name})).
+		WithTemplate(corev1ac.PodTemplateSpec().
+			WithLabels(podLabels).
+			WithSpec(corev1ac.PodSpec().WithContainers(container)))
+	if replicas, ok := instance.Properties["replicas"].(int); ok {
+		spec.WithReplicas(int32(replicas))
+	}
+
+	deployment := appsv1ac.Deployment(instance.Name, namespace).
+		WithLabels(labels).
+		WithSpec(spec)
+
+	_, err := p.clientset.AppsV1().Deployments(namespace).Apply(ctx, deployment, metav1.ApplyOptions{
+		FieldManager: fieldManager,
+		Force:        true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply deployment %s/%s: %w", namespace, instance.Name, err)
+	}
+
+	return nil
+}
+
+// deleteDeployment deletes a Deployment and, in the background, its pods
+func (p *Provider) deleteDeployment(ctx context.Context, instance config.ResourceInstance) error {
+	namespace := namespaceFor(instance)
+
+	err := p.clientset.AppsV1().Deployments(namespace).Delete(ctx, instance.Name, metav1.DeleteOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil // Deployment already deleted
+		}
+		return fmt.Errorf("failed to delete deployment %s/%s: %w", namespace, instance.Name, err)
+	}
+
+	return nil
+}
+
+// getDeploymentState retrieves the current state of a Deployment
+func (p *Provider) getDeploymentState(ctx context.Context, instance config.ResourceInstance) (map[string]interface{}, error) {
+	namespace := namespaceFor(instance)
+
+	deployment, err := p.clientset.AppsV1().Deployments(namespace).Get(ctx, instance.Name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get deployment %s/%s: %w", namespace, instance.Name, err)
+	}
+
+	return deploymentState(deployment, instance.Properties), nil
+}
+
+// deploymentState maps a live Deployment onto the resource's properties. Server-managed
+// fields (status, managed fields, defaulted settings) are left out, and optional
+// properties are reported only when configured so that unmanaged settings aren't drift.
+func deploymentState(deployment *appsv1.Deployment, properties map[string]interface{}) map[string]interface{} {
+	state := namespaceState(deployment.Namespace, properties)
+
+	var container *corev1.Container
+	for i := range deployment.Spec.Template.Spec.Containers {
+		if deployment.Spec.Template.Spec.Containers[i].Name == deployment.Name {
+			container = &deployment.Spec.Template.Spec.Containers[i]
+			break
+		}
+	}
+
+	if _, exists := properties["replicas"]; exists && deployment.Spec.Replicas != nil {
+		state["replicas"] = int(*deployment.Spec.Replicas)
+	}
+
+	if container != nil {
+		state["image"] = container.Image
+
+		if _, exists := properties["container_port"]; exists && len(container.Ports) > 0 {
+			state["container_port"] = int(container.Ports[0].ContainerPort)
+		}
+
+		if _, exists := properties["env"]; exists && len(container.Env) > 0 {
+			env := make(map[string]interface{}, len(container.Env))
+			for _, envVar := range container.Env {
+				env[envVar.Name] = envVar.Value
+			}
+			state["env"] = env
+		}
+	}
+
+	if _, exists := properties["labels"]; exists {
+		if labels := userLabels(deployment.Labels); len(labels) > 0 {
+			state["labels"] = labels
+		}
+	}
+
+	return state
+}
+
+// validateDeployment validates Deployment configuration
+func (p *Provider) validateDeployment(instance config.ResourceInstance) error {
+	if errs := validation.IsDNS1123Subdomain(instance.Name); len(errs) > 0 {
+		return fmt.Errorf("invalid deployment name '%s': %s", instance.Name, errs[0])
+	}
+
+	if err := validateNamespace(instance.Properties); err != nil {
+		return err
+	}
+
+	image, ok := instance.Properties["image"].(string)
+	if !ok || image == "" {
+		return fmt.Errorf("image is required for deployments")
+	}
+
+	if replicasVal, exists := instance.Properties["replicas"]; exists {
+		replicas, ok := replicasVal.(int)
+		if !ok || replicas < 0 {
+			return fmt.Errorf("replicas must be a non-negative integer")
+		}
+	}
+
+	if portVal, exists := instance.Properties["container_port"]; exists {
+		if err := validatePort("container_port", portVal); err != nil {
+			return err
+		}
+	}
+
+	if envVal, exists := instance.Properties["env"]; exists {
+		env, ok := envVal.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("env must be a map of strings")
+		}
+		for name, value := range env {
+			if errs := validation.IsEnvVarName(name); len(errs) > 0 {
+				return fmt.Errorf("invalid environment variable name '%s': %s", name, errs[0])
+			}
+			if _, ok := value.(string); !ok {
+				return fmt.Errorf("value of environment variable '%s' must be a string", name)
+			}
+		}
+	}
+
+	return validateLabels(instance.Properties, "labels")
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
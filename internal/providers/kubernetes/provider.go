@@ -0,0 +1,215 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/ataiva-software/runestone/internal/providers"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// fieldManager identifies Runestone as the owner of the fields it applies
+const fieldManager = "runestone"
+
+// Provider implements the Kubernetes provider
+type Provider struct {
+	clientset kubernetes.Interface
+}
+
+func init() {
+	providers.RegisterFactory("kubernetes", func() providers.Provider { return NewProvider() })
+}
+
+// NewProvider creates a new Kubernetes provider
+func NewProvider() *Provider {
+	return &Provider{}
+}
+
+// Initialize sets up the Kubernetes provider from a kubeconfig. The kubeconfig setting
+// names the file to load (defaulting to $KUBECONFIG or ~/.kube/config) and context
+// selects a context other than the current one.
+func (p *Provider) Initialize(ctx context.Context, providerConfig map[string]interface{}) error {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if kubeconfig, _ := providerConfig["kubeconfig"].(string); kubeconfig != "" {
+		loadingRules.ExplicitPath = kubeconfig
+	}
+
+	overrides := &clientcmd.ConfigOverrides{}
+	if contextName, _ := providerConfig["context"].(string); contextName != "" {
+		overrides.CurrentContext = contextName
+	}
+
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+	p.clientset = clientset
+
+	return nil
+}
+
+// Create creates a new Kubernetes resource
+func (p *Provider) Create(ctx context.Context, instance config.ResourceInstance) error {
+	switch instance.Kind {
+	case "k8s:apps:deployment":
+		return p.applyDeployment(ctx, instance)
+	case "k8s:core:service":
+		return p.applyService(ctx, instance)
+	default:
+		return fmt.Errorf("unsupported resource type: %s", instance.Kind)
+	}
+}
+
+// Update updates an existing Kubernetes resource. Server-side apply makes this the same
+// operation as Create: fields no longer configured are removed from the live object.
+func (p *Provider) Update(ctx context.Context, instance config.ResourceInstance, currentState map[string]interface{}) error {
+	return p.Create(ctx, instance)
+}
+
+// Delete deletes a Kubernetes resource
+func (p *Provider) Delete(ctx context.Context, instance config.ResourceInstance) error {
+	switch instance.Kind {
+	case "k8s:apps:deployment":
+		return p.deleteDeployment(ctx, instance)
+	case "k8s:core:service":
+		return p.deleteService(ctx, instance)
+	default:
+		return fmt.Errorf("unsupported resource type: %s", instance.Kind)
+	}
+}
+
+// GetCurrentState retrieves the current state of a Kubernetes resource, or nil if it doesn't exist
+func (p *Provider) GetCurrentState(ctx context.Context, instance config.ResourceInstance) (map[string]interface{}, error) {
+	switch instance.Kind {
+	case "k8s:apps:deployment":
+		return p.getDeploymentState(ctx, instance)
+	case "k8s:core:service":
+		return p.getServiceState(ctx, instance)
+	default:
+		return nil, fmt.Errorf("unsupported resource type: %s", instance.Kind)
+	}
+}
+
+// ValidateResource validates a Kubernetes resource configuration
+func (p *Provider) ValidateResource(instance config.ResourceInstance) error {
+	switch instance.Kind {
+	case "k8s:apps:deployment":
+		return p.validateDeployment(instance)
+	case "k8s:core:service":
+		return p.validateService(instance)
+	default:
+		return fmt.Errorf("unsupported resource type: %s", instance.Kind)
+	}
+}
+
+// GetSupportedResourceTypes returns the resource types supported by the Kubernetes provider
+func (p *Provider) GetSupportedResourceTypes() []string {
+	return []string{
+		"k8s:apps:deployment",
+		"k8s:core:service",
+	}
+}
+
+// namespaceFor returns the namespace a resource lives in
+func namespaceFor(instance config.ResourceInstance) string {
+	if namespace, ok := instance.Properties["namespace"].(string); ok && namespace != "" {
+		return namespace
+	}
+	return "default"
+}
+
+// namespaceState starts a resource's state, reporting its namespace only when configured
+func namespaceState(namespace string, properties map[string]interface{}) map[string]interface{} {
+	state := make(map[string]interface{})
+	if _, exists := properties["namespace"]; exists {
+		state["namespace"] = namespace
+	}
+	return state
+}
+
+// stringMapFromProperty returns the string entries of a map property
+func stringMapFromProperty(properties map[string]interface{}, property string) map[string]string {
+	values := make(map[string]string)
+	if valuesMap, ok := properties[property].(map[string]interface{}); ok {
+		for key, value := range valuesMap {
+			if valueStr, ok := value.(string); ok {
+				values[key] = valueStr
+			}
+		}
+	}
+	return values
+}
+
+// userLabels returns an object's labels without those reserved for Kubernetes components
+func userLabels(labels map[string]string) map[string]interface{} {
+	result := make(map[string]interface{}, len(labels))
+	for key, value := range labels {
+		if isReservedLabel(key) {
+			continue
+		}
+		result[key] = value
+	}
+	return result
+}
+
+func isReservedLabel(key string) bool {
+	prefix, _, found := strings.Cut(key, "/")
+	return found && (prefix == "kubernetes.io" || strings.HasSuffix(prefix, ".kubernetes.io") ||
+		prefix == "k8s.io" || strings.HasSuffix(prefix, ".k8s.io"))
+}
+
+func validateNamespace(properties map[string]interface{}) error {
+	namespaceVal, exists := properties["namespace"]
+	if !exists {
+		return nil
+	}
+	namespace, ok := namespaceVal.(string)
+	if !ok {
+		return fmt.Errorf("namespace must be a string")
+	}
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return fmt.Errorf("invalid namespace '%s': %s", namespace, errs[0])
+	}
+	return nil
+}
+
+func validatePort(property string, value interface{}) error {
+	port, ok := value.(int)
+	if !ok || validation.IsValidPortNum(port) != nil {
+		return fmt.Errorf("%s must be an integer between 1 and 65535", property)
+	}
+	return nil
+}
+
+func validateLabels(properties map[string]interface{}, property string) error {
+	labelsVal, exists := properties[property]
+	if !exists {
+		return nil
+	}
+	labels, ok := labelsVal.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s must be a map of strings", property)
+	}
+	for key, value := range labels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid %s key '%s': %s", property, key, errs[0])
+		}
+		valueStr, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("value of %s key '%s' must be a string", property, key)
+		}
+		if errs := validation.IsValidLabelValue(valueStr); len(errs) > 0 {
+			return fmt.Errorf("invalid value for %s key '%s': %s", property, key, errs[0])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,312 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/ataiva-software/runestone/internal/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// The Kubernetes provider must be usable wherever an AWS provider is
+var _ providers.Provider = (*Provider)(nil)
+
+func TestProvider_GetSupportedResourceTypes(t *testing.T) {
+	provider := NewProvider()
+
+	types := provider.GetSupportedResourceTypes()
+
+	assert.Contains(t, types, "k8s:apps:deployment")
+	assert.Contains(t, types, "k8s:core:service")
+}
+
+func TestProvider_InitializeWithMissingKubeconfig(t *testing.T) {
+	provider := NewProvider()
+
+	err := provider.Initialize(context.Background(), map[string]interface{}{
+		"kubeconfig": "/nonexistent/kubeconfig",
+	})
+
+	assert.ErrorContains(t, err, "failed to load kubeconfig")
+}
+
+func TestProvider_DeploymentStateStripsServerManagedFields(t *testing.T) {
+	replicas := int32(3)
+	provider := &Provider{clientset: fake.NewSimpleClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "web",
+			Namespace:       "apps",
+			ResourceVersion: "12345",
+			UID:             "0b6e2c8a",
+			Generation:      4,
+			Labels: map[string]string{
+				"team":                        "platform",
+				"app.kubernetes.io/component": "frontend",
+			},
+			Annotations: map[string]string{"deployment.kubernetes.io/revision": "4"},
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{Manager: "runestone", Operation: metav1.ManagedFieldsOperationApply},
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas:                &replicas,
+			ProgressDeadlineSeconds: func() *int32 { v := int32(600); return &v }(),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:                     "web",
+						Image:                    "nginx:1.27",
+						Ports:                    []corev1.ContainerPort{{ContainerPort: 80, Protocol: corev1.ProtocolTCP}},
+						Env:                      []corev1.EnvVar{{Name: "MODE", Value: "production"}},
+						TerminationMessagePath:   "/dev/termination-log",
+						TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+					}},
+				},
+			},
+		},
+		Status: appsv1.DeploymentStatus{ReadyReplicas: 3},
+	})}
+
+	properties := map[string]interface{}{
+		"namespace":      "apps",
+		"image":          "nginx:1.27",
+		"replicas":       3,
+		"container_port": 80,
+		"env":            map[string]interface{}{"MODE": "production"},
+		"labels":         map[string]interface{}{"team": "platform"},
+	}
+
+	state, err := provider.GetCurrentState(context.Background(), config.ResourceInstance{
+		Kind:       "k8s:apps:deployment",
+		Name:       "web",
+		Properties: properties,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, properties, state)
+}
+
+func TestProvider_DeploymentStateOmitsUnconfiguredProperties(t *testing.T) {
+	replicas := int32(1)
+	provider := &Provider{clientset: fake.NewSimpleClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "web", Image: "nginx:1.27"}},
+				},
+			},
+		},
+	})}
+
+	state, err := provider.GetCurrentState(context.Background(), config.ResourceInstance{
+		Kind:       "k8s:apps:deployment",
+		Name:       "web",
+		Properties: map[string]interface{}{"image": "nginx:1.27"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"image": "nginx:1.27"}, state)
+}
+
+func TestProvider_MissingResourceHasNoState(t *testing.T) {
+	provider := &Provider{clientset: fake.NewSimpleClientset()}
+
+	for _, kind := range provider.GetSupportedResourceTypes() {
+		state, err := provider.GetCurrentState(context.Background(), config.ResourceInstance{
+			Kind:       kind,
+			Name:       "missing",
+			Properties: map[string]interface{}{},
+		})
+
+		require.NoError(t, err, kind)
+		assert.Nil(t, state, kind)
+	}
+}
+
+func TestProvider_ServiceStateStripsServerAssignedFields(t *testing.T) {
+	provider := &Provider{clientset: fake.NewSimpleClientset(&corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", ResourceVersion: "99"},
+		Spec: corev1.ServiceSpec{
+			Type:       corev1.ServiceTypeNodePort,
+			ClusterIP:  "10.96.0.12",
+			ClusterIPs: []string{"10.96.0.12"},
+			Selector:   map[string]string{"app": "web"},
+			Ports: []corev1.ServicePort{{
+				Port:       80,
+				TargetPort: intstr.FromInt32(8080),
+				Protocol:   corev1.ProtocolTCP,
+				NodePort:   30080,
+			}},
+			SessionAffinity: corev1.ServiceAffinityNone,
+		},
+	})}
+
+	properties := map[string]interface{}{
+		"type":     "NodePort",
+		"selector": map[string]interface{}{"app": "web"},
+		"ports": []interface{}{
+			map[string]interface{}{"port": 80, "target_port": 8080},
+		},
+	}
+
+	state, err := provider.GetCurrentState(context.Background(), config.ResourceInstance{
+		Kind:       "k8s:core:service",
+		Name:       "web",
+		Properties: properties,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, properties, state)
+}
+
+func TestProvider_ValidateDeployment(t *testing.T) {
+	provider := NewProvider()
+
+	tests := []struct {
+		name       string
+		deployName string
+		properties map[string]interface{}
+		wantErr    string
+	}{
+		{
+			name:       "valid deployment",
+			deployName: "web",
+			properties: map[string]interface{}{
+				"namespace":      "apps",
+				"image":          "nginx:1.27",
+				"replicas":       2,
+				"container_port": 80,
+				"env":            map[string]interface{}{"MODE": "production"},
+				"labels":         map[string]interface{}{"team": "platform"},
+			},
+		},
+		{
+			name:       "missing image",
+			deployName: "web",
+			properties: map[string]interface{}{},
+			wantErr:    "image is required",
+		},
+		{
+			name:       "invalid name",
+			deployName: "Web_App",
+			properties: map[string]interface{}{"image": "nginx"},
+			wantErr:    "invalid deployment name",
+		},
+		{
+			name:       "negative replicas",
+			deployName: "web",
+			properties: map[string]interface{}{"image": "nginx", "replicas": -1},
+			wantErr:    "replicas must be a non-negative integer",
+		},
+		{
+			name:       "port out of range",
+			deployName: "web",
+			properties: map[string]interface{}{"image": "nginx", "container_port": 70000},
+			wantErr:    "container_port must be an integer between 1 and 65535",
+		},
+		{
+			name:       "invalid label value",
+			deployName: "web",
+			properties: map[string]interface{}{
+				"image":  "nginx",
+				"labels": map[string]interface{}{"team": "platform team"},
+			},
+			wantErr: "invalid value for labels key 'team'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := provider.ValidateResource(config.ResourceInstance{
+				Kind:       "k8s:apps:deployment",
+				Name:       tt.deployName,
+				Properties: tt.properties,
+			})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestProvider_ValidateService(t *testing.T) {
+	provider := NewProvider()
+
+	tests := []struct {
+		name       string
+		properties map[string]interface{}
+		wantErr    string
+	}{
+		{
+			name: "valid service",
+			properties: map[string]interface{}{
+				"type":     "LoadBalancer",
+				"selector": map[string]interface{}{"app": "web"},
+				"ports": []interface{}{
+					map[string]interface{}{"name": "http", "port": 80, "target_port": 8080},
+					map[string]interface{}{"name": "dns", "port": 53, "protocol": "UDP"},
+				},
+			},
+		},
+		{
+			name: "missing selector",
+			properties: map[string]interface{}{
+				"ports": []interface{}{map[string]interface{}{"port": 80}},
+			},
+			wantErr: "selector is required",
+		},
+		{
+			name: "missing ports",
+			properties: map[string]interface{}{
+				"selector": map[string]interface{}{"app": "web"},
+			},
+			wantErr: "ports must be a non-empty list",
+		},
+		{
+			name: "unknown type",
+			properties: map[string]interface{}{
+				"type":     "ExternalName",
+				"selector": map[string]interface{}{"app": "web"},
+				"ports":    []interface{}{map[string]interface{}{"port": 80}},
+			},
+			wantErr: "invalid service type",
+		},
+		{
+			name: "unnamed ports",
+			properties: map[string]interface{}{
+				"selector": map[string]interface{}{"app": "web"},
+				"ports": []interface{}{
+					map[string]interface{}{"port": 80},
+					map[string]interface{}{"port": 443},
+				},
+			},
+			wantErr: "ports[0].name is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := provider.ValidateResource(config.ResourceInstance{
+				Kind:       "k8s:core:service",
+				Name:       "web",
+				Properties: tt.properties,
+			})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+)
+
+var (
+	validServiceTypes = []string{"ClusterIP", "NodePort", "LoadBalancer"}
+	validProtocols    = []string{"TCP", "UDP", "SCTP"}
+)
+
+// applyService creates or updates a Service with server-side apply
+func (p *Provider) applyService(ctx context.Context, instance config.ResourceInstance) error {
+	namespace := namespaceFor(instance)
+
+	spec := corev1ac.ServiceSpec().
+		WithSelector(stringMapFromProperty(instance.Properties, "selector"))
+	if serviceType, ok := instance.Properties["type"].(string); ok {
+		spec.WithType(corev1.ServiceType(serviceType))
+	}
+
+	ports, _ := instance.Properties["ports"].([]interface{})
+	for _, portVal := range ports {
+		portMap, ok := portVal.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		port, _ := portMap["port"].(int)
+		servicePort := corev1ac.ServicePort().WithPort(int32(port))
+		if name, ok := portMap["name"].(string); ok {
+			servicePort.WithName(name)
+		}
+		if targetPort, ok := portMap["target_port"].(int); ok {
+			servicePort.WithTargetPort(intstr.FromInt32(int32(targetPort)))
+		}
+		if protocol, ok := portMap["protocol"].(string); ok {
+			servicePort.WithProtocol(corev1.Protocol(protocol))
+		}
+		spec.WithPorts(servicePort)
+	}
+
+	service := corev1ac.Service(instance.Name, namespace).
+		WithLabels(stringMapFromProperty(instance.Properties, "labels")).
+		WithSpec(spec)
+
+	_, err := p.clientset.CoreV1().Services(namespace).Apply(ctx, service, metav1.ApplyOptions{
+		FieldManager: fieldManager,
+		Force:        true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply service %s/%s: %w", namespace, instance.Name, err)
+	}
+
+	return nil
+}
+
+// deleteService deletes a Service
+func (p *Provider) deleteService(ctx context.Context, instance config.ResourceInstance) error {
+	namespace := namespaceFor(instance)
+
+	err := p.clientset.CoreV1().Services(namespace).Delete(ctx, instance.Name, metav1.DeleteOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil // Service already deleted
+		}
+		return fmt.Errorf("failed to delete service %s/%s: %w", namespace, instance.Name, err)
+	}
+
+	return nil
+}
+
+// getServiceState retrieves the current state of a Service
+func (p *Provider) getServiceState(ctx context.Context, instance config.ResourceInstance) (map[string]interface{}, error) {
+	namespace := namespaceFor(instance)
+
+	service, err := p.clientset.CoreV1().Services(namespace).Get(ctx, instance.Name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get service %s/%s: %w", namespace, instance.Name, err)
+	}
+
+	return serviceState(service, instance.Properties), nil
+}
+
+// serviceState maps a live Service onto the resource's properties. Server-assigned fields
+// such as the cluster IP and node ports are left out, and optional settings, including
+// each port's name, target_port and protocol, are reported only when configured.
+func serviceState(service *corev1.Service, properties map[string]interface{}) map[string]interface{} {
+	state := namespaceState(service.Namespace, properties)
+
+	selector := make(map[string]interface{}, len(service.Spec.Selector))
+	for key, value := range service.Spec.Selector {
+		selector[key] = value
+	}
+	state["selector"] = selector
+
+	desiredPorts, _ := properties["ports"].([]interface{})
+	ports := make([]interface{}, 0, len(service.Spec.Ports))
+	for i, servicePort := range service.Spec.Ports {
+		var desired map[string]interface{}
+		if i < len(desiredPorts) {
+			desired, _ = desiredPorts[i].(map[string]interface{})
+		}
+
+		port := map[string]interface{}{
+			"port": int(servicePort.Port),
+		}
+		if _, exists := desired["name"]; exists || servicePort.Name != "" {
+			port["name"] = servicePort.Name
+		}
+		if _, exists := desired["target_port"]; exists || servicePort.TargetPort.IntValue() != int(servicePort.Port) {
+			port["target_port"] = servicePort.TargetPort.IntValue()
+		}
+		if _, exists := desired["protocol"]; exists || servicePort.Protocol != corev1.ProtocolTCP {
+			port["protocol"] = string(servicePort.Protocol)
+		}
+		ports = append(ports, port)
+	}
+	state["ports"] = ports
+
+	if _, exists := properties["type"]; exists || service.Spec.Type != corev1.ServiceTypeClusterIP {
+		state["type"] = string(service.Spec.Type)
+	}
+
+	if _, exists := properties["labels"]; exists {
+		if labels := userLabels(service.Labels); len(labels) > 0 {
+			state["labels"] = labels
+		}
+	}
+
+	return state
+}
+
+// validateService validates Service configuration
+func (p *Provider) validateService(instance config.ResourceInstance) error {
+	if errs := validation.IsDNS1035Label(instance.Name); len(errs) > 0 {
+		return fmt.Errorf("invalid service name '%s': %s", instance.Name, errs[0])
+	}
+
+	if err := validateNamespace(instance.Properties); err != nil {
+		return err
+	}
+
+	if typeVal, exists := instance.Properties["type"]; exists {
+		serviceType, ok := typeVal.(string)
+		if !ok || !contains(validServiceTypes, serviceType) {
+			return fmt.Errorf("invalid service type %v: must be one of %s", typeVal, strings.Join(validServiceTypes, ", "))
+		}
+	}
+
+	if err := validateLabels(instance.Properties, "selector"); err != nil {
+		return err
+	}
+	if len(stringMapFromProperty(instance.Properties, "selector")) == 0 {
+		return fmt.Errorf("selector is required for services")
+	}
+
+	ports, ok := instance.Properties["ports"].([]interface{})
+	if !ok || len(ports) == 0 {
+		return fmt.Errorf("ports must be a non-empty list for services")
+	}
+	for i, portVal := range ports {
+		portMap, ok := portVal.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("ports[%d] must be a map", i)
+		}
+		if err := validatePort(fmt.Sprintf("ports[%d].port", i), portMap["port"]); err != nil {
+			return err
+		}
+		if targetPort, exists := portMap["target_port"]; exists {
+			if err := validatePort(fmt.Sprintf("ports[%d].target_port", i), targetPort); err != nil {
+				return err
+			}
+		}
+		if protocolVal, exists := portMap["protocol"]; exists {
+			protocol, ok := protocolVal.(string)
+			if !ok || !contains(validProtocols, protocol) {
+				return fmt.Errorf("invalid ports[%d].protocol %v: must be one of %s", i, protocolVal, strings.Join(validProtocols, ", "))
+			}
+		}
+		if nameVal, exists := portMap["name"]; exists {
+			name, ok := nameVal.(string)
+			if !ok {
+				return fmt.Errorf("ports[%d].name must be a string", i)
+			}
+			if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+				return fmt.Errorf("invalid ports[%d].name '%s': %s", i, name, errs[0])
+			}
+		}
+	}
+	if len(ports) > 1 {
+		for i, portVal := range ports {
+			if _, named := portVal.(map[string]interface{})["name"]; !named {
+				return fmt.Errorf("ports[%d].name is required when a service has more than one port", i)
+			}
+		}
+	}
+
+	return validateLabels(instance.Properties, "labels")
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
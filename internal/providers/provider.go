@@ -2,6 +2,12 @@ package providers
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"strings"
+	"time"
 
 	"github.com/ataiva-software/runestone/internal/config"
 )
@@ -30,6 +36,101 @@ type Provider interface {
 	GetSupportedResourceTypes() []string
 }
 
+// ComputedFieldsProvider is implemented by providers that declare which state properties
+// of a resource type are computed by the cloud (IDs, ARNs, status) rather than configured.
+// Drift detection ignores computed properties that the configuration doesn't set.
+type ComputedFieldsProvider interface {
+	GetComputedFields(kind string) []string
+}
+
+// IgnoredTagPrefixesProvider is implemented by providers that declare which tag key prefixes
+// belong to tags the cloud adds itself, such as aws:cloudformation:stack-name. Drift detection
+// ignores such tags when the configuration doesn't declare them.
+type IgnoredTagPrefixesProvider interface {
+	GetIgnoredTagPrefixes() []string
+}
+
+// HasTagPrefix reports whether a tag key starts with one of prefixes, such as those a
+// provider's GetIgnoredTagPrefixes returns
+func HasTagPrefix(key string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// SensitiveFieldsProvider is implemented by providers that declare which properties of a
+// resource type hold secrets, such as passwords. Their values are redacted in drift output.
+type SensitiveFieldsProvider interface {
+	GetSensitiveFields(kind string) []string
+}
+
+// BatchStateProvider is implemented by providers that can look up the state of many
+// resources with fewer API calls than one GetCurrentState per resource, such as a single
+// DescribeInstances for every EC2 instance. States are keyed by instance ID; resources that
+// don't exist are left out. Drift detection batches only the kinds SupportsBatchState
+// accepts and looks up the rest concurrently with GetCurrentState.
+type BatchStateProvider interface {
+	SupportsBatchState(kind string) bool
+	GetCurrentStateBatch(ctx context.Context, instances []config.ResourceInstance) (map[string]map[string]interface{}, error)
+}
+
+// OfflineConfigurer is implemented by providers that can take their settings, such as the
+// region, without creating clients or reading credentials. validate configures providers
+// this way, so that checks depending on those settings run offline too.
+type OfflineConfigurer interface {
+	Configure(providerConfig map[string]interface{}) error
+}
+
+// LoggingProvider is implemented by providers that log diagnostics, such as retries and
+// progress while waiting on resources. Providers that aren't given a logger use slog.Default.
+type LoggingProvider interface {
+	SetLogger(logger *slog.Logger)
+}
+
+// Importer is implemented by providers that can adopt existing resources which state
+// lookups wouldn't otherwise match, such as resources found by a Name tag they lack
+type Importer interface {
+	// Import adopts the resource identified by cloudID as the given instance and returns
+	// its properties as the configuration would declare them
+	Import(ctx context.Context, instance config.ResourceInstance, cloudID string) (map[string]interface{}, error)
+}
+
+// Renamer is implemented by providers that can rename an existing resource in place, for
+// resources a moved block gives a new ID. Kinds whose name is their identity in the cloud,
+// such as S3 buckets, can only be replaced, and CanRename reports false for them.
+type Renamer interface {
+	CanRename(kind string) bool
+	// Rename makes the resource created as from the resource declared as to, so that state
+	// lookups for to find it. Its other properties are left for an update to change.
+	Rename(ctx context.Context, from, to config.ResourceInstance) error
+}
+
+// SecretResolver is implemented by providers that can read secrets referenced with
+// ${secret('name')}. Secrets are resolved at commit time, right before the provider call
+// that needs them, so their values never appear in previews or plans.
+type SecretResolver interface {
+	ResolveSecret(ctx context.Context, name string) (string, error)
+}
+
+// Scanner is implemented by providers that can list the resources of some kinds that
+// exist in the account, whether or not a configuration manages them. Scan reports each
+// resource with whether it carries the tags Runestone marks the resources it manages with.
+type Scanner interface {
+	Scan(ctx context.Context) ([]ScannedResource, error)
+}
+
+// ScannedResource is a resource found by a Scanner
+type ScannedResource struct {
+	Kind      string
+	CloudID   string
+	Name      string    // From the Name tag, if any
+	CreatedAt time.Time // Zero for kinds whose APIs don't report it
+	Managed   bool
+}
+
 // ResourceState represents the current state of a resource
 type ResourceState struct {
 	ID         string
@@ -43,15 +144,22 @@ type ResourceState struct {
 // DriftResult represents the result of drift detection
 type DriftResult struct {
 	HasDrift     bool
-	Changes      []string                   // Human-readable list of changes
+	Changes      []string // Human-readable list of changes
 	Differences  map[string]DriftDifference
 	CurrentState map[string]interface{}
 	DesiredState map[string]interface{}
+	// MovedFrom is the resource ID a moved block moves the resource from, when it still
+	// exists under that ID; CurrentState is then its state there, and commit renames it
+	MovedFrom string
 }
 
 // DriftDifference represents a difference between current and desired state
 type DriftDifference struct {
-	Property     string
+	// Property is the dotted path of the value, such as tags.Environment or ports[0].port
+	Property string
+	// Path is the same location as a JSON Pointer (RFC 6901), such as /tags/Environment or
+	// /ports/0/port, which stays unambiguous when map keys contain dots
+	Path         string
 	CurrentValue interface{}
 	DesiredValue interface{}
 	DriftType    DriftType
@@ -66,6 +174,83 @@ const (
 	DriftTypeModified DriftType = "modified"
 )
 
+// forceDeleteKey is the context key marking deletions as forced
+type forceDeleteKey struct{}
+
+// WithForceDelete returns a context that tells providers to override deletion safeguards
+func WithForceDelete(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceDeleteKey{}, true)
+}
+
+// IsForceDelete reports whether deletion safeguards should be overridden
+func IsForceDelete(ctx context.Context) bool {
+	force, _ := ctx.Value(forceDeleteKey{}).(bool)
+	return force
+}
+
+// RunWithTimeout runs a change to a resource, such as a provider's Create, with a context
+// bounded by the resource's timeout for the change from its timeouts block. Without one, ctx
+// is passed as is. A change that runs out of time fails with an error naming the timeout,
+// which still matches context.DeadlineExceeded so the change isn't retried.
+func RunWithTimeout(ctx context.Context, instance config.ResourceInstance, change config.ChangeType, operation func(context.Context) error) error {
+	timeout := instance.Timeouts.Timeout(change)
+	if timeout <= 0 {
+		return operation(ctx)
+	}
+
+	operationCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := operation(operationCtx)
+	if err == nil || ctx.Err() != nil || !errors.Is(operationCtx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		err = fmt.Errorf("%w: %w", err, context.DeadlineExceeded)
+	}
+	return fmt.Errorf("%s of %s timed out after %v; raise timeouts.%s if it needs longer: %w", change, instance.ID, timeout, change, err)
+}
+
+// TransientError marks a provider error as temporary, such as throttling, so that the
+// executor retries the change. Providers that retry their own API calls return it only for
+// errors they haven't retried already, so that the two don't multiply attempts.
+type TransientError struct {
+	Err error
+}
+
+func (e *TransientError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *TransientError) Unwrap() error {
+	return e.Err
+}
+
+// Transient marks err as temporary; a nil error stays nil
+func Transient(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &TransientError{Err: err}
+}
+
+// IsTransient reports whether a provider marked err as temporary
+func IsTransient(err error) bool {
+	var transient *TransientError
+	return errors.As(err, &transient)
+}
+
+// BackoffDelay returns the exponential delay, starting from base, before retrying after an
+// attempt. Equal jitter is applied so that resources failing together don't retry in lockstep.
+func BackoffDelay(base time.Duration, attempt int) time.Duration {
+	delay := base * time.Duration(1<<attempt)
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
 // ProviderRegistry manages available providers
 type ProviderRegistry struct {
 	providers map[string]Provider
@@ -102,3 +287,70 @@ func (r *ProviderRegistry) GetAll() map[string]Provider {
 	}
 	return result
 }
+
+// Factory constructs an uninitialized provider
+type Factory func() Provider
+
+// factories holds the provider implementations that have registered themselves
+var factories = make(map[string]Factory)
+
+// RegisterFactory makes a provider implementation available under a name. Provider
+// packages call it from init, so importing a provider package is enough to enable it.
+func RegisterFactory(name string, factory Factory) {
+	if _, exists := factories[name]; exists {
+		panic(fmt.Sprintf("provider %s registered twice", name))
+	}
+	factories[name] = factory
+}
+
+// NewProviderByName constructs an uninitialized provider by name
+func NewProviderByName(name string) (Provider, error) {
+	factory, exists := factories[name]
+	if !exists {
+		return nil, fmt.Errorf("unsupported provider: %s", name)
+	}
+	return factory(), nil
+}
+
+// kindPrefixes maps resource kind prefixes to providers whose name differs from the prefix
+var kindPrefixes = map[string]string{
+	"k8s": "kubernetes",
+}
+
+// ProviderNameForKind returns the name of the provider that manages a resource kind
+func ProviderNameForKind(kind string) string {
+	prefix, _, _ := strings.Cut(kind, ":")
+	if name, ok := kindPrefixes[prefix]; ok {
+		return name
+	}
+	return prefix
+}
+
+// ProviderType returns the provider implementation a providers entry configures. Entries
+// are keyed by provider name, or by name and alias such as aws.eu-west-1 to configure
+// the same provider more than once.
+func ProviderType(key string) string {
+	name, _, _ := strings.Cut(key, ".")
+	return name
+}
+
+// ProviderKey returns the providers entry that manages an instance: the one its provider
+// field selects, or else the unaliased entry for its kind
+func ProviderKey(instance config.ResourceInstance) string {
+	if instance.Provider != "" {
+		return instance.Provider
+	}
+	return ProviderNameForKind(instance.Kind)
+}
+
+// ValidateProviderKey checks that an instance's provider field selects a configuration of
+// the provider that manages its kind
+func ValidateProviderKey(instance config.ResourceInstance) error {
+	if instance.Provider == "" {
+		return nil
+	}
+	if want := ProviderNameForKind(instance.Kind); ProviderType(instance.Provider) != want {
+		return fmt.Errorf("resource %s of kind %s can't use provider %s; use %s or an alias such as %s.<alias>", instance.ID, instance.Kind, instance.Provider, want, want)
+	}
+	return nil
+}
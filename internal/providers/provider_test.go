@@ -0,0 +1,152 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ataiva-software/runestone/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubProvider struct{}
+
+func (stubProvider) Initialize(ctx context.Context, providerConfig map[string]interface{}) error {
+	return nil
+}
+func (stubProvider) Create(ctx context.Context, instance config.ResourceInstance) error { return nil }
+func (stubProvider) Update(ctx context.Context, instance config.ResourceInstance, currentState map[string]interface{}) error {
+	return nil
+}
+func (stubProvider) Delete(ctx context.Context, instance config.ResourceInstance) error { return nil }
+func (stubProvider) GetCurrentState(ctx context.Context, instance config.ResourceInstance) (map[string]interface{}, error) {
+	return nil, nil
+}
+func (stubProvider) ValidateResource(instance config.ResourceInstance) error { return nil }
+func (stubProvider) GetSupportedResourceTypes() []string                     { return nil }
+
+func TestNewProviderByName(t *testing.T) {
+	RegisterFactory("stub", func() Provider { return stubProvider{} })
+	t.Cleanup(func() { delete(factories, "stub") })
+
+	provider, err := NewProviderByName("stub")
+
+	require.NoError(t, err)
+	assert.IsType(t, stubProvider{}, provider)
+}
+
+func TestNewProviderByName_Unknown(t *testing.T) {
+	_, err := NewProviderByName("azure")
+
+	assert.EqualError(t, err, "unsupported provider: azure")
+}
+
+func TestRegisterFactory_PanicsOnDuplicate(t *testing.T) {
+	RegisterFactory("stub", func() Provider { return stubProvider{} })
+	t.Cleanup(func() { delete(factories, "stub") })
+
+	assert.Panics(t, func() {
+		RegisterFactory("stub", func() Provider { return stubProvider{} })
+	})
+}
+
+func TestProviderNameForKind(t *testing.T) {
+	assert.Equal(t, "aws", ProviderNameForKind("aws:s3:bucket"))
+	assert.Equal(t, "kubernetes", ProviderNameForKind("k8s:apps:deployment"))
+	assert.Equal(t, "custom", ProviderNameForKind("custom"))
+}
+
+func TestHasTagPrefix(t *testing.T) {
+	prefixes := []string{"aws:", "kubernetes.io/"}
+	assert.True(t, HasTagPrefix("aws:cloudformation:stack-name", prefixes))
+	assert.True(t, HasTagPrefix("kubernetes.io/cluster/prod", prefixes))
+	assert.False(t, HasTagPrefix("Environment", prefixes))
+	assert.False(t, HasTagPrefix("aws:cloudformation:stack-name", nil))
+}
+
+func TestProviderType(t *testing.T) {
+	assert.Equal(t, "aws", ProviderType("aws"))
+	assert.Equal(t, "aws", ProviderType("aws.eu-west-1"))
+	assert.Equal(t, "kubernetes", ProviderType("kubernetes.staging"))
+}
+
+func TestProviderKey(t *testing.T) {
+	assert.Equal(t, "aws", ProviderKey(config.ResourceInstance{Kind: "aws:s3:bucket"}))
+	assert.Equal(t, "aws.eu-west-1", ProviderKey(config.ResourceInstance{Kind: "aws:s3:bucket", Provider: "aws.eu-west-1"}))
+	assert.Equal(t, "kubernetes", ProviderKey(config.ResourceInstance{Kind: "k8s:apps:deployment"}))
+}
+
+func TestValidateProviderKey(t *testing.T) {
+	assert.NoError(t, ValidateProviderKey(config.ResourceInstance{ID: "aws:s3:bucket.logs", Kind: "aws:s3:bucket"}))
+	assert.NoError(t, ValidateProviderKey(config.ResourceInstance{ID: "aws:s3:bucket.logs", Kind: "aws:s3:bucket", Provider: "aws.eu-west-1"}))
+	assert.NoError(t, ValidateProviderKey(config.ResourceInstance{ID: "k8s:apps:deployment.web", Kind: "k8s:apps:deployment", Provider: "kubernetes.staging"}))
+
+	err := ValidateProviderKey(config.ResourceInstance{ID: "aws:s3:bucket.logs", Kind: "aws:s3:bucket", Provider: "gcp.europe"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "can't use provider gcp.europe")
+}
+
+func TestRunWithTimeout(t *testing.T) {
+	instance := config.ResourceInstance{ID: "aws:rds:instance.db", Kind: "aws:rds:instance", Name: "db"}
+
+	// Without a timeout the operation gets the caller's context
+	err := RunWithTimeout(context.Background(), instance, config.ChangeTypeCreate, func(ctx context.Context) error {
+		_, hasDeadline := ctx.Deadline()
+		assert.False(t, hasDeadline)
+		return nil
+	})
+	require.NoError(t, err)
+
+	instance.Timeouts = &config.Timeouts{Create: "10ms"}
+	err = RunWithTimeout(context.Background(), instance, config.ChangeTypeUpdate, func(ctx context.Context) error {
+		_, hasDeadline := ctx.Deadline()
+		assert.False(t, hasDeadline, "A create timeout shouldn't bound updates")
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = RunWithTimeout(context.Background(), instance, config.ChangeTypeCreate, func(ctx context.Context) error {
+		<-ctx.Done()
+		return errors.New("waiting for RDS instance db: stopped")
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "create of aws:rds:instance.db timed out after 10ms; raise timeouts.create if it needs longer")
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "Timed out changes shouldn't look retryable")
+
+	// Canceling the caller's context isn't reported as the resource's timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = RunWithTimeout(ctx, instance, config.ChangeTypeCreate, func(ctx context.Context) error {
+		return ctx.Err()
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NotContains(t, err.Error(), "timed out")
+}
+
+func TestTransient(t *testing.T) {
+	assert.NoError(t, Transient(nil))
+	assert.False(t, IsTransient(nil))
+	assert.False(t, IsTransient(errors.New("Throttling: Rate exceeded")))
+
+	throttled := errors.New("Throttling: Rate exceeded")
+	err := fmt.Errorf("create failed: %w", Transient(throttled))
+	assert.True(t, IsTransient(err))
+	assert.ErrorIs(t, err, throttled)
+	assert.Equal(t, "create failed: Throttling: Rate exceeded", err.Error())
+}
+
+func TestBackoffDelay(t *testing.T) {
+	base := 100 * time.Millisecond
+
+	for attempt := 0; attempt < 4; attempt++ {
+		full := base * time.Duration(1<<attempt)
+		for i := 0; i < 20; i++ {
+			delay := BackoffDelay(base, attempt)
+			assert.GreaterOrEqual(t, delay, full/2)
+			assert.LessOrEqual(t, delay, full)
+		}
+	}
+}
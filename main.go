@@ -10,7 +10,5 @@ var version = "dev"
 
 func main() {
 	cmd.SetVersion(version)
-	if err := cmd.Execute(); err != nil {
-		os.Exit(1)
-	}
+	os.Exit(cmd.Execute())
 }